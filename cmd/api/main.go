@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/paulochiaradia/dashtrack/internal/config"
 	"github.com/paulochiaradia/dashtrack/internal/database"
@@ -14,6 +17,9 @@ import (
 
 func main() {
 	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
 
 	// Initialize structured logger
 	err := logger.InitLogger()
@@ -39,11 +45,32 @@ func main() {
 		zap.String("environment", cfg.ServerEnv),
 	)
 
-	db := database.NewDatabase(cfg.DBSource)
+	pool := database.PoolConfig{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: time.Duration(cfg.DBConnMaxLifetimeMinutes) * time.Minute,
+	}
+	db := database.NewDatabase(cfg.DBSource, pool)
 	defer db.Close()
 
+	// Optional read replica for reporting queries
+	var replica *sql.DB
+	if cfg.DBReplicaSource != "" {
+		replica = database.NewDatabase(cfg.DBReplicaSource, pool)
+		defer replica.Close()
+		logger.Info("Read replica configured for reporting queries")
+	}
+
 	// Initialize router
-	router := routes.NewRouter(db, cfg)
+	router := routes.NewRouter(db, replica, cfg)
+
+	// Start background jobs (vehicle document expiry alerts)
+	router.StartBackgroundJobs(context.Background())
+
+	// Optional MQTT ingestion for trackers; an unreachable broker is retried in the background
+	if cfg.MQTTEnabled {
+		router.StartMQTTBridge(context.Background())
+	}
 
 	// Log available endpoints
 	logger.Info("Server configuration",
@@ -55,7 +82,7 @@ func main() {
 
 	logger.Info("Available endpoints documented",
 		zap.Strings("public_endpoints", []string{
-			"GET /health", "GET /metrics", "POST /api/v1/auth/login",
+			"GET /health", "GET /metrics", "GET /openapi.json", "GET /docs", "POST /api/v1/auth/login",
 			"POST /api/v1/auth/refresh", "POST /api/v1/auth/logout",
 			"POST /api/v1/auth/forgot-password", "POST /api/v1/auth/reset-password",
 		}),
@@ -1,14 +1,31 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
-// SMTPConfig contém configurações do servidor SMTP
+// Modos de TLS da conexão SMTP (SMTP_TLS_MODE)
+const (
+	// SMTPTLSModeStartTLS conecta em texto e negocia STARTTLS antes de autenticar (porta 587)
+	SMTPTLSModeStartTLS = "starttls"
+	// SMTPTLSModeImplicit abre a conexão já em TLS (SMTPS, porta 465)
+	SMTPTLSModeImplicit = "tls"
+	// SMTPTLSModeNone não exige TLS; use apenas com servidores locais
+	SMTPTLSModeNone = "none"
+)
+
+// SMTPConfig contém configurações do servidor SMTP. Sem SMTP_HOST o envio de emails fica
+// desligado e a API usa um serviço de email que apenas registra os envios.
 type SMTPConfig struct {
 	Host     string `mapstructure:"SMTP_HOST"`
 	Port     string `mapstructure:"SMTP_PORT"`
@@ -16,12 +33,47 @@ type SMTPConfig struct {
 	Password string `mapstructure:"SMTP_PASSWORD"`
 	From     string `mapstructure:"SMTP_FROM"`
 	FromName string `mapstructure:"SMTP_FROM_NAME"`
-	UseTLS   bool   `mapstructure:"SMTP_USE_TLS"`
+	TLSMode  string `mapstructure:"SMTP_TLS_MODE"`
+}
+
+// Enabled indica se há um servidor SMTP configurado
+func (s SMTPConfig) Enabled() bool {
+	return s.Host != ""
+}
+
+// Validate verifica as configurações necessárias para enviar emails e retorna todos os
+// problemas encontrados
+func (s SMTPConfig) Validate() error {
+	var problems []error
+	if s.Host == "" {
+		problems = append(problems, errors.New("SMTP_HOST is required"))
+	}
+	if port, err := strconv.Atoi(s.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Errorf("SMTP_PORT must be a number between 1 and 65535, got %q", s.Port))
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		problems = append(problems, fmt.Errorf("SMTP_FROM must be an email address, got %q", s.From))
+	}
+	switch s.TLSMode {
+	case SMTPTLSModeStartTLS, SMTPTLSModeImplicit, SMTPTLSModeNone:
+	default:
+		problems = append(problems, fmt.Errorf("SMTP_TLS_MODE must be starttls, tls or none, got %q", s.TLSMode))
+	}
+	if s.Username != "" && s.Password == "" {
+		problems = append(problems, errors.New("SMTP_PASSWORD is required when SMTP_USERNAME is set"))
+	}
+	return errors.Join(problems...)
 }
 
 type Config struct {
 	// Database
-	DBSource string `mapstructure:"DB_SOURCE"`
+	DBSource                 string `mapstructure:"DB_SOURCE"`
+	DBMaxOpenConns           int    `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns           int    `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetimeMinutes int    `mapstructure:"DB_CONN_MAX_LIFETIME_MINUTES"`
+	DBReplicaSource          string `mapstructure:"DB_REPLICA_SOURCE"`
+	DBQueryTimeoutSeconds    int    `mapstructure:"DB_QUERY_TIMEOUT_SECONDS"`
+	DBSlowQueryThresholdMS   int    `mapstructure:"DB_SLOW_QUERY_THRESHOLD_MS"`
 
 	// Server
 	ServerPort string `mapstructure:"SERVER_PORT"`
@@ -31,6 +83,33 @@ type Config struct {
 	JWTSecret              string `mapstructure:"JWT_SECRET"`
 	JWTAccessExpireMinutes int    `mapstructure:"JWT_ACCESS_EXPIRE_MINUTES"`
 	JWTRefreshExpireHours  int    `mapstructure:"JWT_REFRESH_EXPIRE_HOURS"`
+	// Refresh token lifetime of logins sent with remember_me
+	JWTRememberMeRefreshExpireHours int `mapstructure:"JWT_REMEMBER_ME_REFRESH_EXPIRE_HOURS"`
+	// Extra signing keys for secret rotation, as "kid:secret" pairs separated by commas.
+	// JWT_SECRET joins them under the key ID "default".
+	JWTKeys string `mapstructure:"JWT_KEYS"`
+	// Key ID of the key new tokens are signed with
+	JWTActiveKeyID string `mapstructure:"JWT_ACTIVE_KEY_ID"`
+
+	// Sessions
+	SessionIdleTimeoutMinutes int `mapstructure:"SESSION_IDLE_TIMEOUT_MINUTES"`
+	// Users with a valid session used within this many minutes show as online
+	OnlineThresholdMinutes int `mapstructure:"ONLINE_THRESHOLD_MINUTES"`
+
+	// Cookie sessions (browser clients; header-based clients are unaffected)
+	AuthCookieEnabled  bool     `mapstructure:"AUTH_COOKIE_ENABLED"`
+	AuthCookieDomain   string   `mapstructure:"AUTH_COOKIE_DOMAIN"`
+	AuthCookieSecure   bool     `mapstructure:"AUTH_COOKIE_SECURE"`
+	AuthCookieSameSite string   `mapstructure:"AUTH_COOKIE_SAMESITE"`
+	CORSAllowedOrigins []string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+
+	// Reverse proxies: client IP headers are only honored on requests from TrustedProxies
+	// (IPs or CIDRs); with none, the client IP is the address of the TCP connection
+	TrustedProxies  []string `mapstructure:"TRUSTED_PROXIES"`
+	ClientIPHeaders []string `mapstructure:"CLIENT_IP_HEADERS"`
+	// TrustedPlatform trusts the client IP header of a CDN or hosting platform: cloudflare,
+	// google-app-engine or fly-io
+	TrustedPlatform string `mapstructure:"TRUSTED_PLATFORM"`
 
 	// Email/SMTP
 	SMTP SMTPConfig `mapstructure:",squash"`
@@ -43,6 +122,68 @@ type Config struct {
 	// Security
 	BcryptCost               int `mapstructure:"BCRYPT_COST"`
 	PasswordResetExpireHours int `mapstructure:"PASSWORD_RESET_EXPIRE_HOURS"`
+	PasswordHistorySize      int `mapstructure:"PASSWORD_HISTORY_SIZE"`
+	PasswordMaxAgeDays       int `mapstructure:"PASSWORD_MAX_AGE_DAYS"`
+
+	// Suspicious login detection
+	GeoIPEnabled      bool   `mapstructure:"GEOIP_ENABLED"`
+	GeoIPDatabasePath string `mapstructure:"GEOIP_DATABASE_PATH"`
+
+	// Data exports
+	ExportMaxRows           int `mapstructure:"EXPORT_MAX_ROWS"`
+	ExportRateLimit         int `mapstructure:"EXPORT_RATE_LIMIT"`
+	ExportRateWindowMinutes int `mapstructure:"EXPORT_RATE_WINDOW_MINUTES"`
+
+	// Batch requests
+	BatchMaxSize int `mapstructure:"BATCH_MAX_SIZE"`
+
+	// Audit
+	AuditRedactKeys []string `mapstructure:"AUDIT_REDACT_KEYS"`
+
+	// Fleet alerts
+	DocumentExpiryAlertDays int `mapstructure:"DOCUMENT_EXPIRY_ALERT_DAYS"`
+
+	// Trips
+	TripMaxDurationHours int `mapstructure:"TRIP_MAX_DURATION_HOURS"`
+
+	// Teams
+	TeamMemberSoftDelete bool `mapstructure:"TEAM_MEMBER_SOFT_DELETE"`
+
+	// Idempotency keys
+	IdempotencyKeyTTLHours int `mapstructure:"IDEMPOTENCY_KEY_TTL_HOURS"`
+
+	// Metrics
+	MetricsRefreshIntervalSeconds int `mapstructure:"METRICS_REFRESH_INTERVAL_SECONDS"`
+
+	// MQTT ingestion (IoT trackers)
+	MQTTEnabled   bool   `mapstructure:"MQTT_ENABLED"`
+	MQTTBrokerURL string `mapstructure:"MQTT_BROKER_URL"`
+	MQTTClientID  string `mapstructure:"MQTT_CLIENT_ID"`
+	MQTTUsername  string `mapstructure:"MQTT_USERNAME"`
+	MQTTPassword  string `mapstructure:"MQTT_PASSWORD"`
+	MQTTQoS       int    `mapstructure:"MQTT_QOS"`
+
+	// Privacy
+	AccountErasureGraceDays int `mapstructure:"ACCOUNT_ERASURE_GRACE_DAYS"`
+
+	// File storage (uploaded avatars)
+	StorageDriver     string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalDir   string `mapstructure:"STORAGE_LOCAL_DIR"`
+	StoragePublicURL  string `mapstructure:"STORAGE_PUBLIC_URL"`
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"`
+	S3Region          string `mapstructure:"S3_REGION"`
+	S3Bucket          string `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID     string `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	S3PublicURL       string `mapstructure:"S3_PUBLIC_URL"`
+
+	// Avatars
+	AvatarMaxBytes     int `mapstructure:"AVATAR_MAX_BYTES"`
+	AvatarMaxDimension int `mapstructure:"AVATAR_MAX_DIMENSION"`
+
+	// Secrets (DB_SOURCE, DB_REPLICA_SOURCE, JWT_SECRET, JWT_KEYS, SMTP_PASSWORD, S3_SECRET_ACCESS_KEY and MQTT_PASSWORD)
+	SecretProvider string `mapstructure:"SECRET_PROVIDER"`
+	SecretsDir     string `mapstructure:"SECRETS_DIR"`
 }
 
 var (
@@ -50,6 +191,9 @@ var (
 	config *Config
 )
 
+// LoadConfig reads the configuration once (see Load), loading .env into the environment
+// first. It exits when the config file cannot be read, but does not check the values;
+// call Validate before using it.
 func LoadConfig() *Config {
 	once.Do(func() {
 		// Load .env file
@@ -57,50 +201,249 @@ func LoadConfig() *Config {
 			log.Printf("Warning: Error loading .env file: %v", err)
 		}
 
-		viper.AutomaticEnv()
-		// Set defaults
-		viper.SetDefault("SERVER_PORT", "8080")
-		viper.SetDefault("SERVER_ENV", "development")
-		viper.SetDefault("JWT_ACCESS_EXPIRE_MINUTES", 60) // Aumentado para 60 minutos durante testes
-		viper.SetDefault("JWT_REFRESH_EXPIRE_HOURS", 24)
-		viper.SetDefault("SMTP_PORT", "587")
-		viper.SetDefault("SMTP_USE_TLS", true)
-		viper.SetDefault("SMTP_FROM_NAME", "DashTrack")
-		viper.SetDefault("BCRYPT_COST", 12)
-		viper.SetDefault("PASSWORD_RESET_EXPIRE_HOURS", 1)
-		viper.SetDefault("APP_NAME", "Dashtrack API")
-		viper.SetDefault("APP_VERSION", "1.0.0")
-
-		config = &Config{
-			DBSource:               viper.GetString("DB_SOURCE"),
-			ServerPort:             viper.GetString("SERVER_PORT"),
-			ServerEnv:              viper.GetString("SERVER_ENV"),
-			JWTSecret:              viper.GetString("JWT_SECRET"),
-			JWTAccessExpireMinutes: viper.GetInt("JWT_ACCESS_EXPIRE_MINUTES"),
-			JWTRefreshExpireHours:  viper.GetInt("JWT_REFRESH_EXPIRE_HOURS"),
-			SMTP: SMTPConfig{
-				Host:     viper.GetString("SMTP_HOST"),
-				Port:     viper.GetString("SMTP_PORT"),
-				Username: viper.GetString("SMTP_USERNAME"),
-				Password: viper.GetString("SMTP_PASSWORD"),
-				From:     viper.GetString("SMTP_FROM"),
-				FromName: viper.GetString("SMTP_FROM_NAME"),
-				UseTLS:   viper.GetBool("SMTP_USE_TLS"),
-			},
-			AppName:                  viper.GetString("APP_NAME"),
-			AppVersion:               viper.GetString("APP_VERSION"),
-			AppURL:                   viper.GetString("APP_URL"),
-			BcryptCost:               viper.GetInt("BCRYPT_COST"),
-			PasswordResetExpireHours: viper.GetInt("PASSWORD_RESET_EXPIRE_HOURS"),
+		cfg, err := Load()
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		config = cfg
+	})
+	return config
+}
+
+// Load builds the configuration from, in increasing order of precedence: the defaults,
+// the YAML or JSON file named by CONFIG_FILE (optional; keys are the environment variable
+// names, case-insensitive) and the environment. Secrets are then resolved through the
+// provider selected by SECRET_PROVIDER (see RegisterSecretProvider).
+func Load() (*Config, error) {
+	v := viper.New()
+	v.AutomaticEnv()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
+	// Set defaults
+	v.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	v.SetDefault("DB_MAX_IDLE_CONNS", 10)
+	v.SetDefault("DB_CONN_MAX_LIFETIME_MINUTES", 30)
+	v.SetDefault("DB_QUERY_TIMEOUT_SECONDS", 30)
+	v.SetDefault("DB_SLOW_QUERY_THRESHOLD_MS", 500)
+	v.SetDefault("SERVER_PORT", "8080")
+	v.SetDefault("SERVER_ENV", "development")
+	v.SetDefault("JWT_ACCESS_EXPIRE_MINUTES", 60) // Aumentado para 60 minutos durante testes
+	v.SetDefault("JWT_REFRESH_EXPIRE_HOURS", 24)
+	v.SetDefault("JWT_REMEMBER_ME_REFRESH_EXPIRE_HOURS", 720) // 30 days
+	v.SetDefault("JWT_ACTIVE_KEY_ID", DefaultJWTKeyID)
+	v.SetDefault("SESSION_IDLE_TIMEOUT_MINUTES", 60)
+	v.SetDefault("ONLINE_THRESHOLD_MINUTES", 5)
+	v.SetDefault("AUTH_COOKIE_ENABLED", false)
+	v.SetDefault("AUTH_COOKIE_SECURE", true)
+	v.SetDefault("AUTH_COOKIE_SAMESITE", "strict")
+	v.SetDefault("CLIENT_IP_HEADERS", "X-Forwarded-For,X-Real-IP")
+	v.SetDefault("SMTP_PORT", "587")
+	v.SetDefault("SMTP_USE_TLS", true) // Superseded by SMTP_TLS_MODE, still honoured when it is unset
+	v.SetDefault("SMTP_FROM_NAME", "DashTrack")
+	v.SetDefault("BCRYPT_COST", 12)
+	v.SetDefault("PASSWORD_RESET_EXPIRE_HOURS", 1)
+	v.SetDefault("PASSWORD_HISTORY_SIZE", 5)
+	v.SetDefault("PASSWORD_MAX_AGE_DAYS", 0) // Passwords never expire unless configured
+	v.SetDefault("APP_NAME", "Dashtrack API")
+	v.SetDefault("APP_VERSION", "1.0.0")
+	v.SetDefault("EXPORT_MAX_ROWS", 10000)
+	v.SetDefault("BATCH_MAX_SIZE", 1000)
+	v.SetDefault("EXPORT_RATE_LIMIT", 5)
+	v.SetDefault("EXPORT_RATE_WINDOW_MINUTES", 10)
+	v.SetDefault("AUDIT_REDACT_KEYS", "password,current_password,new_password,token,access_token,refresh_token,session_token,api_token,secret,authorization")
+	v.SetDefault("DOCUMENT_EXPIRY_ALERT_DAYS", 30)
+	v.SetDefault("TRIP_MAX_DURATION_HOURS", 24)
+	v.SetDefault("TEAM_MEMBER_SOFT_DELETE", false)
+	v.SetDefault("IDEMPOTENCY_KEY_TTL_HOURS", 24)
+	v.SetDefault("METRICS_REFRESH_INTERVAL_SECONDS", 60)
+	v.SetDefault("GEOIP_ENABLED", false)
+	v.SetDefault("MQTT_ENABLED", false)
+	v.SetDefault("MQTT_BROKER_URL", "tcp://localhost:1883")
+	v.SetDefault("MQTT_CLIENT_ID", "dashtrack-api")
+	v.SetDefault("MQTT_QOS", 1)
+	v.SetDefault("ACCOUNT_ERASURE_GRACE_DAYS", 30)
+	v.SetDefault("STORAGE_DRIVER", "local")
+	v.SetDefault("STORAGE_LOCAL_DIR", "./uploads")
+	v.SetDefault("STORAGE_PUBLIC_URL", "/uploads")
+	v.SetDefault("S3_REGION", "us-east-1")
+	v.SetDefault("AVATAR_MAX_BYTES", 5<<20)
+	v.SetDefault("AVATAR_MAX_DIMENSION", 512)
+	v.SetDefault("SECRET_PROVIDER", "env")
+	v.SetDefault("SECRETS_DIR", "/run/secrets")
+
+	cfg := &Config{
+		DBSource:                        v.GetString("DB_SOURCE"),
+		DBMaxOpenConns:                  v.GetInt("DB_MAX_OPEN_CONNS"),
+		DBMaxIdleConns:                  v.GetInt("DB_MAX_IDLE_CONNS"),
+		DBConnMaxLifetimeMinutes:        v.GetInt("DB_CONN_MAX_LIFETIME_MINUTES"),
+		DBReplicaSource:                 v.GetString("DB_REPLICA_SOURCE"),
+		DBQueryTimeoutSeconds:           v.GetInt("DB_QUERY_TIMEOUT_SECONDS"),
+		DBSlowQueryThresholdMS:          v.GetInt("DB_SLOW_QUERY_THRESHOLD_MS"),
+		ServerPort:                      v.GetString("SERVER_PORT"),
+		ServerEnv:                       v.GetString("SERVER_ENV"),
+		JWTSecret:                       v.GetString("JWT_SECRET"),
+		JWTAccessExpireMinutes:          v.GetInt("JWT_ACCESS_EXPIRE_MINUTES"),
+		JWTRefreshExpireHours:           v.GetInt("JWT_REFRESH_EXPIRE_HOURS"),
+		JWTRememberMeRefreshExpireHours: v.GetInt("JWT_REMEMBER_ME_REFRESH_EXPIRE_HOURS"),
+		JWTKeys:                         v.GetString("JWT_KEYS"),
+		JWTActiveKeyID:                  v.GetString("JWT_ACTIVE_KEY_ID"),
+		SessionIdleTimeoutMinutes:       v.GetInt("SESSION_IDLE_TIMEOUT_MINUTES"),
+		OnlineThresholdMinutes:          v.GetInt("ONLINE_THRESHOLD_MINUTES"),
+		AuthCookieEnabled:               v.GetBool("AUTH_COOKIE_ENABLED"),
+		AuthCookieDomain:                v.GetString("AUTH_COOKIE_DOMAIN"),
+		AuthCookieSecure:                v.GetBool("AUTH_COOKIE_SECURE"),
+		AuthCookieSameSite:              v.GetString("AUTH_COOKIE_SAMESITE"),
+		CORSAllowedOrigins:              listValue(v, "CORS_ALLOWED_ORIGINS"),
+		TrustedProxies:                  listValue(v, "TRUSTED_PROXIES"),
+		ClientIPHeaders:                 listValue(v, "CLIENT_IP_HEADERS"),
+		TrustedPlatform:                 v.GetString("TRUSTED_PLATFORM"),
+		SMTP: SMTPConfig{
+			Host:     v.GetString("SMTP_HOST"),
+			Port:     v.GetString("SMTP_PORT"),
+			Username: v.GetString("SMTP_USERNAME"),
+			Password: v.GetString("SMTP_PASSWORD"),
+			From:     v.GetString("SMTP_FROM"),
+			FromName: v.GetString("SMTP_FROM_NAME"),
+			TLSMode:  smtpTLSMode(v),
+		},
+		AppName:                       v.GetString("APP_NAME"),
+		AppVersion:                    v.GetString("APP_VERSION"),
+		AppURL:                        v.GetString("APP_URL"),
+		BcryptCost:                    v.GetInt("BCRYPT_COST"),
+		PasswordResetExpireHours:      v.GetInt("PASSWORD_RESET_EXPIRE_HOURS"),
+		PasswordHistorySize:           v.GetInt("PASSWORD_HISTORY_SIZE"),
+		PasswordMaxAgeDays:            v.GetInt("PASSWORD_MAX_AGE_DAYS"),
+		GeoIPEnabled:                  v.GetBool("GEOIP_ENABLED"),
+		GeoIPDatabasePath:             v.GetString("GEOIP_DATABASE_PATH"),
+		ExportMaxRows:                 v.GetInt("EXPORT_MAX_ROWS"),
+		BatchMaxSize:                  v.GetInt("BATCH_MAX_SIZE"),
+		ExportRateLimit:               v.GetInt("EXPORT_RATE_LIMIT"),
+		ExportRateWindowMinutes:       v.GetInt("EXPORT_RATE_WINDOW_MINUTES"),
+		AuditRedactKeys:               listValue(v, "AUDIT_REDACT_KEYS"),
+		DocumentExpiryAlertDays:       v.GetInt("DOCUMENT_EXPIRY_ALERT_DAYS"),
+		TripMaxDurationHours:          v.GetInt("TRIP_MAX_DURATION_HOURS"),
+		TeamMemberSoftDelete:          v.GetBool("TEAM_MEMBER_SOFT_DELETE"),
+		IdempotencyKeyTTLHours:        v.GetInt("IDEMPOTENCY_KEY_TTL_HOURS"),
+		MetricsRefreshIntervalSeconds: v.GetInt("METRICS_REFRESH_INTERVAL_SECONDS"),
+		MQTTEnabled:                   v.GetBool("MQTT_ENABLED"),
+		MQTTBrokerURL:                 v.GetString("MQTT_BROKER_URL"),
+		MQTTClientID:                  v.GetString("MQTT_CLIENT_ID"),
+		MQTTUsername:                  v.GetString("MQTT_USERNAME"),
+		MQTTPassword:                  v.GetString("MQTT_PASSWORD"),
+		MQTTQoS:                       v.GetInt("MQTT_QOS"),
+		AccountErasureGraceDays:       v.GetInt("ACCOUNT_ERASURE_GRACE_DAYS"),
+		StorageDriver:                 v.GetString("STORAGE_DRIVER"),
+		StorageLocalDir:               v.GetString("STORAGE_LOCAL_DIR"),
+		StoragePublicURL:              v.GetString("STORAGE_PUBLIC_URL"),
+		S3Endpoint:                    v.GetString("S3_ENDPOINT"),
+		S3Region:                      v.GetString("S3_REGION"),
+		S3Bucket:                      v.GetString("S3_BUCKET"),
+		S3AccessKeyID:                 v.GetString("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey:             v.GetString("S3_SECRET_ACCESS_KEY"),
+		S3PublicURL:                   v.GetString("S3_PUBLIC_URL"),
+		AvatarMaxBytes:                v.GetInt("AVATAR_MAX_BYTES"),
+		AvatarMaxDimension:            v.GetInt("AVATAR_MAX_DIMENSION"),
+		SecretProvider:                v.GetString("SECRET_PROVIDER"),
+		SecretsDir:                    v.GetString("SECRETS_DIR"),
+	}
+
+	if err := applySecrets(cfg); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// smtpTLSMode reads SMTP_TLS_MODE, falling back to the older SMTP_USE_TLS switch
+func smtpTLSMode(v *viper.Viper) string {
+	if mode := strings.ToLower(strings.TrimSpace(v.GetString("SMTP_TLS_MODE"))); mode != "" {
+		return mode
+	}
+	if v.GetBool("SMTP_USE_TLS") {
+		return SMTPTLSModeStartTLS
+	}
+	return SMTPTLSModeNone
+}
+
+// listValue reads a list setting, given as a comma separated string (environment or file)
+// or as a list in the config file
+func listValue(v *viper.Viper, key string) []string {
+	if items, ok := v.Get(key).([]interface{}); ok {
+		values := []string{}
+		for _, item := range items {
+			if value := strings.TrimSpace(fmt.Sprint(item)); value != "" {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return splitList(v.GetString(key))
+}
+
+// splitList parses a comma separated environment value, dropping empty entries
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
+	}
+	return items
+}
+
+// trustedPlatformHeaders are the client IP headers set by the platforms TRUSTED_PLATFORM
+// accepts
+var trustedPlatformHeaders = map[string]string{
+	"cloudflare":        "CF-Connecting-IP",
+	"google-app-engine": "X-Appengine-Remote-Addr",
+	"fly-io":            "Fly-Client-IP",
+}
+
+// TrustedPlatformHeader returns the client IP header of TRUSTED_PLATFORM, empty when no
+// platform is set
+func (c *Config) TrustedPlatformHeader() string {
+	return trustedPlatformHeaders[strings.ToLower(c.TrustedPlatform)]
+}
 
-		// Validate required fields
-		if config.DBSource == "" {
-			log.Fatal("DB_SOURCE is required")
+// DefaultJWTKeyID is the key ID JWT_SECRET signs and verifies tokens under
+const DefaultJWTKeyID = "default"
+
+// JWTSigningKeyID returns the key ID new tokens are signed with, DefaultJWTKeyID when
+// JWT_ACTIVE_KEY_ID is empty
+func (c *Config) JWTSigningKeyID() string {
+	if c.JWTActiveKeyID == "" {
+		return DefaultJWTKeyID
+	}
+	return c.JWTActiveKeyID
+}
+
+// JWTSigningKeys returns the JWT keys by key ID: the entries of JWT_KEYS plus JWT_SECRET
+// as DefaultJWTKeyID, unless JWT_KEYS defines that ID itself
+func (c *Config) JWTSigningKeys() (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(c.JWTKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
 		}
-		if config.JWTSecret == "" {
-			log.Fatal("JWT_SECRET is required")
+		kid, secret, ok := strings.Cut(entry, ":")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("JWT_KEYS entries must look like kid:secret")
 		}
-	})
-	return config
+		if _, duplicate := keys[kid]; duplicate {
+			return nil, fmt.Errorf("JWT_KEYS defines key %q twice", kid)
+		}
+		keys[kid] = secret
+	}
+	if _, ok := keys[DefaultJWTKeyID]; !ok && c.JWTSecret != "" {
+		keys[DefaultJWTKeyID] = c.JWTSecret
+	}
+	return keys, nil
 }
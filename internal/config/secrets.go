@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SecretProvider resolves sensitive settings. ok is false when the provider has no value
+// for key, in which case the config file or environment value is kept.
+type SecretProvider interface {
+	GetSecret(key string) (value string, ok bool, err error)
+}
+
+// SecretProviderFactory builds a provider from the loaded (non-secret) configuration
+type SecretProviderFactory func(cfg *Config) (SecretProvider, error)
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProviderFactory{
+		"env": func(*Config) (SecretProvider, error) { return EnvSecretProvider{}, nil },
+		"file": func(cfg *Config) (SecretProvider, error) {
+			return FileSecretProvider{Dir: cfg.SecretsDir}, nil
+		},
+		"vault":               unavailableSecretProvider("vault"),
+		"aws-secrets-manager": unavailableSecretProvider("aws-secrets-manager"),
+	}
+)
+
+// RegisterSecretProvider makes a provider selectable with SECRET_PROVIDER=name, replacing
+// any provider registered under the same name. Deployments using Vault or AWS Secrets
+// Manager register their client here before calling LoadConfig.
+func RegisterSecretProvider(name string, factory SecretProviderFactory) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[name] = factory
+}
+
+// newSecretProvider returns the provider selected by cfg.SecretProvider
+func newSecretProvider(cfg *Config) (SecretProvider, error) {
+	secretProvidersMu.RLock()
+	factory, ok := secretProviders[cfg.SecretProvider]
+	secretProvidersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown SECRET_PROVIDER %q", cfg.SecretProvider)
+	}
+	return factory(cfg)
+}
+
+// applySecrets replaces the sensitive settings of cfg with the values of the configured provider
+func applySecrets(cfg *Config) error {
+	provider, err := newSecretProvider(cfg)
+	if err != nil {
+		return err
+	}
+
+	secrets := []struct {
+		key   string
+		value *string
+	}{
+		{"DB_SOURCE", &cfg.DBSource},
+		{"DB_REPLICA_SOURCE", &cfg.DBReplicaSource},
+		{"JWT_SECRET", &cfg.JWTSecret},
+		{"JWT_KEYS", &cfg.JWTKeys},
+		{"SMTP_PASSWORD", &cfg.SMTP.Password},
+		{"S3_SECRET_ACCESS_KEY", &cfg.S3SecretAccessKey},
+		{"MQTT_PASSWORD", &cfg.MQTTPassword},
+	}
+	for _, secret := range secrets {
+		value, ok, err := provider.GetSecret(secret.key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			*secret.value = value
+		}
+	}
+	return nil
+}
+
+// EnvSecretProvider reads secrets from environment variables; it is the default
+type EnvSecretProvider struct{}
+
+// GetSecret returns the environment variable named key
+func (EnvSecretProvider) GetSecret(key string) (string, bool, error) {
+	value, ok := os.LookupEnv(key)
+	return value, ok, nil
+}
+
+// FileSecretProvider reads each secret from a file named after its key in Dir, as mounted
+// by Docker and Kubernetes secrets. Surrounding whitespace is trimmed.
+type FileSecretProvider struct {
+	Dir string
+}
+
+// GetSecret returns the content of Dir/key
+func (p FileSecretProvider) GetSecret(key string) (string, bool, error) {
+	data, err := os.ReadFile(filepath.Join(p.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read secret %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+// unavailableSecretProvider reserves the name of a provider whose client is not compiled in,
+// so selecting it fails clearly instead of silently falling back to the environment
+func unavailableSecretProvider(name string) SecretProviderFactory {
+	return func(*Config) (SecretProvider, error) {
+		return nil, fmt.Errorf("secret provider %q is not available in this build; register it with config.RegisterSecretProvider", name)
+	}
+}
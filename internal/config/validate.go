@@ -0,0 +1,200 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinJWTSecretLength is the shortest accepted JWT_SECRET; HS256 keys below 256 bits are guessable
+const MinJWTSecretLength = 32
+
+// MinSecureBcryptCost is the lowest BCRYPT_COST accepted outside the test environment
+const MinSecureBcryptCost = bcrypt.DefaultCost
+
+// mqttSchemes are the broker URL schemes the MQTT client can dial
+var mqttSchemes = map[string]bool{"tcp": true, "mqtt": true, "ssl": true, "mqtts": true, "ws": true, "wss": true}
+
+// knownJWTSecrets are the placeholder and test secrets shipped with the repository. Anyone
+// who has read the source can sign tokens with them.
+var knownJWTSecrets = map[string]bool{
+	"your-secret-key-here-change-in-production":                                          true,
+	"your-super-secret-jwt-key-change-in-production-make-it-longer-and-more-secure-2024": true,
+	"test-secret-key-min-32-characters-long":                                             true,
+	"test-secret-key":                                                                    true,
+	"test-secret":                                                                        true,
+}
+
+// Validate checks the settings the API cannot run correctly without and returns every
+// problem found, one per line, so a misconfigured deployment fails at startup instead of
+// on the first login or email.
+func (c *Config) Validate() error {
+	var problems []error
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if c.DBSource == "" {
+		fail("DB_SOURCE is required")
+	}
+
+	switch {
+	case c.JWTSecret == "" && c.JWTKeys == "":
+		fail("JWT_SECRET is required")
+	case c.JWTSecret != "" && len(c.JWTSecret) < MinJWTSecretLength:
+		fail("JWT_SECRET must be at least %d characters, got %d", MinJWTSecretLength, len(c.JWTSecret))
+	}
+	if keys, err := c.JWTSigningKeys(); err != nil {
+		fail("%v", err)
+	} else {
+		for kid, secret := range keys {
+			if kid != DefaultJWTKeyID && len(secret) < MinJWTSecretLength {
+				fail("JWT_KEYS key %q must be at least %d characters, got %d", kid, MinJWTSecretLength, len(secret))
+			}
+		}
+		if _, ok := keys[c.JWTSigningKeyID()]; len(keys) > 0 && !ok {
+			fail("JWT_ACTIVE_KEY_ID %q is not JWT_SECRET (%q) or a key of JWT_KEYS", c.JWTSigningKeyID(), DefaultJWTKeyID)
+		}
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				fail("TRUSTED_PROXIES entries must be IPs or CIDRs, got %q", proxy)
+			}
+		}
+	}
+	if c.TrustedPlatform != "" && c.TrustedPlatformHeader() == "" {
+		fail("TRUSTED_PLATFORM must be cloudflare, google-app-engine or fly-io, got %q", c.TrustedPlatform)
+	}
+
+	if port, err := strconv.Atoi(c.ServerPort); err != nil || port < 1 || port > 65535 {
+		fail("SERVER_PORT must be a number between 1 and 65535, got %q", c.ServerPort)
+	}
+
+	if c.JWTAccessExpireMinutes <= 0 {
+		fail("JWT_ACCESS_EXPIRE_MINUTES must be positive, got %d", c.JWTAccessExpireMinutes)
+	}
+	if c.JWTRefreshExpireHours <= 0 {
+		fail("JWT_REFRESH_EXPIRE_HOURS must be positive, got %d", c.JWTRefreshExpireHours)
+	} else if c.JWTAccessExpireMinutes > c.JWTRefreshExpireHours*60 {
+		fail("JWT_REFRESH_EXPIRE_HOURS (%dh) must be longer than JWT_ACCESS_EXPIRE_MINUTES (%dm)",
+			c.JWTRefreshExpireHours, c.JWTAccessExpireMinutes)
+	}
+	if c.JWTRememberMeRefreshExpireHours < c.JWTRefreshExpireHours {
+		fail("JWT_REMEMBER_ME_REFRESH_EXPIRE_HOURS (%dh) must not be shorter than JWT_REFRESH_EXPIRE_HOURS (%dh)",
+			c.JWTRememberMeRefreshExpireHours, c.JWTRefreshExpireHours)
+	}
+	if c.SessionIdleTimeoutMinutes < 0 {
+		fail("SESSION_IDLE_TIMEOUT_MINUTES must not be negative, got %d", c.SessionIdleTimeoutMinutes)
+	}
+	if c.OnlineThresholdMinutes < 1 {
+		fail("ONLINE_THRESHOLD_MINUTES must be at least 1, got %d", c.OnlineThresholdMinutes)
+	}
+	if c.PasswordResetExpireHours <= 0 {
+		fail("PASSWORD_RESET_EXPIRE_HOURS must be positive, got %d", c.PasswordResetExpireHours)
+	}
+	if c.PasswordMaxAgeDays < 0 {
+		fail("PASSWORD_MAX_AGE_DAYS must not be negative, got %d", c.PasswordMaxAgeDays)
+	}
+
+	if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+		fail("BCRYPT_COST must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, c.BcryptCost)
+	}
+
+	if c.DBMaxOpenConns < 0 || c.DBMaxIdleConns < 0 || c.DBConnMaxLifetimeMinutes < 0 || c.DBQueryTimeoutSeconds < 0 || c.DBSlowQueryThresholdMS < 0 {
+		fail("DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME_MINUTES, DB_QUERY_TIMEOUT_SECONDS and DB_SLOW_QUERY_THRESHOLD_MS must not be negative")
+	}
+
+	if c.AuthCookieEnabled {
+		switch strings.ToLower(c.AuthCookieSameSite) {
+		case "strict", "lax", "none":
+		default:
+			fail("AUTH_COOKIE_SAMESITE must be strict, lax or none, got %q", c.AuthCookieSameSite)
+		}
+	}
+
+	switch c.StorageDriver {
+	case "local":
+		if c.StorageLocalDir == "" || c.StoragePublicURL == "" {
+			fail("STORAGE_LOCAL_DIR and STORAGE_PUBLIC_URL are required when STORAGE_DRIVER=local")
+		}
+	case "s3":
+		if c.S3Endpoint == "" || c.S3Bucket == "" || c.S3AccessKeyID == "" || c.S3SecretAccessKey == "" {
+			fail("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when STORAGE_DRIVER=s3")
+		}
+	default:
+		fail("STORAGE_DRIVER must be local or s3, got %q", c.StorageDriver)
+	}
+	if c.BatchMaxSize <= 0 {
+		fail("BATCH_MAX_SIZE must be positive")
+	}
+	if c.AvatarMaxBytes <= 0 || c.AvatarMaxDimension <= 0 {
+		fail("AVATAR_MAX_BYTES and AVATAR_MAX_DIMENSION must be positive")
+	}
+	if c.IdempotencyKeyTTLHours < 0 {
+		fail("IDEMPOTENCY_KEY_TTL_HOURS must not be negative")
+	}
+
+	// Without SMTP_HOST emails are only logged; password resets and security alerts never
+	// reach users, which is only acceptable outside production
+	switch {
+	case c.SMTP.Enabled():
+		if err := c.SMTP.Validate(); err != nil {
+			problems = append(problems, err)
+		}
+	case c.ServerEnv == "production":
+		fail("SMTP_HOST is required in production: password resets and security alerts are sent by email")
+	}
+
+	if c.MQTTEnabled {
+		if broker, err := url.Parse(c.MQTTBrokerURL); err != nil || broker.Host == "" || !mqttSchemes[broker.Scheme] {
+			fail("MQTT_BROKER_URL must be a tcp, mqtt, ssl, mqtts, ws or wss broker URL, got %q", c.MQTTBrokerURL)
+		}
+		if c.MQTTClientID == "" {
+			fail("MQTT_CLIENT_ID is required when MQTT_ENABLED=true")
+		}
+		if c.MQTTQoS < 0 || c.MQTTQoS > 2 {
+			fail("MQTT_QOS must be 0, 1 or 2, got %d", c.MQTTQoS)
+		}
+	}
+
+	// Insecure defaults refuse to start in production and are logged everywhere else but in tests
+	for _, problem := range c.insecureDefaults() {
+		switch c.ServerEnv {
+		case "production":
+			fail("%s", problem)
+		case "test":
+			// Tests run with throwaway secrets and cheap hashes on purpose
+		default:
+			log.Printf("WARNING: %s; this is refused when SERVER_ENV=production", problem)
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// insecureDefaults lists the settings left at values that are only safe for local testing
+func (c *Config) insecureDefaults() []string {
+	var problems []string
+	if knownJWTSecrets[c.JWTSecret] {
+		problems = append(problems, "JWT_SECRET is a published default or test value")
+	}
+	if keys, err := c.JWTSigningKeys(); err == nil {
+		for kid, secret := range keys {
+			if kid != DefaultJWTKeyID && knownJWTSecrets[secret] {
+				problems = append(problems, fmt.Sprintf("JWT_KEYS key %q is a published default or test value", kid))
+			}
+		}
+	}
+	if c.BcryptCost >= bcrypt.MinCost && c.BcryptCost < MinSecureBcryptCost {
+		problems = append(problems, fmt.Sprintf("BCRYPT_COST %d is below %d", c.BcryptCost, MinSecureBcryptCost))
+	}
+	return problems
+}
@@ -3,18 +3,29 @@ package database
 import (
 	"database/sql"
 	"log"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
+// PoolConfig sizes the connection pool. Zero values keep the database/sql defaults
+// (unlimited open connections, 2 idle connections, connections never expire).
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
 // NewDatabase creates and returns a new database connection pool.
-func NewDatabase(dsn string) *sql.DB {
+func NewDatabase(dsn string, pool PoolConfig) *sql.DB {
 	db, err := sql.Open("pgx", dsn)
 	if err != nil {
 		log.Fatalf("could not connect to the database: %v", err)
 	}
 
+	ConfigurePool(db, pool)
+
 	if err := db.Ping(); err != nil {
 		log.Fatalf("could not ping the database: %v", err)
 	}
@@ -25,6 +36,19 @@ func NewDatabase(dsn string) *sql.DB {
 	return db
 }
 
+// ConfigurePool applies the pool settings to db
+func ConfigurePool(db *sql.DB, pool PoolConfig) {
+	if pool.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+}
+
 func runMigrations(db *sql.DB) {
 	migrations := &migrate.FileMigrationSource{
 		Dir: "migrations",
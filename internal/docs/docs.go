@@ -0,0 +1,35 @@
+// Package docs embeds the OpenAPI description of the API and the Swagger UI page.
+package docs
+
+import (
+	_ "embed"
+)
+
+// OpenAPISpec is the OpenAPI 3 description served at /openapi.json
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+// SwaggerUIHTML renders Swagger UI against /openapi.json
+const SwaggerUIHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>DashTrack API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({
+        url: '/openapi.json',
+        dom_id: '#swagger-ui',
+        persistAuthorization: true,
+      });
+    };
+  </script>
+</body>
+</html>
+`
@@ -1,19 +1,28 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // AuditHandler handles audit log HTTP requests
 type AuditHandler struct {
-	auditService *services.AuditService
+	auditService  *services.AuditService
+	exportMaxRows int
 }
 
 // NewAuditHandler creates a new audit handler
@@ -23,8 +32,60 @@ func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
 	}
 }
 
+// SetExportMaxRows caps the number of rows an export streams (0 disables the cap)
+func (h *AuditHandler) SetExportMaxRows(maxRows int) {
+	h.exportMaxRows = maxRows
+}
+
 // GetLogs handles GET /api/v1/audit/logs
 func (h *AuditHandler) GetLogs(c *gin.Context) {
+	filter, ok := parseAuditLogFilter(c)
+	if !ok {
+		return
+	}
+	if !scopeAuditFilter(c, filter) {
+		return
+	}
+
+	// Parse pagination
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	} else {
+		filter.Limit = 50 // Default
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	// Get logs
+	logs, total, err := h.auditService.GetLogs(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve audit logs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":   logs,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// parseAuditLogFilter parses the audit log query filters shared by GetLogs and StreamLogs,
+// responding 400 on invalid values
+func parseAuditLogFilter(c *gin.Context) (*models.AuditLogFilter, bool) {
 	filter := &models.AuditLogFilter{}
 
 	// Parse query parameters
@@ -32,7 +93,7 @@ func (h *AuditHandler) GetLogs(c *gin.Context) {
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id format"})
-			return
+			return nil, false
 		}
 		filter.UserID = &userID
 	}
@@ -41,7 +102,7 @@ func (h *AuditHandler) GetLogs(c *gin.Context) {
 		companyID, err := uuid.Parse(companyIDStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company_id format"})
-			return
+			return nil, false
 		}
 		filter.CompanyID = &companyID
 	}
@@ -68,7 +129,7 @@ func (h *AuditHandler) GetLogs(c *gin.Context) {
 		from, err := time.Parse(time.RFC3339, fromStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format (use RFC3339)"})
-			return
+			return nil, false
 		}
 		filter.From = &from
 	}
@@ -77,45 +138,43 @@ func (h *AuditHandler) GetLogs(c *gin.Context) {
 		to, err := time.Parse(time.RFC3339, toStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format (use RFC3339)"})
-			return
+			return nil, false
 		}
 		filter.To = &to
 	}
 
-	// Parse pagination
-	if limitStr := c.Query("limit"); limitStr != "" {
-		limit, err := strconv.Atoi(limitStr)
-		if err != nil || limit < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
-			return
-		}
-		filter.Limit = limit
-	} else {
-		filter.Limit = 50 // Default
-	}
-
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		offset, err := strconv.Atoi(offsetStr)
-		if err != nil || offset < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
-			return
+	// Metadata containment filter, e.g. metadata={"session_id":"..."}
+	if metadataStr := c.Query("metadata"); metadataStr != "" {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil || len(metadata) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metadata filter (use a non-empty JSON object)"})
+			return nil, false
 		}
-		filter.Offset = offset
+		filter.Metadata = metadata
 	}
 
-	// Get logs
-	logs, total, err := h.auditService.GetLogs(c.Request.Context(), filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve audit logs"})
-		return
-	}
+	return filter, true
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"logs":   logs,
-		"total":  total,
-		"limit":  filter.Limit,
-		"offset": filter.Offset,
-	})
+// scopeAuditFilter restricts filter to the caller's company unless the caller is master,
+// ignoring any company_id from the query string. It writes the error response and returns
+// false when the caller has no company.
+func scopeAuditFilter(c *gin.Context, filter *models.AuditLogFilter) bool {
+	value, exists := c.Get("userContext")
+	userCtx, _ := value.(*models.UserContext)
+	if !exists || userCtx == nil {
+		utils.AuthContextMissingResponse(c)
+		return false
+	}
+	if userCtx.IsMaster {
+		return true
+	}
+	if userCtx.CompanyID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Company access required"})
+		return false
+	}
+	filter.CompanyID = userCtx.CompanyID
+	return true
 }
 
 // GetLogByID handles GET /api/v1/audit/logs/:id
@@ -314,63 +373,92 @@ func (h *AuditHandler) GetByTraceID(c *gin.Context) {
 	})
 }
 
-// ExportLogs handles GET /api/v1/audit/export
-func (h *AuditHandler) ExportLogs(c *gin.Context) {
-	format := c.Query("format")
-	if format == "" {
-		format = "json"
-	}
+// auditStreamChunkRows is the number of rows written between flushes of a streamed export
+const auditStreamChunkRows = 200
 
-	if format != "json" && format != "csv" {
+// StreamLogs handles GET /api/v1/audit/logs/export. It takes the filters of GetLogs and
+// streams the matching logs as JSON or CSV straight from a database cursor. Company admins
+// only get their own company's logs; master gets every company.
+func (h *AuditHandler) StreamLogs(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if !slices.Contains(services.AuditExportFormats, format) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format. Use 'json' or 'csv'"})
 		return
 	}
 
-	filter := &models.AuditLogFilter{}
-
-	// Parse filters (same as GetLogs)
-	if userIDStr := c.Query("user_id"); userIDStr != "" {
-		userID, err := uuid.Parse(userIDStr)
-		if err == nil {
-			filter.UserID = &userID
-		}
+	filter, ok := parseAuditLogFilter(c)
+	if !ok {
+		return
 	}
 
-	if action := c.Query("action"); action != "" {
-		filter.Action = &action
+	if !scopeAuditFilter(c, filter) {
+		return
 	}
 
-	if resource := c.Query("resource"); resource != "" {
-		filter.Resource = &resource
-	}
+	ctx := c.Request.Context()
 
-	if fromStr := c.Query("from"); fromStr != "" {
-		from, err := time.Parse(time.RFC3339, fromStr)
-		if err == nil {
-			filter.From = &from
+	// Headers go out before the first row, so truncation is detected up front
+	if h.exportMaxRows > 0 {
+		total, err := h.auditService.CountLogs(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export logs"})
+			return
+		}
+		if total > int64(h.exportMaxRows) {
+			c.Header("X-Export-Truncated", "true")
+			c.Header("X-Export-Row-Limit", strconv.Itoa(h.exportMaxRows))
+			c.Header("Warning", fmt.Sprintf(`199 - "export truncated to %d rows, narrow the date range"`, h.exportMaxRows))
 		}
+		filter.Limit = h.exportMaxRows
 	}
 
-	if toStr := c.Query("to"); toStr != "" {
-		to, err := time.Parse(time.RFC3339, toStr)
-		if err == nil {
-			filter.To = &to
-		}
+	cursor, err := h.auditService.OpenExportCursor(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export logs"})
+		return
 	}
+	defer cursor.Close()
 
-	data, err := h.auditService.ExportLogs(c.Request.Context(), filter, format)
+	encoder, err := services.NewAuditLogEncoder(format, c.Writer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export logs"})
 		return
 	}
 
-	// Set appropriate headers
-	filename := "audit_logs_" + time.Now().Format("20060102_150405")
-	if format == "json" {
-		c.Header("Content-Disposition", "attachment; filename="+filename+".json")
-		c.Data(http.StatusOK, "application/json", data)
-	} else {
-		c.Header("Content-Disposition", "attachment; filename="+filename+".csv")
-		c.Data(http.StatusOK, "text/csv", data)
+	contentType := "application/json"
+	if format == "csv" {
+		contentType = "text/csv"
 	}
+	filename := "audit_logs_" + time.Now().Format("20060102_150405") + "." + format
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Status(http.StatusOK)
+
+	rows := 0
+	c.Stream(func(w io.Writer) bool {
+		// Stop between chunks once the client is gone instead of reading the query to the end
+		if err := ctx.Err(); err != nil {
+			logger.Warn("Audit log export cancelled", zap.Error(err), zap.Int("rows_written", rows))
+			return false
+		}
+		for i := 0; i < auditStreamChunkRows; i++ {
+			if !cursor.Next() {
+				// The response has started, so a failure can only cut the file short
+				if err := cursor.Err(); err != nil {
+					logger.Error("Audit log export failed mid-stream", zap.Error(err), zap.Int("rows_written", rows))
+					return false
+				}
+				if err := encoder.Close(); err != nil {
+					logger.Warn("Failed to finish audit log export", zap.Error(err))
+				}
+				return false
+			}
+			if err := encoder.Encode(cursor.Log()); err != nil {
+				logger.Warn("Audit log export stopped", zap.Error(err), zap.Int("rows_written", rows))
+				return false
+			}
+			rows++
+		}
+		return true
+	})
 }
@@ -1,17 +1,23 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/services"
@@ -23,16 +29,41 @@ import (
 type AuthHandler struct {
 	userRepo     repository.UserRepositoryInterface
 	authLogRepo  repository.AuthLogRepositoryInterface
+	auditLogRepo repository.AuditLogRepositoryInterface
 	roleRepo     repository.RoleRepositoryInterface
 	tokenService *services.TokenService
-	emailService *services.EmailService
+	emailService services.EmailSender
+	webhooks     *services.WebhookDispatcher
 	bcryptCost   int
+
+	loginDetector *services.SuspiciousLoginDetector
+	ipAllowlist   *services.IPAllowlist
+	passwordAge   *services.PasswordExpiryPolicy
+	cookies       *middleware.SessionCookies
+
+	passwordHistory     repository.PasswordHistoryRepositoryInterface
+	passwordHistorySize int
+
+	readReplica *sqlx.DB
+
+	avatars *services.AvatarService
+
+	profileExports repository.ProfileExportRepositoryInterface
 }
 
+const (
+	// maxLoginAttempts is how many wrong passwords in a row block an account
+	maxLoginAttempts = 3
+	// loginBlockDuration is how long an account stays blocked after maxLoginAttempts
+	loginBlockDuration = 15 * time.Minute
+)
+
 // LoginRequest represents login request payload
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	// RememberMe asks for a longer-lived refresh token (JWT_REMEMBER_ME_REFRESH_EXPIRE_HOURS)
+	RememberMe bool `json:"remember_me"`
 }
 
 // LoginResponse represents login response payload
@@ -41,6 +72,8 @@ type LoginResponse struct {
 	AccessToken  string       `json:"access_token"`
 	RefreshToken string       `json:"refresh_token"`
 	ExpiresIn    int64        `json:"expires_in"` // seconds until access token expires
+	// seconds until the refresh token expires; longer for remember_me logins
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
 }
 
 // RefreshTokenRequest represents refresh token request payload
@@ -50,9 +83,10 @@ type RefreshTokenRequest struct {
 
 // RefreshTokenResponse represents refresh token response payload
 type RefreshTokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int64  `json:"expires_in"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
 }
 
 // ChangePasswordRequest represents change password request payload
@@ -61,6 +95,12 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" validate:"required,min=6"`
 }
 
+// ChangeExpiredPasswordRequest represents the payload that replaces an expired password
+type ChangeExpiredPasswordRequest struct {
+	PasswordChangeToken string `json:"password_change_token" validate:"required"`
+	NewPassword         string `json:"new_password" validate:"required,min=6"`
+}
+
 // UserResponse represents user data in responses (no sensitive info)
 type UserResponse struct {
 	ID        string    `json:"id"`
@@ -74,11 +114,17 @@ type UserResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// UserHistoryResponse represents complete user activity history
+// UserHistoryResponse represents the aggregated user activity history
 type UserHistoryResponse struct {
+	UserID  string             `json:"user_id"`
+	Summary UserHistorySummary `json:"summary"`
+}
+
+// UserActivitiesResponse represents one page of the user activity timeline
+type UserActivitiesResponse struct {
 	UserID     string             `json:"user_id"`
-	Summary    UserHistorySummary `json:"summary"`
 	Activities []UserActivityItem `json:"activities"`
+	NextCursor *string            `json:"next_cursor"`
 }
 
 // UserHistorySummary represents aggregated statistics
@@ -106,7 +152,10 @@ type UserActivityItem struct {
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(userRepo repository.UserRepositoryInterface, authLogRepo repository.AuthLogRepositoryInterface, roleRepo repository.RoleRepositoryInterface, tokenService *services.TokenService, emailService *services.EmailService, bcryptCost int) *AuthHandler {
+func NewAuthHandler(userRepo repository.UserRepositoryInterface, authLogRepo repository.AuthLogRepositoryInterface, roleRepo repository.RoleRepositoryInterface, tokenService *services.TokenService, emailService services.EmailSender, bcryptCost int) *AuthHandler {
+	if emailService == nil {
+		emailService = services.NewNoopEmailService()
+	}
 	return &AuthHandler{
 		userRepo:     userRepo,
 		authLogRepo:  authLogRepo,
@@ -117,6 +166,95 @@ func NewAuthHandler(userRepo repository.UserRepositoryInterface, authLogRepo rep
 	}
 }
 
+// SetAuditLogRepository sets the repository used to record logout-all and suspicious login audit entries
+func (h *AuthHandler) SetAuditLogRepository(repo repository.AuditLogRepositoryInterface) {
+	h.auditLogRepo = repo
+}
+
+// SetPasswordHistory sets the repository used to reject reuse of the last size passwords
+func (h *AuthHandler) SetPasswordHistory(repo repository.PasswordHistoryRepositoryInterface, size int) {
+	h.passwordHistory = repo
+	h.passwordHistorySize = size
+}
+
+// SetWebhookDispatcher sets the dispatcher used to notify company webhooks
+func (h *AuthHandler) SetWebhookDispatcher(dispatcher *services.WebhookDispatcher) {
+	h.webhooks = dispatcher
+}
+
+// SetSuspiciousLoginDetector enables flagging logins from countries the user has never logged in from
+func (h *AuthHandler) SetSuspiciousLoginDetector(detector *services.SuspiciousLoginDetector) {
+	h.loginDetector = detector
+}
+
+// SetIPAllowlist restricts logins to the address ranges configured by each user's company
+func (h *AuthHandler) SetIPAllowlist(allowlist *services.IPAllowlist) {
+	h.ipAllowlist = allowlist
+}
+
+// SetPasswordExpiryPolicy sets the policy that makes users with an old password change it
+// before logging in
+func (h *AuthHandler) SetPasswordExpiryPolicy(policy *services.PasswordExpiryPolicy) {
+	h.passwordAge = policy
+}
+
+// SetSessionCookies makes login and refresh also deliver the tokens as cookies for browser
+// clients; the JSON response is unchanged
+func (h *AuthHandler) SetSessionCookies(cookies *middleware.SessionCookies) {
+	h.cookies = cookies
+}
+
+// SetAvatarService enables profile picture uploads
+func (h *AuthHandler) SetAvatarService(avatars *services.AvatarService) {
+	h.avatars = avatars
+}
+
+// SetProfileExportRepository enables the personal data export
+func (h *AuthHandler) SetProfileExportRepository(repo repository.ProfileExportRepositoryInterface) {
+	h.profileExports = repo
+}
+
+// SetReadReplica runs the user history and activity reports against a read replica
+func (h *AuthHandler) SetReadReplica(replica *sqlx.DB) {
+	h.readReplica = replica
+}
+
+// readDB returns the replica for reporting queries, falling back to the primary
+func (h *AuthHandler) readDB() *sqlx.DB {
+	if h.readReplica != nil {
+		return h.readReplica
+	}
+	return h.tokenService.GetDB()
+}
+
+// setSessionCookies sets the token pair cookies when cookie sessions are enabled
+func (h *AuthHandler) setSessionCookies(c *gin.Context, tokenPair *services.TokenPair) {
+	if h.cookies == nil {
+		return
+	}
+	accessExpiresIn := time.Duration(tokenPair.ExpiresIn) * time.Second
+	refreshExpiresIn := time.Duration(tokenPair.RefreshExpiresIn) * time.Second
+	if err := h.cookies.SetTokens(c, tokenPair.AccessToken, tokenPair.RefreshToken, accessExpiresIn, refreshExpiresIn); err != nil {
+		logger.Error("Failed to set session cookies", zap.Error(err))
+	}
+}
+
+// clearSessionCookies expires the session cookies when cookie sessions are enabled
+func (h *AuthHandler) clearSessionCookies(c *gin.Context) {
+	if h.cookies != nil {
+		h.cookies.Clear(c)
+	}
+}
+
+// respondBindError writes a request body binding failure in the auth handlers' error format
+func respondBindError(c *gin.Context, bindErr *utils.BindError) {
+	body := gin.H{"error": bindErr.Message, "code": bindErr.Code}
+	if len(bindErr.Fields) > 0 {
+		body["fields"] = bindErr.Fields
+	}
+	c.JSON(bindErr.Status, body)
+}
+
 // Helper function to get string value from pointer
 func getStringValue(s *string) string {
 	if s == nil {
@@ -132,8 +270,8 @@ func getStringValue(s *string) string {
 // LoginGin handles login requests using Gin framework
 func (h *AuthHandler) LoginGin(c *gin.Context) {
 	var req LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
 		return
 	}
 
@@ -178,27 +316,43 @@ func (h *AuthHandler) LoginGin(c *gin.Context) {
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		// Password incorrect - increment login attempts
-		newAttempts := user.LoginAttempts + 1
+		// Password incorrect - increment login attempts atomically, so parallel wrong
+		// passwords can't slip past the limit by all reading the same count
+		failures, err := h.userRepo.IncrementLoginAttempts(c.Request.Context(), user.ID, maxLoginAttempts, time.Now().Add(loginBlockDuration))
+		if err != nil {
+			logger.Error("Failed to record failed login attempt", zap.Error(err), zap.String("user_id", user.ID.String()))
+			failures = &models.LoginAttempts{Attempts: user.LoginAttempts + 1}
+		}
+		newAttempts := failures.Attempts
 
-		// Block user if 3 or more failed attempts
+		// Block user once maxLoginAttempts is reached
 		var blockedUntil *time.Time
 		var failureReason string
 
-		if newAttempts >= 3 {
-			blockTime := time.Now().Add(15 * time.Minute) // Block for 15 minutes
-			blockedUntil = &blockTime
+		if newAttempts >= maxLoginAttempts && failures.BlockedUntil != nil {
+			blockedUntil = failures.BlockedUntil
 			failureReason = fmt.Sprintf("Account blocked after %d failed attempts", newAttempts)
 
-			// Send password reset email asynchronously
-			go h.sendBlockedAccountEmail(user.Email, user.Name, blockTime)
+			// Only the failure that started the block notifies
+			if failures.NewlyBlocked {
+				// Send password reset email asynchronously
+				go h.sendBlockedAccountEmail(user.Email, user.Name, *blockedUntil)
+
+				// Notify company webhooks
+				if h.webhooks != nil && user.CompanyID != nil {
+					h.webhooks.Dispatch(c.Request.Context(), *user.CompanyID, models.WebhookEventAccountBlocked, gin.H{
+						"user_id":       user.ID,
+						"email":         user.Email,
+						"attempts":      newAttempts,
+						"blocked_until": blockedUntil.UTC(),
+						"ip_address":    clientIP,
+					})
+				}
+			}
 		} else {
-			failureReason = fmt.Sprintf("Invalid password (attempt %d/3)", newAttempts)
+			failureReason = fmt.Sprintf("Invalid password (attempt %d/%d)", newAttempts, maxLoginAttempts)
 		}
 
-		// Update login attempts and blocked_until
-		_ = h.userRepo.UpdateLoginAttempts(c.Request.Context(), user.ID, newAttempts, blockedUntil)
-
 		// Log failed attempt
 		_ = h.logAuthAttempt(&user.ID, req.Email, false, clientIP, userAgent, failureReason)
 
@@ -212,29 +366,60 @@ func (h *AuthHandler) LoginGin(c *gin.Context) {
 
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error":              "Invalid credentials",
-			"attempts_remaining": 3 - newAttempts,
+			"attempts_remaining": max(maxLoginAttempts-newAttempts, 0),
 		})
 		return
 	}
 
+	// Company IP allowlist: checked only once the password is known, so it can't be used
+	// to probe which accounts exist
+	if !h.loginIPAllowed(c, user, clientIP, userAgent) {
+		return
+	}
+
 	// Password correct - Reset login attempts if any
 	if user.LoginAttempts > 0 || user.BlockedUntil != nil {
 		_ = h.userRepo.UpdateLoginAttempts(c.Request.Context(), user.ID, 0, nil)
 	}
 
+	// An expired password must be changed before any session is issued
+	if !h.loginPasswordCurrent(c, user, req.RememberMe, clientIP, userAgent) {
+		return
+	}
+
+	// Re-hash passwords stored at a lower bcrypt cost than configured, off the request path
+	if h.passwordHashOutdated(user.Password) {
+		go h.upgradePasswordHash(user.ID, user.Password, req.Password)
+	}
+
 	// Update last login
 	_ = h.userRepo.UpdateLastLogin(c.Request.Context(), user.ID)
 
 	// Generate token pair using tokenService (with session management)
-	tokenPair, err := h.tokenService.GenerateTokenPair(c.Request.Context(), user, clientIP, userAgent)
+	generateTokenPair := h.tokenService.GenerateTokenPair
+	if req.RememberMe {
+		generateTokenPair = h.tokenService.GenerateRememberedTokenPair
+	}
+	tokenPair, err := generateTokenPair(c.Request.Context(), user, clientIP, userAgent)
 	if err != nil {
 		_ = h.logAuthAttempt(&user.ID, req.Email, false, clientIP, userAgent, "Failed to generate tokens")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
 		return
 	}
 
+	// Resolve the login country before logging it, so it isn't already among the known ones
+	var country string
+	var newCountry bool
+	if h.loginDetector != nil {
+		country, newCountry = h.loginDetector.Check(c.Request.Context(), user.ID, clientIP)
+	}
+
 	// Log successful login
-	_ = h.logAuthAttempt(&user.ID, req.Email, true, clientIP, userAgent, "")
+	_ = h.logSuccessfulLogin(user.ID, req.Email, clientIP, userAgent, country)
+
+	if newCountry {
+		h.flagSuspiciousLogin(c, user, clientIP, userAgent, country)
+	}
 
 	response := LoginResponse{
 		User: UserResponse{
@@ -246,19 +431,24 @@ func (h *AuthHandler) LoginGin(c *gin.Context) {
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
 		},
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		ExpiresIn:    int64(tokenPair.ExpiresIn),
+		AccessToken:      tokenPair.AccessToken,
+		RefreshToken:     tokenPair.RefreshToken,
+		ExpiresIn:        int64(tokenPair.ExpiresIn),
+		RefreshExpiresIn: int64(tokenPair.RefreshExpiresIn),
 	}
 
+	h.setSessionCookies(c, tokenPair)
 	c.JSON(http.StatusOK, response)
 }
 
 // RefreshTokenGin handles refresh token requests using Gin framework
 func (h *AuthHandler) RefreshTokenGin(c *gin.Context) {
 	var req RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+	if h.cookies != nil && c.Request.ContentLength <= 0 && h.cookies.RefreshToken(c) != "" {
+		// Browser clients send the refresh token cookie instead of a body
+		req.RefreshToken = h.cookies.RefreshToken(c)
+	} else if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
 		return
 	}
 
@@ -274,11 +464,13 @@ func (h *AuthHandler) RefreshTokenGin(c *gin.Context) {
 	}
 
 	response := RefreshTokenResponse{
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		ExpiresIn:    int64(tokenPair.ExpiresIn),
+		AccessToken:      tokenPair.AccessToken,
+		RefreshToken:     tokenPair.RefreshToken,
+		ExpiresIn:        int64(tokenPair.ExpiresIn),
+		RefreshExpiresIn: int64(tokenPair.RefreshExpiresIn),
 	}
 
+	h.setSessionCookies(c, tokenPair)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -287,7 +479,7 @@ func (h *AuthHandler) LogoutGin(c *gin.Context) {
 	// Get user context from middleware
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -310,17 +502,6 @@ func (h *AuthHandler) LogoutGin(c *gin.Context) {
 		return
 	}
 
-	// Get user email for audit log
-	userEmail, _ := c.Get("email")
-	emailStr := ""
-	if userEmail != nil {
-		emailStr = userEmail.(string)
-	}
-
-	// Get IP and User-Agent
-	clientIP := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
-
 	// Get session start time to calculate duration
 	var sessionStart time.Time
 	var sessionDurationMinutes float64
@@ -361,41 +542,6 @@ func (h *AuthHandler) LogoutGin(c *gin.Context) {
 		return
 	}
 
-	// Create audit log entry
-	auditLog := map[string]interface{}{
-		"user_id":     userID,
-		"user_email":  emailStr,
-		"action":      "logout",
-		"resource":    "session",
-		"resource_id": sessionID,
-		"method":      "POST",
-		"path":        c.Request.URL.Path,
-		"ip_address":  clientIP,
-		"user_agent":  userAgent,
-		"success":     true,
-		"status_code": 200,
-		"metadata": map[string]interface{}{
-			"session_id":               sessionID.String(),
-			"session_duration_minutes": sessionDurationMinutes,
-			"logout_time":              utils.Now(),
-		},
-	}
-
-	metadataJSON, _ := json.Marshal(auditLog["metadata"])
-
-	_, err = tx.ExecContext(c.Request.Context(), `
-		INSERT INTO audit_logs (
-			user_id, user_email, action, resource, resource_id, method, path,
-			ip_address, user_agent, metadata, success, status_code, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-	`, userID, emailStr, "logout", "session", sessionID, "POST", c.Request.URL.Path,
-		clientIP, userAgent, metadataJSON, true, 200, time.Now())
-
-	if err != nil {
-		logger.Error("Failed to create audit log for logout", zap.Error(err))
-		// Don't fail logout if audit log fails
-	}
-
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		logger.Error("Failed to commit logout transaction", zap.Error(err))
@@ -403,23 +549,119 @@ func (h *AuthHandler) LogoutGin(c *gin.Context) {
 		return
 	}
 
+	// The audit log entry is written by the AuditAction middleware on the route
+	middleware.SetAuditResourceID(c, sessionID.String())
+	middleware.SetAuditMetadata(c, "session_id", sessionID.String())
+	middleware.SetAuditMetadata(c, "session_duration_minutes", sessionDurationMinutes)
+	middleware.SetAuditMetadata(c, "logout_time", utils.Now())
+
 	logger.Info("User logged out successfully",
 		zap.String("user_id", userID.String()),
 		zap.String("session_id", sessionID.String()),
 		zap.Float64("session_duration_minutes", sessionDurationMinutes))
 
+	h.clearSessionCookies(c)
 	c.JSON(http.StatusOK, gin.H{
 		"message":                  "Logout successful",
 		"session_duration_minutes": sessionDurationMinutes,
 	})
 }
 
+// LogoutAllGin revokes every session of the current user, including the one making the
+// request, so a lost or stolen device is signed out everywhere
+func (h *AuthHandler) LogoutAllGin(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	emailStr := c.GetString("email")
+	clientIP := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
+
+	tx, err := h.tokenService.GetDB().BeginTxx(c.Request.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction for logout-all", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(c.Request.Context(),
+		"UPDATE session_tokens SET revoked = true, revoked_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND revoked = false",
+		userID)
+	if err != nil {
+		logger.Error("Failed to revoke sessions in session_tokens", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+	revoked, _ := result.RowsAffected()
+
+	_, err = tx.ExecContext(c.Request.Context(),
+		"UPDATE user_sessions SET active = false WHERE user_id = $1 AND active = true",
+		userID)
+	if err != nil {
+		logger.Error("Failed to mark sessions inactive in user_sessions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+
+	if h.auditLogRepo != nil {
+		// Method, path and status code are filled in from the request context
+		resourceID := userID.String()
+		auditLog := &models.AuditLog{
+			UserID:     &userID,
+			Action:     "logout_all",
+			Resource:   "session",
+			ResourceID: &resourceID,
+			IPAddress:  clientIP,
+			UserAgent:  userAgent,
+			Metadata: map[string]interface{}{
+				"revoked_sessions": revoked,
+				"logout_time":      utils.Now(),
+			},
+			Success: true,
+		}
+		if emailStr != "" {
+			auditLog.UserEmail = &emailStr
+		}
+
+		if err := h.auditLogRepo.CreateTx(c.Request.Context(), tx, auditLog); err != nil {
+			logger.Error("Failed to create audit log for logout-all", zap.Error(err))
+			// Don't fail logout if audit log fails
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit logout-all transaction", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+
+	logger.Info("User logged out of all sessions",
+		zap.String("user_id", userID.String()),
+		zap.Int64("revoked_sessions", revoked))
+
+	h.clearSessionCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Logged out of all sessions",
+		"revoked_sessions": revoked,
+	})
+}
+
 // ChangePasswordGin handles password change requests using Gin framework
 func (h *AuthHandler) ChangePasswordGin(c *gin.Context) {
 	// Get user context from middleware
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -430,8 +672,8 @@ func (h *AuthHandler) ChangePasswordGin(c *gin.Context) {
 	}
 
 	var req ChangePasswordRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
 		return
 	}
 
@@ -449,75 +691,171 @@ func (h *AuthHandler) ChangePasswordGin(c *gin.Context) {
 		return
 	}
 
+	if !h.replacePassword(c, user, req.NewPassword) {
+		return
+	}
+
+	// The audit log entry is written by the AuditAction middleware on the route
+	middleware.SetAuditResourceID(c, userID.String())
+	middleware.SetAuditMetadata(c, "change_method", "manual")
+	middleware.SetAuditMetadata(c, "changed_at", utils.Now().Format(time.RFC3339))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+}
+
+// ChangeExpiredPasswordGin sets a new password for a user whose login was refused with
+// PASSWORD_EXPIRED, using the password_change_token from that response, and completes
+// the login
+func (h *AuthHandler) ChangeExpiredPasswordGin(c *gin.Context) {
+	var req ChangeExpiredPasswordRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
+		return
+	}
+
+	clientIP := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	claims, err := h.tokenService.ValidatePasswordChangeToken(req.PasswordChangeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired password change token"})
+		return
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), claims.UserID)
+	if err != nil || user == nil || !user.Active || user.PasswordChangedAt.Unix() != claims.PasswordChangedAt {
+		// The token is single use: once the password changes it no longer matches
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired password change token"})
+		return
+	}
+
+	if !h.replacePassword(c, user, req.NewPassword) {
+		return
+	}
+
+	middleware.SetAuditResourceID(c, user.ID.String())
+	middleware.SetAuditMetadata(c, "change_method", "expired")
+	middleware.SetAuditMetadata(c, "changed_at", utils.Now().Format(time.RFC3339))
+
+	_ = h.userRepo.UpdateLastLogin(c.Request.Context(), user.ID)
+
+	generateTokenPair := h.tokenService.GenerateTokenPair
+	if claims.RememberMe {
+		generateTokenPair = h.tokenService.GenerateRememberedTokenPair
+	}
+	tokenPair, err := generateTokenPair(c.Request.Context(), user, clientIP, userAgent)
+	if err != nil {
+		_ = h.logAuthAttempt(&user.ID, user.Email, false, clientIP, userAgent, "Failed to generate tokens")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
+		return
+	}
+
+	_ = h.logSuccessfulLogin(user.ID, user.Email, clientIP, userAgent, "")
+
+	response := LoginResponse{
+		User: UserResponse{
+			ID:        user.ID.String(),
+			Email:     user.Email,
+			Name:      user.Name,
+			Role:      user.Role.Name,
+			Active:    user.Active,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+		AccessToken:      tokenPair.AccessToken,
+		RefreshToken:     tokenPair.RefreshToken,
+		ExpiresIn:        int64(tokenPair.ExpiresIn),
+		RefreshExpiresIn: int64(tokenPair.RefreshExpiresIn),
+	}
+
+	h.setSessionCookies(c, tokenPair)
+	c.JSON(http.StatusOK, response)
+}
+
+// replacePassword checks newPassword against the password history, stores its hash and
+// records the previous one. It writes the error response and returns false on failure.
+func (h *AuthHandler) replacePassword(c *gin.Context, user *models.User, newPassword string) bool {
+	// Reject reuse of the current or a recent password
+	reused, err := isPasswordReused(c.Request.Context(), h.passwordHistory, user.ID, user.Password, newPassword, h.passwordHistorySize)
+	if err != nil {
+		logger.Error("Failed to check password history", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check password history"})
+		return false
+	}
+	if reused {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("New password must not match your current password or any of your last %d passwords", h.passwordHistorySize)})
+		return false
+	}
+
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), h.bcryptCost)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
-		return
+		return false
 	}
 
 	// Update password
-	err = h.userRepo.UpdatePassword(c.Request.Context(), userID, string(hashedPassword))
+	err = h.userRepo.UpdatePassword(c.Request.Context(), user.ID, string(hashedPassword))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
-		return
+		return false
 	}
 
-	// Create audit log for password change
-	clientIP := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
+	// Keep the previous hash so it cannot be reused
+	if h.passwordHistory != nil && h.passwordHistorySize > 0 {
+		if err := h.passwordHistory.Add(c.Request.Context(), user.ID, user.Password, h.passwordHistorySize); err != nil {
+			logger.Error("Failed to record password history",
+				zap.Error(err),
+				zap.String("user_id", user.ID.String()))
+		}
+	}
+
+	return true
+}
 
-	metadata := map[string]interface{}{
-		"change_method": "manual",
-		"ip_address":    clientIP,
-		"user_agent":    userAgent,
-		"changed_at":    utils.Now().Format(time.RFC3339),
+// UpdatePreferencesGin updates the current user's notification preferences
+func (h *AuthHandler) UpdatePreferencesGin(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		utils.AuthContextMissingResponse(c)
+		return
 	}
 
-	resourceIDStr := userID.String()
-	auditLog := &models.AuditLog{
-		ID:         uuid.New(),
-		UserID:     &userID,
-		Action:     "password_change",
-		Resource:   "user",
-		ResourceID: &resourceIDStr,
-		IPAddress:  clientIP,
-		UserAgent:  userAgent,
-		Metadata:   metadata,
-		Success:    true,
-		CreatedAt:  utils.Now(),
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
 	}
 
-	// Store audit log in database
-	query := `
-		INSERT INTO audit_logs (
-			id, user_id, action, resource, resource_id,
-			ip_address, user_agent, metadata, success, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-	`
-
-	metadataJSON, _ := json.Marshal(metadata)
-	_, err = h.tokenService.GetDB().ExecContext(c.Request.Context(), query,
-		auditLog.ID, auditLog.UserID, auditLog.Action, auditLog.Resource,
-		auditLog.ResourceID, auditLog.IPAddress, auditLog.UserAgent,
-		metadataJSON, auditLog.Success, auditLog.CreatedAt,
-	)
+	var req models.UpdatePreferencesRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
+		return
+	}
 
+	prefs, err := h.userRepo.UpdatePreferences(c.Request.Context(), userID, req)
 	if err != nil {
-		logger.Error("Failed to create audit log for password change",
-			zap.Error(err),
-			zap.String("user_id", userID.String()))
-		// Don't fail the request if audit log fails
+		logger.Error("Failed to update user preferences", zap.Error(err), zap.String("user_id", userID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+	if prefs == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Preferences updated successfully",
+		"preferences": prefs,
+	})
 }
 
 // MeGin returns current user information using Gin framework
 func (h *AuthHandler) MeGin(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -554,8 +892,33 @@ func (h *AuthHandler) MeGin(c *gin.Context) {
 }
 
 // GetRolesGin returns available roles using Gin framework
+// Optional query parameters: search (name contains), include_counts=true (adds permission_count),
+// limit and offset. Roles are sorted by name.
 func (h *AuthHandler) GetRolesGin(c *gin.Context) {
-	roles, err := h.roleRepo.GetAll(c.Request.Context())
+	filter := models.RoleFilter{
+		Search:                 strings.TrimSpace(c.Query("search")),
+		IncludePermissionCount: c.Query("include_counts") == "true",
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	roles, err := h.roleRepo.List(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve roles"})
 		return
@@ -564,97 +927,40 @@ func (h *AuthHandler) GetRolesGin(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"roles": roles})
 }
 
-// GetUserHistoryGin returns complete user activity history
-func (h *AuthHandler) GetUserHistoryGin(c *gin.Context) {
+// userHistoryTarget resolves the target user of a history request. Access is checked by
+// the RequireSelfOrPermission middleware on the route (user:read_history).
+// It writes the error response and returns false when the ID is invalid.
+func userHistoryTarget(c *gin.Context) (uuid.UUID, bool) {
 	// Get target user ID from URL parameter
 	targetUserIDStr := c.Param("id")
 	targetUserID, err := uuid.Parse(targetUserIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	// Get current user context for authorization
-	currentUserIDStr, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
-		return
-	}
-
-	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid current user ID"})
-		return
+		return uuid.Nil, false
 	}
 
-	// Authorization: user can only view their own history unless they're admin/master
-	role, _ := c.Get("role_name")
-	roleStr := ""
-	if role != nil {
-		roleStr = role.(string)
-	}
+	return targetUserID, true
+}
 
-	if currentUserID != targetUserID && roleStr != "admin" && roleStr != "master" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own history"})
+// GetUserHistoryGin returns the aggregated activity summary of a user.
+// The activity timeline itself is served by GetUserActivitiesGin.
+func (h *AuthHandler) GetUserHistoryGin(c *gin.Context) {
+	targetUserID, ok := userHistoryTarget(c)
+	if !ok {
 		return
 	}
 
 	// Aggregate data from multiple sources
-	db := h.tokenService.GetDB()
-
-	// 1. Get login statistics from auth_logs
-	var totalLogins, successfulLogins, failedLogins int
-	var lastLoginAt sql.NullTime
-
-	err = db.QueryRowContext(c.Request.Context(), `
-		SELECT 
-			COUNT(*) as total,
-			COUNT(*) FILTER (WHERE success = true) as successful,
-			COUNT(*) FILTER (WHERE success = false) as failed,
-			MAX(created_at) FILTER (WHERE success = true) as last_login
-		FROM auth_logs
-		WHERE user_id = $1
-	`, targetUserID).Scan(&totalLogins, &successfulLogins, &failedLogins, &lastLoginAt)
+	db := h.readDB()
 
-	if err != nil && err != sql.ErrNoRows {
+	summary, err := loadUserHistorySummary(c.Request.Context(), db, targetUserID)
+	if err != nil {
 		logger.Error("Failed to get login statistics", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve history"})
 		return
 	}
 
-	// 2. Get logout count and average session duration from audit_logs
-	var totalLogouts int
-	var avgSessionMinutes sql.NullFloat64
-
-	err = db.QueryRowContext(c.Request.Context(), `
-		SELECT 
-			COUNT(*) as total_logouts,
-			AVG((metadata->>'session_duration_minutes')::float) as avg_duration
-		FROM audit_logs
-		WHERE user_id = $1 AND action = 'logout'
-	`, targetUserID).Scan(&totalLogouts, &avgSessionMinutes)
-
-	if err != nil && err != sql.ErrNoRows {
-		logger.Error("Failed to get logout statistics", zap.Error(err))
-	}
-
-	// 3. Get password changes from audit_logs
-	var passwordChanges int
-	var lastPasswordChangeAt sql.NullTime
-
-	err = db.QueryRowContext(c.Request.Context(), `
-		SELECT 
-			COUNT(*) as total,
-			MAX(created_at) as last_change
-		FROM audit_logs
-		WHERE user_id = $1 AND action = 'password_change'
-	`, targetUserID).Scan(&passwordChanges, &lastPasswordChangeAt)
-
-	if err != nil && err != sql.ErrNoRows {
-		logger.Error("Failed to get password change statistics", zap.Error(err))
-	}
-
-	// 4. Get unique IPs from both auth_logs and audit_logs
+	// Get unique IPs from both auth_logs and audit_logs
 	rows, err := db.QueryContext(c.Request.Context(), `
 		SELECT DISTINCT ip_address FROM (
 			SELECT ip_address FROM auth_logs WHERE user_id = $1 AND ip_address IS NOT NULL
@@ -674,100 +980,240 @@ func (h *AuthHandler) GetUserHistoryGin(c *gin.Context) {
 			}
 		}
 	}
+	summary.UniqueIPs = uniqueIPs
 
-	// 5. Get activity timeline (combine auth_logs and audit_logs)
-	activityRows, err := db.QueryContext(c.Request.Context(), `
-		SELECT 
-			'login' as action,
-			CASE WHEN success THEN 'auth' ELSE 'auth' END as resource,
-			success,
-			COALESCE(ip_address, '') as ip,
-			COALESCE(user_agent, '') as user_agent,
-			NULL::jsonb as details,
-			created_at as timestamp
-		FROM auth_logs
-		WHERE user_id = $1
-		
-		UNION ALL
-		
-		SELECT 
-			action,
-			resource,
-			COALESCE(success, true) as success,
-			ip_address as ip,
-			user_agent,
-			metadata as details,
-			created_at as timestamp
-		FROM audit_logs
-		WHERE user_id = $1
-		
-		ORDER BY timestamp DESC
-		LIMIT 100
-	`, targetUserID)
+	response := UserHistoryResponse{
+		UserID:  targetUserID.String(),
+		Summary: summary,
+	}
 
-	activities := []UserActivityItem{}
-	if err == nil {
-		defer activityRows.Close()
-		for activityRows.Next() {
-			var item UserActivityItem
-			var detailsJSON []byte
-			var ipStr, uaStr sql.NullString
-
-			err := activityRows.Scan(
-				&item.Action,
-				&item.Resource,
-				&item.Success,
-				&ipStr,
-				&uaStr,
-				&detailsJSON,
-				&item.Timestamp,
-			)
-
-			if err == nil {
-				item.IPAddress = ipStr.String
-				item.UserAgent = uaStr.String
-
-				if len(detailsJSON) > 0 {
-					json.Unmarshal(detailsJSON, &item.Details)
-				}
+	c.JSON(http.StatusOK, response)
+}
 
-				activities = append(activities, item)
-			}
+const (
+	defaultActivitiesLimit = 50
+	maxActivitiesLimit     = 200
+)
+
+// activityCursor marks the last activity of a page; the next page starts strictly after it
+type activityCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// encodeActivityCursor returns an opaque cursor for the given activity
+func encodeActivityCursor(timestamp time.Time, id uuid.UUID) string {
+	raw := timestamp.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeActivityCursor parses a cursor produced by encodeActivityCursor
+func decodeActivityCursor(cursor string) (*activityCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &activityCursor{Timestamp: timestamp, ID: id}, nil
+}
+
+// GetUserActivitiesGin returns the activity timeline of a user (auth_logs and
+// audit_logs combined), newest first, paginated with an opaque cursor
+func (h *AuthHandler) GetUserActivitiesGin(c *gin.Context) {
+	targetUserID, ok := userHistoryTarget(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultActivitiesLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxActivitiesLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxActivitiesLimit)})
+			return
 		}
+		limit = parsed
 	}
 
-	// Build response
-	summary := UserHistorySummary{
-		TotalLogins:           totalLogins,
-		SuccessfulLogins:      successfulLogins,
-		FailedLogins:          failedLogins,
-		TotalLogouts:          totalLogouts,
-		AverageSessionMinutes: 0,
-		PasswordChanges:       passwordChanges,
-		UniqueIPs:             uniqueIPs,
-		LastLoginAt:           nil,
-		LastPasswordChangeAt:  nil,
+	var cursor *activityCursor
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		var err error
+		cursor, err = decodeActivityCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 	}
 
-	if avgSessionMinutes.Valid {
-		summary.AverageSessionMinutes = avgSessionMinutes.Float64
+	// Activity timeline combining auth_logs and audit_logs, keyset-paginated on (occurred_at, id).
+	// One extra row is fetched to know whether another page exists.
+	query := `
+		SELECT id, action, resource, success, ip, user_agent, details, occurred_at FROM (
+			SELECT 
+				id,
+				'login' as action,
+				'auth' as resource,
+				success,
+				COALESCE(ip_address, '') as ip,
+				COALESCE(user_agent, '') as user_agent,
+				NULL::jsonb as details,
+				created_at as occurred_at
+			FROM auth_logs
+			WHERE user_id = $1
+			
+			UNION ALL
+			
+			SELECT 
+				id,
+				action,
+				resource,
+				COALESCE(success, true) as success,
+				ip_address as ip,
+				user_agent,
+				metadata as details,
+				created_at as occurred_at
+			FROM audit_logs
+			WHERE user_id = $1
+		) AS activities`
+
+	args := []interface{}{targetUserID}
+	if cursor != nil {
+		query += ` WHERE (occurred_at, id) < ($2, $3)`
+		args = append(args, cursor.Timestamp, cursor.ID)
+	}
+	query += fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit+1)
+
+	activityRows, err := h.readDB().QueryContext(c.Request.Context(), query, args...)
+	if err != nil {
+		logger.Error("Failed to get user activities", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve activities"})
+		return
 	}
+	defer activityRows.Close()
 
-	if lastLoginAt.Valid {
-		summary.LastLoginAt = &lastLoginAt.Time
+	activities := []UserActivityItem{}
+	ids := []uuid.UUID{}
+	for activityRows.Next() {
+		var item UserActivityItem
+		var id uuid.UUID
+		var detailsJSON []byte
+		var ipStr, uaStr sql.NullString
+
+		err := activityRows.Scan(
+			&id,
+			&item.Action,
+			&item.Resource,
+			&item.Success,
+			&ipStr,
+			&uaStr,
+			&detailsJSON,
+			&item.Timestamp,
+		)
+		if err != nil {
+			logger.Error("Failed to scan user activity", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve activities"})
+			return
+		}
+
+		item.IPAddress = ipStr.String
+		item.UserAgent = uaStr.String
+
+		if len(detailsJSON) > 0 {
+			json.Unmarshal(detailsJSON, &item.Details)
+		}
+
+		activities = append(activities, item)
+		ids = append(ids, id)
 	}
 
-	if lastPasswordChangeAt.Valid {
-		summary.LastPasswordChangeAt = &lastPasswordChangeAt.Time
+	var nextCursor *string
+	if len(activities) > limit {
+		activities = activities[:limit]
+		last := encodeActivityCursor(activities[limit-1].Timestamp, ids[limit-1])
+		nextCursor = &last
 	}
 
-	response := UserHistoryResponse{
+	c.JSON(http.StatusOK, UserActivitiesResponse{
 		UserID:     targetUserID.String(),
-		Summary:    summary,
 		Activities: activities,
+		NextCursor: nextCursor,
+	})
+}
+
+// loadUserHistorySummary aggregates login, logout and password change statistics.
+// Each summary field is assigned exactly once, right after the query that produces it;
+// UniqueIPs is left for the caller to fill.
+func loadUserHistorySummary(ctx context.Context, db *sqlx.DB, userID uuid.UUID) (UserHistorySummary, error) {
+	var summary UserHistorySummary
+
+	// 1. Login statistics from auth_logs
+	var lastLoginAt sql.NullTime
+	err := db.QueryRowContext(ctx, `
+		SELECT 
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE success = true) as successful,
+			COUNT(*) FILTER (WHERE success = false) as failed,
+			MAX(created_at) FILTER (WHERE success = true) as last_login
+		FROM auth_logs
+		WHERE user_id = $1
+	`, userID).Scan(&summary.TotalLogins, &summary.SuccessfulLogins, &summary.FailedLogins, &lastLoginAt)
+	if err != nil && err != sql.ErrNoRows {
+		return summary, err
+	}
+	if lastLoginAt.Valid {
+		summary.LastLoginAt = &lastLoginAt.Time
 	}
 
-	c.JSON(http.StatusOK, response)
+	// 2. Logout count and average session duration from audit_logs.
+	// AVG is NULL when no logout recorded a duration, which maps to 0.
+	var avgSessionMinutes sql.NullFloat64
+	err = db.QueryRowContext(ctx, `
+		SELECT 
+			COUNT(*) as total_logouts,
+			AVG((metadata->>'session_duration_minutes')::float) as avg_duration
+		FROM audit_logs
+		WHERE user_id = $1 AND action = 'logout'
+	`, userID).Scan(&summary.TotalLogouts, &avgSessionMinutes)
+	if err != nil && err != sql.ErrNoRows {
+		logger.Error("Failed to get logout statistics", zap.Error(err))
+	}
+	if avgSessionMinutes.Valid {
+		summary.AverageSessionMinutes = avgSessionMinutes.Float64
+	}
+
+	// 3. Password changes from audit_logs
+	var lastPasswordChangeAt sql.NullTime
+	err = db.QueryRowContext(ctx, `
+		SELECT 
+			COUNT(*) as total,
+			MAX(created_at) as last_change
+		FROM audit_logs
+		WHERE user_id = $1 AND action = 'password_change'
+	`, userID).Scan(&summary.PasswordChanges, &lastPasswordChangeAt)
+	if err != nil && err != sql.ErrNoRows {
+		logger.Error("Failed to get password change statistics", zap.Error(err))
+	}
+	if lastPasswordChangeAt.Valid {
+		summary.LastPasswordChangeAt = &lastPasswordChangeAt.Time
+	}
+
+	return summary, nil
 }
 
 // ForgotPasswordRequest represents forgot password request payload
@@ -784,8 +1230,8 @@ type ResetPasswordRequest struct {
 // ForgotPasswordGin handles forgot password requests using Gin framework
 func (h *AuthHandler) ForgotPasswordGin(c *gin.Context) {
 	var req ForgotPasswordRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
 		return
 	}
 
@@ -820,8 +1266,8 @@ func (h *AuthHandler) ForgotPasswordGin(c *gin.Context) {
 // ResetPasswordGin handles password reset using Gin framework
 func (h *AuthHandler) ResetPasswordGin(c *gin.Context) {
 	var req ResetPasswordRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		respondBindError(c, bindErr)
 		return
 	}
 
@@ -860,25 +1306,212 @@ func (h *AuthHandler) logAuthAttempt(userID *uuid.UUID, email string, success bo
 		authLog.FailureReason = &failureReason
 	}
 
+	return h.createAuthLog(authLog)
+}
+
+// logSuccessfulLogin logs a successful login along with the country it came from, if known
+func (h *AuthHandler) logSuccessfulLogin(userID uuid.UUID, email, ipAddress, userAgent, countryCode string) error {
+	authLog := &models.AuthLog{
+		ID:           uuid.New(),
+		UserID:       &userID,
+		EmailAttempt: email,
+		Success:      true,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+	}
+
+	if countryCode != "" {
+		authLog.CountryCode = &countryCode
+	}
+
+	return h.createAuthLog(authLog)
+}
+
+// createAuthLog stores an auth log, logging rather than failing the request on error
+func (h *AuthHandler) createAuthLog(authLog *models.AuthLog) error {
 	err := h.authLogRepo.Create(authLog)
 	if err != nil {
 		logger.Error("Failed to log auth attempt",
 			zap.Error(err),
-			zap.String("email", email),
-			zap.Bool("success", success))
+			zap.String("email", authLog.EmailAttempt),
+			zap.Bool("success", authLog.Success))
 	}
 
 	return err
 }
 
-// sendBlockedAccountEmail sends an email to user when account is blocked
-func (h *AuthHandler) sendBlockedAccountEmail(email, name string, blockedUntil time.Time) {
-	if h.emailService == nil {
-		logger.Warn("Email service not available, skipping blocked account email",
-			zap.String("email", email))
+// ErrCodeIPNotAllowed is returned when a login comes from outside the company's IP allowlist
+const ErrCodeIPNotAllowed = "IP_NOT_ALLOWED"
+
+// loginIPAllowed checks clientIP against the IP allowlist of the user's company. Master
+// users and users without a company are never restricted. It writes the error response,
+// auth log and audit entry and returns false when the login must be rejected.
+func (h *AuthHandler) loginIPAllowed(c *gin.Context, user *models.User, clientIP, userAgent string) bool {
+	if h.ipAllowlist == nil || user.CompanyID == nil || (user.Role != nil && user.Role.Name == "master") {
+		return true
+	}
+
+	allowed, err := h.ipAllowlist.Allows(c.Request.Context(), *user.CompanyID, clientIP)
+	if err != nil {
+		logger.Error("Failed to check company IP allowlist",
+			zap.Error(err),
+			zap.String("company_id", user.CompanyID.String()))
+		_ = h.logAuthAttempt(&user.ID, user.Email, false, clientIP, userAgent, "Failed to check IP allowlist")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check IP allowlist"})
+		return false
+	}
+	if allowed {
+		return true
+	}
+
+	_ = h.logAuthAttempt(&user.ID, user.Email, false, clientIP, userAgent, "IP address not in company allowlist")
+
+	if h.auditLogRepo != nil {
+		resourceID := user.ID.String()
+		auditLog := &models.AuditLog{
+			UserID:     &user.ID,
+			UserEmail:  &user.Email,
+			CompanyID:  user.CompanyID,
+			Action:     "login_ip_blocked",
+			Resource:   "user",
+			ResourceID: &resourceID,
+			IPAddress:  clientIP,
+			UserAgent:  userAgent,
+			Metadata: map[string]interface{}{
+				"reason": "ip_not_in_allowlist",
+			},
+			Success:   false,
+			CreatedAt: utils.Now(),
+		}
+
+		if err := h.auditLogRepo.Create(c.Request.Context(), auditLog); err != nil {
+			logger.Error("Failed to create audit log for blocked login IP",
+				zap.Error(err),
+				zap.String("user_id", user.ID.String()))
+		}
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "Login is not allowed from this IP address",
+		"code":  ErrCodeIPNotAllowed,
+	})
+	return false
+}
+
+// passwordHashOutdated reports whether hash was created with a lower bcrypt cost than configured
+func (h *AuthHandler) passwordHashOutdated(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost < h.bcryptCost
+}
+
+// upgradePasswordHash stores password, already verified against currentHash, hashed at the
+// configured bcrypt cost. It runs after the login response, so failures are only logged.
+func (h *AuthHandler) upgradePasswordHash(userID uuid.UUID, currentHash, password string) {
+	upgraded, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+	if err != nil {
+		logger.Warn("Failed to re-hash password at the configured cost",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		return
+	}
+
+	if err := h.userRepo.UpgradePasswordHash(context.Background(), userID, currentHash, string(upgraded)); err != nil {
+		logger.Warn("Failed to store upgraded password hash",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
 		return
 	}
 
+	logger.Debug("Upgraded password hash", zap.String("user_id", userID.String()), zap.Int("bcrypt_cost", h.bcryptCost))
+}
+
+// ErrCodePasswordExpired is returned when a login must change its expired password first
+const ErrCodePasswordExpired = "PASSWORD_EXPIRED"
+
+// loginPasswordCurrent checks the user's password age against the rotation policy. When
+// the password expired it responds 403 with a password_change_token, to be exchanged at
+// /auth/change-expired-password, and returns false.
+func (h *AuthHandler) loginPasswordCurrent(c *gin.Context, user *models.User, rememberMe bool, clientIP, userAgent string) bool {
+	if h.passwordAge == nil {
+		return true
+	}
+
+	expired, err := h.passwordAge.Expired(c.Request.Context(), user)
+	if err != nil {
+		logger.Error("Failed to check password age",
+			zap.Error(err),
+			zap.String("user_id", user.ID.String()))
+		_ = h.logAuthAttempt(&user.ID, user.Email, false, clientIP, userAgent, "Failed to check password age")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check password age"})
+		return false
+	}
+	if !expired {
+		return true
+	}
+
+	changeToken, err := h.tokenService.GeneratePasswordChangeToken(user, rememberMe)
+	if err != nil {
+		_ = h.logAuthAttempt(&user.ID, user.Email, false, clientIP, userAgent, "Failed to generate tokens")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
+		return false
+	}
+
+	_ = h.logAuthAttempt(&user.ID, user.Email, false, clientIP, userAgent, "Password expired")
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":                 "Password expired, choose a new password to continue",
+		"code":                  ErrCodePasswordExpired,
+		"password_change_token": changeToken,
+		"expires_in":            int64(services.PasswordChangeTokenTTL.Seconds()),
+	})
+	return false
+}
+
+// flagSuspiciousLogin records a suspicious_login audit entry and emails the user about a
+// login from a country they have never logged in from. The login itself is still allowed.
+func (h *AuthHandler) flagSuspiciousLogin(c *gin.Context, user *models.User, clientIP, userAgent, countryCode string) {
+	logger.Warn("Login from a new country",
+		zap.String("user_id", user.ID.String()),
+		zap.String("country", countryCode),
+		zap.String("ip", clientIP))
+
+	if h.auditLogRepo != nil {
+		// Method, path and status code are filled in from the request context
+		resourceID := user.ID.String()
+		auditLog := &models.AuditLog{
+			UserID:     &user.ID,
+			UserEmail:  &user.Email,
+			CompanyID:  user.CompanyID,
+			Action:     "suspicious_login",
+			Resource:   "user",
+			ResourceID: &resourceID,
+			IPAddress:  clientIP,
+			UserAgent:  userAgent,
+			Metadata: map[string]interface{}{
+				"reason":       "new_country",
+				"country_code": countryCode,
+			},
+			Success:   true,
+			CreatedAt: utils.Now(),
+		}
+
+		if err := h.auditLogRepo.Create(c.Request.Context(), auditLog); err != nil {
+			logger.Error("Failed to create audit log for suspicious login",
+				zap.Error(err),
+				zap.String("user_id", user.ID.String()))
+		}
+	}
+
+	go func(email, name string) {
+		if err := h.emailService.SendSuspiciousLoginAlert(email, name, clientIP, userAgent, countryCode); err != nil {
+			logger.Error("Failed to send suspicious login alert email",
+				zap.Error(err),
+				zap.String("email", email))
+		}
+	}(user.Email, user.Name)
+}
+
+// sendBlockedAccountEmail sends an email to user when account is blocked
+func (h *AuthHandler) sendBlockedAccountEmail(email, name string, blockedUntil time.Time) {
 	subject := "Conta Temporariamente Bloqueada - DashTrack"
 
 	// Formatar data em português (timezone de Brasília)
@@ -958,13 +1591,34 @@ func (h *AuthHandler) sendBlockedAccountEmail(email, name string, blockedUntil t
     </div>
 </body>
 </html>
+`, name, blockedDate, minutesRemaining)
+
+	textBody := fmt.Sprintf(`Olá %s,
+
+Sua conta DashTrack foi temporariamente bloqueada devido a 3 tentativas consecutivas de login com senha incorreta.
+
+Bloqueio expira em: %s (aproximadamente %d minutos)
+
+Por segurança, recomendamos fortemente que você redefina sua senha:
+1. Acesse a plataforma DashTrack
+2. Na tela de login, clique em "Esqueci minha senha"
+3. Digite seu email e receba um código de verificação
+4. Use o código para criar uma nova senha segura
+
+Se você não reconhece estas tentativas de login, sua conta pode estar sob ataque. Entre em contato com o suporte imediatamente.
+
+--
+DashTrack - Sistema de Gestão de Entregas
+Este é um email automático, não responda.
 `, name, blockedDate, minutesRemaining)
 
 	err := h.emailService.SendEmail(services.EmailData{
-		To:      email,
-		Subject: subject,
-		Body:    body,
-		IsHTML:  true,
+		Type:     services.EmailTypeBlockedAccount,
+		To:       email,
+		Subject:  subject,
+		Body:     body,
+		TextBody: textBody,
+		IsHTML:   true,
 	})
 
 	if err != nil {
@@ -980,12 +1634,6 @@ func (h *AuthHandler) sendBlockedAccountEmail(email, name string, blockedUntil t
 
 // sendNewSessionAlert sends an email when a new session is created and old ones are revoked
 func (h *AuthHandler) sendNewSessionAlert(email, name, newIP, newUserAgent string, revokedCount int) {
-	if h.emailService == nil {
-		logger.Warn("Email service not available, skipping new session alert",
-			zap.String("email", email))
-		return
-	}
-
 	subject := "Nova Sessão Detectada - DashTrack"
 	loginTime := utils.FormatBrasiliaDefault(utils.Now())
 
@@ -1052,13 +1700,32 @@ func (h *AuthHandler) sendNewSessionAlert(email, name, newIP, newUserAgent strin
     </div>
 </body>
 </html>
+`, name, loginTime, newIP, newUserAgent, revokedCount)
+
+	textBody := fmt.Sprintf(`Olá %s,
+
+Detectamos um novo login na sua conta DashTrack.
+
+Data/Hora: %s
+Endereço IP: %s
+Dispositivo: %s
+
+Você tinha %d sessão(ões) ativa(s) que foi(ram) encerrada(s) automaticamente para permitir este novo login, pois o limite máximo é de 3 sessões simultâneas.
+
+Não foi você? Altere sua senha imediatamente e revogue todas as sessões ativas no painel de controle.
+
+--
+DashTrack - Sistema de Gestão de Entregas
+Este é um email automático, não responda.
 `, name, loginTime, newIP, newUserAgent, revokedCount)
 
 	err := h.emailService.SendEmail(services.EmailData{
-		To:      email,
-		Subject: subject,
-		Body:    body,
-		IsHTML:  true,
+		Type:     services.EmailTypeNewSession,
+		To:       email,
+		Subject:  subject,
+		Body:     body,
+		TextBody: textBody,
+		IsHTML:   true,
 	})
 
 	if err != nil {
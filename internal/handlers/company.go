@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,7 +11,9 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 
+	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/utils"
@@ -17,8 +21,10 @@ import (
 
 // CompanyHandler handles company-related HTTP requests
 type CompanyHandler struct {
-	companyRepo *repository.CompanyRepository
-	tracer      trace.Tracer
+	companyRepo  *repository.CompanyRepository
+	userRepo     *repository.UserRepository
+	auditLogRepo repository.AuditLogRepositoryInterface
+	tracer       trace.Tracer
 }
 
 // NewCompanyHandler creates a new company handler
@@ -29,6 +35,16 @@ func NewCompanyHandler(companyRepo *repository.CompanyRepository) *CompanyHandle
 	}
 }
 
+// SetUserRepository sets the repository used to move or deactivate users when deleting a company
+func (h *CompanyHandler) SetUserRepository(repo *repository.UserRepository) {
+	h.userRepo = repo
+}
+
+// SetAuditLogRepository sets the repository used to record bulk user changes
+func (h *CompanyHandler) SetAuditLogRepository(repo repository.AuditLogRepositoryInterface) {
+	h.auditLogRepo = repo
+}
+
 // CreateCompany creates a new company (Master only)
 func (h *CompanyHandler) CreateCompany(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "CompanyHandler.CreateCompany")
@@ -37,7 +53,7 @@ func (h *CompanyHandler) CreateCompany(c *gin.Context) {
 	// Check if user is master
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -101,7 +117,7 @@ func (h *CompanyHandler) GetCompanies(c *gin.Context) {
 	// Check if user is master
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -157,7 +173,7 @@ func (h *CompanyHandler) GetCompany(c *gin.Context) {
 	// Check user context
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -201,7 +217,7 @@ func (h *CompanyHandler) UpdateCompany(c *gin.Context) {
 	// Check user context
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -281,7 +297,10 @@ func (h *CompanyHandler) UpdateCompany(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Company updated successfully", company)
 }
 
-// DeleteCompany soft deletes a company (Master only)
+// DeleteCompany soft deletes a company (Master only). Companies that still have active
+// users, vehicles or teams are rejected with 409 and the remaining counts. The users
+// query parameter handles the company's users first: "reassign" moves them to
+// target_company_id and "deactivate" deactivates them.
 func (h *CompanyHandler) DeleteCompany(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "CompanyHandler.DeleteCompany")
 	defer span.End()
@@ -289,7 +308,7 @@ func (h *CompanyHandler) DeleteCompany(c *gin.Context) {
 	// Check if user is master
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -306,8 +325,40 @@ func (h *CompanyHandler) DeleteCompany(c *gin.Context) {
 		return
 	}
 
+	company, err := h.companyRepo.GetByID(ctx, companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve company")
+		return
+	}
+
+	if company == nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Not Found", "Company not found")
+		return
+	}
+
+	if usersAction := c.Query("users"); usersAction != "" {
+		if !h.handleCompanyUsers(c, ctx, span, userCtx, company, usersAction) {
+			return
+		}
+	}
+
 	err = h.companyRepo.Delete(ctx, companyID)
 	if err != nil {
+		if errors.Is(err, repository.ErrCompanyHasDependents) {
+			// Report what is still active so it can be deactivated or moved first
+			dependents, countErr := h.companyRepo.CountActiveDependents(ctx, companyID)
+			if countErr != nil {
+				span.RecordError(countErr)
+				utils.ErrorResponse(c, http.StatusConflict, "Conflict", err.Error())
+				return
+			}
+			utils.ErrorResponse(c, http.StatusConflict, "Conflict", gin.H{
+				"message":    "Company still has active users, vehicles or teams",
+				"dependents": dependents,
+			})
+			return
+		}
 		span.RecordError(err)
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to delete company")
 		return
@@ -318,6 +369,109 @@ func (h *CompanyHandler) DeleteCompany(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Company deleted successfully", nil)
 }
 
+// handleCompanyUsers moves or deactivates the users of a company about to be deleted and
+// records the bulk change in the audit log. Vehicles and teams are checked first so users
+// are left untouched when the deletion would be rejected anyway. It writes the error
+// response and returns false on failure.
+func (h *CompanyHandler) handleCompanyUsers(c *gin.Context, ctx context.Context, span trace.Span, userCtx *models.UserContext, company *models.Company, action string) bool {
+	if action != "reassign" && action != "deactivate" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid users parameter, expected 'reassign' or 'deactivate'")
+		return false
+	}
+
+	if h.userRepo == nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "User handling is not configured")
+		return false
+	}
+
+	var targetCompanyID uuid.UUID
+	if action == "reassign" {
+		var err error
+		targetCompanyID, err = uuid.Parse(c.Query("target_company_id"))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Bad Request", "A valid target_company_id is required to reassign users")
+			return false
+		}
+		if targetCompanyID == company.ID {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Bad Request", "Target company must differ from the company being deleted")
+			return false
+		}
+
+		target, err := h.companyRepo.GetByID(ctx, targetCompanyID)
+		if err != nil {
+			span.RecordError(err)
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve target company")
+			return false
+		}
+		if target == nil || target.Status != "active" {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Bad Request", "Target company not found or not active")
+			return false
+		}
+	}
+
+	dependents, err := h.companyRepo.CountActiveDependents(ctx, company.ID)
+	if err != nil {
+		span.RecordError(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to check company dependents")
+		return false
+	}
+	if dependents.ActiveVehicles > 0 || dependents.ActiveTeams > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Conflict", gin.H{
+			"message":    "Company still has active vehicles or teams",
+			"dependents": dependents,
+		})
+		return false
+	}
+
+	var affected int64
+	metadata := map[string]interface{}{
+		"operation":    "company_delete_users",
+		"users_action": action,
+		"company_name": company.Name,
+	}
+	if action == "reassign" {
+		affected, err = h.userRepo.ReassignCompany(ctx, company.ID, targetCompanyID)
+		metadata["target_company_id"] = targetCompanyID.String()
+	} else {
+		affected, err = h.userRepo.DeactivateByCompany(ctx, company.ID)
+	}
+	if err != nil {
+		span.RecordError(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to update company users")
+		return false
+	}
+	metadata["affected_users"] = affected
+
+	span.SetAttributes(
+		attribute.String("company.users_action", action),
+		attribute.Int64("company.affected_users", affected),
+	)
+
+	if h.auditLogRepo != nil {
+		resourceIDStr := company.ID.String()
+		auditLog := &models.AuditLog{
+			UserID:     &userCtx.UserID,
+			CompanyID:  &company.ID,
+			Action:     "UPDATE",
+			Resource:   "user",
+			ResourceID: &resourceIDStr,
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.GetHeader("User-Agent"),
+			Metadata:   metadata,
+			Success:    true,
+			CreatedAt:  utils.Now(),
+		}
+		if err := h.auditLogRepo.Create(ctx, auditLog); err != nil {
+			// Don't fail the deletion if the audit log fails
+			logger.Error("Failed to create audit log for company users change",
+				zap.Error(err),
+				zap.String("company_id", company.ID.String()))
+		}
+	}
+
+	return true
+}
+
 // GetCompanyStats retrieves company statistics
 func (h *CompanyHandler) GetCompanyStats(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "CompanyHandler.GetCompanyStats")
@@ -326,7 +480,7 @@ func (h *CompanyHandler) GetCompanyStats(c *gin.Context) {
 	// Check user context
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -365,7 +519,7 @@ func (h *CompanyHandler) GetMyCompany(c *gin.Context) {
 	// Check user context
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User context not found")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
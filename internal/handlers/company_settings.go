@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// CompanySettingsHandler handles company settings HTTP requests
+type CompanySettingsHandler struct {
+	settingsRepo repository.CompanySettingsRepositoryInterface
+	tracer       trace.Tracer
+}
+
+// NewCompanySettingsHandler creates a new company settings handler
+func NewCompanySettingsHandler(settingsRepo repository.CompanySettingsRepositoryInterface) *CompanySettingsHandler {
+	return &CompanySettingsHandler{
+		settingsRepo: settingsRepo,
+		tracer:       otel.Tracer("company-settings-handler"),
+	}
+}
+
+// GetCompanySettings returns the company's settings, with defaults for the unset ones
+func (h *CompanySettingsHandler) GetCompanySettings(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "CompanySettingsHandler.GetCompanySettings")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	record, err := h.settingsRepo.Get(ctx, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve company settings")
+		return
+	}
+
+	span.SetAttributes(attribute.String("company.id", companyID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Company settings retrieved successfully", record)
+}
+
+// UpdateCompanySettings replaces the company's settings. Settings left out of the body
+// go back to their defaults.
+func (h *CompanySettingsHandler) UpdateCompanySettings(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "CompanySettingsHandler.UpdateCompanySettings")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	settings := models.DefaultCompanySettings()
+	if bindErr := utils.BindJSON(c, &settings); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	var updatedBy *uuid.UUID
+	if userID, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		updatedBy = &userID
+	}
+
+	record, err := h.settingsRepo.Upsert(ctx, *companyID, settings, updatedBy)
+	if err != nil {
+		span.RecordError(err)
+		logger.Error("Failed to update company settings", zap.Error(err), zap.String("company_id", companyID.String()))
+		utils.InternalServerErrorResponse(c, "Failed to update company settings")
+		return
+	}
+
+	middleware.SetAuditResourceID(c, companyID.String())
+	middleware.SetAuditMetadata(c, "settings", settings)
+	span.SetAttributes(attribute.String("company.id", companyID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Company settings updated successfully", record)
+}
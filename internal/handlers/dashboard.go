@@ -11,6 +11,7 @@ import (
 	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // DashboardHandler handles dashboard-related requests
@@ -96,7 +97,7 @@ func (h *DashboardHandler) GetDashboard(c *gin.Context) {
 	// Get user context
 	userContext, exists := c.Get("userContext")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
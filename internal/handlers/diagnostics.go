@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// TestEmailSender sends the sample email used to check the SMTP configuration
+type TestEmailSender interface {
+	SendTestEmail(email, requestedBy string) error
+}
+
+// DiagnosticsHandler exposes runtime details for operators
+type DiagnosticsHandler struct {
+	db        *sql.DB
+	testEmail TestEmailSender
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler
+func NewDiagnosticsHandler(db *sql.DB) *DiagnosticsHandler {
+	return &DiagnosticsHandler{db: db}
+}
+
+// SetTestEmailSender sets the sender used by SendTestEmail
+func (h *DiagnosticsHandler) SetTestEmailSender(sender TestEmailSender) {
+	h.testEmail = sender
+}
+
+// DatabasePoolStats handles GET /master/diagnostics/database - current connection pool usage.
+// A steadily growing wait_count means the pool is too small for the load.
+func (h *DiagnosticsHandler) DatabasePoolStats(c *gin.Context) {
+	stats := h.db.Stats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	})
+}
+
+// SendTestEmail handles POST /master/test-email - sends a sample email to the given address
+// through the real SMTP pipeline and reports whether the server accepted it
+func (h *DiagnosticsHandler) SendTestEmail(c *gin.Context) {
+	if h.testEmail == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email sending is not configured"})
+		return
+	}
+
+	var req models.TestEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid \"to\" email address is required"})
+		return
+	}
+
+	requestedBy := c.GetString("email")
+	middleware.SetAuditMetadata(c, "to", req.To)
+
+	start := time.Now()
+	err := h.testEmail.SendTestEmail(req.To, requestedBy)
+	result := models.TestEmailResult{
+		Sent:       err == nil,
+		To:         req.To,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+
+	if errors.Is(err, services.ErrEmailDisabled) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email sending is not configured"})
+		return
+	}
+	if err != nil {
+		logger.Warn("Test email failed", zap.Error(err), zap.String("to", req.To))
+		result.Error = err.Error()
+		// The SMTP server, not this API, failed
+		c.JSON(http.StatusBadGateway, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
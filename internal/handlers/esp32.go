@@ -41,7 +41,7 @@ func (h *ESP32DeviceHandler) CreateDevice(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -99,7 +99,7 @@ func (h *ESP32DeviceHandler) GetDevices(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -153,7 +153,7 @@ func (h *ESP32DeviceHandler) GetDevice(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -193,7 +193,7 @@ func (h *ESP32DeviceHandler) UpdateDevice(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -267,7 +267,7 @@ func (h *ESP32DeviceHandler) DeleteDevice(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -298,7 +298,7 @@ func (h *ESP32DeviceHandler) GetDeviceStats(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -322,7 +322,7 @@ func (h *ESP32DeviceHandler) UpdateDeviceStatus(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -517,7 +517,7 @@ func (h *ESP32DeviceHandler) AssignDeviceToVehicle(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
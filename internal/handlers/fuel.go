@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// FuelHandler handles fuel log and odometer HTTP requests
+type FuelHandler struct {
+	fuelRepo    repository.FuelRepositoryInterface
+	vehicleRepo *repository.VehicleRepository
+	tracer      trace.Tracer
+}
+
+// NewFuelHandler creates a new fuel handler
+func NewFuelHandler(fuelRepo repository.FuelRepositoryInterface, vehicleRepo *repository.VehicleRepository) *FuelHandler {
+	return &FuelHandler{
+		fuelRepo:    fuelRepo,
+		vehicleRepo: vehicleRepo,
+		tracer:      otel.Tracer("fuel-handler"),
+	}
+}
+
+// CreateFuelLog logs a fuel purchase; its odometer must not be below the vehicle's current one
+func (h *FuelHandler) CreateFuelLog(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "FuelHandler.CreateFuelLog")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	var req models.CreateFuelLogRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	createdBy, _ := middleware.GetUserIDFromContext(c)
+
+	log := &models.FuelLog{
+		VehicleID:  vehicle.ID,
+		Liters:     req.Liters,
+		Cost:       req.Cost,
+		OdometerKm: *req.OdometerKm,
+		FilledAt:   req.FilledAt,
+		Station:    req.Station,
+		Notes:      req.Notes,
+		CreatedBy:  createdBy,
+	}
+
+	if err := h.fuelRepo.CreateFuelLog(ctx, log, companyID); err != nil {
+		if errors.Is(err, repository.ErrOdometerDecreased) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to create fuel log")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Fuel log created successfully", log)
+}
+
+// GetFuelLogs lists the fuel logs of a vehicle in a period (from/to, default last 30 days)
+func (h *FuelHandler) GetFuelLogs(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "FuelHandler.GetFuelLogs")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	from, to, ok := fuelPeriod(c)
+	if !ok {
+		return
+	}
+
+	logs, err := h.fuelRepo.ListFuelLogs(ctx, vehicle.ID, companyID, from, to)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve fuel logs")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fuel logs retrieved successfully", gin.H{
+		"vehicle_id": vehicle.ID,
+		"from":       from,
+		"to":         to,
+		"fuel_logs":  logs,
+	})
+}
+
+// GetFuelEfficiency returns the km/L of a vehicle in a period (from/to, default last 30 days)
+func (h *FuelHandler) GetFuelEfficiency(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "FuelHandler.GetFuelEfficiency")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	from, to, ok := fuelPeriod(c)
+	if !ok {
+		return
+	}
+
+	efficiency, err := h.fuelRepo.GetFuelEfficiency(ctx, vehicle.ID, companyID, from, to)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to compute fuel efficiency")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fuel efficiency retrieved successfully", efficiency)
+}
+
+// CreateOdometerReading records an odometer reading, rejecting values below the previous one
+func (h *FuelHandler) CreateOdometerReading(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "FuelHandler.CreateOdometerReading")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOdometerReadingRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	createdBy, _ := middleware.GetUserIDFromContext(c)
+
+	reading := &models.OdometerReading{
+		VehicleID:  vehicle.ID,
+		OdometerKm: *req.OdometerKm,
+		RecordedAt: time.Now(),
+		Source:     "manual",
+		CreatedBy:  createdBy,
+	}
+	if req.RecordedAt != nil {
+		reading.RecordedAt = *req.RecordedAt
+	}
+
+	if err := h.fuelRepo.CreateOdometerReading(ctx, reading, companyID); err != nil {
+		if errors.Is(err, repository.ErrOdometerDecreased) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to record odometer reading")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Odometer reading recorded successfully", reading)
+}
+
+// GetOdometerReadings lists the odometer history of a vehicle
+func (h *FuelHandler) GetOdometerReadings(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "FuelHandler.GetOdometerReadings")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	readings, err := h.fuelRepo.ListOdometerReadings(ctx, vehicle.ID, companyID, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve odometer readings")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Odometer readings retrieved successfully", gin.H{
+		"vehicle_id": vehicle.ID,
+		"readings":   readings,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// fuelPeriod parses the from/to query parameters, defaulting to the last 30 days,
+// writing the error response and returning false when they are invalid
+func fuelPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	var err error
+
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		to, err = parseGrowthTime(toStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid 'to' parameter, expected RFC3339 or YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err = parseGrowthTime(fromStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid 'from' parameter, expected RFC3339 or YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if !from.Before(to) {
+		utils.BadRequestResponse(c, "'from' must be before 'to'")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// IPAllowlistHandler handles company IP allowlist management HTTP requests
+type IPAllowlistHandler struct {
+	allowlistRepo repository.IPAllowlistRepositoryInterface
+	tracer        trace.Tracer
+}
+
+// NewIPAllowlistHandler creates a new IP allowlist handler
+func NewIPAllowlistHandler(allowlistRepo repository.IPAllowlistRepositoryInterface) *IPAllowlistHandler {
+	return &IPAllowlistHandler{
+		allowlistRepo: allowlistRepo,
+		tracer:        otel.Tracer("ip-allowlist-handler"),
+	}
+}
+
+// GetIPAllowlist lists the address ranges the company's users may log in from
+func (h *IPAllowlistHandler) GetIPAllowlist(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "IPAllowlistHandler.GetIPAllowlist")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	entries, err := h.allowlistRepo.GetByCompany(ctx, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve IP allowlist")
+		return
+	}
+
+	span.SetAttributes(attribute.Int("ip_allowlist.count", len(entries)))
+
+	utils.SuccessResponse(c, http.StatusOK, "IP allowlist retrieved successfully", gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// AddIPAllowlistEntry allows logins from an address range. The first entry turns the
+// restriction on for every non-master user of the company.
+func (h *IPAllowlistHandler) AddIPAllowlistEntry(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "IPAllowlistHandler.AddIPAllowlistEntry")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	var req models.CreateIPAllowlistEntryRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	cidr, err := services.ParseAllowlistCIDR(req.CIDR)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	entry := &models.IPAllowlistEntry{
+		CompanyID:   *companyID,
+		CIDR:        cidr,
+		Description: req.Description,
+	}
+	if userID, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		entry.CreatedBy = &userID
+	}
+
+	if err := h.allowlistRepo.Create(ctx, entry); err != nil {
+		if errors.Is(err, repository.ErrIPAllowlistEntryExists) {
+			utils.ConflictResponse(c, "IP range is already in the allowlist")
+			return
+		}
+		span.RecordError(err)
+		logger.Error("Failed to create IP allowlist entry", zap.Error(err), zap.String("company_id", companyID.String()))
+		utils.InternalServerErrorResponse(c, "Failed to add IP allowlist entry")
+		return
+	}
+
+	middleware.SetAuditResourceID(c, entry.ID.String())
+	middleware.SetAuditMetadata(c, "cidr", cidr)
+	span.SetAttributes(
+		attribute.String("ip_allowlist.id", entry.ID.String()),
+		attribute.String("company.id", companyID.String()),
+	)
+
+	utils.SuccessResponse(c, http.StatusCreated, "IP allowlist entry added successfully", gin.H{
+		"entry": entry,
+	})
+}
+
+// DeleteIPAllowlistEntry removes an address range. Removing the last one lifts the restriction.
+func (h *IPAllowlistHandler) DeleteIPAllowlistEntry(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "IPAllowlistHandler.DeleteIPAllowlistEntry")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid IP allowlist entry ID")
+		return
+	}
+
+	if err := h.allowlistRepo.Delete(ctx, entryID, *companyID); err != nil {
+		if errors.Is(err, repository.ErrIPAllowlistEntryNotFound) {
+			utils.NotFoundResponse(c, "IP allowlist entry not found")
+			return
+		}
+		span.RecordError(err)
+		logger.Error("Failed to delete IP allowlist entry", zap.Error(err), zap.String("entry_id", entryID.String()))
+		utils.InternalServerErrorResponse(c, "Failed to delete IP allowlist entry")
+		return
+	}
+
+	span.SetAttributes(attribute.String("ip_allowlist.id", entryID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "IP allowlist entry deleted successfully", nil)
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+const (
+	defaultMaintenanceDueDays = 30
+	defaultMaintenanceDueKm   = 1000
+)
+
+// MaintenanceHandler handles vehicle maintenance HTTP requests
+type MaintenanceHandler struct {
+	maintenanceRepo repository.MaintenanceRepositoryInterface
+	vehicleRepo     *repository.VehicleRepository
+	tracer          trace.Tracer
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceRepo repository.MaintenanceRepositoryInterface, vehicleRepo *repository.VehicleRepository) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceRepo: maintenanceRepo,
+		vehicleRepo:     vehicleRepo,
+		tracer:          otel.Tracer("maintenance-handler"),
+	}
+}
+
+// companyVehicle resolves the company and the :id vehicle, writing the error response
+// and returning false when either is missing
+func companyVehicle(c *gin.Context, span trace.Span, vehicleRepo *repository.VehicleRepository) (uuid.UUID, *models.Vehicle, bool) {
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return uuid.Nil, nil, false
+	}
+
+	vehicleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid vehicle ID")
+		return uuid.Nil, nil, false
+	}
+
+	vehicle, err := vehicleRepo.GetByID(c.Request.Context(), vehicleID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicle")
+		return uuid.Nil, nil, false
+	}
+	if vehicle == nil {
+		utils.NotFoundResponse(c, "Vehicle not found")
+		return uuid.Nil, nil, false
+	}
+
+	span.SetAttributes(attribute.String("vehicle.id", vehicleID.String()))
+	return *companyID, vehicle, true
+}
+
+// CreateMaintenance records a maintenance service for a vehicle
+func (h *MaintenanceHandler) CreateMaintenance(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "MaintenanceHandler.CreateMaintenance")
+	defer span.End()
+
+	_, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	var req models.CreateMaintenanceRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	createdBy, _ := middleware.GetUserIDFromContext(c)
+
+	record := &models.VehicleMaintenance{
+		VehicleID:             vehicle.ID,
+		Type:                  req.Type,
+		PerformedAt:           req.PerformedAt,
+		OdometerKm:            req.OdometerKm,
+		Cost:                  req.Cost,
+		Notes:                 req.Notes,
+		NextServiceDate:       req.NextServiceDate,
+		NextServiceOdometerKm: req.NextServiceOdometerKm,
+		CreatedBy:             createdBy,
+	}
+
+	if err := h.maintenanceRepo.Create(ctx, record); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to create maintenance record")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Maintenance record created successfully", record)
+}
+
+// GetMaintenance lists the maintenance records of a vehicle
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "MaintenanceHandler.GetMaintenance")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	records, err := h.maintenanceRepo.ListByVehicle(ctx, vehicle.ID, companyID, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve maintenance records")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Maintenance records retrieved successfully", gin.H{
+		"vehicle_id":  vehicle.ID,
+		"maintenance": records,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// UpdateMaintenance updates a maintenance record of a vehicle
+func (h *MaintenanceHandler) UpdateMaintenance(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "MaintenanceHandler.UpdateMaintenance")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	recordID, err := uuid.Parse(c.Param("recordId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid maintenance record ID")
+		return
+	}
+
+	var req models.UpdateMaintenanceRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	record, err := h.maintenanceRepo.GetByID(ctx, recordID, vehicle.ID, companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve maintenance record")
+		return
+	}
+	if record == nil {
+		utils.NotFoundResponse(c, "Maintenance record not found")
+		return
+	}
+
+	if req.Type != nil {
+		record.Type = *req.Type
+	}
+	if req.PerformedAt != nil {
+		record.PerformedAt = *req.PerformedAt
+	}
+	if req.OdometerKm != nil {
+		record.OdometerKm = req.OdometerKm
+	}
+	if req.Cost != nil {
+		record.Cost = req.Cost
+	}
+	if req.Notes != nil {
+		record.Notes = req.Notes
+	}
+	if req.NextServiceDate != nil {
+		record.NextServiceDate = req.NextServiceDate
+	}
+	if req.NextServiceOdometerKm != nil {
+		record.NextServiceOdometerKm = req.NextServiceOdometerKm
+	}
+
+	if err := h.maintenanceRepo.Update(ctx, record, companyID); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to update maintenance record")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Maintenance record updated successfully", record)
+}
+
+// GetMaintenanceDueSoon lists the company's vehicles whose next service is approaching.
+// Query parameters: days (default 30) and km (default 1000).
+func (h *MaintenanceHandler) GetMaintenanceDueSoon(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "MaintenanceHandler.GetMaintenanceDueSoon")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultMaintenanceDueDays)))
+	if err != nil || days < 0 || days > 365 {
+		utils.BadRequestResponse(c, "days must be between 0 and 365")
+		return
+	}
+
+	km, err := strconv.Atoi(c.DefaultQuery("km", strconv.Itoa(defaultMaintenanceDueKm)))
+	if err != nil || km < 0 {
+		utils.BadRequestResponse(c, "km must be a non-negative integer")
+		return
+	}
+
+	due, err := h.maintenanceRepo.GetDueSoon(ctx, *companyID, days, km)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve maintenance due soon")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Maintenance due soon retrieved successfully", gin.H{
+		"within_days": days,
+		"within_km":   km,
+		"due":         due,
+	})
+}
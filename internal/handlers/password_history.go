@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// isPasswordReused reports whether newPassword matches the current hash or any
+// of the user's last limit stored hashes
+func isPasswordReused(ctx context.Context, repo repository.PasswordHistoryRepositoryInterface, userID uuid.UUID, currentHash, newPassword string, limit int) (bool, error) {
+	if currentHash != "" && bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(newPassword)) == nil {
+		return true, nil
+	}
+
+	if repo == nil || limit <= 0 {
+		return false, nil
+	}
+
+	hashes, err := repo.GetRecentHashes(ctx, userID, limit)
+	if err != nil {
+		return false, err
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
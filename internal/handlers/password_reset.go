@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -13,23 +14,33 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/services"
 )
 
 // PasswordResetHandler gerencia operações de recuperação de senha
 type PasswordResetHandler struct {
 	db           *sql.DB
-	emailService *services.EmailService
+	emailService services.EmailSender
+
+	passwordHistory     repository.PasswordHistoryRepositoryInterface
+	passwordHistorySize int
 }
 
 // NewPasswordResetHandler cria uma nova instância do handler
-func NewPasswordResetHandler(db *sql.DB, emailService *services.EmailService) *PasswordResetHandler {
+func NewPasswordResetHandler(db *sql.DB, emailService services.EmailSender) *PasswordResetHandler {
 	return &PasswordResetHandler{
 		db:           db,
 		emailService: emailService,
 	}
 }
 
+// SetPasswordHistory define o repositório usado para impedir a reutilização das últimas size senhas
+func (h *PasswordResetHandler) SetPasswordHistory(repo repository.PasswordHistoryRepositoryInterface, size int) {
+	h.passwordHistory = repo
+	h.passwordHistorySize = size
+}
+
 // PasswordResetCodeRequest representa a requisição de esqueci minha senha com código
 type PasswordResetCodeRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -282,11 +293,12 @@ func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 	// Buscar usuário
 	var userID uuid.UUID
 	var userName string
+	var currentHash string
 	err := h.db.QueryRow(`
-		SELECT id, name 
+		SELECT id, name, password
 		FROM users 
 		WHERE email = $1 AND deleted_at IS NULL
-	`, req.Email).Scan(&userID, &userName)
+	`, req.Email).Scan(&userID, &userName, &currentHash)
 
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Email não encontrado"})
@@ -338,6 +350,18 @@ func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	// Impedir a reutilização da senha atual ou de senhas recentes
+	reused, err := isPasswordReused(c.Request.Context(), h.passwordHistory, userID, currentHash, req.NewPassword, h.passwordHistorySize)
+	if err != nil {
+		logger.Error("Erro ao verificar histórico de senhas", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar senha"})
+		return
+	}
+	if reused {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A nova senha não pode ser igual à senha atual nem às últimas %d senhas", h.passwordHistorySize)})
+		return
+	}
+
 	// Hash da nova senha
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -401,11 +425,11 @@ func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 
 	_, err = tx.Exec(`
 		INSERT INTO audit_logs (
-			id, user_id, action, resource, resource_id,
-			ip_address, user_agent, metadata, success, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-	`, uuid.New(), userID, "password_change", "user", resourceIDStr,
-		clientIP, userAgent, metadataJSON, true, time.Now())
+			id, user_id, action, resource, resource_id, method, path,
+			ip_address, user_agent, metadata, success, status_code, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, uuid.New(), userID, "password_change", "user", resourceIDStr, c.Request.Method, c.Request.URL.Path,
+		clientIP, userAgent, metadataJSON, true, http.StatusOK, time.Now())
 
 	if err != nil {
 		logger.Error("Failed to create audit log for password reset",
@@ -421,6 +445,15 @@ func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	// Guardar a senha anterior no histórico
+	if h.passwordHistory != nil && h.passwordHistorySize > 0 {
+		if err := h.passwordHistory.Add(c.Request.Context(), userID, currentHash, h.passwordHistorySize); err != nil {
+			logger.Error("Erro ao registrar histórico de senhas",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+		}
+	}
+
 	// Enviar email de confirmação (async)
 	go func() {
 		err := h.emailService.SendPasswordResetConfirmation(req.Email, userName)
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// avatarFormField is the multipart field carrying the uploaded image
+const avatarFormField = "avatar"
+
+// avatarMultipartOverhead leaves room for the multipart boundaries and headers around the file
+const avatarMultipartOverhead = 64 << 10
+
+// UploadAvatarGin stores a multipart image as the current user's avatar and returns its URL
+func (h *AuthHandler) UploadAvatarGin(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if h.avatars == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Avatar uploads are not enabled"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.avatars.MaxBytes()+avatarMultipartOverhead)
+	fileHeader, err := c.FormFile(avatarFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrAvatarTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A multipart file field named \"avatar\" is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	url, err := h.avatars.Upload(c.Request.Context(), userID, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAvatarTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAvatarUnsupportedType):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAvatarInvalidImage):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			logger.Error("Failed to upload avatar", zap.Error(err), zap.String("user_id", userID.String()))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload avatar"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Avatar updated successfully",
+		"avatar":  url,
+	})
+}
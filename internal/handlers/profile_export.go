@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// profileExportSection is a list section of the export bundle: the repository cursor
+// producing its rows and the model each row is scanned into
+type profileExportSection struct {
+	name string
+	open func(ctx context.Context, userID uuid.UUID) (*repository.ExportRows, error)
+	row  func() interface{}
+}
+
+// profileExportSections lists the sections in the order they are written
+func (h *AuthHandler) profileExportSections() []profileExportSection {
+	return []profileExportSection{
+		{
+			name: "team_memberships",
+			open: h.profileExports.TeamMemberships,
+			row:  func() interface{} { return &models.ProfileExportTeamMembership{} },
+		},
+		{
+			name: "assigned_vehicles",
+			open: h.profileExports.AssignedVehicles,
+			row:  func() interface{} { return &models.ProfileExportVehicle{} },
+		},
+		{
+			name: "auth_history",
+			open: h.profileExports.AuthHistory,
+			row:  func() interface{} { return &models.ProfileExportAuthEntry{} },
+		},
+		{
+			name: "audit_entries",
+			open: h.profileExports.AuditEntries,
+			row:  func() interface{} { return &models.ProfileExportAuditEntry{} },
+		},
+	}
+}
+
+// ExportProfileGin returns a JSON bundle of everything stored about the current user
+// (profile, team memberships, assigned vehicles, auth history and audit entries) for data
+// portability requests. Rows are streamed as they are read so large histories are never
+// held in memory; an error once streaming has started truncates the response.
+func (h *AuthHandler) ExportProfileGin(c *gin.Context) {
+	if h.profileExports == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Profile exports are not enabled"})
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export profile"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	profile := UserResponse{
+		ID:        user.ID.String(),
+		Name:      user.Name,
+		Email:     user.Email,
+		Phone:     getStringValue(user.Phone),
+		Active:    user.Active,
+		Avatar:    getStringValue(user.Avatar),
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+	if user.Role != nil {
+		profile.Role = user.Role.Name
+	}
+
+	header, err := json.Marshal(gin.H{"exported_at": utils.Now(), "profile": profile})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export profile"})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=dashtrack-export-%s.json", userID))
+	c.Status(http.StatusOK)
+
+	// The header object is reopened so the list sections can be appended one by one
+	if _, err := c.Writer.Write(header[:len(header)-1]); err != nil {
+		return
+	}
+
+	for _, section := range h.profileExportSections() {
+		if err := writeProfileExportSection(c, section, userID); err != nil {
+			logger.Error("Failed to stream profile export",
+				zap.Error(err),
+				zap.String("user_id", userID.String()),
+				zap.String("section", section.name))
+			return
+		}
+		c.Writer.Flush()
+	}
+
+	io.WriteString(c.Writer, "}")
+}
+
+// writeProfileExportSection writes `,"<name>":[...]` with one element per row of the section cursor
+func writeProfileExportSection(c *gin.Context, section profileExportSection, userID uuid.UUID) error {
+	rows, err := section.open(c.Request.Context(), userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if _, err := fmt.Fprintf(c.Writer, `,%q:[`, section.name); err != nil {
+		return err
+	}
+
+	for first := true; rows.Next(); first = false {
+		row := section.row()
+		if err := rows.Scan(row); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if !first {
+			data = append([]byte{','}, data...)
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(c.Writer, "]")
+	return err
+}
@@ -10,8 +10,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // SecurityHandler handles security-related endpoints
@@ -80,7 +82,7 @@ func (sh *SecurityHandler) RefreshToken(c *gin.Context) {
 func (sh *SecurityHandler) Logout(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -91,7 +93,7 @@ func (sh *SecurityHandler) Logout(c *gin.Context) {
 	}
 
 	// Revoke all user sessions
-	err = sh.tokenService.RevokeAllUserSessions(c.Request.Context(), userID)
+	_, err = sh.tokenService.RevokeAllUserSessions(c.Request.Context(), userID)
 	if err != nil {
 		logger.Error("Failed to revoke user sessions", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
@@ -108,7 +110,7 @@ func (sh *SecurityHandler) Logout(c *gin.Context) {
 func (sh *SecurityHandler) Setup2FA(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -136,6 +138,7 @@ func (sh *SecurityHandler) Setup2FA(c *gin.Context) {
 		"step": "setup_initiated",
 	})
 
+	middleware.NoStoreIdempotentResponse(c)
 	c.JSON(http.StatusOK, setup)
 }
 
@@ -143,7 +146,7 @@ func (sh *SecurityHandler) Setup2FA(c *gin.Context) {
 func (sh *SecurityHandler) Enable2FA(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -178,7 +181,7 @@ func (sh *SecurityHandler) Enable2FA(c *gin.Context) {
 func (sh *SecurityHandler) Disable2FA(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -213,7 +216,7 @@ func (sh *SecurityHandler) Disable2FA(c *gin.Context) {
 func (sh *SecurityHandler) Verify2FA(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -256,7 +259,7 @@ func (sh *SecurityHandler) Verify2FA(c *gin.Context) {
 func (sh *SecurityHandler) GenerateBackupCodes(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -278,6 +281,7 @@ func (sh *SecurityHandler) GenerateBackupCodes(c *gin.Context) {
 		"action": "backup_codes_generated",
 	})
 
+	middleware.NoStoreIdempotentResponse(c)
 	c.JSON(http.StatusOK, gin.H{
 		"backup_codes": codes,
 		"message":      "New backup codes generated. Store them safely!",
@@ -364,7 +368,7 @@ func (sh *SecurityHandler) GetAuditLogs(c *gin.Context) {
 func (sh *SecurityHandler) Get2FAStatus(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
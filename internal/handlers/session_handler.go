@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 	"go.uber.org/zap"
 )
 
@@ -26,7 +27,7 @@ func NewSessionHandler(sessionManager *services.SessionManager) *SessionHandler
 func (sh *SessionHandler) GetSessionDashboard(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -50,7 +51,7 @@ func (sh *SessionHandler) GetSessionDashboard(c *gin.Context) {
 func (sh *SessionHandler) GetActiveSessions(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -77,7 +78,7 @@ func (sh *SessionHandler) GetActiveSessions(c *gin.Context) {
 func (sh *SessionHandler) RevokeSession(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -134,7 +135,7 @@ func (sh *SessionHandler) RevokeSession(c *gin.Context) {
 func (sh *SessionHandler) GetSessionMetrics(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -158,7 +159,7 @@ func (sh *SessionHandler) GetSessionMetrics(c *gin.Context) {
 func (sh *SessionHandler) GetSecurityAlerts(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -186,7 +187,7 @@ func (sh *SessionHandler) GetSecurityAlerts(c *gin.Context) {
 func (sh *SessionHandler) RevokeAllExceptCurrent(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -199,7 +200,7 @@ func (sh *SessionHandler) RevokeAllExceptCurrent(c *gin.Context) {
 	// Get current session ID from token
 	currentSessionIDStr, exists := c.Get("session_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session context not found"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
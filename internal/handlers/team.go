@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -15,6 +19,7 @@ import (
 	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
 	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
@@ -23,19 +28,48 @@ type TeamHandler struct {
 	teamRepo    repository.TeamRepositoryInterface
 	userRepo    repository.UserRepositoryInterface
 	vehicleRepo repository.VehicleRepositoryInterface
+	webhooks    *services.WebhookDispatcher
+	notifier    TeamManagerNotifier
+	maxBatch    int
 	tracer      trace.Tracer
 }
 
+// TeamManagerNotifier emails users when they become or stop being a team's manager
+type TeamManagerNotifier interface {
+	SendTeamManagerChanged(email, userName, teamName string, assigned bool) error
+}
+
+// teamManagerRoles are the roles allowed to own a team
+var teamManagerRoles = map[string]bool{"company_admin": true, "manager": true}
+
 // NewTeamHandler creates a new team handler
 func NewTeamHandler(teamRepo repository.TeamRepositoryInterface, userRepo repository.UserRepositoryInterface, vehicleRepo repository.VehicleRepositoryInterface) *TeamHandler {
 	return &TeamHandler{
 		teamRepo:    teamRepo,
 		userRepo:    userRepo,
 		vehicleRepo: vehicleRepo,
+		maxBatch:    repository.DefaultMaxBatchSize,
 		tracer:      otel.Tracer("team-handler"),
 	}
 }
 
+// SetWebhookDispatcher sets the dispatcher used to notify company webhooks
+func (h *TeamHandler) SetWebhookDispatcher(dispatcher *services.WebhookDispatcher) {
+	h.webhooks = dispatcher
+}
+
+// SetManagerNotifier sets the notifier used to email old and new team managers
+func (h *TeamHandler) SetManagerNotifier(notifier TeamManagerNotifier) {
+	h.notifier = notifier
+}
+
+// SetMaxBatchSize caps the number of members accepted by a single bulk add
+func (h *TeamHandler) SetMaxBatchSize(maxBatch int) {
+	if maxBatch > 0 {
+		h.maxBatch = maxBatch
+	}
+}
+
 // CreateTeam creates a new team
 func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.CreateTeam")
@@ -44,14 +78,14 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
 	var req models.CreateTeamRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		span.RecordError(err)
-		utils.ValidationErrorResponse(c, err)
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
 		return
 	}
 
@@ -91,7 +125,7 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 		attribute.String("company.id", companyID.String()),
 	)
 
-	utils.SuccessResponse(c, http.StatusCreated, "Team created successfully", team)
+	utils.CreatedResponse(c, team.ID.String(), "Team created successfully", team)
 }
 
 // GetTeams retrieves teams for a company
@@ -102,7 +136,7 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -120,24 +154,36 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 		offset = 0
 	}
 
-	teams, err := h.teamRepo.GetByCompany(ctx, *companyID, limit, offset)
+	includeArchived := false
+	if includeStr := c.Query("include_archived"); includeStr != "" {
+		includeArchived, err = strconv.ParseBool(includeStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "include_archived must be true or false")
+			return
+		}
+	}
+
+	teams, err := h.teamRepo.GetByCompany(ctx, *companyID, limit, offset, includeArchived)
 	if err != nil {
 		span.RecordError(err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve teams")
 		return
 	}
 
+	total, err := h.teamRepo.CountByCompany(ctx, *companyID, includeArchived)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to count teams")
+		return
+	}
+
 	span.SetAttributes(
 		attribute.String("company.id", companyID.String()),
 		attribute.Int("teams.count", len(teams)),
+		attribute.Int("teams.total", total),
 	)
 
-	utils.SuccessResponse(c, http.StatusOK, "Teams retrieved successfully", gin.H{
-		"teams":  teams,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(teams),
-	})
+	utils.PaginatedResponse(c, teams, total, limit, offset)
 }
 
 // GetTeam retrieves a specific team
@@ -148,7 +194,7 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -197,7 +243,7 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -209,9 +255,9 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	}
 
 	var req models.CreateTeamRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		span.RecordError(err)
-		utils.ValidationErrorResponse(c, err)
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
 		return
 	}
 
@@ -230,20 +276,12 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 
 	// Validate manager if provided
 	if req.ManagerID != nil {
-		manager, err := h.userRepo.GetByID(ctx, *req.ManagerID)
-		if err != nil || manager == nil {
-			utils.BadRequestResponse(c, "Invalid manager ID")
-			return
-		}
-
-		// Check if manager belongs to the same company
-		if manager.CompanyID == nil || *manager.CompanyID != *companyID {
-			utils.BadRequestResponse(c, "Manager must belong to the same company")
+		if _, ok := h.validateTeamManager(c, *req.ManagerID, *companyID); !ok {
 			return
 		}
 	}
 
-	// Update team fields
+	// Replace all team fields; omitted optional fields are cleared
 	team.Name = req.Name
 	team.Description = req.Description
 	team.ManagerID = req.ManagerID
@@ -260,6 +298,209 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Team updated successfully", team)
 }
 
+// PatchTeam partially updates a team; only the fields present in the body are changed
+func (h *TeamHandler) PatchTeam(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.PatchTeam")
+	defer span.End()
+
+	// Get company ID from context
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	teamIDStr := c.Param("id")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid team ID")
+		return
+	}
+
+	var req models.UpdateTeamRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	// Get existing team
+	team, err := h.teamRepo.GetByID(ctx, teamID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve team")
+		return
+	}
+
+	if team == nil {
+		utils.NotFoundResponse(c, "Team not found")
+		return
+	}
+
+	if req.ManagerID != nil {
+		if _, ok := h.validateTeamManager(c, *req.ManagerID, *companyID); !ok {
+			return
+		}
+	}
+
+	if req.Name != nil {
+		team.Name = *req.Name
+	}
+	if req.Description != nil {
+		team.Description = req.Description
+	}
+	if req.ManagerID != nil {
+		team.ManagerID = req.ManagerID
+	}
+
+	err = h.teamRepo.Update(ctx, team)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to update team")
+		return
+	}
+
+	span.SetAttributes(attribute.String("team.id", team.ID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Team updated successfully", team)
+}
+
+// ChangeTeamManager hands a team over to a new manager and notifies the previous and
+// new managers by email. The new manager must be an active company_admin or manager
+// of the same company.
+func (h *TeamHandler) ChangeTeamManager(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.ChangeTeamManager")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid team ID")
+		return
+	}
+
+	var req models.ChangeTeamManagerRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	team, err := h.teamRepo.GetByID(ctx, teamID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve team")
+		return
+	}
+	if team == nil {
+		utils.NotFoundResponse(c, "Team not found")
+		return
+	}
+
+	if team.ManagerID != nil && *team.ManagerID == req.ManagerID {
+		utils.BadRequestResponse(c, "User is already the team manager")
+		return
+	}
+
+	newManager, ok := h.validateTeamManager(c, req.ManagerID, *companyID)
+	if !ok {
+		return
+	}
+	if !newManager.Active {
+		utils.BadRequestResponse(c, "Manager must be an active user")
+		return
+	}
+	if newManager.Role == nil || !teamManagerRoles[newManager.Role.Name] {
+		utils.BadRequestResponse(c, "Manager must have the company_admin or manager role")
+		return
+	}
+
+	var previousManager *models.User
+	if team.ManagerID != nil {
+		previousManager, err = h.userRepo.GetByID(ctx, *team.ManagerID)
+		if err != nil {
+			// The handover doesn't depend on the previous manager; only their notification is lost
+			logger.Warn("Failed to load previous team manager",
+				zap.Error(err),
+				zap.String("team_id", team.ID.String()),
+			)
+		}
+	}
+
+	previousManagerID := team.ManagerID
+	team.ManagerID = &newManager.ID
+
+	if err := h.teamRepo.Update(ctx, team); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to update team manager")
+		return
+	}
+
+	changedBy, _ := middleware.GetUserIDFromContext(c)
+	fields := []zap.Field{
+		zap.String("team_id", team.ID.String()),
+		zap.String("company_id", companyID.String()),
+		zap.String("new_manager_id", newManager.ID.String()),
+	}
+	if previousManagerID != nil {
+		fields = append(fields, zap.String("previous_manager_id", previousManagerID.String()))
+	}
+	if changedBy != nil {
+		fields = append(fields, zap.String("changed_by", changedBy.String()))
+	}
+	logger.Info("Team manager changed", fields...)
+
+	span.SetAttributes(
+		attribute.String("team.id", team.ID.String()),
+		attribute.String("team.manager_id", newManager.ID.String()),
+	)
+
+	h.notifyManagerChange(team.Name, previousManager, newManager)
+
+	utils.SuccessResponse(c, http.StatusOK, "Team manager updated successfully", team)
+}
+
+// notifyManagerChange emails the previous and new managers in the background
+func (h *TeamHandler) notifyManagerChange(teamName string, previous, current *models.User) {
+	if h.notifier == nil {
+		return
+	}
+
+	go func() {
+		if previous != nil {
+			if err := h.notifier.SendTeamManagerChanged(previous.Email, previous.Name, teamName, false); err != nil {
+				logger.Error("Failed to notify previous team manager", zap.Error(err), zap.String("user_id", previous.ID.String()))
+			}
+		}
+		if err := h.notifier.SendTeamManagerChanged(current.Email, current.Name, teamName, true); err != nil {
+			logger.Error("Failed to notify new team manager", zap.Error(err), zap.String("user_id", current.ID.String()))
+		}
+	}()
+}
+
+// validateTeamManager checks that the manager exists and belongs to the company,
+// writing the error response and returning false otherwise
+func (h *TeamHandler) validateTeamManager(c *gin.Context, managerID, companyID uuid.UUID) (*models.User, bool) {
+	manager, err := h.userRepo.GetByID(c.Request.Context(), managerID)
+	if err != nil || manager == nil {
+		utils.BadRequestResponse(c, "Invalid manager ID")
+		return nil, false
+	}
+
+	// Check if manager belongs to the same company
+	if manager.CompanyID == nil || *manager.CompanyID != companyID {
+		utils.BadRequestResponse(c, "Manager must belong to the same company")
+		return nil, false
+	}
+
+	return manager, true
+}
+
 // DeleteTeam deletes a team
 func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.DeleteTeam")
@@ -268,7 +509,7 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -292,6 +533,77 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Team deleted successfully", nil)
 }
 
+// ArchiveTeam hides a team from default lists while keeping it readable and restorable
+func (h *TeamHandler) ArchiveTeam(c *gin.Context) {
+	h.setTeamArchived(c, true)
+}
+
+// UnarchiveTeam restores an archived team to active
+func (h *TeamHandler) UnarchiveTeam(c *gin.Context) {
+	h.setTeamArchived(c, false)
+}
+
+// setTeamArchived moves a team between active and archived. Deleted teams can't be
+// archived or restored.
+func (h *TeamHandler) setTeamArchived(c *gin.Context, archive bool) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.setTeamArchived")
+	defer span.End()
+
+	// Get company ID from context
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid team ID")
+		return
+	}
+
+	team, err := h.teamRepo.GetByID(ctx, teamID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve team")
+		return
+	}
+	if team == nil || team.DeletedAt != nil {
+		utils.NotFoundResponse(c, "Team not found")
+		return
+	}
+
+	archived := team.Status == models.TeamStatusArchived
+	if archive && archived {
+		utils.ConflictResponse(c, "Team is already archived")
+		return
+	}
+	if !archive && !archived {
+		utils.ConflictResponse(c, "Team is not archived")
+		return
+	}
+
+	status, message := models.TeamStatusArchived, "Team archived successfully"
+	if !archive {
+		status, message = models.TeamStatusActive, "Team unarchived successfully"
+	}
+
+	if err := h.teamRepo.SetStatus(ctx, teamID, *companyID, status); err != nil {
+		span.RecordError(err)
+		logger.Error("Failed to update team status", zap.Error(err), zap.String("team_id", teamID.String()), zap.String("status", status))
+		utils.InternalServerErrorResponse(c, "Failed to update team status")
+		return
+	}
+	team.Status = status
+
+	span.SetAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.String("team.status", status),
+	)
+
+	utils.SuccessResponse(c, http.StatusOK, message, team)
+}
+
 // AddMember adds a user to a team
 func (h *TeamHandler) AddMember(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.AddMember")
@@ -300,7 +612,7 @@ func (h *TeamHandler) AddMember(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -312,9 +624,9 @@ func (h *TeamHandler) AddMember(c *gin.Context) {
 	}
 
 	var req models.AssignTeamMemberRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		span.RecordError(err)
-		utils.ValidationErrorResponse(c, err)
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
 		return
 	}
 
@@ -375,7 +687,132 @@ func (h *TeamHandler) AddMember(c *gin.Context) {
 		attribute.String("role", req.RoleInTeam),
 	)
 
-	utils.SuccessResponse(c, http.StatusCreated, "Team member added successfully", teamMember)
+	utils.CreatedResponse(c, req.UserID.String(), "Team member added successfully", teamMember)
+}
+
+// AddMembersBulk adds several users to a team. Entries are validated one by one: invalid
+// entries and users outside the company fail, repeated users and existing members are
+// skipped, without stopping the others. The remaining users are then added in a single
+// transaction with one history row each, so either all of them are added or none is.
+func (h *TeamHandler) AddMembersBulk(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.AddMembersBulk")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid team ID")
+		return
+	}
+
+	// Entries are decoded one by one so a bad entry fails alone
+	var entries []json.RawMessage
+	if bindErr := utils.BindJSON(c, &entries); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+	if len(entries) == 0 {
+		utils.BadRequestResponse(c, "At least one member is required")
+		return
+	}
+	if err := repository.CheckBatchSize(len(entries), h.maxBatch); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	team, err := h.teamRepo.GetByID(ctx, teamID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve team")
+		return
+	}
+	if team == nil {
+		utils.NotFoundResponse(c, "Team not found")
+		return
+	}
+
+	result := models.NewBatchResult(len(entries))
+	seen := make(map[uuid.UUID]bool, len(entries))
+	var members []*models.TeamMember
+	var indexes []int // entry index of each member
+	for i, raw := range entries {
+		var req models.AssignTeamMemberRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			result.Fail(i, "", "Invalid member entry")
+			continue
+		}
+		if err := binding.Validator.ValidateStruct(&req); err != nil {
+			result.Fail(i, "", "user_id is required and role_in_team must be one of manager, driver, assistant, supervisor, helper, team_lead")
+			continue
+		}
+		userID := req.UserID.String()
+
+		if seen[req.UserID] {
+			result.Skip(i, userID, "User appears more than once in the request")
+			continue
+		}
+		seen[req.UserID] = true
+
+		user, err := h.userRepo.GetByID(ctx, req.UserID)
+		if err != nil || user == nil || user.CompanyID == nil || *user.CompanyID != *companyID {
+			result.Fail(i, userID, "User not found in this company")
+			continue
+		}
+
+		exists, err := h.teamRepo.CheckMemberExists(ctx, teamID, req.UserID)
+		if err != nil {
+			span.RecordError(err)
+			result.Fail(i, userID, "Failed to check member existence")
+			continue
+		}
+		if exists {
+			result.Skip(i, userID, "User is already a member of this team")
+			continue
+		}
+
+		members = append(members, &models.TeamMember{
+			TeamID:     teamID,
+			UserID:     req.UserID,
+			RoleInTeam: req.RoleInTeam,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if len(members) > 0 {
+		added, err := h.teamRepo.AddMembers(ctx, *companyID, members)
+		if err != nil {
+			span.RecordError(err)
+			logger.Error("Failed to add team members", zap.Error(err), zap.String("team_id", teamID.String()))
+			added = nil
+		}
+		for n, member := range members {
+			userID := member.UserID.String()
+			switch {
+			case added == nil:
+				result.Fail(indexes[n], userID, "Failed to add team member")
+			case !added[n]:
+				result.Skip(indexes[n], userID, "User is already a member of this team")
+			default:
+				result.Succeed(indexes[n], userID, member)
+			}
+		}
+	}
+	result.SortByIndex()
+
+	span.SetAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.Int("members.added", result.Summary.Succeeded),
+		attribute.Int("members.failed", result.Summary.Failed),
+		attribute.Int("members.skipped", result.Summary.Skipped),
+	)
+
+	utils.BatchResponse(c, "Team members processed", result)
 }
 
 // RemoveMember removes a user from a team
@@ -386,7 +823,7 @@ func (h *TeamHandler) RemoveMember(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -441,7 +878,7 @@ func (h *TeamHandler) GetMembers(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -492,7 +929,7 @@ func (h *TeamHandler) UpdateMemberRole(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -513,9 +950,9 @@ func (h *TeamHandler) UpdateMemberRole(c *gin.Context) {
 	var req struct {
 		RoleInTeam string `json:"role_in_team" binding:"required,oneof=manager driver assistant supervisor helper team_lead"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		span.RecordError(err)
-		utils.ValidationErrorResponse(c, err)
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
 		return
 	}
 
@@ -562,7 +999,7 @@ func (h *TeamHandler) GetTeamStats(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -610,15 +1047,23 @@ func (h *TeamHandler) GetTeamStats(c *gin.Context) {
 		}
 	}
 
-	stats := gin.H{
-		"team_id":         teamID,
-		"team_name":       team.Name,
-		"member_count":    len(members),
-		"vehicle_count":   len(vehicles),
-		"active_vehicles": activeVehicles,
-		"status":          team.Status,
-		"created_at":      team.CreatedAt,
-		"manager_id":      team.ManagerID,
+	// Soft-deleted teams keep their last status in the column; report them as deleted
+	// so they can't be mistaken for archived ones
+	status := team.Status
+	if team.DeletedAt != nil {
+		status = models.TeamStatusDeleted
+	}
+
+	stats := models.TeamStats{
+		TeamID:         teamID,
+		TeamName:       team.Name,
+		MemberCount:    len(members),
+		VehicleCount:   len(vehicles),
+		ActiveVehicles: activeVehicles,
+		Status:         status,
+		CreatedAt:      team.CreatedAt,
+		DeletedAt:      team.DeletedAt,
+		ManagerID:      team.ManagerID,
 	}
 
 	span.SetAttributes(
@@ -639,7 +1084,7 @@ func (h *TeamHandler) GetTeamVehicles(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -692,7 +1137,7 @@ func (h *TeamHandler) GetMyTeams(c *gin.Context) {
 	// Get user ID from context
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil || userID == nil {
-		utils.BadRequestResponse(c, "User context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -714,7 +1159,79 @@ func (h *TeamHandler) GetMyTeams(c *gin.Context) {
 	})
 }
 
-// AssignVehicleToTeam assigns a vehicle to a team
+// GetManagerDashboard aggregates the teams the current user manages, with their members
+// and vehicles, so team leads see their teams without company-wide data
+func (h *TeamHandler) GetManagerDashboard(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.GetManagerDashboard")
+	defer span.End()
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil || userID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	teams, err := h.teamRepo.GetTeamsManagedBy(ctx, *userID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve managed teams")
+		return
+	}
+
+	dashboard := models.ManagerDashboard{
+		Teams:     make([]models.ManagedTeam, 0, len(teams)),
+		TeamCount: len(teams),
+	}
+	memberIDs := make(map[uuid.UUID]struct{})
+	for _, team := range teams {
+		members, err := h.teamRepo.GetMembers(ctx, team.ID)
+		if err != nil {
+			span.RecordError(err)
+			utils.InternalServerErrorResponse(c, "Failed to retrieve team members")
+			return
+		}
+		vehicles, err := h.vehicleRepo.GetByTeam(ctx, team.ID, team.CompanyID)
+		if err != nil {
+			span.RecordError(err)
+			utils.InternalServerErrorResponse(c, "Failed to retrieve team vehicles")
+			return
+		}
+		if members == nil {
+			members = []models.TeamMember{}
+		}
+		if vehicles == nil {
+			vehicles = []models.Vehicle{}
+		}
+
+		managed := models.ManagedTeam{Team: team, Members: members, Vehicles: vehicles}
+		for _, v := range vehicles {
+			if v.Status == "active" {
+				managed.ActiveVehicles++
+			}
+		}
+		for _, m := range members {
+			memberIDs[m.UserID] = struct{}{}
+		}
+
+		dashboard.Teams = append(dashboard.Teams, managed)
+		dashboard.VehicleCount += len(vehicles)
+		dashboard.ActiveVehicles += managed.ActiveVehicles
+	}
+	dashboard.MemberCount = len(memberIDs)
+
+	span.SetAttributes(
+		attribute.String("user.id", userID.String()),
+		attribute.Int("teams.count", dashboard.TeamCount),
+		attribute.Int("members.count", dashboard.MemberCount),
+		attribute.Int("vehicles.count", dashboard.VehicleCount),
+	)
+
+	utils.SuccessResponse(c, http.StatusOK, "Manager dashboard retrieved successfully", dashboard)
+}
+
+// AssignVehicleToTeam assigns a vehicle to a team. With ?strict=true the vehicle's current
+// driver and helper must be members of the team, otherwise it responds 409 naming the first
+// one who isn't; by default the crew is not checked.
 func (h *TeamHandler) AssignVehicleToTeam(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.AssignVehicleToTeam")
 	defer span.End()
@@ -722,7 +1239,7 @@ func (h *TeamHandler) AssignVehicleToTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -740,6 +1257,15 @@ func (h *TeamHandler) AssignVehicleToTeam(c *gin.Context) {
 		return
 	}
 
+	strict := false
+	if strictStr := c.Query("strict"); strictStr != "" {
+		strict, err = strconv.ParseBool(strictStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "strict must be true or false")
+			return
+		}
+	}
+
 	// Verify team exists and belongs to company
 	team, err := h.teamRepo.GetByID(ctx, teamID, *companyID)
 	if err != nil {
@@ -767,15 +1293,28 @@ func (h *TeamHandler) AssignVehicleToTeam(c *gin.Context) {
 		return
 	}
 
+	if strict && !h.checkCrewInTeam(c, span, teamID, vehicle) {
+		return
+	}
+
 	// Update vehicle assignment
 	err = h.vehicleRepo.UpdateAssignment(ctx, vehicleID, *companyID, vehicle.DriverID, vehicle.HelperID, &teamID)
 	if err != nil {
+		if errors.Is(err, repository.ErrAssignmentOutsideCompany) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
 		span.RecordError(err)
 		logger.Error("Failed to assign vehicle to team", zap.Error(err), zap.String("vehicle_id", vehicleID.String()), zap.String("team_id", teamID.String()))
 		utils.InternalServerErrorResponse(c, "Failed to assign vehicle to team")
 		return
 	}
 
+	if h.webhooks != nil {
+		h.webhooks.Dispatch(ctx, *companyID, models.WebhookEventVehicleAssignmentChanged,
+			assignmentChangedEventData(vehicle, vehicle.DriverID, vehicle.HelperID, &teamID))
+	}
+
 	span.SetAttributes(
 		attribute.String("team.id", teamID.String()),
 		attribute.String("vehicle.id", vehicleID.String()),
@@ -787,6 +1326,39 @@ func (h *TeamHandler) AssignVehicleToTeam(c *gin.Context) {
 	})
 }
 
+// checkCrewInTeam verifies the vehicle's driver and helper are members of the team. It writes
+// a 409 naming the first outsider (or a 500) and returns false when the check fails.
+func (h *TeamHandler) checkCrewInTeam(c *gin.Context, span trace.Span, teamID uuid.UUID, vehicle *models.Vehicle) bool {
+	crew := []struct {
+		assignment string
+		userID     *uuid.UUID
+	}{
+		{"driver", vehicle.DriverID},
+		{"helper", vehicle.HelperID},
+	}
+
+	for _, member := range crew {
+		if member.userID == nil {
+			continue
+		}
+		exists, err := h.teamRepo.CheckMemberExists(c.Request.Context(), teamID, *member.userID)
+		if err != nil {
+			span.RecordError(err)
+			utils.InternalServerErrorResponse(c, "Failed to check member existence")
+			return false
+		}
+		if !exists {
+			utils.ErrorResponse(c, http.StatusConflict, "Conflict", gin.H{
+				"message":    "The vehicle's " + member.assignment + " is not a member of this team",
+				"assignment": member.assignment,
+				"user_id":    *member.userID,
+			})
+			return false
+		}
+	}
+	return true
+}
+
 // UnassignVehicleFromTeam removes a vehicle from a team
 func (h *TeamHandler) UnassignVehicleFromTeam(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.UnassignVehicleFromTeam")
@@ -795,7 +1367,7 @@ func (h *TeamHandler) UnassignVehicleFromTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -854,6 +1426,11 @@ func (h *TeamHandler) UnassignVehicleFromTeam(c *gin.Context) {
 		return
 	}
 
+	if h.webhooks != nil {
+		h.webhooks.Dispatch(ctx, *companyID, models.WebhookEventVehicleAssignmentChanged,
+			assignmentChangedEventData(vehicle, vehicle.DriverID, vehicle.HelperID, nil))
+	}
+
 	span.SetAttributes(
 		attribute.String("team.id", teamID.String()),
 		attribute.String("vehicle.id", vehicleID.String()),
@@ -877,7 +1454,7 @@ func (h *TeamHandler) TransferMemberToTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -900,9 +1477,9 @@ func (h *TeamHandler) TransferMemberToTeam(c *gin.Context) {
 		ToTeamID   uuid.UUID `json:"to_team_id" binding:"required"`
 		RoleInTeam string    `json:"role_in_team" binding:"required,oneof=manager driver assistant supervisor helper team_lead"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		span.RecordError(err)
-		utils.ValidationErrorResponse(c, err)
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
 		return
 	}
 
@@ -994,6 +1571,12 @@ func (h *TeamHandler) TransferMemberToTeam(c *gin.Context) {
 // TEAM MEMBER HISTORY
 // ============================================================================
 
+// Page size of the team membership and vehicle assignment history endpoints
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
 // GetTeamMemberHistory retrieves the membership history for a team
 func (h *TeamHandler) GetTeamMemberHistory(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.GetTeamMemberHistory")
@@ -1002,7 +1585,7 @@ func (h *TeamHandler) GetTeamMemberHistory(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -1014,11 +1597,11 @@ func (h *TeamHandler) GetTeamMemberHistory(c *gin.Context) {
 		return
 	}
 
-	// Parse limit parameter (optional)
-	limitStr := c.DefaultQuery("limit", "50")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 500 {
-		limit = 50 // Default to 50 if invalid
+	// Parse limit parameter (optional, clamped to the maximum)
+	limit, err := utils.ParseLimit(c, defaultHistoryLimit, maxHistoryLimit)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
 	}
 
 	// Verify team exists and belongs to company
@@ -1067,7 +1650,7 @@ func (h *TeamHandler) GetUserTeamHistory(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -1079,11 +1662,11 @@ func (h *TeamHandler) GetUserTeamHistory(c *gin.Context) {
 		return
 	}
 
-	// Parse limit parameter (optional)
-	limitStr := c.DefaultQuery("limit", "50")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 500 {
-		limit = 50
+	// Parse limit parameter (optional, clamped to the maximum)
+	limit, err := utils.ParseLimit(c, defaultHistoryLimit, maxHistoryLimit)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
 	}
 
 	// Get user team history with details
@@ -1107,3 +1690,86 @@ func (h *TeamHandler) GetUserTeamHistory(c *gin.Context) {
 		"limit":   limit,
 	})
 }
+
+// teamGrowthBuckets lists the date_trunc units accepted by GetTeamGrowth
+var teamGrowthBuckets = map[string]bool{"day": true, "week": true, "month": true}
+
+// parseGrowthTime accepts either an RFC3339 timestamp or a plain date
+func parseGrowthTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// GetTeamGrowth returns member additions vs removals per time bucket for the company
+func (h *TeamHandler) GetTeamGrowth(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TeamHandler.GetTeamGrowth")
+	defer span.End()
+
+	// Get company ID from context
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	// Default to the last 30 days
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		to, err = parseGrowthTime(toStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid 'to' parameter, expected RFC3339 or YYYY-MM-DD")
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err = parseGrowthTime(fromStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid 'from' parameter, expected RFC3339 or YYYY-MM-DD")
+			return
+		}
+	}
+
+	if !from.Before(to) {
+		utils.BadRequestResponse(c, "'from' must be before 'to'")
+		return
+	}
+
+	bucket := c.DefaultQuery("bucket", "day")
+	if !teamGrowthBuckets[bucket] {
+		utils.BadRequestResponse(c, "Invalid bucket, must be one of: day, week, month")
+		return
+	}
+
+	growth, err := h.teamRepo.GetMembershipGrowth(ctx, *companyID, from, to, bucket)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve team growth")
+		return
+	}
+
+	totalAdded, totalRemoved := 0, 0
+	for _, b := range growth {
+		totalAdded += b.Added
+		totalRemoved += b.Removed
+	}
+
+	span.SetAttributes(
+		attribute.String("company.id", companyID.String()),
+		attribute.String("growth.bucket", bucket),
+		attribute.Int("growth.buckets", len(growth)),
+	)
+
+	utils.SuccessResponse(c, http.StatusOK, "Team growth retrieved successfully", gin.H{
+		"from":          from,
+		"to":            to,
+		"bucket":        bucket,
+		"buckets":       growth,
+		"total_added":   totalAdded,
+		"total_removed": totalRemoved,
+		"net_change":    totalAdded - totalRemoved,
+	})
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// TripHandler handles trip route (GPS points) HTTP requests
+type TripHandler struct {
+	tripRepo    *repository.TripRepository
+	vehicleRepo *repository.VehicleRepository
+	maxPoints   int
+	liveFeed    *services.LiveFeedHub
+	tracer      trace.Tracer
+}
+
+// NewTripHandler creates a new trip handler
+func NewTripHandler(tripRepo *repository.TripRepository, vehicleRepo *repository.VehicleRepository) *TripHandler {
+	return &TripHandler{
+		tripRepo:    tripRepo,
+		vehicleRepo: vehicleRepo,
+		maxPoints:   repository.DefaultMaxBatchSize,
+		tracer:      otel.Tracer("trip-handler"),
+	}
+}
+
+// SetMaxPoints caps the number of points accepted in a single batch
+func (h *TripHandler) SetMaxPoints(maxPoints int) {
+	h.maxPoints = maxPoints
+}
+
+// SetLiveFeed publishes recorded GPS points to the vehicle's real-time feed
+func (h *TripHandler) SetLiveFeed(hub *services.LiveFeedHub) {
+	h.liveFeed = hub
+}
+
+// vehicleTrip resolves the :id vehicle of the caller's company and its :tripId trip,
+// writing the error response when either can't be found
+func (h *TripHandler) vehicleTrip(c *gin.Context, span trace.Span) (*models.VehicleTrip, bool) {
+	_, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return nil, false
+	}
+
+	tripID, err := uuid.Parse(c.Param("tripId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid trip ID")
+		return nil, false
+	}
+
+	trip, err := h.tripRepo.GetByID(c.Request.Context(), tripID, vehicle.ID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve trip")
+		return nil, false
+	}
+	if trip == nil {
+		utils.NotFoundResponse(c, "Trip not found")
+		return nil, false
+	}
+
+	span.SetAttributes(attribute.String("trip.id", tripID.String()))
+	return trip, true
+}
+
+// AddTripPoints stores a batch of GPS positions for an active trip
+func (h *TripHandler) AddTripPoints(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TripHandler.AddTripPoints")
+	defer span.End()
+
+	trip, ok := h.vehicleTrip(c, span)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTripPointsRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+	if err := repository.CheckBatchSize(len(req.Points), h.maxPoints); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if trip.Status != "active" {
+		utils.ConflictResponse(c, "Points can only be added to an active trip")
+		return
+	}
+
+	points := make([]models.TripPoint, len(req.Points))
+	for i, p := range req.Points {
+		points[i] = models.TripPoint{
+			TripID:     trip.ID,
+			Latitude:   *p.Latitude,
+			Longitude:  *p.Longitude,
+			Speed:      p.Speed,
+			RecordedAt: p.RecordedAt,
+		}
+	}
+
+	if err := h.tripRepo.AddPoints(ctx, trip.ID, points); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to store trip points")
+		return
+	}
+
+	if h.liveFeed != nil {
+		h.liveFeed.Publish(models.LiveEvent{
+			Type:      models.LiveEventReading,
+			VehicleID: trip.VehicleID,
+			Source:    "trip",
+			Timestamp: time.Now().UTC(),
+			Data:      gin.H{"trip_id": trip.ID, "points": points},
+		})
+	}
+
+	span.SetAttributes(attribute.Int("points.count", len(points)))
+	utils.SuccessResponse(c, http.StatusCreated, "Trip points recorded successfully", gin.H{
+		"trip_id":  trip.ID,
+		"accepted": len(points),
+	})
+}
+
+// GetTripPoints returns the route of a trip as its GPS points in recording order
+func (h *TripHandler) GetTripPoints(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TripHandler.GetTripPoints")
+	defer span.End()
+
+	trip, ok := h.vehicleTrip(c, span)
+	if !ok {
+		return
+	}
+
+	points, err := h.tripRepo.ListPoints(ctx, trip.ID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve trip points")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trip points retrieved successfully", gin.H{
+		"trip_id":     trip.ID,
+		"points":      points,
+		"count":       len(points),
+		"distance_km": models.TripPointsDistanceKm(points),
+	})
+}
+
+// EndTrip completes an active trip, computing its distance from the recorded points
+// when the client doesn't send distance_km
+func (h *TripHandler) EndTrip(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "TripHandler.EndTrip")
+	defer span.End()
+
+	trip, ok := h.vehicleTrip(c, span)
+	if !ok {
+		return
+	}
+
+	var req models.EndTripRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	ended, err := h.tripRepo.EndTrip(ctx, trip.ID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrTripNotActive) {
+			utils.ConflictResponse(c, "Trip is not active")
+			return
+		}
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to end trip")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trip ended successfully", ended)
+}
@@ -1,19 +1,27 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // UserHandler handles HTTP requests for user operations
 type UserHandler struct {
-	userService *services.UserService
+	userService  *services.UserService
+	auditLogRepo repository.AuditLogRepositoryInterface
 }
 
 // NewUserHandler creates a new user handler
@@ -23,6 +31,11 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 	}
 }
 
+// SetAuditLogRepository sets the repository used to record forced session revocations
+func (h *UserHandler) SetAuditLogRepository(repo repository.AuditLogRepositoryInterface) {
+	h.auditLogRepo = repo
+}
+
 // getUserContext extracts UserContext from gin.Context
 func (h *UserHandler) getUserContext(c *gin.Context) *models.UserContext {
 	userContext, exists := c.Get("userContext")
@@ -36,7 +49,7 @@ func (h *UserHandler) getUserContext(c *gin.Context) *models.UserContext {
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	userContext := h.getUserContext(c)
 	if userContext == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -56,6 +69,14 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 		}
 	}
 
+	// offset, as on the other list endpoints, takes precedence over page
+	offset := (page - 1) * limit
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
 	var active *bool
 	if activeStr := c.Query("active"); activeStr != "" {
 		if a, err := strconv.ParseBool(activeStr); err == nil {
@@ -63,10 +84,18 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 		}
 	}
 
+	sort, err := repository.UserSortFields.Parse(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	req := services.UserListRequest{
 		Page:   page,
 		Limit:  limit,
+		Offset: offset,
 		Active: active,
+		Sort:   sort,
 	}
 
 	response, err := h.userService.GetUsers(c.Request.Context(), userContext, req)
@@ -79,14 +108,14 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	utils.PaginatedResponse(c, response.Users, response.Total, limit, offset)
 }
 
 // GetUserByID handles GET /users/:id
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userContext := h.getUserContext(c)
 	if userContext == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -114,11 +143,31 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// CheckEmail handles GET /users/check-email - lets user creation forms flag a taken email
+// before submitting. Only user admins can call it, under a rate limit, so it can't be used
+// to enumerate registered accounts.
+func (h *UserHandler) CheckEmail(c *gin.Context) {
+	var req models.CheckEmailRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email query parameter is required"})
+		return
+	}
+
+	available, err := h.userService.IsEmailAvailable(c.Request.Context(), req.Email)
+	if err != nil {
+		logger.Error("Failed to check email availability", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check email availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.EmailAvailability{Available: available})
+}
+
 // CreateUser handles POST /users
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	userContext := h.getUserContext(c)
 	if userContext == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -145,6 +194,10 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Role requires company assignment"})
 		case services.ErrRoleProhibitsCompany:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Role prohibits company assignment"})
+		case services.ErrInvalidCPF:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CPF"})
+		case services.ErrCPFAlreadyExists:
+			c.JSON(http.StatusConflict, gin.H{"error": "CPF already exists in this company"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -158,7 +211,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userContext := h.getUserContext(c)
 	if userContext == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -186,6 +239,10 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
 		case services.ErrCannotModifyOwnRole:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot modify own role"})
+		case services.ErrInvalidCPF:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CPF"})
+		case services.ErrCPFAlreadyExists:
+			c.JSON(http.StatusConflict, gin.H{"error": "CPF already exists in this company"})
 		case services.ErrInvalidRole:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
 		default:
@@ -201,7 +258,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userContext := h.getUserContext(c)
 	if userContext == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -230,11 +287,171 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// RevokeUserSessions handles POST /admin/users/:id/revoke-sessions - kills every session of
+// a (possibly compromised) account and optionally deactivates it
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	userContext := h.getUserContext(c)
+	if userContext == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// The body is optional; without it the sessions are revoked and the account stays active
+	var req models.RevokeSessionsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	targetUser, revoked, err := h.userService.RevokeUserSessions(c.Request.Context(), userContext, userID, req.Deactivate)
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case services.ErrCompanyMismatch:
+			c.JSON(http.StatusForbidden, gin.H{"error": "User does not belong to your company"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if h.auditLogRepo != nil {
+		// Method, path and status code are filled in from the request context
+		resourceID := userID.String()
+		metadata := map[string]interface{}{
+			"revoked_sessions": revoked,
+			"deactivated":      req.Deactivate,
+			"target_email":     targetUser.Email,
+		}
+		if req.Reason != "" {
+			metadata["reason"] = req.Reason
+		}
+		auditLog := &models.AuditLog{
+			UserID:     &userContext.UserID,
+			CompanyID:  targetUser.CompanyID,
+			Action:     "revoke_sessions",
+			Resource:   "user",
+			ResourceID: &resourceID,
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.GetHeader("User-Agent"),
+			Metadata:   metadata,
+			Success:    true,
+			CreatedAt:  utils.Now(),
+		}
+		if email := c.GetString("email"); email != "" {
+			auditLog.UserEmail = &email
+		}
+		if err := h.auditLogRepo.Create(c.Request.Context(), auditLog); err != nil {
+			logger.Error("Failed to create audit log for forced session revocation",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "User sessions revoked",
+		"user_id":          userID,
+		"revoked_sessions": revoked,
+		"active":           targetUser.Active,
+	})
+}
+
+// RequestAccountDeletion handles POST /profile/delete-request - the current user asks for their
+// account to be erased. The account is closed immediately; the company admins are notified and
+// the user's log data is anonymized after the grace period.
+func (h *UserHandler) RequestAccountDeletion(c *gin.Context) {
+	userContext := h.getUserContext(c)
+	if userContext == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	result, err := h.userService.RequestAccountDeletion(c.Request.Context(), userContext.UserID)
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case services.ErrLastCompanyAdmin:
+			c.JSON(http.StatusConflict, gin.H{"error": "You are the last administrator of your company; promote another administrator before deleting your account"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	middleware.SetAuditResourceID(c, userContext.UserID.String())
+	middleware.SetAuditMetadata(c, "revoked_sessions", result.RevokedSessions)
+	middleware.SetAuditMetadata(c, "erasure_scheduled_at", result.ErasureScheduledAt)
+
+	go func() {
+		if err := h.userService.NotifyAccountDeletion(context.Background(), result); err != nil {
+			logger.Error("Failed to notify admins of account deletion request",
+				zap.Error(err),
+				zap.String("user_id", userContext.UserID.String()))
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":              "Account deletion requested",
+		"revoked_sessions":     result.RevokedSessions,
+		"erasure_scheduled_at": result.ErasureScheduledAt,
+	})
+}
+
+// PurgeUser handles DELETE /master/users/:id/purge - irreversibly erases a user's personal
+// data for LGPD/GDPR erasure requests, keeping anonymized history for statistics
+func (h *UserHandler) PurgeUser(c *gin.Context) {
+	userContext := h.getUserContext(c)
+	if userContext == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	result, err := h.userService.PurgeUser(c.Request.Context(), userContext, userID)
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case services.ErrInsufficientPermissions:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		case services.ErrCannotDeleteSelf:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot purge yourself"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	middleware.SetAuditMetadata(c, "anonymized_auth_logs", result.AuthLogs)
+	middleware.SetAuditMetadata(c, "anonymized_audit_logs", result.AuditLogs)
+	middleware.SetAuditMetadata(c, "sessions_deleted", result.SessionsDeleted)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User purged",
+		"result":  result,
+	})
+}
+
 // TransferUserToCompany handles PATCH /master/users/:id/transfer - Master only
 func (h *UserHandler) TransferUserToCompany(c *gin.Context) {
 	userContext := h.getUserContext(c)
 	if userContext == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
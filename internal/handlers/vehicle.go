@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,6 +18,7 @@ import (
 	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
 	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
@@ -20,6 +26,10 @@ import (
 type VehicleHandler struct {
 	vehicleRepo *repository.VehicleRepository
 	teamRepo    *repository.TeamRepository
+	webhooks    *services.WebhookDispatcher
+	importer    *services.VehicleImporter
+	assignments *services.AssignmentNotifier
+	settings    services.CompanySettingsSource
 	tracer      trace.Tracer
 }
 
@@ -28,10 +38,46 @@ func NewVehicleHandler(vehicleRepo *repository.VehicleRepository, teamRepo *repo
 	return &VehicleHandler{
 		vehicleRepo: vehicleRepo,
 		teamRepo:    teamRepo,
+		importer:    services.NewVehicleImporter(vehicleRepo),
 		tracer:      otel.Tracer("vehicle-handler"),
 	}
 }
 
+// SetCompanySettings sets the source of the company settings deciding whether assignment
+// changes need a reason
+func (h *VehicleHandler) SetCompanySettings(settings services.CompanySettingsSource) {
+	h.settings = settings
+}
+
+// SetWebhookDispatcher sets the dispatcher used to notify company webhooks
+func (h *VehicleHandler) SetWebhookDispatcher(dispatcher *services.WebhookDispatcher) {
+	h.webhooks = dispatcher
+}
+
+// SetAssignmentNotifier sets the notifier that emails crews about assignment changes
+func (h *VehicleHandler) SetAssignmentNotifier(notifier *services.AssignmentNotifier) {
+	h.assignments = notifier
+}
+
+// SetImportMaxRows caps the number of rows accepted by ImportVehicles
+func (h *VehicleHandler) SetImportMaxRows(maxRows int) {
+	h.importer.SetMaxRows(maxRows)
+}
+
+// assignmentChangedEventData builds the vehicle.assignment_changed webhook payload
+func assignmentChangedEventData(previous *models.Vehicle, driverID, helperID, teamID *uuid.UUID) gin.H {
+	return gin.H{
+		"vehicle_id":         previous.ID,
+		"license_plate":      previous.LicensePlate,
+		"previous_driver_id": previous.DriverID,
+		"previous_helper_id": previous.HelperID,
+		"previous_team_id":   previous.TeamID,
+		"driver_id":          driverID,
+		"helper_id":          helperID,
+		"team_id":            teamID,
+	}
+}
+
 // CreateVehicle creates a new vehicle
 func (h *VehicleHandler) CreateVehicle(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.CreateVehicle")
@@ -40,7 +86,7 @@ func (h *VehicleHandler) CreateVehicle(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -89,7 +135,7 @@ func (h *VehicleHandler) CreateVehicle(c *gin.Context) {
 		attribute.String("company.id", companyID.String()),
 	)
 
-	utils.SuccessResponse(c, http.StatusCreated, "Vehicle created successfully", vehicle)
+	utils.CreatedResponse(c, vehicle.ID.String(), "Vehicle created successfully", vehicle)
 }
 
 // GetVehicles retrieves vehicles for a company
@@ -100,7 +146,7 @@ func (h *VehicleHandler) GetVehicles(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -118,33 +164,59 @@ func (h *VehicleHandler) GetVehicles(c *gin.Context) {
 		offset = 0
 	}
 
-	// Parse filter parameters (for future use)
+	// Parse filter parameters (team_id is echoed back but not applied yet)
 	status := c.Query("status")
 	teamIDStr := c.Query("team_id")
 	vehicleType := c.Query("vehicle_type")
 
-	vehicles, err := h.vehicleRepo.GetByCompany(ctx, *companyID, limit, offset)
+	filter := models.VehicleFilter{Status: status, VehicleType: vehicleType}
+	if status != "" && !models.IsValidVehicleStatus(status) {
+		utils.BadRequestResponse(c, "status must be one of "+strings.Join(models.VehicleStatuses, ", "))
+		return
+	}
+	if vehicleType != "" && !slices.Contains(models.VehicleTypes, vehicleType) {
+		utils.BadRequestResponse(c, "vehicle_type must be one of "+strings.Join(models.VehicleTypes, ", "))
+		return
+	}
+	if unassigned := c.Query("unassigned"); unassigned != "" {
+		filter.Unassigned, err = strconv.ParseBool(unassigned)
+		if err != nil {
+			utils.BadRequestResponse(c, "unassigned must be true or false")
+			return
+		}
+	}
+
+	sort, err := repository.VehicleSortFields.Parse(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	vehicles, err := h.vehicleRepo.GetByCompany(ctx, *companyID, filter, limit, offset, sort)
 	if err != nil {
 		span.RecordError(err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicles")
 		return
 	}
 
+	total, err := h.vehicleRepo.CountByCompany(ctx, *companyID, filter)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to count vehicles")
+		return
+	}
+
 	span.SetAttributes(
 		attribute.String("company.id", companyID.String()),
 		attribute.Int("vehicles.count", len(vehicles)),
+		attribute.Int("vehicles.total", total),
 	)
 
-	utils.SuccessResponse(c, http.StatusOK, "Vehicles retrieved successfully", gin.H{
-		"vehicles": vehicles,
-		"limit":    limit,
-		"offset":   offset,
-		"count":    len(vehicles),
-		"filters": gin.H{
-			"status":       status,
-			"team_id":      teamIDStr,
-			"vehicle_type": vehicleType,
-		},
+	utils.FilteredPaginatedResponse(c, vehicles, total, limit, offset, gin.H{
+		"status":       status,
+		"team_id":      teamIDStr,
+		"vehicle_type": vehicleType,
+		"unassigned":   filter.Unassigned,
 	})
 }
 
@@ -156,7 +228,7 @@ func (h *VehicleHandler) GetVehicle(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -188,6 +260,82 @@ func (h *VehicleHandler) GetVehicle(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Vehicle retrieved successfully", vehicle)
 }
 
+// GetVehicleByPlate retrieves a vehicle of the caller's company by license plate.
+// The plate is normalized first, so "abc-1234" finds ABC1234.
+func (h *VehicleHandler) GetVehicleByPlate(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.GetVehicleByPlate")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	plate := utils.NormalizeLicensePlate(c.Param("plate"))
+	if plate == "" {
+		utils.BadRequestResponse(c, "Invalid license plate")
+		return
+	}
+
+	vehicle, err := h.vehicleRepo.GetByLicensePlate(ctx, plate, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicle")
+		return
+	}
+
+	if vehicle == nil {
+		utils.NotFoundResponse(c, "Vehicle not found")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("vehicle.id", vehicle.ID.String()),
+		attribute.String("vehicle.license_plate", vehicle.LicensePlate),
+		attribute.String("company.id", companyID.String()),
+	)
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle retrieved successfully", vehicle)
+}
+
+// GetVehicleAssignment returns just the current driver, helper and team of a vehicle,
+// with their names, for clients refreshing an assignment without the full vehicle
+func (h *VehicleHandler) GetVehicleAssignment(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.GetVehicleAssignment")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	vehicleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid vehicle ID")
+		return
+	}
+
+	assignment, err := h.vehicleRepo.GetAssignment(ctx, vehicleID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicle assignment")
+		return
+	}
+	if assignment == nil {
+		utils.NotFoundResponse(c, "Vehicle not found")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("vehicle.id", vehicleID.String()),
+		attribute.String("company.id", companyID.String()),
+	)
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle assignment retrieved successfully", assignment)
+}
+
 // UpdateVehicle updates a vehicle
 func (h *VehicleHandler) UpdateVehicle(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.UpdateVehicle")
@@ -196,7 +344,7 @@ func (h *VehicleHandler) UpdateVehicle(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -207,7 +355,7 @@ func (h *VehicleHandler) UpdateVehicle(c *gin.Context) {
 		return
 	}
 
-	var req models.CreateVehicleRequest
+	var req models.UpdateVehicleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		span.RecordError(err)
 		utils.ValidationErrorResponse(c, err)
@@ -227,6 +375,15 @@ func (h *VehicleHandler) UpdateVehicle(c *gin.Context) {
 		return
 	}
 
+	if req.Status != nil && !vehicle.CanTransitionTo(*req.Status) {
+		if !models.IsValidVehicleStatus(*req.Status) {
+			utils.BadRequestResponse(c, fmt.Sprintf("Invalid status %q, must be one of %s", *req.Status, strings.Join(models.VehicleStatuses, ", ")))
+			return
+		}
+		utils.BadRequestResponse(c, fmt.Sprintf("Vehicle status cannot change from %s to %s", vehicle.Status, *req.Status))
+		return
+	}
+
 	// Validate team if provided
 	if req.TeamID != nil {
 		team, err := h.teamRepo.GetByID(ctx, *req.TeamID, *companyID)
@@ -248,6 +405,9 @@ func (h *VehicleHandler) UpdateVehicle(c *gin.Context) {
 	vehicle.DriverID = req.DriverID
 	vehicle.HelperID = req.HelperID
 	vehicle.TeamID = req.TeamID
+	if req.Status != nil {
+		vehicle.Status = *req.Status
+	}
 
 	err = h.vehicleRepo.Update(ctx, vehicle)
 	if err != nil {
@@ -272,7 +432,7 @@ func (h *VehicleHandler) DeleteVehicle(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -303,12 +463,12 @@ func (h *VehicleHandler) GetVehicleStats(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
 	// Get basic vehicle count as stats
-	vehicles, err := h.vehicleRepo.GetByCompany(ctx, *companyID, 1000, 0)
+	vehicles, err := h.vehicleRepo.GetByCompany(ctx, *companyID, models.VehicleFilter{}, 1000, 0, repository.Sort{})
 	if err != nil {
 		span.RecordError(err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicle statistics")
@@ -316,21 +476,15 @@ func (h *VehicleHandler) GetVehicleStats(c *gin.Context) {
 	}
 
 	// Calculate basic statistics
-	stats := map[string]interface{}{
-		"total_vehicles": len(vehicles),
-		"active":         0,
-		"inactive":       0,
-		"maintenance":    0,
-	}
-
+	stats := models.VehicleStats{TotalVehicles: len(vehicles)}
 	for _, vehicle := range vehicles {
 		switch vehicle.Status {
 		case "active":
-			stats["active"] = stats["active"].(int) + 1
+			stats.Active++
 		case "inactive":
-			stats["inactive"] = stats["inactive"].(int) + 1
+			stats.Inactive++
 		case "maintenance":
-			stats["maintenance"] = stats["maintenance"].(int) + 1
+			stats.Maintenance++
 		}
 	}
 
@@ -347,7 +501,7 @@ func (h *VehicleHandler) AssignVehicleToTeam(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -390,6 +544,7 @@ func (h *VehicleHandler) AssignVehicleToTeam(c *gin.Context) {
 	}
 
 	// Update vehicle team assignment
+	eventData := assignmentChangedEventData(vehicle, vehicle.DriverID, vehicle.HelperID, req.TeamID)
 	vehicle.TeamID = req.TeamID
 
 	err = h.vehicleRepo.Update(ctx, vehicle)
@@ -399,6 +554,10 @@ func (h *VehicleHandler) AssignVehicleToTeam(c *gin.Context) {
 		return
 	}
 
+	if h.webhooks != nil {
+		h.webhooks.Dispatch(ctx, *companyID, models.WebhookEventVehicleAssignmentChanged, eventData)
+	}
+
 	span.SetAttributes(
 		attribute.String("vehicle.id", vehicle.ID.String()),
 		attribute.String("vehicle.license_plate", vehicle.LicensePlate),
@@ -415,7 +574,7 @@ func (h *VehicleHandler) AssignUsers(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -433,6 +592,26 @@ func (h *VehicleHandler) AssignUsers(c *gin.Context) {
 		return
 	}
 
+	if req.Reason != nil {
+		if reason := strings.TrimSpace(*req.Reason); reason != "" {
+			req.Reason = &reason
+		} else {
+			req.Reason = nil
+		}
+	}
+	if req.Reason == nil && h.settings != nil {
+		record, err := h.settings.Get(ctx, *companyID)
+		if err != nil {
+			span.RecordError(err)
+			utils.InternalServerErrorResponse(c, "Failed to retrieve company settings")
+			return
+		}
+		if record.Settings.RequireAssignmentReason {
+			utils.BadRequestResponse(c, "A reason is required to change vehicle assignments")
+			return
+		}
+	}
+
 	// Get existing vehicle
 	vehicle, err := h.vehicleRepo.GetByID(ctx, vehicleID, *companyID)
 	if err != nil {
@@ -447,13 +626,26 @@ func (h *VehicleHandler) AssignUsers(c *gin.Context) {
 	}
 
 	// Update assignments
-	err = h.vehicleRepo.UpdateAssignment(ctx, vehicleID, *companyID, req.DriverID, req.HelperID, vehicle.TeamID)
+	err = h.vehicleRepo.UpdateAssignmentWithReason(ctx, vehicleID, *companyID, req.DriverID, req.HelperID, vehicle.TeamID, req.Reason)
 	if err != nil {
+		if errors.Is(err, repository.ErrAssignmentOutsideCompany) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
 		span.RecordError(err)
 		utils.InternalServerErrorResponse(c, "Failed to update vehicle assignment")
 		return
 	}
 
+	if h.webhooks != nil {
+		h.webhooks.Dispatch(ctx, *companyID, models.WebhookEventVehicleAssignmentChanged,
+			assignmentChangedEventData(vehicle, req.DriverID, req.HelperID, vehicle.TeamID))
+	}
+
+	if h.assignments != nil {
+		h.assignments.Enqueue(h.assignmentChange(ctx, vehicle, *companyID, req.DriverID, req.HelperID))
+	}
+
 	// Fetch updated vehicle
 	vehicle, _ = h.vehicleRepo.GetByID(ctx, vehicleID, *companyID)
 
@@ -464,6 +656,23 @@ func (h *VehicleHandler) AssignUsers(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Vehicle assignment updated successfully", vehicle)
 }
 
+// assignmentChange describes a crew update of vehicle for the assignment notifier
+func (h *VehicleHandler) assignmentChange(ctx context.Context, vehicle *models.Vehicle, companyID uuid.UUID, driverID, helperID *uuid.UUID) services.AssignmentChange {
+	change := services.AssignmentChange{
+		LicensePlate:     vehicle.LicensePlate,
+		PreviousDriverID: vehicle.DriverID,
+		PreviousHelperID: vehicle.HelperID,
+		DriverID:         driverID,
+		HelperID:         helperID,
+	}
+	if vehicle.TeamID != nil && h.teamRepo != nil {
+		if team, err := h.teamRepo.GetByID(ctx, *vehicle.TeamID, companyID); err == nil && team != nil {
+			change.ManagerID = team.ManagerID
+		}
+	}
+	return change
+}
+
 // GetMyVehicle retrieves the vehicle assigned to the current user
 func (h *VehicleHandler) GetMyVehicle(c *gin.Context) {
 	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.GetMyVehicle")
@@ -472,7 +681,7 @@ func (h *VehicleHandler) GetMyVehicle(c *gin.Context) {
 	// Get user ID from context
 	userIDStr := c.GetString("user_id")
 	if userIDStr == "" {
-		utils.UnauthorizedResponse(c, "User not authenticated")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -485,12 +694,12 @@ func (h *VehicleHandler) GetMyVehicle(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
 	// Get vehicles where user is driver or helper
-	vehicles, err := h.vehicleRepo.GetByCompany(ctx, *companyID, 1000, 0) // Get up to 1000 vehicles
+	vehicles, err := h.vehicleRepo.GetByCompany(ctx, *companyID, models.VehicleFilter{}, 1000, 0, repository.Sort{}) // Get up to 1000 vehicles
 	if err != nil {
 		span.RecordError(err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicles")
@@ -529,7 +738,7 @@ func (h *VehicleHandler) GetVehicleAssignmentHistory(c *gin.Context) {
 	// Get company ID from context
 	companyID, err := middleware.GetCompanyIDFromContext(c)
 	if err != nil || companyID == nil {
-		utils.BadRequestResponse(c, "Company context required")
+		utils.AuthContextMissingResponse(c)
 		return
 	}
 
@@ -541,11 +750,11 @@ func (h *VehicleHandler) GetVehicleAssignmentHistory(c *gin.Context) {
 		return
 	}
 
-	// Parse limit parameter (optional)
-	limitStr := c.DefaultQuery("limit", "50")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 500 {
-		limit = 50 // Default to 50 if invalid
+	// Parse limit parameter (optional, clamped to the maximum)
+	limit, err := utils.ParseLimit(c, defaultHistoryLimit, maxHistoryLimit)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
 	}
 
 	// Verify vehicle exists and belongs to company
@@ -587,3 +796,36 @@ func (h *VehicleHandler) GetVehicleAssignmentHistory(c *gin.Context) {
 		"limit":   limit,
 	})
 }
+
+// ============================================================================
+// ACTIVE TRIPS
+// ============================================================================
+
+// GetActiveTrips lists every trip in progress for the caller's company
+func (h *VehicleHandler) GetActiveTrips(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.GetActiveTrips")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	trips, err := h.vehicleRepo.GetActiveTripsByCompany(ctx, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve active trips")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("company.id", companyID.String()),
+		attribute.Int("trips.count", len(trips)),
+	)
+
+	utils.SuccessResponse(c, http.StatusOK, "Active trips retrieved successfully", gin.H{
+		"trips": trips,
+		"count": len(trips),
+	})
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+const defaultDocumentExpiryDays = 30
+
+// VehicleDocumentHandler handles vehicle document HTTP requests
+type VehicleDocumentHandler struct {
+	documentRepo repository.VehicleDocumentRepositoryInterface
+	vehicleRepo  *repository.VehicleRepository
+	tracer       trace.Tracer
+}
+
+// NewVehicleDocumentHandler creates a new vehicle document handler
+func NewVehicleDocumentHandler(documentRepo repository.VehicleDocumentRepositoryInterface, vehicleRepo *repository.VehicleRepository) *VehicleDocumentHandler {
+	return &VehicleDocumentHandler{
+		documentRepo: documentRepo,
+		vehicleRepo:  vehicleRepo,
+		tracer:       otel.Tracer("vehicle-document-handler"),
+	}
+}
+
+// CreateDocument registers a document for a vehicle
+func (h *VehicleDocumentHandler) CreateDocument(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleDocumentHandler.CreateDocument")
+	defer span.End()
+
+	_, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	var req models.CreateVehicleDocumentRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	if req.IssuedAt != nil && req.IssuedAt.After(req.ExpiresAt) {
+		utils.BadRequestResponse(c, "issued_at must be before expires_at")
+		return
+	}
+
+	createdBy, _ := middleware.GetUserIDFromContext(c)
+
+	doc := &models.VehicleDocument{
+		VehicleID: vehicle.ID,
+		Type:      req.Type,
+		Number:    req.Number,
+		IssuedAt:  req.IssuedAt,
+		ExpiresAt: req.ExpiresAt,
+		Notes:     req.Notes,
+		CreatedBy: createdBy,
+	}
+
+	if err := h.documentRepo.Create(ctx, doc); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to create vehicle document")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Vehicle document created successfully", doc)
+}
+
+// GetDocuments lists the documents of a vehicle
+func (h *VehicleDocumentHandler) GetDocuments(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleDocumentHandler.GetDocuments")
+	defer span.End()
+
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return
+	}
+
+	docs, err := h.documentRepo.ListByVehicle(ctx, vehicle.ID, companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicle documents")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle documents retrieved successfully", gin.H{
+		"vehicle_id": vehicle.ID,
+		"documents":  docs,
+	})
+}
+
+// GetDocument retrieves a single document of a vehicle
+func (h *VehicleDocumentHandler) GetDocument(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleDocumentHandler.GetDocument")
+	defer span.End()
+
+	doc, _, ok := h.vehicleDocument(ctx, c, span)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle document retrieved successfully", doc)
+}
+
+// UpdateDocument updates a document of a vehicle
+func (h *VehicleDocumentHandler) UpdateDocument(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleDocumentHandler.UpdateDocument")
+	defer span.End()
+
+	var req models.UpdateVehicleDocumentRequest
+	if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+		span.RecordError(bindErr)
+		utils.BindErrorResponse(c, bindErr)
+		return
+	}
+
+	doc, companyID, ok := h.vehicleDocument(ctx, c, span)
+	if !ok {
+		return
+	}
+
+	if req.Type != nil {
+		doc.Type = *req.Type
+	}
+	if req.Number != nil {
+		doc.Number = *req.Number
+	}
+	if req.IssuedAt != nil {
+		doc.IssuedAt = req.IssuedAt
+	}
+	if req.ExpiresAt != nil {
+		doc.ExpiresAt = *req.ExpiresAt
+	}
+	if req.Notes != nil {
+		doc.Notes = req.Notes
+	}
+
+	if doc.IssuedAt != nil && doc.IssuedAt.After(doc.ExpiresAt) {
+		utils.BadRequestResponse(c, "issued_at must be before expires_at")
+		return
+	}
+
+	if err := h.documentRepo.Update(ctx, doc, companyID); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to update vehicle document")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle document updated successfully", doc)
+}
+
+// DeleteDocument removes a document of a vehicle
+func (h *VehicleDocumentHandler) DeleteDocument(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleDocumentHandler.DeleteDocument")
+	defer span.End()
+
+	doc, companyID, ok := h.vehicleDocument(ctx, c, span)
+	if !ok {
+		return
+	}
+
+	if err := h.documentRepo.Delete(ctx, doc.ID, doc.VehicleID, companyID); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to delete vehicle document")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle document deleted successfully", nil)
+}
+
+// GetExpiringDocuments lists the company's vehicle documents expiring within the
+// given number of days (query parameter days, default 30), including lapsed ones
+func (h *VehicleDocumentHandler) GetExpiringDocuments(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleDocumentHandler.GetExpiringDocuments")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultDocumentExpiryDays)))
+	if err != nil || days < 0 || days > 365 {
+		utils.BadRequestResponse(c, "days must be between 0 and 365")
+		return
+	}
+
+	docs, err := h.documentRepo.GetExpiringDocuments(ctx, *companyID, days)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve expiring documents")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Expiring documents retrieved successfully", gin.H{
+		"within_days": days,
+		"documents":   docs,
+	})
+}
+
+// vehicleDocument resolves the :documentId document of the :id vehicle, writing the
+// error response and returning false when it can't be found in the company
+func (h *VehicleDocumentHandler) vehicleDocument(ctx context.Context, c *gin.Context, span trace.Span) (*models.VehicleDocument, uuid.UUID, bool) {
+	companyID, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid document ID")
+		return nil, uuid.Nil, false
+	}
+
+	doc, err := h.documentRepo.GetByID(ctx, documentID, vehicle.ID, companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve vehicle document")
+		return nil, uuid.Nil, false
+	}
+	if doc == nil {
+		utils.NotFoundResponse(c, "Vehicle document not found")
+		return nil, uuid.Nil, false
+	}
+
+	span.SetAttributes(attribute.String("document.id", doc.ID.String()))
+	return doc, companyID, true
+}
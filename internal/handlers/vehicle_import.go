@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// vehicleImportFormField is the multipart field carrying the CSV file
+const vehicleImportFormField = "file"
+
+// vehicleImportMaxBytes bounds the size of an import request
+const vehicleImportMaxBytes = 2 << 20
+
+// ImportVehicles creates the company's vehicles from a CSV file, sent either as a multipart
+// "file" field or as a text/csv body, and reports which rows were created or rejected
+func (h *VehicleHandler) ImportVehicles(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "VehicleHandler.ImportVehicles")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, vehicleImportMaxBytes)
+
+	var body io.Reader = c.Request.Body
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		fileHeader, err := c.FormFile(vehicleImportFormField)
+		if err != nil {
+			if isMaxBytesError(err) {
+				utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "The CSV file is too large", nil)
+				return
+			}
+			utils.BadRequestResponse(c, "A multipart file field named \"file\" is required")
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.BadRequestResponse(c, "Failed to read uploaded file")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.importer.Import(ctx, *companyID, body)
+	if err != nil {
+		span.RecordError(err)
+		var headerErr *services.VehicleImportHeaderError
+		switch {
+		case errors.As(err, &headerErr):
+			utils.ErrorResponse(c, http.StatusBadRequest, "Bad Request", gin.H{
+				"message":          headerErr.Error(),
+				"missing_columns":  headerErr.Missing,
+				"unknown_columns":  headerErr.Unknown,
+				"repeated_columns": headerErr.Repeated,
+				"expected_columns": services.VehicleImportColumns,
+			})
+		case isMaxBytesError(err):
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "The CSV file is too large", nil)
+		case errors.Is(err, services.ErrVehicleImportEmpty),
+			errors.Is(err, services.ErrVehicleImportInvalidHeader),
+			errors.Is(err, repository.ErrBatchTooLarge),
+			errors.Is(err, services.ErrVehicleImportMalformed):
+			utils.BadRequestResponse(c, err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to import vehicles")
+		}
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("company.id", companyID.String()),
+		attribute.Int("vehicles.created", result.Created),
+		attribute.Int("vehicles.rejected", result.Rejected),
+	)
+	middleware.SetAuditMetadata(c, "created", result.Created)
+	middleware.SetAuditMetadata(c, "rejected", result.Rejected)
+
+	utils.SuccessResponse(c, http.StatusOK, "Vehicle import processed", result)
+}
+
+// GetImportTemplate sends the CSV header expected by ImportVehicles, with an example row
+// when ?sample=true, so integrators can generate files the importer accepts
+func (h *VehicleHandler) GetImportTemplate(c *gin.Context) {
+	withSample := false
+	if value := c.Query("sample"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.BadRequestResponse(c, "sample must be true or false")
+			return
+		}
+		withSample = parsed
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="vehicle_import_template.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(services.VehicleImportColumns)
+	if withSample {
+		_ = writer.Write(services.VehicleImportSampleRow)
+	}
+	writer.Flush()
+}
+
+// isMaxBytesError reports whether err comes from a body cut off by http.MaxBytesReader
+func isMaxBytesError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
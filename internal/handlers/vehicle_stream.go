@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+const (
+	defaultStreamHeartbeat = 30 * time.Second
+	streamWriteTimeout     = 10 * time.Second
+)
+
+var errStreamOriginNotAllowed = errors.New("origin not allowed")
+
+// VehicleStreamHandler pushes a vehicle's sensor readings and alerts to WebSocket clients
+type VehicleStreamHandler struct {
+	hub            *services.LiveFeedHub
+	vehicleRepo    *repository.VehicleRepository
+	allowedOrigins map[string]bool
+	heartbeat      time.Duration
+	tracer         trace.Tracer
+}
+
+// NewVehicleStreamHandler creates a new vehicle stream handler
+func NewVehicleStreamHandler(hub *services.LiveFeedHub, vehicleRepo *repository.VehicleRepository) *VehicleStreamHandler {
+	return &VehicleStreamHandler{
+		hub:            hub,
+		vehicleRepo:    vehicleRepo,
+		allowedOrigins: map[string]bool{},
+		heartbeat:      defaultStreamHeartbeat,
+		tracer:         otel.Tracer("vehicle-stream-handler"),
+	}
+}
+
+// SetAllowedOrigins lists the browser origins, besides the API's own, that may open a stream
+func (h *VehicleStreamHandler) SetAllowedOrigins(origins []string) {
+	h.allowedOrigins = make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		h.allowedOrigins[origin] = true
+	}
+}
+
+// SetHeartbeatInterval sets how often an idle stream sends a heartbeat, which also
+// detects clients that went away without closing the connection
+func (h *VehicleStreamHandler) SetHeartbeatInterval(interval time.Duration) {
+	if interval > 0 {
+		h.heartbeat = interval
+	}
+}
+
+// StreamVehicle upgrades the request to a WebSocket and pushes the vehicle's live events
+func (h *VehicleStreamHandler) StreamVehicle(c *gin.Context) {
+	_, span := h.tracer.Start(c.Request.Context(), "VehicleStreamHandler.StreamVehicle")
+	_, vehicle, ok := companyVehicle(c, span, h.vehicleRepo)
+	span.End()
+	if !ok {
+		return
+	}
+
+	if !c.IsWebsocket() {
+		utils.BadRequestResponse(c, "WebSocket upgrade required")
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.serve(ws, vehicle.ID)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin rejects cross-site browsers so a session cookie can't be used to open a
+// stream from another site. Clients that send no Origin (devices, scripts) are accepted.
+func (h *VehicleStreamHandler) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if parsed.Host != req.Host && !h.allowedOrigins[origin] {
+		return errStreamOriginNotAllowed
+	}
+	config.Origin = parsed
+	return nil
+}
+
+// serve forwards events to the connection until the client disconnects, a write fails
+// or the subscription is dropped for falling behind
+func (h *VehicleStreamHandler) serve(ws *websocket.Conn, vehicleID uuid.UUID) {
+	defer ws.Close()
+
+	sub := h.hub.Subscribe(vehicleID)
+	defer sub.Close()
+
+	// Clients don't send anything meaningful; reading only detects the disconnect
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var msg []byte
+		for {
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Lagged() {
+					logger.Warn("Vehicle stream client too slow, disconnecting",
+						zap.String("vehicle_id", vehicleID.String()))
+				}
+				return
+			}
+			if err := sendStreamEvent(ws, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			heartbeat := models.LiveEvent{Type: models.LiveEventHeartbeat, VehicleID: vehicleID, Timestamp: time.Now().UTC()}
+			if err := sendStreamEvent(ws, heartbeat); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+func sendStreamEvent(ws *websocket.Conn, event models.LiveEvent) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(ws, event)
+}
@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// WebhookHandler handles webhook management HTTP requests
+type WebhookHandler struct {
+	webhookRepo repository.WebhookRepositoryInterface
+	tracer      trace.Tracer
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookRepo repository.WebhookRepositoryInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+		tracer:      otel.Tracer("webhook-handler"),
+	}
+}
+
+// generateWebhookSecret returns a random hex secret for signing payloads
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateWebhook registers a new webhook for the company
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "WebhookHandler.CreateWebhook")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		span.RecordError(err)
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := services.ValidateWebhookURL(ctx, req.URL); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	secret := ""
+	if req.Secret != nil {
+		secret = *req.Secret
+	} else {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			span.RecordError(err)
+			utils.InternalServerErrorResponse(c, "Failed to generate webhook secret")
+			return
+		}
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	webhook := &models.Webhook{
+		CompanyID: *companyID,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    req.Events,
+		Active:    active,
+	}
+
+	if err := h.webhookRepo.Create(ctx, webhook); err != nil {
+		span.RecordError(err)
+		logger.Error("Failed to create webhook", zap.Error(err), zap.String("company_id", companyID.String()))
+		utils.InternalServerErrorResponse(c, "Failed to create webhook")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("webhook.id", webhook.ID.String()),
+		attribute.String("company.id", companyID.String()),
+	)
+
+	// The secret is only returned once, at creation time
+	middleware.NoStoreIdempotentResponse(c)
+	utils.SuccessResponse(c, http.StatusCreated, "Webhook created successfully", gin.H{
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+// GetWebhooks lists the webhooks registered by the company
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "WebhookHandler.GetWebhooks")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	webhooks, err := h.webhookRepo.GetByCompany(ctx, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve webhooks")
+		return
+	}
+
+	span.SetAttributes(attribute.Int("webhooks.count", len(webhooks)))
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhooks retrieved successfully", gin.H{
+		"webhooks": webhooks,
+		"count":    len(webhooks),
+	})
+}
+
+// GetWebhook retrieves a specific webhook
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "WebhookHandler.GetWebhook")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook ID")
+		return
+	}
+
+	webhook, err := h.webhookRepo.GetByID(ctx, webhookID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve webhook")
+		return
+	}
+
+	if webhook == nil {
+		utils.NotFoundResponse(c, "Webhook not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook retrieved successfully", webhook)
+}
+
+// UpdateWebhook updates a webhook
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "WebhookHandler.UpdateWebhook")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook ID")
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		span.RecordError(err)
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	webhook, err := h.webhookRepo.GetByID(ctx, webhookID, *companyID)
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve webhook")
+		return
+	}
+
+	if webhook == nil {
+		utils.NotFoundResponse(c, "Webhook not found")
+		return
+	}
+
+	if req.URL != nil {
+		if err := services.ValidateWebhookURL(ctx, *req.URL); err != nil {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		webhook.Events = req.Events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := h.webhookRepo.Update(ctx, webhook); err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to update webhook")
+		return
+	}
+
+	span.SetAttributes(attribute.String("webhook.id", webhook.ID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook updated successfully", webhook)
+}
+
+// RotateWebhookSecret replaces the signing secret of a webhook and returns the new one once.
+// The old secret keeps signing deliveries for the requested grace window.
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "WebhookHandler.RotateWebhookSecret")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook ID")
+		return
+	}
+
+	// The body is optional; without it the old secret stays valid for the default grace window
+	var req models.RotateWebhookSecretRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			span.RecordError(err)
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	graceHours := models.DefaultWebhookSecretGraceHours
+	if req.GracePeriodHours != nil {
+		graceHours = *req.GracePeriodHours
+	}
+
+	var previousExpiresAt *time.Time
+	if graceHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(graceHours) * time.Hour)
+		previousExpiresAt = &expiresAt
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		span.RecordError(err)
+		utils.InternalServerErrorResponse(c, "Failed to generate webhook secret")
+		return
+	}
+
+	webhook, err := h.webhookRepo.RotateSecret(ctx, webhookID, *companyID, secret, previousExpiresAt)
+	if err != nil {
+		span.RecordError(err)
+		logger.Error("Failed to rotate webhook secret", zap.Error(err), zap.String("webhook_id", webhookID.String()))
+		utils.InternalServerErrorResponse(c, "Failed to rotate webhook secret")
+		return
+	}
+
+	if webhook == nil {
+		utils.NotFoundResponse(c, "Webhook not found")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("webhook.id", webhook.ID.String()),
+		attribute.Int("webhook.grace_period_hours", graceHours),
+	)
+
+	// Like at creation, the new secret is only returned once
+	middleware.NoStoreIdempotentResponse(c)
+	utils.SuccessResponse(c, http.StatusOK, "Webhook secret rotated successfully", gin.H{
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+// DeleteWebhook removes a webhook
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), "WebhookHandler.DeleteWebhook")
+	defer span.End()
+
+	companyID, err := middleware.GetCompanyIDFromContext(c)
+	if err != nil || companyID == nil {
+		utils.AuthContextMissingResponse(c)
+		return
+	}
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookRepo.Delete(ctx, webhookID, *companyID); err != nil {
+		span.RecordError(err)
+		logger.Error("Failed to delete webhook", zap.Error(err), zap.String("webhook_id", webhookID.String()))
+		utils.InternalServerErrorResponse(c, "Failed to delete webhook")
+		return
+	}
+
+	span.SetAttributes(attribute.String("webhook.id", webhookID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook deleted successfully", nil)
+}
@@ -90,6 +90,20 @@ var (
 		},
 	)
 
+	ActiveSessionsTotal = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "active_sessions_total",
+			Help: "Number of sessions that are neither revoked nor expired",
+		},
+	)
+
+	ActiveTripsTotal = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "active_trips_total",
+			Help: "Number of vehicle trips currently in progress",
+		},
+	)
+
 	// Business metrics
 	DashboardViewsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -105,4 +119,38 @@ var (
 			Help: "Total number of companies in the system",
 		},
 	)
+
+	// Idempotency metrics
+	IdempotentReplaysTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "idempotent_replays_total",
+			Help: "Write requests answered from a stored Idempotency-Key response instead of running again",
+		},
+		[]string{"method"},
+	)
+
+	IdempotencyKeysPurgedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "idempotency_keys_purged_total",
+			Help: "Expired idempotency keys deleted by the cleanup job",
+		},
+	)
+
+	// Email metrics
+	EmailSentTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dashtrack_email_sent_total",
+			Help: "Emails handed to the SMTP server, by email type and result (success or failure)",
+		},
+		[]string{"type", "result"},
+	)
+
+	EmailSendDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dashtrack_email_send_duration_seconds",
+			Help:    "Time spent delivering an email to the SMTP server",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"type"},
+	)
 )
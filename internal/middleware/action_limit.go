@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+)
+
+// ActionRateLimiter limits how many times a user can run a costly action per window,
+// such as a data export or a test email. These actions get a budget separate from the
+// general request rate limits; each route creates its own limiter, so budgets are not
+// shared between actions.
+type ActionRateLimiter struct {
+	action     string
+	maxActions int
+	window     time.Duration
+	cache      map[string]*RateLimitCache
+	lastSweep  time.Time
+	mutex      sync.Mutex
+}
+
+// NewActionRateLimiter creates a new action rate limiter. action names the limited
+// action in logs and error responses.
+func NewActionRateLimiter(action string, maxActions int, window time.Duration) *ActionRateLimiter {
+	return &ActionRateLimiter{
+		action:     action,
+		maxActions: maxActions,
+		window:     window,
+		cache:      make(map[string]*RateLimitCache),
+	}
+}
+
+// Middleware returns a gin middleware enforcing the per-user action budget.
+// A non-positive limit disables the check.
+func (l *ActionRateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.maxActions <= 0 {
+			c.Next()
+			return
+		}
+
+		key := "ip:" + c.ClientIP()
+		if userID := c.GetString("user_id"); userID != "" {
+			key = "user:" + userID
+		}
+
+		allowed, remaining, resetAt := l.allow(key)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(l.maxActions))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if !allowed {
+			retryAfter := int(time.Until(resetAt).Seconds()) + 1
+			logger.Warn(l.action+" rate limit exceeded",
+				zap.String("key", key),
+				zap.String("path", c.Request.URL.Path),
+			)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       l.action + " rate limit exceeded",
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow records an attempt of the action for key and reports whether it is within budget
+func (l *ActionRateLimiter) allow(key string) (bool, int, time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	entry, exists := l.cache[key]
+	if !exists || now.After(entry.ResetTime) {
+		entry = &RateLimitCache{ResetTime: now.Add(l.window)}
+		l.cache[key] = entry
+	}
+
+	if entry.Count >= l.maxActions {
+		entry.Blocked = true
+		return false, 0, entry.ResetTime
+	}
+
+	entry.Count++
+	return true, l.maxActions - entry.Count, entry.ResetTime
+}
+
+// sweep drops the entries whose window has ended, at most once per window, so users who
+// acted once do not stay in memory for the life of the process. Callers hold the mutex.
+func (l *ActionRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for key, entry := range l.cache {
+		if now.After(entry.ResetTime) {
+			delete(l.cache, key)
+		}
+	}
+}
+
+// Size returns the number of users and clients currently tracked
+func (l *ActionRateLimiter) Size() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.cache)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+const (
+	auditMetadataKey   = "audit_metadata"
+	auditResourceIDKey = "audit_resource_id"
+)
+
+// SetAuditMetadata adds an entry to the metadata of the audit log AuditAction writes for
+// the current request
+func SetAuditMetadata(c *gin.Context, key string, value interface{}) {
+	metadata, _ := c.Get(auditMetadataKey)
+	m, ok := metadata.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+		c.Set(auditMetadataKey, m)
+	}
+	m[key] = value
+}
+
+// SetAuditResourceID sets the resource ID of the audit log AuditAction writes for the
+// current request, overriding the one taken from the route parameters
+func SetAuditResourceID(c *gin.Context, resourceID string) {
+	c.Set(auditResourceIDKey, resourceID)
+}
+
+// AuditAction records a named action in audit_logs once the route's handler has run, with
+// the acting user, method, path, status code and latency, plus any metadata the handler
+// attached with SetAuditMetadata. Failed requests are recorded with success = false. It
+// must run after RequireAuth so the user is known.
+func AuditAction(repo repository.AuditLogRepositoryInterface, action, resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		duration := time.Since(start).Milliseconds()
+		statusCode := c.Writer.Status()
+		method := c.Request.Method
+		path := c.Request.URL.Path
+
+		auditLog := &models.AuditLog{
+			CompanyID:  extractCompanyID(c),
+			Action:     action,
+			Resource:   resource,
+			Method:     &method,
+			Path:       &path,
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			Success:    statusCode < 400,
+			StatusCode: &statusCode,
+			DurationMs: &duration,
+			CreatedAt:  time.Now(),
+		}
+
+		if userID := extractUserID(c); userID != uuid.Nil {
+			auditLog.UserID = &userID
+		}
+		if email := c.GetString("email"); email != "" {
+			auditLog.UserEmail = &email
+		}
+
+		if resourceID := c.GetString(auditResourceIDKey); resourceID != "" {
+			auditLog.ResourceID = &resourceID
+		} else if id := extractResourceID(c); id != nil {
+			resourceID := id.String()
+			auditLog.ResourceID = &resourceID
+		}
+
+		if metadata, ok := c.Get(auditMetadataKey); ok {
+			auditLog.Metadata, _ = metadata.(map[string]interface{})
+		}
+
+		if err, ok := c.Get("error"); ok && !auditLog.Success {
+			if e, isErr := err.(error); isErr {
+				message := e.Error()
+				auditLog.ErrorMessage = &message
+			}
+		}
+
+		if err := repo.Create(c.Request.Context(), auditLog); err != nil {
+			// Don't fail the request if the audit log fails
+			logger.Error("Failed to create audit log",
+				zap.Error(err),
+				zap.String("action", action),
+				zap.String("path", path))
+		}
+	}
+}
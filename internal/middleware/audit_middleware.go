@@ -125,6 +125,8 @@ func shouldSkipAudit(path string) bool {
 		"/health",
 		"/metrics",
 		"/favicon.ico",
+		"/docs",
+		"/openapi.json",
 	}
 
 	for _, skipPath := range skipPaths {
@@ -153,17 +155,11 @@ func extractUserID(c *gin.Context) uuid.UUID {
 
 // extractCompanyID extracts company ID from context
 func extractCompanyID(c *gin.Context) *uuid.UUID {
-	companyIDStr := c.GetString("company_id")
-	if companyIDStr == "" {
-		return nil
-	}
-
-	companyID, err := uuid.Parse(companyIDStr)
+	companyID, err := GetCompanyIDFromContext(c)
 	if err != nil {
 		return nil
 	}
-
-	return &companyID
+	return companyID
 }
 
 // extractUserEmail extracts user email from context
@@ -1,16 +1,29 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
+// APITokenHeader carries the API token of devices and integrations
+const APITokenHeader = "X-API-Token"
+
+// APITokenLookup resolves the active user owning an API token, nil when there is none
+type APITokenLookup interface {
+	GetByAPIToken(ctx context.Context, token string) (*models.User, error)
+}
+
 type GinAuthMiddleware struct {
 	tokenService *services.TokenService
+	cookies      *SessionCookies
+	apiTokens    APITokenLookup
+	permissions  PermissionChecker
 }
 
 func NewGinAuthMiddleware(tokenService *services.TokenService) *GinAuthMiddleware {
@@ -19,26 +32,42 @@ func NewGinAuthMiddleware(tokenService *services.TokenService) *GinAuthMiddlewar
 	}
 }
 
+// SetSessionCookies lets requests without an Authorization header authenticate with the
+// access token cookie
+func (m *GinAuthMiddleware) SetSessionCookies(cookies *SessionCookies) {
+	m.cookies = cookies
+}
+
+// SetAPITokenLookup enables RequireAPIToken
+func (m *GinAuthMiddleware) SetAPITokenLookup(lookup APITokenLookup) {
+	m.apiTokens = lookup
+}
+
 // RequireAuth middleware ensures the request has a valid JWT token
 func (m *GinAuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		var tokenString string
+
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
+		if authHeader != "" {
+			// Check Bearer token format
+			tokenParts := strings.Split(authHeader, " ")
+			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+				c.Abort()
+				return
+			}
+			tokenString = tokenParts[1]
+		} else if m.cookies != nil {
+			tokenString = m.cookies.AccessToken(c)
 		}
 
-		// Check Bearer token format
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			c.Abort()
 			return
 		}
 
-		tokenString := tokenParts[1]
-
 		// Validate token using TokenService and get session_id
 		user, sessionID, err := m.tokenService.ValidateAccessTokenWithSession(c.Request.Context(), tokenString)
 		if err != nil {
@@ -47,37 +76,71 @@ func (m *GinAuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Set user context
-		c.Set("user_id", user.ID.String())
 		c.Set("session_id", sessionID.String())
-		c.Set("email", user.Email)
-		c.Set("name", user.Name)
-		c.Set("role_id", user.RoleID.String())
-		c.Set("role_name", user.Role.Name)
-		c.Set("user_role", user.Role.Name) // For compatibility with UserHandler
-		if user.CompanyID != nil {
-			c.Set("tenant_id", user.CompanyID.String())
-			c.Set("company_id", user.CompanyID.String()) // For compatibility with UserHandler
+		setAuthenticatedUser(c, user)
+
+		c.Next()
+	}
+}
+
+// RequireAPIToken middleware authenticates devices and integrations streaming data with
+// the X-API-Token header instead of a session JWT
+func (m *GinAuthMiddleware) RequireAPIToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(APITokenHeader)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API token required"})
+			c.Abort()
+			return
+		}
+
+		if m.apiTokens == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API token authentication is not enabled"})
+			c.Abort()
+			return
 		}
 
-		// Create user context for multitenant middleware
-		userContext := &models.UserContext{
-			UserID:    user.ID,
-			CompanyID: user.CompanyID,
-			Role:      user.Role.Name,
-			IsMaster:  user.Role.Name == "master",
+		user, err := m.apiTokens.GetByAPIToken(c.Request.Context(), token)
+		if err != nil || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API token"})
+			c.Abort()
+			return
 		}
-		c.Set("userContext", userContext)
 
+		setAuthenticatedUser(c, user)
 		c.Next()
 	}
-} // RequireRole middleware ensures the user has the specified role
+}
+
+// setAuthenticatedUser stores the authenticated user in the request context
+func setAuthenticatedUser(c *gin.Context, user *models.User) {
+	c.Set("user_id", user.ID.String())
+	c.Set("email", user.Email)
+	c.Set("name", user.Name)
+	c.Set("role_id", user.RoleID.String())
+	c.Set("role_name", user.Role.Name)
+	c.Set("user_role", user.Role.Name) // For compatibility with UserHandler
+	if user.CompanyID != nil {
+		c.Set("tenant_id", user.CompanyID.String())
+	}
+
+	// Create user context for multitenant middleware
+	userContext := &models.UserContext{
+		UserID:    user.ID,
+		CompanyID: user.CompanyID,
+		Role:      user.Role.Name,
+		IsMaster:  user.Role.Name == "master",
+	}
+	c.Set("userContext", userContext)
+}
+
+// RequireRole middleware ensures the user has the specified role
 // Master role has universal access to all routes
 func (m *GinAuthMiddleware) RequireRole(role string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role_name")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -106,7 +169,7 @@ func (m *GinAuthMiddleware) RequireAnyRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role_name")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -137,7 +200,7 @@ func (m *GinAuthMiddleware) RequireAdminRole() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role_name")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -165,7 +228,7 @@ func (m *GinAuthMiddleware) RequireMasterRole() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role_name")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User context not found"})
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
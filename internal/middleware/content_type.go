@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// RequireJSONContentType rejects POST, PUT and PATCH requests whose body isn't declared
+// as JSON with 415, instead of letting the JSON binding fail with a confusing error.
+// Requests without a body pass, as do the routes in exemptRoutes (route patterns as
+// returned by c.FullPath()), which accept uploads.
+func RequireJSONContentType(exemptRoutes ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptRoutes))
+	for _, route := range exemptRoutes {
+		exempt[route] = true
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength == 0 || exempt[c.FullPath()] || isJSONContentType(c.GetHeader("Content-Type")) {
+			c.Next()
+			return
+		}
+
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, "Unsupported Media Type", gin.H{
+			"code":    utils.ErrCodeUnsupportedMediaType,
+			"message": "Content-Type must be application/json",
+		})
+		c.Abort()
+	}
+}
+
+// isJSONContentType reports whether contentType is application/json or a +json type,
+// ignoring parameters such as charset
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
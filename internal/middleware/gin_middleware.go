@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"github.com/paulochiaradia/dashtrack/internal/metrics"
+	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/tracing"
 	"go.uber.org/zap"
 )
@@ -84,3 +85,17 @@ func GinTracingMiddleware() gin.HandlerFunc {
 		)
 	})
 }
+
+// RequestInfoMiddleware exposes the request method, path and response status
+// through the request context for layers that don't see the gin context
+func RequestInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := &models.RequestInfo{
+			Method: c.Request.Method,
+			Path:   c.Request.URL.Path,
+			Status: c.Writer.Status,
+		}
+		c.Request = c.Request.WithContext(models.WithRequestInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/metrics"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying a client-generated idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from a stored idempotency key
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	// ErrCodeIdempotencyKeyReused is returned when a key is sent again with a different request
+	ErrCodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	// ErrCodeIdempotencyKeyInProgress is returned while the first request with a key is still running
+	ErrCodeIdempotencyKeyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodyBytes bounds the request body buffered to hash it; the CSV import
+	// (2 MiB) is the largest body a client may send with a key
+	maxIdempotentBodyBytes = 4 << 20
+	// idempotencyClaimTTL frees the key of a request that never completed, e.g. after a crash
+	idempotencyClaimTTL = 5 * time.Minute
+
+	// idempotencyExemptPrefix covers login, refresh and password endpoints, whose
+	// responses carry tokens and must never be stored
+	idempotencyExemptPrefix   = "/api/v1/auth/"
+	noStoreIdempotentResponse = "idempotency_no_store"
+)
+
+// NoStoreIdempotentResponse marks the response of the current request as not storable,
+// for handlers returning secrets (webhook secrets, 2FA setup, backup codes). A retry with
+// the same Idempotency-Key runs the handler again instead of replaying the secret.
+func NoStoreIdempotentResponse(c *gin.Context) {
+	c.Set(noStoreIdempotentResponse, true)
+}
+
+// Idempotency makes POST, PUT, PATCH and DELETE requests sent with an Idempotency-Key
+// header safe to retry: the first successful response is stored for ttl and replayed for
+// the same key, without running the handler again. The key is claimed before the handler
+// runs, so a concurrent request with the same key gets 409 instead of running twice.
+// Keys are scoped to the credentials that sent them (or the client IP for anonymous
+// requests), and a key reused for a different method, path or body is rejected with 422.
+// Requests without the header, failed responses, /api/v1/auth/ responses and responses
+// marked with NoStoreIdempotentResponse are not stored.
+func Idempotency(store repository.IdempotencyRepositoryInterface, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isIdempotentWrite(c.Request.Method) || strings.HasPrefix(c.Request.URL.Path, idempotencyExemptPrefix) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.BadRequestResponse(c, "Idempotency-Key must be at most 255 characters")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			utils.BadRequestResponse(c, "Failed to read request body")
+			c.Abort()
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body too large for an Idempotency-Key", nil)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record := &models.IdempotencyRecord{
+			Scope:       idempotencyScope(c),
+			Key:         key,
+			RequestHash: hashRequest(c.Request.Method, c.Request.URL.RequestURI(), body),
+			ExpiresAt:   time.Now().Add(idempotencyClaimTTL),
+		}
+
+		claimed, err := store.Claim(ctx, record)
+		if err != nil {
+			// Without the store the request still runs, it just isn't protected against retries
+			logger.Error("Failed to claim idempotency key", zap.Error(err))
+			c.Next()
+			return
+		}
+		if !claimed {
+			replayIdempotentResponse(c, store, record)
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The handler has run; record its outcome even if the client went away
+		ctx = context.WithoutCancel(ctx)
+		status := recorder.Status()
+		if status < 200 || status >= 300 || c.GetBool(noStoreIdempotentResponse) {
+			if err := store.Release(ctx, record.Scope, record.Key); err != nil {
+				logger.Error("Failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		record.StatusCode = status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.Bytes()
+		record.ExpiresAt = time.Now().Add(ttl)
+		if err := store.Complete(ctx, record); err != nil {
+			logger.Error("Failed to store idempotency key", zap.Error(err))
+		}
+	}
+}
+
+// replayIdempotentResponse answers a request whose key is already held: with the stored
+// response when the first request completed, or with an error when it is still running or
+// the key was used for a different request
+func replayIdempotentResponse(c *gin.Context, store repository.IdempotencyRepositoryInterface, request *models.IdempotencyRecord) {
+	defer c.Abort()
+
+	stored, err := store.Get(c.Request.Context(), request.Scope, request.Key)
+	if err != nil {
+		logger.Error("Failed to look up idempotency key", zap.Error(err))
+		utils.InternalServerErrorResponse(c, "Failed to look up Idempotency-Key")
+		return
+	}
+
+	switch {
+	case stored != nil && stored.RequestHash != request.RequestHash:
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, "Unprocessable Entity", gin.H{
+			"code":    ErrCodeIdempotencyKeyReused,
+			"message": "Idempotency-Key was already used for a different request",
+		})
+	case stored == nil || stored.Pending():
+		// stored is nil when the first request failed and released the key in between
+		utils.ErrorResponse(c, http.StatusConflict, "Conflict", gin.H{
+			"code":    ErrCodeIdempotencyKeyInProgress,
+			"message": "A request with this Idempotency-Key is still being processed",
+		})
+	default:
+		metrics.IdempotentReplaysTotal.WithLabelValues(c.Request.Method).Inc()
+		c.Header(IdempotentReplayHeader, "true")
+		c.Data(stored.StatusCode, stored.ContentType, stored.ResponseBody)
+	}
+}
+
+func isIdempotentWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// idempotencyScope identifies who sent a key without storing their credentials
+func idempotencyScope(c *gin.Context) string {
+	credential := c.GetHeader("Authorization")
+	if credential == "" {
+		credential, _ = c.Cookie(AccessTokenCookie)
+	}
+	if credential == "" {
+		return "ip:" + c.ClientIP()
+	}
+	sum := sha256.Sum256([]byte(credential))
+	return "auth:" + hex.EncodeToString(sum[:16])
+}
+
+func hashRequest(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyRecorder keeps a copy of the response body so it can be stored
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
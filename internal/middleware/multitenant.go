@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
@@ -17,7 +19,7 @@ func RequireMasterRole() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -37,7 +39,7 @@ func RequireCompanyAccess() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -50,7 +52,7 @@ func RequireCompanyAccess() gin.HandlerFunc {
 		}
 
 		// Store company ID in context for easy access
-		c.Set("companyID", *userCtx.CompanyID)
+		SetCompanyID(c, *userCtx.CompanyID)
 		c.Next()
 	}
 }
@@ -59,7 +61,7 @@ func RequireCompanyAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -81,7 +83,7 @@ func RequireDriverOrHelper() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -103,7 +105,7 @@ func RequireVehicleAccess() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -158,7 +160,7 @@ func (m *MultiTenantMiddleware) RequireCompanyAccess() gin.HandlerFunc {
 		// Get user context from previous middleware
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -193,7 +195,7 @@ func (m *MultiTenantMiddleware) RequireCompanyAccess() gin.HandlerFunc {
 			}
 
 			// Add company ID to context for handlers to use
-			c.Set("companyID", companyID)
+			SetCompanyID(c, companyID)
 			span.SetAttributes(attribute.String("company.id", companyID.String()))
 		}
 
@@ -210,7 +212,7 @@ func (m *MultiTenantMiddleware) RequireMasterRole() gin.HandlerFunc {
 
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -243,7 +245,7 @@ func (m *MultiTenantMiddleware) RequireCompanyAdmin() gin.HandlerFunc {
 
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -277,7 +279,7 @@ func (m *MultiTenantMiddleware) InjectUserContext() gin.HandlerFunc {
 		// Get user ID from existing auth middleware
 		userIDInterface, exists := c.Get("userID")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User ID not found in context")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -329,7 +331,7 @@ func (m *MultiTenantMiddleware) RequireCompanyScope() gin.HandlerFunc {
 
 		userContext, exists := c.Get("userContext")
 		if !exists {
-			utils.UnauthorizedResponse(c, "User context not found")
+			utils.AuthContextMissingResponse(c)
 			c.Abort()
 			return
 		}
@@ -351,7 +353,7 @@ func (m *MultiTenantMiddleware) RequireCompanyScope() gin.HandlerFunc {
 		}
 
 		// Add user's company ID as the scope for all operations
-		c.Set("scopeCompanyID", *userCtx.CompanyID)
+		c.Set(ScopeCompanyIDKey, *userCtx.CompanyID)
 
 		span.SetAttributes(
 			attribute.String("user.id", userCtx.UserID.String()),
@@ -362,20 +364,35 @@ func (m *MultiTenantMiddleware) RequireCompanyScope() gin.HandlerFunc {
 	}
 }
 
-// GetCompanyIDFromContext retrieves company ID from context with fallback logic
+// Context keys holding the company a request is scoped to. Both always hold a uuid.UUID;
+// use SetCompanyID and GetCompanyIDFromContext instead of reading or writing them directly.
+const (
+	// CompanyIDKey is the company selected for the request (URL parameter or the user's company)
+	CompanyIDKey = "companyID"
+	// ScopeCompanyIDKey is the user's own company, set by CompanyScopeMiddleware
+	ScopeCompanyIDKey = "scopeCompanyID"
+
+	// legacyCompanyIDKey was once set as a string; it is rejected to surface stale callers
+	legacyCompanyIDKey = "company_id"
+)
+
+// SetCompanyID stores the request's company under the canonical key and type
+func SetCompanyID(c *gin.Context, companyID uuid.UUID) {
+	c.Set(CompanyIDKey, companyID)
+}
+
+// GetCompanyIDFromContext retrieves company ID from context with fallback logic: the
+// request's company, then the user's scope, then the user context. It returns nil, nil
+// when no company is set and an error when a key holds something other than a uuid.UUID.
 func GetCompanyIDFromContext(c *gin.Context) (*uuid.UUID, error) {
 	// First try to get from URL parameter
-	if companyIDInterface, exists := c.Get("companyID"); exists {
-		if companyID, ok := companyIDInterface.(uuid.UUID); ok {
-			return &companyID, nil
-		}
+	if companyID, exists, err := companyIDFromKey(c, CompanyIDKey); err != nil || exists {
+		return companyID, err
 	}
 
 	// Then try to get from scope (user's company)
-	if scopeCompanyIDInterface, exists := c.Get("scopeCompanyID"); exists {
-		if scopeCompanyID, ok := scopeCompanyIDInterface.(uuid.UUID); ok {
-			return &scopeCompanyID, nil
-		}
+	if companyID, exists, err := companyIDFromKey(c, ScopeCompanyIDKey); err != nil || exists {
+		return companyID, err
 	}
 
 	// Finally try to get from user context
@@ -385,9 +402,27 @@ func GetCompanyIDFromContext(c *gin.Context) (*uuid.UUID, error) {
 		}
 	}
 
+	if value, exists := c.Get(legacyCompanyIDKey); exists {
+		return nil, fmt.Errorf("company ID set under legacy key %q (%T); use middleware.SetCompanyID", legacyCompanyIDKey, value)
+	}
+
 	return nil, nil
 }
 
+// companyIDFromKey reads a company key, failing when it doesn't hold a uuid.UUID
+func companyIDFromKey(c *gin.Context, key string) (*uuid.UUID, bool, error) {
+	value, exists := c.Get(key)
+	if !exists {
+		return nil, false, nil
+	}
+
+	companyID, ok := value.(uuid.UUID)
+	if !ok {
+		return nil, true, fmt.Errorf("company context key %q holds %T, want uuid.UUID", key, value)
+	}
+	return &companyID, true, nil
+}
+
 // ExtractUserContext is a helper to get user context from gin context
 func ExtractUserContext(c *gin.Context) (*models.UserContext, bool) {
 	userContext, exists := c.Get("userContext")
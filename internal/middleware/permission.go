@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// PermissionChecker reports whether a role has been granted a permission
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role, permission string) bool
+}
+
+// SetPermissionChecker sets the role -> permissions policy used by RequirePermission and
+// RequireSelfOrPermission. Without one every permission check is denied.
+func (m *GinAuthMiddleware) SetPermissionChecker(checker PermissionChecker) {
+	m.permissions = checker
+}
+
+// RequirePermission middleware ensures the user's role has been granted permission
+func (m *GinAuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role_name")
+		if !exists {
+			utils.AuthContextMissingResponse(c)
+			c.Abort()
+			return
+		}
+
+		if !m.hasPermission(c, role.(string), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission middleware lets users act on themselves, identified by the
+// route parameter param, and requires permission to act on anyone else. Invalid IDs are
+// passed through for the handler to reject.
+func (m *GinAuthMiddleware) RequireSelfOrPermission(param, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		role, roleExists := c.Get("role_name")
+		if !exists || !roleExists {
+			utils.AuthContextMissingResponse(c)
+			c.Abort()
+			return
+		}
+
+		targetID, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		currentID, err := uuid.Parse(userID.(string))
+		if err == nil && currentID == targetID {
+			c.Next()
+			return
+		}
+
+		if !m.hasPermission(c, role.(string), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasPermission checks permission against the configured policy, denying when there is none
+func (m *GinAuthMiddleware) hasPermission(c *gin.Context, role, permission string) bool {
+	if m.permissions == nil {
+		return false
+	}
+	return m.permissions.HasPermission(c.Request.Context(), role, permission)
+}
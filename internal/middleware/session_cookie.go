@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// AccessTokenCookie carries the access token for cookie-based sessions (HttpOnly)
+	AccessTokenCookie = "dashtrack_access_token"
+	// RefreshTokenCookie carries the refresh token, sent only to the refresh endpoint (HttpOnly)
+	RefreshTokenCookie = "dashtrack_refresh_token"
+	// CSRFTokenCookie carries the double-submit CSRF token; readable by the web client
+	CSRFTokenCookie = "dashtrack_csrf_token"
+	// CSRFTokenHeader must echo the CSRF cookie on state-changing cookie-authenticated requests
+	CSRFTokenHeader = "X-CSRF-Token"
+
+	refreshTokenCookiePath = "/api/v1/auth/refresh"
+)
+
+// SessionCookies delivers session tokens as cookies to browser clients and enforces the
+// double-submit CSRF check on the requests they authenticate
+type SessionCookies struct {
+	domain   string
+	secure   bool
+	sameSite http.SameSite
+}
+
+// NewSessionCookies creates the cookie settings. sameSite is "strict", "lax" or "none";
+// anything else falls back to strict.
+func NewSessionCookies(domain string, secure bool, sameSite string) *SessionCookies {
+	mode := http.SameSiteStrictMode
+	switch strings.ToLower(sameSite) {
+	case "lax":
+		mode = http.SameSiteLaxMode
+	case "none":
+		mode = http.SameSiteNoneMode
+	}
+
+	return &SessionCookies{
+		domain:   domain,
+		secure:   secure,
+		sameSite: mode,
+	}
+}
+
+// SetTokens sets the access and refresh token cookies along with a fresh CSRF token
+func (s *SessionCookies) SetTokens(c *gin.Context, accessToken, refreshToken string, accessExpiresIn, refreshExpiresIn time.Duration) error {
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	s.setCookie(c, AccessTokenCookie, accessToken, "/", int(accessExpiresIn.Seconds()), true)
+	s.setCookie(c, RefreshTokenCookie, refreshToken, refreshTokenCookiePath, int(refreshExpiresIn.Seconds()), true)
+	s.setCookie(c, CSRFTokenCookie, csrfToken, "/", int(refreshExpiresIn.Seconds()), false)
+	return nil
+}
+
+// Clear expires all session cookies
+func (s *SessionCookies) Clear(c *gin.Context) {
+	s.setCookie(c, AccessTokenCookie, "", "/", -1, true)
+	s.setCookie(c, RefreshTokenCookie, "", refreshTokenCookiePath, -1, true)
+	s.setCookie(c, CSRFTokenCookie, "", "/", -1, false)
+}
+
+// AccessToken returns the access token cookie of the request, or "" when absent
+func (s *SessionCookies) AccessToken(c *gin.Context) string {
+	token, _ := c.Cookie(AccessTokenCookie)
+	return token
+}
+
+// RefreshToken returns the refresh token cookie of the request, or "" when absent
+func (s *SessionCookies) RefreshToken(c *gin.Context) string {
+	token, _ := c.Cookie(RefreshTokenCookie)
+	return token
+}
+
+// CSRFProtection rejects state-changing requests authenticated by a session cookie unless
+// the X-CSRF-Token header matches the CSRF cookie. Requests using the Authorization header,
+// or carrying no session cookie, are not affected.
+func (s *SessionCookies) CSRFProtection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if c.GetHeader("Authorization") != "" || (s.AccessToken(c) == "" && s.RefreshToken(c) == "") {
+			c.Next()
+			return
+		}
+
+		cookieToken, _ := c.Cookie(CSRFTokenCookie)
+		headerToken := c.GetHeader(CSRFTokenHeader)
+		if cookieToken == "" || subtle.ConstantTimeCompare([]byte(cookieToken), []byte(headerToken)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "CSRF token missing or invalid"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (s *SessionCookies) setCookie(c *gin.Context, name, value, path string, maxAge int, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   s.domain,
+		MaxAge:   maxAge,
+		Secure:   s.secure,
+		HttpOnly: httpOnly,
+		SameSite: s.sameSite,
+	})
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CORSMiddleware answers cross-origin requests from the allowed origins. Credentials are
+// allowed so browser clients can send session cookies; the origin is echoed rather than
+// using a wildcard, which browsers reject together with credentials.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || !allowed[origin] {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Allow-Credentials", "true")
+		header.Add("Vary", "Origin")
+
+		if c.Request.Method == http.MethodOptions {
+			header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, "+CSRFTokenHeader)
+			header.Set("Access-Control-Max-Age", "600")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
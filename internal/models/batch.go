@@ -0,0 +1,81 @@
+package models
+
+import "sort"
+
+// Outcomes of a single batch item
+const (
+	BatchItemSucceeded = "succeeded"
+	BatchItemFailed    = "failed"
+	BatchItemSkipped   = "skipped"
+)
+
+// BatchItemResult is the outcome of one item of a batch request, identified by its
+// position in the request
+type BatchItemResult struct {
+	Index  int         `json:"index"`
+	ID     string      `json:"id,omitempty"`
+	Status string      `json:"status"`
+	Error  string      `json:"error,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// BatchSummary counts the items of a batch request by outcome
+type BatchSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// BatchResult reports a batch request item by item, so one bad item doesn't hide
+// what happened to the others
+type BatchResult struct {
+	Results []BatchItemResult `json:"results"`
+	Summary BatchSummary      `json:"summary"`
+}
+
+// NewBatchResult creates an empty result for a batch of size items
+func NewBatchResult(size int) *BatchResult {
+	return &BatchResult{Results: make([]BatchItemResult, 0, size)}
+}
+
+// Succeed records a processed item
+func (r *BatchResult) Succeed(index int, id string, data interface{}) {
+	r.add(BatchItemResult{Index: index, ID: id, Status: BatchItemSucceeded, Data: data})
+}
+
+// Fail records an item that could not be processed
+func (r *BatchResult) Fail(index int, id string, reason string) {
+	r.add(BatchItemResult{Index: index, ID: id, Status: BatchItemFailed, Error: reason})
+}
+
+// Skip records an item left alone on purpose, e.g. because it was already applied
+func (r *BatchResult) Skip(index int, id string, reason string) {
+	r.add(BatchItemResult{Index: index, ID: id, Status: BatchItemSkipped, Error: reason})
+}
+
+// AllSucceeded reports whether every item of the batch was processed
+func (r *BatchResult) AllSucceeded() bool {
+	return r.Summary.Succeeded == r.Summary.Total
+}
+
+// SortByIndex orders the results as the items of the request, for batches whose items
+// are not all resolved in request order
+func (r *BatchResult) SortByIndex() {
+	sort.SliceStable(r.Results, func(i, j int) bool {
+		return r.Results[i].Index < r.Results[j].Index
+	})
+}
+
+func (r *BatchResult) add(item BatchItemResult) {
+	r.Results = append(r.Results, item)
+	r.Summary.Total++
+	switch item.Status {
+	case BatchItemSucceeded:
+		r.Summary.Succeeded++
+	case BatchItemFailed:
+		r.Summary.Failed++
+	case BatchItemSkipped:
+		r.Summary.Skipped++
+	}
+}
@@ -36,6 +36,7 @@ type Team struct {
 	Status      string     `json:"status" db:"status"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 
 	// Populated fields (not in DB)
 	Manager *User        `json:"manager,omitempty"`
@@ -43,19 +44,39 @@ type Team struct {
 	Company *Company     `json:"company,omitempty"`
 }
 
+// Team lifecycle states. Archived teams are hidden from default lists but stay readable
+// and can be restored; deleted is the terminal soft delete, marked by deleted_at rather
+// than the status column.
+const (
+	TeamStatusActive   = "active"
+	TeamStatusArchived = "archived"
+	TeamStatusDeleted  = "deleted"
+)
+
+// TeamRoleManager is the role_in_team of a team's manager, who is kept as a member
+const TeamRoleManager = "manager"
+
 // TeamMember represents the many-to-many relationship between teams and users
 type TeamMember struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	TeamID     uuid.UUID `json:"team_id" db:"team_id"`
-	UserID     uuid.UUID `json:"user_id" db:"user_id"`
-	RoleInTeam string    `json:"role_in_team" db:"role_in_team"`
-	JoinedAt   time.Time `json:"joined_at" db:"joined_at"`
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TeamID     uuid.UUID  `json:"team_id" db:"team_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	RoleInTeam string     `json:"role_in_team" db:"role_in_team"`
+	JoinedAt   time.Time  `json:"joined_at" db:"joined_at"`
+	LeftAt     *time.Time `json:"left_at,omitempty" db:"left_at"`
 
 	// Populated fields
 	User *User `json:"user,omitempty"`
 	Team *Team `json:"team,omitempty"`
 }
 
+// UserTeam is a team as seen by one of its members, with that member's role in it
+type UserTeam struct {
+	Team
+	RoleInTeam string    `json:"role_in_team" db:"role_in_team"`
+	JoinedAt   time.Time `json:"joined_at" db:"joined_at"`
+}
+
 // TeamMemberHistory tracks changes to team memberships
 type TeamMemberHistory struct {
 	ID                 uuid.UUID  `json:"id" db:"id"`
@@ -80,6 +101,13 @@ type TeamMemberHistory struct {
 	ChangedByUser *User `json:"changed_by_user,omitempty"`
 }
 
+// TeamGrowthBucket aggregates team membership changes over a time bucket
+type TeamGrowthBucket struct {
+	Bucket  time.Time `json:"bucket" db:"bucket"`
+	Added   int       `json:"added" db:"added"`
+	Removed int       `json:"removed" db:"removed"`
+}
+
 // Vehicle represents a company vehicle with IoT sensors
 type Vehicle struct {
 	ID            uuid.UUID  `json:"id" db:"id"`
@@ -108,6 +136,21 @@ type Vehicle struct {
 	ESP32Devices []ESP32Device `json:"esp32_devices,omitempty"`
 }
 
+// AssignmentRef names a user or team a vehicle is assigned to
+type AssignmentRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// VehicleAssignment is the current crew and team of a vehicle; unassigned slots are null
+type VehicleAssignment struct {
+	VehicleID    uuid.UUID      `json:"vehicle_id"`
+	LicensePlate string         `json:"license_plate"`
+	Driver       *AssignmentRef `json:"driver"`
+	Helper       *AssignmentRef `json:"helper"`
+	Team         *AssignmentRef `json:"team"`
+}
+
 // VehicleAssignmentHistory tracks changes to vehicle assignments
 type VehicleAssignmentHistory struct {
 	ID               uuid.UUID  `json:"id" db:"id"`
@@ -190,6 +233,31 @@ type VehicleTrip struct {
 	Helper  *User    `json:"helper,omitempty"`
 }
 
+// ActiveTrip is a trip in progress together with a summary of the vehicle
+// and driver, used by the live operations view
+type ActiveTrip struct {
+	Trip    VehicleTrip       `json:"trip"`
+	Vehicle ActiveTripVehicle `json:"vehicle"`
+	Driver  *ActiveTripDriver `json:"driver"`
+}
+
+// ActiveTripVehicle summarizes the vehicle running an active trip
+type ActiveTripVehicle struct {
+	ID           uuid.UUID  `json:"id"`
+	LicensePlate string     `json:"license_plate"`
+	Brand        string     `json:"brand"`
+	Model        string     `json:"model"`
+	VehicleType  string     `json:"vehicle_type"`
+	TeamID       *uuid.UUID `json:"team_id"`
+}
+
+// ActiveTripDriver summarizes the driver of an active trip
+type ActiveTripDriver struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Phone *string   `json:"phone"`
+}
+
 // CompanySetting represents per-company configuration
 type CompanySetting struct {
 	ID           uuid.UUID `json:"id" db:"id"`
@@ -228,12 +296,28 @@ type UpdateTeamRequest struct {
 	ManagerID   *uuid.UUID `json:"manager_id"`
 }
 
+// ChangeTeamManagerRequest represents request to hand a team over to a new manager
+type ChangeTeamManagerRequest struct {
+	ManagerID uuid.UUID `json:"manager_id" binding:"required"`
+}
+
 // TransferTeamMemberRequest represents request to transfer a member to another team
 type TransferTeamMemberRequest struct {
 	FromTeamID uuid.UUID `json:"from_team_id" binding:"required"`
 	RoleInTeam string    `json:"role_in_team" binding:"required,oneof=manager driver assistant supervisor helper team_lead"`
 }
 
+// VehicleTypes lists the vehicle types accepted on create
+var VehicleTypes = []string{"truck", "van", "car", "motorcycle", "bus"}
+
+// VehicleFilter narrows a company's vehicle list; zero fields don't filter
+type VehicleFilter struct {
+	Status      string
+	VehicleType string
+	// Unassigned keeps vehicles missing a driver or a team
+	Unassigned bool
+}
+
 // CreateVehicleRequest represents request to create a new vehicle
 type CreateVehicleRequest struct {
 	TeamID        *uuid.UUID `json:"team_id"`
@@ -262,11 +346,20 @@ type CreateESP32DeviceRequest struct {
 	InstallationDate *time.Time `json:"installation_date"`
 }
 
+// UpdateVehicleRequest represents request to update a vehicle. Status is optional and
+// must be a transition allowed by Vehicle.CanTransitionTo.
+type UpdateVehicleRequest struct {
+	CreateVehicleRequest
+	Status *string `json:"status"`
+}
+
 // UpdateVehicleAssignmentRequest represents request to assign/unassign users to vehicle
 type UpdateVehicleAssignmentRequest struct {
 	DriverID *uuid.UUID `json:"driver_id"`
 	HelperID *uuid.UUID `json:"helper_id"`
 	TeamID   *uuid.UUID `json:"team_id"`
+	// Reason explains the change and is kept in the vehicle's assignment history
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
 }
 
 // VehicleDashboardData represents real-time dashboard data for a vehicle
@@ -314,3 +407,64 @@ type CompanyStats struct {
 	ESP32DevicesOnline  int     `json:"esp32_devices_online"`
 	ESP32DevicesOffline int     `json:"esp32_devices_offline"`
 }
+
+// TeamStats represents member and vehicle counts for a team
+type TeamStats struct {
+	TeamID         uuid.UUID  `json:"team_id"`
+	TeamName       string     `json:"team_name"`
+	MemberCount    int        `json:"member_count"`
+	VehicleCount   int        `json:"vehicle_count"`
+	ActiveVehicles int        `json:"active_vehicles"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	ManagerID      *uuid.UUID `json:"manager_id"`
+}
+
+// ManagedTeam is one team of a manager's dashboard with its current members and vehicles
+type ManagedTeam struct {
+	Team           Team         `json:"team"`
+	Members        []TeamMember `json:"members"`
+	Vehicles       []Vehicle    `json:"vehicles"`
+	ActiveVehicles int          `json:"active_vehicles"`
+}
+
+// ManagerDashboard aggregates the teams a manager manages; users in several of those
+// teams are counted once
+type ManagerDashboard struct {
+	Teams          []ManagedTeam `json:"teams"`
+	TeamCount      int           `json:"team_count"`
+	MemberCount    int           `json:"member_count"`
+	VehicleCount   int           `json:"vehicle_count"`
+	ActiveVehicles int           `json:"active_vehicles"`
+}
+
+// VehicleStats represents vehicle counts by status for a company
+type VehicleStats struct {
+	TotalVehicles int `json:"total_vehicles"`
+	Active        int `json:"active"`
+	Inactive      int `json:"inactive"`
+	Maintenance   int `json:"maintenance"`
+}
+
+// ESP32DeviceStats represents device counts and averages for a company
+type ESP32DeviceStats struct {
+	TotalDevices      int      `json:"total_devices" db:"total_devices"`
+	OnlineDevices     int      `json:"online_devices" db:"online_devices"`
+	OfflineDevices    int      `json:"offline_devices" db:"offline_devices"`
+	InactiveDevices   int      `json:"inactive_devices" db:"inactive_devices"`
+	AvgBatteryLevel   *float64 `json:"avg_battery_level" db:"avg_battery_level"`
+	AvgSignalStrength *float64 `json:"avg_signal_strength" db:"avg_signal_strength"`
+}
+
+// CompanyDependents counts the active records that still belong to a company
+type CompanyDependents struct {
+	ActiveUsers    int `json:"active_users" db:"active_users"`
+	ActiveVehicles int `json:"active_vehicles" db:"active_vehicles"`
+	ActiveTeams    int `json:"active_teams" db:"active_teams"`
+}
+
+// HasAny reports whether the company still has any active dependent
+func (d CompanyDependents) HasAny() bool {
+	return d.ActiveUsers > 0 || d.ActiveVehicles > 0 || d.ActiveTeams > 0
+}
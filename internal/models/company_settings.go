@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxSessionsPerUser is how many concurrent sessions a user keeps when the company
+// doesn't set its own limit
+const DefaultMaxSessionsPerUser = 3
+
+// CompanySettings holds the tenant-level options of a company. It is stored as a JSON
+// document; keys that are not set take the values of DefaultCompanySettings.
+type CompanySettings struct {
+	// MaxSessionsPerUser is how many concurrent sessions each user keeps; logging in
+	// beyond it revokes the oldest ones
+	MaxSessionsPerUser int `json:"max_sessions_per_user" binding:"min=1,max=20"`
+	// EnforceIPAllowlist restricts logins to the company's IP allowlist, when it has entries
+	EnforceIPAllowlist bool `json:"enforce_ip_allowlist"`
+	// PasswordMaxAgeDays overrides PASSWORD_MAX_AGE_DAYS; nil uses the global setting and
+	// 0 disables password expiry
+	PasswordMaxAgeDays *int `json:"password_max_age_days" binding:"omitempty,min=0"`
+	// RequireAssignmentReason rejects vehicle driver/helper changes that don't give a reason
+	RequireAssignmentReason bool `json:"require_assignment_reason"`
+}
+
+// DefaultCompanySettings returns the settings of a company that hasn't changed any
+func DefaultCompanySettings() CompanySettings {
+	return CompanySettings{
+		MaxSessionsPerUser: DefaultMaxSessionsPerUser,
+		EnforceIPAllowlist: true,
+	}
+}
+
+// CompanySettingsRecord is a company's settings with who last changed them
+type CompanySettingsRecord struct {
+	CompanyID uuid.UUID       `json:"company_id"`
+	Settings  CompanySettings `json:"settings"`
+	UpdatedBy *uuid.UUID      `json:"updated_by"`
+	// UpdatedAt is nil while the company still uses the defaults
+	UpdatedAt *time.Time `json:"updated_at"`
+}
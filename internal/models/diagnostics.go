@@ -0,0 +1,14 @@
+package models
+
+// TestEmailRequest represents request to send a sample email through the SMTP pipeline
+type TestEmailRequest struct {
+	To string `json:"to" binding:"required,email"`
+}
+
+// TestEmailResult reports the outcome of a test email
+type TestEmailResult struct {
+	Sent       bool   `json:"sent"`
+	To         string `json:"to"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FuelLog is a fuel purchase for a vehicle
+type FuelLog struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	VehicleID  uuid.UUID  `json:"vehicle_id" db:"vehicle_id"`
+	Liters     float64    `json:"liters" db:"liters"`
+	Cost       float64    `json:"cost" db:"cost"`
+	OdometerKm int        `json:"odometer_km" db:"odometer_km"`
+	FilledAt   time.Time  `json:"filled_at" db:"filled_at"`
+	Station    *string    `json:"station" db:"station"`
+	Notes      *string    `json:"notes" db:"notes"`
+	CreatedBy  *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// OdometerReading is an odometer value recorded for a vehicle
+type OdometerReading struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	VehicleID  uuid.UUID  `json:"vehicle_id" db:"vehicle_id"`
+	OdometerKm int        `json:"odometer_km" db:"odometer_km"`
+	RecordedAt time.Time  `json:"recorded_at" db:"recorded_at"`
+	Source     string     `json:"source" db:"source"`
+	CreatedBy  *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// FuelEfficiency summarizes the fuel logs of a vehicle over a period. The fuel of the
+// first fill in the period was burned before it, so it only counts towards TotalLiters.
+type FuelEfficiency struct {
+	VehicleID      uuid.UUID `json:"vehicle_id" db:"-"`
+	From           time.Time `json:"from" db:"-"`
+	To             time.Time `json:"to" db:"-"`
+	FillCount      int       `json:"fill_count" db:"fill_count"`
+	DistanceKm     int       `json:"distance_km" db:"distance_km"`
+	LitersConsumed float64   `json:"liters_consumed" db:"liters_consumed"`
+	TotalLiters    float64   `json:"total_liters" db:"total_liters"`
+	TotalCost      float64   `json:"total_cost" db:"total_cost"`
+	KmPerLiter     *float64  `json:"km_per_liter" db:"-"`
+	CostPerKm      *float64  `json:"cost_per_km" db:"-"`
+}
+
+// CreateFuelLogRequest represents request to log a fuel purchase
+type CreateFuelLogRequest struct {
+	Liters     float64   `json:"liters" binding:"required,gt=0"`
+	Cost       float64   `json:"cost" binding:"min=0"`
+	OdometerKm *int      `json:"odometer_km" binding:"required,min=0"`
+	FilledAt   time.Time `json:"filled_at" binding:"required"`
+	Station    *string   `json:"station" binding:"omitempty,max=255"`
+	Notes      *string   `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// CreateOdometerReadingRequest represents request to record an odometer reading
+type CreateOdometerReadingRequest struct {
+	OdometerKm *int       `json:"odometer_km" binding:"required,min=0"`
+	RecordedAt *time.Time `json:"recorded_at"`
+}
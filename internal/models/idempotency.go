@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the stored response of a write request sent with an Idempotency-Key.
+// Until the request completes, the record is a claim on the key with no status code.
+type IdempotencyRecord struct {
+	Scope        string    `db:"scope"`
+	Key          string    `db:"idempotency_key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   int       `db:"status_code"`
+	ContentType  string    `db:"content_type"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+// Pending reports whether the request holding the key is still running
+func (r *IdempotencyRecord) Pending() bool {
+	return r.StatusCode == 0
+}
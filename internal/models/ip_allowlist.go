@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IPAllowlistEntry is an address range the users of a company may log in from
+type IPAllowlistEntry struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	CompanyID   uuid.UUID  `json:"company_id" db:"company_id"`
+	CIDR        string     `json:"cidr" db:"cidr"`
+	Description string     `json:"description" db:"description"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateIPAllowlistEntryRequest represents request to allow logins from an address range.
+// CIDR accepts IPv4 or IPv6 ranges, or a single address.
+type CreateIPAllowlistEntryRequest struct {
+	CIDR        string `json:"cidr" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=255"`
+}
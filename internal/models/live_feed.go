@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Live feed event types pushed to vehicle stream clients
+const (
+	LiveEventReading   = "reading"
+	LiveEventAlert     = "alert"
+	LiveEventHeartbeat = "heartbeat"
+)
+
+// LiveEvent is a single message of a vehicle's real-time feed
+type LiveEvent struct {
+	Type      string      `json:"type"`
+	VehicleID uuid.UUID   `json:"vehicle_id"`
+	Source    string      `json:"source,omitempty"` // sensor type, or "trip" for trip GPS points
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
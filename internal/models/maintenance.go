@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VehicleMaintenance is a service performed on a vehicle
+type VehicleMaintenance struct {
+	ID                    uuid.UUID  `json:"id" db:"id"`
+	VehicleID             uuid.UUID  `json:"vehicle_id" db:"vehicle_id"`
+	Type                  string     `json:"type" db:"type"`
+	PerformedAt           time.Time  `json:"performed_at" db:"performed_at"`
+	OdometerKm            *int       `json:"odometer_km" db:"odometer_km"`
+	Cost                  *float64   `json:"cost" db:"cost"`
+	Notes                 *string    `json:"notes" db:"notes"`
+	NextServiceDate       *time.Time `json:"next_service_date" db:"next_service_date"`
+	NextServiceOdometerKm *int       `json:"next_service_odometer_km" db:"next_service_odometer_km"`
+	CreatedBy             *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MaintenanceDue flags a vehicle whose next service of a type is approaching by date or odometer
+type MaintenanceDue struct {
+	VehicleID             uuid.UUID  `json:"vehicle_id" db:"vehicle_id"`
+	LicensePlate          string     `json:"license_plate" db:"license_plate"`
+	Type                  string     `json:"type" db:"type"`
+	LastPerformedAt       time.Time  `json:"last_performed_at" db:"last_performed_at"`
+	NextServiceDate       *time.Time `json:"next_service_date" db:"next_service_date"`
+	NextServiceOdometerKm *int       `json:"next_service_odometer_km" db:"next_service_odometer_km"`
+	CurrentOdometerKm     *int       `json:"current_odometer_km" db:"current_odometer_km"`
+	DaysRemaining         *int       `json:"days_remaining" db:"days_remaining"`
+	KmRemaining           *int       `json:"km_remaining" db:"km_remaining"`
+}
+
+// CreateMaintenanceRequest represents request to record a maintenance service
+type CreateMaintenanceRequest struct {
+	Type                  string     `json:"type" binding:"required,oneof=oil_change tire_rotation brake_service inspection battery filter_change other"`
+	PerformedAt           time.Time  `json:"performed_at" binding:"required"`
+	OdometerKm            *int       `json:"odometer_km" binding:"omitempty,min=0"`
+	Cost                  *float64   `json:"cost" binding:"omitempty,min=0"`
+	Notes                 *string    `json:"notes" binding:"omitempty,max=2000"`
+	NextServiceDate       *time.Time `json:"next_service_date"`
+	NextServiceOdometerKm *int       `json:"next_service_odometer_km" binding:"omitempty,min=0"`
+}
+
+// UpdateMaintenanceRequest represents request to update a maintenance record
+type UpdateMaintenanceRequest struct {
+	Type                  *string    `json:"type" binding:"omitempty,oneof=oil_change tire_rotation brake_service inspection battery filter_change other"`
+	PerformedAt           *time.Time `json:"performed_at"`
+	OdometerKm            *int       `json:"odometer_km" binding:"omitempty,min=0"`
+	Cost                  *float64   `json:"cost" binding:"omitempty,min=0"`
+	Notes                 *string    `json:"notes" binding:"omitempty,max=2000"`
+	NextServiceDate       *time.Time `json:"next_service_date"`
+	NextServiceOdometerKm *int       `json:"next_service_odometer_km" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Permissions granted to roles in the role_permissions table
+const (
+	// PermissionUserReadHistory allows reading the history and activities of other users
+	PermissionUserReadHistory = "user:read_history"
+)
+
+// RolePermission grants one permission to the role called RoleName
+type RolePermission struct {
+	RoleName   string `db:"role_name"`
+	Permission string `db:"permission"`
+}
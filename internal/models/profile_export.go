@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileExportTeamMembership is a team membership in a profile export
+type ProfileExportTeamMembership struct {
+	TeamID     uuid.UUID  `json:"team_id" db:"team_id"`
+	TeamName   string     `json:"team_name" db:"team_name"`
+	RoleInTeam string     `json:"role_in_team" db:"role_in_team"`
+	JoinedAt   time.Time  `json:"joined_at" db:"joined_at"`
+	LeftAt     *time.Time `json:"left_at,omitempty" db:"left_at"`
+}
+
+// ProfileExportVehicle is a vehicle the user is assigned to, as driver or helper
+type ProfileExportVehicle struct {
+	VehicleID    uuid.UUID `json:"vehicle_id" db:"vehicle_id"`
+	LicensePlate string    `json:"license_plate" db:"license_plate"`
+	Brand        string    `json:"brand" db:"brand"`
+	Model        string    `json:"model" db:"model"`
+	Year         int       `json:"year" db:"year"`
+	Assignment   string    `json:"assignment" db:"assignment"`
+}
+
+// ProfileExportAuthEntry is a login attempt in a profile export
+type ProfileExportAuthEntry struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	Success       bool      `json:"success" db:"success"`
+	IPAddress     *string   `json:"ip_address" db:"ip_address"`
+	UserAgent     *string   `json:"user_agent" db:"user_agent"`
+	FailureReason *string   `json:"failure_reason" db:"failure_reason"`
+	CountryCode   *string   `json:"country_code,omitempty" db:"country_code"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProfileExportAuditEntry is an audit log entry in a profile export
+type ProfileExportAuditEntry struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	Action     string          `json:"action" db:"action"`
+	Resource   string          `json:"resource" db:"resource"`
+	ResourceID *string         `json:"resource_id" db:"resource_id"`
+	IPAddress  *string         `json:"ip_address" db:"ip_address"`
+	UserAgent  *string         `json:"user_agent" db:"user_agent"`
+	Success    bool            `json:"success" db:"success"`
+	Metadata   json.RawMessage `json:"metadata" db:"metadata"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
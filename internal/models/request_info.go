@@ -0,0 +1,46 @@
+package models
+
+import "context"
+
+// RequestInfo describes the HTTP request an operation runs in, so lower layers
+// such as audit logging can record it without depending on the web framework
+type RequestInfo struct {
+	Method string
+	Path   string
+	// Status reports the response status code at the time it is called
+	Status func() int
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying the request info
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info stored in ctx, if any
+func RequestInfoFromContext(ctx context.Context) (*RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info, ok && info != nil
+}
+
+// FillRequestInfo populates the method, path and status code of an audit entry
+// from the request. Fields set by the caller are kept; a captured error status
+// (4xx/5xx) also marks the entry as unsuccessful.
+func (l *AuditLog) FillRequestInfo(info *RequestInfo) {
+	if l.Method == nil && info.Method != "" {
+		method := info.Method
+		l.Method = &method
+	}
+	if l.Path == nil && info.Path != "" {
+		path := info.Path
+		l.Path = &path
+	}
+	if l.StatusCode == nil && info.Status != nil {
+		status := info.Status()
+		l.StatusCode = &status
+		if status >= 400 {
+			l.Success = false
+		}
+	}
+}
@@ -43,8 +43,10 @@ type SessionToken struct {
 	UserAgent        string     `json:"user_agent" db:"user_agent"`
 	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
 	RefreshExpiresAt time.Time  `json:"refresh_expires_at" db:"refresh_expires_at"`
+	RefreshTTL       int64      `json:"-" db:"refresh_ttl_seconds"` // Refresh lifetime kept across refreshes, in seconds; 0 means the default
 	Revoked          bool       `json:"revoked" db:"revoked"`
 	RevokedAt        *time.Time `json:"revoked_at" db:"revoked_at"`
+	LastUsedAt       time.Time  `json:"last_used_at" db:"last_used_at"`
 	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 }
@@ -95,8 +97,10 @@ type AuditLogFilter struct {
 	Success    *bool      `json:"success"`
 	From       *time.Time `json:"from"`
 	To         *time.Time `json:"to"`
-	Limit      int        `json:"limit"`
-	Offset     int        `json:"offset"`
+	// Metadata matches entries whose metadata contains these key/value pairs (JSONB @>)
+	Metadata map[string]interface{} `json:"metadata"`
+	Limit    int                    `json:"limit"`
+	Offset   int                    `json:"offset"`
 }
 
 // AuditLogStats represents aggregated audit log statistics
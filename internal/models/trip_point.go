@@ -0,0 +1,66 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// earthRadiusKm is the mean Earth radius used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// TripPoint is a GPS position recorded during a trip
+type TripPoint struct {
+	ID         int64     `json:"id" db:"id"`
+	TripID     uuid.UUID `json:"trip_id" db:"trip_id"`
+	Latitude   float64   `json:"latitude" db:"latitude"`
+	Longitude  float64   `json:"longitude" db:"longitude"`
+	Speed      *float64  `json:"speed" db:"speed"`
+	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// TripPointInput is a single position in a CreateTripPointsRequest
+type TripPointInput struct {
+	Latitude   *float64  `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude  *float64  `json:"longitude" binding:"required,min=-180,max=180"`
+	Speed      *float64  `json:"speed" binding:"omitempty,min=0"`
+	RecordedAt time.Time `json:"recorded_at" binding:"required"`
+}
+
+// CreateTripPointsRequest represents a batch of positions streamed for a trip
+type CreateTripPointsRequest struct {
+	Points []TripPointInput `json:"points" binding:"required,min=1,dive"`
+}
+
+// EndTripRequest represents request to end an active trip. When DistanceKm is omitted
+// it is computed from the trip's recorded points.
+type EndTripRequest struct {
+	EndLocation     *string  `json:"end_location" binding:"omitempty,max=255"`
+	EndLatitude     *float64 `json:"end_latitude" binding:"omitempty,min=-90,max=90"`
+	EndLongitude    *float64 `json:"end_longitude" binding:"omitempty,min=-180,max=180"`
+	DistanceKm      *float64 `json:"distance_km" binding:"omitempty,min=0"`
+	FuelConsumption *float64 `json:"fuel_consumption" binding:"omitempty,min=0"`
+	Notes           *string  `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// TripPointsDistanceKm sums the great-circle distance between consecutive points,
+// which must be ordered by recorded_at
+func TripPointsDistanceKm(points []TripPoint) float64 {
+	var total float64
+	for i := 1; i < len(points); i++ {
+		total += haversineKm(points[i-1].Latitude, points[i-1].Longitude, points[i].Latitude, points[i].Longitude)
+	}
+	return total
+}
+
+// haversineKm returns the great-circle distance between two coordinates
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -8,11 +8,20 @@ import (
 
 // Role represents a user role in the system
 type Role struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID `json:"id" db:"id"`
+	Name            string    `json:"name" db:"name"`
+	Description     string    `json:"description" db:"description"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	PermissionCount *int      `json:"permission_count,omitempty" db:"-"` // Only set when requested on role listings
+}
+
+// RoleFilter represents the filters for listing roles
+type RoleFilter struct {
+	Search                 string // Case-insensitive match on role name
+	IncludePermissionCount bool
+	Limit                  int
+	Offset                 int
 }
 
 // User represents a user in the system
@@ -37,6 +46,19 @@ type User struct {
 	PasswordChangedAt time.Time  `json:"password_changed_at" db:"password_changed_at"`
 	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Activity, filled in for company admins and managers looking at their users
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty" db:"-"`
+	Online         *bool      `json:"online,omitempty" db:"-"`
+}
+
+// UserActivity is when a user last made an authenticated request
+type UserActivity struct {
+	UserID         uuid.UUID `db:"user_id"`
+	LastActivityAt time.Time `db:"last_activity_at"`
+	// ActiveSessionUsedAt is the last use of a session that is still valid, nil when the
+	// user has none
+	ActiveSessionUsedAt *time.Time `db:"active_session_used_at"`
 }
 
 // UserSession represents a user session
@@ -60,6 +82,7 @@ type AuthLog struct {
 	IPAddress     *string    `json:"ip_address" db:"ip_address"`
 	UserAgent     *string    `json:"user_agent" db:"user_agent"`
 	FailureReason *string    `json:"failure_reason" db:"failure_reason"`
+	CountryCode   *string    `json:"country_code,omitempty" db:"country_code"`
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 }
 
@@ -82,7 +105,7 @@ type CreateUserRequest struct {
 	Email     string  `json:"email" binding:"required,email,max=100"`
 	Password  string  `json:"password" binding:"required,min=8,max=255"`
 	Phone     string  `json:"phone" binding:"required,min=10,max=20"` // Obrigatório: telefone
-	CPF       string  `json:"cpf" binding:"required,len=14"`          // Obrigatório: CPF no formato XXX.XXX.XXX-XX
+	CPF       string  `json:"cpf" binding:"required,min=11,max=14"`   // Obrigatório: CPF com ou sem pontuação, salvo só com dígitos
 	RoleID    string  `json:"role_id" binding:"required,uuid"`
 	CompanyID *string `json:"company_id,omitempty" binding:"omitempty,uuid"` // For company users
 }
@@ -92,13 +115,75 @@ type UpdateUserRequest struct {
 	Name            string `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
 	Email           string `json:"email,omitempty" binding:"omitempty,email,max=100"`
 	Phone           string `json:"phone,omitempty" binding:"omitempty,max=20"`
-	CPF             string `json:"cpf,omitempty" binding:"omitempty,len=14"`
+	CPF             string `json:"cpf,omitempty" binding:"omitempty,min=11,max=14"`
 	Avatar          string `json:"avatar,omitempty" binding:"omitempty,max=255"`
 	Active          *bool  `json:"active,omitempty"`
 	DashboardConfig string `json:"dashboard_config,omitempty"`
 	RoleID          string `json:"role_id,omitempty" binding:"omitempty,uuid"`
 }
 
+// CheckEmailRequest is the query of an email availability check
+type CheckEmailRequest struct {
+	Email string `form:"email" binding:"required,email,max=100"`
+}
+
+// EmailAvailability tells whether a new user can be created with an email
+type EmailAvailability struct {
+	Available bool `json:"available"`
+}
+
+// RevokeSessionsRequest is the optional body of an admin-forced session revocation
+type RevokeSessionsRequest struct {
+	Deactivate bool   `json:"deactivate"`
+	Reason     string `json:"reason,omitempty" binding:"omitempty,max=255"`
+}
+
+// AccountDeletionResult describes an accepted account deletion request
+type AccountDeletionResult struct {
+	User               *User     `json:"-"`
+	RevokedSessions    int64     `json:"revoked_sessions"`
+	ErasureScheduledAt time.Time `json:"erasure_scheduled_at"`
+}
+
+// UserPurgeResult reports what was erased when a user was purged
+type UserPurgeResult struct {
+	UserID          uuid.UUID `json:"user_id"`
+	AuthLogs        int64     `json:"anonymized_auth_logs"`
+	AuditLogs       int64     `json:"anonymized_audit_logs"`
+	SessionsDeleted int64     `json:"sessions_deleted"`
+	Avatar          *string   `json:"-"` // Avatar URL the user had, whose stored file is deleted after the purge
+}
+
+// LoginAttempts is a user's failed login count after recording one more failure
+type LoginAttempts struct {
+	Attempts     int
+	BlockedUntil *time.Time
+	// NewlyBlocked is set when this failure blocked the account, as opposed to one
+	// recorded while it was already blocked
+	NewlyBlocked bool
+}
+
+// UserPreferences holds a user's notification toggles
+type UserPreferences struct {
+	NotifyNewSession        bool `json:"notify_new_session" db:"notify_new_session"`
+	NotifyAssignmentChanges bool `json:"notify_assignment_changes" db:"notify_assignment_changes"`
+}
+
+// UpdatePreferencesRequest represents a partial update of the user's preferences;
+// omitted fields keep their current value
+type UpdatePreferencesRequest struct {
+	NotifyNewSession        *bool `json:"notify_new_session"`
+	NotifyAssignmentChanges *bool `json:"notify_assignment_changes"`
+}
+
+// AssignmentRecipient is a user who may be emailed about vehicle assignment changes
+type AssignmentRecipient struct {
+	ID                      uuid.UUID `db:"id"`
+	Name                    string    `db:"name"`
+	Email                   string    `db:"email"`
+	NotifyAssignmentChanges bool      `db:"notify_assignment_changes"`
+}
+
 // TransferUserRequest represents the request to transfer a user to another company (Master only)
 type TransferUserRequest struct {
 	CompanyID string `json:"company_id" binding:"required,uuid"`
@@ -232,6 +317,6 @@ type CreateCompanyUserRequest struct {
 	Email    string `json:"email" binding:"required,email,max=100"`
 	Password string `json:"password" binding:"required,min=8,max=255"`
 	Phone    string `json:"phone,omitempty" binding:"omitempty,max=20"`
-	CPF      string `json:"cpf,omitempty" binding:"omitempty,len=14"`
+	CPF      string `json:"cpf,omitempty" binding:"omitempty,min=11,max=14"`
 	Role     string `json:"role" binding:"required,oneof=driver helper supervisor company_admin"`
 }
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VehicleDocument is an insurance policy, registration or other document of a vehicle
+type VehicleDocument struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	VehicleID uuid.UUID  `json:"vehicle_id" db:"vehicle_id"`
+	Type      string     `json:"type" db:"type"`
+	Number    string     `json:"number" db:"number"`
+	IssuedAt  *time.Time `json:"issued_at" db:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	Notes     *string    `json:"notes" db:"notes"`
+	CreatedBy *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ExpiringDocument is a vehicle document that lapses soon (or already has)
+type ExpiringDocument struct {
+	DocumentID    uuid.UUID `json:"document_id" db:"document_id"`
+	VehicleID     uuid.UUID `json:"vehicle_id" db:"vehicle_id"`
+	LicensePlate  string    `json:"license_plate" db:"license_plate"`
+	Type          string    `json:"type" db:"type"`
+	Number        string    `json:"number" db:"number"`
+	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
+	DaysRemaining int       `json:"days_remaining" db:"days_remaining"`
+}
+
+// CreateVehicleDocumentRequest represents request to register a vehicle document
+type CreateVehicleDocumentRequest struct {
+	Type      string     `json:"type" binding:"required,oneof=insurance registration inspection emission permit other"`
+	Number    string     `json:"number" binding:"required,max=100"`
+	IssuedAt  *time.Time `json:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at" binding:"required"`
+	Notes     *string    `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// UpdateVehicleDocumentRequest represents request to update a vehicle document
+type UpdateVehicleDocumentRequest struct {
+	Type      *string    `json:"type" binding:"omitempty,oneof=insurance registration inspection emission permit other"`
+	Number    *string    `json:"number" binding:"omitempty,min=1,max=100"`
+	IssuedAt  *time.Time `json:"issued_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Notes     *string    `json:"notes" binding:"omitempty,max=2000"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "github.com/google/uuid"
+
+// Vehicle import row statuses
+const (
+	VehicleImportCreated  = "created"
+	VehicleImportRejected = "rejected"
+)
+
+// VehicleImportRowResult reports what happened to one data row of an imported CSV
+type VehicleImportRowResult struct {
+	Row          int        `json:"row"` // line in the file, the header being line 1
+	LicensePlate string     `json:"license_plate"`
+	Status       string     `json:"status"`
+	VehicleID    *uuid.UUID `json:"vehicle_id,omitempty"`
+	Errors       []string   `json:"errors,omitempty"`
+}
+
+// VehicleImportResult is the report of a CSV vehicle import
+type VehicleImportResult struct {
+	Created  int                      `json:"created"`
+	Rejected int                      `json:"rejected"`
+	Rows     []VehicleImportRowResult `json:"rows"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// Vehicle statuses, matching the CHECK constraint on vehicles.status
+const (
+	VehicleStatusActive      = "active"
+	VehicleStatusInactive    = "inactive"
+	VehicleStatusMaintenance = "maintenance"
+	VehicleStatusRetired     = "retired"
+)
+
+// VehicleStatuses lists every valid vehicle status
+var VehicleStatuses = []string{VehicleStatusActive, VehicleStatusInactive, VehicleStatusMaintenance, VehicleStatusRetired}
+
+// vehicleStatusTransitions maps each status to the statuses a vehicle may move to from it.
+// Retired is final: a retired vehicle is kept for its history only.
+var vehicleStatusTransitions = map[string][]string{
+	VehicleStatusActive:      {VehicleStatusInactive, VehicleStatusMaintenance, VehicleStatusRetired},
+	VehicleStatusInactive:    {VehicleStatusActive, VehicleStatusMaintenance, VehicleStatusRetired},
+	VehicleStatusMaintenance: {VehicleStatusActive, VehicleStatusInactive, VehicleStatusRetired},
+	VehicleStatusRetired:     {},
+}
+
+// IsValidVehicleStatus reports whether status is one of VehicleStatuses
+func IsValidVehicleStatus(status string) bool {
+	_, ok := vehicleStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionTo reports whether the vehicle may move from its current status to status.
+// Keeping the current status is always allowed. A vehicle whose stored status is not a
+// valid one (legacy data such as "actve") may move to any valid status so it can be fixed.
+func (v *Vehicle) CanTransitionTo(status string) bool {
+	if !IsValidVehicleStatus(status) {
+		return false
+	}
+	if status == v.Status {
+		return true
+	}
+
+	allowed, ok := vehicleStatusTransitions[v.Status]
+	if !ok {
+		return true
+	}
+	for _, next := range allowed {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
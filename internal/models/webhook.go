@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Webhook event types
+const (
+	WebhookEventAccountBlocked           = "auth.account_blocked"
+	WebhookEventVehicleAssignmentChanged = "vehicle.assignment_changed"
+)
+
+// Webhook represents a company endpoint that receives event notifications
+type Webhook struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	CompanyID uuid.UUID      `json:"company_id" db:"company_id"`
+	URL       string         `json:"url" db:"url"`
+	Secret    string         `json:"-" db:"secret"`
+	Events    pq.StringArray `json:"events" db:"events"`
+	Active    bool           `json:"active" db:"active"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Secret replaced by the last rotation, still used to sign deliveries until it expires
+	PreviousSecret          *string    `json:"-" db:"previous_secret"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty" db:"previous_secret_expires_at"`
+}
+
+// ActivePreviousSecret returns the secret replaced by the last rotation while its grace
+// window is open at now, or an empty string
+func (w *Webhook) ActivePreviousSecret(now time.Time) string {
+	if w.PreviousSecret == nil || w.PreviousSecretExpiresAt == nil || !now.Before(*w.PreviousSecretExpiresAt) {
+		return ""
+	}
+	return *w.PreviousSecret
+}
+
+// Subscribes reports whether the webhook is subscribed to the given event
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the JSON body POSTed to webhook endpoints
+type WebhookPayload struct {
+	ID         uuid.UUID   `json:"id"`
+	Event      string      `json:"event"`
+	CompanyID  uuid.UUID   `json:"company_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// CreateWebhookRequest represents request to register a new webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Secret *string  `json:"secret" binding:"omitempty,min=16,max=255"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=auth.account_blocked vehicle.assignment_changed"`
+	Active *bool    `json:"active"`
+}
+
+// UpdateWebhookRequest represents request to update an existing webhook
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" binding:"omitempty,url"`
+	Secret *string  `json:"secret" binding:"omitempty,min=16,max=255"`
+	Events []string `json:"events" binding:"omitempty,min=1,dive,oneof=auth.account_blocked vehicle.assignment_changed"`
+	Active *bool    `json:"active"`
+}
+
+// RotateWebhookSecretRequest represents request to replace a webhook's signing secret.
+// GracePeriodHours defaults to DefaultWebhookSecretGraceHours; 0 retires the old secret immediately.
+type RotateWebhookSecretRequest struct {
+	GracePeriodHours *int `json:"grace_period_hours" binding:"omitempty,min=0,max=168"`
+}
+
+// DefaultWebhookSecretGraceHours is how long a rotated-out secret keeps signing deliveries
+const DefaultWebhookSecretGraceHours = 24
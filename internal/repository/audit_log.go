@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,26 +16,88 @@ import (
 // AuditLogRepositoryInterface defines the contract for audit log repository
 type AuditLogRepositoryInterface interface {
 	Create(ctx context.Context, log *models.AuditLog) error
+	CreateTx(ctx context.Context, tx *sqlx.Tx, log *models.AuditLog) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error)
 	List(ctx context.Context, filter *models.AuditLogFilter) ([]*models.AuditLog, error)
+	Cursor(ctx context.Context, filter *models.AuditLogFilter) (*AuditLogCursor, error)
 	Count(ctx context.Context, filter *models.AuditLogFilter) (int64, error)
 	GetStats(ctx context.Context, filter *models.AuditLogFilter) (*models.AuditLogStats, error)
 	GetByTraceID(ctx context.Context, traceID string) ([]*models.AuditLog, error)
 	DeleteOldLogs(ctx context.Context, olderThan time.Time) (int64, error)
 }
 
+// DefaultAuditRedactedKeys are the metadata keys stripped from audit entries
+// unless overridden with SetRedactedKeys
+var DefaultAuditRedactedKeys = []string{
+	"password", "current_password", "new_password",
+	"token", "access_token", "refresh_token", "session_token", "api_token",
+	"secret", "authorization",
+}
+
 // AuditLogRepository handles audit log database operations
 type AuditLogRepository struct {
 	db *sqlx.DB
+	readReplica
+	redactedKeys map[string]struct{}
 }
 
 // NewAuditLogRepository creates a new audit log repository
 func NewAuditLogRepository(db *sqlx.DB) *AuditLogRepository {
-	return &AuditLogRepository{db: db}
+	r := &AuditLogRepository{db: db, readReplica: readReplica{primary: db}}
+	r.SetRedactedKeys(DefaultAuditRedactedKeys)
+	return r
+}
+
+// SetRedactedKeys replaces the set of keys stripped from metadata and changes
+// before they are persisted. Keys are matched case-insensitively at any depth.
+func (r *AuditLogRepository) SetRedactedKeys(keys []string) {
+	r.redactedKeys = make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		r.redactedKeys[strings.ToLower(strings.TrimSpace(key))] = struct{}{}
+	}
+}
+
+// SetReadReplica routes List, Count and GetStats to a read replica; inserts and
+// lookups by ID or trace stay on the primary
+func (r *AuditLogRepository) SetReadReplica(replica *sqlx.DB) {
+	r.replica = replica
+}
+
+// redact returns a copy of m without the configured sensitive keys
+func (r *AuditLogRepository) redact(m map[string]interface{}) map[string]interface{} {
+	if m == nil || len(r.redactedKeys) == 0 {
+		return m
+	}
+
+	clean := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if _, sensitive := r.redactedKeys[strings.ToLower(key)]; sensitive {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = r.redact(nested)
+		}
+		clean[key] = value
+	}
+	return clean
 }
 
 // Create inserts a new audit log entry
 func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	return r.insert(ctx, r.db, log)
+}
+
+// CreateTx inserts a new audit log entry as part of an existing transaction
+func (r *AuditLogRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, log *models.AuditLog) error {
+	return r.insert(ctx, tx, log)
+}
+
+// insert writes an audit log entry, filling in the ID and timestamp when unset,
+// stripping sensitive keys and marshaling the changes and metadata maps to JSON (NULL when empty)
+func (r *AuditLogRepository) insert(ctx context.Context, db sqlx.ExtContext, log *models.AuditLog) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO audit_logs (
 			id, user_id, user_email, company_id, action, resource, resource_id,
@@ -46,13 +109,23 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) e
 			:success, :error_message, :status_code, :duration_ms, :trace_id, :span_id, :created_at
 		)`
 
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+	if info, ok := models.RequestInfoFromContext(ctx); ok {
+		log.FillRequestInfo(info)
+	}
+
 	// Convert maps to JSON
-	changesJSON, err := json.Marshal(log.Changes)
+	changesJSON, err := marshalAuditJSON(r.redact(log.Changes))
 	if err != nil {
 		return fmt.Errorf("failed to marshal changes: %w", err)
 	}
 
-	metadataJSON, err := json.Marshal(log.Metadata)
+	metadataJSON, err := marshalAuditJSON(r.redact(log.Metadata))
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
@@ -81,12 +154,29 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) e
 		"created_at":    log.CreatedAt,
 	}
 
-	_, err = r.db.NamedExecContext(ctx, query, data)
-	return err
+	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
+// marshalAuditJSON encodes a JSONB column value, storing NULL for empty maps
+func marshalAuditJSON(m map[string]interface{}) (interface{}, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 // GetByID retrieves an audit log by ID
 func (r *AuditLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			id, user_id, user_email, company_id, action, resource, resource_id,
@@ -129,65 +219,99 @@ func (r *AuditLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 
 // List retrieves audit logs with filters
 func (r *AuditLogRepository) List(ctx context.Context, filter *models.AuditLogFilter) ([]*models.AuditLog, error) {
-	query := `
-		SELECT 
-			id, user_id, user_email, company_id, action, resource, resource_id,
-			method, path, ip_address, user_agent, changes, metadata,
-			success, error_message, status_code, duration_ms, trace_id, span_id, created_at
-		FROM audit_logs
-		WHERE 1=1`
-
-	args := []interface{}{}
-	argCount := 1
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	// Apply filters
-	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND user_id = $%d", argCount)
-		args = append(args, *filter.UserID)
-		argCount++
+	query, args, err := auditLogListQuery(filter)
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.CompanyID != nil {
-		query += fmt.Sprintf(" AND company_id = $%d", argCount)
-		args = append(args, *filter.CompanyID)
-		argCount++
+	rows, err := r.reader().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	if filter.Action != nil {
-		query += fmt.Sprintf(" AND action = $%d", argCount)
-		args = append(args, *filter.Action)
-		argCount++
+	var logs []*models.AuditLog
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
 	}
 
-	if filter.Resource != nil {
-		query += fmt.Sprintf(" AND resource = $%d", argCount)
-		args = append(args, *filter.Resource)
-		argCount++
+	return logs, rows.Err()
+}
+
+// AuditLogCursor iterates over audit logs one row at a time, so large result sets can be
+// streamed without loading them in memory
+type AuditLogCursor struct {
+	rows *sqlx.Rows
+	log  *models.AuditLog
+	err  error
+}
+
+// Next advances to the next log, returning false at the end or on error
+func (c *AuditLogCursor) Next() bool {
+	if c.err != nil || !c.rows.Next() {
+		return false
 	}
+	c.log, c.err = scanAuditLog(c.rows)
+	return c.err == nil
+}
 
-	if filter.ResourceID != nil {
-		query += fmt.Sprintf(" AND resource_id = $%d", argCount)
-		args = append(args, *filter.ResourceID)
-		argCount++
+// Log returns the current log
+func (c *AuditLogCursor) Log() *models.AuditLog {
+	return c.log
+}
+
+// Err returns the error that stopped the iteration, if any
+func (c *AuditLogCursor) Err() error {
+	if c.err != nil {
+		return c.err
 	}
+	return c.rows.Err()
+}
 
-	if filter.Success != nil {
-		query += fmt.Sprintf(" AND success = $%d", argCount)
-		args = append(args, *filter.Success)
-		argCount++
+// Close releases the cursor's connection
+func (c *AuditLogCursor) Close() error {
+	return c.rows.Close()
+}
+
+// Cursor opens a cursor over the audit logs matching the filter, newest first; a positive
+// filter.Limit caps the rows. No query timeout applies: the read lasts as long as the caller
+// consumes it, bounded by ctx. The caller must Close the cursor.
+func (r *AuditLogRepository) Cursor(ctx context.Context, filter *models.AuditLogFilter) (*AuditLogCursor, error) {
+	query, args, err := auditLogListQuery(filter)
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.From != nil {
-		query += fmt.Sprintf(" AND created_at >= $%d", argCount)
-		args = append(args, *filter.From)
-		argCount++
+	rows, err := r.reader().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit logs: %w", err)
 	}
 
-	if filter.To != nil {
-		query += fmt.Sprintf(" AND created_at <= $%d", argCount)
-		args = append(args, *filter.To)
-		argCount++
+	return &AuditLogCursor{rows: rows}, nil
+}
+
+// auditLogListQuery builds the query shared by List and Cursor
+func auditLogListQuery(filter *models.AuditLogFilter) (string, []interface{}, error) {
+	where, args, err := auditLogWhere(filter)
+	if err != nil {
+		return "", nil, err
 	}
+	argCount := len(args) + 1
+
+	query := `
+		SELECT 
+			id, user_id, user_email, company_id, action, resource, resource_id,
+			method, path, ip_address, user_agent, changes, metadata,
+			success, error_message, status_code, duration_ms, trace_id, span_id, created_at
+		FROM audit_logs
+		` + where
 
 	// Order by created_at desc
 	query += " ORDER BY created_at DESC"
@@ -204,95 +328,122 @@ func (r *AuditLogRepository) List(ctx context.Context, filter *models.AuditLogFi
 		args = append(args, filter.Offset)
 	}
 
-	rows, err := r.db.QueryxContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var logs []*models.AuditLog
-	for rows.Next() {
-		var log models.AuditLog
-		var changesJSON, metadataJSON []byte
-
-		err := rows.Scan(
-			&log.ID, &log.UserID, &log.UserEmail, &log.CompanyID, &log.Action, &log.Resource, &log.ResourceID,
-			&log.Method, &log.Path, &log.IPAddress, &log.UserAgent, &changesJSON, &metadataJSON,
-			&log.Success, &log.ErrorMessage, &log.StatusCode, &log.DurationMs, &log.TraceID, &log.SpanID, &log.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		// Unmarshal JSON fields
-		if changesJSON != nil {
-			json.Unmarshal(changesJSON, &log.Changes)
-		}
-		if metadataJSON != nil {
-			json.Unmarshal(metadataJSON, &log.Metadata)
-		}
-
-		logs = append(logs, &log)
-	}
-
-	return logs, rows.Err()
+	return query, args, nil
 }
 
-// Count returns the total count of audit logs matching the filter
-func (r *AuditLogRepository) Count(ctx context.Context, filter *models.AuditLogFilter) (int64, error) {
-	query := "SELECT COUNT(*) FROM audit_logs WHERE 1=1"
+// auditLogWhere builds the WHERE clause for the filter, shared by List, Cursor and Count
+func auditLogWhere(filter *models.AuditLogFilter) (string, []interface{}, error) {
+	where := "WHERE 1=1"
 	args := []interface{}{}
 	argCount := 1
 
-	// Apply same filters as List
+	// Apply filters
 	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND user_id = $%d", argCount)
+		where += fmt.Sprintf(" AND user_id = $%d", argCount)
 		args = append(args, *filter.UserID)
 		argCount++
 	}
 
 	if filter.CompanyID != nil {
-		query += fmt.Sprintf(" AND company_id = $%d", argCount)
+		where += fmt.Sprintf(" AND company_id = $%d", argCount)
 		args = append(args, *filter.CompanyID)
 		argCount++
 	}
 
 	if filter.Action != nil {
-		query += fmt.Sprintf(" AND action = $%d", argCount)
+		where += fmt.Sprintf(" AND action = $%d", argCount)
 		args = append(args, *filter.Action)
 		argCount++
 	}
 
 	if filter.Resource != nil {
-		query += fmt.Sprintf(" AND resource = $%d", argCount)
+		where += fmt.Sprintf(" AND resource = $%d", argCount)
 		args = append(args, *filter.Resource)
 		argCount++
 	}
 
+	if filter.ResourceID != nil {
+		where += fmt.Sprintf(" AND resource_id = $%d", argCount)
+		args = append(args, *filter.ResourceID)
+		argCount++
+	}
+
 	if filter.Success != nil {
-		query += fmt.Sprintf(" AND success = $%d", argCount)
+		where += fmt.Sprintf(" AND success = $%d", argCount)
 		args = append(args, *filter.Success)
 		argCount++
 	}
 
 	if filter.From != nil {
-		query += fmt.Sprintf(" AND created_at >= $%d", argCount)
+		where += fmt.Sprintf(" AND created_at >= $%d", argCount)
 		args = append(args, *filter.From)
 		argCount++
 	}
 
 	if filter.To != nil {
-		query += fmt.Sprintf(" AND created_at <= $%d", argCount)
+		where += fmt.Sprintf(" AND created_at <= $%d", argCount)
 		args = append(args, *filter.To)
+		argCount++
+	}
+
+	if len(filter.Metadata) > 0 {
+		metadataJSON, err := json.Marshal(filter.Metadata)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal metadata filter: %w", err)
+		}
+		where += fmt.Sprintf(" AND metadata @> $%d", argCount)
+		args = append(args, metadataJSON)
+	}
+
+	return where, args, nil
+}
+
+// scanAuditLog scans the current row of a List or Cursor query
+func scanAuditLog(rows *sqlx.Rows) (*models.AuditLog, error) {
+	var log models.AuditLog
+	var changesJSON, metadataJSON []byte
+
+	err := rows.Scan(
+		&log.ID, &log.UserID, &log.UserEmail, &log.CompanyID, &log.Action, &log.Resource, &log.ResourceID,
+		&log.Method, &log.Path, &log.IPAddress, &log.UserAgent, &changesJSON, &metadataJSON,
+		&log.Success, &log.ErrorMessage, &log.StatusCode, &log.DurationMs, &log.TraceID, &log.SpanID, &log.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal JSON fields
+	if changesJSON != nil {
+		json.Unmarshal(changesJSON, &log.Changes)
+	}
+	if metadataJSON != nil {
+		json.Unmarshal(metadataJSON, &log.Metadata)
+	}
+
+	return &log, nil
+}
+
+// Count returns the total count of audit logs matching the filter
+func (r *AuditLogRepository) Count(ctx context.Context, filter *models.AuditLogFilter) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// Apply same filters as List
+	where, args, err := auditLogWhere(filter)
+	if err != nil {
+		return 0, err
 	}
 
 	var count int64
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err = r.reader().GetContext(ctx, &count, "SELECT COUNT(*) FROM audit_logs "+where, args...)
 	return count, err
 }
 
 // GetStats returns aggregated statistics for audit logs
 func (r *AuditLogRepository) GetStats(ctx context.Context, filter *models.AuditLogFilter) (*models.AuditLogStats, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	// Base stats query
 	var stats models.AuditLogStats
 
@@ -315,7 +466,7 @@ func (r *AuditLogRepository) GetStats(ctx context.Context, filter *models.AuditL
 
 	var total int64
 	var successRate, avgDuration sql.NullFloat64
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&total, &successRate, &avgDuration)
+	err := r.reader().QueryRowContext(ctx, query, args...).Scan(&total, &successRate, &avgDuration)
 	if err != nil {
 		return nil, err
 	}
@@ -342,7 +493,7 @@ func (r *AuditLogRepository) GetStats(ctx context.Context, filter *models.AuditL
 	}
 	actionQuery += " GROUP BY action"
 
-	rows, err := r.db.QueryContext(ctx, actionQuery, args...)
+	rows, err := r.reader().QueryContext(ctx, actionQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -362,6 +513,9 @@ func (r *AuditLogRepository) GetStats(ctx context.Context, filter *models.AuditL
 
 // GetByTraceID retrieves all audit logs for a specific Jaeger trace ID
 func (r *AuditLogRepository) GetByTraceID(ctx context.Context, traceID string) ([]*models.AuditLog, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			id, user_id, user_email, company_id, action, resource, resource_id,
@@ -406,6 +560,9 @@ func (r *AuditLogRepository) GetByTraceID(ctx context.Context, traceID string) (
 
 // DeleteOldLogs deletes audit logs older than the specified date
 func (r *AuditLogRepository) DeleteOldLogs(ctx context.Context, olderThan time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM audit_logs WHERE created_at < $1"
 	result, err := r.db.ExecContext(ctx, query, olderThan)
 	if err != nil {
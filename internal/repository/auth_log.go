@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/paulochiaradia/dashtrack/internal/models"
 )
 
@@ -15,6 +16,7 @@ type AuthLogRepositoryInterface interface {
 	Create(log *models.AuthLog) error
 	GetRecentFailedAttempts(email string, since time.Time) (int, error)
 	GetByUserID(userID uuid.UUID, limit int) ([]*models.AuthLog, error)
+	GetUserLoginCountries(ctx context.Context, userID uuid.UUID) ([]string, error)
 
 	// Dashboard methods
 	CountLogins(ctx context.Context, companyID *uuid.UUID, from, to time.Time) (int, error)
@@ -32,18 +34,25 @@ type AuthLogRepositoryInterface interface {
 // AuthLogRepository handles authentication log database operations
 type AuthLogRepository struct {
 	db *sql.DB
+	readReplica
 }
 
 // NewAuthLogRepository creates a new auth log repository
 func NewAuthLogRepository(db *sql.DB) *AuthLogRepository {
-	return &AuthLogRepository{db: db}
+	return &AuthLogRepository{db: db, readReplica: readReplica{primary: sqlx.NewDb(db, "postgres")}}
+}
+
+// SetReadReplica routes login history and dashboard queries to a read replica.
+// Failed-attempt and country lookups used during login stay on the primary.
+func (r *AuthLogRepository) SetReadReplica(replica *sqlx.DB) {
+	r.replica = replica
 }
 
 // Create inserts a new authentication log
 func (r *AuthLogRepository) Create(log *models.AuthLog) error {
 	query := `
-		INSERT INTO auth_logs (id, user_id, email_attempt, success, ip_address, user_agent, failure_reason)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO auth_logs (id, user_id, email_attempt, success, ip_address, user_agent, failure_reason, country_code)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING created_at`
 
 	return r.db.QueryRow(
@@ -55,9 +64,38 @@ func (r *AuthLogRepository) Create(log *models.AuthLog) error {
 		log.IPAddress,
 		log.UserAgent,
 		log.FailureReason,
+		log.CountryCode,
 	).Scan(&log.CreatedAt)
 }
 
+// GetUserLoginCountries returns the distinct countries the user has successfully logged in from
+func (r *AuthLogRepository) GetUserLoginCountries(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT DISTINCT country_code
+		FROM auth_logs
+		WHERE user_id = $1 AND success = true AND country_code IS NOT NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user login countries: %w", err)
+	}
+	defer rows.Close()
+
+	countries := []string{}
+	for rows.Next() {
+		var country string
+		if err := rows.Scan(&country); err != nil {
+			return nil, fmt.Errorf("failed to scan login country: %w", err)
+		}
+		countries = append(countries, country)
+	}
+
+	return countries, rows.Err()
+}
+
 // GetRecentFailedAttempts retrieves recent failed login attempts for an email
 func (r *AuthLogRepository) GetRecentFailedAttempts(email string, since time.Time) (int, error) {
 	query := `
@@ -79,7 +117,7 @@ func (r *AuthLogRepository) GetByUserID(userID uuid.UUID, limit int) ([]*models.
 		ORDER BY created_at DESC 
 		LIMIT $2`
 
-	rows, err := r.db.Query(query, userID, limit)
+	rows, err := r.reader().Query(query, userID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +154,7 @@ func (r *AuthLogRepository) GetLoginHistory(userID uuid.UUID, limit, offset int)
 		ORDER BY created_at DESC 
 		LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.Query(query, userID, limit, offset)
+	rows, err := r.reader().Query(query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +184,9 @@ func (r *AuthLogRepository) GetLoginHistory(userID uuid.UUID, limit, offset int)
 
 // CountLogins counts total login attempts for a company or all companies in a time range
 func (r *AuthLogRepository) CountLogins(ctx context.Context, companyID *uuid.UUID, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var query string
 	var args []interface{}
 
@@ -162,12 +203,15 @@ func (r *AuthLogRepository) CountLogins(ctx context.Context, companyID *uuid.UUI
 	}
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
 // CountSuccessfulLogins counts successful login attempts
 func (r *AuthLogRepository) CountSuccessfulLogins(ctx context.Context, companyID *uuid.UUID, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var query string
 	var args []interface{}
 
@@ -184,12 +228,15 @@ func (r *AuthLogRepository) CountSuccessfulLogins(ctx context.Context, companyID
 	}
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
 // CountFailedLogins counts failed login attempts
 func (r *AuthLogRepository) CountFailedLogins(ctx context.Context, companyID *uuid.UUID, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var query string
 	var args []interface{}
 
@@ -206,36 +253,48 @@ func (r *AuthLogRepository) CountFailedLogins(ctx context.Context, companyID *uu
 	}
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
 // CountUserLogins counts login attempts for a specific user
 func (r *AuthLogRepository) CountUserLogins(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT COUNT(*) FROM auth_logs WHERE user_id = $1 AND created_at BETWEEN $2 AND $3"
 	var count int
-	err := r.db.QueryRowContext(ctx, query, userID, from, to).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, userID, from, to).Scan(&count)
 	return count, err
 }
 
 // CountUserSuccessfulLogins counts successful login attempts for a specific user
 func (r *AuthLogRepository) CountUserSuccessfulLogins(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT COUNT(*) FROM auth_logs WHERE user_id = $1 AND success = true AND created_at BETWEEN $2 AND $3"
 	var count int
-	err := r.db.QueryRowContext(ctx, query, userID, from, to).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, userID, from, to).Scan(&count)
 	return count, err
 }
 
 // CountUserFailedLogins counts failed login attempts for a specific user
 func (r *AuthLogRepository) CountUserFailedLogins(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT COUNT(*) FROM auth_logs WHERE user_id = $1 AND success = false AND created_at BETWEEN $2 AND $3"
 	var count int
-	err := r.db.QueryRowContext(ctx, query, userID, from, to).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, userID, from, to).Scan(&count)
 	return count, err
 }
 
 // GetRecentSuccessfulLogins gets recent successful logins with user information
 func (r *AuthLogRepository) GetRecentSuccessfulLogins(ctx context.Context, companyID *uuid.UUID, from, to time.Time, limit int) ([]models.RecentLogin, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			al.user_id, 
@@ -263,7 +322,7 @@ func (r *AuthLogRepository) GetRecentSuccessfulLogins(ctx context.Context, compa
 	query += " ORDER BY al.created_at DESC LIMIT $" + fmt.Sprintf("%d", len(args)+1)
 	args = append(args, limit)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -294,6 +353,9 @@ func (r *AuthLogRepository) GetRecentSuccessfulLogins(ctx context.Context, compa
 
 // GetUserRecentSuccessfulLogins gets recent successful logins for a specific user
 func (r *AuthLogRepository) GetUserRecentSuccessfulLogins(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) ([]models.RecentLogin, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			al.user_id, 
@@ -314,7 +376,7 @@ func (r *AuthLogRepository) GetUserRecentSuccessfulLogins(ctx context.Context, u
 		ORDER BY al.created_at DESC 
 		LIMIT $4`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, from, to, limit)
+	rows, err := r.reader().QueryContext(ctx, query, userID, from, to, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxBatchSize is the number of items a batch request may carry when none is configured
+const DefaultMaxBatchSize = 1000
+
+// ErrBatchTooLarge is returned when a batch request carries more items than allowed
+var ErrBatchTooLarge = errors.New("batch is too large")
+
+// CheckBatchSize returns ErrBatchTooLarge when size exceeds maxSize. Batch queries pass
+// their IDs as a single array parameter (= ANY($1)), so the cap bounds the work done per
+// request rather than the number of query parameters.
+func CheckBatchSize(size, maxSize int) error {
+	if size > maxSize {
+		return fmt.Errorf("%w: at most %d items per request, got %d", ErrBatchTooLarge, maxSize, size)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,26 +17,37 @@ import (
 	"github.com/paulochiaradia/dashtrack/internal/models"
 )
 
+// ErrCompanyHasDependents is returned when deleting a company that still has active users, vehicles or teams
+var ErrCompanyHasDependents = errors.New("company still has active users, vehicles or teams")
+
 // CompanyRepositoryInterface defines the contract for company repository
 type CompanyRepositoryInterface interface {
+	List(ctx context.Context, limit, offset int) ([]models.Company, error)
 	CountCompanies(ctx context.Context) (int, error)
 	CountActiveCompanies(ctx context.Context) (int, error)
 }
 
 // CompanyRepository handles database operations for companies
 type CompanyRepository struct {
-	db     *sqlx.DB
+	db *sqlx.DB
+	readReplica
 	tracer trace.Tracer
 }
 
 // NewCompanyRepository creates a new company repository
 func NewCompanyRepository(db *sqlx.DB) *CompanyRepository {
 	return &CompanyRepository{
-		db:     db,
-		tracer: otel.Tracer("company-repository"),
+		db:          db,
+		readReplica: readReplica{primary: db},
+		tracer:      otel.Tracer("company-repository"),
 	}
 }
 
+// SetReadReplica routes the dashboard company counts to a read replica
+func (r *CompanyRepository) SetReadReplica(replica *sqlx.DB) {
+	r.replica = replica
+}
+
 // Create creates a new company
 func (r *CompanyRepository) Create(ctx context.Context, company *models.Company) error {
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Create",
@@ -44,6 +56,8 @@ func (r *CompanyRepository) Create(ctx context.Context, company *models.Company)
 			attribute.String("company.slug", company.Slug),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Set defaults
 	company.ID = uuid.New()
@@ -99,6 +113,8 @@ func (r *CompanyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.GetByID",
 		trace.WithAttributes(attribute.String("company.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var company models.Company
 	query := `
@@ -126,6 +142,8 @@ func (r *CompanyRepository) GetBySlug(ctx context.Context, slug string) (*models
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.GetBySlug",
 		trace.WithAttributes(attribute.String("company.slug", slug)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var company models.Company
 	query := `
@@ -156,6 +174,8 @@ func (r *CompanyRepository) List(ctx context.Context, limit, offset int) ([]mode
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var companies []models.Company
 	query := `
@@ -182,6 +202,8 @@ func (r *CompanyRepository) Update(ctx context.Context, company *models.Company)
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Update",
 		trace.WithAttributes(attribute.String("company.id", company.ID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	company.UpdatedAt = time.Now()
 
@@ -217,34 +239,89 @@ func (r *CompanyRepository) Update(ctx context.Context, company *models.Company)
 	return nil
 }
 
-// Delete soft deletes a company
+// Delete soft deletes a company. It fails with ErrCompanyHasDependents while the company
+// still has active users, vehicles or teams; the company row is locked during the check.
 func (r *CompanyRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Delete",
 		trace.WithAttributes(attribute.String("company.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	// Soft delete: mark as inactive instead of deleting
-	query := `UPDATE companies SET status = 'inactive', updated_at = NOW() WHERE id = $1`
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var companyID uuid.UUID
+	err = tx.GetContext(ctx, &companyID, `SELECT id FROM companies WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("company not found")
+		}
+		span.RecordError(err)
+		return fmt.Errorf("failed to lock company: %w", err)
+	}
+
+	var dependents models.CompanyDependents
+	if err := tx.GetContext(ctx, &dependents, companyDependentsQuery, id); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to count company dependents: %w", err)
+	}
+	if dependents.HasAny() {
+		return fmt.Errorf("%w (users: %d, vehicles: %d, teams: %d)", ErrCompanyHasDependents,
+			dependents.ActiveUsers, dependents.ActiveVehicles, dependents.ActiveTeams)
+	}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	// Soft delete: mark as inactive instead of deleting
+	_, err = tx.ExecContext(ctx, `UPDATE companies SET status = 'inactive', deleted_at = NOW(), updated_at = NOW() WHERE id = $1`, id)
 	if err != nil {
 		span.RecordError(err)
 		return fmt.Errorf("failed to delete company: %w", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("company not found")
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit company deletion: %w", err)
 	}
 
 	return nil
 }
 
+// companyDependentsQuery counts the active users, vehicles and teams of a company
+const companyDependentsQuery = `
+	SELECT
+		(SELECT COUNT(*) FROM users WHERE company_id = $1 AND active = true AND deleted_at IS NULL) AS active_users,
+		(SELECT COUNT(*) FROM vehicles WHERE company_id = $1 AND status <> 'retired' AND deleted_at IS NULL) AS active_vehicles,
+		(SELECT COUNT(*) FROM teams WHERE company_id = $1 AND status = 'active' AND deleted_at IS NULL) AS active_teams
+`
+
+// CountActiveDependents returns how many active users, vehicles and teams a company still has
+func (r *CompanyRepository) CountActiveDependents(ctx context.Context, id uuid.UUID) (*models.CompanyDependents, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.CountActiveDependents",
+		trace.WithAttributes(attribute.String("company.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var dependents models.CompanyDependents
+	if err := r.db.GetContext(ctx, &dependents, companyDependentsQuery, id); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to count company dependents: %w", err)
+	}
+
+	return &dependents, nil
+}
+
 // GetCompanyStats returns statistical data for a company
 func (r *CompanyRepository) GetCompanyStats(ctx context.Context, companyID uuid.UUID) (*models.CompanyStats, error) {
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.GetCompanyStats",
 		trace.WithAttributes(attribute.String("company.id", companyID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	stats := &models.CompanyStats{}
 
@@ -366,6 +443,8 @@ func (r *CompanyRepository) CheckSlugExists(ctx context.Context, slug string, ex
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.CheckSlugExists",
 		trace.WithAttributes(attribute.String("company.slug", slug)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `SELECT COUNT(*) FROM companies WHERE slug = $1`
 	args := []interface{}{slug}
@@ -394,6 +473,8 @@ func (r *CompanyRepository) Search(ctx context.Context, searchTerm string, limit
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var companies []models.Company
 	searchPattern := "%" + strings.ToLower(searchTerm) + "%"
@@ -423,10 +504,12 @@ func (r *CompanyRepository) Search(ctx context.Context, searchTerm string, limit
 func (r *CompanyRepository) CountCompanies(ctx context.Context) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.CountCompanies")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := "SELECT COUNT(*) FROM companies"
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		span.RecordError(err)
 		return 0, fmt.Errorf("failed to count companies: %w", err)
@@ -439,11 +522,13 @@ func (r *CompanyRepository) CountCompanies(ctx context.Context) (int, error) {
 func (r *CompanyRepository) CountActiveCompanies(ctx context.Context) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "CompanyRepository.CountActiveCompanies")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Temporarily count all companies since active column might not exist
 	query := "SELECT COUNT(*) FROM companies"
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		span.RecordError(err)
 		return 0, fmt.Errorf("failed to count active companies: %w", err)
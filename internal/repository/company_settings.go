@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// CompanySettingsRepositoryInterface defines the contract for company settings repository
+type CompanySettingsRepositoryInterface interface {
+	Get(ctx context.Context, companyID uuid.UUID) (*models.CompanySettingsRecord, error)
+	Upsert(ctx context.Context, companyID uuid.UUID, settings models.CompanySettings, updatedBy *uuid.UUID) (*models.CompanySettingsRecord, error)
+}
+
+// CompanySettingsRepository handles database operations for company settings
+type CompanySettingsRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewCompanySettingsRepository creates a new company settings repository
+func NewCompanySettingsRepository(db *sqlx.DB) *CompanySettingsRepository {
+	return &CompanySettingsRepository{
+		db:     db,
+		tracer: otel.Tracer("company-settings-repository"),
+	}
+}
+
+// Get returns a company's settings, with defaults for every key the company hasn't set.
+// A company without stored settings gets the defaults.
+func (r *CompanySettingsRepository) Get(ctx context.Context, companyID uuid.UUID) (*models.CompanySettingsRecord, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanySettingsRepository.Get",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	record := &models.CompanySettingsRecord{
+		CompanyID: companyID,
+		Settings:  models.DefaultCompanySettings(),
+	}
+
+	var document []byte
+	var updatedAt time.Time
+	query := `SELECT settings, updated_by, updated_at FROM company_settings WHERE company_id = $1`
+	err := r.db.QueryRowContext(ctx, query, companyID).Scan(&document, &record.UpdatedBy, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return record, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get company settings: %w", err)
+	}
+
+	// Keys missing from the document keep their defaults
+	if err := json.Unmarshal(document, &record.Settings); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to decode company settings: %w", err)
+	}
+	record.UpdatedAt = &updatedAt
+
+	return record, nil
+}
+
+// Upsert stores a company's settings, replacing any previous ones
+func (r *CompanySettingsRepository) Upsert(ctx context.Context, companyID uuid.UUID, settings models.CompanySettings, updatedBy *uuid.UUID) (*models.CompanySettingsRecord, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanySettingsRepository.Upsert",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	document, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode company settings: %w", err)
+	}
+
+	updatedAt := time.Now()
+	query := `
+		INSERT INTO company_settings (company_id, settings, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (company_id) DO UPDATE
+		SET settings = EXCLUDED.settings, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query, companyID, document, updatedBy, updatedAt); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to save company settings: %w", err)
+	}
+
+	return &models.CompanySettingsRecord{
+		CompanyID: companyID,
+		Settings:  settings,
+		UpdatedBy: updatedBy,
+		UpdatedAt: &updatedAt,
+	}, nil
+}
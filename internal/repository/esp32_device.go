@@ -38,6 +38,8 @@ func (r *ESP32DeviceRepository) Create(ctx context.Context, device *models.ESP32
 			attribute.String("device.name", device.DeviceName),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Add company_id attribute only if it's not nil
 	if device.CompanyID != nil {
@@ -81,6 +83,8 @@ func (r *ESP32DeviceRepository) GetByID(ctx context.Context, id uuid.UUID, compa
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var device models.ESP32Device
 	query := `
@@ -108,6 +112,8 @@ func (r *ESP32DeviceRepository) GetByDeviceID(ctx context.Context, deviceID stri
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.GetByDeviceID",
 		trace.WithAttributes(attribute.String("device.device_id", deviceID)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var device models.ESP32Device
 	query := `
@@ -139,6 +145,8 @@ func (r *ESP32DeviceRepository) GetByCompany(ctx context.Context, companyID uuid
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var devices []models.ESP32Device
 	query := `
@@ -169,6 +177,8 @@ func (r *ESP32DeviceRepository) GetByVehicle(ctx context.Context, vehicleID uuid
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var devices []models.ESP32Device
 	query := `
@@ -195,6 +205,8 @@ func (r *ESP32DeviceRepository) Update(ctx context.Context, device *models.ESP32
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.Update",
 		trace.WithAttributes(attribute.String("device.id", device.ID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	device.UpdatedAt = time.Now()
 
@@ -235,6 +247,8 @@ func (r *ESP32DeviceRepository) UpdateHeartbeat(ctx context.Context, deviceID st
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.UpdateHeartbeat",
 		trace.WithAttributes(attribute.String("device.device_id", deviceID)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE esp32_devices SET
@@ -268,6 +282,8 @@ func (r *ESP32DeviceRepository) UpdateVehicleAssignment(ctx context.Context, dev
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE esp32_devices SET
@@ -298,6 +314,8 @@ func (r *ESP32DeviceRepository) Delete(ctx context.Context, id uuid.UUID, compan
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE esp32_devices 
@@ -324,6 +342,8 @@ func (r *ESP32DeviceRepository) MarkOfflineDevices(ctx context.Context, timeoutM
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.MarkOfflineDevices",
 		trace.WithAttributes(attribute.Int("timeout_minutes", timeoutMinutes)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE esp32_devices 
@@ -349,6 +369,8 @@ func (r *ESP32DeviceRepository) GetOnlineDevices(ctx context.Context, companyID
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.GetOnlineDevices",
 		trace.WithAttributes(attribute.String("company.id", companyID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var devices []models.ESP32Device
 	query := `
@@ -380,6 +402,8 @@ func (r *ESP32DeviceRepository) Search(ctx context.Context, companyID uuid.UUID,
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var devices []models.ESP32Device
 	searchPattern := "%" + strings.ToLower(searchTerm) + "%"
@@ -411,6 +435,8 @@ func (r *ESP32DeviceRepository) CheckDeviceIDExists(ctx context.Context, deviceI
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.CheckDeviceIDExists",
 		trace.WithAttributes(attribute.String("device.device_id", deviceID)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `SELECT COUNT(*) FROM esp32_devices WHERE device_id = $1 AND status != 'deleted'`
 	args := []interface{}{deviceID}
@@ -431,9 +457,11 @@ func (r *ESP32DeviceRepository) CheckDeviceIDExists(ctx context.Context, deviceI
 }
 
 // GetDeviceStatistics retrieves statistics for ESP32 devices
-func (r *ESP32DeviceRepository) GetDeviceStatistics(ctx context.Context, companyID *uuid.UUID) (map[string]interface{}, error) {
+func (r *ESP32DeviceRepository) GetDeviceStatistics(ctx context.Context, companyID *uuid.UUID) (*models.ESP32DeviceStats, error) {
 	ctx, span := r.tracer.Start(ctx, "ESP32DeviceRepository.GetDeviceStatistics")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT 
@@ -453,29 +481,12 @@ func (r *ESP32DeviceRepository) GetDeviceStatistics(ctx context.Context, company
 		span.SetAttributes(attribute.String("company.id", companyID.String()))
 	}
 
-	var stats struct {
-		TotalDevices      int      `db:"total_devices"`
-		OnlineDevices     int      `db:"online_devices"`
-		OfflineDevices    int      `db:"offline_devices"`
-		InactiveDevices   int      `db:"inactive_devices"`
-		AvgBatteryLevel   *float64 `db:"avg_battery_level"`
-		AvgSignalStrength *float64 `db:"avg_signal_strength"`
-	}
-
+	var stats models.ESP32DeviceStats
 	err := r.db.GetContext(ctx, &stats, query, args...)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to get device statistics: %w", err)
 	}
 
-	result := map[string]interface{}{
-		"total_devices":       stats.TotalDevices,
-		"online_devices":      stats.OnlineDevices,
-		"offline_devices":     stats.OfflineDevices,
-		"inactive_devices":    stats.InactiveDevices,
-		"avg_battery_level":   stats.AvgBatteryLevel,
-		"avg_signal_strength": stats.AvgSignalStrength,
-	}
-
-	return result, nil
+	return &stats, nil
 }
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// ErrOdometerDecreased is returned when a reading is lower than the vehicle's current odometer
+var ErrOdometerDecreased = errors.New("odometer reading is lower than the previous reading")
+
+// FuelRepositoryInterface defines the contract for fuel and odometer repository
+type FuelRepositoryInterface interface {
+	CreateFuelLog(ctx context.Context, log *models.FuelLog, companyID uuid.UUID) error
+	ListFuelLogs(ctx context.Context, vehicleID, companyID uuid.UUID, from, to time.Time) ([]models.FuelLog, error)
+	GetFuelEfficiency(ctx context.Context, vehicleID, companyID uuid.UUID, from, to time.Time) (*models.FuelEfficiency, error)
+	CreateOdometerReading(ctx context.Context, reading *models.OdometerReading, companyID uuid.UUID) error
+	ListOdometerReadings(ctx context.Context, vehicleID, companyID uuid.UUID, limit, offset int) ([]models.OdometerReading, error)
+}
+
+// FuelRepository handles database operations for fuel logs and odometer readings.
+// Both are scoped through the vehicle, whose odometer column tracks the latest reading.
+type FuelRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewFuelRepository creates a new fuel repository
+func NewFuelRepository(db *sqlx.DB) *FuelRepository {
+	return &FuelRepository{
+		db:     db,
+		tracer: otel.Tracer("fuel-repository"),
+	}
+}
+
+// CreateFuelLog records a fuel purchase along with the odometer reading taken at the pump
+func (r *FuelRepository) CreateFuelLog(ctx context.Context, log *models.FuelLog, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "FuelRepository.CreateFuelLog",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", log.VehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	log.ID = uuid.New()
+	log.CreatedAt = time.Now()
+
+	reading := &models.OdometerReading{
+		VehicleID:  log.VehicleID,
+		OdometerKm: log.OdometerKm,
+		RecordedAt: log.FilledAt,
+		Source:     "fuel_log",
+		CreatedBy:  log.CreatedBy,
+	}
+	if err := r.advanceOdometer(ctx, tx, reading, companyID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, err = tx.NamedExecContext(ctx, `
+		INSERT INTO fuel_logs (
+			id, vehicle_id, liters, cost, odometer_km, filled_at, station, notes, created_by, created_at
+		) VALUES (
+			:id, :vehicle_id, :liters, :cost, :odometer_km, :filled_at, :station, :notes, :created_by, :created_at
+		)
+	`, log)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to create fuel log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit fuel log: %w", err)
+	}
+
+	span.SetAttributes(attribute.String("fuel_log.id", log.ID.String()))
+	return nil
+}
+
+// ListFuelLogs retrieves the fuel logs of a vehicle filled in [from, to), most recent first
+func (r *FuelRepository) ListFuelLogs(ctx context.Context, vehicleID, companyID uuid.UUID, from, to time.Time) ([]models.FuelLog, error) {
+	ctx, span := r.tracer.Start(ctx, "FuelRepository.ListFuelLogs",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", vehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	logs := []models.FuelLog{}
+	query := `
+		SELECT f.id, f.vehicle_id, f.liters, f.cost, f.odometer_km, f.filled_at, f.station, f.notes,
+		       f.created_by, f.created_at
+		FROM fuel_logs f
+		JOIN vehicles v ON v.id = f.vehicle_id
+		WHERE f.vehicle_id = $1 AND v.company_id = $2 AND v.deleted_at IS NULL
+		  AND f.filled_at >= $3 AND f.filled_at < $4
+		ORDER BY f.filled_at DESC, f.id
+	`
+
+	err := r.db.SelectContext(ctx, &logs, query, vehicleID, companyID, from, to)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to list fuel logs: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("fuel_log.count", len(logs)))
+	return logs, nil
+}
+
+// GetFuelEfficiency computes the km/L of a vehicle from the fuel logs filled in [from, to).
+// Distance is the odometer span of the fills; the first fill's liters are excluded from
+// consumption. KmPerLiter is nil when fewer than two fills are available.
+func (r *FuelRepository) GetFuelEfficiency(ctx context.Context, vehicleID, companyID uuid.UUID, from, to time.Time) (*models.FuelEfficiency, error) {
+	ctx, span := r.tracer.Start(ctx, "FuelRepository.GetFuelEfficiency",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", vehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	efficiency := models.FuelEfficiency{}
+	query := `
+		WITH fills AS (
+			SELECT f.liters, f.cost, f.odometer_km,
+			       ROW_NUMBER() OVER (ORDER BY f.odometer_km, f.filled_at) AS rn
+			FROM fuel_logs f
+			JOIN vehicles v ON v.id = f.vehicle_id
+			WHERE f.vehicle_id = $1 AND v.company_id = $2 AND v.deleted_at IS NULL
+			  AND f.filled_at >= $3 AND f.filled_at < $4
+		)
+		SELECT COUNT(*) AS fill_count,
+		       COALESCE(MAX(odometer_km) - MIN(odometer_km), 0) AS distance_km,
+		       COALESCE(SUM(liters) FILTER (WHERE rn > 1), 0) AS liters_consumed,
+		       COALESCE(SUM(liters), 0) AS total_liters,
+		       COALESCE(SUM(cost), 0) AS total_cost
+		FROM fills
+	`
+
+	err := r.db.GetContext(ctx, &efficiency, query, vehicleID, companyID, from, to)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get fuel efficiency: %w", err)
+	}
+
+	efficiency.VehicleID = vehicleID
+	efficiency.From = from
+	efficiency.To = to
+	if efficiency.FillCount > 1 && efficiency.LitersConsumed > 0 {
+		kmPerLiter := float64(efficiency.DistanceKm) / efficiency.LitersConsumed
+		efficiency.KmPerLiter = &kmPerLiter
+	}
+	if efficiency.DistanceKm > 0 {
+		costPerKm := efficiency.TotalCost / float64(efficiency.DistanceKm)
+		efficiency.CostPerKm = &costPerKm
+	}
+
+	return &efficiency, nil
+}
+
+// CreateOdometerReading records an odometer reading; it fails with ErrOdometerDecreased
+// when the reading is below the vehicle's current odometer
+func (r *FuelRepository) CreateOdometerReading(ctx context.Context, reading *models.OdometerReading, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "FuelRepository.CreateOdometerReading",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", reading.VehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.advanceOdometer(ctx, tx, reading, companyID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit odometer reading: %w", err)
+	}
+
+	return nil
+}
+
+// ListOdometerReadings retrieves the odometer history of a vehicle, most recent first
+func (r *FuelRepository) ListOdometerReadings(ctx context.Context, vehicleID, companyID uuid.UUID, limit, offset int) ([]models.OdometerReading, error) {
+	ctx, span := r.tracer.Start(ctx, "FuelRepository.ListOdometerReadings",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", vehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	readings := []models.OdometerReading{}
+	query := `
+		SELECT o.id, o.vehicle_id, o.odometer_km, o.recorded_at, o.source, o.created_by, o.created_at
+		FROM odometer_readings o
+		JOIN vehicles v ON v.id = o.vehicle_id
+		WHERE o.vehicle_id = $1 AND v.company_id = $2 AND v.deleted_at IS NULL
+		ORDER BY o.recorded_at DESC, o.id
+		LIMIT $3 OFFSET $4
+	`
+
+	err := r.db.SelectContext(ctx, &readings, query, vehicleID, companyID, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to list odometer readings: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("odometer_reading.count", len(readings)))
+	return readings, nil
+}
+
+// advanceOdometer locks the vehicle, rejects readings below its current odometer, stores
+// the reading and moves the vehicle's odometer forward
+func (r *FuelRepository) advanceOdometer(ctx context.Context, tx *sqlx.Tx, reading *models.OdometerReading, companyID uuid.UUID) error {
+	var current sql.NullInt64
+	err := tx.GetContext(ctx, &current, `
+		SELECT odometer FROM vehicles
+		WHERE id = $1 AND company_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, reading.VehicleID, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("vehicle not found or not authorized")
+		}
+		return fmt.Errorf("failed to lock vehicle odometer: %w", err)
+	}
+
+	if current.Valid && int64(reading.OdometerKm) < current.Int64 {
+		return fmt.Errorf("%w (%d km < %d km)", ErrOdometerDecreased, reading.OdometerKm, current.Int64)
+	}
+
+	reading.ID = uuid.New()
+	reading.CreatedAt = time.Now()
+
+	_, err = tx.NamedExecContext(ctx, `
+		INSERT INTO odometer_readings (id, vehicle_id, odometer_km, recorded_at, source, created_by, created_at)
+		VALUES (:id, :vehicle_id, :odometer_km, :recorded_at, :source, :created_by, :created_at)
+	`, reading)
+	if err != nil {
+		return fmt.Errorf("failed to create odometer reading: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE vehicles SET odometer = $1, updated_at = NOW() WHERE id = $2`,
+		reading.OdometerKm, reading.VehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle odometer: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// IdempotencyRepositoryInterface defines the contract for idempotency key repository
+type IdempotencyRepositoryInterface interface {
+	Get(ctx context.Context, scope, key string) (*models.IdempotencyRecord, error)
+	Claim(ctx context.Context, record *models.IdempotencyRecord) (bool, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// IdempotencyRepository handles database operations for idempotency keys
+type IdempotencyRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:     db,
+		tracer: otel.Tracer("idempotency-repository"),
+	}
+}
+
+// Get returns the unexpired response stored for a key, or nil when there is none
+func (r *IdempotencyRepository) Get(ctx context.Context, scope, key string) (*models.IdempotencyRecord, error) {
+	ctx, span := r.tracer.Start(ctx, "IdempotencyRepository.Get")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var record models.IdempotencyRecord
+	query := `
+		SELECT scope, idempotency_key, request_hash, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND expires_at > NOW()
+	`
+
+	err := r.db.GetContext(ctx, &record, query, scope, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Claim reserves a key for a request about to run by inserting record as a pending row.
+// It returns false when an unexpired row already holds the key, whether its request is
+// still running or has completed; an expired row that was not purged yet is taken over.
+func (r *IdempotencyRepository) Claim(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "IdempotencyRepository.Claim")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	if record.ResponseBody == nil {
+		record.ResponseBody = []byte{}
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (
+			scope, idempotency_key, request_hash, status_code, content_type, response_body, created_at, expires_at
+		) VALUES (
+			:scope, :idempotency_key, :request_hash, :status_code, :content_type, :response_body, :created_at, :expires_at
+		)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = EXCLUDED.status_code,
+			content_type = EXCLUDED.content_type,
+			response_body = EXCLUDED.response_body,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, record)
+	if err != nil {
+		span.RecordError(err)
+		return false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	claimed, _ := result.RowsAffected()
+	span.SetAttributes(attribute.Bool("idempotency_key.claimed", claimed == 1))
+	return claimed == 1, nil
+}
+
+// Complete stores the response of a claimed key, so it is replayed until record.ExpiresAt
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	ctx, span := r.tracer.Start(ctx, "IdempotencyRepository.Complete",
+		trace.WithAttributes(attribute.Int("status_code", record.StatusCode)))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = :status_code, content_type = :content_type,
+			response_body = :response_body, expires_at = :expires_at
+		WHERE scope = :scope AND idempotency_key = :idempotency_key
+			AND request_hash = :request_hash AND status_code = 0
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, record); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release drops the claim on a key whose response is not stored, so a retry runs again
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	ctx, span := r.tracer.Start(ctx, "IdempotencyRepository.Release")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2 AND status_code = 0`
+	if _, err := r.db.ExecContext(ctx, query, scope, key); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the keys that expired before now and returns how many were removed
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "IdempotencyRepository.DeleteExpired")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+	span.SetAttributes(attribute.Int64("idempotency_keys.deleted", deleted))
+	return deleted, nil
+}
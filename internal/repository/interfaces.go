@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/paulochiaradia/dashtrack/internal/models"
@@ -11,28 +12,35 @@ import (
 type TeamRepositoryInterface interface {
 	Create(ctx context.Context, team *models.Team) error
 	GetByID(ctx context.Context, id uuid.UUID, companyID uuid.UUID) (*models.Team, error)
-	GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]models.Team, error)
+	GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, includeArchived bool) ([]models.Team, error)
+	CountByCompany(ctx context.Context, companyID uuid.UUID, includeArchived bool) (int, error)
 	Update(ctx context.Context, team *models.Team) error
+	SetStatus(ctx context.Context, id uuid.UUID, companyID uuid.UUID, status string) error
 	Delete(ctx context.Context, id uuid.UUID, companyID uuid.UUID) error
 	AddMember(ctx context.Context, teamMember *models.TeamMember) error
+	AddMembers(ctx context.Context, companyID uuid.UUID, members []*models.TeamMember) ([]bool, error)
 	RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error
 	GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error)
 	UpdateMemberRole(ctx context.Context, teamID, userID uuid.UUID, newRole string) error
-	GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]models.Team, error)
+	GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]models.UserTeam, error)
+	GetTeamsManagedBy(ctx context.Context, managerID uuid.UUID) ([]models.Team, error)
 	CheckMemberExists(ctx context.Context, teamID, userID uuid.UUID) (bool, error)
 	LogMemberChange(ctx context.Context, history *models.TeamMemberHistory) error
 	GetMemberHistory(ctx context.Context, teamID, companyID uuid.UUID, limit int) ([]models.TeamMemberHistory, error)
 	GetUserTeamHistory(ctx context.Context, userID, companyID uuid.UUID, limit int) ([]models.TeamMemberHistory, error)
 	GetMemberHistoryWithDetails(ctx context.Context, teamID, companyID uuid.UUID, limit int) ([]models.TeamMemberHistory, error)
 	GetUserTeamHistoryWithDetails(ctx context.Context, userID, companyID uuid.UUID, limit int) ([]models.TeamMemberHistory, error)
+	GetMembershipGrowth(ctx context.Context, companyID uuid.UUID, from, to time.Time, bucket string) ([]models.TeamGrowthBucket, error)
 }
 
 // VehicleRepositoryInterface defines the interface for vehicle repository operations
 type VehicleRepositoryInterface interface {
 	Create(ctx context.Context, vehicle *models.Vehicle) error
+	CreateBatch(ctx context.Context, vehicles []*models.Vehicle) error
 	GetByID(ctx context.Context, id uuid.UUID, companyID uuid.UUID) (*models.Vehicle, error)
 	GetByLicensePlate(ctx context.Context, licensePlate string, companyID uuid.UUID) (*models.Vehicle, error)
-	GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]models.Vehicle, error)
+	GetByCompany(ctx context.Context, companyID uuid.UUID, filter models.VehicleFilter, limit, offset int, sort Sort) ([]models.Vehicle, error)
+	CountByCompany(ctx context.Context, companyID uuid.UUID, filter models.VehicleFilter) (int, error)
 	GetByTeam(ctx context.Context, teamID uuid.UUID, companyID uuid.UUID) ([]models.Vehicle, error)
 	GetByDriver(ctx context.Context, driverID uuid.UUID, companyID uuid.UUID) ([]models.Vehicle, error)
 	Update(ctx context.Context, vehicle *models.Vehicle) error
@@ -40,6 +48,7 @@ type VehicleRepositoryInterface interface {
 	Delete(ctx context.Context, id uuid.UUID, companyID uuid.UUID) error
 	GetVehicleDashboardData(ctx context.Context, vehicleID, companyID uuid.UUID) (*models.VehicleDashboardData, error)
 	GetActiveTrip(ctx context.Context, vehicleID uuid.UUID) (*models.VehicleTrip, error)
+	GetActiveTripsByCompany(ctx context.Context, companyID uuid.UUID) ([]models.ActiveTrip, error)
 	Search(ctx context.Context, companyID uuid.UUID, searchTerm string, limit, offset int) ([]models.Vehicle, error)
 	CheckLicensePlateExists(ctx context.Context, licensePlate string, companyID uuid.UUID, excludeID *uuid.UUID) (bool, error)
 	LogAssignmentChange(ctx context.Context, history *models.VehicleAssignmentHistory) error
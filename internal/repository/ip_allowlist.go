@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+var (
+	// ErrIPAllowlistEntryExists is returned when the company already allows the range
+	ErrIPAllowlistEntryExists = errors.New("IP range is already in the allowlist")
+	// ErrIPAllowlistEntryNotFound is returned when the entry does not exist in the company
+	ErrIPAllowlistEntryNotFound = errors.New("IP allowlist entry not found")
+)
+
+// IPAllowlistRepositoryInterface defines the contract for company IP allowlist repository
+type IPAllowlistRepositoryInterface interface {
+	Create(ctx context.Context, entry *models.IPAllowlistEntry) error
+	GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.IPAllowlistEntry, error)
+	Delete(ctx context.Context, id, companyID uuid.UUID) error
+}
+
+// IPAllowlistRepository handles database operations for company IP allowlists
+type IPAllowlistRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewIPAllowlistRepository creates a new IP allowlist repository
+func NewIPAllowlistRepository(db *sqlx.DB) *IPAllowlistRepository {
+	return &IPAllowlistRepository{
+		db:     db,
+		tracer: otel.Tracer("ip-allowlist-repository"),
+	}
+}
+
+// Create adds an address range to a company's allowlist. The range must already be in
+// canonical form (no host bits set), as Postgres rejects other CIDR values.
+func (r *IPAllowlistRepository) Create(ctx context.Context, entry *models.IPAllowlistEntry) error {
+	ctx, span := r.tracer.Start(ctx, "IPAllowlistRepository.Create",
+		trace.WithAttributes(attribute.String("company.id", entry.CompanyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO company_ip_allowlist (id, company_id, cidr, description, created_by, created_at)
+		VALUES (:id, :company_id, :cidr, :description, :created_by, :created_at)
+		ON CONFLICT (company_id, cidr) DO NOTHING
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, entry)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to create IP allowlist entry: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrIPAllowlistEntryExists
+	}
+
+	return nil
+}
+
+// GetByCompany retrieves the allowlist of a company, oldest entry first
+func (r *IPAllowlistRepository) GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.IPAllowlistEntry, error) {
+	ctx, span := r.tracer.Start(ctx, "IPAllowlistRepository.GetByCompany",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	entries := []models.IPAllowlistEntry{}
+	query := `
+		SELECT id, company_id, cidr::text AS cidr, description, created_by, created_at
+		FROM company_ip_allowlist
+		WHERE company_id = $1
+		ORDER BY created_at, id
+	`
+
+	if err := r.db.SelectContext(ctx, &entries, query, companyID); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get IP allowlist: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("ip_allowlist.count", len(entries)))
+	return entries, nil
+}
+
+// Delete removes an entry from a company's allowlist
+func (r *IPAllowlistRepository) Delete(ctx context.Context, id, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "IPAllowlistRepository.Delete",
+		trace.WithAttributes(
+			attribute.String("ip_allowlist.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM company_ip_allowlist WHERE id = $1 AND company_id = $2`, id, companyID)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to delete IP allowlist entry: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrIPAllowlistEntryNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// anonymizedMetadataKeys are the audit metadata keys that can hold personal data
+var anonymizedMetadataKeys = []string{"ip_address", "user_agent", "email", "target_email"}
+
+// AnonymizedLogCounts reports how many log rows were anonymized
+type AnonymizedLogCounts struct {
+	AuthLogs  int64 `json:"auth_logs"`
+	AuditLogs int64 `json:"audit_logs"`
+}
+
+// AnonymizeUserLogs removes a user's personal data from auth_logs and audit_logs without
+// deleting any row, so counts and success rates per period are unchanged:
+//   - emails are replaced by utils.AnonymizeEmail, keeping distinct users distinct;
+//   - IP addresses and user agents are cleared;
+//   - audit metadata keys that can hold personal data are dropped, and the before/after
+//     changes of audit entries about the user are emptied.
+//
+// exec is usually a transaction so the anonymization is applied entirely or not at all.
+func AnonymizeUserLogs(ctx context.Context, exec sqlx.ExecerContext, userID uuid.UUID, email string) (*AnonymizedLogCounts, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	anonymized := utils.AnonymizeEmail(email)
+	counts := &AnonymizedLogCounts{}
+
+	// Failed attempts with the user's email are included even when no user_id was recorded
+	result, err := exec.ExecContext(ctx, `
+		UPDATE auth_logs SET email_attempt = $2, ip_address = NULL, user_agent = NULL
+		WHERE user_id = $1 OR LOWER(email_attempt) = LOWER($3)`,
+		userID, anonymized, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize auth logs: %w", err)
+	}
+	counts.AuthLogs, _ = result.RowsAffected()
+
+	// audit_logs.ip_address is NOT NULL, so it is blanked rather than nulled
+	result, err = exec.ExecContext(ctx, `
+		UPDATE audit_logs SET user_email = $2, ip_address = '', user_agent = NULL, metadata = metadata - $3::text[]
+		WHERE user_id = $1`,
+		userID, anonymized, pq.Array(anonymizedMetadataKeys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize audit logs: %w", err)
+	}
+	counts.AuditLogs, _ = result.RowsAffected()
+
+	if _, err := exec.ExecContext(ctx, `
+		UPDATE audit_logs SET changes = '{}'::jsonb, metadata = metadata - $2::text[]
+		WHERE resource = 'user' AND resource_id = $1`,
+		userID, pq.Array(anonymizedMetadataKeys)); err != nil {
+		return nil, fmt.Errorf("failed to anonymize audit entries about the user: %w", err)
+	}
+
+	return counts, nil
+}
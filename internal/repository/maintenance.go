@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// MaintenanceRepositoryInterface defines the contract for vehicle maintenance repository
+type MaintenanceRepositoryInterface interface {
+	Create(ctx context.Context, record *models.VehicleMaintenance) error
+	GetByID(ctx context.Context, id, vehicleID, companyID uuid.UUID) (*models.VehicleMaintenance, error)
+	ListByVehicle(ctx context.Context, vehicleID, companyID uuid.UUID, limit, offset int) ([]models.VehicleMaintenance, error)
+	Update(ctx context.Context, record *models.VehicleMaintenance, companyID uuid.UUID) error
+	GetDueSoon(ctx context.Context, companyID uuid.UUID, withinDays, withinKm int) ([]models.MaintenanceDue, error)
+}
+
+// MaintenanceRepository handles database operations for vehicle maintenance records.
+// Records have no company column; every query is scoped through the vehicle.
+type MaintenanceRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(db *sqlx.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{
+		db:     db,
+		tracer: otel.Tracer("maintenance-repository"),
+	}
+}
+
+// Create records a maintenance service; the caller must have checked the vehicle belongs to the company
+func (r *MaintenanceRepository) Create(ctx context.Context, record *models.VehicleMaintenance) error {
+	ctx, span := r.tracer.Start(ctx, "MaintenanceRepository.Create",
+		trace.WithAttributes(attribute.String("vehicle.id", record.VehicleID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	record.ID = uuid.New()
+	record.CreatedAt = time.Now()
+	record.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO vehicle_maintenance (
+			id, vehicle_id, type, performed_at, odometer_km, cost, notes,
+			next_service_date, next_service_odometer_km, created_by, created_at, updated_at
+		) VALUES (
+			:id, :vehicle_id, :type, :performed_at, :odometer_km, :cost, :notes,
+			:next_service_date, :next_service_odometer_km, :created_by, :created_at, :updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, record)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to create maintenance record: %w", err)
+	}
+
+	span.SetAttributes(attribute.String("maintenance.id", record.ID.String()))
+	return nil
+}
+
+// GetByID retrieves a maintenance record of a vehicle with company context
+func (r *MaintenanceRepository) GetByID(ctx context.Context, id, vehicleID, companyID uuid.UUID) (*models.VehicleMaintenance, error) {
+	ctx, span := r.tracer.Start(ctx, "MaintenanceRepository.GetByID",
+		trace.WithAttributes(
+			attribute.String("maintenance.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var record models.VehicleMaintenance
+	query := `
+		SELECT m.id, m.vehicle_id, m.type, m.performed_at, m.odometer_km, m.cost, m.notes,
+		       m.next_service_date, m.next_service_odometer_km, m.created_by, m.created_at, m.updated_at
+		FROM vehicle_maintenance m
+		JOIN vehicles v ON v.id = m.vehicle_id
+		WHERE m.id = $1 AND m.vehicle_id = $2 AND v.company_id = $3 AND v.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &record, query, id, vehicleID, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get maintenance record by ID: %w", err)
+	}
+
+	return &record, nil
+}
+
+// ListByVehicle retrieves the maintenance records of a vehicle, most recent first
+func (r *MaintenanceRepository) ListByVehicle(ctx context.Context, vehicleID, companyID uuid.UUID, limit, offset int) ([]models.VehicleMaintenance, error) {
+	ctx, span := r.tracer.Start(ctx, "MaintenanceRepository.ListByVehicle",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", vehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	records := []models.VehicleMaintenance{}
+	query := `
+		SELECT m.id, m.vehicle_id, m.type, m.performed_at, m.odometer_km, m.cost, m.notes,
+		       m.next_service_date, m.next_service_odometer_km, m.created_by, m.created_at, m.updated_at
+		FROM vehicle_maintenance m
+		JOIN vehicles v ON v.id = m.vehicle_id
+		WHERE m.vehicle_id = $1 AND v.company_id = $2 AND v.deleted_at IS NULL
+		ORDER BY m.performed_at DESC, m.id
+		LIMIT $3 OFFSET $4
+	`
+
+	err := r.db.SelectContext(ctx, &records, query, vehicleID, companyID, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to list maintenance records: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("maintenance.count", len(records)))
+	return records, nil
+}
+
+// Update updates a maintenance record of a vehicle that belongs to the company
+func (r *MaintenanceRepository) Update(ctx context.Context, record *models.VehicleMaintenance, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "MaintenanceRepository.Update",
+		trace.WithAttributes(attribute.String("maintenance.id", record.ID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	record.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE vehicle_maintenance m SET
+			type = $1,
+			performed_at = $2,
+			odometer_km = $3,
+			cost = $4,
+			notes = $5,
+			next_service_date = $6,
+			next_service_odometer_km = $7,
+			updated_at = $8
+		FROM vehicles v
+		WHERE m.id = $9 AND m.vehicle_id = $10 AND v.id = m.vehicle_id AND v.company_id = $11
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		record.Type, record.PerformedAt, record.OdometerKm, record.Cost, record.Notes,
+		record.NextServiceDate, record.NextServiceOdometerKm, record.UpdatedAt,
+		record.ID, record.VehicleID, companyID,
+	)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to update maintenance record: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("maintenance record not found or not authorized")
+	}
+
+	return nil
+}
+
+// GetDueSoon returns, for each vehicle and service type, the latest record whose next
+// service is due within withinDays days or withinKm km of the vehicle's odometer.
+// Overdue services are included with negative days/km remaining.
+func (r *MaintenanceRepository) GetDueSoon(ctx context.Context, companyID uuid.UUID, withinDays, withinKm int) ([]models.MaintenanceDue, error) {
+	ctx, span := r.tracer.Start(ctx, "MaintenanceRepository.GetDueSoon",
+		trace.WithAttributes(
+			attribute.String("company.id", companyID.String()),
+			attribute.Int("within.days", withinDays),
+			attribute.Int("within.km", withinKm),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	due := []models.MaintenanceDue{}
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (m.vehicle_id, m.type)
+			       m.vehicle_id, m.type, m.performed_at, m.next_service_date, m.next_service_odometer_km
+			FROM vehicle_maintenance m
+			JOIN vehicles v ON v.id = m.vehicle_id
+			WHERE v.company_id = $1 AND v.deleted_at IS NULL
+			ORDER BY m.vehicle_id, m.type, m.performed_at DESC
+		)
+		SELECT l.vehicle_id, v.license_plate, l.type, l.performed_at AS last_performed_at,
+		       l.next_service_date, l.next_service_odometer_km, v.odometer AS current_odometer_km,
+		       (l.next_service_date - CURRENT_DATE) AS days_remaining,
+		       (l.next_service_odometer_km - v.odometer) AS km_remaining
+		FROM latest l
+		JOIN vehicles v ON v.id = l.vehicle_id
+		WHERE (l.next_service_date IS NOT NULL AND l.next_service_date <= CURRENT_DATE + $2::int)
+		   OR (l.next_service_odometer_km IS NOT NULL AND v.odometer IS NOT NULL
+		       AND l.next_service_odometer_km - v.odometer <= $3)
+		ORDER BY days_remaining NULLS LAST, km_remaining NULLS LAST, v.license_plate
+	`
+
+	err := r.db.SelectContext(ctx, &due, query, companyID, withinDays, withinKm)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get maintenance due soon: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("maintenance.due_count", len(due)))
+	return due, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PasswordHistoryRepositoryInterface defines the contract for password history repository
+type PasswordHistoryRepositoryInterface interface {
+	GetRecentHashes(ctx context.Context, userID uuid.UUID, limit int) ([]string, error)
+	Add(ctx context.Context, userID uuid.UUID, passwordHash string, keep int) error
+}
+
+// PasswordHistoryRepository handles database operations for previous password hashes
+type PasswordHistoryRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewPasswordHistoryRepository creates a new password history repository
+func NewPasswordHistoryRepository(db *sqlx.DB) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		db:     db,
+		tracer: otel.Tracer("password-history-repository"),
+	}
+}
+
+// GetRecentHashes returns the most recent password hashes of a user, newest first
+func (r *PasswordHistoryRepository) GetRecentHashes(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "PasswordHistoryRepository.GetRecentHashes",
+		trace.WithAttributes(
+			attribute.String("user.id", userID.String()),
+			attribute.Int("limit", limit),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	hashes := []string{}
+	query := `
+		SELECT password_hash
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	if err := r.db.SelectContext(ctx, &hashes, query, userID, limit); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// Add records a previous password hash and trims the user's history to the
+// newest keep entries
+func (r *PasswordHistoryRepository) Add(ctx context.Context, userID uuid.UUID, passwordHash string, keep int) error {
+	ctx, span := r.tracer.Start(ctx, "PasswordHistoryRepository.Add",
+		trace.WithAttributes(
+			attribute.String("user.id", userID.String()),
+			attribute.Int("keep", keep),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO password_history (id, user_id, password_hash, created_at)
+		VALUES ($1, $2, $3, NOW())
+	`, uuid.New(), userID, passwordHash)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to add password history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2
+		)
+	`, userID, keep)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to trim password history: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit password history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ProfileExportRepositoryInterface defines the contract for profile export repository
+type ProfileExportRepositoryInterface interface {
+	TeamMemberships(ctx context.Context, userID uuid.UUID) (*ExportRows, error)
+	AssignedVehicles(ctx context.Context, userID uuid.UUID) (*ExportRows, error)
+	AuthHistory(ctx context.Context, userID uuid.UUID) (*ExportRows, error)
+	AuditEntries(ctx context.Context, userID uuid.UUID) (*ExportRows, error)
+}
+
+// ProfileExportRepository reads everything stored about a user for data portability
+// exports. Each method returns a cursor, so long histories are never held in memory.
+type ProfileExportRepository struct {
+	readReplica
+}
+
+// NewProfileExportRepository creates a new profile export repository
+func NewProfileExportRepository(db *sqlx.DB) *ProfileExportRepository {
+	return &ProfileExportRepository{readReplica: readReplica{primary: db}}
+}
+
+// SetReadReplica runs every export query against a read replica
+func (r *ProfileExportRepository) SetReadReplica(replica *sqlx.DB) {
+	r.replica = replica
+}
+
+// ExportRows iterates over the rows of a profile export section. The query timeout applies
+// until the cursor is closed, which the caller must do.
+type ExportRows struct {
+	rows   *sqlx.Rows
+	cancel context.CancelFunc
+}
+
+// Next advances to the next row, returning false at the end or on error
+func (e *ExportRows) Next() bool {
+	return e.rows.Next()
+}
+
+// Scan copies the current row into dest, a pointer to a struct with db tags
+func (e *ExportRows) Scan(dest interface{}) error {
+	return e.rows.StructScan(dest)
+}
+
+// Err returns the error that stopped the iteration, if any
+func (e *ExportRows) Err() error {
+	return e.rows.Err()
+}
+
+// Close releases the cursor's connection
+func (e *ExportRows) Close() error {
+	err := e.rows.Close()
+	e.cancel()
+	return err
+}
+
+// TeamMemberships returns the user's team memberships, including the ones they left, as
+// models.ProfileExportTeamMembership rows
+func (r *ProfileExportRepository) TeamMemberships(ctx context.Context, userID uuid.UUID) (*ExportRows, error) {
+	query := `
+		SELECT t.id AS team_id, t.name AS team_name, tm.role_in_team, tm.joined_at, tm.left_at
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		WHERE tm.user_id = $1
+		ORDER BY tm.joined_at`
+
+	ctx, cancel := withQueryTimeout(ctx)
+	rows, err := r.open(ctx, cancel, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team memberships: %w", err)
+	}
+	return rows, nil
+}
+
+// AssignedVehicles returns the vehicles the user drives or helps on as
+// models.ProfileExportVehicle rows
+func (r *ProfileExportRepository) AssignedVehicles(ctx context.Context, userID uuid.UUID) (*ExportRows, error) {
+	query := `
+		SELECT id AS vehicle_id, license_plate, brand, model, year,
+			CASE WHEN driver_id = $1 THEN 'driver' ELSE 'helper' END AS assignment
+		FROM vehicles
+		WHERE (driver_id = $1 OR helper_id = $1) AND deleted_at IS NULL
+		ORDER BY license_plate`
+
+	ctx, cancel := withQueryTimeout(ctx)
+	rows, err := r.open(ctx, cancel, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assigned vehicles: %w", err)
+	}
+	return rows, nil
+}
+
+// AuthHistory returns the user's login attempts as models.ProfileExportAuthEntry rows
+func (r *ProfileExportRepository) AuthHistory(ctx context.Context, userID uuid.UUID) (*ExportRows, error) {
+	query := `
+		SELECT id, success, ip_address, user_agent, failure_reason, country_code, created_at
+		FROM auth_logs
+		WHERE user_id = $1
+		ORDER BY created_at`
+
+	ctx, cancel := withQueryTimeout(ctx)
+	rows, err := r.open(ctx, cancel, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auth history: %w", err)
+	}
+	return rows, nil
+}
+
+// AuditEntries returns the audit log entries of the user's actions as
+// models.ProfileExportAuditEntry rows
+func (r *ProfileExportRepository) AuditEntries(ctx context.Context, userID uuid.UUID) (*ExportRows, error) {
+	query := `
+		SELECT id, action, resource, resource_id, ip_address, user_agent,
+			COALESCE(success, true) AS success, metadata, created_at
+		FROM audit_logs
+		WHERE user_id = $1
+		ORDER BY created_at`
+
+	ctx, cancel := withQueryTimeout(ctx)
+	rows, err := r.open(ctx, cancel, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	return rows, nil
+}
+
+// open runs query and returns a cursor that calls cancel, the query timeout's cancel, on Close
+func (r *ProfileExportRepository) open(ctx context.Context, cancel context.CancelFunc, query string, args ...interface{}) (*ExportRows, error) {
+	rows, err := r.reader().QueryxContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &ExportRows{rows: rows, cancel: cancel}, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+)
+
+// DefaultQueryTimeout bounds repository queries unless overridden with SetQueryTimeout
+const DefaultQueryTimeout = 30 * time.Second
+
+var (
+	queryTimeout       atomic.Int64
+	slowQueryThreshold atomic.Int64
+)
+
+func init() {
+	queryTimeout.Store(int64(DefaultQueryTimeout))
+}
+
+// SetQueryTimeout sets how long a repository method may spend on the database before its
+// context is cancelled. Zero or negative disables the limit; the caller's deadline still applies.
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout.Store(int64(timeout))
+}
+
+// QueryTimeout returns the configured repository query timeout
+func QueryTimeout() time.Duration {
+	return time.Duration(queryTimeout.Load())
+}
+
+// SetSlowQueryThreshold sets how long a repository method may take before it is logged as a
+// slow query. Zero or negative disables slow query logging, which is the default.
+func SetSlowQueryThreshold(threshold time.Duration) {
+	slowQueryThreshold.Store(int64(threshold))
+}
+
+// SlowQueryThreshold returns the configured slow query threshold
+func SlowQueryThreshold() time.Duration {
+	return time.Duration(slowQueryThreshold.Load())
+}
+
+// withQueryTimeout derives a context bounded by the query timeout from ctx. The timeout is
+// recorded on the span already in ctx, and the span is marked as failed when the deadline
+// is hit, so slow queries show up in traces. Calls slower than the slow query threshold are
+// also logged when the returned cancel runs, so it must be deferred.
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := boundQuery(ctx)
+
+	threshold := SlowQueryThreshold()
+	if threshold <= 0 {
+		return ctx, cancel
+	}
+
+	var caller [1]uintptr
+	runtime.Callers(2, caller[:])
+	start := time.Now()
+	return ctx, func() {
+		if elapsed := time.Since(start); elapsed >= threshold {
+			logSlowQuery(ctx, caller[0], elapsed, threshold)
+		}
+		cancel()
+	}
+}
+
+// boundQuery applies the query timeout to ctx and records it on the span in ctx
+func boundQuery(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := QueryTimeout()
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.query_timeout_ms", timeout.Milliseconds()))
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			span.SetAttributes(attribute.Bool("db.query_timed_out", true))
+			span.SetStatus(codes.Error, "query timeout exceeded")
+		}
+		cancel()
+	}
+}
+
+// logSlowQuery warns about a repository method that took longer than the threshold. The
+// query is named after the method, e.g. UserRepository.GetByID, matching its span.
+func logSlowQuery(ctx context.Context, pc uintptr, elapsed, threshold time.Duration) {
+	fields := []zap.Field{
+		zap.String("query", queryName(pc)),
+		zap.Duration("duration", elapsed),
+		zap.Duration("threshold", threshold),
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		fields = append(fields, zap.String("trace_id", spanCtx.TraceID().String()))
+	}
+	logger.Warn("Slow query", fields...)
+}
+
+// queryName turns the repository method at pc into Type.Method
+func queryName(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	name := frame.Function
+	if name == "" {
+		return "unknown"
+	}
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimPrefix(name, "repository.")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// readReplica is embedded by repositories whose read-only queries can be served by a
+// read replica. Each repository's SetReadReplica documents which of its queries use it.
+type readReplica struct {
+	primary *sqlx.DB
+	replica *sqlx.DB
+}
+
+// reader returns the replica when one is configured, the primary otherwise
+func (r *readReplica) reader() *sqlx.DB {
+	if r.replica != nil {
+		return r.replica
+	}
+	return r.primary
+}
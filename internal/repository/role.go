@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/paulochiaradia/dashtrack/internal/models"
@@ -11,6 +12,7 @@ import (
 // RoleRepositoryInterface defines the contract for role repository
 type RoleRepositoryInterface interface {
 	GetAll(ctx context.Context) ([]*models.Role, error)
+	List(ctx context.Context, filter models.RoleFilter) ([]*models.Role, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Role, error)
 }
 
@@ -26,6 +28,9 @@ func NewRoleRepository(db *sql.DB) *RoleRepository {
 
 // GetAll retrieves all roles
 func (r *RoleRepository) GetAll(ctx context.Context) ([]*models.Role, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, name, description, created_at, updated_at FROM roles ORDER BY name"
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -47,8 +52,68 @@ func (r *RoleRepository) GetAll(ctx context.Context) ([]*models.Role, error) {
 	return roles, rows.Err()
 }
 
+// List retrieves roles matching the filter, sorted by name then id so pages are stable
+func (r *RoleRepository) List(ctx context.Context, filter models.RoleFilter) ([]*models.Role, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT r.id, r.name, r.description, r.created_at, r.updated_at"
+	if filter.IncludePermissionCount {
+		query += ", (SELECT COUNT(*) FROM role_permissions rp WHERE rp.role_id = r.id) AS permission_count"
+	}
+	query += " FROM roles r WHERE 1=1"
+
+	args := []interface{}{}
+	argCount := 1
+
+	if filter.Search != "" {
+		query += fmt.Sprintf(" AND r.name ILIKE $%d", argCount)
+		args = append(args, "%"+filter.Search+"%")
+		argCount++
+	}
+
+	query += " ORDER BY r.name, r.id"
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, filter.Limit)
+		argCount++
+	}
+
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argCount)
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []*models.Role{}
+	for rows.Next() {
+		role := &models.Role{}
+		dest := []interface{}{&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt}
+		if filter.IncludePermissionCount {
+			var count int
+			role.PermissionCount = &count
+			dest = append(dest, role.PermissionCount)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
 // GetByID retrieves a role by ID
 func (r *RoleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, name, description, created_at, updated_at FROM roles WHERE id = $1"
 
 	role := &models.Role{}
@@ -63,6 +128,34 @@ func (r *RoleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Rol
 	return role, nil
 }
 
+// ListPermissions retrieves every permission granted to a role
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]models.RolePermission, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT r.name, rp.permission
+		FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		ORDER BY r.name, rp.permission`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := []models.RolePermission{}
+	for rows.Next() {
+		var permission models.RolePermission
+		if err := rows.Scan(&permission.RoleName, &permission.Permission); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
 // GetByName retrieves a role by name
 func (r *RoleRepository) GetByName(name string) (*models.Role, error) {
 	query := "SELECT id, name, description, created_at, updated_at FROM roles WHERE name = $1"
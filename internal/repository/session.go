@@ -37,6 +37,8 @@ func NewSessionRepository(db *sqlx.DB) *SessionRepository {
 func (r *SessionRepository) CountActiveSessions(ctx context.Context, companyID *uuid.UUID) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "SessionRepository.CountActiveSessions")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Count sessions from user_sessions table that are still active
 	query := `
@@ -69,6 +71,8 @@ func (r *SessionRepository) CountActiveSessions(ctx context.Context, companyID *
 func (r *SessionRepository) GetAverageSessionDuration(ctx context.Context, companyID *uuid.UUID, from, to time.Time) (float64, error) {
 	ctx, span := r.tracer.Start(ctx, "SessionRepository.GetAverageSessionDuration")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Calculate average session duration from user_sessions table
 	query := `
@@ -101,6 +105,8 @@ func (r *SessionRepository) GetAverageSessionDuration(ctx context.Context, compa
 func (r *SessionRepository) CountUserActiveSessions(ctx context.Context, userID uuid.UUID) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "SessionRepository.CountUserActiveSessions")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT COUNT(*)
@@ -124,6 +130,8 @@ func (r *SessionRepository) CountUserActiveSessions(ctx context.Context, userID
 func (r *SessionRepository) GetUserAverageSessionDuration(ctx context.Context, userID uuid.UUID, from, to time.Time) (float64, error) {
 	ctx, span := r.tracer.Start(ctx, "SessionRepository.GetUserAverageSessionDuration")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT COALESCE(AVG(EXTRACT(EPOCH FROM (COALESCE(revoked_at, expires_at) - created_at))/60), 0)
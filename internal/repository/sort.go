@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidSort is returned when a sort key or order is not allowed for a list
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortFields maps the public sort keys of a list to the SQL columns they order by.
+// Only these columns can ever reach an ORDER BY clause.
+type SortFields map[string]string
+
+// UserSortFields are the sort keys accepted by the user lists
+var UserSortFields = SortFields{
+	"name":       "u.name",
+	"email":      "u.email",
+	"created_at": "u.created_at",
+	"last_login": "u.last_login",
+}
+
+// VehicleSortFields are the sort keys accepted by the vehicle lists
+var VehicleSortFields = SortFields{
+	"license_plate": "license_plate",
+	"brand":         "brand",
+	"model":         "model",
+	"year":          "year",
+	"status":        "status",
+	"created_at":    "created_at",
+}
+
+// Sort is an ORDER BY built from SortFields.Parse. The zero value keeps the list's default order.
+type Sort struct {
+	column     string
+	descending bool
+}
+
+// Parse validates the sort key and order ("asc" or "desc", default "asc") of a list request.
+// An empty key returns the zero Sort.
+func (f SortFields) Parse(key, order string) (Sort, error) {
+	if key == "" {
+		if order != "" {
+			return Sort{}, fmt.Errorf("%w: order requires sort", ErrInvalidSort)
+		}
+		return Sort{}, nil
+	}
+
+	column, ok := f[key]
+	if !ok {
+		return Sort{}, fmt.Errorf("%w: sort must be one of %s", ErrInvalidSort, strings.Join(f.Keys(), ", "))
+	}
+
+	switch strings.ToLower(order) {
+	case "", "asc":
+		return Sort{column: column}, nil
+	case "desc":
+		return Sort{column: column, descending: true}, nil
+	default:
+		return Sort{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidSort)
+	}
+}
+
+// Keys returns the accepted sort keys in alphabetical order
+func (f SortFields) Keys() []string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// orderBy returns the ORDER BY expression for the sort, or defaultOrder for the zero Sort.
+// idColumn breaks ties so pagination stays stable; NULLs always come last.
+func (s Sort) orderBy(defaultOrder, idColumn string) string {
+	if s.column == "" {
+		return defaultOrder
+	}
+
+	direction := "ASC"
+	if s.descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, %s %s", s.column, direction, idColumn, direction)
+}
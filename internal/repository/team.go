@@ -17,19 +17,27 @@ import (
 
 // TeamRepository handles database operations for teams
 type TeamRepository struct {
-	db     *sqlx.DB
-	tracer trace.Tracer
+	db                *sqlx.DB
+	users             *UserRepository
+	tracer            trace.Tracer
+	softDeleteMembers bool
 }
 
 // NewTeamRepository creates a new team repository
 func NewTeamRepository(db *sqlx.DB) *TeamRepository {
 	return &TeamRepository{
 		db:     db,
+		users:  NewUserRepository(db),
 		tracer: otel.Tracer("team-repository"),
 	}
 }
 
-// Create creates a new team
+// SetSoftDeleteMembers makes RemoveMember stamp left_at instead of deleting the membership row
+func (r *TeamRepository) SetSoftDeleteMembers(enabled bool) {
+	r.softDeleteMembers = enabled
+}
+
+// Create creates a new team. Its manager, if any, is added as a member with the manager role.
 func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.Create",
 		trace.WithAttributes(
@@ -37,6 +45,8 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 			attribute.String("company.id", team.CompanyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	team.ID = uuid.New()
 	team.CreatedAt = time.Now()
@@ -45,6 +55,13 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 		team.Status = "active"
 	}
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO teams (
 			id, company_id, name, description, manager_id, status, created_at, updated_at
@@ -53,12 +70,22 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 		)
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, team)
+	_, err = tx.NamedExecContext(ctx, query, team)
 	if err != nil {
 		span.RecordError(err)
 		return fmt.Errorf("failed to create team: %w", err)
 	}
 
+	if err := r.syncManagerMembership(ctx, tx, team, nil); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit team: %w", err)
+	}
+
 	span.SetAttributes(attribute.String("team.id", team.ID.String()))
 	return nil
 }
@@ -71,10 +98,12 @@ func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID, companyID uu
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var team models.Team
 	query := `
-		SELECT id, company_id, name, description, manager_id, status, created_at, updated_at
+		SELECT id, company_id, name, description, manager_id, status, created_at, updated_at, deleted_at
 		FROM teams 
 		WHERE id = $1 AND company_id = $2
 	`
@@ -91,26 +120,30 @@ func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID, companyID uu
 	return &team, nil
 }
 
-// GetByCompany retrieves all teams for a company
-func (r *TeamRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]models.Team, error) {
+// GetByCompany retrieves the teams of a company that are not deleted. Archived teams are
+// left out unless includeArchived is set.
+func (r *TeamRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, includeArchived bool) ([]models.Team, error) {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.GetByCompany",
 		trace.WithAttributes(
 			attribute.String("company.id", companyID.String()),
 			attribute.Int("limit", limit),
 			attribute.Int("offset", offset),
+			attribute.Bool("include_archived", includeArchived),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var teams []models.Team
 	query := `
 		SELECT id, company_id, name, description, manager_id, status, created_at, updated_at
 		FROM teams 
-		WHERE company_id = $1 AND status != 'deleted'
-		ORDER BY created_at DESC
+		WHERE company_id = $1 AND deleted_at IS NULL AND ($4 OR status != 'archived')
+		ORDER BY created_at DESC, id DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	err := r.db.SelectContext(ctx, &teams, query, companyID, limit, offset)
+	err := r.db.SelectContext(ctx, &teams, query, companyID, limit, offset, includeArchived)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to get teams by company: %w", err)
@@ -120,14 +153,55 @@ func (r *TeamRepository) GetByCompany(ctx context.Context, companyID uuid.UUID,
 	return teams, nil
 }
 
-// Update updates a team
+// CountByCompany counts the teams of a company that GetByCompany would list
+func (r *TeamRepository) CountByCompany(ctx context.Context, companyID uuid.UUID, includeArchived bool) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "TeamRepository.CountByCompany",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM teams WHERE company_id = $1 AND deleted_at IS NULL AND ($2 OR status != 'archived')`
+	if err := r.db.GetContext(ctx, &count, query, companyID, includeArchived); err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to count teams by company: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update updates a team. When the manager changes, the membership moves with it: the new
+// manager becomes a member with the manager role and the previous one, if they were a
+// member as manager, leaves the team.
 func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.Update",
 		trace.WithAttributes(attribute.String("team.id", team.ID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	team.UpdatedAt = time.Now()
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previousManagerID *uuid.UUID
+	err = tx.GetContext(ctx, &previousManagerID,
+		`SELECT manager_id FROM teams WHERE id = $1 AND company_id = $2 FOR UPDATE`,
+		team.ID, team.CompanyID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("team not found or not authorized")
+	}
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to get team manager: %w", err)
+	}
+
 	query := `
 		UPDATE teams SET
 			name = :name,
@@ -138,7 +212,7 @@ func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
 		WHERE id = :id AND company_id = :company_id
 	`
 
-	result, err := r.db.NamedExecContext(ctx, query, team)
+	result, err := tx.NamedExecContext(ctx, query, team)
 	if err != nil {
 		span.RecordError(err)
 		return fmt.Errorf("failed to update team: %w", err)
@@ -149,9 +223,99 @@ func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
 		return fmt.Errorf("team not found or not authorized")
 	}
 
+	if err := r.syncManagerMembership(ctx, tx, team, previousManagerID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit team: %w", err)
+	}
 	return nil
 }
 
+// syncManagerMembership makes the team's manager a member with the manager role, and
+// removes the previous manager's membership if they were a member as manager. Each change
+// is logged to the member history.
+func (r *TeamRepository) syncManagerMembership(ctx context.Context, tx *sqlx.Tx, team *models.Team, previousManagerID *uuid.UUID) error {
+	if sameManager(previousManagerID, team.ManagerID) {
+		return nil
+	}
+
+	if previousManagerID != nil {
+		var role string
+		err := tx.GetContext(ctx, &role,
+			`SELECT role_in_team FROM team_members WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL FOR UPDATE`,
+			team.ID, *previousManagerID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get previous manager membership: %w", err)
+		}
+		if err == nil && role == models.TeamRoleManager {
+			query := `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL`
+			if r.softDeleteMembers {
+				query = `UPDATE team_members SET left_at = NOW(), updated_at = NOW() WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL`
+			}
+			if _, err := tx.ExecContext(ctx, query, team.ID, *previousManagerID); err != nil {
+				return fmt.Errorf("failed to remove previous manager membership: %w", err)
+			}
+			if err := r.insertHistory(ctx, tx, &models.TeamMemberHistory{
+				TeamID:             team.ID,
+				UserID:             *previousManagerID,
+				CompanyID:          team.CompanyID,
+				PreviousRoleInTeam: &role,
+				ChangeType:         "removed",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if team.ManagerID == nil {
+		return nil
+	}
+
+	var role string
+	err := tx.GetContext(ctx, &role,
+		`SELECT role_in_team FROM team_members WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL FOR UPDATE`,
+		team.ID, *team.ManagerID)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err := r.insertMember(ctx, tx, team.CompanyID, &models.TeamMember{
+			TeamID:     team.ID,
+			UserID:     *team.ManagerID,
+			RoleInTeam: models.TeamRoleManager,
+		})
+		return err
+	case err != nil:
+		return fmt.Errorf("failed to get manager membership: %w", err)
+	case role == models.TeamRoleManager:
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE team_members SET role_in_team = $1 WHERE team_id = $2 AND user_id = $3 AND left_at IS NULL`,
+		models.TeamRoleManager, team.ID, *team.ManagerID); err != nil {
+		return fmt.Errorf("failed to update manager membership: %w", err)
+	}
+	newRole := models.TeamRoleManager
+	return r.insertHistory(ctx, tx, &models.TeamMemberHistory{
+		TeamID:             team.ID,
+		UserID:             *team.ManagerID,
+		CompanyID:          team.CompanyID,
+		PreviousRoleInTeam: &role,
+		NewRoleInTeam:      &newRole,
+		ChangeType:         "role_changed",
+	})
+}
+
+func sameManager(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // Delete soft deletes a team
 func (r *TeamRepository) Delete(ctx context.Context, id uuid.UUID, companyID uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.Delete",
@@ -160,6 +324,8 @@ func (r *TeamRepository) Delete(ctx context.Context, id uuid.UUID, companyID uui
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE teams 
@@ -181,6 +347,38 @@ func (r *TeamRepository) Delete(ctx context.Context, id uuid.UUID, companyID uui
 	return nil
 }
 
+// SetStatus moves a team that is not deleted to status, e.g. to archive or restore it
+func (r *TeamRepository) SetStatus(ctx context.Context, id uuid.UUID, companyID uuid.UUID, status string) error {
+	ctx, span := r.tracer.Start(ctx, "TeamRepository.SetStatus",
+		trace.WithAttributes(
+			attribute.String("team.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+			attribute.String("team.status", status),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE teams
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND company_id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, status, id, companyID)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to update team status: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("team not found or not authorized")
+	}
+
+	return nil
+}
+
 // AddMember adds a user to a team
 func (r *TeamRepository) AddMember(ctx context.Context, teamMember *models.TeamMember) error {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.AddMember",
@@ -190,6 +388,8 @@ func (r *TeamRepository) AddMember(ctx context.Context, teamMember *models.TeamM
 			attribute.String("role", teamMember.RoleInTeam),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	teamMember.ID = uuid.New()
 	teamMember.JoinedAt = time.Now()
@@ -230,7 +430,79 @@ func (r *TeamRepository) AddMember(ctx context.Context, teamMember *models.TeamM
 	return nil
 }
 
-// RemoveMember removes a user from a team
+// AddMembers adds several users to a team of companyID in one transaction, logging an
+// "added" history row for each. Users who are already active members are left alone:
+// added[i] reports whether members[i] was inserted. Any other failure rolls back every
+// addition.
+func (r *TeamRepository) AddMembers(ctx context.Context, companyID uuid.UUID, members []*models.TeamMember) ([]bool, error) {
+	ctx, span := r.tracer.Start(ctx, "TeamRepository.AddMembers",
+		trace.WithAttributes(
+			attribute.String("company.id", companyID.String()),
+			attribute.Int("members.count", len(members)),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := make([]bool, len(members))
+	for i, member := range members {
+		added[i], err = r.insertMember(ctx, tx, companyID, member)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to commit team members: %w", err)
+	}
+	return added, nil
+}
+
+// insertMember adds a user to a team and logs the addition, unless the user is already an
+// active member; it reports whether the user was added
+func (r *TeamRepository) insertMember(ctx context.Context, db sqlx.ExtContext, companyID uuid.UUID, member *models.TeamMember) (bool, error) {
+	member.ID = uuid.New()
+	member.JoinedAt = time.Now()
+
+	query := `
+		INSERT INTO team_members (id, team_id, user_id, role_in_team, joined_at)
+		VALUES (:id, :team_id, :user_id, :role_in_team, :joined_at)
+		ON CONFLICT (team_id, user_id) WHERE left_at IS NULL DO NOTHING
+	`
+
+	result, err := sqlx.NamedExecContext(ctx, db, query, member)
+	if err != nil {
+		return false, fmt.Errorf("failed to add team member %s: %w", member.UserID, err)
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return false, nil
+	}
+
+	newRole := member.RoleInTeam
+	history := &models.TeamMemberHistory{
+		TeamID:        member.TeamID,
+		UserID:        member.UserID,
+		CompanyID:     companyID,
+		NewRoleInTeam: &newRole,
+		ChangeType:    "added",
+	}
+	if err := r.insertHistory(ctx, db, history); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveMember removes a user from a team. With soft delete enabled the membership row is
+// kept with left_at set, otherwise it is deleted and only the history records it.
 func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.RemoveMember",
 		trace.WithAttributes(
@@ -238,22 +510,26 @@ func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.U
 			attribute.String("user.id", userID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Get current member state before removal (for history)
 	var currentMember models.TeamMember
 	var companyID uuid.UUID
 	err := r.db.GetContext(ctx, &currentMember,
-		`SELECT tm.*, t.company_id 
-		 FROM team_members tm 
-		 JOIN teams t ON tm.team_id = t.id 
-		 WHERE tm.team_id = $1 AND tm.user_id = $2`,
+		`SELECT tm.id, tm.team_id, tm.user_id, tm.role_in_team, tm.joined_at
+		 FROM team_members tm
+		 WHERE tm.team_id = $1 AND tm.user_id = $2 AND tm.left_at IS NULL`,
 		teamID, userID)
 
 	if err == nil {
 		err = r.db.GetContext(ctx, &companyID, `SELECT company_id FROM teams WHERE id = $1`, teamID)
 	}
 
-	query := `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`
+	query := `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL`
+	if r.softDeleteMembers {
+		query = `UPDATE team_members SET left_at = NOW(), updated_at = NOW() WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL`
+	}
 
 	result, err2 := r.db.ExecContext(ctx, query, teamID, userID)
 	if err2 != nil {
@@ -286,11 +562,13 @@ func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.U
 	return nil
 }
 
-// GetMembers retrieves all members of a team
+// GetMembers retrieves the active members of a team
 func (r *TeamRepository) GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error) {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.GetMembers",
 		trace.WithAttributes(attribute.String("team.id", teamID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var members []models.TeamMember
 	query := `
@@ -298,7 +576,7 @@ func (r *TeamRepository) GetMembers(ctx context.Context, teamID uuid.UUID) ([]mo
 			   u.name, u.email, u.phone, u.active
 		FROM team_members tm
 		JOIN users u ON tm.user_id = u.id
-		WHERE tm.team_id = $1
+		WHERE tm.team_id = $1 AND tm.left_at IS NULL
 		ORDER BY tm.joined_at ASC
 	`
 
@@ -331,7 +609,8 @@ func (r *TeamRepository) GetMembers(ctx context.Context, teamID uuid.UUID) ([]mo
 	return members, nil
 }
 
-// UpdateMemberRole updates a team member's role
+// UpdateMemberRole updates a team member's role and logs the change to history, reading
+// the previous role and writing the new one in a single transaction
 func (r *TeamRepository) UpdateMemberRole(ctx context.Context, teamID, userID uuid.UUID, newRole string) error {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.UpdateMemberRole",
 		trace.WithAttributes(
@@ -340,67 +619,88 @@ func (r *TeamRepository) UpdateMemberRole(ctx context.Context, teamID, userID uu
 			attribute.String("new_role", newRole),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	// Get current role before update (for history)
-	var currentRole string
-	var companyID uuid.UUID
-	errRole := r.db.GetContext(ctx, &currentRole,
-		`SELECT role_in_team FROM team_members WHERE team_id = $1 AND user_id = $2`,
-		teamID, userID)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the membership so concurrent updates apply one after the other and each
+	// history row records the role it actually replaced
+	var current struct {
+		Role      string    `db:"role_in_team"`
+		CompanyID uuid.UUID `db:"company_id"`
+	}
+	err = tx.GetContext(ctx, &current, `
+		SELECT tm.role_in_team, t.company_id
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		WHERE tm.team_id = $1 AND tm.user_id = $2 AND tm.left_at IS NULL
+		FOR UPDATE OF tm
+	`, teamID, userID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("team member not found")
+	}
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to get member role: %w", err)
+	}
 
-	if errRole == nil {
-		errRole = r.db.GetContext(ctx, &companyID, `SELECT company_id FROM teams WHERE id = $1`, teamID)
+	if current.Role == newRole {
+		return nil
 	}
 
 	query := `
 		UPDATE team_members 
 		SET role_in_team = $1 
-		WHERE team_id = $2 AND user_id = $3
+		WHERE team_id = $2 AND user_id = $3 AND left_at IS NULL
 	`
 
-	result, err := r.db.ExecContext(ctx, query, newRole, teamID, userID)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, query, newRole, teamID, userID); err != nil {
 		span.RecordError(err)
 		return fmt.Errorf("failed to update member role: %w", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("team member not found")
+	previousRole := current.Role
+	history := &models.TeamMemberHistory{
+		TeamID:             teamID,
+		UserID:             userID,
+		CompanyID:          current.CompanyID,
+		PreviousRoleInTeam: &previousRole,
+		NewRoleInTeam:      &newRole,
+		ChangeType:         "role_changed",
 	}
-
-	// Log role change to history (only if role actually changed)
-	if errRole == nil && currentRole != newRole {
-		history := &models.TeamMemberHistory{
-			TeamID:             teamID,
-			UserID:             userID,
-			CompanyID:          companyID,
-			PreviousRoleInTeam: &currentRole,
-			NewRoleInTeam:      &newRole,
-			ChangeType:         "role_changed",
-		}
-
-		// Log history (non-critical)
-		if err := r.LogMemberChange(ctx, history); err != nil {
-			span.RecordError(fmt.Errorf("failed to log role change: %w", err))
-		}
+	if err := r.insertHistory(ctx, tx, history); err != nil {
+		span.RecordError(err)
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit member role: %w", err)
+	}
 	return nil
 }
 
-// GetTeamsByUser retrieves all teams a user belongs to
-func (r *TeamRepository) GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]models.Team, error) {
+// GetTeamsByUser retrieves all teams a user belongs to, with the user's role in each
+func (r *TeamRepository) GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]models.UserTeam, error) {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.GetTeamsByUser",
 		trace.WithAttributes(attribute.String("user.id", userID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	var teams []models.Team
+	var teams []models.UserTeam
 	query := `
-		SELECT t.id, t.company_id, t.name, t.description, t.manager_id, t.status, t.created_at, t.updated_at
+		SELECT t.id, t.company_id, t.name, t.description, t.manager_id, t.status, t.created_at, t.updated_at,
+			   tm.role_in_team, tm.joined_at
 		FROM teams t
 		JOIN team_members tm ON t.id = tm.team_id
-		WHERE tm.user_id = $1 AND t.status = 'active'
+		WHERE tm.user_id = $1 AND tm.left_at IS NULL AND t.status = 'active'
 		ORDER BY t.name ASC
 	`
 
@@ -414,6 +714,32 @@ func (r *TeamRepository) GetTeamsByUser(ctx context.Context, userID uuid.UUID) (
 	return teams, nil
 }
 
+// GetTeamsManagedBy retrieves the teams a user is the manager of
+func (r *TeamRepository) GetTeamsManagedBy(ctx context.Context, managerID uuid.UUID) ([]models.Team, error) {
+	ctx, span := r.tracer.Start(ctx, "TeamRepository.GetTeamsManagedBy",
+		trace.WithAttributes(attribute.String("manager.id", managerID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var teams []models.Team
+	query := `
+		SELECT id, company_id, name, description, manager_id, status, created_at, updated_at
+		FROM teams
+		WHERE manager_id = $1 AND deleted_at IS NULL AND status != 'archived'
+		ORDER BY name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &teams, query, managerID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get teams managed by user: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("teams.count", len(teams)))
+	return teams, nil
+}
+
 // CheckMemberExists checks if a user is already a member of a team
 func (r *TeamRepository) CheckMemberExists(ctx context.Context, teamID, userID uuid.UUID) (bool, error) {
 	ctx, span := r.tracer.Start(ctx, "TeamRepository.CheckMemberExists",
@@ -422,9 +748,11 @@ func (r *TeamRepository) CheckMemberExists(ctx context.Context, teamID, userID u
 			attribute.String("user.id", userID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var count int
-	query := `SELECT COUNT(*) FROM team_members WHERE team_id = $1 AND user_id = $2`
+	query := `SELECT COUNT(*) FROM team_members WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL`
 
 	err := r.db.GetContext(ctx, &count, query, teamID, userID)
 	if err != nil {
@@ -448,7 +776,19 @@ func (r *TeamRepository) LogMemberChange(ctx context.Context, history *models.Te
 			attribute.String("change.type", history.ChangeType),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
+	if err := r.insertHistory(ctx, r.db, history); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// insertHistory writes a membership history row, filling in its ID and timestamps
+func (r *TeamRepository) insertHistory(ctx context.Context, db sqlx.ExtContext, history *models.TeamMemberHistory) error {
 	history.ID = uuid.New()
 	history.ChangedAt = time.Now()
 	history.CreatedAt = time.Now()
@@ -469,12 +809,9 @@ func (r *TeamRepository) LogMemberChange(ctx context.Context, history *models.Te
 		)
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, history)
-	if err != nil {
-		span.RecordError(err)
+	if _, err := sqlx.NamedExecContext(ctx, db, query, history); err != nil {
 		return fmt.Errorf("failed to log member change: %w", err)
 	}
-
 	return nil
 }
 
@@ -486,6 +823,8 @@ func (r *TeamRepository) GetMemberHistory(ctx context.Context, teamID, companyID
 			attribute.Int("limit", limit),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if limit == 0 {
 		limit = 50 // Default limit
@@ -524,6 +863,8 @@ func (r *TeamRepository) GetUserTeamHistory(ctx context.Context, userID, company
 			attribute.Int("limit", limit),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if limit == 0 {
 		limit = 50
@@ -562,6 +903,8 @@ func (r *TeamRepository) GetMemberHistoryWithDetails(ctx context.Context, teamID
 			attribute.Int("limit", limit),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Get history first
 	history, err := r.GetMemberHistory(ctx, teamID, companyID, limit)
@@ -569,20 +912,22 @@ func (r *TeamRepository) GetMemberHistoryWithDetails(ctx context.Context, teamID
 		return nil, err
 	}
 
+	users, err := r.users.GetByIDs(ctx, historyUserIDs(history))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	// Populate user and team details for each history entry
 	for i := range history {
 		entry := &history[i]
 
-		// Load user
-		var user models.User
-		err := r.db.GetContext(ctx, &user, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.UserID)
-		if err == nil {
-			entry.User = &user
-		}
+		entry.User = users[entry.UserID]
+		entry.ChangedByUser = lookupUser(users, entry.ChangedByUserID)
 
 		// Load team
 		var team models.Team
-		err = r.db.GetContext(ctx, &team, `SELECT id, name, company_id FROM teams WHERE id = $1`, entry.TeamID)
+		err := r.db.GetContext(ctx, &team, `SELECT id, name, company_id FROM teams WHERE id = $1`, entry.TeamID)
 		if err == nil {
 			entry.Team = &team
 		}
@@ -604,15 +949,6 @@ func (r *TeamRepository) GetMemberHistoryWithDetails(ctx context.Context, teamID
 				entry.NewTeam = &newTeam
 			}
 		}
-
-		// Load changed by user
-		if entry.ChangedByUserID != nil {
-			var changedBy models.User
-			err = r.db.GetContext(ctx, &changedBy, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.ChangedByUserID)
-			if err == nil {
-				entry.ChangedByUser = &changedBy
-			}
-		}
 	}
 
 	return history, nil
@@ -626,6 +962,8 @@ func (r *TeamRepository) GetUserTeamHistoryWithDetails(ctx context.Context, user
 			attribute.Int("limit", limit),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Get history first
 	history, err := r.GetUserTeamHistory(ctx, userID, companyID, limit)
@@ -633,20 +971,22 @@ func (r *TeamRepository) GetUserTeamHistoryWithDetails(ctx context.Context, user
 		return nil, err
 	}
 
+	users, err := r.users.GetByIDs(ctx, historyUserIDs(history))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	// Populate details (same as GetMemberHistoryWithDetails)
 	for i := range history {
 		entry := &history[i]
 
-		// Load user
-		var user models.User
-		err := r.db.GetContext(ctx, &user, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.UserID)
-		if err == nil {
-			entry.User = &user
-		}
+		entry.User = users[entry.UserID]
+		entry.ChangedByUser = lookupUser(users, entry.ChangedByUserID)
 
 		// Load team
 		var team models.Team
-		err = r.db.GetContext(ctx, &team, `SELECT id, name, company_id FROM teams WHERE id = $1`, entry.TeamID)
+		err := r.db.GetContext(ctx, &team, `SELECT id, name, company_id FROM teams WHERE id = $1`, entry.TeamID)
 		if err == nil {
 			entry.Team = &team
 		}
@@ -668,16 +1008,54 @@ func (r *TeamRepository) GetUserTeamHistoryWithDetails(ctx context.Context, user
 				entry.NewTeam = &newTeam
 			}
 		}
+	}
+
+	return history, nil
+}
 
-		// Load changed by user
+// historyUserIDs collects the IDs of the members and actors named in history entries
+func historyUserIDs(history []models.TeamMemberHistory) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(history))
+	for _, entry := range history {
+		ids = append(ids, entry.UserID)
 		if entry.ChangedByUserID != nil {
-			var changedBy models.User
-			err = r.db.GetContext(ctx, &changedBy, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.ChangedByUserID)
-			if err == nil {
-				entry.ChangedByUser = &changedBy
-			}
+			ids = append(ids, *entry.ChangedByUserID)
 		}
 	}
+	return ids
+}
 
-	return history, nil
+// GetMembershipGrowth aggregates member additions and removals per time bucket.
+// bucket must be a date_trunc unit (day, week or month); transfers count on both sides.
+func (r *TeamRepository) GetMembershipGrowth(ctx context.Context, companyID uuid.UUID, from, to time.Time, bucket string) ([]models.TeamGrowthBucket, error) {
+	ctx, span := r.tracer.Start(ctx, "TeamRepository.GetMembershipGrowth",
+		trace.WithAttributes(
+			attribute.String("company.id", companyID.String()),
+			attribute.String("bucket", bucket),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT
+			date_trunc($4, h.changed_at) AS bucket,
+			COUNT(*) FILTER (WHERE h.change_type IN ('added', 'transferred_in')) AS added,
+			COUNT(*) FILTER (WHERE h.change_type IN ('removed', 'transferred_out')) AS removed
+		FROM team_member_history h
+		WHERE h.company_id = $1 AND h.changed_at >= $2 AND h.changed_at < $3
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	growth := []models.TeamGrowthBucket{}
+	err := r.db.SelectContext(ctx, &growth, query, companyID, from, to, bucket)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get membership growth: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("buckets.count", len(growth)))
+
+	return growth, nil
 }
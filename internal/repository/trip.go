@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// ErrTripNotActive is returned when ending a trip that is no longer active
+var ErrTripNotActive = errors.New("trip is not active")
+
+// tripColumns are the vehicle_trips columns scanned into models.VehicleTrip
+const tripColumns = `id, vehicle_id, driver_id, helper_id, start_location, end_location,
+			   start_latitude, start_longitude, end_latitude, end_longitude,
+			   start_time, end_time, distance_km, duration_minutes, fuel_consumption,
+			   status, notes, created_at, updated_at`
+
+// TripRepositoryInterface defines the contract for trip repository
+type TripRepositoryInterface interface {
+	GetActiveTrips(ctx context.Context, vehicleID uuid.UUID) ([]models.VehicleTrip, error)
+	ListVehiclesWithDuplicateActiveTrips(ctx context.Context) ([]uuid.UUID, error)
+	CloseTrips(ctx context.Context, ids []uuid.UUID, note string) (int64, error)
+	AutoCloseStaleTrips(ctx context.Context, startedBefore time.Time, note string) (int64, error)
+}
+
+// TripRepository handles database operations for vehicle trips
+type TripRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewTripRepository creates a new trip repository
+func NewTripRepository(db *sqlx.DB) *TripRepository {
+	return &TripRepository{
+		db:     db,
+		tracer: otel.Tracer("trip-repository"),
+	}
+}
+
+// AutoCloseStaleTrips marks every trip still active that started before startedBefore as
+// auto_closed, appending note to its notes. End time and duration are left unset since the
+// real end of the trip is unknown. It returns the number of trips closed.
+func (r *TripRepository) AutoCloseStaleTrips(ctx context.Context, startedBefore time.Time, note string) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.AutoCloseStaleTrips",
+		trace.WithAttributes(attribute.String("trip.started_before", startedBefore.Format(time.RFC3339))))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE vehicle_trips
+		SET status = 'auto_closed',
+			notes = CONCAT_WS(E'\n', notes, $1::text),
+			updated_at = NOW()
+		WHERE status = 'active' AND start_time < $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, note, startedBefore)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to auto-close stale trips: %w", err)
+	}
+
+	closed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("trip.closed_count", closed))
+	return closed, nil
+}
+
+// GetActiveTrips retrieves every active trip of a vehicle, most recent first. A vehicle
+// should have at most one; more than one means a missed end-trip event.
+func (r *TripRepository) GetActiveTrips(ctx context.Context, vehicleID uuid.UUID) ([]models.VehicleTrip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.GetActiveTrips",
+		trace.WithAttributes(attribute.String("vehicle.id", vehicleID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	trips := []models.VehicleTrip{}
+	query := `
+		SELECT id, vehicle_id, driver_id, helper_id, start_location, end_location,
+			   start_latitude, start_longitude, end_latitude, end_longitude,
+			   start_time, end_time, distance_km, duration_minutes, fuel_consumption,
+			   status, notes, created_at, updated_at
+		FROM vehicle_trips
+		WHERE vehicle_id = $1 AND status = 'active'
+		ORDER BY start_time DESC, id
+	`
+
+	err := r.db.SelectContext(ctx, &trips, query, vehicleID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get active trips: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("trip.count", len(trips)))
+	return trips, nil
+}
+
+// ListVehiclesWithDuplicateActiveTrips returns the vehicles with more than one active trip
+func (r *TripRepository) ListVehiclesWithDuplicateActiveTrips(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.ListVehiclesWithDuplicateActiveTrips")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	vehicleIDs := []uuid.UUID{}
+	query := `
+		SELECT vehicle_id
+		FROM vehicle_trips
+		WHERE status = 'active'
+		GROUP BY vehicle_id
+		HAVING COUNT(*) > 1
+	`
+
+	err := r.db.SelectContext(ctx, &vehicleIDs, query)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to list vehicles with duplicate active trips: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("vehicle.count", len(vehicleIDs)))
+	return vehicleIDs, nil
+}
+
+// CloseTrips marks the given active trips as auto_closed, appending note to their notes,
+// and returns how many were closed
+func (r *TripRepository) CloseTrips(ctx context.Context, ids []uuid.UUID, note string) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.CloseTrips",
+		trace.WithAttributes(attribute.Int("trip.count", len(ids))))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE vehicle_trips
+		SET status = 'auto_closed',
+			notes = CONCAT_WS(E'\n', notes, $1::text),
+			updated_at = NOW()
+		WHERE id = ANY($2) AND status = 'active'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, note, pq.Array(ids))
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to close trips: %w", err)
+	}
+
+	closed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return closed, nil
+}
+
+// GetByID retrieves a trip of a vehicle
+func (r *TripRepository) GetByID(ctx context.Context, id, vehicleID uuid.UUID) (*models.VehicleTrip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.GetByID",
+		trace.WithAttributes(attribute.String("trip.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var trip models.VehicleTrip
+	query := `SELECT ` + tripColumns + ` FROM vehicle_trips WHERE id = $1 AND vehicle_id = $2`
+
+	err := r.db.GetContext(ctx, &trip, query, id, vehicleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	return &trip, nil
+}
+
+// AddPoints stores a batch of GPS points for a trip with a single multi-row insert
+func (r *TripRepository) AddPoints(ctx context.Context, tripID uuid.UUID, points []models.TripPoint) error {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.AddPoints",
+		trace.WithAttributes(
+			attribute.String("trip.id", tripID.String()),
+			attribute.Int("points.count", len(points)),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(points))
+	args := make([]interface{}, 0, len(points)*5)
+	for i, point := range points {
+		n := i * 5
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args, tripID, point.Latitude, point.Longitude, point.Speed, point.RecordedAt)
+	}
+
+	query := `INSERT INTO trip_points (trip_id, latitude, longitude, speed, recorded_at) VALUES ` +
+		strings.Join(values, ", ")
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to add trip points: %w", err)
+	}
+
+	return nil
+}
+
+// ListPoints retrieves the GPS points of a trip in the order they were recorded
+func (r *TripRepository) ListPoints(ctx context.Context, tripID uuid.UUID) ([]models.TripPoint, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.ListPoints",
+		trace.WithAttributes(attribute.String("trip.id", tripID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	return r.listPoints(ctx, r.db, tripID)
+}
+
+func (r *TripRepository) listPoints(ctx context.Context, db sqlx.QueryerContext, tripID uuid.UUID) ([]models.TripPoint, error) {
+	points := []models.TripPoint{}
+	query := `
+		SELECT id, trip_id, latitude, longitude, speed, recorded_at, created_at
+		FROM trip_points
+		WHERE trip_id = $1
+		ORDER BY recorded_at, id
+	`
+
+	if err := sqlx.SelectContext(ctx, db, &points, query, tripID); err != nil {
+		return nil, fmt.Errorf("failed to list trip points: %w", err)
+	}
+
+	return points, nil
+}
+
+// EndTrip completes an active trip. Without a distance in req, the distance is computed
+// from the trip's recorded points, if it has at least two.
+func (r *TripRepository) EndTrip(ctx context.Context, id uuid.UUID, req models.EndTripRequest) (*models.VehicleTrip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.EndTrip",
+		trace.WithAttributes(attribute.String("trip.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	distanceKm := req.DistanceKm
+	if distanceKm == nil {
+		points, err := r.listPoints(ctx, tx, id)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		if len(points) > 1 {
+			distance := models.TripPointsDistanceKm(points)
+			distanceKm = &distance
+			span.SetAttributes(attribute.Bool("trip.distance_from_points", true))
+		}
+	}
+
+	var trip models.VehicleTrip
+	query := `
+		UPDATE vehicle_trips
+		SET status = 'completed',
+			end_time = NOW(),
+			duration_minutes = CEIL(EXTRACT(EPOCH FROM (NOW() - start_time)) / 60),
+			end_location = COALESCE($2, end_location),
+			end_latitude = COALESCE($3, end_latitude),
+			end_longitude = COALESCE($4, end_longitude),
+			distance_km = COALESCE($5, distance_km),
+			fuel_consumption = COALESCE($6, fuel_consumption),
+			notes = COALESCE($7, notes),
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'active'
+		RETURNING ` + tripColumns
+
+	err = tx.GetContext(ctx, &trip, query, id, req.EndLocation, req.EndLatitude, req.EndLongitude,
+		distanceKm, req.FuelConsumption, req.Notes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTripNotActive
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to end trip: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &trip, nil
+}
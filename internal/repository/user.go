@@ -4,54 +4,74 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // UserRepositoryInterface defines the contract for user repository
 type UserRepositoryInterface interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
-	GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]*models.User, error)
+	GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, sort Sort) ([]*models.User, error)
 	Update(ctx context.Context, id uuid.UUID, updateReq models.UpdateUserRequest) (*models.User, error)
 	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	UpgradePasswordHash(ctx context.Context, id uuid.UUID, currentHash, upgradedHash string) error
 	UpdateCompany(ctx context.Context, userID, companyID uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID) ([]*models.User, error)
-	ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int) ([]*models.User, error)
+	RequestErasure(ctx context.Context, id uuid.UUID) error
+	PurgeUser(ctx context.Context, id uuid.UUID) (*models.UserPurgeResult, error)
+	List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID, sort Sort) ([]*models.User, error)
+	ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int, sort Sort) ([]*models.User, error)
 	ListByRoles(ctx context.Context, roles []string, limit, offset int) ([]*models.User, error)
 	CountByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string) (int, error)
 	UpdateLoginAttempts(ctx context.Context, id uuid.UUID, attempts int, blockedUntil *time.Time) error
+	IncrementLoginAttempts(ctx context.Context, id uuid.UUID, maxAttempts int, blockUntil time.Time) (*models.LoginAttempts, error)
 	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+	GetLastActivity(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserActivity, error)
 	GetUserContext(ctx context.Context, userID uuid.UUID) (*models.UserContext, error)
-	Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int) ([]*models.User, error)
+	Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int, sort Sort) ([]*models.User, error)
 	CountUsers(ctx context.Context, companyID *uuid.UUID) (int, error)
 	CountActiveUsers(ctx context.Context, companyID *uuid.UUID) (int, error)
+	ExistsByCPF(ctx context.Context, companyID *uuid.UUID, cpf string, excludeUserID *uuid.UUID) (bool, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	UpdatePreferences(ctx context.Context, id uuid.UUID, req models.UpdatePreferencesRequest) (*models.UserPreferences, error)
 }
 
 // UserRepository handles user database operations
 type UserRepository struct {
-	db     *sqlx.DB
+	db *sqlx.DB
+	readReplica
 	tracer trace.Tracer
 }
 
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *sqlx.DB) *UserRepository {
 	return &UserRepository{
-		db:     db,
-		tracer: otel.Tracer("user-repository"),
+		db:          db,
+		readReplica: readReplica{primary: db},
+		tracer:      otel.Tracer("user-repository"),
 	}
 }
 
+// SetReadReplica routes listing, search and count queries to a read replica.
+// Lookups used by writes and guards (GetByID, ExistsByCPF, role checks) stay on the primary.
+func (r *UserRepository) SetReadReplica(replica *sqlx.DB) {
+	r.replica = replica
+}
+
 // Create inserts a new user into the database
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Create",
@@ -60,6 +80,8 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 			attribute.String("user.name", user.Name),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if user.CompanyID != nil {
 		span.SetAttributes(attribute.String("company.id", user.CompanyID.String()))
@@ -99,6 +121,8 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	ctx, span := r.tracer.Start(ctx, "UserRepository.GetByID",
 		trace.WithAttributes(attribute.String("user.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT u.id, u.name, u.email, u.password, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
@@ -147,11 +171,137 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	return user, nil
 }
 
+// GetByIDs retrieves several users with role information in a single query, keyed by ID.
+// Like GetByID it skips soft-deleted users; IDs with no active user are left out of the map.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.GetByIDs",
+		trace.WithAttributes(attribute.Int("users.count", len(ids))))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	users := make(map[uuid.UUID]*models.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	query := `
+		SELECT u.id, u.name, u.email, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
+		       u.active, u.last_login, u.dashboard_config, u.login_attempts,
+		       u.blocked_until, u.password_changed_at, u.created_at, u.updated_at,
+		       r.id, r.name, r.description, r.created_at, r.updated_at
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		WHERE u.id = ANY($1) AND u.deleted_at IS NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &models.User{Role: &models.Role{}}
+		err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Email,
+			&user.Phone,
+			&user.CPF,
+			&user.Avatar,
+			&user.RoleID,
+			&user.CompanyID,
+			&user.Active,
+			&user.LastLogin,
+			&user.DashboardConfig,
+			&user.LoginAttempts,
+			&user.BlockedUntil,
+			&user.PasswordChangedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.Role.ID,
+			&user.Role.Name,
+			&user.Role.Description,
+			&user.Role.CreatedAt,
+			&user.Role.UpdatedAt,
+		)
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users[user.ID] = user
+	}
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("users.found", len(users)))
+	return users, nil
+}
+
+// GetByAPIToken retrieves the active user owning an API token, with role information
+func (r *UserRepository) GetByAPIToken(ctx context.Context, token string) (*models.User, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.GetByAPIToken")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT u.id, u.name, u.email, u.password, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
+		       u.active, u.last_login, u.dashboard_config, u.api_token, u.login_attempts,
+		       u.blocked_until, u.password_changed_at, u.created_at, u.updated_at,
+		       r.id, r.name, r.description, r.created_at, r.updated_at
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		WHERE u.api_token = $1 AND u.active = true AND u.deleted_at IS NULL`
+
+	user := &models.User{Role: &models.Role{}}
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Password,
+		&user.Phone,
+		&user.CPF,
+		&user.Avatar,
+		&user.RoleID,
+		&user.CompanyID,
+		&user.Active,
+		&user.LastLogin,
+		&user.DashboardConfig,
+		&user.APIToken,
+		&user.LoginAttempts,
+		&user.BlockedUntil,
+		&user.PasswordChangedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Role.ID,
+		&user.Role.Name,
+		&user.Role.Description,
+		&user.Role.CreatedAt,
+		&user.Role.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get user by API token: %w", err)
+	}
+
+	return user, nil
+}
+
 // GetByEmail retrieves a user by email with role information
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.GetByEmail",
 		trace.WithAttributes(attribute.String("user.email", email)))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT u.id, u.name, u.email, u.password, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
@@ -201,7 +351,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 }
 
 // GetByCompany retrieves all users for a specific company
-func (r *UserRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]*models.User, error) {
+func (r *UserRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, sort Sort) ([]*models.User, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.GetByCompany",
 		trace.WithAttributes(
 			attribute.String("company.id", companyID.String()),
@@ -209,6 +359,8 @@ func (r *UserRepository) GetByCompany(ctx context.Context, companyID uuid.UUID,
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT u.id, u.name, u.email, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
@@ -218,10 +370,10 @@ func (r *UserRepository) GetByCompany(ctx context.Context, companyID uuid.UUID,
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
 		WHERE u.company_id = $1 AND u.active = true
-		ORDER BY u.created_at DESC
+		ORDER BY ` + sort.orderBy("u.created_at DESC, u.id DESC", "u.id") + `
 		LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.QueryContext(ctx, query, companyID, limit, offset)
+	rows, err := r.reader().QueryContext(ctx, query, companyID, limit, offset)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to get users by company: %w", err)
@@ -270,6 +422,8 @@ func (r *UserRepository) Update(ctx context.Context, id uuid.UUID, updateReq mod
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Update",
 		trace.WithAttributes(attribute.String("user.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	updates := []string{}
 	args := []interface{}{}
@@ -346,6 +500,8 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashe
 	ctx, span := r.tracer.Start(ctx, "UserRepository.UpdatePassword",
 		trace.WithAttributes(attribute.String("user.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users 
@@ -361,6 +517,27 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashe
 	return nil
 }
 
+// UpgradePasswordHash replaces the user's password hash with a stronger hash of the same
+// password. password_changed_at is left alone, and nothing is written when the stored hash
+// is no longer currentHash, so a password changed meanwhile is never overwritten.
+func (r *UserRepository) UpgradePasswordHash(ctx context.Context, id uuid.UUID, currentHash, upgradedHash string) error {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.UpgradePasswordHash",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND password = $4`
+
+	_, err := r.db.ExecContext(ctx, query, upgradedHash, time.Now(), id, currentHash)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to upgrade password hash: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateCompany updates a user's company (Master only operation)
 func (r *UserRepository) UpdateCompany(ctx context.Context, userID, companyID uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.UpdateCompany",
@@ -369,6 +546,8 @@ func (r *UserRepository) UpdateCompany(ctx context.Context, userID, companyID uu
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users 
@@ -393,11 +572,126 @@ func (r *UserRepository) UpdateCompany(ctx context.Context, userID, companyID uu
 	return nil
 }
 
+// UpdatePreferences applies the supplied preference changes and returns the resulting
+// preferences, or nil when the user does not exist
+func (r *UserRepository) UpdatePreferences(ctx context.Context, id uuid.UUID, req models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.UpdatePreferences",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE users
+		SET notify_new_session = COALESCE($1, notify_new_session),
+			notify_assignment_changes = COALESCE($2, notify_assignment_changes),
+			updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL
+		RETURNING notify_new_session, notify_assignment_changes`
+
+	var prefs models.UserPreferences
+	err := r.db.GetContext(ctx, &prefs, query, req.NotifyNewSession, req.NotifyAssignmentChanges, time.Now(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to update user preferences: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+// GetAssignmentRecipients retrieves the name, email and assignment notification preference
+// of the active users among ids
+func (r *UserRepository) GetAssignmentRecipients(ctx context.Context, ids []uuid.UUID) ([]models.AssignmentRecipient, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.GetAssignmentRecipients",
+		trace.WithAttributes(attribute.Int("users.count", len(ids))))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, name, email, notify_assignment_changes
+		FROM users
+		WHERE id = ANY($1) AND active = true AND deleted_at IS NULL`
+
+	var recipients []models.AssignmentRecipient
+	if err := r.db.SelectContext(ctx, &recipients, query, pq.Array(ids)); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get assignment recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// ReassignCompany moves every non-deleted user of a company to another company,
+// returning how many users were moved
+func (r *UserRepository) ReassignCompany(ctx context.Context, fromCompanyID, toCompanyID uuid.UUID) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.ReassignCompany",
+		trace.WithAttributes(
+			attribute.String("company.from_id", fromCompanyID.String()),
+			attribute.String("company.to_id", toCompanyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE users
+		SET company_id = $1, updated_at = $2
+		WHERE company_id = $3 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, toCompanyID, time.Now(), fromCompanyID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to reassign company users: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("users.count", rowsAffected))
+	return rowsAffected, nil
+}
+
+// DeactivateByCompany deactivates every active user of a company, returning how many
+// users were deactivated
+func (r *UserRepository) DeactivateByCompany(ctx context.Context, companyID uuid.UUID) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.DeactivateByCompany",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE users
+		SET active = false, updated_at = $1
+		WHERE company_id = $2 AND active = true AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), companyID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to deactivate company users: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("users.count", rowsAffected))
+	return rowsAffected, nil
+}
+
 // Delete soft deletes a user (sets active = false)
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Delete",
 		trace.WithAttributes(attribute.String("user.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `UPDATE users SET deleted_at = $1, updated_at = $1 WHERE id = $2`
 
@@ -410,14 +704,127 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// purgedUserCredentialTables hold sessions and credentials of a user; PurgeUser deletes their rows
+var purgedUserCredentialTables = []string{
+	"user_sessions", "session_tokens", "password_reset_tokens", "password_history", "two_factor_auth",
+}
+
+// PurgeUser irreversibly erases a user's personal data in one transaction. The user row is
+// kept, so history that references it (trips, team history, vehicle assignments) still counts
+// the user, but every personal field is cleared:
+//   - users: name becomes "Deleted user", email is replaced by utils.AnonymizeEmail, and
+//     password, phone, CPF, avatar, API token and dashboard config are cleared; the account
+//     is deactivated, soft deleted and marked erased;
+//   - auth_logs and audit_logs: anonymized with AnonymizeUserLogs, no row is deleted;
+//   - user_sessions, session_tokens, password_reset_tokens, password_history and
+//     two_factor_auth: the user's rows are deleted.
+//
+// The stored avatar file is not touched; its URL is returned in the result for the caller
+// to delete. Returns nil, nil when the user does not exist.
+func (r *UserRepository) PurgeUser(ctx context.Context, id uuid.UUID) (*models.UserPurgeResult, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.PurgeUser",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var user struct {
+		Email  string  `db:"email"`
+		Avatar *string `db:"avatar"`
+	}
+	err = tx.GetContext(ctx, &user, `SELECT email, avatar FROM users WHERE id = $1 FOR UPDATE`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	logs, err := AnonymizeUserLogs(ctx, tx, id, user.Email)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := &models.UserPurgeResult{UserID: id, AuthLogs: logs.AuthLogs, AuditLogs: logs.AuditLogs, Avatar: user.Avatar}
+	for _, table := range purgedUserCredentialTables {
+		res, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, table), id)
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+		if table == "user_sessions" || table == "session_tokens" {
+			deleted, _ := res.RowsAffected()
+			result.SessionsDeleted += deleted
+		}
+	}
+
+	query := `
+		UPDATE users SET
+			name = 'Deleted user', email = $2, password = '',
+			phone = NULL, cpf = NULL, avatar = NULL, api_token = NULL, dashboard_config = NULL,
+			last_login = NULL, login_attempts = 0, blocked_until = NULL, active = false,
+			deleted_at = COALESCE(deleted_at, NOW()), erased_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, utils.AnonymizeEmail(user.Email)); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to commit user purge: %w", err)
+	}
+
+	return result, nil
+}
+
+// RequestErasure records an account erasure request: the user is soft deleted and
+// deactivated, and their log data is anonymized later by the erasure job
+func (r *UserRepository) RequestErasure(ctx context.Context, id uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.RequestErasure",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE users
+		SET erasure_requested_at = $1, deleted_at = $1, active = false, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to request user erasure: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
 // List retrieves users with optional filters
-func (r *UserRepository) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID) ([]*models.User, error) {
+func (r *UserRepository) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID, sort Sort) ([]*models.User, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.List",
 		trace.WithAttributes(
 			attribute.Int("limit", limit),
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	whereConditions := []string{"u.deleted_at IS NULL"} // Always exclude soft-deleted users
 	args := []interface{}{}
@@ -450,12 +857,12 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int, active *bo
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
 		%s
-		ORDER BY u.created_at DESC
-		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, whereClause, sort.orderBy("u.created_at DESC, u.id DESC", "u.id"), argIndex, argIndex+1)
 
 	args = append(args, limit, offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to list users: %w", err)
@@ -507,6 +914,8 @@ func (r *UserRepository) UpdateLoginAttempts(ctx context.Context, id uuid.UUID,
 			attribute.Int("attempts", attempts),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `UPDATE users SET login_attempts = $1, blocked_until = $2, updated_at = $3 WHERE id = $4`
 
@@ -519,11 +928,82 @@ func (r *UserRepository) UpdateLoginAttempts(ctx context.Context, id uuid.UUID,
 	return nil
 }
 
+// IncrementLoginAttempts records a failed login in a single statement, so parallel
+// failures are all counted. Reaching maxAttempts blocks the user until blockUntil, unless
+// a block is already running.
+func (r *UserRepository) IncrementLoginAttempts(ctx context.Context, id uuid.UUID, maxAttempts int, blockUntil time.Time) (*models.LoginAttempts, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.IncrementLoginAttempts",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		UPDATE users SET
+			login_attempts = login_attempts + 1,
+			blocked_until = CASE
+				WHEN login_attempts + 1 >= $2 AND (blocked_until IS NULL OR blocked_until <= $4) THEN $3
+				ELSE blocked_until
+			END,
+			updated_at = $4
+		WHERE id = $1
+		RETURNING login_attempts, blocked_until, blocked_until IS NOT DISTINCT FROM $3 AS newly_blocked
+	`
+
+	var result models.LoginAttempts
+	err := r.db.QueryRowContext(ctx, query, id, maxAttempts, blockUntil, time.Now()).
+		Scan(&result.Attempts, &result.BlockedUntil, &result.NewlyBlocked)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to increment login attempts: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("attempts", result.Attempts))
+	return &result, nil
+}
+
+// GetLastActivity returns when each of the users last used a session, from
+// session_tokens.last_used_at. Users that never logged in are left out of the map.
+func (r *UserRepository) GetLastActivity(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserActivity, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.GetLastActivity",
+		trace.WithAttributes(attribute.Int("users.count", len(userIDs))))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	activity := make(map[uuid.UUID]models.UserActivity, len(userIDs))
+	if len(userIDs) == 0 {
+		return activity, nil
+	}
+
+	query := `
+		SELECT user_id,
+			MAX(last_used_at) AS last_activity_at,
+			MAX(last_used_at) FILTER (WHERE NOT revoked AND refresh_expires_at > NOW()) AS active_session_used_at
+		FROM session_tokens
+		WHERE user_id = ANY($1)
+		GROUP BY user_id
+	`
+
+	var rows []models.UserActivity
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(userIDs)); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get last activity: %w", err)
+	}
+
+	for _, row := range rows {
+		activity[row.UserID] = row
+	}
+	return activity, nil
+}
+
 // UpdateLastLogin updates the last_login timestamp for a user
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.UpdateLastLogin",
 		trace.WithAttributes(attribute.String("user.id", id.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	now := time.Now()
 	query := `UPDATE users SET last_login = $1, updated_at = $2 WHERE id = $3`
@@ -542,6 +1022,8 @@ func (r *UserRepository) GetUserContext(ctx context.Context, userID uuid.UUID) (
 	ctx, span := r.tracer.Start(ctx, "UserRepository.GetUserContext",
 		trace.WithAttributes(attribute.String("user.id", userID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		SELECT u.id, u.company_id, r.name as role_name
@@ -572,8 +1054,28 @@ func (r *UserRepository) GetUserContext(ctx context.Context, userID uuid.UUID) (
 	return &userContext, nil
 }
 
-// Search searches users by name or email
-func (r *UserRepository) Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int) ([]*models.User, error) {
+// numericSearchTerm matches search terms made only of digits and phone/CPF punctuation
+var numericSearchTerm = regexp.MustCompile(`^[\d\s().+\-/]+$`)
+
+// minSearchDigits keeps one or two digit terms from matching nearly every phone number
+const minSearchDigits = 3
+
+// searchDigits returns the digits of a search term that looks like a phone number or CPF
+// ("(11) 98765-4321", "123.456"), or "" when the term should only match names and emails
+func searchDigits(term string) string {
+	if !numericSearchTerm.MatchString(term) {
+		return ""
+	}
+	digits := utils.NormalizeCPF(term)
+	if len(digits) < minSearchDigits {
+		return ""
+	}
+	return digits
+}
+
+// Search searches users by name or email and, when the term looks numeric, by the digits of
+// their phone or CPF
+func (r *UserRepository) Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int, sort Sort) ([]*models.User, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Search",
 		trace.WithAttributes(
 			attribute.String("search_term", searchTerm),
@@ -581,12 +1083,25 @@ func (r *UserRepository) Search(ctx context.Context, companyID *uuid.UUID, searc
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	searchPattern := "%" + strings.ToLower(searchTerm) + "%"
-	whereConditions := []string{"(LOWER(u.name) LIKE $1 OR LOWER(u.email) LIKE $1)", "u.active = true"}
+	matchConditions := []string{"LOWER(u.name) LIKE $1", "LOWER(u.email) LIKE $1"}
 	args := []interface{}{searchPattern}
 	argIndex := 2
 
+	// Support agents identify drivers by phone or CPF, typed with any punctuation
+	if digits := searchDigits(searchTerm); digits != "" {
+		matchConditions = append(matchConditions,
+			fmt.Sprintf(`regexp_replace(u.phone, '\D', '', 'g') LIKE $%d`, argIndex),
+			fmt.Sprintf("u.cpf LIKE $%d", argIndex))
+		args = append(args, "%"+digits+"%")
+		argIndex++
+	}
+
+	whereConditions := []string{"(" + strings.Join(matchConditions, " OR ") + ")", "u.active = true"}
+
 	if companyID != nil {
 		whereConditions = append(whereConditions, fmt.Sprintf("u.company_id = $%d", argIndex))
 		args = append(args, *companyID)
@@ -602,12 +1117,12 @@ func (r *UserRepository) Search(ctx context.Context, companyID *uuid.UUID, searc
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
 		WHERE %s
-		ORDER BY u.name ASC
-		LIMIT $%d OFFSET $%d`, strings.Join(whereConditions, " AND "), argIndex, argIndex+1)
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, strings.Join(whereConditions, " AND "), sort.orderBy("u.name ASC, u.id ASC", "u.id"), argIndex, argIndex+1)
 
 	args = append(args, limit, offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to search users: %w", err)
@@ -655,6 +1170,8 @@ func (r *UserRepository) Search(ctx context.Context, companyID *uuid.UUID, searc
 func (r *UserRepository) CountUsers(ctx context.Context, companyID *uuid.UUID) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.CountUsers")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := "SELECT COUNT(*) FROM users WHERE 1=1"
 	args := []interface{}{}
@@ -665,7 +1182,7 @@ func (r *UserRepository) CountUsers(ctx context.Context, companyID *uuid.UUID) (
 	}
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		span.RecordError(err)
 		return 0, fmt.Errorf("failed to count users: %w", err)
@@ -674,10 +1191,56 @@ func (r *UserRepository) CountUsers(ctx context.Context, companyID *uuid.UUID) (
 	return count, nil
 }
 
+// ExistsByCPF reports whether another non-deleted user of the same company already uses the CPF
+func (r *UserRepository) ExistsByCPF(ctx context.Context, companyID *uuid.UUID, cpf string, excludeUserID *uuid.UUID) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.ExistsByCPF")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM users
+			WHERE company_id IS NOT DISTINCT FROM $1
+			  AND cpf = $2
+			  AND deleted_at IS NULL
+			  AND ($3::uuid IS NULL OR id <> $3)
+		)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, companyID, cpf, excludeUserID).Scan(&exists)
+	if err != nil {
+		span.RecordError(err)
+		return false, fmt.Errorf("failed to check cpf uniqueness: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ExistsByEmail reports whether any user row, deleted ones included, holds the email.
+// It mirrors the unique constraint on users.email, so false means an insert won't clash.
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.ExistsByEmail")
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE email = $1)`, email).Scan(&exists)
+	if err != nil {
+		span.RecordError(err)
+		return false, fmt.Errorf("failed to check email uniqueness: %w", err)
+	}
+
+	return exists, nil
+}
+
 // CountActiveUsers counts active users, optionally filtered by company
 func (r *UserRepository) CountActiveUsers(ctx context.Context, companyID *uuid.UUID) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.CountActiveUsers")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := "SELECT COUNT(*) FROM users WHERE active = true"
 	args := []interface{}{}
@@ -688,7 +1251,7 @@ func (r *UserRepository) CountActiveUsers(ctx context.Context, companyID *uuid.U
 	}
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := r.reader().QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		span.RecordError(err)
 		return 0, fmt.Errorf("failed to count active users: %w", err)
@@ -698,24 +1261,19 @@ func (r *UserRepository) CountActiveUsers(ctx context.Context, companyID *uuid.U
 }
 
 // ListByCompanyAndRoles retrieves users by company and specific roles
-func (r *UserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int) ([]*models.User, error) {
+func (r *UserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int, sort Sort) ([]*models.User, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.ListByCompanyAndRoles")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if len(roles) == 0 {
 		return []*models.User{}, nil
 	}
 
-	// Create placeholders for roles
-	rolePlaceholders := make([]string, len(roles))
-	args := []interface{}{}
-	paramCount := 1
-
-	for i, role := range roles {
-		rolePlaceholders[i] = fmt.Sprintf("$%d", paramCount)
-		args = append(args, role)
-		paramCount++
-	}
+	// Roles travel as one array parameter, however many there are
+	args := []interface{}{pq.Array(roles)}
+	paramCount := 2
 
 	query := `
 		SELECT u.id, u.name, u.email, u.password, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
@@ -724,7 +1282,7 @@ func (r *UserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *u
 		       r.id, r.name, r.description, r.created_at, r.updated_at
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
-		WHERE u.deleted_at IS NULL AND r.name IN (` + strings.Join(rolePlaceholders, ",") + `)`
+		WHERE u.deleted_at IS NULL AND r.name = ANY($1)`
 
 	if companyID != nil {
 		query += fmt.Sprintf(" AND u.company_id = $%d", paramCount)
@@ -732,7 +1290,7 @@ func (r *UserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *u
 		paramCount++
 	}
 
-	query += fmt.Sprintf(" ORDER BY u.created_at DESC LIMIT $%d OFFSET $%d", paramCount, paramCount+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", sort.orderBy("u.created_at DESC, u.id DESC", "u.id"), paramCount, paramCount+1)
 	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -784,20 +1342,13 @@ func (r *UserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *u
 func (r *UserRepository) ListByRoles(ctx context.Context, roles []string, limit, offset int) ([]*models.User, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.ListByRoles")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if len(roles) == 0 {
 		return []*models.User{}, nil
 	}
 
-	// Create placeholders for roles
-	rolePlaceholders := make([]string, len(roles))
-	args := []interface{}{}
-
-	for i, role := range roles {
-		rolePlaceholders[i] = fmt.Sprintf("$%d", i+1)
-		args = append(args, role)
-	}
-
 	query := `
 		SELECT u.id, u.name, u.email, u.password, u.phone, u.cpf, u.avatar, u.role_id, u.company_id,
 		       u.active, u.last_login, u.dashboard_config, u.api_token, u.login_attempts,
@@ -805,12 +1356,10 @@ func (r *UserRepository) ListByRoles(ctx context.Context, roles []string, limit,
 		       r.id, r.name, r.description, r.created_at, r.updated_at
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
-		WHERE u.deleted_at IS NULL AND r.name IN (` + strings.Join(rolePlaceholders, ",") + `)
-		ORDER BY u.created_at DESC LIMIT $` + fmt.Sprintf("%d", len(roles)+1) + ` OFFSET $` + fmt.Sprintf("%d", len(roles)+2)
-
-	args = append(args, limit, offset)
+		WHERE u.deleted_at IS NULL AND r.name = ANY($1)
+		ORDER BY u.created_at DESC, u.id DESC LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(roles), limit, offset)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to list users by roles: %w", err)
@@ -859,27 +1408,22 @@ func (r *UserRepository) ListByRoles(ctx context.Context, roles []string, limit,
 func (r *UserRepository) CountByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.CountByCompanyAndRoles")
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if len(roles) == 0 {
 		return 0, nil
 	}
 
-	// Create placeholders for roles
-	rolePlaceholders := make([]string, len(roles))
-	args := []interface{}{}
-	paramCount := 1
-
-	for i, role := range roles {
-		rolePlaceholders[i] = fmt.Sprintf("$%d", paramCount)
-		args = append(args, role)
-		paramCount++
-	}
+	// Roles travel as one array parameter, however many there are
+	args := []interface{}{pq.Array(roles)}
+	paramCount := 2
 
 	query := `
 		SELECT COUNT(*)
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
-		WHERE r.name IN (` + strings.Join(rolePlaceholders, ",") + `)`
+		WHERE r.name = ANY($1)`
 
 	if companyID != nil {
 		query += fmt.Sprintf(" AND u.company_id = $%d", paramCount)
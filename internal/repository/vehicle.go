@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,9 +17,14 @@ import (
 	"github.com/paulochiaradia/dashtrack/internal/models"
 )
 
+// ErrAssignmentOutsideCompany is returned when a driver, helper or team being assigned
+// to a vehicle does not belong to the vehicle's company
+var ErrAssignmentOutsideCompany = errors.New("assignment does not belong to the vehicle's company")
+
 // VehicleRepository handles database operations for vehicles
 type VehicleRepository struct {
 	db     *sqlx.DB
+	users  *UserRepository
 	tracer trace.Tracer
 }
 
@@ -26,6 +32,7 @@ type VehicleRepository struct {
 func NewVehicleRepository(db *sqlx.DB) *VehicleRepository {
 	return &VehicleRepository{
 		db:     db,
+		users:  NewUserRepository(db),
 		tracer: otel.Tracer("vehicle-repository"),
 	}
 }
@@ -38,7 +45,49 @@ func (r *VehicleRepository) Create(ctx context.Context, vehicle *models.Vehicle)
 			attribute.String("company.id", vehicle.CompanyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if err := r.insert(ctx, r.db, vehicle); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.SetAttributes(attribute.String("vehicle.id", vehicle.ID.String()))
+	return nil
+}
+
+// CreateBatch creates several vehicles in one transaction; if any insert fails none is created
+func (r *VehicleRepository) CreateBatch(ctx context.Context, vehicles []*models.Vehicle) error {
+	ctx, span := r.tracer.Start(ctx, "VehicleRepository.CreateBatch",
+		trace.WithAttributes(attribute.Int("vehicles.count", len(vehicles))))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, vehicle := range vehicles {
+		if err := r.insert(ctx, tx, vehicle); err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("vehicle %s: %w", vehicle.LicensePlate, err)
+		}
+	}
 
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to commit vehicles: %w", err)
+	}
+	return nil
+}
+
+// insert writes a new vehicle, filling in its ID, timestamps and default status
+func (r *VehicleRepository) insert(ctx context.Context, db sqlx.ExtContext, vehicle *models.Vehicle) error {
 	vehicle.ID = uuid.New()
 	vehicle.CreatedAt = time.Now()
 	vehicle.UpdatedAt = time.Now()
@@ -58,13 +107,9 @@ func (r *VehicleRepository) Create(ctx context.Context, vehicle *models.Vehicle)
 		)
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, vehicle)
-	if err != nil {
-		span.RecordError(err)
+	if _, err := sqlx.NamedExecContext(ctx, db, query, vehicle); err != nil {
 		return fmt.Errorf("failed to create vehicle: %w", err)
 	}
-
-	span.SetAttributes(attribute.String("vehicle.id", vehicle.ID.String()))
 	return nil
 }
 
@@ -76,6 +121,8 @@ func (r *VehicleRepository) GetByID(ctx context.Context, id uuid.UUID, companyID
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var vehicle models.Vehicle
 	query := `
@@ -98,7 +145,8 @@ func (r *VehicleRepository) GetByID(ctx context.Context, id uuid.UUID, companyID
 	return &vehicle, nil
 }
 
-// GetByLicensePlate retrieves a vehicle by license plate within company
+// GetByLicensePlate retrieves a non-deleted vehicle by its normalized license plate
+// within company. Stored plates are compared without separators, so "ABC-1234" matches.
 func (r *VehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate string, companyID uuid.UUID) (*models.Vehicle, error) {
 	ctx, span := r.tracer.Start(ctx, "VehicleRepository.GetByLicensePlate",
 		trace.WithAttributes(
@@ -106,6 +154,8 @@ func (r *VehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var vehicle models.Vehicle
 	query := `
@@ -113,7 +163,8 @@ func (r *VehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 			   vehicle_type, fuel_type, cargo_capacity, driver_id, helper_id, status,
 			   created_at, updated_at
 		FROM vehicles 
-		WHERE license_plate = $1 AND company_id = $2
+		WHERE regexp_replace(upper(license_plate), '[^A-Z0-9]', '', 'g') = $1
+		  AND company_id = $2 AND status != 'deleted'
 	`
 
 	err := r.db.GetContext(ctx, &vehicle, query, licensePlate, companyID)
@@ -128,8 +179,8 @@ func (r *VehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 	return &vehicle, nil
 }
 
-// GetByCompany retrieves all vehicles for a company
-func (r *VehicleRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]models.Vehicle, error) {
+// GetByCompany retrieves vehicles for a company with pagination, narrowed by filter
+func (r *VehicleRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, filter models.VehicleFilter, limit, offset int, sort Sort) ([]models.Vehicle, error) {
 	ctx, span := r.tracer.Start(ctx, "VehicleRepository.GetByCompany",
 		trace.WithAttributes(
 			attribute.String("company.id", companyID.String()),
@@ -137,19 +188,23 @@ func (r *VehicleRepository) GetByCompany(ctx context.Context, companyID uuid.UUI
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	var vehicles []models.Vehicle
+	where, args := vehicleCompanyFilter(companyID, filter)
+	argCount := len(args) + 1
 	query := `
 		SELECT id, company_id, team_id, license_plate, brand, model, year, color,
 			   vehicle_type, fuel_type, cargo_capacity, driver_id, helper_id, status,
 			   created_at, updated_at
 		FROM vehicles 
-		WHERE company_id = $1 AND status != 'deleted'
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		` + where
 
-	err := r.db.SelectContext(ctx, &vehicles, query, companyID, limit, offset)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", sort.orderBy("created_at DESC, id DESC", "id"), argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	var vehicles []models.Vehicle
+	err := r.db.SelectContext(ctx, &vehicles, query, args...)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to get vehicles by company: %w", err)
@@ -159,6 +214,47 @@ func (r *VehicleRepository) GetByCompany(ctx context.Context, companyID uuid.UUI
 	return vehicles, nil
 }
 
+// CountByCompany counts the vehicles of a company matching the filter
+func (r *VehicleRepository) CountByCompany(ctx context.Context, companyID uuid.UUID, filter models.VehicleFilter) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "VehicleRepository.CountByCompany",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	where, args := vehicleCompanyFilter(companyID, filter)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM vehicles "+where, args...); err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("failed to count vehicles by company: %w", err)
+	}
+
+	return count, nil
+}
+
+// vehicleCompanyFilter builds the WHERE clause shared by GetByCompany and CountByCompany
+func vehicleCompanyFilter(companyID uuid.UUID, filter models.VehicleFilter) (string, []interface{}) {
+	where := "WHERE company_id = $1 AND status != 'deleted'"
+	args := []interface{}{companyID}
+	argCount := 2
+
+	if filter.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filter.Status)
+		argCount++
+	}
+	if filter.VehicleType != "" {
+		where += fmt.Sprintf(" AND vehicle_type = $%d", argCount)
+		args = append(args, filter.VehicleType)
+	}
+	if filter.Unassigned {
+		where += " AND (driver_id IS NULL OR team_id IS NULL)"
+	}
+
+	return where, args
+}
+
 // GetByTeam retrieves all vehicles for a team
 func (r *VehicleRepository) GetByTeam(ctx context.Context, teamID uuid.UUID, companyID uuid.UUID) ([]models.Vehicle, error) {
 	ctx, span := r.tracer.Start(ctx, "VehicleRepository.GetByTeam",
@@ -167,6 +263,8 @@ func (r *VehicleRepository) GetByTeam(ctx context.Context, teamID uuid.UUID, com
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var vehicles []models.Vehicle
 	query := `
@@ -196,6 +294,8 @@ func (r *VehicleRepository) GetByDriver(ctx context.Context, driverID uuid.UUID,
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var vehicles []models.Vehicle
 	query := `
@@ -222,6 +322,8 @@ func (r *VehicleRepository) Update(ctx context.Context, vehicle *models.Vehicle)
 	ctx, span := r.tracer.Start(ctx, "VehicleRepository.Update",
 		trace.WithAttributes(attribute.String("vehicle.id", vehicle.ID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	vehicle.UpdatedAt = time.Now()
 
@@ -259,12 +361,25 @@ func (r *VehicleRepository) Update(ctx context.Context, vehicle *models.Vehicle)
 
 // UpdateAssignment updates vehicle assignments (driver, helper, team) and logs the change
 func (r *VehicleRepository) UpdateAssignment(ctx context.Context, vehicleID, companyID uuid.UUID, driverID, helperID, teamID *uuid.UUID) error {
-	ctx, span := r.tracer.Start(ctx, "VehicleRepository.UpdateAssignment",
+	return r.UpdateAssignmentWithReason(ctx, vehicleID, companyID, driverID, helperID, teamID, nil)
+}
+
+// UpdateAssignmentWithReason updates vehicle assignments like UpdateAssignment, recording
+// reason as the change reason in the assignment history
+func (r *VehicleRepository) UpdateAssignmentWithReason(ctx context.Context, vehicleID, companyID uuid.UUID, driverID, helperID, teamID *uuid.UUID, reason *string) error {
+	ctx, span := r.tracer.Start(ctx, "VehicleRepository.UpdateAssignmentWithReason",
 		trace.WithAttributes(
 			attribute.String("vehicle.id", vehicleID.String()),
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if err := r.checkAssignmentMembership(ctx, companyID, driverID, helperID, teamID); err != nil {
+		span.RecordError(err)
+		return err
+	}
 
 	// Get current vehicle state before update
 	var currentVehicle models.Vehicle
@@ -316,6 +431,7 @@ func (r *VehicleRepository) UpdateAssignment(ctx context.Context, vehicleID, com
 			NewTeamID:        teamID,
 			ChangeType:       changeType,
 			ChangedByUserID:  nil, // Should be set by handler
+			ChangeReason:     reason,
 		}
 
 		// Log the change (non-critical, don't fail the update if logging fails)
@@ -328,6 +444,36 @@ func (r *VehicleRepository) UpdateAssignment(ctx context.Context, vehicleID, com
 	return nil
 }
 
+// checkAssignmentMembership verifies that every supplied driver, helper and team belongs to
+// the company, failing with ErrAssignmentOutsideCompany for the first one that does not
+func (r *VehicleRepository) checkAssignmentMembership(ctx context.Context, companyID uuid.UUID, driverID, helperID, teamID *uuid.UUID) error {
+	checks := []struct {
+		name  string
+		id    *uuid.UUID
+		query string
+	}{
+		{"driver", driverID, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND company_id = $2 AND deleted_at IS NULL)`},
+		{"helper", helperID, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND company_id = $2 AND deleted_at IS NULL)`},
+		{"team", teamID, `SELECT EXISTS(SELECT 1 FROM teams WHERE id = $1 AND company_id = $2 AND deleted_at IS NULL)`},
+	}
+
+	for _, check := range checks {
+		if check.id == nil {
+			continue
+		}
+
+		var exists bool
+		if err := r.db.GetContext(ctx, &exists, check.query, *check.id, companyID); err != nil {
+			return fmt.Errorf("failed to verify %s company: %w", check.name, err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s %s", ErrAssignmentOutsideCompany, check.name, check.id)
+		}
+	}
+
+	return nil
+}
+
 // determineChangeType determines what type of change occurred
 func (r *VehicleRepository) determineChangeType(oldDriverID, oldHelperID, oldTeamID, newDriverID, newHelperID, newTeamID *uuid.UUID) string {
 	driverChanged := !uuidPtrEqual(oldDriverID, newDriverID)
@@ -388,6 +534,8 @@ func (r *VehicleRepository) Delete(ctx context.Context, id uuid.UUID, companyID
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE vehicles 
@@ -417,6 +565,8 @@ func (r *VehicleRepository) GetVehicleDashboardData(ctx context.Context, vehicle
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	// Get vehicle basic info
 	vehicle, err := r.GetByID(ctx, vehicleID, companyID)
@@ -492,11 +642,78 @@ func (r *VehicleRepository) GetVehicleDashboardData(ctx context.Context, vehicle
 	return data, nil
 }
 
+// GetAssignment retrieves the current driver, helper and team of a vehicle with their names.
+// It returns nil when the vehicle doesn't exist in the company.
+func (r *VehicleRepository) GetAssignment(ctx context.Context, vehicleID, companyID uuid.UUID) (*models.VehicleAssignment, error) {
+	ctx, span := r.tracer.Start(ctx, "VehicleRepository.GetAssignment",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", vehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var row struct {
+		VehicleID    uuid.UUID  `db:"vehicle_id"`
+		LicensePlate string     `db:"license_plate"`
+		DriverID     *uuid.UUID `db:"driver_id"`
+		DriverName   *string    `db:"driver_name"`
+		HelperID     *uuid.UUID `db:"helper_id"`
+		HelperName   *string    `db:"helper_name"`
+		TeamID       *uuid.UUID `db:"team_id"`
+		TeamName     *string    `db:"team_name"`
+	}
+	query := `
+		SELECT v.id AS vehicle_id, v.license_plate,
+			   v.driver_id, d.name AS driver_name,
+			   v.helper_id, h.name AS helper_name,
+			   v.team_id, t.name AS team_name
+		FROM vehicles v
+		LEFT JOIN users d ON d.id = v.driver_id
+		LEFT JOIN users h ON h.id = v.helper_id
+		LEFT JOIN teams t ON t.id = v.team_id
+		WHERE v.id = $1 AND v.company_id = $2
+	`
+
+	err := r.db.GetContext(ctx, &row, query, vehicleID, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get vehicle assignment: %w", err)
+	}
+
+	assignment := &models.VehicleAssignment{
+		VehicleID:    row.VehicleID,
+		LicensePlate: row.LicensePlate,
+		Driver:       assignmentRef(row.DriverID, row.DriverName),
+		Helper:       assignmentRef(row.HelperID, row.HelperName),
+		Team:         assignmentRef(row.TeamID, row.TeamName),
+	}
+	return assignment, nil
+}
+
+// assignmentRef builds the reference of an assigned user or team, nil when unassigned
+func assignmentRef(id *uuid.UUID, name *string) *models.AssignmentRef {
+	if id == nil {
+		return nil
+	}
+	ref := &models.AssignmentRef{ID: *id}
+	if name != nil {
+		ref.Name = *name
+	}
+	return ref
+}
+
 // GetActiveTrip retrieves the currently active trip for a vehicle
 func (r *VehicleRepository) GetActiveTrip(ctx context.Context, vehicleID uuid.UUID) (*models.VehicleTrip, error) {
 	ctx, span := r.tracer.Start(ctx, "VehicleRepository.GetActiveTrip",
 		trace.WithAttributes(attribute.String("vehicle.id", vehicleID.String())))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var trip models.VehicleTrip
 	query := `
@@ -522,6 +739,74 @@ func (r *VehicleRepository) GetActiveTrip(ctx context.Context, vehicleID uuid.UU
 	return &trip, nil
 }
 
+// activeTripRow is the flattened result of the active trips join
+type activeTripRow struct {
+	models.VehicleTrip
+	LicensePlate string     `db:"license_plate"`
+	Brand        string     `db:"brand"`
+	Model        string     `db:"model"`
+	VehicleType  string     `db:"vehicle_type"`
+	TeamID       *uuid.UUID `db:"team_id"`
+	DriverName   *string    `db:"driver_name"`
+	DriverPhone  *string    `db:"driver_phone"`
+}
+
+// GetActiveTripsByCompany retrieves every trip in progress for a company's
+// vehicles, with a vehicle and driver summary, in a single query
+func (r *VehicleRepository) GetActiveTripsByCompany(ctx context.Context, companyID uuid.UUID) ([]models.ActiveTrip, error) {
+	ctx, span := r.tracer.Start(ctx, "VehicleRepository.GetActiveTripsByCompany",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT t.id, t.vehicle_id, t.driver_id, t.helper_id, t.start_location, t.end_location,
+			   t.start_latitude, t.start_longitude, t.end_latitude, t.end_longitude,
+			   t.start_time, t.end_time, t.distance_km, t.duration_minutes, t.fuel_consumption,
+			   t.status, t.notes, t.created_at, t.updated_at,
+			   v.license_plate, v.brand, v.model, v.vehicle_type, v.team_id,
+			   d.name AS driver_name, d.phone AS driver_phone
+		FROM vehicle_trips t
+		JOIN vehicles v ON v.id = t.vehicle_id
+		LEFT JOIN users d ON d.id = t.driver_id
+		WHERE v.company_id = $1 AND t.status = 'active' AND v.status != 'deleted'
+		ORDER BY t.start_time DESC
+	`
+
+	var rows []activeTripRow
+	if err := r.db.SelectContext(ctx, &rows, query, companyID); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get active trips: %w", err)
+	}
+
+	trips := make([]models.ActiveTrip, 0, len(rows))
+	for _, row := range rows {
+		trip := models.ActiveTrip{
+			Trip: row.VehicleTrip,
+			Vehicle: models.ActiveTripVehicle{
+				ID:           row.VehicleID,
+				LicensePlate: row.LicensePlate,
+				Brand:        row.Brand,
+				Model:        row.Model,
+				VehicleType:  row.VehicleType,
+				TeamID:       row.TeamID,
+			},
+		}
+		if row.DriverID != nil && row.DriverName != nil {
+			trip.Driver = &models.ActiveTripDriver{
+				ID:    *row.DriverID,
+				Name:  *row.DriverName,
+				Phone: row.DriverPhone,
+			}
+		}
+		trips = append(trips, trip)
+	}
+
+	span.SetAttributes(attribute.Int("trips.count", len(trips)))
+	return trips, nil
+}
+
 // Search searches vehicles by license plate, brand, or model
 func (r *VehicleRepository) Search(ctx context.Context, companyID uuid.UUID, searchTerm string, limit, offset int) ([]models.Vehicle, error) {
 	ctx, span := r.tracer.Start(ctx, "VehicleRepository.Search",
@@ -532,6 +817,8 @@ func (r *VehicleRepository) Search(ctx context.Context, companyID uuid.UUID, sea
 			attribute.Int("offset", offset),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	var vehicles []models.Vehicle
 	searchPattern := "%" + strings.ToLower(searchTerm) + "%"
@@ -544,7 +831,7 @@ func (r *VehicleRepository) Search(ctx context.Context, companyID uuid.UUID, sea
 		WHERE company_id = $1 
 		AND (LOWER(license_plate) LIKE $2 OR LOWER(brand) LIKE $2 OR LOWER(model) LIKE $2)
 		AND status != 'deleted'
-		ORDER BY license_plate ASC
+		ORDER BY license_plate ASC, id ASC
 		LIMIT $3 OFFSET $4
 	`
 
@@ -566,6 +853,8 @@ func (r *VehicleRepository) CheckLicensePlateExists(ctx context.Context, license
 			attribute.String("company.id", companyID.String()),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	query := `SELECT COUNT(*) FROM vehicles WHERE license_plate = $1 AND company_id = $2 AND status != 'deleted'`
 	args := []interface{}{licensePlate, companyID}
@@ -597,6 +886,8 @@ func (r *VehicleRepository) LogAssignmentChange(ctx context.Context, history *mo
 			attribute.String("change.type", history.ChangeType),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	history.ID = uuid.New()
 	history.ChangedAt = time.Now()
@@ -635,6 +926,8 @@ func (r *VehicleRepository) GetAssignmentHistory(ctx context.Context, vehicleID,
 			attribute.Int("limit", limit),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if limit == 0 {
 		limit = 50 // Default limit
@@ -673,6 +966,8 @@ func (r *VehicleRepository) GetAssignmentHistoryWithDetails(ctx context.Context,
 			attribute.Int("limit", limit),
 		))
 	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if limit == 0 {
 		limit = 50
@@ -684,27 +979,29 @@ func (r *VehicleRepository) GetAssignmentHistoryWithDetails(ctx context.Context,
 		return nil, err
 	}
 
+	var userIDs []uuid.UUID
+	for _, entry := range history {
+		for _, id := range []*uuid.UUID{entry.PreviousDriverID, entry.PreviousHelperID, entry.NewDriverID, entry.NewHelperID, entry.ChangedByUserID} {
+			if id != nil {
+				userIDs = append(userIDs, *id)
+			}
+		}
+	}
+	users, err := r.users.GetByIDs(ctx, userIDs)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	// Populate user and team details for each history entry
 	for i := range history {
 		entry := &history[i]
 
-		// Load previous driver
-		if entry.PreviousDriverID != nil {
-			var driver models.User
-			err := r.db.GetContext(ctx, &driver, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.PreviousDriverID)
-			if err == nil {
-				entry.PreviousDriver = &driver
-			}
-		}
-
-		// Load previous helper
-		if entry.PreviousHelperID != nil {
-			var helper models.User
-			err := r.db.GetContext(ctx, &helper, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.PreviousHelperID)
-			if err == nil {
-				entry.PreviousHelper = &helper
-			}
-		}
+		entry.PreviousDriver = lookupUser(users, entry.PreviousDriverID)
+		entry.PreviousHelper = lookupUser(users, entry.PreviousHelperID)
+		entry.NewDriver = lookupUser(users, entry.NewDriverID)
+		entry.NewHelper = lookupUser(users, entry.NewHelperID)
+		entry.ChangedByUser = lookupUser(users, entry.ChangedByUserID)
 
 		// Load previous team
 		if entry.PreviousTeamID != nil {
@@ -715,24 +1012,6 @@ func (r *VehicleRepository) GetAssignmentHistoryWithDetails(ctx context.Context,
 			}
 		}
 
-		// Load new driver
-		if entry.NewDriverID != nil {
-			var driver models.User
-			err := r.db.GetContext(ctx, &driver, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.NewDriverID)
-			if err == nil {
-				entry.NewDriver = &driver
-			}
-		}
-
-		// Load new helper
-		if entry.NewHelperID != nil {
-			var helper models.User
-			err := r.db.GetContext(ctx, &helper, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.NewHelperID)
-			if err == nil {
-				entry.NewHelper = &helper
-			}
-		}
-
 		// Load new team
 		if entry.NewTeamID != nil {
 			var team models.Team
@@ -741,16 +1020,15 @@ func (r *VehicleRepository) GetAssignmentHistoryWithDetails(ctx context.Context,
 				entry.NewTeam = &team
 			}
 		}
-
-		// Load changed by user
-		if entry.ChangedByUserID != nil {
-			var changedBy models.User
-			err := r.db.GetContext(ctx, &changedBy, `SELECT id, name, email, role FROM users WHERE id = $1`, entry.ChangedByUserID)
-			if err == nil {
-				entry.ChangedByUser = &changedBy
-			}
-		}
 	}
 
 	return history, nil
 }
+
+// lookupUser returns the user with an optional ID from a GetByIDs result
+func lookupUser(users map[uuid.UUID]*models.User, id *uuid.UUID) *models.User {
+	if id == nil {
+		return nil
+	}
+	return users[*id]
+}
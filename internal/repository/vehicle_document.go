@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// VehicleDocumentRepositoryInterface defines the contract for vehicle document repository
+type VehicleDocumentRepositoryInterface interface {
+	Create(ctx context.Context, doc *models.VehicleDocument) error
+	GetByID(ctx context.Context, id, vehicleID, companyID uuid.UUID) (*models.VehicleDocument, error)
+	ListByVehicle(ctx context.Context, vehicleID, companyID uuid.UUID) ([]models.VehicleDocument, error)
+	Update(ctx context.Context, doc *models.VehicleDocument, companyID uuid.UUID) error
+	Delete(ctx context.Context, id, vehicleID, companyID uuid.UUID) error
+	GetExpiringDocuments(ctx context.Context, companyID uuid.UUID, withinDays int) ([]models.ExpiringDocument, error)
+}
+
+// VehicleDocumentRepository handles database operations for vehicle documents.
+// Documents have no company column; every query is scoped through the vehicle.
+type VehicleDocumentRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewVehicleDocumentRepository creates a new vehicle document repository
+func NewVehicleDocumentRepository(db *sqlx.DB) *VehicleDocumentRepository {
+	return &VehicleDocumentRepository{
+		db:     db,
+		tracer: otel.Tracer("vehicle-document-repository"),
+	}
+}
+
+// Create registers a document; the caller must have checked the vehicle belongs to the company
+func (r *VehicleDocumentRepository) Create(ctx context.Context, doc *models.VehicleDocument) error {
+	ctx, span := r.tracer.Start(ctx, "VehicleDocumentRepository.Create",
+		trace.WithAttributes(attribute.String("vehicle.id", doc.VehicleID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	doc.ID = uuid.New()
+	doc.CreatedAt = time.Now()
+	doc.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO vehicle_documents (
+			id, vehicle_id, type, number, issued_at, expires_at, notes, created_by, created_at, updated_at
+		) VALUES (
+			:id, :vehicle_id, :type, :number, :issued_at, :expires_at, :notes, :created_by, :created_at, :updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, doc)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to create vehicle document: %w", err)
+	}
+
+	span.SetAttributes(attribute.String("document.id", doc.ID.String()))
+	return nil
+}
+
+// GetByID retrieves a document of a vehicle with company context
+func (r *VehicleDocumentRepository) GetByID(ctx context.Context, id, vehicleID, companyID uuid.UUID) (*models.VehicleDocument, error) {
+	ctx, span := r.tracer.Start(ctx, "VehicleDocumentRepository.GetByID",
+		trace.WithAttributes(
+			attribute.String("document.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var doc models.VehicleDocument
+	query := `
+		SELECT d.id, d.vehicle_id, d.type, d.number, d.issued_at, d.expires_at, d.notes,
+		       d.created_by, d.created_at, d.updated_at
+		FROM vehicle_documents d
+		JOIN vehicles v ON v.id = d.vehicle_id
+		WHERE d.id = $1 AND d.vehicle_id = $2 AND v.company_id = $3 AND v.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &doc, query, id, vehicleID, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get vehicle document by ID: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// ListByVehicle retrieves the documents of a vehicle, soonest expiry first
+func (r *VehicleDocumentRepository) ListByVehicle(ctx context.Context, vehicleID, companyID uuid.UUID) ([]models.VehicleDocument, error) {
+	ctx, span := r.tracer.Start(ctx, "VehicleDocumentRepository.ListByVehicle",
+		trace.WithAttributes(
+			attribute.String("vehicle.id", vehicleID.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	docs := []models.VehicleDocument{}
+	query := `
+		SELECT d.id, d.vehicle_id, d.type, d.number, d.issued_at, d.expires_at, d.notes,
+		       d.created_by, d.created_at, d.updated_at
+		FROM vehicle_documents d
+		JOIN vehicles v ON v.id = d.vehicle_id
+		WHERE d.vehicle_id = $1 AND v.company_id = $2 AND v.deleted_at IS NULL
+		ORDER BY d.expires_at, d.id
+	`
+
+	err := r.db.SelectContext(ctx, &docs, query, vehicleID, companyID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to list vehicle documents: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("document.count", len(docs)))
+	return docs, nil
+}
+
+// Update updates a document of a vehicle that belongs to the company
+func (r *VehicleDocumentRepository) Update(ctx context.Context, doc *models.VehicleDocument, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "VehicleDocumentRepository.Update",
+		trace.WithAttributes(attribute.String("document.id", doc.ID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	doc.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE vehicle_documents d SET
+			type = $1,
+			number = $2,
+			issued_at = $3,
+			expires_at = $4,
+			notes = $5,
+			updated_at = $6
+		FROM vehicles v
+		WHERE d.id = $7 AND d.vehicle_id = $8 AND v.id = d.vehicle_id AND v.company_id = $9
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		doc.Type, doc.Number, doc.IssuedAt, doc.ExpiresAt, doc.Notes, doc.UpdatedAt,
+		doc.ID, doc.VehicleID, companyID,
+	)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to update vehicle document: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle document not found or not authorized")
+	}
+
+	return nil
+}
+
+// Delete removes a document of a vehicle that belongs to the company
+func (r *VehicleDocumentRepository) Delete(ctx context.Context, id, vehicleID, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "VehicleDocumentRepository.Delete",
+		trace.WithAttributes(attribute.String("document.id", id.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		DELETE FROM vehicle_documents d
+		USING vehicles v
+		WHERE d.id = $1 AND d.vehicle_id = $2 AND v.id = d.vehicle_id AND v.company_id = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, vehicleID, companyID)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to delete vehicle document: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle document not found or not authorized")
+	}
+
+	return nil
+}
+
+// GetExpiringDocuments returns the company's vehicle documents that expire within
+// withinDays days. Already lapsed documents are included with negative days remaining.
+func (r *VehicleDocumentRepository) GetExpiringDocuments(ctx context.Context, companyID uuid.UUID, withinDays int) ([]models.ExpiringDocument, error) {
+	ctx, span := r.tracer.Start(ctx, "VehicleDocumentRepository.GetExpiringDocuments",
+		trace.WithAttributes(
+			attribute.String("company.id", companyID.String()),
+			attribute.Int("within.days", withinDays),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	docs := []models.ExpiringDocument{}
+	query := `
+		SELECT d.id AS document_id, d.vehicle_id, v.license_plate, d.type, d.number, d.expires_at,
+		       (d.expires_at - CURRENT_DATE) AS days_remaining
+		FROM vehicle_documents d
+		JOIN vehicles v ON v.id = d.vehicle_id
+		WHERE v.company_id = $1 AND v.deleted_at IS NULL
+		  AND d.expires_at <= CURRENT_DATE + $2::int
+		ORDER BY d.expires_at, v.license_plate
+	`
+
+	err := r.db.SelectContext(ctx, &docs, query, companyID, withinDays)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get expiring vehicle documents: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("document.expiring_count", len(docs)))
+	return docs, nil
+}
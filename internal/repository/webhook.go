@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// WebhookRepositoryInterface defines the contract for webhook repository
+type WebhookRepositoryInterface interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id, companyID uuid.UUID) (*models.Webhook, error)
+	GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.Webhook, error)
+	GetActiveByEvent(ctx context.Context, companyID uuid.UUID, event string) ([]models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	RotateSecret(ctx context.Context, id, companyID uuid.UUID, secret string, previousExpiresAt *time.Time) (*models.Webhook, error)
+	Delete(ctx context.Context, id, companyID uuid.UUID) error
+}
+
+// WebhookRepository handles database operations for webhooks
+type WebhookRepository struct {
+	db     *sqlx.DB
+	tracer trace.Tracer
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		tracer: otel.Tracer("webhook-repository"),
+	}
+}
+
+// Create registers a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.Create",
+		trace.WithAttributes(attribute.String("company.id", webhook.CompanyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	webhook.ID = uuid.New()
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO webhooks (id, company_id, url, secret, events, active, created_at, updated_at)
+		VALUES (:id, :company_id, :url, :secret, :events, :active, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, webhook)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	span.SetAttributes(attribute.String("webhook.id", webhook.ID.String()))
+	return nil
+}
+
+// GetByID retrieves a webhook by ID with company context
+func (r *WebhookRepository) GetByID(ctx context.Context, id, companyID uuid.UUID) (*models.Webhook, error) {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.GetByID",
+		trace.WithAttributes(
+			attribute.String("webhook.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var webhook models.Webhook
+	query := `
+		SELECT id, company_id, url, secret, events, active, created_at, updated_at,
+		       previous_secret, previous_secret_expires_at
+		FROM webhooks
+		WHERE id = $1 AND company_id = $2
+	`
+
+	err := r.db.GetContext(ctx, &webhook, query, id, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get webhook by ID: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// GetByCompany retrieves all webhooks registered by a company
+func (r *WebhookRepository) GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.Webhook, error) {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.GetByCompany",
+		trace.WithAttributes(attribute.String("company.id", companyID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	webhooks := []models.Webhook{}
+	query := `
+		SELECT id, company_id, url, secret, events, active, created_at, updated_at,
+		       previous_secret, previous_secret_expires_at
+		FROM webhooks
+		WHERE company_id = $1
+		ORDER BY created_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &webhooks, query, companyID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get webhooks by company: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("webhooks.count", len(webhooks)))
+	return webhooks, nil
+}
+
+// GetActiveByEvent retrieves the active webhooks of a company subscribed to an event
+func (r *WebhookRepository) GetActiveByEvent(ctx context.Context, companyID uuid.UUID, event string) ([]models.Webhook, error) {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.GetActiveByEvent",
+		trace.WithAttributes(
+			attribute.String("company.id", companyID.String()),
+			attribute.String("webhook.event", event),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var webhooks []models.Webhook
+	query := `
+		SELECT id, company_id, url, secret, events, active, created_at, updated_at,
+		       previous_secret, previous_secret_expires_at
+		FROM webhooks
+		WHERE company_id = $1 AND active = true AND $2 = ANY(events)
+	`
+
+	err := r.db.SelectContext(ctx, &webhooks, query, companyID, event)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to get webhooks by event: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("webhooks.count", len(webhooks)))
+	return webhooks, nil
+}
+
+// Update updates a webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.Update",
+		trace.WithAttributes(attribute.String("webhook.id", webhook.ID.String())))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	webhook.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE webhooks SET
+			url = :url,
+			secret = :secret,
+			events = :events,
+			active = :active,
+			updated_at = :updated_at
+		WHERE id = :id AND company_id = :company_id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, webhook)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found or not authorized")
+	}
+
+	return nil
+}
+
+// RotateSecret replaces the signing secret of a webhook. The current secret is kept as the
+// previous secret until previousExpiresAt; nil retires it immediately. Returns nil when the
+// webhook does not exist in the company.
+func (r *WebhookRepository) RotateSecret(ctx context.Context, id, companyID uuid.UUID, secret string, previousExpiresAt *time.Time) (*models.Webhook, error) {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.RotateSecret",
+		trace.WithAttributes(
+			attribute.String("webhook.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var webhook models.Webhook
+	query := `
+		UPDATE webhooks SET
+			previous_secret = CASE WHEN $4::timestamp IS NULL THEN NULL ELSE secret END,
+			previous_secret_expires_at = $4,
+			secret = $3,
+			updated_at = $5
+		WHERE id = $1 AND company_id = $2
+		RETURNING id, company_id, url, secret, events, active, created_at, updated_at,
+		          previous_secret, previous_secret_expires_at
+	`
+
+	err := r.db.GetContext(ctx, &webhook, query, id, companyID, secret, previousExpiresAt, time.Now())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// Delete removes a webhook
+func (r *WebhookRepository) Delete(ctx context.Context, id, companyID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "WebhookRepository.Delete",
+		trace.WithAttributes(
+			attribute.String("webhook.id", id.String()),
+			attribute.String("company.id", companyID.String()),
+		))
+	defer span.End()
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1 AND company_id = $2`, id, companyID)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found or not authorized")
+	}
+
+	return nil
+}
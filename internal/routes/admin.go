@@ -16,6 +16,7 @@ func (r *Router) setupAdminRoutes() {
 	admin.GET("/users/:id", r.userHandler.GetUserByID)
 	admin.PUT("/users/:id", r.userHandler.UpdateUser)
 	admin.DELETE("/users/:id", r.userHandler.DeleteUser)
+	admin.POST("/users/:id/revoke-sessions", r.userHandler.RevokeUserSessions)
 
 	// System Configuration (admin-only)
 	// TODO: implement system config handlers
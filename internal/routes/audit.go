@@ -1,7 +1,11 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
 )
 
 // setupAuditRoutes configures audit log routes (Master and Admin only)
@@ -9,11 +13,13 @@ func (router *Router) setupAuditRoutes(api *gin.RouterGroup) {
 	audit := api.Group("/audit")
 	audit.Use(router.authMiddleware.RequireAuth()) // Require authentication
 
-	// All audit endpoints require master or admin role
-	// TODO: Add role-based middleware for master/admin only
+	// List audit logs with filters, including metadata search. Admins and company admins
+	// only see their own company's logs; master sees all.
+	audit.GET("/logs",
+		router.authMiddleware.RequireAnyRole("admin", "company_admin"),
+		router.auditHandler.GetLogs)
 
-	// List audit logs with filters
-	audit.GET("/logs", router.auditHandler.GetLogs)
+	// TODO: Add role-based middleware for master/admin only to the endpoints below
 
 	// Get specific audit log
 	audit.GET("/logs/:id", router.auditHandler.GetLogByID)
@@ -33,6 +39,17 @@ func (router *Router) setupAuditRoutes(api *gin.RouterGroup) {
 	// Get logs by Jaeger trace ID
 	audit.GET("/traces/:traceId", router.auditHandler.GetByTraceID)
 
-	// Export audit logs (JSON or CSV)
-	audit.GET("/export", router.auditHandler.ExportLogs)
+	// Stream an export (JSON or CSV) for auditors: company admins get their company, master
+	// gets all. Both paths share one per-user export budget; /export is kept for existing clients.
+	exportLimiter := middleware.NewActionRateLimiter(
+		"Export",
+		router.cfg.ExportRateLimit,
+		time.Duration(router.cfg.ExportRateWindowMinutes)*time.Minute,
+	)
+	for _, path := range []string{"/export", "/logs/export"} {
+		audit.GET(path,
+			router.authMiddleware.RequireAnyRole("company_admin"),
+			exportLimiter.Middleware(),
+			router.auditHandler.StreamLogs)
+	}
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupCompanySettingsRoutes configures company settings routes
+func (r *Router) setupCompanySettingsRoutes(api *gin.RouterGroup) {
+	settings := api.Group("/company/settings")
+	settings.Use(r.authMiddleware.RequireAuth())
+	settings.Use(r.authMiddleware.RequireRole("company_admin"))
+	settings.Use(middleware.RequireCompanyAccess())
+	{
+		settings.GET("", r.companySettingsHandler.GetCompanySettings)                                                                                  // Current settings
+		settings.PUT("", middleware.AuditAction(r.auditLogRepo, "company_settings_update", "company"), r.companySettingsHandler.UpdateCompanySettings) // Replace settings
+	}
+}
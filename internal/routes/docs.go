@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/docs"
+)
+
+// setupDocsRoutes serves the OpenAPI spec and Swagger UI (public, no auth)
+func (r *Router) setupDocsRoutes() {
+	r.engine.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", docs.OpenAPISpec)
+	})
+
+	r.engine.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docs.SwaggerUIHTML))
+	})
+}
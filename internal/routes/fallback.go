@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// setupFallbackHandlers configures the responses for requests that match no route: paths
+// differing only by a trailing slash are redirected to the registered one, and a path that
+// exists for other methods gets 405 with the Allow header instead of 404
+func (r *Router) setupFallbackHandlers() {
+	r.engine.RedirectTrailingSlash = true
+	r.engine.HandleMethodNotAllowed = true
+
+	r.engine.NoMethod(func(c *gin.Context) {
+		// gin has already set the Allow header with the methods registered for the path
+		allowed := c.Writer.Header().Get("Allow")
+		utils.ErrorResponse(c, http.StatusMethodNotAllowed, "Method Not Allowed", gin.H{
+			"code":    "METHOD_NOT_ALLOWED",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+			"allowed": strings.Split(allowed, ", "),
+		})
+	})
+
+	r.engine.NoRoute(func(c *gin.Context) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Not Found", gin.H{
+			"code":    "NOT_FOUND",
+			"path":    c.Request.URL.Path,
+			"message": fmt.Sprintf("No route for %s %s", c.Request.Method, c.Request.URL.Path),
+		})
+	})
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupFuelRoutes configures fuel log and odometer routes for fleet managers
+func (r *Router) setupFuelRoutes(api *gin.RouterGroup) {
+	fuel := api.Group("/vehicles")
+	fuel.Use(r.authMiddleware.RequireAuth())
+	fuel.Use(r.authMiddleware.RequireAnyRole("company_admin", "manager"))
+	fuel.Use(middleware.RequireCompanyAccess())
+	{
+		fuel.POST("/:id/fuel", r.fuelHandler.CreateFuelLog)               // Log a fuel purchase
+		fuel.GET("/:id/fuel", r.fuelHandler.GetFuelLogs)                  // List fuel purchases in a period
+		fuel.GET("/:id/fuel/efficiency", r.fuelHandler.GetFuelEfficiency) // km/L over a period
+		fuel.POST("/:id/odometer", r.fuelHandler.CreateOdometerReading)   // Record an odometer reading
+		fuel.GET("/:id/odometer", r.fuelHandler.GetOdometerReadings)      // Odometer history
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupIPAllowlistRoutes configures company IP allowlist management routes
+func (r *Router) setupIPAllowlistRoutes(api *gin.RouterGroup) {
+	allowlist := api.Group("/company/ip-allowlist")
+	allowlist.Use(r.authMiddleware.RequireAuth())
+	allowlist.Use(r.authMiddleware.RequireRole("company_admin"))
+	allowlist.Use(middleware.RequireCompanyAccess())
+	{
+		allowlist.GET("", r.ipAllowlistHandler.GetIPAllowlist)                                                                                          // List allowed ranges
+		allowlist.POST("", middleware.AuditAction(r.auditLogRepo, "ip_allowlist_add", "company"), r.ipAllowlistHandler.AddIPAllowlistEntry)             // Allow a range
+		allowlist.DELETE("/:id", middleware.AuditAction(r.auditLogRepo, "ip_allowlist_remove", "company"), r.ipAllowlistHandler.DeleteIPAllowlistEntry) // Remove a range
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupMaintenanceRoutes configures vehicle maintenance routes for fleet managers
+func (r *Router) setupMaintenanceRoutes(api *gin.RouterGroup) {
+	maintenance := api.Group("/vehicles")
+	maintenance.Use(r.authMiddleware.RequireAuth())
+	maintenance.Use(r.authMiddleware.RequireAnyRole("company_admin", "manager"))
+	maintenance.Use(middleware.RequireCompanyAccess())
+	{
+		maintenance.GET("/maintenance/due-soon", r.maintenanceHandler.GetMaintenanceDueSoon)  // Vehicles with services approaching
+		maintenance.POST("/:id/maintenance", r.maintenanceHandler.CreateMaintenance)          // Record a service
+		maintenance.GET("/:id/maintenance", r.maintenanceHandler.GetMaintenance)              // List vehicle services
+		maintenance.PUT("/:id/maintenance/:recordId", r.maintenanceHandler.UpdateMaintenance) // Update a service record
+	}
+}
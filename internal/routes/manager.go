@@ -12,6 +12,9 @@ func (r *Router) setupManagerRoutes() {
 	// User management (limited to same store/company)
 	manager.GET("/users", r.userHandler.GetUsers)
 
+	// Dashboard of the teams the current user manages
+	manager.GET("/dashboard", r.teamHandler.GetManagerDashboard)
+
 	// Team management (TODO: implement handlers)
 	// manager.GET("/teams", r.teamHandler.GetTeamsGin)
 	// manager.POST("/teams", r.teamHandler.CreateTeamGin)
@@ -1,5 +1,17 @@
 package routes
 
+import (
+	"time"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// Test emails go through the real SMTP server, so each master gets a small budget
+const (
+	testEmailRateLimit  = 5
+	testEmailRateWindow = time.Hour
+)
+
 func (r *Router) setupMasterRoutes() {
 	// Create Gin middleware from auth middleware
 	authMiddleware := r.authMiddleware
@@ -16,6 +28,7 @@ func (r *Router) setupMasterRoutes() {
 	master.GET("/users/:id", r.userHandler.GetUserByID)
 	master.PUT("/users/:id", r.userHandler.UpdateUser)
 	master.DELETE("/users/:id", r.userHandler.DeleteUser)
+	master.DELETE("/users/:id/purge", middleware.AuditAction(r.auditLogRepo, "user_purge", "user"), r.userHandler.PurgeUser)
 	// Company Management (master-only)
 	master.GET("/companies", r.companyHandler.GetCompanies)
 	master.POST("/companies", r.companyHandler.CreateCompany)
@@ -23,6 +36,11 @@ func (r *Router) setupMasterRoutes() {
 	master.PUT("/companies/:id", r.companyHandler.UpdateCompany)
 	master.DELETE("/companies/:id", r.companyHandler.DeleteCompany)
 
+	// Diagnostics (master-only)
+	master.GET("/diagnostics/database", r.diagnosticsHandler.DatabasePoolStats)
+	testEmailLimiter := middleware.NewActionRateLimiter("Test email", testEmailRateLimit, testEmailRateWindow)
+	master.POST("/test-email", testEmailLimiter.Middleware(), middleware.AuditAction(r.auditLogRepo, "test_email", "email"), r.diagnosticsHandler.SendTestEmail)
+
 	// System-wide Analytics (master-only)
 	// TODO: implement analytics handlers
 	// master.GET("/analytics/users", r.analyticsHandler.GetUserAnalytics)
@@ -1,5 +1,12 @@
 package routes
 
+import (
+	"time"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
 func (r *Router) setupProtectedRoutes() {
 	// Create Gin middleware from auth middleware
 	authMiddleware := r.authMiddleware
@@ -8,9 +15,22 @@ func (r *Router) setupProtectedRoutes() {
 	protected := r.engine.Group("/api/v1")
 	protected.Use(authMiddleware.RequireAuth())
 	protected.GET("/profile", r.authHandler.MeGin)
-	protected.POST("/profile/change-password", r.authHandler.ChangePasswordGin)
+	protected.POST("/profile/change-password", middleware.AuditAction(r.auditLogRepo, "password_change", "user"), r.authHandler.ChangePasswordGin)
+	protected.PATCH("/profile/preferences", r.authHandler.UpdatePreferencesGin)
+	protected.POST("/profile/avatar", middleware.AuditAction(r.auditLogRepo, "avatar_upload", "user"), r.authHandler.UploadAvatarGin)
+	protected.POST("/profile/logout-all", r.authHandler.LogoutAllGin)
+	// Exports read every table holding the user's data, so they are rate limited like audit exports
+	profileExportLimiter := middleware.NewActionRateLimiter(
+		"Profile export",
+		r.cfg.ExportRateLimit,
+		time.Duration(r.cfg.ExportRateWindowMinutes)*time.Minute,
+	)
+	protected.GET("/profile/export", profileExportLimiter.Middleware(), r.authHandler.ExportProfileGin)
+	protected.POST("/profile/delete-request", middleware.AuditAction(r.auditLogRepo, "account_deletion_request", "user"), r.userHandler.RequestAccountDeletion)
 	protected.GET("/roles", r.authHandler.GetRolesGin)
-	protected.GET("/users/:id/history", r.authHandler.GetUserHistoryGin)
+	readHistory := authMiddleware.RequireSelfOrPermission("id", models.PermissionUserReadHistory)
+	protected.GET("/users/:id/history", readHistory, r.authHandler.GetUserHistoryGin)
+	protected.GET("/users/:id/activities", readHistory, r.authHandler.GetUserActivitiesGin)
 
 	// Dashboard for all authenticated users (role-based filtering happens inside handler)
 	protected.GET("/dashboard", r.dashboardHandler.GetDashboard)
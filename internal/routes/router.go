@@ -1,114 +1,305 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"github.com/paulochiaradia/dashtrack/internal/config"
 	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/services"
+	"go.uber.org/zap"
+)
+
+// Email availability checks reveal whether an account exists, so each admin gets enough
+// for typing into a form but not for enumerating addresses
+const (
+	emailCheckRateLimit  = 30
+	emailCheckRateWindow = time.Minute
 )
 
 // Router struct holds all dependencies for the router
 type Router struct {
-	engine               *gin.Engine
-	cfg                  *config.Config
-	db                   *sqlx.DB
-	authHandler          *handlers.AuthHandler
-	userHandler          *handlers.UserHandler
-	sensorHandler        *handlers.SensorHandler
-	companyHandler       *handlers.CompanyHandler
-	teamHandler          *handlers.TeamHandler
-	vehicleHandler       *handlers.VehicleHandler
-	esp32Handler         *handlers.ESP32DeviceHandler
-	securityHandler      *handlers.SecurityHandler
-	sessionHandler       *handlers.SessionHandler
-	dashboardHandler     *handlers.DashboardHandler
-	auditHandler         *handlers.AuditHandler
-	passwordResetHandler *handlers.PasswordResetHandler
-	tokenService         *services.TokenService
-	auditService         *services.AuditService
-	emailService         *services.EmailService
-	authMiddleware       *middleware.GinAuthMiddleware
+	engine                 *gin.Engine
+	cfg                    *config.Config
+	db                     *sqlx.DB
+	authHandler            *handlers.AuthHandler
+	userHandler            *handlers.UserHandler
+	sensorHandler          *handlers.SensorHandler
+	companyHandler         *handlers.CompanyHandler
+	teamHandler            *handlers.TeamHandler
+	vehicleHandler         *handlers.VehicleHandler
+	esp32Handler           *handlers.ESP32DeviceHandler
+	securityHandler        *handlers.SecurityHandler
+	sessionHandler         *handlers.SessionHandler
+	dashboardHandler       *handlers.DashboardHandler
+	auditHandler           *handlers.AuditHandler
+	passwordResetHandler   *handlers.PasswordResetHandler
+	webhookHandler         *handlers.WebhookHandler
+	ipAllowlistHandler     *handlers.IPAllowlistHandler
+	companySettingsHandler *handlers.CompanySettingsHandler
+	maintenanceHandler     *handlers.MaintenanceHandler
+	vehicleDocumentHandler *handlers.VehicleDocumentHandler
+	fuelHandler            *handlers.FuelHandler
+	tripHandler            *handlers.TripHandler
+	vehicleStreamHandler   *handlers.VehicleStreamHandler
+	diagnosticsHandler     *handlers.DiagnosticsHandler
+	tokenService           *services.TokenService
+	auditLogRepo           repository.AuditLogRepositoryInterface
+	auditService           *services.AuditService
+	emailService           services.EmailSender
+	documentExpiryNotifier *services.DocumentExpiryNotifier
+	assignmentNotifier     *services.AssignmentNotifier
+	staleTripCloser        *services.StaleTripCloser
+	activityMetrics        *services.ActivityMetricsCollector
+	accountErasureJob      *services.AccountErasureJob
+	idempotencyRepo        repository.IdempotencyRepositoryInterface
+	idempotencyKeyCleaner  *services.IdempotencyKeyCleaner
+	mqttBridge             *services.MQTTBridge
+	authMiddleware         *middleware.GinAuthMiddleware
+	sessionCookies         *middleware.SessionCookies
+}
+
+// uploadRoutes accept multipart or CSV bodies instead of JSON
+var uploadRoutes = []string{
+	"/api/v1/profile/avatar",
+	"/api/v1/vehicles/import",
 }
 
-// NewRouter creates and configures a new router
-func NewRouter(db *sql.DB, cfg *config.Config) *Router {
+// NewRouter creates and configures a new router. replica is an optional read-only
+// pool for reporting queries; when nil everything runs on db.
+func NewRouter(db *sql.DB, replica *sql.DB, cfg *config.Config) *Router {
 	if cfg.ServerEnv == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
 	// Repositories
+	repository.SetQueryTimeout(time.Duration(cfg.DBQueryTimeoutSeconds) * time.Second)
+	repository.SetSlowQueryThreshold(time.Duration(cfg.DBSlowQueryThresholdMS) * time.Millisecond)
 	userRepo := repository.NewUserRepository(sqlxDB)
 	roleRepo := repository.NewRoleRepository(db)
 	authLogRepo := repository.NewAuthLogRepository(db)
 	sensorRepo := repository.NewSensorRepository(sqlxDB)
 	companyRepo := repository.NewCompanyRepository(sqlxDB)
 	teamRepo := repository.NewTeamRepository(sqlxDB)
+	teamRepo.SetSoftDeleteMembers(cfg.TeamMemberSoftDelete)
 	vehicleRepo := repository.NewVehicleRepository(sqlxDB)
 	esp32Repo := repository.NewESP32DeviceRepository(sqlxDB)
 
 	sessionRepo := repository.NewSessionRepository(sqlxDB)
+	webhookRepo := repository.NewWebhookRepository(sqlxDB)
+	ipAllowlistRepo := repository.NewIPAllowlistRepository(sqlxDB)
+	companySettingsRepo := repository.NewCompanySettingsRepository(sqlxDB)
+	maintenanceRepo := repository.NewMaintenanceRepository(sqlxDB)
+	vehicleDocumentRepo := repository.NewVehicleDocumentRepository(sqlxDB)
+	fuelRepo := repository.NewFuelRepository(sqlxDB)
+	tripRepo := repository.NewTripRepository(sqlxDB)
+	auditLogRepo := repository.NewAuditLogRepository(sqlxDB)
+	if len(cfg.AuditRedactKeys) > 0 {
+		auditLogRepo.SetRedactedKeys(cfg.AuditRedactKeys)
+	}
+	passwordHistoryRepo := repository.NewPasswordHistoryRepository(sqlxDB)
+	profileExportRepo := repository.NewProfileExportRepository(sqlxDB)
+
+	// Listing, search, history and dashboard reads go to the replica when one is configured
+	var sqlxReplica *sqlx.DB
+	if replica != nil {
+		sqlxReplica = sqlx.NewDb(replica, "postgres")
+		userRepo.SetReadReplica(sqlxReplica)
+		authLogRepo.SetReadReplica(sqlxReplica)
+		companyRepo.SetReadReplica(sqlxReplica)
+		auditLogRepo.SetReadReplica(sqlxReplica)
+		profileExportRepo.SetReadReplica(sqlxReplica)
+	}
 
 	// Services
 	accessExpiry := time.Duration(cfg.JWTAccessExpireMinutes) * time.Minute
 	refreshExpiry := time.Duration(cfg.JWTRefreshExpireHours) * time.Hour
 	tokenService := services.NewTokenService(sqlxDB, cfg.JWTSecret, accessExpiry, refreshExpiry)
+	tokenService.SetSessionIdleTimeout(time.Duration(cfg.SessionIdleTimeoutMinutes) * time.Minute)
+	tokenService.SetRememberMeRefreshTTL(time.Duration(cfg.JWTRememberMeRefreshExpireHours) * time.Hour)
+	tokenService.SetCompanySettings(companySettingsRepo)
+	if err := useJWTKeyring(tokenService, cfg); err != nil {
+		logger.Fatal("Invalid JWT keys", zap.Error(err))
+	}
 	twoFactorService := services.NewTwoFactorService(sqlxDB)
-	auditService := services.NewAuditService(sqlxDB)
+	auditService := services.NewAuditServiceWithRepository(sqlxDB, auditLogRepo)
 	sessionManager := services.NewSessionManager(sqlxDB)
+	fileStorage := newFileStorage(cfg)
 	userService := services.NewUserService(userRepo, roleRepo, cfg.BcryptCost)
-	emailService := services.NewEmailService(cfg)
+	userService.SetSessionRevoker(tokenService)
+	userService.SetFileStorage(fileStorage)
+	userService.SetOnlineThreshold(time.Duration(cfg.OnlineThresholdMinutes) * time.Minute)
+	emailService, err := services.NewEmailSender(cfg)
+	if err != nil {
+		logger.Warn("Email sending disabled: invalid SMTP configuration", zap.Error(err))
+		emailService = services.NewNoopEmailService()
+	}
+	erasureGracePeriod := time.Duration(cfg.AccountErasureGraceDays) * 24 * time.Hour
+	userService.SetAccountDeletionMailer(emailService)
+	userService.SetErasureGracePeriod(erasureGracePeriod)
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo)
+	documentExpiryNotifier := services.NewDocumentExpiryNotifier(vehicleDocumentRepo, companyRepo, userRepo, emailService, cfg.DocumentExpiryAlertDays)
+	staleTripCloser := services.NewStaleTripCloser(tripRepo, time.Duration(cfg.TripMaxDurationHours)*time.Hour)
+	activityMetrics := services.NewActivityMetricsCollector(sqlxDB, time.Duration(cfg.MetricsRefreshIntervalSeconds)*time.Second)
+	accountErasureJob := services.NewAccountErasureJob(sqlxDB, erasureGracePeriod)
+	accountErasureJob.SetFileStorage(fileStorage)
+	idempotencyRepo := repository.NewIdempotencyRepository(sqlxDB)
+	idempotencyKeyCleaner := services.NewIdempotencyKeyCleaner(idempotencyRepo)
 
 	// Set email service in token service for session limit notifications
 	tokenService.SetEmailService(emailService)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(userRepo, authLogRepo, roleRepo, tokenService, emailService, cfg.BcryptCost)
+	authHandler.SetAuditLogRepository(auditLogRepo)
+	ipAllowlist := services.NewIPAllowlist(ipAllowlistRepo)
+	ipAllowlist.SetCompanySettings(companySettingsRepo)
+	authHandler.SetIPAllowlist(ipAllowlist)
+	authHandler.SetPasswordExpiryPolicy(services.NewPasswordExpiryPolicy(cfg.PasswordMaxAgeDays, companySettingsRepo))
+	authHandler.SetPasswordHistory(passwordHistoryRepo, cfg.PasswordHistorySize)
+	authHandler.SetProfileExportRepository(profileExportRepo)
+	if sqlxReplica != nil {
+		authHandler.SetReadReplica(sqlxReplica)
+	}
+	if cfg.GeoIPEnabled {
+		// Geo data isn't always available; logins keep working without the check
+		geoIP, err := services.NewCIDRCountryProviderFromFile(cfg.GeoIPDatabasePath)
+		if err != nil {
+			logger.Warn("Suspicious login detection disabled: GeoIP database unavailable", zap.Error(err))
+		} else {
+			authHandler.SetSuspiciousLoginDetector(services.NewSuspiciousLoginDetector(geoIP, authLogRepo))
+		}
+	}
+	authHandler.SetAvatarService(services.NewAvatarService(fileStorage, userRepo, int64(cfg.AvatarMaxBytes), cfg.AvatarMaxDimension))
 	userHandler := handlers.NewUserHandler(userService)
-	sensorHandler := handlers.NewSensorHandler(sensorRepo)
+	userHandler.SetAuditLogRepository(auditLogRepo)
+	liveFeed := services.NewLiveFeedHub(services.DefaultLiveFeedBuffer)
+	sensorIngestion := services.NewSensorIngestionService(sensorRepo)
+	sensorHandler := handlers.NewSensorHandler(sensorRepo, sensorIngestion)
 	companyHandler := handlers.NewCompanyHandler(companyRepo)
+	companyHandler.SetUserRepository(userRepo)
+	companyHandler.SetAuditLogRepository(auditLogRepo)
 	teamHandler := handlers.NewTeamHandler(teamRepo, userRepo, vehicleRepo)
+	teamHandler.SetMaxBatchSize(cfg.BatchMaxSize)
 	vehicleHandler := handlers.NewVehicleHandler(vehicleRepo, teamRepo)
+	vehicleHandler.SetImportMaxRows(cfg.BatchMaxSize)
 	esp32Handler := handlers.NewESP32DeviceHandler(esp32Repo, vehicleRepo)
 	securityHandler := handlers.NewSecurityHandler(tokenService, twoFactorService, auditService)
 	sessionHandler := handlers.NewSessionHandler(sessionManager)
 	dashboardHandler := handlers.NewDashboardHandler(userRepo, authLogRepo, sessionRepo, companyRepo)
 	auditHandler := handlers.NewAuditHandler(auditService)
+	auditHandler.SetExportMaxRows(cfg.ExportMaxRows)
 	passwordResetHandler := handlers.NewPasswordResetHandler(db, emailService)
+	passwordResetHandler.SetPasswordHistory(passwordHistoryRepo, cfg.PasswordHistorySize)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo)
+	ipAllowlistHandler := handlers.NewIPAllowlistHandler(ipAllowlistRepo)
+	companySettingsHandler := handlers.NewCompanySettingsHandler(companySettingsRepo)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceRepo, vehicleRepo)
+	vehicleDocumentHandler := handlers.NewVehicleDocumentHandler(vehicleDocumentRepo, vehicleRepo)
+	fuelHandler := handlers.NewFuelHandler(fuelRepo, vehicleRepo)
+	tripHandler := handlers.NewTripHandler(tripRepo, vehicleRepo)
+	tripHandler.SetMaxPoints(cfg.BatchMaxSize)
+	vehicleStreamHandler := handlers.NewVehicleStreamHandler(liveFeed, vehicleRepo)
+	vehicleStreamHandler.SetAllowedOrigins(cfg.CORSAllowedOrigins)
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(db)
+
+	// Email notifications
+	teamHandler.SetManagerNotifier(emailService)
+	diagnosticsHandler.SetTestEmailSender(emailService)
+
+	// Real-time vehicle feed, published by sensor ingestion and trip GPS points
+	sensorIngestion.SetLiveFeed(liveFeed, esp32Repo)
+	tripHandler.SetLiveFeed(liveFeed)
+
+	// Tracker readings over MQTT go through the same ingestion as the HTTP endpoint
+	mqttBridge := services.NewMQTTBridge(services.MQTTConfig{
+		BrokerURL: cfg.MQTTBrokerURL,
+		ClientID:  cfg.MQTTClientID,
+		Username:  cfg.MQTTUsername,
+		Password:  cfg.MQTTPassword,
+		QoS:       byte(cfg.MQTTQoS),
+	}, sensorIngestion, esp32Repo)
+
+	// Event notifications to company webhooks
+	authHandler.SetWebhookDispatcher(webhookDispatcher)
+	teamHandler.SetWebhookDispatcher(webhookDispatcher)
+	vehicleHandler.SetWebhookDispatcher(webhookDispatcher)
+
+	// Opt-in emails to drivers, helpers and managers about vehicle crew changes
+	assignmentNotifier := services.NewAssignmentNotifier(userRepo, emailService)
+	vehicleHandler.SetAssignmentNotifier(assignmentNotifier)
+	vehicleHandler.SetCompanySettings(companySettingsRepo)
 
 	// Middleware
 	authMiddleware := middleware.NewGinAuthMiddleware(tokenService)
+	authMiddleware.SetAPITokenLookup(userRepo)
+	authMiddleware.SetPermissionChecker(services.NewRolePermissions(roleRepo))
+
+	// Cookie sessions for browser clients, protected by a double-submit CSRF token
+	var sessionCookies *middleware.SessionCookies
+	if cfg.AuthCookieEnabled {
+		sessionCookies = middleware.NewSessionCookies(cfg.AuthCookieDomain, cfg.AuthCookieSecure, cfg.AuthCookieSameSite)
+		authMiddleware.SetSessionCookies(sessionCookies)
+		authHandler.SetSessionCookies(sessionCookies)
+	}
+
+	engine := gin.New()
+	if err := configureClientIP(engine, cfg); err != nil {
+		logger.Fatal("Invalid trusted proxy configuration", zap.Error(err))
+	}
 
 	router := &Router{
-		engine:               gin.New(),
-		cfg:                  cfg,
-		db:                   sqlxDB,
-		authHandler:          authHandler,
-		userHandler:          userHandler,
-		sensorHandler:        sensorHandler,
-		companyHandler:       companyHandler,
-		teamHandler:          teamHandler,
-		vehicleHandler:       vehicleHandler,
-		esp32Handler:         esp32Handler,
-		securityHandler:      securityHandler,
-		sessionHandler:       sessionHandler,
-		dashboardHandler:     dashboardHandler,
-		auditHandler:         auditHandler,
-		passwordResetHandler: passwordResetHandler,
-		tokenService:         tokenService,
-		auditService:         auditService,
-		emailService:         emailService,
-		authMiddleware:       authMiddleware,
+		engine:                 engine,
+		cfg:                    cfg,
+		db:                     sqlxDB,
+		authHandler:            authHandler,
+		userHandler:            userHandler,
+		sensorHandler:          sensorHandler,
+		companyHandler:         companyHandler,
+		teamHandler:            teamHandler,
+		vehicleHandler:         vehicleHandler,
+		esp32Handler:           esp32Handler,
+		securityHandler:        securityHandler,
+		sessionHandler:         sessionHandler,
+		dashboardHandler:       dashboardHandler,
+		auditHandler:           auditHandler,
+		passwordResetHandler:   passwordResetHandler,
+		webhookHandler:         webhookHandler,
+		ipAllowlistHandler:     ipAllowlistHandler,
+		companySettingsHandler: companySettingsHandler,
+		maintenanceHandler:     maintenanceHandler,
+		vehicleDocumentHandler: vehicleDocumentHandler,
+		fuelHandler:            fuelHandler,
+		tripHandler:            tripHandler,
+		vehicleStreamHandler:   vehicleStreamHandler,
+		diagnosticsHandler:     diagnosticsHandler,
+		tokenService:           tokenService,
+		auditLogRepo:           auditLogRepo,
+		auditService:           auditService,
+		emailService:           emailService,
+		documentExpiryNotifier: documentExpiryNotifier,
+		assignmentNotifier:     assignmentNotifier,
+		staleTripCloser:        staleTripCloser,
+		activityMetrics:        activityMetrics,
+		accountErasureJob:      accountErasureJob,
+		idempotencyRepo:        idempotencyRepo,
+		idempotencyKeyCleaner:  idempotencyKeyCleaner,
+		mqttBridge:             mqttBridge,
+		authMiddleware:         authMiddleware,
+		sessionCookies:         sessionCookies,
 	}
 
 	router.setupMiddleware()
 	router.setupRoutes()
+	router.setupFallbackHandlers()
 
 	return router
 }
@@ -116,6 +307,9 @@ func NewRouter(db *sql.DB, cfg *config.Config) *Router {
 func (r *Router) setupMiddleware() {
 	r.engine.Use(gin.Recovery())
 
+	// Request info - lets repositories record method, path and status of the current request
+	r.engine.Use(middleware.RequestInfoMiddleware())
+
 	// Logging middleware - logs all HTTP requests including health checks
 	r.engine.Use(middleware.GinLoggingMiddleware())
 
@@ -123,8 +317,25 @@ func (r *Router) setupMiddleware() {
 	// Skips health and metrics endpoints
 	r.engine.Use(middleware.AuditMiddleware(r.auditService))
 
+	// CORS - only for the configured browser origins
+	if len(r.cfg.CORSAllowedOrigins) > 0 {
+		r.engine.Use(middleware.CORSMiddleware(r.cfg.CORSAllowedOrigins))
+	}
+
+	// CSRF - requests authenticated by session cookie must echo the CSRF cookie
+	if r.sessionCookies != nil {
+		r.engine.Use(r.sessionCookies.CSRFProtection())
+	}
+
+	// Content-Type - write requests must send JSON, except the upload routes
+	r.engine.Use(middleware.RequireJSONContentType(uploadRoutes...))
+
+	// Idempotency keys - retried writes carrying the same Idempotency-Key get the stored response
+	if r.cfg.IdempotencyKeyTTLHours > 0 {
+		r.engine.Use(middleware.Idempotency(r.idempotencyRepo, time.Duration(r.cfg.IdempotencyKeyTTLHours)*time.Hour))
+	}
+
 	// TODO: Add other middlewares when they are implemented
-	// r.engine.Use(middleware.CORSMiddleware())
 	// r.engine.Use(middleware.RateLimitMiddleware())
 	// r.engine.Use(middleware.SecurityHeaders())
 }
@@ -138,6 +349,7 @@ func (r *Router) setupRoutes() {
 	{
 		public.POST("/login", r.authHandler.LoginGin)
 		public.POST("/refresh", r.authHandler.RefreshTokenGin)
+		public.POST("/change-expired-password", middleware.AuditAction(r.auditLogRepo, "password_change", "user"), r.authHandler.ChangeExpiredPasswordGin)
 
 		// Password recovery routes
 		public.POST("/forgot-password", r.passwordResetHandler.ForgotPassword)
@@ -145,13 +357,18 @@ func (r *Router) setupRoutes() {
 		public.POST("/reset-password", r.passwordResetHandler.ResetPassword)
 	}
 
+	// Uploaded files stored on local disk (avatars) are public, like objects in an S3 bucket
+	if r.cfg.StorageDriver == "local" && strings.HasPrefix(r.cfg.StoragePublicURL, "/") {
+		r.engine.Static(r.cfg.StoragePublicURL, r.cfg.StorageLocalDir)
+	}
+
 	// Protected routes (authentication required)
 	protected := v1.Group("")
 	protected.Use(r.authMiddleware.RequireAuth())
 	{
 		// Auth routes
-		protected.POST("/auth/logout", r.authHandler.LogoutGin)
-		protected.POST("/auth/change-password", r.authHandler.ChangePasswordGin)
+		protected.POST("/auth/logout", middleware.AuditAction(r.auditLogRepo, "logout", "session"), r.authHandler.LogoutGin)
+		protected.POST("/auth/change-password", middleware.AuditAction(r.auditLogRepo, "password_change", "user"), r.authHandler.ChangePasswordGin)
 
 		// User routes with role-based access
 		userRoutes := protected.Group("/users")
@@ -167,6 +384,9 @@ func (r *Router) setupRoutes() {
 		adminRoutes.Use(r.authMiddleware.RequireAnyRole("admin", "company_admin"))
 		{
 			adminRoutes.POST("/users", r.userHandler.CreateUser) // Create user
+
+			emailCheckLimiter := middleware.NewActionRateLimiter("Email check", emailCheckRateLimit, emailCheckRateWindow)
+			adminRoutes.GET("/users/check-email", emailCheckLimiter.Middleware(), r.userHandler.CheckEmail) // Email availability
 		}
 		// Master-only routes
 		masterRoutes := protected.Group("")
@@ -184,12 +404,86 @@ func (r *Router) setupRoutes() {
 	r.setupTeamRoutes()      // Team management routes
 	r.setupVehicleRoutes(v1) // Vehicle management routes (Phase 4)
 	r.setupHealthRoutes()
+	r.setupDocsRoutes() // OpenAPI spec and Swagger UI
 	r.setupSecurityRoutes()
 	r.setupSessionRoutes()
-	r.setupAuditRoutes(v1) // Audit logs routes
+	r.setupAuditRoutes(v1)           // Audit logs routes
+	r.setupWebhookRoutes(v1)         // Company webhook routes
+	r.setupIPAllowlistRoutes(v1)     // Company login IP allowlist routes
+	r.setupCompanySettingsRoutes(v1) // Company settings routes
+	r.setupMaintenanceRoutes(v1)     // Vehicle maintenance routes
+	r.setupVehicleDocumentRoutes(v1) // Vehicle document routes
+	r.setupFuelRoutes(v1)            // Fuel log and odometer routes
+	r.setupTripRoutes(v1)            // Trip GPS route points
+	r.setupVehicleStreamRoutes(v1)   // Real-time vehicle feed (WebSocket)
+}
+
+// newFileStorage returns the storage selected by STORAGE_DRIVER
+func newFileStorage(cfg *config.Config) services.FileStorage {
+	if cfg.StorageDriver == "s3" {
+		return services.NewS3FileStorage(services.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PublicURL:       cfg.S3PublicURL,
+		})
+	}
+	return services.NewLocalFileStorage(cfg.StorageLocalDir, cfg.StoragePublicURL)
 }
 
 // Engine returns the gin engine
 func (r *Router) Engine() *gin.Engine {
 	return r.engine
 }
+
+// StartBackgroundJobs starts the periodic jobs that run alongside the HTTP server
+func (r *Router) StartBackgroundJobs(ctx context.Context) {
+	if r.cfg.DocumentExpiryAlertDays > 0 {
+		r.documentExpiryNotifier.Start(ctx)
+	}
+	if r.cfg.TripMaxDurationHours > 0 {
+		r.staleTripCloser.Start(ctx)
+	}
+	if r.cfg.MetricsRefreshIntervalSeconds > 0 {
+		r.activityMetrics.Start(ctx)
+	}
+	r.accountErasureJob.Start(ctx)
+	r.assignmentNotifier.Start(ctx)
+	if r.cfg.IdempotencyKeyTTLHours > 0 {
+		r.idempotencyKeyCleaner.Start(ctx)
+	}
+}
+
+// StartMQTTBridge starts ingesting tracker readings from the MQTT broker until ctx is cancelled
+func (r *Router) StartMQTTBridge(ctx context.Context) {
+	r.mqttBridge.Start(ctx)
+}
+
+// useJWTKeyring makes tokenService sign with the configured JWT keys
+func useJWTKeyring(tokenService *services.TokenService, cfg *config.Config) error {
+	keys, err := cfg.JWTSigningKeys()
+	if err != nil {
+		return err
+	}
+	keyring, err := services.NewJWTKeyring(keys, cfg.JWTSigningKeyID())
+	if err != nil {
+		return err
+	}
+	tokenService.SetKeyring(keyring)
+	return nil
+}
+
+// configureClientIP sets which proxies and headers c.ClientIP() trusts. Without trusted
+// proxies it returns the connection's address, so a client can't spoof its IP with a header.
+func configureClientIP(engine *gin.Engine, cfg *config.Config) error {
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return err
+	}
+	if len(cfg.ClientIPHeaders) > 0 {
+		engine.RemoteIPHeaders = cfg.ClientIPHeaders
+	}
+	engine.TrustedPlatform = cfg.TrustedPlatformHeader()
+	return nil
+}
@@ -1,5 +1,9 @@
 package routes
 
+import (
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
 func (r *Router) setupTeamRoutes() {
 	authMiddleware := r.authMiddleware
 
@@ -14,12 +18,14 @@ func (r *Router) setupTeamRoutes() {
 	companyAdmin.GET("", r.teamHandler.GetTeams)          // List all teams
 	companyAdmin.POST("", r.teamHandler.CreateTeam)       // Create team
 	companyAdmin.GET("/:id", r.teamHandler.GetTeam)       // Get team details
-	companyAdmin.PUT("/:id", r.teamHandler.UpdateTeam)    // Update team
+	companyAdmin.PUT("/:id", r.teamHandler.UpdateTeam)    // Replace team
+	companyAdmin.PATCH("/:id", r.teamHandler.PatchTeam)   // Partially update team
 	companyAdmin.DELETE("/:id", r.teamHandler.DeleteTeam) // Delete team
 
 	// Member Management
 	companyAdmin.GET("/:id/members", r.teamHandler.GetMembers)                             // List team members
 	companyAdmin.POST("/:id/members", r.teamHandler.AddMember)                             // Add member to team
+	companyAdmin.POST("/:id/members/bulk", r.teamHandler.AddMembersBulk)                   // Add several members to team
 	companyAdmin.DELETE("/:id/members/:userId", r.teamHandler.RemoveMember)                // Remove member from team
 	companyAdmin.PUT("/:id/members/:userId/role", r.teamHandler.UpdateMemberRole)          // Update member role
 	companyAdmin.POST("/:id/members/:userId/transfer", r.teamHandler.TransferMemberToTeam) // Transfer member to another team
@@ -36,6 +42,16 @@ func (r *Router) setupTeamRoutes() {
 	companyAdmin.GET("/:id/member-history", r.teamHandler.GetTeamMemberHistory)       // Get team member history
 	companyAdmin.GET("/users/:userId/team-history", r.teamHandler.GetUserTeamHistory) // Get user team membership history
 
+	// ==================================================
+	// COMPANY ANALYTICS - Team membership growth
+	// ==================================================
+	growth := r.engine.Group("/api/v1/company/team-growth")
+	growth.Use(authMiddleware.RequireAuth())
+	growth.Use(authMiddleware.RequireAnyRole("company_admin", "admin"))
+	growth.Use(middleware.RequireCompanyAccess())
+
+	growth.GET("", r.teamHandler.GetTeamGrowth) // Member added vs removed per bucket
+
 	// ==================================================
 	// ADMIN ROUTES - Team Management within Company
 	// ==================================================
@@ -73,4 +89,17 @@ func (r *Router) setupTeamRoutes() {
 
 	// Any authenticated user can view teams they belong to
 	user.GET("/my-teams", r.teamHandler.GetMyTeams) // Get current user's teams
+
+	// ==================================================
+	// OWNERSHIP - Hand a team over to a new manager, archive or restore it
+	// ==================================================
+	ownership := r.engine.Group("/api/v1/teams")
+	ownership.Use(authMiddleware.RequireAuth())
+	ownership.Use(authMiddleware.RequireRole("company_admin"))
+	ownership.Use(middleware.RequireCompanyAccess())
+
+	ownership.PUT("/:id/manager", r.teamHandler.ChangeTeamManager)    // Change team manager and notify both managers
+	ownership.POST("/:id/archive", r.teamHandler.ArchiveTeam)         // Hide team from default lists
+	ownership.POST("/:id/unarchive", r.teamHandler.UnarchiveTeam)     // Restore archived team
+	ownership.POST("/:id/members/bulk", r.teamHandler.AddMembersBulk) // Add several members to team
 }
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupTripRoutes configures trip route (GPS points) routes
+func (r *Router) setupTripRoutes(api *gin.RouterGroup) {
+	// Devices and integrations stream positions with an API token
+	device := api.Group("/vehicles")
+	device.Use(r.authMiddleware.RequireAPIToken())
+	device.Use(middleware.RequireCompanyAccess())
+	{
+		device.POST("/:id/trips/:tripId/points", r.tripHandler.AddTripPoints) // Record a batch of GPS points
+		device.POST("/:id/trips/:tripId/end", r.tripHandler.EndTrip)          // End an active trip
+	}
+
+	trips := api.Group("/vehicles")
+	trips.Use(r.authMiddleware.RequireAuth())
+	trips.Use(middleware.RequireCompanyAccess())
+	{
+		trips.GET("/:id/trips/:tripId/points", r.tripHandler.GetTripPoints) // Trip polyline
+	}
+}
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
 )
 
 // setupVehicleRoutes configures vehicle management routes
@@ -40,10 +42,28 @@ func (r *Router) setupVehicleRoutes(api *gin.RouterGroup) {
 		manager.GET("/:id", r.vehicleHandler.GetVehicle) // Get vehicle details
 	}
 
+	// Live operations view of trips in progress (any company member)
+	trips := api.Group("/company/trips")
+	trips.Use(r.authMiddleware.RequireAuth())
+	trips.Use(middleware.RequireCompanyAccess())
+	{
+		trips.GET("/active", r.vehicleHandler.GetActiveTrips) // List active trips with vehicle and driver
+	}
+
 	// Driver/Assistant routes (read-only for assigned vehicles)
 	user := api.Group("/vehicles")
 	user.Use(r.authMiddleware.RequireAuth())
 	{
-		user.GET("/my-vehicle", r.vehicleHandler.GetMyVehicle) // Get vehicle assigned to current user
+		user.GET("/my-vehicle", r.vehicleHandler.GetMyVehicle)                                                // Get vehicle assigned to current user
+		user.GET("/import/template", r.vehicleHandler.GetImportTemplate)                                      // CSV header expected by the vehicle import
+		user.GET("/by-plate/:plate", middleware.RequireCompanyAccess(), r.vehicleHandler.GetVehicleByPlate)   // Look up a vehicle by license plate
+		user.GET("/:id/assignment", middleware.RequireCompanyAccess(), r.vehicleHandler.GetVehicleAssignment) // Current driver, helper and team
+
+		// Bulk create from a CSV file (company admins)
+		user.POST("/import",
+			r.authMiddleware.RequireAnyRole("company_admin"),
+			middleware.RequireCompanyAccess(),
+			middleware.AuditAction(r.auditLogRepo, "vehicle_import", "vehicle"),
+			r.vehicleHandler.ImportVehicles)
 	}
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupVehicleDocumentRoutes configures vehicle document routes for fleet managers
+func (r *Router) setupVehicleDocumentRoutes(api *gin.RouterGroup) {
+	documents := api.Group("/vehicles")
+	documents.Use(r.authMiddleware.RequireAuth())
+	documents.Use(r.authMiddleware.RequireAnyRole("company_admin", "manager"))
+	documents.Use(middleware.RequireCompanyAccess())
+	{
+		documents.GET("/documents/expiring", r.vehicleDocumentHandler.GetExpiringDocuments)     // Documents expiring soon
+		documents.POST("/:id/documents", r.vehicleDocumentHandler.CreateDocument)               // Register a document
+		documents.GET("/:id/documents", r.vehicleDocumentHandler.GetDocuments)                  // List vehicle documents
+		documents.GET("/:id/documents/:documentId", r.vehicleDocumentHandler.GetDocument)       // Get a document
+		documents.PUT("/:id/documents/:documentId", r.vehicleDocumentHandler.UpdateDocument)    // Update a document
+		documents.DELETE("/:id/documents/:documentId", r.vehicleDocumentHandler.DeleteDocument) // Delete a document
+	}
+}
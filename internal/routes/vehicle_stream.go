@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupVehicleStreamRoutes configures the real-time vehicle feed
+func (r *Router) setupVehicleStreamRoutes(api *gin.RouterGroup) {
+	stream := api.Group("/vehicles")
+	stream.Use(r.authMiddleware.RequireAuth())
+	stream.Use(middleware.RequireCompanyAccess())
+	{
+		stream.GET("/:id/stream", r.vehicleStreamHandler.StreamVehicle) // WebSocket of sensor readings and alerts
+	}
+}
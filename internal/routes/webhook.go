@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+// setupWebhookRoutes configures company webhook management routes
+func (r *Router) setupWebhookRoutes(api *gin.RouterGroup) {
+	webhooks := api.Group("/company/webhooks")
+	webhooks.Use(r.authMiddleware.RequireAuth())
+	webhooks.Use(r.authMiddleware.RequireRole("company_admin"))
+	webhooks.Use(middleware.RequireCompanyAccess())
+	{
+		webhooks.POST("", r.webhookHandler.CreateWebhook)       // Register webhook
+		webhooks.GET("", r.webhookHandler.GetWebhooks)          // List webhooks
+		webhooks.GET("/:id", r.webhookHandler.GetWebhook)       // Get webhook details
+		webhooks.PUT("/:id", r.webhookHandler.UpdateWebhook)    // Update webhook
+		webhooks.DELETE("/:id", r.webhookHandler.DeleteWebhook) // Delete webhook
+	}
+
+	// Signing secret rotation, served under /webhooks rather than /company/webhooks
+	secrets := api.Group("/webhooks")
+	secrets.Use(r.authMiddleware.RequireAuth())
+	secrets.Use(r.authMiddleware.RequireRole("company_admin"))
+	secrets.Use(middleware.RequireCompanyAccess())
+	{
+		secrets.POST("/:id/rotate-secret", r.webhookHandler.RotateWebhookSecret) // Rotate signing secret
+	}
+}
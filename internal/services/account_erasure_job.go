@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// AccountErasureJob anonymizes the personal data kept in auth_logs and audit_logs for
+// users whose account deletion request is older than the grace period, using
+// repository.AnonymizeUserLogs so aggregate statistics stay correct, and deletes their
+// stored avatar. It runs hourly.
+type AccountErasureJob struct {
+	db          *sqlx.DB
+	storage     FileStorage
+	gracePeriod time.Duration
+	interval    time.Duration
+}
+
+// NewAccountErasureJob creates a job erasing users gracePeriod after their deletion request
+func NewAccountErasureJob(db *sqlx.DB, gracePeriod time.Duration) *AccountErasureJob {
+	return &AccountErasureJob{
+		db:          db,
+		gracePeriod: gracePeriod,
+		interval:    time.Hour,
+	}
+}
+
+// SetFileStorage sets where uploaded avatars are stored, so erased users' pictures are deleted
+func (j *AccountErasureJob) SetFileStorage(storage FileStorage) {
+	j.storage = storage
+}
+
+// Start runs the job immediately and then once per interval until ctx is cancelled
+func (j *AccountErasureJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := j.RunOnce(ctx); err != nil {
+				logger.Error("Account erasure job failed", zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce anonymizes the logs of every user whose grace period has elapsed and returns
+// how many users were erased. A failure for one user doesn't stop the others.
+func (j *AccountErasureJob) RunOnce(ctx context.Context) (int, error) {
+	var userIDs []uuid.UUID
+	query := `
+		SELECT id FROM users
+		WHERE erasure_requested_at IS NOT NULL AND erased_at IS NULL AND erasure_requested_at <= $1
+		ORDER BY erasure_requested_at`
+	if err := j.db.SelectContext(ctx, &userIDs, query, time.Now().Add(-j.gracePeriod)); err != nil {
+		return 0, fmt.Errorf("failed to list pending erasures: %w", err)
+	}
+
+	erased := 0
+	for _, userID := range userIDs {
+		if err := j.eraseUser(ctx, userID); err != nil {
+			logger.Error("Failed to erase user data",
+				zap.Error(err),
+				zap.String("user_id", userID.String()),
+			)
+			continue
+		}
+		erased++
+	}
+
+	if erased > 0 {
+		logger.Info("Erased personal data of deleted accounts", zap.Int("users", erased))
+	}
+	return erased, nil
+}
+
+// eraseUser anonymizes the logs of one user, clears their avatar and marks them as erased,
+// in a single transaction; the avatar file is deleted once it is committed
+func (j *AccountErasureJob) eraseUser(ctx context.Context, userID uuid.UUID) error {
+	tx, err := j.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var user struct {
+		Email  string  `db:"email"`
+		Avatar *string `db:"avatar"`
+	}
+	if err := tx.GetContext(ctx, &user, `SELECT email, avatar FROM users WHERE id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to get user email: %w", err)
+	}
+
+	counts, err := repository.AnonymizeUserLogs(ctx, tx, userID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET erased_at = NOW(), avatar = NULL WHERE id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to mark user as erased: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	deleteStoredAvatar(ctx, j.storage, user.Avatar)
+
+	logger.Info("Anonymized logs of deleted account",
+		zap.String("user_id", userID.String()),
+		zap.Int64("auth_logs", counts.AuthLogs),
+		zap.Int64("audit_logs", counts.AuditLogs),
+	)
+	return nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/metrics"
+)
+
+// ActivityMetricsCollector periodically recounts the active sessions and trips and
+// publishes them on the active_sessions_total and active_trips_total gauges, so capacity
+// can be monitored from Prometheus without querying the database directly.
+type ActivityMetricsCollector struct {
+	db       *sqlx.DB
+	interval time.Duration
+}
+
+// NewActivityMetricsCollector creates a collector that refreshes the gauges every interval
+func NewActivityMetricsCollector(db *sqlx.DB, interval time.Duration) *ActivityMetricsCollector {
+	return &ActivityMetricsCollector{
+		db:       db,
+		interval: interval,
+	}
+}
+
+// Start refreshes the gauges immediately and then once per interval until ctx is cancelled
+func (c *ActivityMetricsCollector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.RunOnce(ctx); err != nil {
+				logger.Error("Activity metrics refresh failed", zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce counts the active sessions and trips and updates the gauges. A gauge keeps its
+// previous value when its count fails.
+func (c *ActivityMetricsCollector) RunOnce(ctx context.Context) error {
+	var sessions int64
+	sessionsQuery := `
+		SELECT COUNT(*) FROM session_tokens
+		WHERE revoked = false AND expires_at > NOW()`
+	if err := c.db.GetContext(ctx, &sessions, sessionsQuery); err != nil {
+		return fmt.Errorf("failed to count active sessions: %w", err)
+	}
+	metrics.ActiveSessionsTotal.Set(float64(sessions))
+
+	var trips int64
+	tripsQuery := `SELECT COUNT(*) FROM vehicle_trips WHERE status = 'active'`
+	if err := c.db.GetContext(ctx, &trips, tripsQuery); err != nil {
+		return fmt.Errorf("failed to count active trips: %w", err)
+	}
+	metrics.ActiveTripsTotal.Set(float64(trips))
+
+	return nil
+}
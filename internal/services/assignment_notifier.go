@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+const (
+	// AssignmentRoleDriver marks a notice about the vehicle's driver seat
+	AssignmentRoleDriver = "driver"
+	// AssignmentRoleHelper marks a notice about the vehicle's helper seat
+	AssignmentRoleHelper = "helper"
+
+	assignmentNotifierQueueSize = 256
+)
+
+// AssignmentNotice is the content of one assignment-change email
+type AssignmentNotice struct {
+	LicensePlate string
+	Role         string
+	// CrewName is the driver or helper the notice is about; only used for manager notices
+	CrewName   string
+	Assigned   bool
+	ForManager bool
+}
+
+// AssignmentChange describes one update of a vehicle's driver and helper
+type AssignmentChange struct {
+	LicensePlate     string
+	PreviousDriverID *uuid.UUID
+	PreviousHelperID *uuid.UUID
+	DriverID         *uuid.UUID
+	HelperID         *uuid.UUID
+	// ManagerID is the manager of the vehicle's team, if any
+	ManagerID *uuid.UUID
+}
+
+// AssignmentRecipientLookup loads the users affected by an assignment change
+type AssignmentRecipientLookup interface {
+	GetAssignmentRecipients(ctx context.Context, ids []uuid.UUID) ([]models.AssignmentRecipient, error)
+}
+
+// AssignmentMailer sends the assignment-change email
+type AssignmentMailer interface {
+	SendVehicleAssignmentChanged(email, userName string, notice AssignmentNotice) error
+}
+
+// AssignmentNotifier emails drivers, helpers and their team manager when a vehicle's crew
+// changes. Changes are queued by the request and sent by a background worker; only users
+// who opted in through their preferences receive anything.
+type AssignmentNotifier struct {
+	recipients AssignmentRecipientLookup
+	mailer     AssignmentMailer
+	queue      chan AssignmentChange
+}
+
+// NewAssignmentNotifier creates a new assignment notifier
+func NewAssignmentNotifier(recipients AssignmentRecipientLookup, mailer AssignmentMailer) *AssignmentNotifier {
+	return &AssignmentNotifier{
+		recipients: recipients,
+		mailer:     mailer,
+		queue:      make(chan AssignmentChange, assignmentNotifierQueueSize),
+	}
+}
+
+// Enqueue queues change for the worker without blocking. It returns false when the
+// queue is full and the change was dropped.
+func (n *AssignmentNotifier) Enqueue(change AssignmentChange) bool {
+	select {
+	case n.queue <- change:
+		return true
+	default:
+		logger.Warn("Assignment notification queue full, dropping change",
+			zap.String("license_plate", change.LicensePlate))
+		return false
+	}
+}
+
+// Start sends queued notifications until ctx is cancelled
+func (n *AssignmentNotifier) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change := <-n.queue:
+				if _, err := n.Process(ctx, change); err != nil {
+					logger.Error("Assignment notification failed",
+						zap.Error(err),
+						zap.String("license_plate", change.LicensePlate),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// assignmentEvent is one crew member joining or leaving a seat
+type assignmentEvent struct {
+	userID   uuid.UUID
+	role     string
+	assigned bool
+}
+
+// Process emails every opted-in user affected by change and returns the number of emails
+// sent. A failed email doesn't stop the others.
+func (n *AssignmentNotifier) Process(ctx context.Context, change AssignmentChange) (int, error) {
+	var events []assignmentEvent
+	events = appendSeatEvents(events, AssignmentRoleDriver, change.PreviousDriverID, change.DriverID)
+	events = appendSeatEvents(events, AssignmentRoleHelper, change.PreviousHelperID, change.HelperID)
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(events)+1)
+	for _, event := range events {
+		ids = append(ids, event.userID)
+	}
+	if change.ManagerID != nil {
+		ids = append(ids, *change.ManagerID)
+	}
+
+	found, err := n.recipients.GetAssignmentRecipients(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load assignment recipients: %w", err)
+	}
+	users := make(map[uuid.UUID]models.AssignmentRecipient, len(found))
+	for _, user := range found {
+		users[user.ID] = user
+	}
+
+	sent := 0
+	send := func(recipient models.AssignmentRecipient, notice AssignmentNotice) {
+		if !recipient.NotifyAssignmentChanges {
+			return
+		}
+		if err := n.mailer.SendVehicleAssignmentChanged(recipient.Email, recipient.Name, notice); err != nil {
+			logger.Warn("Failed to send assignment notification",
+				zap.Error(err),
+				zap.String("user_id", recipient.ID.String()),
+			)
+			return
+		}
+		sent++
+	}
+
+	for _, event := range events {
+		member, ok := users[event.userID]
+		if !ok {
+			continue
+		}
+		notice := AssignmentNotice{
+			LicensePlate: change.LicensePlate,
+			Role:         event.role,
+			CrewName:     member.Name,
+			Assigned:     event.assigned,
+		}
+		send(member, notice)
+
+		if change.ManagerID == nil || *change.ManagerID == member.ID {
+			continue
+		}
+		if manager, ok := users[*change.ManagerID]; ok {
+			notice.ForManager = true
+			send(manager, notice)
+		}
+	}
+
+	return sent, nil
+}
+
+// appendSeatEvents records who left and who took a seat when it changed hands
+func appendSeatEvents(events []assignmentEvent, role string, previous, current *uuid.UUID) []assignmentEvent {
+	if previous != nil && current != nil && *previous == *current {
+		return events
+	}
+	if previous != nil {
+		events = append(events, assignmentEvent{userID: *previous, role: role, assigned: false})
+	}
+	if current != nil {
+		events = append(events, assignmentEvent{userID: *current, role: role, assigned: true})
+	}
+	return events
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// AuditExportFormats are the formats accepted by NewAuditLogEncoder
+var AuditExportFormats = []string{"json", "csv"}
+
+// auditCSVHeader are the columns of CSV audit exports
+var auditCSVHeader = []string{
+	"ID", "Timestamp", "User ID", "User Email", "Company ID", "Action", "Resource", "Resource ID",
+	"Method", "Path", "IP Address", "Success", "Status Code", "Duration (ms)", "Trace ID", "Metadata",
+}
+
+// OpenExportCursor opens a cursor over the logs matching the filter for a streaming export
+func (as *AuditService) OpenExportCursor(ctx context.Context, filter *models.AuditLogFilter) (*repository.AuditLogCursor, error) {
+	return as.repo.Cursor(ctx, filter)
+}
+
+// CountLogs counts the logs matching the filter
+func (as *AuditService) CountLogs(ctx context.Context, filter *models.AuditLogFilter) (int64, error) {
+	return as.repo.Count(ctx, filter)
+}
+
+// AuditLogEncoder writes audit logs to an export one at a time. Close completes the
+// document and must be called after the last log, even when there were none.
+type AuditLogEncoder interface {
+	Encode(log *models.AuditLog) error
+	Close() error
+}
+
+// NewAuditLogEncoder returns an encoder writing format ("json" or "csv") to w
+func NewAuditLogEncoder(format string, w io.Writer) (AuditLogEncoder, error) {
+	switch format {
+	case "json":
+		return &jsonAuditLogEncoder{w: w}, nil
+	case "csv":
+		return &csvAuditLogEncoder{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// jsonAuditLogEncoder writes a JSON array, one element per log
+type jsonAuditLogEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonAuditLogEncoder) Encode(log *models.AuditLog) error {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n"
+	if e.count == 0 {
+		prefix = "[\n"
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonAuditLogEncoder) Close() error {
+	closing := "\n]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+// csvAuditLogEncoder writes a header row and one row per log. Metadata is written as
+// a compact JSON object so nested values survive the round trip.
+type csvAuditLogEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvAuditLogEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.w.Write(auditCSVHeader)
+}
+
+func (e *csvAuditLogEncoder) Encode(log *models.AuditLog) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	metadata := ""
+	if len(log.Metadata) > 0 {
+		data, err := json.Marshal(log.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = string(data)
+	}
+
+	userID, companyID := "", ""
+	if log.UserID != nil {
+		userID = log.UserID.String()
+	}
+	if log.CompanyID != nil {
+		companyID = log.CompanyID.String()
+	}
+	statusCode, durationMs := "", ""
+	if log.StatusCode != nil {
+		statusCode = strconv.Itoa(*log.StatusCode)
+	}
+	if log.DurationMs != nil {
+		durationMs = strconv.FormatInt(*log.DurationMs, 10)
+	}
+
+	if err := e.w.Write([]string{
+		log.ID.String(),
+		log.CreatedAt.Format(time.RFC3339),
+		userID,
+		stringValue(log.UserEmail),
+		companyID,
+		log.Action,
+		log.Resource,
+		stringValue(log.ResourceID),
+		stringValue(log.Method),
+		stringValue(log.Path),
+		log.IPAddress,
+		strconv.FormatBool(log.Success),
+		statusCode,
+		durationMs,
+		stringValue(log.TraceID),
+		metadata,
+	}); err != nil {
+		return err
+	}
+
+	// Hand each row to the underlying writer so it streams instead of buffering
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvAuditLogEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// stringValue dereferences an optional string, empty when nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -31,6 +30,14 @@ func NewAuditService(db *sqlx.DB) *AuditService {
 	}
 }
 
+// NewAuditServiceWithRepository creates an audit service backed by the given repository
+func NewAuditServiceWithRepository(db *sqlx.DB, repo repository.AuditLogRepositoryInterface) *AuditService {
+	return &AuditService{
+		db:   db,
+		repo: repo,
+	}
+}
+
 // AuditAction represents an audit action
 type AuditAction string
 
@@ -109,6 +116,11 @@ func (as *AuditService) Log(ctx context.Context, entry *LogEntry) error {
 		CreatedAt:    time.Now(),
 	}
 
+	// Capture the request details now; the request is gone once the goroutine runs
+	if info, ok := models.RequestInfoFromContext(ctx); ok {
+		auditLog.FillRequestInfo(info)
+	}
+
 	// Store asynchronously to avoid blocking main flow
 	go func() {
 		err := as.storeAuditLog(context.Background(), auditLog)
@@ -399,100 +411,3 @@ func (as *AuditService) GetStats(ctx context.Context, filter *models.AuditLogFil
 func (as *AuditService) GetByTraceID(ctx context.Context, traceID string) ([]*models.AuditLog, error) {
 	return as.repo.GetByTraceID(ctx, traceID)
 }
-
-// ExportLogs exports audit logs to JSON or CSV format
-func (as *AuditService) ExportLogs(ctx context.Context, filter *models.AuditLogFilter, format string) ([]byte, error) {
-	// Get all logs without pagination for export
-	filter.Limit = 0
-	filter.Offset = 0
-
-	logs, err := as.repo.List(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	switch format {
-	case "json":
-		return json.MarshalIndent(logs, "", "  ")
-	case "csv":
-		return exportToCSV(logs)
-	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
-	}
-}
-
-// exportToCSV converts audit logs to CSV format
-func exportToCSV(logs []*models.AuditLog) ([]byte, error) {
-	var csv string
-
-	// Header
-	csv += "ID,Timestamp,User ID,User Email,Company ID,Action,Resource,Resource ID,Method,Path,IP Address,Success,Status Code,Duration (ms),Trace ID\n"
-
-	// Rows
-	for _, log := range logs {
-		userID := ""
-		if log.UserID != nil {
-			userID = log.UserID.String()
-		}
-
-		userEmail := ""
-		if log.UserEmail != nil {
-			userEmail = *log.UserEmail
-		}
-
-		companyID := ""
-		if log.CompanyID != nil {
-			companyID = log.CompanyID.String()
-		}
-
-		resourceID := ""
-		if log.ResourceID != nil {
-			resourceID = *log.ResourceID
-		}
-
-		method := ""
-		if log.Method != nil {
-			method = *log.Method
-		}
-
-		path := ""
-		if log.Path != nil {
-			path = *log.Path
-		}
-
-		statusCode := ""
-		if log.StatusCode != nil {
-			statusCode = fmt.Sprintf("%d", *log.StatusCode)
-		}
-
-		durationMs := ""
-		if log.DurationMs != nil {
-			durationMs = fmt.Sprintf("%d", *log.DurationMs)
-		}
-
-		traceID := ""
-		if log.TraceID != nil {
-			traceID = *log.TraceID
-		}
-
-		csv += fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%t,%s,%s,%s\n",
-			log.ID.String(),
-			log.CreatedAt.Format(time.RFC3339),
-			userID,
-			userEmail,
-			companyID,
-			log.Action,
-			log.Resource,
-			resourceID,
-			method,
-			path,
-			log.IPAddress,
-			log.Success,
-			statusCode,
-			durationMs,
-			traceID,
-		)
-	}
-
-	return []byte(csv), nil
-}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // decoder for image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+var (
+	ErrAvatarTooLarge        = errors.New("avatar file is too large")
+	ErrAvatarUnsupportedType = errors.New("avatar must be a JPEG, PNG or GIF image")
+	ErrAvatarInvalidImage    = errors.New("avatar is not a valid image")
+)
+
+// avatarMaxSourcePixels bounds the decoded size of an upload, so a small file declaring
+// huge dimensions cannot exhaust memory
+const avatarMaxSourcePixels = 40_000_000
+
+// avatarJPEGQuality is the quality avatars are re-encoded with
+const avatarJPEGQuality = 85
+
+// AvatarService validates, resizes and stores profile pictures
+type AvatarService struct {
+	storage      FileStorage
+	userRepo     repository.UserRepositoryInterface
+	maxBytes     int64
+	maxDimension int
+}
+
+// NewAvatarService creates an avatar service accepting files up to maxBytes and scaling
+// images down so neither side exceeds maxDimension pixels
+func NewAvatarService(storage FileStorage, userRepo repository.UserRepositoryInterface, maxBytes int64, maxDimension int) *AvatarService {
+	return &AvatarService{
+		storage:      storage,
+		userRepo:     userRepo,
+		maxBytes:     maxBytes,
+		maxDimension: maxDimension,
+	}
+}
+
+// MaxBytes returns the largest accepted upload
+func (s *AvatarService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload stores the image read from r as the user's avatar and returns its URL. The
+// image type is detected from its content, not the client's headers, and the image is
+// re-encoded, which also drops any embedded metadata such as GPS coordinates. The
+// previous avatar is deleted when it was stored by this service.
+func (s *AvatarService) Upload(ctx context.Context, userID uuid.UUID, r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if int64(len(data)) > s.maxBytes {
+		return "", ErrAvatarTooLarge
+	}
+
+	encoded, contentType, ext, err := s.process(data)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+
+	// A fresh name per upload so clients and CDNs never serve a cached previous picture
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate avatar name: %w", err)
+	}
+	key := fmt.Sprintf("avatars/%s/%s.%s", userID, hex.EncodeToString(suffix), ext)
+
+	url, err := s.storage.Put(ctx, key, contentType, encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	if _, err := s.userRepo.Update(ctx, userID, models.UpdateUserRequest{Avatar: url}); err != nil {
+		s.deleteQuietly(ctx, key)
+		return "", fmt.Errorf("failed to save avatar: %w", err)
+	}
+
+	deleteStoredAvatar(ctx, s.storage, user.Avatar)
+
+	return url, nil
+}
+
+// process validates an upload and returns it resized and re-encoded with its content
+// type and file extension. PNG keeps transparency; GIFs are stored as a PNG of their first frame.
+func (s *AvatarService) process(data []byte) ([]byte, string, string, error) {
+	var encode func(io.Writer, image.Image) error
+	contentType, ext := "image/png", "png"
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		contentType, ext = "image/jpeg", "jpg"
+		encode = func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: avatarJPEGQuality})
+		}
+	case "image/png", "image/gif":
+		encode = png.Encode
+	default:
+		return nil, "", "", ErrAvatarUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, "", "", ErrAvatarInvalidImage
+	}
+	if config.Width*config.Height > avatarMaxSourcePixels {
+		return nil, "", "", fmt.Errorf("%w: %dx%d pixels is too large", ErrAvatarInvalidImage, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", "", ErrAvatarInvalidImage
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, FitImage(img, s.maxDimension)); err != nil {
+		return nil, "", "", fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	return buf.Bytes(), contentType, ext, nil
+}
+
+// deleteQuietly removes a stored avatar, logging instead of failing the request
+func (s *AvatarService) deleteQuietly(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		logger.Warn("Failed to delete avatar file", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// deleteStoredAvatar removes the file behind an avatar URL when it was stored in storage,
+// logging instead of failing the caller. Avatars hosted elsewhere are left alone.
+func deleteStoredAvatar(ctx context.Context, storage FileStorage, avatarURL *string) {
+	if storage == nil || avatarURL == nil {
+		return
+	}
+	key, ok := storage.KeyForURL(*avatarURL)
+	if !ok {
+		return
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		logger.Warn("Failed to delete avatar file", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// FitImage scales img down, keeping its aspect ratio, so neither side exceeds maxDimension.
+// Each target pixel averages the source pixels it covers. Smaller images are returned as is.
+func FitImage(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		return img
+	}
+
+	targetWidth, targetHeight := maxDimension, maxDimension
+	if width > height {
+		targetHeight = max(1, height*maxDimension/width)
+	} else {
+		targetWidth = max(1, width*maxDimension/height)
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		y0, y1 := y*height/targetHeight, max((y+1)*height/targetHeight, y*height/targetHeight+1)
+		for x := 0; x < targetWidth; x++ {
+			x0, x1 := x*width/targetWidth, max((x+1)*width/targetWidth, x*width/targetWidth+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					p := src.NRGBAAt(sx, sy)
+					// Weight colours by alpha so transparent pixels don't darken the edges
+					r += uint64(p.R) * uint64(p.A)
+					g += uint64(p.G) * uint64(p.A)
+					b += uint64(p.B) * uint64(p.A)
+					a += uint64(p.A)
+					n++
+				}
+			}
+
+			if a == 0 {
+				continue
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / a),
+				G: uint8(g / a),
+				B: uint8(b / a),
+				A: uint8(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// CompanySettingsSource returns a company's settings, with defaults for the unset ones
+type CompanySettingsSource interface {
+	Get(ctx context.Context, companyID uuid.UUID) (*models.CompanySettingsRecord, error)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+const documentExpiryCompanyBatch = 100
+
+// documentExpiryRecipientRoles are the roles alerted about expiring vehicle documents
+var documentExpiryRecipientRoles = []string{"company_admin", "manager"}
+
+// DocumentExpiryMailer sends the expiring documents digest to a manager
+type DocumentExpiryMailer interface {
+	SendDocumentExpiryAlert(email, userName string, docs []models.ExpiringDocument, withinDays int) error
+}
+
+// DocumentExpiryNotifier emails company managers about vehicle documents that are
+// about to lapse. It checks every company once per interval (daily by default).
+type DocumentExpiryNotifier struct {
+	documentRepo repository.VehicleDocumentRepositoryInterface
+	companyRepo  repository.CompanyRepositoryInterface
+	userRepo     repository.UserRepositoryInterface
+	mailer       DocumentExpiryMailer
+	withinDays   int
+	interval     time.Duration
+}
+
+// NewDocumentExpiryNotifier creates a notifier alerting about documents expiring within withinDays days
+func NewDocumentExpiryNotifier(
+	documentRepo repository.VehicleDocumentRepositoryInterface,
+	companyRepo repository.CompanyRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	mailer DocumentExpiryMailer,
+	withinDays int,
+) *DocumentExpiryNotifier {
+	return &DocumentExpiryNotifier{
+		documentRepo: documentRepo,
+		companyRepo:  companyRepo,
+		userRepo:     userRepo,
+		mailer:       mailer,
+		withinDays:   withinDays,
+		interval:     24 * time.Hour,
+	}
+}
+
+// Start runs a check immediately and then once per interval until ctx is cancelled
+func (n *DocumentExpiryNotifier) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(n.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := n.RunOnce(ctx); err != nil {
+				logger.Error("Document expiry check failed", zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce checks every company and emails its managers the documents expiring soon.
+// It returns the number of emails sent; failures for one company don't stop the others.
+func (n *DocumentExpiryNotifier) RunOnce(ctx context.Context) (int, error) {
+	sent := 0
+	for offset := 0; ; offset += documentExpiryCompanyBatch {
+		companies, err := n.companyRepo.List(ctx, documentExpiryCompanyBatch, offset)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, company := range companies {
+			if company.Status != "active" {
+				continue
+			}
+			sent += n.notifyCompany(ctx, company)
+		}
+
+		if len(companies) < documentExpiryCompanyBatch {
+			return sent, nil
+		}
+	}
+}
+
+// notifyCompany emails the company's managers when it has expiring documents
+func (n *DocumentExpiryNotifier) notifyCompany(ctx context.Context, company models.Company) int {
+	docs, err := n.documentRepo.GetExpiringDocuments(ctx, company.ID, n.withinDays)
+	if err != nil {
+		logger.Error("Failed to load expiring vehicle documents",
+			zap.Error(err),
+			zap.String("company_id", company.ID.String()),
+		)
+		return 0
+	}
+	if len(docs) == 0 {
+		return 0
+	}
+
+	managers, err := n.userRepo.ListByCompanyAndRoles(ctx, &company.ID, documentExpiryRecipientRoles, 1000, 0, repository.Sort{})
+	if err != nil {
+		logger.Error("Failed to load managers for document expiry alert",
+			zap.Error(err),
+			zap.String("company_id", company.ID.String()),
+		)
+		return 0
+	}
+
+	sent := 0
+	for _, manager := range managers {
+		if !manager.Active {
+			continue
+		}
+		if err := n.mailer.SendDocumentExpiryAlert(manager.Email, manager.Name, docs, n.withinDays); err != nil {
+			logger.Error("Failed to send document expiry alert",
+				zap.Error(err),
+				zap.String("company_id", company.ID.String()),
+				zap.String("user_id", manager.ID.String()),
+			)
+			continue
+		}
+		sent++
+	}
+
+	logger.Info("Document expiry alerts sent",
+		zap.String("company_id", company.ID.String()),
+		zap.Int("documents", len(docs)),
+		zap.Int("emails", sent),
+	)
+	return sent
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// ErrEmailDisabled é retornado pelo envio de email de teste quando não há servidor SMTP
+var ErrEmailDisabled = errors.New("email sending is disabled: SMTP_HOST is not set")
+
+// EmailSender envia os emails da aplicação. EmailService entrega via SMTP e
+// NoopEmailService apenas registra os envios, para desenvolvimento e testes.
+type EmailSender interface {
+	SendEmail(data EmailData) error
+	SendPasswordResetCode(email, code, userName string) error
+	SendPasswordResetConfirmation(email, userName string) error
+	SendDocumentExpiryAlert(email, userName string, docs []models.ExpiringDocument, withinDays int) error
+	SendTeamManagerChanged(email, userName, teamName string, assigned bool) error
+	SendVehicleAssignmentChanged(email, userName string, notice AssignmentNotice) error
+	SendSuspiciousLoginAlert(email, userName, ipAddress, userAgent, countryCode string) error
+	SendAccountDeletionRequested(email, adminName, userName, userEmail string, erasureAt time.Time) error
+	SendTestEmail(email, requestedBy string) error
+}
+
+var (
+	_ EmailSender = (*EmailService)(nil)
+	_ EmailSender = (*NoopEmailService)(nil)
+)
+
+// NewEmailSender cria o serviço de email a partir da configuração: SMTP quando SMTP_HOST
+// está definido (validando as demais configurações), ou um NoopEmailService caso contrário
+func NewEmailSender(cfg *config.Config) (EmailSender, error) {
+	if !cfg.SMTP.Enabled() {
+		return NewNoopEmailService(), nil
+	}
+	if err := cfg.SMTP.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid SMTP configuration: %w", err)
+	}
+	return NewEmailService(cfg), nil
+}
+
+// NoopEmailService descarta os emails, registrando apenas tipo e destinatário. Assim os
+// chamadores nunca precisam tratar a ausência de um serviço de email.
+type NoopEmailService struct{}
+
+// NewNoopEmailService cria um serviço de email que não envia nada
+func NewNoopEmailService() *NoopEmailService {
+	return &NoopEmailService{}
+}
+
+// skip registra o email descartado
+func (n *NoopEmailService) skip(emailType, to string) error {
+	logger.Debug("Email sending disabled, email not sent",
+		zap.String("type", emailType),
+		zap.String("to", to))
+	return nil
+}
+
+// SendEmail descarta o email
+func (n *NoopEmailService) SendEmail(data EmailData) error {
+	emailType := data.Type
+	if emailType == "" {
+		emailType = EmailTypeOther
+	}
+	return n.skip(emailType, data.To)
+}
+
+// SendPasswordResetCode descarta o email
+func (n *NoopEmailService) SendPasswordResetCode(email, code, userName string) error {
+	return n.skip(EmailTypePasswordReset, email)
+}
+
+// SendPasswordResetConfirmation descarta o email
+func (n *NoopEmailService) SendPasswordResetConfirmation(email, userName string) error {
+	return n.skip(EmailTypePasswordChanged, email)
+}
+
+// SendDocumentExpiryAlert descarta o email
+func (n *NoopEmailService) SendDocumentExpiryAlert(email, userName string, docs []models.ExpiringDocument, withinDays int) error {
+	return n.skip(EmailTypeDocumentExpiry, email)
+}
+
+// SendTeamManagerChanged descarta o email
+func (n *NoopEmailService) SendTeamManagerChanged(email, userName, teamName string, assigned bool) error {
+	return n.skip(EmailTypeTeamManager, email)
+}
+
+// SendVehicleAssignmentChanged descarta o email
+func (n *NoopEmailService) SendVehicleAssignmentChanged(email, userName string, notice AssignmentNotice) error {
+	return n.skip(EmailTypeVehicleAssignment, email)
+}
+
+// SendSuspiciousLoginAlert descarta o email
+func (n *NoopEmailService) SendSuspiciousLoginAlert(email, userName, ipAddress, userAgent, countryCode string) error {
+	return n.skip(EmailTypeSuspiciousLogin, email)
+}
+
+// SendAccountDeletionRequested descarta o email
+func (n *NoopEmailService) SendAccountDeletionRequested(email, adminName, userName, userEmail string, erasureAt time.Time) error {
+	return n.skip(EmailTypeAccountDeletion, email)
+}
+
+// SendTestEmail falha com ErrEmailDisabled: o email de teste existe para verificar a
+// entrega, e fingir sucesso esconderia a falta de configuração
+func (n *NoopEmailService) SendTestEmail(email, requestedBy string) error {
+	return ErrEmailDisabled
+}
@@ -2,40 +2,103 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"html/template"
 	"net"
 	"net/smtp"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 
 	"github.com/paulochiaradia/dashtrack/internal/config"
 	"github.com/paulochiaradia/dashtrack/internal/logger"
-	"go.uber.org/zap"
+	"github.com/paulochiaradia/dashtrack/internal/metrics"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // EmailService gerencia o envio de emails
 type EmailService struct {
 	config *config.Config
+	tracer trace.Tracer
 }
 
 // NewEmailService cria uma nova instância do serviço de email
 func NewEmailService(cfg *config.Config) *EmailService {
 	return &EmailService{
 		config: cfg,
+		tracer: otel.Tracer("email-service"),
 	}
 }
 
+// Tipos de email, usados para rotular spans e métricas de envio
+const (
+	EmailTypeBlockedAccount    = "blocked_account"
+	EmailTypeNewSession        = "new_session"
+	EmailTypePasswordReset     = "reset"
+	EmailTypePasswordChanged   = "reset_confirmation"
+	EmailTypeDocumentExpiry    = "document_expiry"
+	EmailTypeTeamManager       = "team_manager"
+	EmailTypeVehicleAssignment = "vehicle_assignment"
+	EmailTypeSuspiciousLogin   = "suspicious_login"
+	EmailTypeAccountDeletion   = "account_deletion"
+	EmailTypeTest              = "test"
+	// EmailTypeOther rotula emails enviados sem Type
+	EmailTypeOther = "other"
+)
+
 // EmailData representa os dados de um email
 type EmailData struct {
+	// Type identifica o tipo do email nas métricas e no tracing (EmailType*)
+	Type    string
 	To      string
 	Subject string
 	Body    string
-	IsHTML  bool
+	// TextBody é a versão text/plain de um email HTML. Quando vazio, é gerado a partir do Body.
+	TextBody string
+	IsHTML   bool
 }
 
-// SendEmail envia um email usando SMTP
+// SendEmail envia um email usando SMTP, registrando um span e as métricas de envio
+// (dashtrack_email_sent_total e dashtrack_email_send_duration_seconds) por tipo
 func (s *EmailService) SendEmail(data EmailData) error {
+	emailType := data.Type
+	if emailType == "" {
+		emailType = EmailTypeOther
+	}
+
+	_, span := s.tracer.Start(context.Background(), "EmailService.SendEmail",
+		trace.WithAttributes(
+			attribute.String("email.type", emailType),
+			attribute.Bool("email.html", data.IsHTML),
+			attribute.String("smtp.tls_mode", s.config.SMTP.TLSMode),
+		))
+	defer span.End()
+
+	start := time.Now()
+	err := s.send(data)
+	metrics.EmailSendDuration.WithLabelValues(emailType).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "email delivery failed")
+		metrics.EmailSentTotal.WithLabelValues(emailType, "failure").Inc()
+		return err
+	}
+
+	metrics.EmailSentTotal.WithLabelValues(emailType, "success").Inc()
+	return nil
+}
+
+// send monta a mensagem e a entrega ao servidor SMTP
+func (s *EmailService) send(data EmailData) error {
 	// Validação básica
 	if data.To == "" {
 		return fmt.Errorf("email destinatário não pode estar vazio")
@@ -60,28 +123,34 @@ func (s *EmailService) SendEmail(data EmailData) error {
 	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", data.Subject))
 
 	if data.IsHTML {
-		msg.WriteString("MIME-Version: 1.0\r\n")
-		msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		// multipart/alternative: clientes que bloqueiam HTML exibem a parte em texto
+		textBody := data.TextBody
+		if textBody == "" {
+			textBody = htmlToText(data.Body)
+		}
+		if err := writeAlternativeBody(&msg, textBody, data.Body); err != nil {
+			return fmt.Errorf("erro ao montar email: %w", err)
+		}
 	} else {
 		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		msg.WriteString("\r\n")
+		msg.WriteString(data.Body)
 	}
 
-	msg.WriteString("\r\n")
-	msg.WriteString(data.Body)
-
 	// Endereço do servidor SMTP
 	addr := fmt.Sprintf("%s:%s", smtpHost, smtpPort)
 
-	// Conectar com TLS (opcional mas recomendado)
-	if s.config.SMTP.UseTLS {
+	switch s.config.SMTP.TLSMode {
+	case config.SMTPTLSModeStartTLS, config.SMTPTLSModeImplicit:
 		return s.sendWithTLS(addr, auth, from, []string{data.To}, msg.Bytes())
+	default:
+		// Enviar sem TLS
+		return smtp.SendMail(addr, auth, from, []string{data.To}, msg.Bytes())
 	}
-
-	// Enviar sem TLS
-	return smtp.SendMail(addr, auth, from, []string{data.To}, msg.Bytes())
 }
 
-// sendWithTLS envia email com criptografia STARTTLS (porta 587)
+// sendWithTLS envia email com criptografia: STARTTLS (porta 587) ou TLS implícito (porta 465),
+// conforme SMTP_TLS_MODE
 func (s *EmailService) sendWithTLS(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
 	// Separar host da porta
 	parts := strings.Split(addr, ":")
@@ -90,8 +159,20 @@ func (s *EmailService) sendWithTLS(addr string, auth smtp.Auth, from string, to
 	}
 	host := parts[0]
 
-	// Conectar ao servidor SMTP (sem TLS inicialmente)
-	conn, err := net.Dial("tcp", addr)
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: false,
+	}
+	implicitTLS := s.config.SMTP.TLSMode == config.SMTPTLSModeImplicit
+
+	// Conectar ao servidor SMTP: já em TLS no modo implícito, em texto para STARTTLS
+	var conn net.Conn
+	var err error
+	if implicitTLS {
+		conn, err = tls.Dial("tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.Dial("tcp", addr)
+	}
 	if err != nil {
 		logger.Error("Erro ao conectar com servidor SMTP",
 			zap.Error(err),
@@ -108,16 +189,13 @@ func (s *EmailService) sendWithTLS(addr string, auth smtp.Auth, from string, to
 	defer client.Close()
 
 	// Iniciar STARTTLS
-	tlsConfig := &tls.Config{
-		ServerName:         host,
-		InsecureSkipVerify: false,
-	}
-
-	if err := client.StartTLS(tlsConfig); err != nil {
-		logger.Error("Erro ao iniciar STARTTLS",
-			zap.Error(err),
-			zap.String("host", addr))
-		return fmt.Errorf("erro ao iniciar STARTTLS: %w", err)
+	if !implicitTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			logger.Error("Erro ao iniciar STARTTLS",
+				zap.Error(err),
+				zap.String("host", addr))
+			return fmt.Errorf("erro ao iniciar STARTTLS: %w", err)
+		}
 	}
 
 	// Autenticar
@@ -228,6 +306,7 @@ func (s *EmailService) SendPasswordResetCode(email, code, userName string) error
 	}
 
 	return s.SendEmail(EmailData{
+		Type:    EmailTypePasswordReset,
 		To:      email,
 		Subject: "Recuperação de Senha - DashTrack",
 		Body:    body.String(),
@@ -292,9 +371,449 @@ func (s *EmailService) SendPasswordResetConfirmation(email, userName string) err
 	}
 
 	return s.SendEmail(EmailData{
+		Type:    EmailTypePasswordChanged,
 		To:      email,
 		Subject: "Senha Alterada - DashTrack",
 		Body:    body.String(),
 		IsHTML:  true,
 	})
 }
+
+// SendDocumentExpiryAlert avisa um gestor sobre documentos de veículos vencidos ou a vencer
+func (s *EmailService) SendDocumentExpiryAlert(email, userName string, docs []models.ExpiringDocument, withinDays int) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #FF9800; color: white; padding: 20px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 5px; margin-top: 20px; }
+        table { width: 100%; border-collapse: collapse; margin: 15px 0; }
+        th, td { text-align: left; padding: 8px; border-bottom: 1px solid #ddd; }
+        .expired { color: #c62828; font-weight: bold; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📄 Documentos de Veículos a Vencer</h1>
+        </div>
+        <div class="content">
+            <p>Olá <strong>{{.UserName}}</strong>,</p>
+
+            <p>Os documentos abaixo estão vencidos ou vencem nos próximos {{.WithinDays}} dias:</p>
+
+            <table>
+                <tr><th>Placa</th><th>Documento</th><th>Número</th><th>Vencimento</th></tr>
+                {{range .Documents}}
+                <tr>
+                    <td>{{.LicensePlate}}</td>
+                    <td>{{.Type}}</td>
+                    <td>{{.Number}}</td>
+                    <td{{if lt .DaysRemaining 0}} class="expired"{{end}}>{{.ExpiresAt.Format "02/01/2006"}}</td>
+                </tr>
+                {{end}}
+            </table>
+
+            <p>Renove os documentos para evitar que a frota opere irregularmente.</p>
+        </div>
+        <div class="footer">
+            <p>DashTrack - Sistema de Gestão de Entregas</p>
+            <p>Este é um email automático, não responda.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("document_expiry").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("erro ao criar template: %w", err)
+	}
+
+	var body bytes.Buffer
+	err = t.Execute(&body, map[string]interface{}{
+		"UserName":   userName,
+		"WithinDays": withinDays,
+		"Documents":  docs,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao executar template: %w", err)
+	}
+
+	return s.SendEmail(EmailData{
+		Type:    EmailTypeDocumentExpiry,
+		To:      email,
+		Subject: fmt.Sprintf("%d documento(s) de veículos a vencer - DashTrack", len(docs)),
+		Body:    body.String(),
+		IsHTML:  true,
+	})
+}
+
+// SendTeamManagerChanged avisa um usuário que passou a gerir (ou deixou de gerir) uma equipe
+func (s *EmailService) SendTeamManagerChanged(email, userName, teamName string, assigned bool) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 5px; margin-top: 20px; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>👥 Gestão de Equipe</h1>
+        </div>
+        <div class="content">
+            <p>Olá <strong>{{.UserName}}</strong>,</p>
+            {{if .Assigned}}
+            <p>Você agora é o gestor da equipe <strong>{{.TeamName}}</strong>.</p>
+            {{else}}
+            <p>Você não é mais o gestor da equipe <strong>{{.TeamName}}</strong>. A gestão foi transferida para outro usuário.</p>
+            {{end}}
+            <p>Se tiver dúvidas, fale com o administrador da sua empresa.</p>
+        </div>
+        <div class="footer">
+            <p>DashTrack - Sistema de Gestão de Entregas</p>
+            <p>Este é um email automático, não responda.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("team_manager").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("erro ao criar template: %w", err)
+	}
+
+	var body bytes.Buffer
+	err = t.Execute(&body, map[string]interface{}{
+		"UserName": userName,
+		"TeamName": teamName,
+		"Assigned": assigned,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao executar template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Você agora gerencia a equipe %s - DashTrack", teamName)
+	if !assigned {
+		subject = fmt.Sprintf("Gestão da equipe %s transferida - DashTrack", teamName)
+	}
+
+	return s.SendEmail(EmailData{
+		Type:    EmailTypeTeamManager,
+		To:      email,
+		Subject: subject,
+		Body:    body.String(),
+		IsHTML:  true,
+	})
+}
+
+// SendVehicleAssignmentChanged avisa um motorista ou ajudante (ou o gestor da equipe) que a
+// tripulação de um veículo mudou
+func (s *EmailService) SendVehicleAssignmentChanged(email, userName string, notice AssignmentNotice) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 5px; margin-top: 20px; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🚚 Atribuição de Veículo</h1>
+        </div>
+        <div class="content">
+            <p>Olá <strong>{{.UserName}}</strong>,</p>
+            {{if .ForManager}}
+            {{if .Assigned}}
+            <p><strong>{{.CrewName}}</strong> agora é {{.Role}} do veículo <strong>{{.LicensePlate}}</strong>, da sua equipe.</p>
+            {{else}}
+            <p><strong>{{.CrewName}}</strong> não é mais {{.Role}} do veículo <strong>{{.LicensePlate}}</strong>, da sua equipe.</p>
+            {{end}}
+            {{else}}
+            {{if .Assigned}}
+            <p>Você foi atribuído como {{.Role}} do veículo <strong>{{.LicensePlate}}</strong>.</p>
+            {{else}}
+            <p>Você não é mais {{.Role}} do veículo <strong>{{.LicensePlate}}</strong>.</p>
+            {{end}}
+            {{end}}
+            <p>Para deixar de receber estes avisos, desative a opção nas preferências do seu perfil.</p>
+        </div>
+        <div class="footer">
+            <p>DashTrack - Sistema de Gestão de Entregas</p>
+            <p>Este é um email automático, não responda.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("vehicle_assignment").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("erro ao criar template: %w", err)
+	}
+
+	role := "motorista"
+	if notice.Role == AssignmentRoleHelper {
+		role = "ajudante"
+	}
+
+	var body bytes.Buffer
+	err = t.Execute(&body, map[string]interface{}{
+		"UserName":     userName,
+		"LicensePlate": notice.LicensePlate,
+		"Role":         role,
+		"CrewName":     notice.CrewName,
+		"Assigned":     notice.Assigned,
+		"ForManager":   notice.ForManager,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao executar template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Você foi atribuído ao veículo %s - DashTrack", notice.LicensePlate)
+	switch {
+	case notice.ForManager:
+		subject = fmt.Sprintf("Tripulação do veículo %s alterada - DashTrack", notice.LicensePlate)
+	case !notice.Assigned:
+		subject = fmt.Sprintf("Você não está mais no veículo %s - DashTrack", notice.LicensePlate)
+	}
+
+	return s.SendEmail(EmailData{
+		Type:    EmailTypeVehicleAssignment,
+		To:      email,
+		Subject: subject,
+		Body:    body.String(),
+		IsHTML:  true,
+	})
+}
+
+// SendSuspiciousLoginAlert avisa o usuário sobre um login vindo de um país nunca usado antes
+func (s *EmailService) SendSuspiciousLoginAlert(email, userName, ipAddress, userAgent, countryCode string) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #f44336; color: white; padding: 20px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 5px; margin-top: 20px; }
+        .info-box { background-color: #fff; border: 2px solid #f44336; padding: 15px; margin: 20px 0; border-radius: 5px; }
+        .alert { background-color: #f8d7da; border-left: 4px solid #dc3545; padding: 15px; margin: 15px 0; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🌍 Login de um Novo País</h1>
+        </div>
+        <div class="content">
+            <p>Olá <strong>{{.UserName}}</strong>,</p>
+            <p>Sua conta DashTrack foi acessada de um país onde você <strong>nunca fez login antes</strong>.</p>
+
+            <div class="info-box">
+                <p style="margin: 5px 0;"><strong>Data/Hora:</strong> {{.LoginTime}}</p>
+                <p style="margin: 5px 0;"><strong>País:</strong> {{.CountryCode}}</p>
+                <p style="margin: 5px 0;"><strong>Endereço IP:</strong> {{.IPAddress}}</p>
+                <p style="margin: 5px 0;"><strong>Dispositivo:</strong> {{.UserAgent}}</p>
+            </div>
+
+            <div class="alert">
+                <strong>⚠️ Não foi você?</strong>
+                <p style="margin: 10px 0;">
+                    <strong>Altere sua senha imediatamente</strong> e encerre todas as sessões ativas no painel de controle.
+                </p>
+            </div>
+
+            <p>Se foi você, por exemplo durante uma viagem, pode ignorar este email.</p>
+        </div>
+        <div class="footer">
+            <p>DashTrack - Sistema de Gestão de Entregas</p>
+            <p>Este é um email automático, não responda.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("suspicious_login").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("erro ao criar template: %w", err)
+	}
+
+	var body bytes.Buffer
+	err = t.Execute(&body, map[string]interface{}{
+		"UserName":    userName,
+		"LoginTime":   utils.FormatBrasiliaDefault(utils.Now()),
+		"CountryCode": countryCode,
+		"IPAddress":   ipAddress,
+		"UserAgent":   userAgent,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao executar template: %w", err)
+	}
+
+	return s.SendEmail(EmailData{
+		Type:    EmailTypeSuspiciousLogin,
+		To:      email,
+		Subject: "Alerta de Segurança: login de um novo país - DashTrack",
+		Body:    body.String(),
+		IsHTML:  true,
+	})
+}
+
+// SendAccountDeletionRequested avisa um administrador da empresa que um usuário pediu a exclusão da conta
+func (s *EmailService) SendAccountDeletionRequested(email, adminName, userName, userEmail string, erasureAt time.Time) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #607d8b; color: white; padding: 20px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 5px; margin-top: 20px; }
+        .info-box { background-color: #fff; border: 2px solid #607d8b; padding: 15px; margin: 20px 0; border-radius: 5px; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Solicitação de Exclusão de Conta</h1>
+        </div>
+        <div class="content">
+            <p>Olá <strong>{{.AdminName}}</strong>,</p>
+            <p>Um usuário da sua empresa solicitou a exclusão da própria conta, conforme a LGPD.</p>
+
+            <div class="info-box">
+                <p style="margin: 5px 0;"><strong>Usuário:</strong> {{.UserName}} ({{.UserEmail}})</p>
+                <p style="margin: 5px 0;"><strong>Solicitado em:</strong> {{.RequestTime}}</p>
+                <p style="margin: 5px 0;"><strong>Anonimização dos dados:</strong> {{.ErasureTime}}</p>
+            </div>
+
+            <p>A conta já foi desativada e todas as sessões foram encerradas. Reatribua veículos e equipes deste usuário, se necessário.</p>
+        </div>
+        <div class="footer">
+            <p>DashTrack - Sistema de Gestão de Entregas</p>
+            <p>Este é um email automático, não responda.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("account_deletion").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("erro ao criar template: %w", err)
+	}
+
+	var body bytes.Buffer
+	err = t.Execute(&body, map[string]interface{}{
+		"AdminName":   adminName,
+		"UserName":    userName,
+		"UserEmail":   userEmail,
+		"RequestTime": utils.FormatBrasiliaDefault(utils.Now()),
+		"ErasureTime": utils.FormatBrasiliaDefault(erasureAt),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao executar template: %w", err)
+	}
+
+	return s.SendEmail(EmailData{
+		Type:    EmailTypeAccountDeletion,
+		To:      email,
+		Subject: "Solicitação de exclusão de conta - DashTrack",
+		Body:    body.String(),
+		IsHTML:  true,
+	})
+}
+
+// SendTestEmail envia um email de exemplo para conferir a configuração SMTP
+func (s *EmailService) SendTestEmail(email, requestedBy string) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #4CAF50; color: white; padding: 20px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 5px; margin-top: 20px; }
+        .info-box { background-color: #fff; border: 2px solid #4CAF50; padding: 15px; margin: 20px 0; border-radius: 5px; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>✅ Email de Teste</h1>
+        </div>
+        <div class="content">
+            <p>Se você está lendo esta mensagem, o envio de emails do DashTrack está funcionando.</p>
+
+            <div class="info-box">
+                <p style="margin: 5px 0;"><strong>Servidor SMTP:</strong> {{.Host}}</p>
+                <p style="margin: 5px 0;"><strong>Solicitado por:</strong> {{.RequestedBy}}</p>
+                <p style="margin: 5px 0;"><strong>Enviado em:</strong> {{.SentAt}}</p>
+            </div>
+
+            <p>Nenhuma ação é necessária.</p>
+        </div>
+        <div class="footer">
+            <p>DashTrack - Sistema de Gestão de Entregas</p>
+            <p>Este é um email automático, não responda.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("test_email").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("erro ao criar template: %w", err)
+	}
+
+	var body bytes.Buffer
+	err = t.Execute(&body, map[string]interface{}{
+		"Host":        fmt.Sprintf("%s:%s", s.config.SMTP.Host, s.config.SMTP.Port),
+		"RequestedBy": requestedBy,
+		"SentAt":      utils.FormatBrasiliaDefault(utils.Now()),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao executar template: %w", err)
+	}
+
+	return s.SendEmail(EmailData{
+		Type:    EmailTypeTest,
+		To:      email,
+		Subject: "Email de teste - DashTrack",
+		Body:    body.String(),
+		IsHTML:  true,
+	})
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"regexp"
+	"strings"
+)
+
+var (
+	// htmlHiddenBlocks são elementos cujo conteúdo não aparece para o leitor
+	htmlHiddenBlocks = regexp.MustCompile(`(?is)<(head|style|script)\b.*?</(head|style|script)>`)
+	htmlLinks        = regexp.MustCompile(`(?is)<a\b[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlListItems    = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlLineBreaks   = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|tr|ol|ul|table)>`)
+	htmlTags         = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines       = regexp.MustCompile(`\n{3,}`)
+	repeatedSpaces   = regexp.MustCompile(`[ \t]+`)
+)
+
+// htmlToText gera a versão em texto de um email HTML: remove estilos e tags, mantém
+// quebras de parágrafo, itens de lista e o endereço dos links
+func htmlToText(body string) string {
+	text := htmlHiddenBlocks.ReplaceAllString(body, "")
+	text = htmlLinks.ReplaceAllString(text, "$2 ($1)")
+	text = htmlListItems.ReplaceAllString(text, "\n- ")
+	text = htmlLineBreaks.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(repeatedSpaces.ReplaceAllString(line, " "))
+	}
+	text = blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+
+	return strings.TrimSpace(text) + "\n"
+}
+
+// writeAlternativeBody escreve os cabeçalhos MIME e o corpo multipart/alternative com as
+// partes text/plain e text/html, nesta ordem (a última é a preferida pelo cliente)
+func writeAlternativeBody(msg *bytes.Buffer, textBody, htmlBody string) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", textBody},
+		{"text/html; charset=UTF-8", htmlBody},
+	}
+	for _, p := range parts {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write([]byte(p.content)); err != nil {
+			return err
+		}
+		if err := qp.Close(); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q\r\n", writer.Boundary()))
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileStorage stores uploaded files under a key such as "avatars/<user>/<name>.png" and
+// serves them from a public URL
+type FileStorage interface {
+	// Put stores data under key, replacing any existing file, and returns its public URL
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete removes the file stored under key; a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// KeyForURL returns the key of a URL returned by Put, or false when the URL points elsewhere
+	KeyForURL(fileURL string) (string, bool)
+}
+
+// ErrInvalidStorageKey is returned for keys that are empty or escape the storage root
+var ErrInvalidStorageKey = errors.New("invalid storage key")
+
+// cleanStorageKey rejects keys that are absolute or contain ".." so a key can never
+// address a file outside the storage root
+func cleanStorageKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStorageKey, key)
+	}
+	return cleaned, nil
+}
+
+// LocalFileStorage keeps files on local disk; the router serves the directory at the base URL
+type LocalFileStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalFileStorage creates a storage writing to dir, whose files are served at baseURL
+// (a path such as "/uploads" or an absolute URL)
+func NewLocalFileStorage(dir, baseURL string) *LocalFileStorage {
+	return &LocalFileStorage{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Put writes data to dir/key
+func (s *LocalFileStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	key, err := cleanStorageKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+// Delete removes dir/key
+func (s *LocalFileStorage) Delete(ctx context.Context, key string) error {
+	key, err := cleanStorageKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// KeyForURL strips the base URL from a URL returned by Put
+func (s *LocalFileStorage) KeyForURL(fileURL string) (string, bool) {
+	return keyAfterPrefix(fileURL, s.baseURL)
+}
+
+// S3Config configures an S3-compatible bucket (AWS S3, MinIO, Cloudflare R2, ...)
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or http://minio:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL is where stored objects are readable, e.g. a CDN; defaults to Endpoint/Bucket
+	PublicURL string
+}
+
+// S3FileStorage stores files in an S3-compatible bucket using path-style requests signed
+// with AWS Signature Version 4. Objects must be made readable by the bucket policy.
+type S3FileStorage struct {
+	cfg       S3Config
+	publicURL string
+	client    *http.Client
+}
+
+// NewS3FileStorage creates a storage for the configured bucket
+func NewS3FileStorage(cfg S3Config) *S3FileStorage {
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	publicURL := strings.TrimRight(cfg.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = cfg.Endpoint + "/" + cfg.Bucket
+	}
+
+	return &S3FileStorage{
+		cfg:       cfg,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetHTTPClient overrides the HTTP client used to reach the bucket
+func (s *S3FileStorage) SetHTTPClient(client *http.Client) {
+	s.client = client
+}
+
+// Put uploads data to bucket/key
+func (s *S3FileStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	key, err := cleanStorageKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.do(ctx, http.MethodPut, key, contentType, data); err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return s.publicURL + "/" + key, nil
+}
+
+// Delete removes bucket/key
+func (s *S3FileStorage) Delete(ctx context.Context, key string) error {
+	key, err := cleanStorageKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.do(ctx, http.MethodDelete, key, "", nil); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// KeyForURL strips the public URL from a URL returned by Put
+func (s *S3FileStorage) KeyForURL(fileURL string) (string, bool) {
+	return keyAfterPrefix(fileURL, s.publicURL)
+}
+
+// do sends a signed object request and fails on any non-2xx answer. S3 answers a DELETE
+// of a missing key with 204, so deletes stay idempotent.
+func (s *S3FileStorage) do(ctx context.Context, method, key, contentType string, body []byte) error {
+	objectPath := "/" + s.cfg.Bucket + "/" + escapeS3Key(key)
+	req, err := http.NewRequestWithContext(ctx, method, s.cfg.Endpoint+objectPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, objectPath, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("storage returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers for the S3 service to req
+func (s *S3FileStorage) sign(req *http.Request, canonicalURI string, body []byte) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+		names = append([]string{"content-type"}, names...)
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, canonicalURI, "", canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	for _, part := range []string{s.cfg.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapeS3Key URI-encodes each segment of an object key as Signature Version 4 expects
+func escapeS3Key(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// keyAfterPrefix returns the part of fileURL after prefix + "/"
+func keyAfterPrefix(fileURL, prefix string) (string, bool) {
+	key, ok := strings.CutPrefix(fileURL, prefix+"/")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
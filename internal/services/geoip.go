@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// GeoIPProvider resolves an IP address to an ISO 3166-1 alpha-2 country code. It returns
+// an empty code when the address can't be located.
+type GeoIPProvider interface {
+	CountryCode(ip net.IP) (string, error)
+}
+
+type ipRange struct {
+	start   net.IP
+	end     net.IP
+	country string
+}
+
+// CIDRCountryProvider is an offline GeoIPProvider backed by a list of networks and their
+// countries, such as the GeoLite2 Country CSV joined with its locations file. Each record
+// is "network,country_code"; a header row and blank country codes are skipped.
+type CIDRCountryProvider struct {
+	ranges []ipRange
+}
+
+// NewCIDRCountryProvider loads the network/country records from r
+func NewCIDRCountryProvider(r io.Reader) (*CIDRCountryProvider, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	provider := &CIDRCountryProvider{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read geoip record %d: %w", line, err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("geoip record %d: expected network and country code", line)
+		}
+
+		_, network, err := net.ParseCIDR(strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("geoip record %d: invalid network %q", line, record[0])
+		}
+		country := strings.ToUpper(strings.TrimSpace(record[1]))
+		if country == "" {
+			continue
+		}
+
+		start := network.IP.To16()
+		end := make(net.IP, len(start))
+		mask := net.IP(network.Mask)
+		if len(mask) == net.IPv4len {
+			mask = append(net.IP{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, mask...)
+		}
+		for i := range start {
+			end[i] = start[i] | ^mask[i]
+		}
+		provider.ranges = append(provider.ranges, ipRange{start: start, end: end, country: country})
+	}
+
+	sort.Slice(provider.ranges, func(i, j int) bool {
+		return bytes.Compare(provider.ranges[i].start, provider.ranges[j].start) < 0
+	})
+	return provider, nil
+}
+
+// NewCIDRCountryProviderFromFile loads the network/country records from the CSV file at path
+func NewCIDRCountryProviderFromFile(path string) (*CIDRCountryProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+	defer file.Close()
+
+	return NewCIDRCountryProvider(file)
+}
+
+// CountryCode returns the country of the network containing ip, or "" when none does
+func (p *CIDRCountryProvider) CountryCode(ip net.IP) (string, error) {
+	ip = ip.To16()
+	if ip == nil {
+		return "", fmt.Errorf("invalid IP address")
+	}
+
+	// Last range starting at or before ip; networks don't overlap in the source data
+	i := sort.Search(len(p.ranges), func(i int) bool {
+		return bytes.Compare(p.ranges[i].start, ip) > 0
+	}) - 1
+	if i >= 0 && bytes.Compare(ip, p.ranges[i].end) <= 0 {
+		return p.ranges[i].country, nil
+	}
+	return "", nil
+}
+
+// SuspiciousLoginDetector flags logins from a country the user has never logged in from
+type SuspiciousLoginDetector struct {
+	provider    GeoIPProvider
+	authLogRepo repository.AuthLogRepositoryInterface
+}
+
+// NewSuspiciousLoginDetector creates a detector resolving countries with provider and
+// comparing them with the user's successful logins in auth_logs
+func NewSuspiciousLoginDetector(provider GeoIPProvider, authLogRepo repository.AuthLogRepositoryInterface) *SuspiciousLoginDetector {
+	return &SuspiciousLoginDetector{
+		provider:    provider,
+		authLogRepo: authLogRepo,
+	}
+}
+
+// Check resolves the country of clientIP and reports whether the user has logged in from
+// it before. Private, unknown or unresolvable addresses yield an empty country and are never
+// flagged, and neither is a user's first located login, since there is nothing to compare
+// it with yet.
+func (d *SuspiciousLoginDetector) Check(ctx context.Context, userID uuid.UUID, clientIP string) (country string, newCountry bool) {
+	ip := net.ParseIP(clientIP)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() {
+		return "", false
+	}
+
+	country, err := d.provider.CountryCode(ip)
+	if err != nil {
+		logger.Warn("Failed to geolocate login IP", zap.Error(err), zap.String("ip", clientIP))
+		return "", false
+	}
+	if country == "" {
+		return "", false
+	}
+
+	known, err := d.authLogRepo.GetUserLoginCountries(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to load user login countries",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		return country, false
+	}
+	if len(known) == 0 {
+		return country, false
+	}
+
+	for _, c := range known {
+		if strings.EqualFold(c, country) {
+			return country, false
+		}
+	}
+	return country, true
+}
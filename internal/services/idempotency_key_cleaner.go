@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/metrics"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// IdempotencyKeyCleaner deletes expired idempotency keys so the table only holds the
+// responses that can still be replayed. It runs hourly.
+type IdempotencyKeyCleaner struct {
+	repo     repository.IdempotencyRepositoryInterface
+	interval time.Duration
+}
+
+// NewIdempotencyKeyCleaner creates a new idempotency key cleaner
+func NewIdempotencyKeyCleaner(repo repository.IdempotencyRepositoryInterface) *IdempotencyKeyCleaner {
+	return &IdempotencyKeyCleaner{
+		repo:     repo,
+		interval: time.Hour,
+	}
+}
+
+// Start runs a cleanup immediately and then once per interval until ctx is cancelled
+func (j *IdempotencyKeyCleaner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := j.RunOnce(ctx); err != nil {
+				logger.Error("Idempotency key cleanup failed", zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce deletes the keys that have expired and returns how many were deleted
+func (j *IdempotencyKeyCleaner) RunOnce(ctx context.Context) (int64, error) {
+	deleted, err := j.repo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		metrics.IdempotencyKeysPurgedTotal.Add(float64(deleted))
+		logger.Info("Purged expired idempotency keys", zap.Int64("keys", deleted))
+	}
+	return deleted, nil
+}
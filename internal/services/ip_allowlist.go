@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// IPAllowlistSource lists the address ranges a company's users may log in from
+type IPAllowlistSource interface {
+	GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.IPAllowlistEntry, error)
+}
+
+// IPAllowlist restricts the logins of a company's users to the company's address ranges
+type IPAllowlist struct {
+	source   IPAllowlistSource
+	settings CompanySettingsSource
+}
+
+// NewIPAllowlist creates an allowlist check backed by source
+func NewIPAllowlist(source IPAllowlistSource) *IPAllowlist {
+	return &IPAllowlist{source: source}
+}
+
+// SetCompanySettings lets companies switch enforcement off with enforce_ip_allowlist
+// without deleting their ranges. Without settings the allowlist is always enforced.
+func (a *IPAllowlist) SetCompanySettings(settings CompanySettingsSource) {
+	a.settings = settings
+}
+
+// Allows reports whether clientIP is inside one of the company's ranges. A company
+// without ranges, or that turned enforcement off, has no restriction; an unparseable
+// clientIP is never allowed otherwise.
+func (a *IPAllowlist) Allows(ctx context.Context, companyID uuid.UUID, clientIP string) (bool, error) {
+	if a.settings != nil {
+		record, err := a.settings.Get(ctx, companyID)
+		if err != nil {
+			return false, err
+		}
+		if !record.Settings.EnforceIPAllowlist {
+			return true, nil
+		}
+	}
+
+	entries, err := a.source.GetByCompany(ctx, companyID)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) == 0 {
+		return true, nil
+	}
+
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return false, nil
+	}
+	addr = addr.Unmap()
+
+	for _, entry := range entries {
+		prefix, err := netip.ParsePrefix(entry.CIDR)
+		if err != nil {
+			logger.Warn("Skipping invalid IP allowlist entry",
+				zap.String("entry_id", entry.ID.String()),
+				zap.String("cidr", entry.CIDR))
+			continue
+		}
+		if prefix.Contains(addr) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ParseAllowlistCIDR validates an IPv4 or IPv6 range, or a single address, and returns it in
+// canonical form: host bits cleared, and /32 or /128 added to single addresses
+func ParseAllowlistCIDR(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil || addr.Zone() != "" {
+			return "", fmt.Errorf("invalid IP address or CIDR range %q", value)
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()).String(), nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid IP address or CIDR range %q", value)
+	}
+	return prefix.Masked().String(), nil
+}
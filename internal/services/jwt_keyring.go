@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+)
+
+// DefaultJWTKeyID is the key ID of JWT_SECRET. Tokens issued before key IDs were added
+// carry no kid header and are verified with this key.
+const DefaultJWTKeyID = config.DefaultJWTKeyID
+
+// JWTKeyring holds the HMAC keys tokens may be signed with, by key ID. New tokens are
+// signed with the active key; any key in the ring verifies, so during a rotation tokens
+// signed with the previous key stay valid until they expire.
+type JWTKeyring struct {
+	keys   map[string][]byte
+	active string
+}
+
+// NewJWTKeyring creates a keyring signing with the key activeKeyID
+func NewJWTKeyring(keys map[string]string, activeKeyID string) (*JWTKeyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keyring needs at least one key")
+	}
+
+	ring := &JWTKeyring{keys: make(map[string][]byte, len(keys)), active: activeKeyID}
+	for kid, secret := range keys {
+		if kid == "" || secret == "" {
+			return nil, fmt.Errorf("keyring entries need a key ID and a secret")
+		}
+		ring.keys[kid] = []byte(secret)
+	}
+	if _, ok := ring.keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", activeKeyID)
+	}
+	return ring, nil
+}
+
+// ActiveKeyID returns the ID of the key new tokens are signed with
+func (k *JWTKeyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Sign issues an HS256 token for claims with the active key, naming it in the kid header
+func (k *JWTKeyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = k.active
+	return token.SignedString(k.keys[k.active])
+}
+
+// Key is a jwt.Keyfunc returning the key named by the token's kid header
+func (k *JWTKeyring) Key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid := DefaultJWTKeyID
+	if value, present := token.Header["kid"]; present {
+		var ok bool
+		if kid, ok = value.(string); !ok {
+			return nil, fmt.Errorf("invalid kid header")
+		}
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// DefaultLiveFeedBuffer is the number of events queued per subscriber before it is
+// considered too slow and disconnected
+const DefaultLiveFeedBuffer = 64
+
+// LiveFeedHub fans out vehicle events from the ingestion path to connected stream clients.
+// Publishing never blocks: a subscriber whose buffer is full is dropped and has to reconnect.
+type LiveFeedHub struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*LiveFeedSubscription]struct{}
+	bufferSize  int
+}
+
+// LiveFeedSubscription receives the events of one vehicle
+type LiveFeedSubscription struct {
+	hub       *LiveFeedHub
+	vehicleID uuid.UUID
+	events    chan models.LiveEvent
+	closed    bool
+	lagged    bool
+}
+
+// NewLiveFeedHub creates a new live feed hub
+func NewLiveFeedHub(bufferSize int) *LiveFeedHub {
+	if bufferSize < 1 {
+		bufferSize = DefaultLiveFeedBuffer
+	}
+	return &LiveFeedHub{
+		subscribers: make(map[uuid.UUID]map[*LiveFeedSubscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a new subscriber for the events of a vehicle
+func (h *LiveFeedHub) Subscribe(vehicleID uuid.UUID) *LiveFeedSubscription {
+	sub := &LiveFeedSubscription{
+		hub:       h,
+		vehicleID: vehicleID,
+		events:    make(chan models.LiveEvent, h.bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[vehicleID] == nil {
+		h.subscribers[vehicleID] = make(map[*LiveFeedSubscription]struct{})
+	}
+	h.subscribers[vehicleID][sub] = struct{}{}
+	return sub
+}
+
+// Publish delivers an event to every subscriber of its vehicle
+func (h *LiveFeedHub) Publish(event models.LiveEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[event.VehicleID] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.lagged = true
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// HasSubscribers reports whether anyone is listening to a vehicle
+func (h *LiveFeedHub) HasSubscribers(vehicleID uuid.UUID) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers[vehicleID]) > 0
+}
+
+func (h *LiveFeedHub) removeLocked(sub *LiveFeedSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.events)
+
+	subs := h.subscribers[sub.vehicleID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.vehicleID)
+	}
+}
+
+// Events returns the channel of events; it is closed when the subscription ends
+func (s *LiveFeedSubscription) Events() <-chan models.LiveEvent {
+	return s.events
+}
+
+// Lagged reports whether the subscription was dropped for falling behind
+func (s *LiveFeedSubscription) Lagged() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.lagged
+}
+
+// Close unregisters the subscription; it is safe to call more than once
+func (s *LiveFeedSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
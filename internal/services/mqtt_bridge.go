@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// MQTTTopicFilter matches every dashtrack/{companyId}/{vehicleId}/{sensorDeviceId} topic
+const MQTTTopicFilter = "dashtrack/+/+/+"
+
+const (
+	mqttRetryInterval   = 10 * time.Second
+	mqttMessageTimeout  = 30 * time.Second
+	mqttDisconnectQuiet = 250 // milliseconds
+)
+
+var (
+	// ErrInvalidMQTTTopic is returned for topics outside the dashtrack/{companyId}/{vehicleId}/{sensorDeviceId} pattern
+	ErrInvalidMQTTTopic = errors.New("invalid MQTT topic")
+	// ErrDeviceNotAuthorized is returned when the device is unknown, disabled or not bound to the
+	// company and vehicle of the topic it published on
+	ErrDeviceNotAuthorized = errors.New("device not authorized for topic")
+)
+
+// MQTTConfig holds the broker connection settings
+type MQTTConfig struct {
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	QoS       byte
+}
+
+// mqttReading is the body trackers publish; the device ID comes from the topic
+type mqttReading struct {
+	Type      models.SensorType      `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// MQTTBridge subscribes to tracker topics and feeds their readings through the same
+// ingestion path as POST /api/v1/iot/data
+type MQTTBridge struct {
+	cfg       MQTTConfig
+	ingestion *SensorIngestionService
+	devices   SensorDeviceLookup
+	client    mqtt.Client
+}
+
+// NewMQTTBridge creates a new MQTT ingestion bridge
+func NewMQTTBridge(cfg MQTTConfig, ingestion *SensorIngestionService, devices SensorDeviceLookup) *MQTTBridge {
+	return &MQTTBridge{
+		cfg:       cfg,
+		ingestion: ingestion,
+		devices:   devices,
+	}
+}
+
+// Start connects to the broker in the background and subscribes on every (re)connection.
+// An unreachable broker is retried until ctx is cancelled; it never blocks or stops the API.
+func (b *MQTTBridge) Start(ctx context.Context) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(b.cfg.BrokerURL).
+		SetClientID(b.cfg.ClientID).
+		SetUsername(b.cfg.Username).
+		SetPassword(b.cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(mqttRetryInterval).
+		SetOrderMatters(false).
+		SetOnConnectHandler(b.subscribe).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("MQTT connection lost, reconnecting", zap.Error(err))
+		})
+
+	b.client = mqtt.NewClient(opts)
+	b.client.Connect()
+
+	logger.Info("MQTT bridge started",
+		zap.String("broker", b.cfg.BrokerURL),
+		zap.String("topic", MQTTTopicFilter))
+
+	go func() {
+		<-ctx.Done()
+		b.client.Disconnect(mqttDisconnectQuiet)
+		logger.Info("MQTT bridge stopped")
+	}()
+}
+
+// Connected reports whether the bridge currently has a broker connection
+func (b *MQTTBridge) Connected() bool {
+	return b.client != nil && b.client.IsConnectionOpen()
+}
+
+func (b *MQTTBridge) subscribe(client mqtt.Client) {
+	token := client.Subscribe(MQTTTopicFilter, b.cfg.QoS, b.onMessage)
+	go func() {
+		token.Wait()
+		if err := token.Error(); err != nil {
+			logger.Error("Failed to subscribe to MQTT topics", zap.String("topic", MQTTTopicFilter), zap.Error(err))
+			return
+		}
+		logger.Info("MQTT bridge subscribed", zap.String("topic", MQTTTopicFilter))
+	}()
+}
+
+func (b *MQTTBridge) onMessage(_ mqtt.Client, msg mqtt.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), mqttMessageTimeout)
+	defer cancel()
+
+	if err := b.HandleMessage(ctx, msg.Topic(), msg.Payload()); err != nil {
+		logger.Warn("Rejected MQTT reading", zap.String("topic", msg.Topic()), zap.Error(err))
+	}
+}
+
+// HandleMessage authenticates the device of a topic and ingests the reading it published
+func (b *MQTTBridge) HandleMessage(ctx context.Context, topic string, body []byte) error {
+	companyID, vehicleID, deviceID, err := ParseMQTTTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	var reading mqttReading
+	if err := json.Unmarshal(body, &reading); err != nil {
+		return fmt.Errorf("invalid reading payload: %w", err)
+	}
+
+	if err := b.authenticate(ctx, companyID, vehicleID, deviceID); err != nil {
+		return err
+	}
+
+	payload := models.SensorDataPayload{
+		DeviceID:  deviceID,
+		Type:      reading.Type,
+		Timestamp: reading.Timestamp,
+		Data:      reading.Data,
+	}
+	sensor, err := b.ingestion.ResolveSensor(payload)
+	if err != nil {
+		return err
+	}
+	return b.ingestion.Ingest(ctx, sensor, payload)
+}
+
+// authenticate accepts a reading only from a registered, enabled device bound to the company
+// and vehicle named in the topic, so a tracker can't publish into another fleet's feed
+func (b *MQTTBridge) authenticate(ctx context.Context, companyID, vehicleID uuid.UUID, deviceID string) error {
+	device, err := b.devices.GetByDeviceID(ctx, deviceID)
+	if err != nil {
+		return fmt.Errorf("failed to look up device: %w", err)
+	}
+	if device == nil || device.Status == "inactive" || device.Status == "deleted" {
+		return ErrDeviceNotAuthorized
+	}
+	if device.CompanyID == nil || *device.CompanyID != companyID {
+		return ErrDeviceNotAuthorized
+	}
+	if device.VehicleID == nil || *device.VehicleID != vehicleID {
+		return ErrDeviceNotAuthorized
+	}
+	return nil
+}
+
+// ParseMQTTTopic splits a dashtrack/{companyId}/{vehicleId}/{sensorDeviceId} topic
+func ParseMQTTTopic(topic string) (companyID, vehicleID uuid.UUID, deviceID string, err error) {
+	parts := strings.Split(topic, "/")
+	if len(parts) != 4 || parts[0] != "dashtrack" || parts[3] == "" {
+		return uuid.Nil, uuid.Nil, "", ErrInvalidMQTTTopic
+	}
+
+	companyID, err = uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "", fmt.Errorf("%w: company ID", ErrInvalidMQTTTopic)
+	}
+	vehicleID, err = uuid.Parse(parts[2])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "", fmt.Errorf("%w: vehicle ID", ErrInvalidMQTTTopic)
+	}
+	return companyID, vehicleID, parts[3], nil
+}
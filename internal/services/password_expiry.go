@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// PasswordExpiryPolicy decides when a user must change their password before logging in
+type PasswordExpiryPolicy struct {
+	maxAgeDays int
+	settings   CompanySettingsSource
+}
+
+// NewPasswordExpiryPolicy creates a policy expiring passwords older than maxAgeDays, 0
+// meaning never, unless the user's company sets its own limit. settings may be nil.
+func NewPasswordExpiryPolicy(maxAgeDays int, settings CompanySettingsSource) *PasswordExpiryPolicy {
+	return &PasswordExpiryPolicy{maxAgeDays: maxAgeDays, settings: settings}
+}
+
+// MaxAgeDays returns the password lifetime that applies to users of companyID, 0 when
+// passwords don't expire. A nil companyID gets the global policy.
+func (p *PasswordExpiryPolicy) MaxAgeDays(ctx context.Context, companyID *uuid.UUID) (int, error) {
+	if companyID == nil || p.settings == nil {
+		return p.maxAgeDays, nil
+	}
+
+	record, err := p.settings.Get(ctx, *companyID)
+	if err != nil {
+		return 0, err
+	}
+	if record.Settings.PasswordMaxAgeDays == nil {
+		return p.maxAgeDays, nil
+	}
+	return *record.Settings.PasswordMaxAgeDays, nil
+}
+
+// Expired reports whether the user's password is older than the policy allows
+func (p *PasswordExpiryPolicy) Expired(ctx context.Context, user *models.User) (bool, error) {
+	days, err := p.MaxAgeDays(ctx, user.CompanyID)
+	if err != nil {
+		return false, err
+	}
+	if days <= 0 {
+		return false, nil
+	}
+	return time.Since(user.PasswordChangedAt) >= time.Duration(days)*24*time.Hour, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// RolePermissionsRefreshInterval is how long a loaded role -> permissions mapping is used
+// before it is read again, so grants changed in the database apply without a restart
+const RolePermissionsRefreshInterval = time.Minute
+
+// RolePermissionSource lists the permissions granted to each role
+type RolePermissionSource interface {
+	ListPermissions(ctx context.Context) ([]models.RolePermission, error)
+}
+
+// RolePermissions answers whether a role holds a permission, from the role_permissions
+// table. The mapping is cached and reloaded once it is older than the refresh interval;
+// when a reload fails the previous mapping keeps being used, and until one has loaded
+// every check is denied.
+type RolePermissions struct {
+	source   RolePermissionSource
+	interval time.Duration
+
+	mu       sync.Mutex
+	byRole   map[string]map[string]bool
+	loadedAt time.Time
+}
+
+// NewRolePermissions creates a permission checker backed by source
+func NewRolePermissions(source RolePermissionSource) *RolePermissions {
+	return &RolePermissions{
+		source:   source,
+		interval: RolePermissionsRefreshInterval,
+	}
+}
+
+// SetRefreshInterval sets how long a loaded mapping is used before it is read again
+func (p *RolePermissions) SetRefreshInterval(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+}
+
+// HasPermission reports whether role has been granted permission
+func (p *RolePermissions) HasPermission(ctx context.Context, role, permission string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.byRole == nil || time.Since(p.loadedAt) >= p.interval {
+		if err := p.load(ctx); err != nil {
+			logger.Error("Failed to load role permissions", zap.Error(err))
+		}
+	}
+
+	return p.byRole[role][permission]
+}
+
+// load replaces the cached mapping with the one in the source
+func (p *RolePermissions) load(ctx context.Context) error {
+	grants, err := p.source.ListPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	byRole := make(map[string]map[string]bool)
+	for _, grant := range grants {
+		if byRole[grant.RoleName] == nil {
+			byRole[grant.RoleName] = make(map[string]bool)
+		}
+		byRole[grant.RoleName][grant.Permission] = true
+	}
+
+	p.byRole = byRole
+	p.loadedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+var (
+	// ErrSensorNotRegistered is returned for readings from an unknown device ID
+	ErrSensorNotRegistered = errors.New("sensor not registered")
+	// ErrSensorTypeMismatch is returned when a reading's type differs from the registered sensor's
+	ErrSensorTypeMismatch = errors.New("sensor type mismatch")
+	// ErrUnsupportedSensorType is returned for readings of a type without an ingestion path
+	ErrUnsupportedSensorType = errors.New("unsupported sensor type")
+)
+
+// vibrationThreshold is the acceleration magnitude (m/s²) above which a gyroscope reading is a vibration
+const vibrationThreshold = 15.0
+
+// SensorValidationError reports a reading field that is missing or out of range
+type SensorValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *SensorValidationError) Error() string {
+	return e.Message
+}
+
+// SensorDeviceLookup finds the ESP32 device (and so the vehicle) a sensor reports through
+type SensorDeviceLookup interface {
+	GetByDeviceID(ctx context.Context, deviceID string) (*models.ESP32Device, error)
+}
+
+// SensorIngestionService validates and stores sensor readings and raises threshold alerts.
+// It is shared by every transport devices report through (HTTP and MQTT).
+type SensorIngestionService struct {
+	sensorRepo repository.SensorRepositoryInterface
+	liveFeed   *LiveFeedHub
+	devices    SensorDeviceLookup
+}
+
+// NewSensorIngestionService creates a new sensor ingestion service
+func NewSensorIngestionService(sensorRepo repository.SensorRepositoryInterface) *SensorIngestionService {
+	return &SensorIngestionService{sensorRepo: sensorRepo}
+}
+
+// SetLiveFeed publishes readings and alerts to the real-time feed of the sensor's vehicle
+func (s *SensorIngestionService) SetLiveFeed(hub *LiveFeedHub, devices SensorDeviceLookup) {
+	s.liveFeed = hub
+	s.devices = devices
+}
+
+// ResolveSensor returns the registered sensor of a payload, checking its type
+func (s *SensorIngestionService) ResolveSensor(payload models.SensorDataPayload) (*models.Sensor, error) {
+	sensor, err := s.sensorRepo.GetSensorByDeviceID(payload.DeviceID)
+	if err != nil || sensor == nil {
+		return nil, ErrSensorNotRegistered
+	}
+	if sensor.Type != payload.Type {
+		return nil, ErrSensorTypeMismatch
+	}
+	return sensor, nil
+}
+
+// Ingest validates and stores a reading of sensor, then checks its alert thresholds
+func (s *SensorIngestionService) Ingest(ctx context.Context, sensor *models.Sensor, payload models.SensorDataPayload) error {
+	switch payload.Type {
+	case models.SensorTypeDHT11:
+		return s.ingestDHT11(ctx, sensor, payload)
+	case models.SensorTypeGyroscope:
+		return s.ingestGyroscope(ctx, sensor, payload)
+	case models.SensorTypeGPS:
+		return s.ingestGPS(ctx, sensor, payload)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedSensorType, payload.Type)
+	}
+}
+
+func (s *SensorIngestionService) ingestDHT11(ctx context.Context, sensor *models.Sensor, payload models.SensorDataPayload) error {
+	temperature, ok := payload.Data["temperature"].(float64)
+	if !ok {
+		return &SensorValidationError{Field: "temperature", Message: "temperature field is required and must be a number"}
+	}
+
+	humidity, ok := payload.Data["humidity"].(float64)
+	if !ok {
+		return &SensorValidationError{Field: "humidity", Message: "humidity field is required and must be a number"}
+	}
+
+	if temperature < -40 || temperature > 80 {
+		return &SensorValidationError{Field: "temperature", Message: "temperature must be between -40 and 80 Celsius"}
+	}
+
+	if humidity < 0 || humidity > 100 {
+		return &SensorValidationError{Field: "humidity", Message: "humidity must be between 0 and 100 percent"}
+	}
+
+	reading := &models.DHT11Reading{
+		SensorReading: models.SensorReading{
+			SensorID:  sensor.ID,
+			DeviceID:  payload.DeviceID,
+			Timestamp: payload.Timestamp,
+		},
+		Temperature: temperature,
+		Humidity:    humidity,
+		HeatIndex:   calculateHeatIndex(temperature, humidity),
+	}
+
+	if err := s.sensorRepo.CreateDHT11Reading(reading); err != nil {
+		return err
+	}
+	s.publishLive(ctx, sensor, models.LiveEventReading, reading.Timestamp, reading)
+
+	s.checkTemperatureAlerts(ctx, sensor, temperature, humidity)
+	return nil
+}
+
+func (s *SensorIngestionService) ingestGyroscope(ctx context.Context, sensor *models.Sensor, payload models.SensorDataPayload) error {
+	accelX, _ := payload.Data["accel_x"].(float64)
+	accelY, _ := payload.Data["accel_y"].(float64)
+	accelZ, _ := payload.Data["accel_z"].(float64)
+	gyroX, _ := payload.Data["gyro_x"].(float64)
+	gyroY, _ := payload.Data["gyro_y"].(float64)
+	gyroZ, _ := payload.Data["gyro_z"].(float64)
+
+	magnitude := math.Sqrt(accelX*accelX + accelY*accelY + accelZ*accelZ)
+	isVibrating := magnitude > vibrationThreshold
+
+	reading := &models.GyroscopeReading{
+		SensorReading: models.SensorReading{
+			SensorID:  sensor.ID,
+			DeviceID:  payload.DeviceID,
+			Timestamp: payload.Timestamp,
+		},
+		AccelX:      accelX,
+		AccelY:      accelY,
+		AccelZ:      accelZ,
+		GyroX:       gyroX,
+		GyroY:       gyroY,
+		GyroZ:       gyroZ,
+		Magnitude:   magnitude,
+		IsVibrating: isVibrating,
+	}
+
+	if err := s.sensorRepo.CreateGyroscopeReading(reading); err != nil {
+		return err
+	}
+	s.publishLive(ctx, sensor, models.LiveEventReading, reading.Timestamp, reading)
+
+	if isVibrating {
+		s.checkVibrationAlerts(ctx, sensor, magnitude, vibrationThreshold)
+	}
+	return nil
+}
+
+func (s *SensorIngestionService) ingestGPS(ctx context.Context, sensor *models.Sensor, payload models.SensorDataPayload) error {
+	latitude, _ := payload.Data["latitude"].(float64)
+	longitude, _ := payload.Data["longitude"].(float64)
+	altitude, _ := payload.Data["altitude"].(float64)
+	speed, _ := payload.Data["speed"].(float64)
+	heading, _ := payload.Data["heading"].(float64)
+	satellites, _ := payload.Data["satellites"].(float64)
+	hdop, _ := payload.Data["hdop"].(float64)
+	isValid, _ := payload.Data["is_valid"].(bool)
+
+	if latitude < -90 || latitude > 90 {
+		return &SensorValidationError{Field: "latitude", Message: "latitude must be between -90 and 90"}
+	}
+
+	if longitude < -180 || longitude > 180 {
+		return &SensorValidationError{Field: "longitude", Message: "longitude must be between -180 and 180"}
+	}
+
+	reading := &models.GPSReading{
+		SensorReading: models.SensorReading{
+			SensorID:  sensor.ID,
+			DeviceID:  payload.DeviceID,
+			Timestamp: payload.Timestamp,
+		},
+		Latitude:   latitude,
+		Longitude:  longitude,
+		Altitude:   altitude,
+		Speed:      speed,
+		Heading:    heading,
+		Satellites: int(satellites),
+		HDOP:       hdop,
+		IsValid:    isValid,
+	}
+
+	if err := s.sensorRepo.CreateGPSReading(reading); err != nil {
+		return err
+	}
+	s.publishLive(ctx, sensor, models.LiveEventReading, reading.Timestamp, reading)
+	return nil
+}
+
+// calculateHeatIndex returns the apparent temperature in Celsius
+func calculateHeatIndex(tempC, humidity float64) float64 {
+	// The formula works in Fahrenheit
+	tempF := tempC*9/5 + 32
+
+	// Below 80°F the heat index is the temperature itself
+	if tempF < 80 {
+		return tempC
+	}
+
+	hi := -42.379 + 2.04901523*tempF + 10.14333127*humidity - 0.22475541*tempF*humidity
+	hi += -0.00683783*tempF*tempF - 0.05481717*humidity*humidity + 0.00122874*tempF*tempF*humidity
+	hi += 0.00085282*tempF*humidity*humidity - 0.00000199*tempF*tempF*humidity*humidity
+
+	return (hi - 32) * 5 / 9
+}
+
+func (s *SensorIngestionService) checkTemperatureAlerts(ctx context.Context, sensor *models.Sensor, temperature, humidity float64) {
+	if temperature > 35 {
+		s.raiseAlert(ctx, sensor, &models.SensorAlert{
+			SensorID:  sensor.ID,
+			Type:      "temperature_high",
+			Message:   "Temperature above safe threshold",
+			Value:     temperature,
+			Threshold: 35,
+			Severity:  "medium",
+		})
+	}
+
+	if humidity > 80 {
+		s.raiseAlert(ctx, sensor, &models.SensorAlert{
+			SensorID:  sensor.ID,
+			Type:      "humidity_high",
+			Message:   "Humidity above safe threshold",
+			Value:     humidity,
+			Threshold: 80,
+			Severity:  "low",
+		})
+	}
+}
+
+func (s *SensorIngestionService) checkVibrationAlerts(ctx context.Context, sensor *models.Sensor, magnitude, threshold float64) {
+	severity := "low"
+	if magnitude > threshold*2 {
+		severity = "high"
+	} else if magnitude > threshold*1.5 {
+		severity = "medium"
+	}
+
+	s.raiseAlert(ctx, sensor, &models.SensorAlert{
+		SensorID:  sensor.ID,
+		Type:      "vibration_detected",
+		Message:   "Vibration detected above threshold",
+		Value:     magnitude,
+		Threshold: threshold,
+		Severity:  severity,
+	})
+}
+
+func (s *SensorIngestionService) raiseAlert(ctx context.Context, sensor *models.Sensor, alert *models.SensorAlert) {
+	if err := s.sensorRepo.CreateSensorAlert(alert); err != nil {
+		logger.Error("Failed to store sensor alert",
+			zap.String("device_id", sensor.DeviceID),
+			zap.String("type", alert.Type),
+			zap.Error(err))
+		return
+	}
+	s.publishLive(ctx, sensor, models.LiveEventAlert, alert.CreatedAt, alert)
+}
+
+// publishLive sends an event to the feed of the vehicle the sensor is installed in.
+// Sensors whose device is not assigned to a vehicle are skipped.
+func (s *SensorIngestionService) publishLive(ctx context.Context, sensor *models.Sensor, eventType string, timestamp time.Time, data interface{}) {
+	if s.liveFeed == nil || s.devices == nil {
+		return
+	}
+
+	device, err := s.devices.GetByDeviceID(ctx, sensor.DeviceID)
+	if err != nil {
+		logger.Warn("Failed to resolve vehicle for live feed",
+			zap.String("device_id", sensor.DeviceID),
+			zap.Error(err))
+		return
+	}
+	if device == nil || device.VehicleID == nil {
+		return
+	}
+
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+	s.liveFeed.Publish(models.LiveEvent{
+		Type:      eventType,
+		VehicleID: *device.VehicleID,
+		Source:    string(sensor.Type),
+		Timestamp: timestamp,
+		Data:      data,
+	})
+}
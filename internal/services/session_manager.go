@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/paulochiaradia/dashtrack/internal/logger"
 	"go.uber.org/zap"
 )
@@ -162,7 +163,7 @@ func (sm *SessionManager) RevokeOldestSessions(ctx context.Context, sessionIDs [
 		WHERE id = ANY($1)
 	`
 
-	_, err = tx.ExecContext(ctx, query1, sessionIDs)
+	_, err = tx.ExecContext(ctx, query1, pq.Array(sessionIDs))
 	if err != nil {
 		return fmt.Errorf("failed to revoke sessions in session_tokens: %w", err)
 	}
@@ -180,7 +181,7 @@ func (sm *SessionManager) RevokeOldestSessions(ctx context.Context, sessionIDs [
 		WHERE id = ANY($1)
 	`
 
-	_, err = tx.ExecContext(ctx, query2, sessionIDStrings)
+	_, err = tx.ExecContext(ctx, query2, pq.Array(sessionIDStrings))
 	if err != nil {
 		return fmt.Errorf("failed to revoke sessions in user_sessions: %w", err)
 	}
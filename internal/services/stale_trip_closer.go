@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// StaleTripCloser auto-closes trips left active longer than the maximum trip duration,
+// typically because the device never sent the end of the trip, and reconciles vehicles
+// that ended up with more than one active trip. It runs hourly.
+type StaleTripCloser struct {
+	tripRepo    repository.TripRepositoryInterface
+	maxDuration time.Duration
+	interval    time.Duration
+}
+
+// NewStaleTripCloser creates a closer for trips active longer than maxDuration
+func NewStaleTripCloser(tripRepo repository.TripRepositoryInterface, maxDuration time.Duration) *StaleTripCloser {
+	return &StaleTripCloser{
+		tripRepo:    tripRepo,
+		maxDuration: maxDuration,
+		interval:    time.Hour,
+	}
+}
+
+// Start runs a check immediately and then once per interval until ctx is cancelled
+func (s *StaleTripCloser) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.ReconcileActiveTrips(ctx); err != nil {
+				logger.Error("Active trip reconciliation failed", zap.Error(err))
+			}
+			if _, err := s.RunOnce(ctx); err != nil {
+				logger.Error("Stale trip check failed", zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce auto-closes the trips that started more than the maximum duration ago and
+// returns how many were closed
+func (s *StaleTripCloser) RunOnce(ctx context.Context) (int64, error) {
+	now := time.Now()
+	note := fmt.Sprintf("Auto-closed at %s: trip was active for more than %s without an end event",
+		now.UTC().Format(time.RFC3339), s.maxDuration)
+
+	closed, err := s.tripRepo.AutoCloseStaleTrips(ctx, now.Add(-s.maxDuration), note)
+	if err != nil {
+		return 0, err
+	}
+
+	if closed > 0 {
+		logger.Warn("Auto-closed stale trips",
+			zap.Int64("trips", closed),
+			zap.Duration("max_duration", s.maxDuration),
+		)
+	}
+	return closed, nil
+}
+
+// ReconcileActiveTrips keeps only the most recent active trip of each vehicle, auto-closing
+// the others, and returns how many trips were closed. Failures for one vehicle don't stop
+// the others.
+func (s *StaleTripCloser) ReconcileActiveTrips(ctx context.Context) (int64, error) {
+	vehicleIDs, err := s.tripRepo.ListVehiclesWithDuplicateActiveTrips(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var closed int64
+	for _, vehicleID := range vehicleIDs {
+		trips, err := s.tripRepo.GetActiveTrips(ctx, vehicleID)
+		if err != nil {
+			logger.Error("Failed to load active trips for reconciliation",
+				zap.Error(err),
+				zap.String("vehicle_id", vehicleID.String()),
+			)
+			continue
+		}
+		if len(trips) < 2 {
+			continue
+		}
+
+		latest := 0
+		for i := range trips {
+			if trips[i].StartTime.After(trips[latest].StartTime) {
+				latest = i
+			}
+		}
+
+		ids := make([]uuid.UUID, 0, len(trips)-1)
+		for i, trip := range trips {
+			if i != latest {
+				ids = append(ids, trip.ID)
+			}
+		}
+
+		note := fmt.Sprintf("Auto-closed at %s: trip %s started later on the same vehicle",
+			time.Now().UTC().Format(time.RFC3339), trips[latest].ID)
+		n, err := s.tripRepo.CloseTrips(ctx, ids, note)
+		if err != nil {
+			logger.Error("Failed to close duplicate active trips",
+				zap.Error(err),
+				zap.String("vehicle_id", vehicleID.String()),
+			)
+			continue
+		}
+		closed += n
+	}
+
+	if closed > 0 {
+		logger.Warn("Closed duplicate active trips",
+			zap.Int("vehicles", len(vehicleIDs)),
+			zap.Int64("trips", closed),
+		)
+	}
+	return closed, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -16,33 +17,70 @@ import (
 	"github.com/paulochiaradia/dashtrack/internal/models"
 )
 
+// sessionUseThrottle is the minimum time between two last_used_at updates of a session
+const sessionUseThrottle = time.Minute
+
 // TokenService handles JWT token operations with session management
 type TokenService struct {
-	db              *sqlx.DB
-	jwtSecret       []byte
-	accessTokenTTL  time.Duration
-	refreshTokenTTL time.Duration
-	sessionManager  *SessionManager
-	emailService    *EmailService
+	db                 *sqlx.DB
+	keys               *JWTKeyring
+	accessTokenTTL     time.Duration
+	refreshTokenTTL    time.Duration
+	rememberMeTTL      time.Duration
+	sessionIdleTimeout time.Duration
+	sessionManager     *SessionManager
+	emailService       EmailSender
+	companySettings    CompanySettingsSource
 }
 
-// NewTokenService creates a new token service
+// NewTokenService creates a new token service signing tokens with jwtSecret, under the
+// key ID DefaultJWTKeyID
 func NewTokenService(db *sqlx.DB, jwtSecret string, accessTokenTTL, refreshTokenTTL time.Duration) *TokenService {
 	return &TokenService{
 		db:              db,
-		jwtSecret:       []byte(jwtSecret),
+		keys:            &JWTKeyring{keys: map[string][]byte{DefaultJWTKeyID: []byte(jwtSecret)}, active: DefaultJWTKeyID},
 		accessTokenTTL:  accessTokenTTL,
 		refreshTokenTTL: refreshTokenTTL,
+		rememberMeTTL:   refreshTokenTTL,
 		sessionManager:  NewSessionManager(db),
-		emailService:    nil, // Will be set later via SetEmailService
+		emailService:    NewNoopEmailService(),
 	}
 }
 
+// SetKeyring replaces the signing key with a keyring, for rotating the JWT secret without
+// invalidating live tokens
+func (ts *TokenService) SetKeyring(keyring *JWTKeyring) {
+	ts.keys = keyring
+}
+
 // SetEmailService sets the email service for sending notifications
-func (ts *TokenService) SetEmailService(emailService *EmailService) {
+func (ts *TokenService) SetEmailService(emailService EmailSender) {
 	ts.emailService = emailService
 }
 
+// SetSessionIdleTimeout sets how long a session may go without an authenticated request
+// before it is rejected; zero disables the idle timeout
+func (ts *TokenService) SetSessionIdleTimeout(timeout time.Duration) {
+	ts.sessionIdleTimeout = timeout
+}
+
+// SetRememberMeRefreshTTL sets the refresh token lifetime of logins that ask to be
+// remembered; by default they get the regular refresh lifetime
+func (ts *TokenService) SetRememberMeRefreshTTL(ttl time.Duration) {
+	ts.rememberMeTTL = ttl
+}
+
+// SetCompanySettings sets where the per-company session limit is read from; without it
+// every user gets models.DefaultMaxSessionsPerUser sessions
+func (ts *TokenService) SetCompanySettings(settings CompanySettingsSource) {
+	ts.companySettings = settings
+}
+
+// RefreshTokenTTL returns how long refresh tokens stay valid
+func (ts *TokenService) RefreshTokenTTL() time.Duration {
+	return ts.refreshTokenTTL
+}
+
 // GetDB returns the database connection
 func (ts *TokenService) GetDB() *sqlx.DB {
 	return ts.db
@@ -50,18 +88,31 @@ func (ts *TokenService) GetDB() *sqlx.DB {
 
 // TokenPair represents an access and refresh token pair
 type TokenPair struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	TokenType    string    `json:"token_type"`
-	ExpiresIn    int       `json:"expires_in"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresIn int       `json:"refresh_expires_in"`
 }
 
 // GenerateTokenPair generates a new access and refresh token pair
 func (ts *TokenService) GenerateTokenPair(ctx context.Context, user *models.User, clientIP, userAgent string) (*TokenPair, error) {
+	return ts.generateTokenPair(ctx, user, clientIP, userAgent, ts.refreshTokenTTL)
+}
+
+// GenerateRememberedTokenPair generates a token pair for a login that asked to be
+// remembered: the refresh token lasts the remember-me lifetime, and so do the tokens
+// obtained by refreshing it. The access token lifetime is unchanged.
+func (ts *TokenService) GenerateRememberedTokenPair(ctx context.Context, user *models.User, clientIP, userAgent string) (*TokenPair, error) {
+	return ts.generateTokenPair(ctx, user, clientIP, userAgent, ts.rememberMeTTL)
+}
+
+// generateTokenPair generates a token pair whose refresh token lasts refreshTTL
+func (ts *TokenService) generateTokenPair(ctx context.Context, user *models.User, clientIP, userAgent string, refreshTTL time.Duration) (*TokenPair, error) {
 	now := time.Now()
 	accessTokenExp := now.Add(ts.accessTokenTTL)
-	refreshTokenExp := now.Add(ts.refreshTokenTTL)
+	refreshTokenExp := now.Add(refreshTTL)
 
 	// Generate access token
 	accessToken, err := ts.generateAccessToken(user, accessTokenExp)
@@ -70,13 +121,13 @@ func (ts *TokenService) GenerateTokenPair(ctx context.Context, user *models.User
 	}
 
 	// Generate refresh token
-	refreshToken, err := ts.generateRefreshToken(user.ID)
+	refreshToken, err := ts.generateRefreshToken(user.ID, refreshTokenExp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	// Check session limits and revoke old sessions if necessary
-	const maxSessions = 3 // Maximum allowed concurrent sessions
+	maxSessions := ts.maxSessionsPerUser(ctx, user)
 	allowed, sessionsToRevoke, err := ts.sessionManager.CheckSessionLimits(ctx, user.ID, maxSessions)
 	if err != nil {
 		logger.Error("Failed to check session limits", zap.Error(err))
@@ -112,7 +163,9 @@ func (ts *TokenService) GenerateTokenPair(ctx context.Context, user *models.User
 		UserAgent:        userAgent,
 		ExpiresAt:        accessTokenExp,
 		RefreshExpiresAt: refreshTokenExp,
+		RefreshTTL:       int64(refreshTTL.Seconds()),
 		Revoked:          false,
+		LastUsedAt:       now,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
@@ -123,7 +176,8 @@ func (ts *TokenService) GenerateTokenPair(ctx context.Context, user *models.User
 	}
 
 	// AGORA envia email DEPOIS de criar a nova sessão
-	if shouldSendEmail && ts.emailService != nil {
+	// The sessions were revoked regardless; the preference only silences the email
+	if shouldSendEmail && ts.wantsNewSessionEmail(ctx, user.ID) {
 		err = ts.sendSessionLimitEmail(user, clientIP, userAgent, revokedCount)
 		if err != nil {
 			logger.Error("Failed to send session limit email",
@@ -133,14 +187,32 @@ func (ts *TokenService) GenerateTokenPair(ctx context.Context, user *models.User
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    "Bearer",
-		ExpiresIn:    int(ts.accessTokenTTL.Seconds()),
-		ExpiresAt:    accessTokenExp,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(ts.accessTokenTTL.Seconds()),
+		ExpiresAt:        accessTokenExp,
+		RefreshExpiresIn: int(refreshTTL.Seconds()),
 	}, nil
 }
 
+// maxSessionsPerUser returns how many concurrent sessions the user's company allows,
+// falling back to the default when it can't be read
+func (ts *TokenService) maxSessionsPerUser(ctx context.Context, user *models.User) int {
+	if ts.companySettings == nil || user.CompanyID == nil {
+		return models.DefaultMaxSessionsPerUser
+	}
+
+	record, err := ts.companySettings.Get(ctx, *user.CompanyID)
+	if err != nil {
+		logger.Error("Failed to load company settings for session limit",
+			zap.Error(err),
+			zap.String("company_id", user.CompanyID.String()))
+		return models.DefaultMaxSessionsPerUser
+	}
+	return record.Settings.MaxSessionsPerUser
+}
+
 // RefreshTokenPair generates a new token pair using a refresh token
 func (ts *TokenService) RefreshTokenPair(ctx context.Context, refreshToken, clientIP, userAgent string) (*TokenPair, error) {
 	logger.Info("Starting refresh token validation",
@@ -171,19 +243,18 @@ func (ts *TokenService) RefreshTokenPair(ctx context.Context, refreshToken, clie
 		logger.Error("Failed to revoke old session", zap.Error(err))
 	}
 
-	// Generate new token pair
-	return ts.GenerateTokenPair(ctx, user, clientIP, userAgent)
+	// Generate new token pair, keeping the refresh lifetime chosen at login
+	refreshTTL := ts.refreshTokenTTL
+	if session.RefreshTTL > 0 {
+		refreshTTL = time.Duration(session.RefreshTTL) * time.Second
+	}
+	return ts.generateTokenPair(ctx, user, clientIP, userAgent, refreshTTL)
 }
 
 // ValidateAccessToken validates an access token
 func (ts *TokenService) ValidateAccessToken(ctx context.Context, tokenString string) (*models.User, error) {
 	// Parse JWT token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return ts.jwtSecret, nil
-	})
+	token, err := jwt.Parse(tokenString, ts.keys.Key)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -225,12 +296,7 @@ func (ts *TokenService) ValidateAccessToken(ctx context.Context, tokenString str
 // ValidateAccessTokenWithSession validates a token and returns both user and session_id
 func (ts *TokenService) ValidateAccessTokenWithSession(ctx context.Context, tokenString string) (*models.User, uuid.UUID, error) {
 	// Parse JWT token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return ts.jwtSecret, nil
-	})
+	token, err := jwt.Parse(tokenString, ts.keys.Key)
 
 	if err != nil {
 		return nil, uuid.Nil, fmt.Errorf("failed to parse token: %w", err)
@@ -256,10 +322,7 @@ func (ts *TokenService) ValidateAccessTokenWithSession(ctx context.Context, toke
 	}
 
 	// Get session ID from token hash
-	tokenHash := ts.hashToken(tokenString)
-	var sessionID uuid.UUID
-	query := `SELECT id FROM session_tokens WHERE access_token_hash = $1 AND revoked = false`
-	err = ts.db.GetContext(ctx, &sessionID, query, tokenHash)
+	sessionID, err := ts.activeSessionID(ctx, ts.hashToken(tokenString))
 	if err != nil {
 		return nil, uuid.Nil, fmt.Errorf("session not found or revoked: %w", err)
 	}
@@ -273,16 +336,86 @@ func (ts *TokenService) ValidateAccessTokenWithSession(ctx context.Context, toke
 	return user, sessionID, nil
 }
 
-// RevokeAllUserSessions revokes all sessions for a user
-func (ts *TokenService) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) error {
+// RevokeAllUserSessions revokes all sessions for a user and returns how many were revoked
+func (ts *TokenService) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
 	query := `
 		UPDATE session_tokens 
 		SET revoked = true, revoked_at = NOW(), updated_at = NOW()
 		WHERE user_id = $1 AND revoked = false
 	`
 
-	_, err := ts.db.ExecContext(ctx, query, userID)
-	return err
+	result, err := ts.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PasswordChangeTokenTTL is how long a user whose password expired has to choose a new one
+const PasswordChangeTokenTTL = 10 * time.Minute
+
+// passwordChangePurpose marks tokens that only allow changing an expired password
+const passwordChangePurpose = "password_change"
+
+// PasswordChangeClaims identifies the login that a password change token was issued for
+type PasswordChangeClaims struct {
+	UserID uuid.UUID
+	// PasswordChangedAt is when the expired password was set; once the password changes
+	// the token no longer matches and can't be used again
+	PasswordChangedAt int64
+	RememberMe        bool
+}
+
+// GeneratePasswordChangeToken issues a short-lived token that lets a user whose password
+// expired set a new one. It is not an access token: no session is stored for it.
+func (ts *TokenService) GeneratePasswordChangeToken(user *models.User, rememberMe bool) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":                 user.ID.String(),
+		"purpose":             passwordChangePurpose,
+		"password_changed_at": user.PasswordChangedAt.Unix(),
+		"remember_me":         rememberMe,
+		"exp":                 now.Add(PasswordChangeTokenTTL).Unix(),
+		"iat":                 now.Unix(),
+		"iss":                 "dashtrack-api",
+	}
+
+	return ts.keys.Sign(claims)
+}
+
+// ValidatePasswordChangeToken parses a token issued by GeneratePasswordChangeToken
+func (ts *TokenService) ValidatePasswordChangeToken(tokenString string) (*PasswordChangeClaims, error) {
+	token, err := jwt.Parse(tokenString, ts.keys.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != passwordChangePurpose {
+		return nil, fmt.Errorf("not a password change token")
+	}
+
+	subject, _ := claims["sub"].(string)
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject in token: %w", err)
+	}
+
+	changedAt, ok := claims["password_changed_at"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid password_changed_at in token")
+	}
+	rememberMe, _ := claims["remember_me"].(bool)
+
+	return &PasswordChangeClaims{
+		UserID:            userID,
+		PasswordChangedAt: int64(changedAt),
+		RememberMe:        rememberMe,
+	}, nil
 }
 
 // generateAccessToken generates a JWT access token
@@ -297,23 +430,21 @@ func (ts *TokenService) generateAccessToken(user *models.User, expiresAt time.Ti
 		"iss":        "dashtrack-api",
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(ts.jwtSecret)
+	return ts.keys.Sign(claims)
 }
 
 // generateRefreshToken generates a JWT refresh token for compatibility
-func (ts *TokenService) generateRefreshToken(userID uuid.UUID) (string, error) {
+func (ts *TokenService) generateRefreshToken(userID uuid.UUID, expiresAt time.Time) (string, error) {
 	now := time.Now()
 	claims := jwt.MapClaims{
 		"iss": "Dashtrack API",
 		"sub": userID.String(),
-		"exp": now.Add(ts.refreshTokenTTL).Unix(),
+		"exp": expiresAt.Unix(),
 		"nbf": now.Unix(),
 		"iat": now.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(ts.jwtSecret)
+	return ts.keys.Sign(claims)
 }
 
 // hashToken creates a hash of the token for storage
@@ -335,14 +466,14 @@ func (ts *TokenService) storeSession(ctx context.Context, session *models.Sessio
 	query1 := `
 		INSERT INTO session_tokens (
 			id, user_id, access_token_hash, refresh_token_hash, ip_address, user_agent,
-			expires_at, refresh_expires_at, revoked, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			expires_at, refresh_expires_at, refresh_ttl_seconds, revoked, last_used_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
 	_, err = tx.ExecContext(ctx, query1,
 		session.ID, session.UserID, session.AccessToken, session.RefreshToken,
 		session.IPAddress, session.UserAgent, session.ExpiresAt, session.RefreshExpiresAt,
-		session.Revoked, session.CreatedAt, session.UpdatedAt,
+		session.RefreshTTL, session.Revoked, session.LastUsedAt, session.CreatedAt, session.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert into session_tokens: %w", err)
@@ -400,12 +531,7 @@ func (ts *TokenService) storeSession(ctx context.Context, session *models.Sessio
 // validateRefreshToken validates a JWT refresh token and returns the session
 func (ts *TokenService) validateRefreshToken(ctx context.Context, refreshToken string) (*models.SessionToken, error) {
 	// First validate JWT format and get user ID
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return ts.jwtSecret, nil
-	})
+	token, err := jwt.Parse(refreshToken, ts.keys.Key)
 
 	if err != nil || !token.Valid {
 		return nil, fmt.Errorf("invalid refresh token")
@@ -429,15 +555,19 @@ func (ts *TokenService) validateRefreshToken(ctx context.Context, refreshToken s
 	// Then find session by hashed token
 	hashedToken := ts.hashToken(refreshToken)
 
+	// Idle sessions can't be refreshed either, otherwise the idle timeout would only last
+	// until the client's next refresh. Remember-me sessions, whose refresh lifetime is longer
+	// than the default, are meant to outlive idle periods and are only bound by their expiry.
 	query := `
 		SELECT id, user_id, access_token_hash, refresh_token_hash, ip_address, user_agent,
-			   expires_at, refresh_expires_at, revoked, revoked_at, created_at, updated_at
+			   expires_at, refresh_expires_at, refresh_ttl_seconds, revoked, revoked_at, last_used_at, created_at, updated_at
 		FROM session_tokens
 		WHERE refresh_token_hash = $1 AND user_id = $2 AND revoked = false AND refresh_expires_at > NOW()
+		  AND ($3::bigint = 0 OR refresh_ttl_seconds > $4 OR last_used_at > NOW() - $3::bigint * INTERVAL '1 second')
 	`
 
 	var session models.SessionToken
-	err = ts.db.GetContext(ctx, &session, query, hashedToken, userID)
+	err = ts.db.GetContext(ctx, &session, query, hashedToken, userID, ts.idleTimeoutSeconds(), int64(ts.refreshTokenTTL/time.Second))
 	if err != nil {
 		return nil, err
 	}
@@ -445,18 +575,48 @@ func (ts *TokenService) validateRefreshToken(ctx context.Context, refreshToken s
 	return &session, nil
 }
 
-// isSessionValid checks if a session is valid (not revoked)
+// isSessionValid checks if a session is valid (not revoked, not expired and not idle
+// longer than the idle timeout), recording its use
 func (ts *TokenService) isSessionValid(ctx context.Context, accessTokenHash string) (bool, error) {
+	_, err := ts.activeSessionID(ctx, accessTokenHash)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// activeSessionID returns the ID of the valid session of an access token, or sql.ErrNoRows
+// when it is revoked, expired or idle. The session's last_used_at is refreshed at most
+// once per sessionUseThrottle so busy clients don't write on every request.
+func (ts *TokenService) activeSessionID(ctx context.Context, accessTokenHash string) (uuid.UUID, error) {
 	query := `
-		SELECT EXISTS(
-			SELECT 1 FROM session_tokens
-			WHERE access_token_hash = $1 AND revoked = false AND expires_at > NOW()
-		)
+		SELECT id, last_used_at FROM session_tokens
+		WHERE access_token_hash = $1 AND revoked = false AND expires_at > NOW()
+		  AND ($2::bigint = 0 OR last_used_at > NOW() - $2::bigint * INTERVAL '1 second')
 	`
 
-	var exists bool
-	err := ts.db.GetContext(ctx, &exists, query, accessTokenHash)
-	return exists, err
+	var session struct {
+		ID         uuid.UUID `db:"id"`
+		LastUsedAt time.Time `db:"last_used_at"`
+	}
+	if err := ts.db.GetContext(ctx, &session, query, accessTokenHash, ts.idleTimeoutSeconds()); err != nil {
+		return uuid.Nil, err
+	}
+
+	if time.Since(session.LastUsedAt) >= sessionUseThrottle {
+		_, err := ts.db.ExecContext(ctx, `UPDATE session_tokens SET last_used_at = NOW() WHERE id = $1`, session.ID)
+		if err != nil {
+			// Don't fail the request, the next one will try again
+			logger.Warn("Failed to update session last use", zap.Error(err), zap.String("session_id", session.ID.String()))
+		}
+	}
+
+	return session.ID, nil
+}
+
+// idleTimeoutSeconds returns the idle timeout as the seconds parameter of the session queries
+func (ts *TokenService) idleTimeoutSeconds() int64 {
+	return int64(ts.sessionIdleTimeout / time.Second)
 }
 
 // revokeSession revokes a specific session
@@ -504,6 +664,18 @@ func (ts *TokenService) getUserByID(ctx context.Context, userID uuid.UUID) (*mod
 	return user, nil
 }
 
+// wantsNewSessionEmail reports whether the user wants to be emailed about new sessions.
+// It defaults to true when the preference can't be read.
+func (ts *TokenService) wantsNewSessionEmail(ctx context.Context, userID uuid.UUID) bool {
+	var notify bool
+	err := ts.db.GetContext(ctx, &notify, `SELECT notify_new_session FROM users WHERE id = $1`, userID)
+	if err != nil {
+		logger.Warn("Failed to read new session email preference", zap.Error(err), zap.String("user_id", userID.String()))
+		return true
+	}
+	return notify
+}
+
 // sendSessionLimitEmail sends an email notification when sessions are revoked due to limit
 func (ts *TokenService) sendSessionLimitEmail(user *models.User, newIP, newUserAgent string, revokedCount int) error {
 	subject := "🔒 Nova sessão ativada - Sessões antigas revogadas"
@@ -522,8 +694,9 @@ func (ts *TokenService) sendSessionLimitEmail(user *models.User, newIP, newUserA
 		activeSessions = []ActiveSession{} // Continue mesmo se falhar
 	}
 
-	// Construir lista de sessões ativas em HTML
+	// Construir lista de sessões ativas em HTML e em texto
 	sessionsListHTML := ""
+	sessionsListText := ""
 	for i, session := range activeSessions {
 		sessionTime := session.CreatedAt.In(location).Format("02/01/2006 às 15:04:05")
 		isCurrent := (i == 0) // A primeira é a mais recente (sessão atual)
@@ -548,6 +721,13 @@ func (ts *TokenService) sendSessionLimitEmail(user *models.User, newIP, newUserA
 			truncateUserAgent(session.UserAgent),
 			sessionTime,
 		)
+
+		currentLabel := ""
+		if isCurrent {
+			currentLabel = " (atual)"
+		}
+		sessionsListText += fmt.Sprintf("- Sessão %d%s: IP %s, %s, início %s\n",
+			i+1, currentLabel, session.IPAddress, truncateUserAgent(session.UserAgent), sessionTime)
 	}
 
 	currentTime := time.Now().In(location).Format("02/01/2006 às 15:04:05")
@@ -622,11 +802,33 @@ func (ts *TokenService) sendSessionLimitEmail(user *models.User, newIP, newUserA
 </html>
 `, user.Name, revokedCount, newIP, truncateUserAgent(newUserAgent), currentTime, revokedCount, len(activeSessions), sessionsListHTML)
 
+	textBody := fmt.Sprintf(`Olá %s,
+
+Detectamos um novo login na sua conta DashTrack. Como você atingiu o limite de 3 sessões simultâneas, revogamos automaticamente %d sessão(ões) antiga(s) para manter sua conta segura.
+
+Detalhes da nova sessão:
+- Endereço IP: %s
+- Dispositivo: %s
+- Data/Hora: %s
+
+Não foi você? Recomendamos que você:
+1. Altere sua senha imediatamente
+2. Revogue todas as sessões ativas
+3. Verifique as configurações de segurança da sua conta
+
+Suas sessões ativas atuais (%d):
+%s
+--
+Este é um email automático de segurança do DashTrack
+`, user.Name, revokedCount, newIP, truncateUserAgent(newUserAgent), currentTime, len(activeSessions), sessionsListText)
+
 	emailData := EmailData{
-		To:      user.Email,
-		Subject: subject,
-		Body:    htmlBody,
-		IsHTML:  true,
+		Type:     EmailTypeNewSession,
+		To:       user.Email,
+		Subject:  subject,
+		Body:     htmlBody,
+		TextBody: textBody,
+		IsHTML:   true,
 	}
 
 	return ts.emailService.SendEmail(emailData)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 var (
@@ -26,13 +27,41 @@ var (
 	ErrCannotDeleteSelf        = errors.New("cannot delete yourself")
 	ErrRoleRequiresCompany     = errors.New("role requires company assignment")
 	ErrRoleProhibitsCompany    = errors.New("role prohibits company assignment")
+	ErrInvalidCPF              = errors.New("invalid cpf")
+	ErrCPFAlreadyExists        = errors.New("cpf already exists in this company")
+	ErrLastCompanyAdmin        = errors.New("user is the last administrator of the company")
 )
 
+// companyAdminRoles are the roles that administer a company
+var companyAdminRoles = []string{"company_admin", "admin"}
+
+// defaultErasureGracePeriod is how long after a deletion request the user's log data is anonymized
+const defaultErasureGracePeriod = 30 * 24 * time.Hour
+
+// defaultOnlineThreshold is how recently a user must have used a session to show as online
+const defaultOnlineThreshold = 5 * time.Minute
+
+// UserSessionRevoker revokes every session of a user
+type UserSessionRevoker interface {
+	RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// AccountDeletionMailer notifies company administrators of an account deletion request
+type AccountDeletionMailer interface {
+	SendAccountDeletionRequested(email, adminName, userName, userEmail string, erasureAt time.Time) error
+}
+
 // UserService handles user business logic with multi-tenant permissions
 type UserService struct {
-	userRepo   repository.UserRepositoryInterface
-	roleRepo   repository.RoleRepositoryInterface
-	bcryptCost int
+	userRepo       repository.UserRepositoryInterface
+	roleRepo       repository.RoleRepositoryInterface
+	sessionRevoker UserSessionRevoker
+	fileStorage    FileStorage
+	bcryptCost     int
+
+	deletionMailer     AccountDeletionMailer
+	erasureGracePeriod time.Duration
+	onlineThreshold    time.Duration
 }
 
 // NewUserService creates a new user service
@@ -41,14 +70,46 @@ func NewUserService(userRepo repository.UserRepositoryInterface, roleRepo reposi
 		userRepo:   userRepo,
 		roleRepo:   roleRepo,
 		bcryptCost: bcryptCost,
+
+		erasureGracePeriod: defaultErasureGracePeriod,
+		onlineThreshold:    defaultOnlineThreshold,
 	}
 }
 
+// SetSessionRevoker sets what RevokeUserSessions uses to revoke a user's sessions
+func (s *UserService) SetSessionRevoker(revoker UserSessionRevoker) {
+	s.sessionRevoker = revoker
+}
+
+// SetFileStorage sets where uploaded avatars are stored, so PurgeUser can delete them
+func (s *UserService) SetFileStorage(storage FileStorage) {
+	s.fileStorage = storage
+}
+
+// SetAccountDeletionMailer sets what NotifyAccountDeletion uses to notify company admins
+func (s *UserService) SetAccountDeletionMailer(mailer AccountDeletionMailer) {
+	s.deletionMailer = mailer
+}
+
+// SetErasureGracePeriod sets how long after a deletion request the user's log data is anonymized
+func (s *UserService) SetErasureGracePeriod(gracePeriod time.Duration) {
+	s.erasureGracePeriod = gracePeriod
+}
+
+// SetOnlineThreshold sets how recently a user must have used a session to show as online
+func (s *UserService) SetOnlineThreshold(threshold time.Duration) {
+	s.onlineThreshold = threshold
+}
+
 // UserListRequest represents request parameters for listing users
 type UserListRequest struct {
-	Page   int   `json:"page" form:"page" binding:"min=1"`
-	Limit  int   `json:"limit" form:"limit" binding:"min=1,max=100"`
+	Page  int `json:"page" form:"page" binding:"min=1"`
+	Limit int `json:"limit" form:"limit" binding:"min=1,max=100"`
+	// Offset, when set, is used instead of the offset of Page
+	Offset int   `json:"offset" form:"offset" binding:"min=0"`
 	Active *bool `json:"active" form:"active"`
+	// Sort is parsed from the sort/order query params against repository.UserSortFields
+	Sort repository.Sort `json:"-" form:"-"`
 }
 
 // UserListResponse represents paginated user list response
@@ -63,6 +124,9 @@ type UserListResponse struct {
 // GetUsers retrieves users based on the requesting user's permissions
 func (s *UserService) GetUsers(ctx context.Context, requesterContext *models.UserContext, req UserListRequest) (*UserListResponse, error) {
 	offset := (req.Page - 1) * req.Limit
+	if req.Offset > 0 {
+		offset = req.Offset
+	}
 
 	var users []*models.User
 	var total int
@@ -71,7 +135,7 @@ func (s *UserService) GetUsers(ctx context.Context, requesterContext *models.Use
 	switch requesterContext.Role {
 	case "master":
 		// Master can see all users
-		users, err = s.userRepo.List(ctx, req.Limit, offset, req.Active, nil)
+		users, err = s.userRepo.List(ctx, req.Limit, offset, req.Active, nil, req.Sort)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list all users: %w", err)
 		}
@@ -85,7 +149,20 @@ func (s *UserService) GetUsers(ctx context.Context, requesterContext *models.Use
 
 		// Company admins can see all roles in their company
 		roles := []string{"company_admin", "manager", "driver", "helper"}
-		users, err = s.userRepo.ListByCompanyAndRoles(ctx, requesterContext.CompanyID, roles, req.Limit, offset)
+		users, err = s.userRepo.ListByCompanyAndRoles(ctx, requesterContext.CompanyID, roles, req.Limit, offset, req.Sort)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list company users: %w", err)
+		}
+		total, err = s.userRepo.CountByCompanyAndRoles(ctx, requesterContext.CompanyID, roles)
+
+	case "manager":
+		// Managers see the drivers and helpers of their company
+		if requesterContext.CompanyID == nil {
+			return nil, ErrInsufficientPermissions
+		}
+
+		roles := []string{"driver", "helper"}
+		users, err = s.userRepo.ListByCompanyAndRoles(ctx, requesterContext.CompanyID, roles, req.Limit, offset, req.Sort)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list company users: %w", err)
 		}
@@ -93,7 +170,7 @@ func (s *UserService) GetUsers(ctx context.Context, requesterContext *models.Use
 
 	case "admin":
 		// Global admin can see all users from all companies
-		users, err = s.userRepo.List(ctx, req.Limit, offset, req.Active, nil)
+		users, err = s.userRepo.List(ctx, req.Limit, offset, req.Active, nil, req.Sort)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list all users: %w", err)
 		}
@@ -107,6 +184,12 @@ func (s *UserService) GetUsers(ctx context.Context, requesterContext *models.Use
 		return nil, fmt.Errorf("failed to count users: %w", err)
 	}
 
+	if seesActivity(requesterContext) {
+		if err := s.attachActivity(ctx, users...); err != nil {
+			return nil, err
+		}
+	}
+
 	totalPages := (total + req.Limit - 1) / req.Limit
 
 	return &UserListResponse{
@@ -133,11 +216,56 @@ func (s *UserService) GetUserByID(ctx context.Context, requesterContext *models.
 		return nil, ErrInsufficientPermissions
 	}
 
+	if seesActivity(requesterContext) {
+		if err := s.attachActivity(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
 	// Remove sensitive data
 	user.Password = ""
 	return user, nil
 }
 
+// seesActivity reports whether the requester is shown when users were last active
+func seesActivity(requesterContext *models.UserContext) bool {
+	return requesterContext.Role == "company_admin" || requesterContext.Role == "manager"
+}
+
+// attachActivity fills in when each user was last active, and whether they are online:
+// holding a valid session used within the online threshold
+func (s *UserService) attachActivity(ctx context.Context, users ...*models.User) error {
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+
+	activity, err := s.userRepo.GetLastActivity(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get user activity: %w", err)
+	}
+
+	for _, user := range users {
+		online := false
+		if last, ok := activity[user.ID]; ok {
+			lastActivityAt := last.LastActivityAt
+			user.LastActivityAt = &lastActivityAt
+			online = last.ActiveSessionUsedAt != nil && time.Since(*last.ActiveSessionUsedAt) <= s.onlineThreshold
+		}
+		user.Online = &online
+	}
+	return nil
+}
+
+// IsEmailAvailable reports whether a new user could be created with the email
+func (s *UserService) IsEmailAvailable(ctx context.Context, email string) (bool, error) {
+	exists, err := s.userRepo.ExistsByEmail(ctx, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email availability: %w", err)
+	}
+	return !exists, nil
+}
+
 // CreateUser creates a new user with permission checks
 func (s *UserService) CreateUser(ctx context.Context, requesterContext *models.UserContext, req models.CreateUserRequest) (*models.User, error) {
 	// Check if requester can create users
@@ -215,6 +343,15 @@ func (s *UserService) CreateUser(ctx context.Context, requesterContext *models.U
 		}
 	}
 
+	// Validate and normalize CPF, unique per company
+	if req.CPF != "" {
+		cpf, err := s.normalizeCPF(ctx, companyID, req.CPF, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.CPF = cpf
+	}
+
 	// Create user
 	user := &models.User{
 		ID:                uuid.New(),
@@ -280,6 +417,15 @@ func (s *UserService) UpdateUser(ctx context.Context, requesterContext *models.U
 		}
 	}
 
+	// Validate and normalize CPF, unique per company
+	if req.CPF != "" {
+		cpf, err := s.normalizeCPF(ctx, existingUser.CompanyID, req.CPF, &userID)
+		if err != nil {
+			return nil, err
+		}
+		req.CPF = cpf
+	}
+
 	// Update user
 	updatedUser, err := s.userRepo.Update(ctx, userID, req)
 	if err != nil {
@@ -315,8 +461,203 @@ func (s *UserService) DeleteUser(ctx context.Context, requesterContext *models.U
 	return s.userRepo.Delete(ctx, userID)
 }
 
+// RevokeUserSessions revokes every session of a user, optionally deactivating the account
+// as well, and returns the target user and how many sessions were revoked. Admins may only
+// target users of their own company ranked below them; master may target anyone.
+func (s *UserService) RevokeUserSessions(ctx context.Context, requesterContext *models.UserContext, userID uuid.UUID, deactivate bool) (*models.User, int64, error) {
+	if s.sessionRevoker == nil {
+		return nil, 0, errors.New("session revocation is not configured")
+	}
+
+	targetUser, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	if targetUser == nil {
+		return nil, 0, ErrUserNotFound
+	}
+
+	if !s.canRevokeSessions(requesterContext, targetUser) {
+		return nil, 0, ErrCompanyMismatch
+	}
+
+	revoked, err := s.sessionRevoker.RevokeAllUserSessions(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	if deactivate && targetUser.Active {
+		inactive := false
+		updated, err := s.userRepo.Update(ctx, userID, models.UpdateUserRequest{Active: &inactive})
+		if err != nil {
+			return nil, revoked, fmt.Errorf("failed to deactivate user: %w", err)
+		}
+		if updated != nil {
+			targetUser = updated
+		}
+	}
+
+	return targetUser, revoked, nil
+}
+
+// RequestAccountDeletion handles a user's request to erase their own account: the account
+// is soft deleted and deactivated, every session is revoked, and the user's log data is
+// scheduled for anonymization after the grace period. The last active administrator of a
+// company cannot delete their account.
+func (s *UserService) RequestAccountDeletion(ctx context.Context, userID uuid.UUID) (*models.AccountDeletionResult, error) {
+	if s.sessionRevoker == nil {
+		return nil, errors.New("session revocation is not configured")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.CompanyID != nil && user.Role != nil && isCompanyAdminRole(user.Role.Name) {
+		hasOther, err := s.hasOtherActiveAdmin(ctx, *user.CompanyID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOther {
+			return nil, ErrLastCompanyAdmin
+		}
+	}
+
+	if err := s.userRepo.RequestErasure(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.sessionRevoker.RevokeAllUserSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	user.Active = false
+	user.Password = ""
+	return &models.AccountDeletionResult{
+		User:               user,
+		RevokedSessions:    revoked,
+		ErasureScheduledAt: utils.Now().Add(s.erasureGracePeriod),
+	}, nil
+}
+
+// NotifyAccountDeletion emails the administrators of the user's company about an account
+// deletion request. Users without a company have no one to notify.
+func (s *UserService) NotifyAccountDeletion(ctx context.Context, result *models.AccountDeletionResult) error {
+	if s.deletionMailer == nil || result.User.CompanyID == nil {
+		return nil
+	}
+
+	admins, err := s.userRepo.ListByCompanyAndRoles(ctx, result.User.CompanyID, companyAdminRoles, 100, 0, repository.Sort{})
+	if err != nil {
+		return fmt.Errorf("failed to list company admins: %w", err)
+	}
+
+	var sendErr error
+	for _, admin := range admins {
+		if !admin.Active {
+			continue
+		}
+		if err := s.deletionMailer.SendAccountDeletionRequested(admin.Email, admin.Name, result.User.Name, result.User.Email, result.ErasureScheduledAt); err != nil {
+			sendErr = fmt.Errorf("failed to notify %s: %w", admin.Email, err)
+		}
+	}
+	return sendErr
+}
+
+// PurgeUser irreversibly erases a user's personal data (see UserRepository.PurgeUser for
+// the tables touched). Only master may purge, and never their own account.
+func (s *UserService) PurgeUser(ctx context.Context, requesterContext *models.UserContext, userID uuid.UUID) (*models.UserPurgeResult, error) {
+	if requesterContext.Role != "master" {
+		return nil, ErrInsufficientPermissions
+	}
+	if requesterContext.UserID == userID {
+		return nil, ErrCannotDeleteSelf
+	}
+
+	result, err := s.userRepo.PurgeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// The picture stays publicly readable until its file is gone
+	deleteStoredAvatar(ctx, s.fileStorage, result.Avatar)
+	return result, nil
+}
+
+// hasOtherActiveAdmin reports whether the company has an active administrator other than userID
+func (s *UserService) hasOtherActiveAdmin(ctx context.Context, companyID, userID uuid.UUID) (bool, error) {
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		admins, err := s.userRepo.ListByCompanyAndRoles(ctx, &companyID, companyAdminRoles, pageSize, offset, repository.Sort{})
+		if err != nil {
+			return false, fmt.Errorf("failed to list company admins: %w", err)
+		}
+		for _, admin := range admins {
+			if admin.ID != userID && admin.Active {
+				return true, nil
+			}
+		}
+		if len(admins) < pageSize {
+			return false, nil
+		}
+	}
+}
+
+func isCompanyAdminRole(role string) bool {
+	for _, r := range companyAdminRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCPF validates a CPF, strips its formatting and checks it is not used by
+// another user of the company
+func (s *UserService) normalizeCPF(ctx context.Context, companyID *uuid.UUID, cpf string, excludeUserID *uuid.UUID) (string, error) {
+	if !utils.ValidateCPF(cpf) {
+		return "", ErrInvalidCPF
+	}
+	normalized := utils.NormalizeCPF(cpf)
+
+	exists, err := s.userRepo.ExistsByCPF(ctx, companyID, normalized, excludeUserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check cpf uniqueness: %w", err)
+	}
+	if exists {
+		return "", ErrCPFAlreadyExists
+	}
+
+	return normalized, nil
+}
+
 // Permission helper methods
 
+func (s *UserService) canRevokeSessions(requesterContext *models.UserContext, targetUser *models.User) bool {
+	switch requesterContext.Role {
+	case "master":
+		return true
+	case "admin":
+		// Only users ranked below the admin, never master or another administrator
+		if targetUser.Role == nil || targetUser.Role.Name == "master" || isCompanyAdminRole(targetUser.Role.Name) {
+			return false
+		}
+		return requesterContext.CompanyID != nil &&
+			targetUser.CompanyID != nil &&
+			*requesterContext.CompanyID == *targetUser.CompanyID
+	default:
+		return false
+	}
+}
+
 func (s *UserService) canAccessUser(requesterContext *models.UserContext, targetUser *models.User) bool {
 	switch requesterContext.Role {
 	case "master":
@@ -327,6 +668,11 @@ func (s *UserService) canAccessUser(requesterContext *models.UserContext, target
 			*requesterContext.CompanyID == *targetUser.CompanyID
 	case "admin":
 		return targetUser.Role != nil && (targetUser.Role.Name == "driver" || targetUser.Role.Name == "helper")
+	case "manager":
+		return requesterContext.CompanyID != nil &&
+			targetUser.CompanyID != nil &&
+			*requesterContext.CompanyID == *targetUser.CompanyID &&
+			targetUser.Role != nil && (targetUser.Role.Name == "driver" || targetUser.Role.Name == "helper")
 	case "driver", "helper":
 		return requesterContext.UserID == targetUser.ID
 	default:
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+var (
+	ErrVehicleImportEmpty         = errors.New("the CSV file has no vehicle rows")
+	ErrVehicleImportInvalidHeader = errors.New("invalid CSV header")
+	ErrVehicleImportMalformed     = errors.New("malformed CSV file")
+)
+
+// VehicleImportColumns are the CSV columns, in the order of the import template. Only
+// license_plate, brand, model, year, type and fuel_type are required.
+var VehicleImportColumns = []string{"license_plate", "brand", "model", "year", "type", "fuel_type", "capacity"}
+
+// VehicleImportSampleRow is an example row matching VehicleImportColumns, offered with the template
+var VehicleImportSampleRow = []string{"ABC1D23", "Volvo", "FH 540", "2022", "truck", "diesel", "25000"}
+
+// VehicleImportHeaderError lists everything wrong with the header of an import file.
+// It wraps ErrVehicleImportInvalidHeader.
+type VehicleImportHeaderError struct {
+	Missing  []string
+	Unknown  []string
+	Repeated []string
+}
+
+func (e *VehicleImportHeaderError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing columns: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown columns: "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Repeated) > 0 {
+		problems = append(problems, "repeated columns: "+strings.Join(e.Repeated, ", "))
+	}
+	return fmt.Sprintf("%s: %s; expected %s", ErrVehicleImportInvalidHeader, strings.Join(problems, "; "), strings.Join(VehicleImportColumns, ","))
+}
+
+func (e *VehicleImportHeaderError) Unwrap() error {
+	return ErrVehicleImportInvalidHeader
+}
+
+// vehicleImportColumnAliases maps the vehicle field names to the template columns
+var vehicleImportColumnAliases = map[string]string{
+	"vehicle_type":   "type",
+	"cargo_capacity": "capacity",
+}
+
+var vehicleImportFuelTypes = []string{"gasoline", "diesel", "electric", "hybrid", "cng"}
+
+// VehicleImporter creates vehicles from a CSV exported from a fleet spreadsheet
+type VehicleImporter struct {
+	vehicleRepo repository.VehicleRepositoryInterface
+	maxRows     int
+}
+
+// NewVehicleImporter creates a vehicle importer accepting up to repository.DefaultMaxBatchSize rows
+func NewVehicleImporter(vehicleRepo repository.VehicleRepositoryInterface) *VehicleImporter {
+	return &VehicleImporter{
+		vehicleRepo: vehicleRepo,
+		maxRows:     repository.DefaultMaxBatchSize,
+	}
+}
+
+// SetMaxRows overrides the maximum number of data rows per import
+func (i *VehicleImporter) SetMaxRows(maxRows int) {
+	i.maxRows = maxRows
+}
+
+// Import validates every row of the CSV read from r and creates the valid ones for the
+// company in a single transaction. Invalid rows, plates already registered to the company
+// and plates repeated in the file are rejected with their reasons; they don't stop the
+// import. An error is returned, and nothing is created, when the file itself is unusable,
+// has more rows than allowed (repository.ErrBatchTooLarge) or the insert fails.
+func (i *VehicleImporter) Import(ctx context.Context, companyID uuid.UUID, r io.Reader) (*models.VehicleImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrVehicleImportEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrVehicleImportMalformed, err)
+	}
+
+	columns, err := vehicleImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.VehicleImportResult{Rows: []models.VehicleImportRowResult{}}
+	var vehicles []*models.Vehicle
+	var created []int // index in result.Rows of each vehicle
+	seen := map[string]int{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrVehicleImportMalformed, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if err := repository.CheckBatchSize(len(result.Rows)+1, i.maxRows); err != nil {
+			return nil, err
+		}
+
+		field := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		vehicle, problems := parseVehicleImportRow(field)
+		vehicle.CompanyID = companyID
+		row := models.VehicleImportRowResult{Row: line, LicensePlate: vehicle.LicensePlate}
+
+		if len(problems) == 0 {
+			if firstLine, ok := seen[vehicle.LicensePlate]; ok {
+				problems = append(problems, fmt.Sprintf("license_plate is repeated from row %d", firstLine))
+			} else {
+				exists, err := i.vehicleRepo.CheckLicensePlateExists(ctx, vehicle.LicensePlate, companyID, nil)
+				if err != nil {
+					return nil, err
+				}
+				if exists {
+					problems = append(problems, "license_plate is already registered")
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			row.Status = models.VehicleImportRejected
+			row.Errors = problems
+			result.Rejected++
+		} else {
+			seen[vehicle.LicensePlate] = line
+			row.Status = models.VehicleImportCreated
+			vehicles = append(vehicles, vehicle)
+			created = append(created, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if len(result.Rows) == 0 {
+		return nil, ErrVehicleImportEmpty
+	}
+
+	if len(vehicles) > 0 {
+		if err := i.vehicleRepo.CreateBatch(ctx, vehicles); err != nil {
+			return nil, fmt.Errorf("failed to import vehicles: %w", err)
+		}
+		for n, vehicle := range vehicles {
+			id := vehicle.ID
+			result.Rows[created[n]].VehicleID = &id
+		}
+		result.Created = len(vehicles)
+	}
+
+	return result, nil
+}
+
+// vehicleImportHeader maps each known column of the header to its index. A header with
+// missing, unknown or repeated columns is rejected with a *VehicleImportHeaderError
+// listing all of them.
+func vehicleImportHeader(header []string) (map[string]int, error) {
+	known := map[string]bool{}
+	for _, column := range VehicleImportColumns {
+		known[column] = true
+	}
+
+	headerErr := &VehicleImportHeaderError{}
+	columns := map[string]int{}
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if alias, ok := vehicleImportColumnAliases[name]; ok {
+			name = alias
+		}
+		if !known[name] {
+			headerErr.Unknown = append(headerErr.Unknown, name)
+			continue
+		}
+		if _, dup := columns[name]; dup {
+			headerErr.Repeated = append(headerErr.Repeated, name)
+			continue
+		}
+		columns[name] = index
+	}
+
+	for _, required := range VehicleImportColumns[:6] {
+		if _, ok := columns[required]; !ok {
+			headerErr.Missing = append(headerErr.Missing, required)
+		}
+	}
+	if len(headerErr.Missing) > 0 || len(headerErr.Unknown) > 0 || len(headerErr.Repeated) > 0 {
+		return nil, headerErr
+	}
+	return columns, nil
+}
+
+// parseVehicleImportRow builds a vehicle from a row and lists everything wrong with it
+func parseVehicleImportRow(field func(column string) string) (*models.Vehicle, []string) {
+	var problems []string
+	vehicle := &models.Vehicle{
+		LicensePlate: utils.NormalizeLicensePlate(field("license_plate")),
+		Brand:        field("brand"),
+		Model:        field("model"),
+		VehicleType:  strings.ToLower(field("type")),
+		FuelType:     strings.ToLower(field("fuel_type")),
+		Status:       models.VehicleStatusActive,
+	}
+
+	switch {
+	case vehicle.LicensePlate == "":
+		problems = append(problems, "license_plate is required")
+	case !utils.ValidateLicensePlate(vehicle.LicensePlate):
+		problems = append(problems, fmt.Sprintf("license_plate %q is not a valid plate (ABC1234 or ABC1D23)", field("license_plate")))
+	}
+
+	if vehicle.Brand == "" {
+		problems = append(problems, "brand is required")
+	}
+	if vehicle.Model == "" {
+		problems = append(problems, "model is required")
+	}
+
+	year, err := strconv.Atoi(field("year"))
+	if err != nil || year < 1900 || year > 2100 {
+		problems = append(problems, "year must be a number between 1900 and 2100")
+	}
+	vehicle.Year = year
+
+	if !containsString(models.VehicleTypes, vehicle.VehicleType) {
+		problems = append(problems, "type must be one of "+strings.Join(models.VehicleTypes, ", "))
+	}
+	if !containsString(vehicleImportFuelTypes, vehicle.FuelType) {
+		problems = append(problems, "fuel_type must be one of "+strings.Join(vehicleImportFuelTypes, ", "))
+	}
+
+	if value := field("capacity"); value != "" {
+		capacity, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil || capacity < 0 {
+			problems = append(problems, "capacity must be a non-negative number")
+		} else {
+			vehicle.CargoCapacity = &capacity
+		}
+	}
+
+	return vehicle, problems
+}
+
+// isBlankRecord reports whether every field of a CSV record is empty, as left by spreadsheets
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
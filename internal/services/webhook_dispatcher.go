@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body
+	WebhookSignatureHeader = "X-Dashtrack-Signature"
+	// WebhookPreviousSignatureHeader carries the signature made with the rotated-out secret
+	// during its grace window, for consumers that have not switched to the new secret yet
+	WebhookPreviousSignatureHeader = "X-Dashtrack-Signature-Previous"
+	// WebhookEventHeader carries the event type
+	WebhookEventHeader = "X-Dashtrack-Event"
+	// WebhookDeliveryHeader carries the unique delivery ID
+	WebhookDeliveryHeader = "X-Dashtrack-Delivery"
+	// WebhookTimestampHeader carries the unix timestamp of the delivery
+	WebhookTimestampHeader = "X-Dashtrack-Timestamp"
+)
+
+// WebhookDispatcher delivers signed event payloads to company webhooks
+type WebhookDispatcher struct {
+	repo        repository.WebhookRepositoryInterface
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(repo repository.WebhookRepositoryInterface) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:        repo,
+		client:      newWebhookHTTPClient(10 * time.Second),
+		maxAttempts: 3,
+		backoff:     time.Second,
+	}
+}
+
+// SetRetryPolicy overrides the number of delivery attempts and the initial backoff
+func (d *WebhookDispatcher) SetRetryPolicy(maxAttempts int, backoff time.Duration) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	d.maxAttempts = maxAttempts
+	d.backoff = backoff
+}
+
+// SetHTTPClient overrides the HTTP client used for deliveries. The default client refuses
+// to connect to internal addresses; a replacement is trusted as is.
+func (d *WebhookDispatcher) SetHTTPClient(client *http.Client) {
+	d.client = client
+}
+
+// SignPayload returns the hex-encoded HMAC-SHA256 of body using secret
+func SignPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatch notifies every active webhook of the company subscribed to event.
+// Deliveries run in the background so callers are never blocked by slow endpoints.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, companyID uuid.UUID, event string, data interface{}) {
+	webhooks, err := d.repo.GetActiveByEvent(ctx, companyID, event)
+	if err != nil {
+		logger.Error("Failed to load webhooks for event",
+			zap.Error(err),
+			zap.String("company_id", companyID.String()),
+			zap.String("event", event),
+		)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload := models.WebhookPayload{
+		ID:         uuid.New(),
+		Event:      event,
+		CompanyID:  companyID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error("Failed to marshal webhook payload", zap.Error(err), zap.String("event", event))
+		return
+	}
+
+	for _, webhook := range webhooks {
+		go func(w models.Webhook) {
+			if err := d.Deliver(context.Background(), &w, payload.ID, event, body); err != nil {
+				logger.Warn("Webhook delivery failed",
+					zap.Error(err),
+					zap.String("webhook_id", w.ID.String()),
+					zap.String("event", event),
+				)
+			}
+		}(webhook)
+	}
+}
+
+// Deliver POSTs a signed body to a single webhook, retrying with exponential backoff
+func (d *WebhookDispatcher) Deliver(ctx context.Context, webhook *models.Webhook, deliveryID uuid.UUID, event string, body []byte) error {
+	signature := SignPayload(webhook.Secret, body)
+	previousSignature := ""
+	if previous := webhook.ActivePreviousSecret(time.Now()); previous != "" {
+		previousSignature = SignPayload(previous, body)
+	}
+	backoff := d.backoff
+
+	var lastErr error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		lastErr = d.post(ctx, webhook.URL, deliveryID, event, signature, previousSignature, body)
+		if lastErr == nil {
+			logger.Info("Webhook delivered",
+				zap.String("webhook_id", webhook.ID.String()),
+				zap.String("event", event),
+				zap.Int("attempt", attempt),
+			)
+			return nil
+		}
+
+		if attempt == d.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return fmt.Errorf("webhook delivery failed after %d attempts: %w", d.maxAttempts, lastErr)
+}
+
+// post performs a single delivery attempt
+func (d *WebhookDispatcher) post(ctx context.Context, url string, deliveryID uuid.UUID, event, signature, previousSignature string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookDeliveryHeader, deliveryID.String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+	req.Header.Set(WebhookSignatureHeader, "sha256="+signature)
+	if previousSignature != "" {
+		req.Header.Set(WebhookPreviousSignatureHeader, "sha256="+previousSignature)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrWebhookURLNotAllowed is returned for webhook URLs the server must not call
+var ErrWebhookURLNotAllowed = errors.New("webhook URL not allowed")
+
+// ValidateWebhookURL checks that a webhook URL uses https and that its host does not point
+// at the server itself or an internal network. Host names are resolved and every address
+// they resolve to is checked.
+func ValidateWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: invalid URL", ErrWebhookURLNotAllowed)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: URL must use https", ErrWebhookURLNotAllowed)
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkWebhookAddr(addr)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %q does not resolve", ErrWebhookURLNotAllowed, host)
+	}
+	for _, addr := range addrs {
+		if err := checkWebhookAddr(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkWebhookAddr rejects loopback, private, link-local, multicast and unspecified addresses
+func checkWebhookAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() {
+		return fmt.Errorf("%w: %s is an internal address", ErrWebhookURLNotAllowed, addr)
+	}
+	return nil
+}
+
+// webhookDialControl re-checks the address actually dialed, so a host name that resolved
+// to a public address when the webhook was saved cannot be rebound to an internal one
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookURLNotAllowed, err)
+	}
+	return checkWebhookAddr(addrPort.Addr())
+}
+
+// newWebhookHTTPClient creates the client deliveries are sent with, which refuses to
+// connect to internal addresses, including after redirects
+func newWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: webhookDialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// AnonymizedEmailDomain is the domain of the pseudonymous emails produced by AnonymizeEmail
+const AnonymizedEmailDomain = "anonymized.invalid"
+
+// AnonymizeEmail replaces an email with a pseudonym derived from its hash. The same email
+// always gives the same pseudonym (case and surrounding spaces are ignored), so per-user
+// statistics still add up after the original address is gone.
+func AnonymizeEmail(email string) string {
+	normalized := strings.ToLower(strings.TrimSpace(email))
+	if normalized == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(normalized))
+	return "anon-" + hex.EncodeToString(sum[:8]) + "@" + AnonymizedEmailDomain
+}
+
+// IsAnonymizedEmail reports whether an email was produced by AnonymizeEmail
+func IsAnonymizedEmail(email string) bool {
+	return strings.HasPrefix(email, "anon-") && strings.HasSuffix(email, "@"+AnonymizedEmailDomain)
+}
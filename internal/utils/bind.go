@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Error codes returned by BindJSON
+const (
+	ErrCodeMalformedJSON    = "MALFORMED_JSON"
+	ErrCodeValidationFailed = "VALIDATION_FAILED"
+)
+
+// ErrCodeUnsupportedMediaType is returned for write requests whose body isn't JSON
+const ErrCodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+
+// FieldError describes a single field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// BindError is returned by BindJSON and carries the status and code to respond with
+type BindError struct {
+	Status  int          `json:"-"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Err     error        `json:"-"`
+}
+
+func (e *BindError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Code, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// BindJSON binds the request body into obj and classifies failures: bodies that
+// can't be decoded are 400 MALFORMED_JSON, bodies that decode but fail the
+// binding rules are 422 VALIDATION_FAILED with per-field details
+func BindJSON(c *gin.Context, obj interface{}) *BindError {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			name := jsonFieldName(obj, fieldErr)
+			fields = append(fields, FieldError{
+				Field:   name,
+				Tag:     fieldErr.Tag(),
+				Param:   fieldErr.Param(),
+				Message: fieldMessage(name, fieldErr),
+			})
+		}
+		return &BindError{
+			Status:  http.StatusUnprocessableEntity,
+			Code:    ErrCodeValidationFailed,
+			Message: "Request validation failed",
+			Fields:  fields,
+			Err:     err,
+		}
+	}
+
+	message := "Malformed JSON body"
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		message = "Request body is empty"
+	case errors.As(err, &syntaxErr):
+		message = fmt.Sprintf("Malformed JSON at position %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		message = fmt.Sprintf("Field '%s' must be of type %s", typeErr.Field, typeErr.Type)
+	}
+
+	return &BindError{
+		Status:  http.StatusBadRequest,
+		Code:    ErrCodeMalformedJSON,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// BindErrorResponse sends a bind error in the standard response format
+func BindErrorResponse(c *gin.Context, bindErr *BindError) {
+	ErrorResponse(c, bindErr.Status, bindErr.Message, bindErr)
+}
+
+// jsonFieldName resolves the JSON name of a failed field from the struct's json tag
+func jsonFieldName(obj interface{}, fieldErr validator.FieldError) string {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fieldErr.Field()
+	}
+
+	field, ok := t.FieldByName(fieldErr.StructField())
+	if !ok {
+		return fieldErr.Field()
+	}
+
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return fieldErr.Field()
+	}
+	return name
+}
+
+// fieldMessage builds a readable message for common validation tags
+func fieldMessage(field string, fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email", field)
+	case "uuid":
+		return fmt.Sprintf("%s must be a valid UUID", field)
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", field, fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", field, fieldErr.Param())
+	case "len":
+		return fmt.Sprintf("%s must have length %s", field, fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, fieldErr.Param())
+	default:
+		return fmt.Sprintf("%s failed validation: %s", field, fieldErr.Tag())
+	}
+}
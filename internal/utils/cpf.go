@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cpfFormatted aceita CPF com ou sem pontuação (XXX.XXX.XXX-XX ou 11 dígitos)
+var cpfFormatted = regexp.MustCompile(`^(\d{3}\.\d{3}\.\d{3}-\d{2}|\d{11})$`)
+
+// NormalizeCPF remove tudo que não for dígito do CPF
+func NormalizeCPF(cpf string) string {
+	var b strings.Builder
+	for _, r := range cpf {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ValidateCPF verifica o formato e os dígitos verificadores de um CPF
+func ValidateCPF(cpf string) bool {
+	cpf = strings.TrimSpace(cpf)
+	if !cpfFormatted.MatchString(cpf) {
+		return false
+	}
+
+	digits := NormalizeCPF(cpf)
+
+	// Sequências repetidas (000.000.000-00, 111.111.111-11, ...) passam no cálculo mas são inválidas
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return false
+	}
+
+	for _, length := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < length; i++ {
+			sum += int(digits[i]-'0') * (length + 1 - i)
+		}
+		check := (sum * 10) % 11
+		if check == 10 {
+			check = 0
+		}
+		if check != int(digits[length]-'0') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseLimit reads the optional "limit" query parameter. A missing value yields defaultLimit
+// and a value above maxLimit is clamped to it; a non-numeric, zero or negative value is an
+// error, so invalid input is rejected instead of silently replaced by the default.
+func ParseLimit(c *gin.Context, defaultLimit, maxLimit int) (int, error) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("limit must be a positive integer (max %d)", maxLimit)
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return limit, nil
+}
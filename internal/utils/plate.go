@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// licensePlateFormat matches Brazilian plates after NormalizeLicensePlate: the old format
+// (ABC1234) and the Mercosul format (ABC1D23)
+var licensePlateFormat = regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z0-9][0-9]{2}$`)
+
+// NormalizeLicensePlate uppercases a plate and drops separators, so "abc-1234" and
+// "ABC 1234" are stored as "ABC1234"
+func NormalizeLicensePlate(plate string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(plate) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ValidateLicensePlate reports whether a normalized plate has a valid format
+func ValidateLicensePlate(plate string) bool {
+	return licensePlateFormat.MatchString(plate)
+}
@@ -3,17 +3,47 @@ package utils
 import (
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/models"
 )
 
+// ErrCodeAuthContextMissing is the code of AuthContextMissingResponse
+const ErrCodeAuthContextMissing = "AUTH_CONTEXT_MISSING"
+
 // StandardResponse represents the standard API response format
 type StandardResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
+	Success    bool        `json:"success"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Meta       interface{} `json:"meta,omitempty"`
+	Error      interface{} `json:"error,omitempty"`
+}
+
+// Pagination describes the page returned by a list endpoint
+type Pagination struct {
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
+// NewPagination describes a page of limit items starting at offset, out of total
+// matching items
+func NewPagination(total, limit, offset int) Pagination {
+	return Pagination{
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+limit < total,
+	}
 }
 
 // SuccessResponse sends a success response
@@ -26,6 +56,53 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 	c.JSON(statusCode, response)
 }
 
+// PaginatedResponse sends a 200 response for a list endpoint: the page of items is the
+// data and the paging details go in pagination. A nil slice is sent as an empty list.
+func PaginatedResponse(c *gin.Context, items interface{}, total, limit, offset int) {
+	paginatedResponse(c, items, NewPagination(total, limit, offset), nil)
+}
+
+// FilteredPaginatedResponse is PaginatedResponse for list endpoints that also echo the
+// filters they applied, under meta.filters
+func FilteredPaginatedResponse(c *gin.Context, items interface{}, total, limit, offset int, filters interface{}) {
+	paginatedResponse(c, items, NewPagination(total, limit, offset), gin.H{"filters": filters})
+}
+
+func paginatedResponse(c *gin.Context, items interface{}, pagination Pagination, meta interface{}) {
+	if v := reflect.ValueOf(items); v.Kind() == reflect.Slice && v.IsNil() {
+		items = []interface{}{}
+	}
+	c.JSON(http.StatusOK, StandardResponse{
+		Success:    true,
+		Message:    "OK",
+		Data:       items,
+		Pagination: &pagination,
+		Meta:       meta,
+	})
+}
+
+// BatchResponse sends the per-item outcome of a batch request: 200 when every item
+// succeeded, otherwise 207 Multi-Status so clients know to inspect each result.
+// The response is only unsuccessful when no item succeeded.
+func BatchResponse(c *gin.Context, message string, result *models.BatchResult) {
+	status := http.StatusOK
+	if !result.AllSucceeded() {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, StandardResponse{
+		Success: result.Summary.Succeeded > 0 || result.Summary.Failed == 0,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// CreatedResponse sends a 201 response with a Location header pointing at the new resource,
+// resolved against the collection path of the request (POST /teams -> /teams/{id})
+func CreatedResponse(c *gin.Context, resourceID string, message string, data interface{}) {
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+resourceID)
+	SuccessResponse(c, http.StatusCreated, message, data)
+}
+
 // ErrorResponse sends an error response
 func ErrorResponse(c *gin.Context, statusCode int, message string, details interface{}) {
 	response := StandardResponse{
@@ -58,6 +135,25 @@ func UnauthorizedResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", message)
 }
 
+// AuthContextMissingResponse aborts a request whose user or company context, normally set
+// by the auth middleware, is missing. That means the route is wired without the middleware
+// it relies on, so it is logged as an error; the client gets 401 as the request can't be
+// attributed to a user.
+func AuthContextMissingResponse(c *gin.Context) {
+	logger.Error("Auth context missing after authentication middleware",
+		zap.String("method", c.Request.Method),
+		zap.String("path", c.FullPath()))
+
+	c.AbortWithStatusJSON(http.StatusUnauthorized, StandardResponse{
+		Success: false,
+		Message: "Unauthorized",
+		Error: gin.H{
+			"code":    ErrCodeAuthContextMissing,
+			"message": "Authentication context is missing",
+		},
+	})
+}
+
 // ForbiddenResponse sends a forbidden response
 func ForbiddenResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusForbidden, "Forbidden", message)
@@ -0,0 +1,6 @@
+-- Migration: Drop webhooks table
+
+DROP INDEX IF EXISTS idx_webhooks_events;
+DROP INDEX IF EXISTS idx_webhooks_company;
+
+DROP TABLE IF EXISTS webhooks;
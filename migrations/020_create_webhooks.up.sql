@@ -0,0 +1,20 @@
+-- Migration: Create webhooks table
+-- Stores company-registered endpoints that receive signed event notifications
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_company ON webhooks(company_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_events ON webhooks USING GIN(events);
+
+COMMENT ON TABLE webhooks IS 'Company endpoints notified via signed HTTP POST when subscribed events occur';
+COMMENT ON COLUMN webhooks.secret IS 'Shared secret used to sign payloads with HMAC-SHA256 (X-Dashtrack-Signature header)';
+COMMENT ON COLUMN webhooks.events IS 'Subscribed event types, e.g. auth.account_blocked, vehicle.assignment_changed';
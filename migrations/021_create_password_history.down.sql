@@ -0,0 +1,5 @@
+-- Migration: Drop password history table
+
+DROP INDEX IF EXISTS idx_password_history_user_created;
+
+DROP TABLE IF EXISTS password_history;
@@ -0,0 +1,14 @@
+-- Migration: Create password history table
+-- Keeps the most recent password hashes per user to prevent password reuse
+
+CREATE TABLE IF NOT EXISTS password_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_created ON password_history(user_id, created_at DESC);
+
+COMMENT ON TABLE password_history IS 'Previous bcrypt password hashes per user, trimmed to PASSWORD_HISTORY_SIZE entries';
+COMMENT ON COLUMN password_history.password_hash IS 'Bcrypt hash of a password the user had before a change or reset';
@@ -0,0 +1,17 @@
+-- Migration: Restore formatted CPF and global uniqueness
+
+DROP INDEX IF EXISTS idx_users_company_cpf;
+
+ALTER TABLE users
+DROP CONSTRAINT IF EXISTS chk_cpf_format;
+
+UPDATE users
+SET cpf = regexp_replace(cpf, '^(\d{3})(\d{3})(\d{3})(\d{2})$', '\1.\2.\3-\4')
+WHERE cpf ~ '^\d{11}$';
+
+ALTER TABLE users
+ADD CONSTRAINT chk_cpf_format
+CHECK (cpf ~ '^\d{3}\.\d{3}\.\d{3}-\d{2}$');
+
+ALTER TABLE users
+ADD CONSTRAINT users_cpf_key UNIQUE (cpf);
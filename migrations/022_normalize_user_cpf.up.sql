@@ -0,0 +1,23 @@
+-- Migration: Normalize user CPF to digits only and scope uniqueness per company
+
+-- Drop the formatted CPF check before rewriting the values
+ALTER TABLE users
+DROP CONSTRAINT IF EXISTS chk_cpf_format;
+
+UPDATE users
+SET cpf = regexp_replace(cpf, '\D', '', 'g')
+WHERE cpf ~ '\D';
+
+ALTER TABLE users
+ADD CONSTRAINT chk_cpf_format
+CHECK (cpf ~ '^\d{11}$');
+
+-- CPF was globally unique; two users of the same company must not share a CPF
+ALTER TABLE users
+DROP CONSTRAINT IF EXISTS users_cpf_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_company_cpf
+ON users(company_id, cpf)
+WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN users.cpf IS 'Brazilian tax ID stored as 11 digits, unique per company';
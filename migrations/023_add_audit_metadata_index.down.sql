@@ -0,0 +1,3 @@
+-- Migration: Drop audit log metadata index
+
+DROP INDEX IF EXISTS idx_audit_logs_metadata;
@@ -0,0 +1,3 @@
+-- Migration: Index audit log metadata for JSONB containment searches (metadata @> '{...}')
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_metadata ON audit_logs USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,6 @@
+-- Migration: Drop vehicle maintenance table
+
+DROP INDEX IF EXISTS idx_vehicle_maintenance_next_service_date;
+DROP INDEX IF EXISTS idx_vehicle_maintenance_vehicle_performed;
+
+DROP TABLE IF EXISTS vehicle_maintenance;
@@ -0,0 +1,24 @@
+-- Migration: Create vehicle maintenance table
+-- Service records per vehicle (oil change, tire rotation, ...) with the next service due
+
+CREATE TABLE IF NOT EXISTS vehicle_maintenance (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('oil_change', 'tire_rotation', 'brake_service', 'inspection', 'battery', 'filter_change', 'other')),
+    performed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    odometer_km INTEGER CHECK (odometer_km >= 0),
+    cost DECIMAL(12, 2) CHECK (cost >= 0),
+    notes TEXT,
+    next_service_date DATE,
+    next_service_odometer_km INTEGER CHECK (next_service_odometer_km >= 0),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_vehicle_performed ON vehicle_maintenance(vehicle_id, performed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_next_service_date ON vehicle_maintenance(next_service_date) WHERE next_service_date IS NOT NULL;
+
+COMMENT ON TABLE vehicle_maintenance IS 'Maintenance services performed on vehicles; company scope comes from the vehicle';
+COMMENT ON COLUMN vehicle_maintenance.next_service_date IS 'Date the next service of this type is due';
+COMMENT ON COLUMN vehicle_maintenance.next_service_odometer_km IS 'Odometer reading (km) at which the next service of this type is due';
@@ -0,0 +1,6 @@
+-- Migration: Drop vehicle documents table
+
+DROP INDEX IF EXISTS idx_vehicle_documents_expires_at;
+DROP INDEX IF EXISTS idx_vehicle_documents_vehicle;
+
+DROP TABLE IF EXISTS vehicle_documents;
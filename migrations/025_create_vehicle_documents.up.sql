@@ -0,0 +1,22 @@
+-- Migration: Create vehicle documents table
+-- Insurance, registration and other vehicle documents with their expiry date
+
+CREATE TABLE IF NOT EXISTS vehicle_documents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('insurance', 'registration', 'inspection', 'emission', 'permit', 'other')),
+    number VARCHAR(100) NOT NULL,
+    issued_at DATE,
+    expires_at DATE NOT NULL,
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_documents_vehicle ON vehicle_documents(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_documents_expires_at ON vehicle_documents(expires_at);
+
+COMMENT ON TABLE vehicle_documents IS 'Documents attached to vehicles; company scope comes from the vehicle';
+COMMENT ON COLUMN vehicle_documents.number IS 'Policy, registration or certificate number';
+COMMENT ON COLUMN vehicle_documents.expires_at IS 'Date the document lapses; managers are alerted ahead of it';
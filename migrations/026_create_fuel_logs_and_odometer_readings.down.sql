@@ -0,0 +1,7 @@
+-- Migration: Drop fuel logs and odometer readings tables
+
+DROP INDEX IF EXISTS idx_fuel_logs_vehicle_filled;
+DROP TABLE IF EXISTS fuel_logs;
+
+DROP INDEX IF EXISTS idx_odometer_readings_vehicle_recorded;
+DROP TABLE IF EXISTS odometer_readings;
@@ -0,0 +1,33 @@
+-- Migration: Create fuel logs and odometer readings tables
+-- Fuel purchases and running odometer per vehicle; vehicles.odometer keeps the latest reading
+
+CREATE TABLE IF NOT EXISTS odometer_readings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    odometer_km INTEGER NOT NULL CHECK (odometer_km >= 0),
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'fuel_log')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_odometer_readings_vehicle_recorded ON odometer_readings(vehicle_id, recorded_at DESC);
+
+CREATE TABLE IF NOT EXISTS fuel_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    liters DECIMAL(10, 2) NOT NULL CHECK (liters > 0),
+    cost DECIMAL(12, 2) NOT NULL CHECK (cost >= 0),
+    odometer_km INTEGER NOT NULL CHECK (odometer_km >= 0),
+    filled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    station VARCHAR(255),
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_vehicle_filled ON fuel_logs(vehicle_id, filled_at DESC);
+
+COMMENT ON TABLE odometer_readings IS 'Odometer history per vehicle; readings never decrease';
+COMMENT ON COLUMN odometer_readings.source IS 'manual entry or recorded along with a fuel log';
+COMMENT ON TABLE fuel_logs IS 'Fuel purchases per vehicle, used to compute fuel efficiency';
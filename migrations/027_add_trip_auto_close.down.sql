@@ -0,0 +1,9 @@
+-- Migration: Revert auto-closed trips support
+
+DROP INDEX IF EXISTS idx_trips_active_start_time;
+
+UPDATE vehicle_trips SET status = 'cancelled' WHERE status = 'auto_closed';
+
+ALTER TABLE vehicle_trips DROP CONSTRAINT IF EXISTS vehicle_trips_status_check;
+ALTER TABLE vehicle_trips ADD CONSTRAINT vehicle_trips_status_check
+    CHECK (status IN ('planning', 'active', 'completed', 'cancelled'));
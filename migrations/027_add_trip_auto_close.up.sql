@@ -0,0 +1,36 @@
+-- Migration: Allow stale active trips to be auto-closed
+-- vehicle_trips was only created by scripts/migrations/002; make sure it exists here too
+
+CREATE TABLE IF NOT EXISTS vehicle_trips (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    helper_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    start_location VARCHAR(255),
+    end_location VARCHAR(255),
+    start_latitude DECIMAL(10,8),
+    start_longitude DECIMAL(11,8),
+    end_latitude DECIMAL(10,8),
+    end_longitude DECIMAL(11,8),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE,
+    distance_km DECIMAL(8,2),
+    duration_minutes INTEGER,
+    fuel_consumption DECIMAL(8,2),
+    status VARCHAR(20) DEFAULT 'active',
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trips_vehicle_id ON vehicle_trips(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_trips_start_time ON vehicle_trips(start_time);
+
+ALTER TABLE vehicle_trips DROP CONSTRAINT IF EXISTS vehicle_trips_status_check;
+ALTER TABLE vehicle_trips ADD CONSTRAINT vehicle_trips_status_check
+    CHECK (status IN ('planning', 'active', 'completed', 'cancelled', 'auto_closed'));
+
+-- The stale trip job scans active trips by start time
+CREATE INDEX IF NOT EXISTS idx_trips_active_start_time ON vehicle_trips(start_time) WHERE status = 'active';
+
+COMMENT ON COLUMN vehicle_trips.status IS 'Trip status: planning, active, completed, cancelled, auto_closed (active past the maximum trip duration)';
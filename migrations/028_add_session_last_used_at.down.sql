@@ -0,0 +1,3 @@
+-- Migration: Drop session activity tracking
+
+ALTER TABLE session_tokens DROP COLUMN IF EXISTS last_used_at;
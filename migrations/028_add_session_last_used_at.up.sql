@@ -0,0 +1,6 @@
+-- Migration: Track session activity for the idle timeout
+-- Existing sessions start counting from the migration time
+
+ALTER TABLE session_tokens ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+COMMENT ON COLUMN session_tokens.last_used_at IS 'Last authenticated request of the session, updated at most once per minute';
@@ -0,0 +1,3 @@
+-- Migration: Drop the single active trip per vehicle constraint
+
+DROP INDEX IF EXISTS idx_trips_one_active_per_vehicle;
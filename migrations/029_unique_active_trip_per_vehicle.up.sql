@@ -0,0 +1,17 @@
+-- Migration: Allow a single active trip per vehicle
+-- Duplicates left by missed end-trip events are closed first, keeping the latest trip
+
+UPDATE vehicle_trips t
+SET status = 'auto_closed',
+    notes = CONCAT_WS(E'\n', t.notes, 'Auto-closed by migration 029: a later trip was active on the same vehicle'),
+    updated_at = NOW()
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY vehicle_id ORDER BY start_time DESC, id) AS rn
+    FROM vehicle_trips
+    WHERE status = 'active'
+) ranked
+WHERE t.id = ranked.id AND ranked.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trips_one_active_per_vehicle ON vehicle_trips(vehicle_id) WHERE status = 'active';
+
+COMMENT ON INDEX idx_trips_one_active_per_vehicle IS 'A vehicle has at most one active trip';
@@ -0,0 +1,3 @@
+-- Migration: Drop user notification preferences
+
+ALTER TABLE users DROP COLUMN IF EXISTS notify_new_session;
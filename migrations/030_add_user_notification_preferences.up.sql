@@ -0,0 +1,5 @@
+-- Migration: Add user notification preferences
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notify_new_session BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN users.notify_new_session IS 'Email the user when a new login revokes older sessions';
@@ -0,0 +1,4 @@
+-- Migration: Drop the login country from auth logs
+
+DROP INDEX IF EXISTS idx_auth_logs_user_country;
+ALTER TABLE auth_logs DROP COLUMN IF EXISTS country_code;
@@ -0,0 +1,10 @@
+-- Migration: Record the login country on auth logs
+
+ALTER TABLE auth_logs ADD COLUMN IF NOT EXISTS country_code VARCHAR(2);
+
+-- Distinct countries a user has successfully logged in from
+CREATE INDEX IF NOT EXISTS idx_auth_logs_user_country
+    ON auth_logs(user_id, country_code)
+    WHERE success = true AND country_code IS NOT NULL;
+
+COMMENT ON COLUMN auth_logs.country_code IS 'ISO 3166-1 alpha-2 country resolved from ip_address, NULL when geolocation is disabled or unknown';
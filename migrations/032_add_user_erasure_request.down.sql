@@ -0,0 +1,5 @@
+-- Migration: Drop account erasure request tracking
+
+DROP INDEX IF EXISTS idx_users_pending_erasure;
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
+ALTER TABLE users DROP COLUMN IF EXISTS erasure_requested_at;
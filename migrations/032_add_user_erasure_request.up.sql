@@ -0,0 +1,12 @@
+-- Migration: Track account erasure requests
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erasure_requested_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+
+-- Erasure requests still waiting for their logs to be anonymized
+CREATE INDEX IF NOT EXISTS idx_users_pending_erasure
+    ON users(erasure_requested_at)
+    WHERE erasure_requested_at IS NOT NULL AND erased_at IS NULL;
+
+COMMENT ON COLUMN users.erasure_requested_at IS 'When the user asked for their account to be erased (LGPD/GDPR); the account is soft-deleted at the same time';
+COMMENT ON COLUMN users.erased_at IS 'When the personal data in auth_logs and audit_logs was anonymized, after the grace period';
@@ -0,0 +1,6 @@
+-- Migration: Require phone and CPF on every user again
+-- Fails while purged users exist; they have no phone or CPF to restore
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_contact_required;
+ALTER TABLE users ALTER COLUMN cpf SET NOT NULL;
+ALTER TABLE users ALTER COLUMN phone SET NOT NULL;
@@ -0,0 +1,11 @@
+-- Migration: Allow purged users to keep their row without phone or CPF
+
+-- Purging a user (DELETE /api/v1/master/users/:id/purge) clears every personal field but keeps
+-- the row so foreign-keyed history (trips, team history, assignments) still counts it
+ALTER TABLE users ALTER COLUMN phone DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN cpf DROP NOT NULL;
+
+-- Accounts that were not erased still need both
+ALTER TABLE users
+ADD CONSTRAINT chk_users_contact_required
+CHECK (erased_at IS NOT NULL OR (phone IS NOT NULL AND cpf IS NOT NULL));
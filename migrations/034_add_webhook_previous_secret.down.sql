@@ -0,0 +1,4 @@
+-- Migration: Drop the previous webhook secret
+
+ALTER TABLE webhooks DROP COLUMN IF EXISTS previous_secret_expires_at;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS previous_secret;
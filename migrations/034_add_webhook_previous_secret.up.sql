@@ -0,0 +1,8 @@
+-- Migration: Keep the previous webhook secret valid for a grace window after rotation
+
+-- POST /api/v1/company/webhooks/:id/rotate-secret moves the current secret here; until
+-- previous_secret_expires_at deliveries are also signed with it so consumers can switch over
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS previous_secret VARCHAR(255);
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMP;
+
+COMMENT ON COLUMN webhooks.previous_secret IS 'Secret replaced by the last rotation, also used to sign payloads (X-Dashtrack-Signature-Previous) until previous_secret_expires_at';
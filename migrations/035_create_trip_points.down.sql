@@ -0,0 +1,4 @@
+-- Migration: Drop trip points table
+
+DROP INDEX IF EXISTS idx_trip_points_trip_recorded;
+DROP TABLE IF EXISTS trip_points;
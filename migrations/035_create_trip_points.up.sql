@@ -0,0 +1,17 @@
+-- Migration: Create trip points table
+-- GPS positions streamed while a trip is active; the ordered points are the trip's route
+
+CREATE TABLE IF NOT EXISTS trip_points (
+    id BIGSERIAL PRIMARY KEY,
+    trip_id UUID NOT NULL REFERENCES vehicle_trips(id) ON DELETE CASCADE,
+    latitude DECIMAL(10,8) NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude DECIMAL(11,8) NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    speed DECIMAL(6,2) CHECK (speed >= 0),
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_points_trip_recorded ON trip_points(trip_id, recorded_at);
+
+COMMENT ON TABLE trip_points IS 'GPS route of a trip, used for the polyline and as the distance fallback when a trip ends without distance_km';
+COMMENT ON COLUMN trip_points.speed IS 'Speed in km/h reported by the device, if any';
@@ -0,0 +1,7 @@
+-- Migration: Drop soft-deleted team memberships
+
+DELETE FROM team_members WHERE left_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_team_members_active_unique;
+ALTER TABLE team_members ADD CONSTRAINT team_members_unique UNIQUE (team_id, user_id);
+ALTER TABLE team_members DROP COLUMN IF EXISTS left_at;
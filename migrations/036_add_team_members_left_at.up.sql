@@ -0,0 +1,10 @@
+-- Migration: Soft-delete team memberships
+-- With TEAM_MEMBER_SOFT_DELETE enabled, removing a member stamps left_at instead of deleting the row
+
+ALTER TABLE team_members ADD COLUMN IF NOT EXISTS left_at TIMESTAMP WITH TIME ZONE;
+
+-- A user can rejoin a team they left, so uniqueness only applies to active memberships
+ALTER TABLE team_members DROP CONSTRAINT IF EXISTS team_members_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_members_active_unique ON team_members(team_id, user_id) WHERE left_at IS NULL;
+
+COMMENT ON COLUMN team_members.left_at IS 'When the user left the team; NULL for active members';
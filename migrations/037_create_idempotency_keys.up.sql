@@ -0,0 +1,20 @@
+-- Migration: Create idempotency keys table
+-- Responses of write requests sent with an Idempotency-Key header, replayed when a client
+-- retries the same request. Rows expire after IDEMPOTENCY_KEY_TTL_HOURS and are purged hourly.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL,
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    response_body BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+COMMENT ON COLUMN idempotency_keys.scope IS 'Who sent the key: a hash of the credentials, or the client IP for anonymous requests';
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 of method, path and body; a key reused for another request is rejected';
@@ -0,0 +1,3 @@
+-- Migration: Drop the vehicle assignment notification preference
+
+ALTER TABLE users DROP COLUMN IF EXISTS notify_assignment_changes;
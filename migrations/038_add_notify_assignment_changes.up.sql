@@ -0,0 +1,5 @@
+-- Migration: Opt-in emails about vehicle assignment changes
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notify_assignment_changes BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN users.notify_assignment_changes IS 'Email the user when they are assigned to or unassigned from a vehicle, or when the crew of a vehicle of a team they manage changes';
@@ -0,0 +1,3 @@
+-- Migration: Drop role permissions table
+
+DROP TABLE IF EXISTS role_permissions;
@@ -0,0 +1,17 @@
+-- Migration: Create role permissions table
+-- Authorization policy as data: each row grants one permission to one role. Checked by
+-- the RequirePermission middlewares; the API reloads it every minute.
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (role_id, permission)
+);
+
+-- Default policy: admins and masters may read the history of any user
+INSERT INTO role_permissions (role_id, permission)
+SELECT id, 'user:read_history' FROM roles WHERE name IN ('master', 'admin')
+ON CONFLICT (role_id, permission) DO NOTHING;
+
+COMMENT ON COLUMN role_permissions.permission IS 'Permission name in resource:action form, e.g. user:read_history';
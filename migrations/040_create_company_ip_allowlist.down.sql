@@ -0,0 +1,3 @@
+-- Migration: Drop company IP allowlist table
+
+DROP TABLE IF EXISTS company_ip_allowlist;
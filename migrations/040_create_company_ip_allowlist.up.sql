@@ -0,0 +1,17 @@
+-- Migration: Create company IP allowlist table
+-- Address ranges a company's users may log in from. A company without entries has no
+-- restriction; master users are never restricted.
+
+CREATE TABLE IF NOT EXISTS company_ip_allowlist (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    cidr CIDR NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (company_id, cidr)
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_ip_allowlist_company ON company_ip_allowlist(company_id);
+
+COMMENT ON TABLE company_ip_allowlist IS 'IPv4/IPv6 ranges the users of a company may log in from; no rows means no restriction';
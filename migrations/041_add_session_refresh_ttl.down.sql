@@ -0,0 +1,3 @@
+-- Migration: Drop the per-session refresh token lifetime
+
+ALTER TABLE session_tokens DROP COLUMN IF EXISTS refresh_ttl_seconds;
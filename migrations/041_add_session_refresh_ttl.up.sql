@@ -0,0 +1,6 @@
+-- Migration: Record the refresh token lifetime chosen for each session
+-- Logins with remember_me get a longer refresh lifetime; refreshing a session keeps it.
+
+ALTER TABLE session_tokens ADD COLUMN IF NOT EXISTS refresh_ttl_seconds BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN session_tokens.refresh_ttl_seconds IS 'Refresh token lifetime used when the session is refreshed; 0 for sessions created before it was recorded, which use JWT_REFRESH_EXPIRE_HOURS';
@@ -0,0 +1,3 @@
+-- Migration: Drop the per-company password rotation policy
+
+ALTER TABLE companies DROP COLUMN IF EXISTS password_max_age_days;
@@ -0,0 +1,7 @@
+-- Migration: Let companies override the password rotation policy
+-- Users whose password is older than the limit must change it before logging in.
+
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS password_max_age_days INTEGER
+    CHECK (password_max_age_days IS NULL OR password_max_age_days >= 0);
+
+COMMENT ON COLUMN companies.password_max_age_days IS 'Days after which user passwords expire; NULL uses PASSWORD_MAX_AGE_DAYS, 0 disables expiry for the company';
@@ -0,0 +1,11 @@
+-- Migration: Drop company settings table, restoring the password rotation column
+
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS password_max_age_days INTEGER
+    CHECK (password_max_age_days IS NULL OR password_max_age_days >= 0);
+
+UPDATE companies c
+SET password_max_age_days = (s.settings->>'password_max_age_days')::INTEGER
+FROM company_settings s
+WHERE s.company_id = c.id AND s.settings ? 'password_max_age_days';
+
+DROP TABLE IF EXISTS company_settings;
@@ -0,0 +1,22 @@
+-- Migration: Create company settings table
+-- Single home for tenant-level options (session limits, IP allowlist, password policy),
+-- stored as a JSONB document; keys that are not set use the application defaults.
+
+CREATE TABLE IF NOT EXISTS company_settings (
+    company_id UUID PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+    settings JSONB NOT NULL DEFAULT '{}'::jsonb,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The password rotation override moves from its own column into the settings document
+INSERT INTO company_settings (company_id, settings)
+SELECT id, jsonb_build_object('password_max_age_days', password_max_age_days)
+FROM companies
+WHERE password_max_age_days IS NOT NULL
+ON CONFLICT (company_id) DO NOTHING;
+
+ALTER TABLE companies DROP COLUMN IF EXISTS password_max_age_days;
+
+COMMENT ON TABLE company_settings IS 'Tenant-level options of a company; missing rows and keys use the application defaults';
+COMMENT ON COLUMN company_settings.settings IS 'JSON document of models.CompanySettings';
@@ -7,7 +7,7 @@ SELECT
     'admin@test.com', 
     '$2a$12$9GVupqmRFeydx4TjCwboqOL7zZQMzSL8pw0Mi.URuc4pbymow1Msi', 
     '+5511999999999', 
-    '000.000.000-00', 
+    '00000000000', 
     r.id, 
     NULL, 
     true 
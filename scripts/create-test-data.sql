@@ -25,7 +25,7 @@ BEGIN
     DELETE FROM users WHERE 
         id IN ('1b4f2ac0-d611-474d-9d25-97b3fa5369f4'::uuid, '3ac7e51e-28b0-4498-adf7-27b406b33c37'::uuid)
         OR email IN ('testuser@dashtrack.com', 'testdriver@dashtrack.com')
-        OR cpf IN ('111.222.333-44', '111.222.333-45');
+        OR cpf IN ('11122233344', '11122233345');
     
     -- Create a test user for team member operations
     INSERT INTO users (id, name, email, password, role_id, company_id, cpf, phone, active, created_at, updated_at)
@@ -36,7 +36,7 @@ BEGIN
         '$2a$12$UVS/cjIV95Lc8SIXs1o41u0T5il06vjkJ71f7GruHbXm.pqgp3Lh2', -- password: "password"
         driver_role_id,
         master_company_id,
-        '111.222.333-44',
+        '11122233344',
         '+5511888888888',
         true,
         NOW(),
@@ -54,7 +54,7 @@ BEGIN
         '$2a$12$UVS/cjIV95Lc8SIXs1o41u0T5il06vjkJ71f7GruHbXm.pqgp3Lh2', -- password: "password"
         driver_role_id,
         master_company_id,
-        '111.222.333-45',
+        '11122233345',
         '+5511888888887',
         true,
         NOW(),
@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+// UserHistoryTestSuite tests the user history summary against a seeded database
+type UserHistoryTestSuite struct {
+	suite.Suite
+	testDB       *testutils.TestDB
+	tokenService *services.TokenService
+	authHandler  *handlers.AuthHandler
+	roleRepo     *repository.RoleRepository
+	driverRoleID uuid.UUID
+}
+
+func TestUserHistoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserHistoryTestSuite))
+}
+
+func (s *UserHistoryTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+
+	var err error
+	s.testDB, err = testutils.SetupTestDB("user_history")
+	s.Require().NoError(err, "Failed to setup test database")
+
+	s.roleRepo = repository.NewRoleRepository(s.testDB.SqlDB)
+	s.tokenService = services.NewTokenService(
+		s.testDB.SqlxDB,
+		"test-secret-key-min-32-characters-long",
+		15*time.Minute,
+		24*time.Hour,
+	)
+
+	s.authHandler = handlers.NewAuthHandler(
+		repository.NewUserRepository(s.testDB.SqlxDB),
+		repository.NewAuthLogRepository(s.testDB.SqlDB),
+		s.roleRepo,
+		s.tokenService,
+		nil,
+		4,
+	)
+
+	driverRole, err := s.roleRepo.GetByName("driver")
+	s.Require().NoError(err)
+	s.driverRoleID = driverRole.ID
+}
+
+func (s *UserHistoryTestSuite) TearDownSuite() {
+	if s.testDB != nil {
+		s.testDB.TearDown()
+	}
+}
+
+// createUser inserts a user that will view its own history
+func (s *UserHistoryTestSuite) createUser(email string) uuid.UUID {
+	userID := uuid.New()
+	_, err := s.testDB.SqlxDB.Exec(`
+		INSERT INTO users (id, name, email, password, role_id, active, created_at, updated_at)
+		VALUES ($1, $2, $3, 'not-a-real-hash', $4, true, NOW(), NOW())
+	`, userID, "History User", email, s.driverRoleID)
+	s.Require().NoError(err)
+	return userID
+}
+
+// seedLogout inserts a logout audit entry with the given metadata
+func (s *UserHistoryTestSuite) seedLogout(userID uuid.UUID, metadata map[string]interface{}) {
+	metadataJSON, err := json.Marshal(metadata)
+	s.Require().NoError(err)
+
+	_, err = s.testDB.SqlxDB.Exec(`
+		INSERT INTO audit_logs (user_id, action, resource, ip_address, user_agent, metadata, success, created_at)
+		VALUES ($1, 'logout', 'session', '127.0.0.1', 'test-agent', $2, true, NOW())
+	`, userID, metadataJSON)
+	s.Require().NoError(err)
+}
+
+// getHistory calls the history endpoint as the user itself
+func (s *UserHistoryTestSuite) getHistory(userID uuid.UUID) handlers.UserHistoryResponse {
+	router := gin.New()
+	router.GET("/users/:id/history", func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Set("role_name", "driver")
+		c.Next()
+	}, s.authHandler.GetUserHistoryGin)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%s/history", userID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var response handlers.UserHistoryResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+// TestAverageSessionMinutesMatchesMetadataMean asserts the average is the mean
+// of the session_duration_minutes recorded on logout
+func (s *UserHistoryTestSuite) TestAverageSessionMinutesMatchesMetadataMean() {
+	userID := s.createUser("history-avg@test.com")
+
+	durations := []float64{10, 20, 45}
+	for _, d := range durations {
+		s.seedLogout(userID, map[string]interface{}{"session_duration_minutes": d})
+	}
+
+	response := s.getHistory(userID)
+
+	s.Equal(3, response.Summary.TotalLogouts)
+	s.InDelta(25.0, response.Summary.AverageSessionMinutes, 0.0001)
+}
+
+// TestAverageSessionMinutesWithoutDurations asserts a NULL average maps to 0
+func (s *UserHistoryTestSuite) TestAverageSessionMinutesWithoutDurations() {
+	userID := s.createUser("history-null@test.com")
+
+	// Logout recorded without a duration, so AVG yields NULL
+	s.seedLogout(userID, map[string]interface{}{"session_id": uuid.New().String()})
+
+	response := s.getHistory(userID)
+
+	s.Equal(1, response.Summary.TotalLogouts)
+	s.Equal(0.0, response.Summary.AverageSessionMinutes)
+}
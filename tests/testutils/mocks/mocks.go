@@ -9,6 +9,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/services"
 )
 
@@ -78,6 +79,35 @@ func (mr *MockUserRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), ctx, id)
 }
 
+// RequestErasure mocks base method.
+func (m *MockUserRepository) RequestErasure(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestErasure", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestErasure indicates an expected call of RequestErasure.
+func (mr *MockUserRepositoryMockRecorder) RequestErasure(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestErasure", reflect.TypeOf((*MockUserRepository)(nil).RequestErasure), ctx, id)
+}
+
+// PurgeUser mocks base method.
+func (m *MockUserRepository) PurgeUser(ctx context.Context, id uuid.UUID) (*models.UserPurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUser", ctx, id)
+	ret0, _ := ret[0].(*models.UserPurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUser indicates an expected call of PurgeUser.
+func (mr *MockUserRepositoryMockRecorder) PurgeUser(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUser", reflect.TypeOf((*MockUserRepository)(nil).PurgeUser), ctx, id)
+}
+
 // GetByEmail mocks base method.
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -109,18 +139,18 @@ func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.C
 }
 
 // ListByCompanyAndRoles mocks base method.
-func (m *MockUserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int) ([]*models.User, error) {
+func (m *MockUserRepository) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int, sort repository.Sort) ([]*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByCompanyAndRoles", ctx, companyID, roles, limit, offset)
+	ret := m.ctrl.Call(m, "ListByCompanyAndRoles", ctx, companyID, roles, limit, offset, sort)
 	ret0, _ := ret[0].([]*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByCompanyAndRoles indicates an expected call of ListByCompanyAndRoles.
-func (mr *MockUserRepositoryMockRecorder) ListByCompanyAndRoles(ctx, companyID, roles, limit, offset interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) ListByCompanyAndRoles(ctx, companyID, roles, limit, offset, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCompanyAndRoles", reflect.TypeOf((*MockUserRepository)(nil).ListByCompanyAndRoles), ctx, companyID, roles, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCompanyAndRoles", reflect.TypeOf((*MockUserRepository)(nil).ListByCompanyAndRoles), ctx, companyID, roles, limit, offset, sort)
 }
 
 // Update mocks base method.
@@ -139,18 +169,18 @@ func (mr *MockUserRepositoryMockRecorder) Update(ctx, id, req interface{}) *gomo
 }
 
 // List mocks base method.
-func (m *MockUserRepository) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID) ([]*models.User, error) {
+func (m *MockUserRepository) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID, sort repository.Sort) ([]*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, limit, offset, active, roleID)
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset, active, roleID, sort)
 	ret0, _ := ret[0].([]*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockUserRepositoryMockRecorder) List(ctx, limit, offset, active, roleID interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) List(ctx, limit, offset, active, roleID, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, limit, offset, active, roleID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, limit, offset, active, roleID, sort)
 }
 
 // CountUsers mocks base method.
@@ -183,19 +213,64 @@ func (mr *MockUserRepositoryMockRecorder) CountActiveUsers(ctx, companyID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveUsers", reflect.TypeOf((*MockUserRepository)(nil).CountActiveUsers), ctx, companyID)
 }
 
+// ExistsByCPF mocks base method.
+func (m *MockUserRepository) ExistsByCPF(ctx context.Context, companyID *uuid.UUID, cpf string, excludeUserID *uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByCPF", ctx, companyID, cpf, excludeUserID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByCPF indicates an expected call of ExistsByCPF.
+func (mr *MockUserRepositoryMockRecorder) ExistsByCPF(ctx, companyID, cpf, excludeUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByCPF", reflect.TypeOf((*MockUserRepository)(nil).ExistsByCPF), ctx, companyID, cpf, excludeUserID)
+}
+
+// ExistsByEmail mocks base method.
+func (m *MockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByEmail", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByEmail indicates an expected call of ExistsByEmail.
+func (mr *MockUserRepositoryMockRecorder) ExistsByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByEmail", reflect.TypeOf((*MockUserRepository)(nil).ExistsByEmail), ctx, email)
+}
+
+// UpdatePreferences mocks base method.
+func (m *MockUserRepository) UpdatePreferences(ctx context.Context, id uuid.UUID, req models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", ctx, id, req)
+	ret0, _ := ret[0].(*models.UserPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences.
+func (mr *MockUserRepositoryMockRecorder) UpdatePreferences(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockUserRepository)(nil).UpdatePreferences), ctx, id, req)
+}
+
 // GetByCompany mocks base method.
-func (m *MockUserRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]*models.User, error) {
+func (m *MockUserRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, sort repository.Sort) ([]*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCompany", ctx, companyID, limit, offset)
+	ret := m.ctrl.Call(m, "GetByCompany", ctx, companyID, limit, offset, sort)
 	ret0, _ := ret[0].([]*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCompany indicates an expected call of GetByCompany.
-func (mr *MockUserRepositoryMockRecorder) GetByCompany(ctx, companyID, limit, offset interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) GetByCompany(ctx, companyID, limit, offset, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCompany", reflect.TypeOf((*MockUserRepository)(nil).GetByCompany), ctx, companyID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCompany", reflect.TypeOf((*MockUserRepository)(nil).GetByCompany), ctx, companyID, limit, offset, sort)
 }
 
 // ListByRoles mocks base method.
@@ -227,6 +302,51 @@ func (mr *MockUserRepositoryMockRecorder) UpdateLoginAttempts(ctx, id, attempts,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoginAttempts", reflect.TypeOf((*MockUserRepository)(nil).UpdateLoginAttempts), ctx, id, attempts, blockedUntil)
 }
 
+// IncrementLoginAttempts mocks base method.
+func (m *MockUserRepository) IncrementLoginAttempts(ctx context.Context, id uuid.UUID, maxAttempts int, blockUntil time.Time) (*models.LoginAttempts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLoginAttempts", ctx, id, maxAttempts, blockUntil)
+	ret0, _ := ret[0].(*models.LoginAttempts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementLoginAttempts indicates an expected call of IncrementLoginAttempts.
+func (mr *MockUserRepositoryMockRecorder) IncrementLoginAttempts(ctx, id, maxAttempts, blockUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLoginAttempts", reflect.TypeOf((*MockUserRepository)(nil).IncrementLoginAttempts), ctx, id, maxAttempts, blockUntil)
+}
+
+// GetByIDs mocks base method.
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[uuid.UUID]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockUserRepositoryMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUserRepository)(nil).GetByIDs), ctx, ids)
+}
+
+// GetLastActivity mocks base method.
+func (m *MockUserRepository) GetLastActivity(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastActivity", ctx, userIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]models.UserActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastActivity indicates an expected call of GetLastActivity.
+func (mr *MockUserRepositoryMockRecorder) GetLastActivity(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastActivity", reflect.TypeOf((*MockUserRepository)(nil).GetLastActivity), ctx, userIDs)
+}
+
 // UpdateLastLogin mocks base method.
 func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -269,6 +389,20 @@ func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, id, hashedPassword
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, id, hashedPassword)
 }
 
+// UpgradePasswordHash mocks base method.
+func (m *MockUserRepository) UpgradePasswordHash(ctx context.Context, id uuid.UUID, currentHash, upgradedHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradePasswordHash", ctx, id, currentHash, upgradedHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpgradePasswordHash indicates an expected call of UpgradePasswordHash.
+func (mr *MockUserRepositoryMockRecorder) UpgradePasswordHash(ctx, id, currentHash, upgradedHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradePasswordHash", reflect.TypeOf((*MockUserRepository)(nil).UpgradePasswordHash), ctx, id, currentHash, upgradedHash)
+}
+
 // GetUserContext mocks base method.
 func (m *MockUserRepository) GetUserContext(ctx context.Context, userID uuid.UUID) (*models.UserContext, error) {
 	m.ctrl.T.Helper()
@@ -337,6 +471,21 @@ func (mr *MockRoleRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRoleRepository)(nil).GetAll), ctx)
 }
 
+// List mocks base method.
+func (m *MockRoleRepository) List(ctx context.Context, filter models.RoleFilter) ([]*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoleRepositoryMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoleRepository)(nil).List), ctx, filter)
+}
+
 // GetByID mocks base method.
 func (m *MockRoleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Role, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,72 @@
+package testutils
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SpanRecorder collects every span ended while it is installed as the global tracer provider
+type SpanRecorder struct {
+	*tracetest.SpanRecorder
+}
+
+// InstallSpanRecorder replaces the global tracer provider with one that records spans and
+// restores the previous provider when the test ends.
+//
+// Repositories, services and handlers call otel.Tracer when they are constructed, so the
+// recorder must be installed before the code under test is built. The provider is global:
+// tests using it must not call t.Parallel.
+func InstallSpanRecorder(t testing.TB) *SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	return &SpanRecorder{SpanRecorder: recorder}
+}
+
+// Named returns the ended spans called name, in the order they ended
+func (r *SpanRecorder) Named(name string) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, span := range r.Ended() {
+		if span.Name() == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// RequireSpan fails the test unless exactly one ended span is called name, and returns it
+func (r *SpanRecorder) RequireSpan(t testing.TB, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	spans := r.Named(name)
+	if len(spans) != 1 {
+		names := make([]string, 0, len(r.Ended()))
+		for _, span := range r.Ended() {
+			names = append(names, span.Name())
+		}
+		t.Fatalf("expected one %q span, got %d (ended spans: %v)", name, len(spans), names)
+	}
+	return spans[0]
+}
+
+// SpanAttributes returns the attributes of span keyed by name
+func SpanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes()))
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
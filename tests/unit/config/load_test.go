@@ -0,0 +1,69 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+)
+
+// writeConfigFile writes content to a file named name in a temporary directory and points CONFIG_FILE at it
+func writeConfigFile(t *testing.T, name, content string) {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+}
+
+func TestLoad_UsesConfigFileValues(t *testing.T) {
+	writeConfigFile(t, "config.yaml", `
+server_port: "9090"
+jwt_access_expire_minutes: 15
+cors_allowed_origins:
+  - https://app.dashtrack.io
+  - https://admin.dashtrack.io
+`)
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.ServerPort)
+	assert.Equal(t, 15, cfg.JWTAccessExpireMinutes)
+	assert.Equal(t, []string{"https://app.dashtrack.io", "https://admin.dashtrack.io"}, cfg.CORSAllowedOrigins)
+	assert.Equal(t, 24, cfg.JWTRefreshExpireHours, "unset keys keep their defaults")
+}
+
+func TestLoad_EnvironmentOverridesConfigFile(t *testing.T) {
+	writeConfigFile(t, "config.json", `{"SERVER_PORT": "9090", "BCRYPT_COST": 10}`)
+	t.Setenv("SERVER_PORT", "7070")
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "7070", cfg.ServerPort)
+	assert.Equal(t, 10, cfg.BcryptCost)
+}
+
+func TestLoad_MissingConfigFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	_, err := config.Load()
+
+	assert.Error(t, err)
+}
+
+func TestLoad_SMTPTLSModeFallsBackToUseTLS(t *testing.T) {
+	writeConfigFile(t, "config.json", `{"SMTP_USE_TLS": false}`)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, config.SMTPTLSModeNone, cfg.SMTP.TLSMode)
+
+	t.Setenv("SMTP_TLS_MODE", "TLS")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, config.SMTPTLSModeImplicit, cfg.SMTP.TLSMode)
+}
@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+)
+
+// stubSecretProvider serves secrets from a map
+type stubSecretProvider map[string]string
+
+func (p stubSecretProvider) GetSecret(key string) (string, bool, error) {
+	value, ok := p[key]
+	return value, ok, nil
+}
+
+func TestLoad_SecretProviderOverridesEnvironment(t *testing.T) {
+	config.RegisterSecretProvider("stub", func(*config.Config) (config.SecretProvider, error) {
+		return stubSecretProvider{"JWT_SECRET": "secret-from-the-vault-0123456789abcdef"}, nil
+	})
+	t.Setenv("SECRET_PROVIDER", "stub")
+	t.Setenv("JWT_SECRET", "secret-from-the-environment-0123456789")
+	t.Setenv("DB_SOURCE", "postgres://dashtrack@db/dashtrack")
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "secret-from-the-vault-0123456789abcdef", cfg.JWTSecret)
+	assert.Equal(t, "postgres://dashtrack@db/dashtrack", cfg.DBSource, "secrets the provider lacks keep the env value")
+}
+
+func TestLoad_FileSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "DB_SOURCE"), []byte("postgres://dashtrack@from-secret-file/dashtrack\n"), 0o600))
+	t.Setenv("SECRET_PROVIDER", "file")
+	t.Setenv("SECRETS_DIR", dir)
+	t.Setenv("DB_SOURCE", "postgres://dashtrack@env/dashtrack")
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://dashtrack@from-secret-file/dashtrack", cfg.DBSource)
+}
+
+func TestLoad_UnavailableSecretProvider(t *testing.T) {
+	for _, name := range []string{"vault", "does-not-exist"} {
+		t.Setenv("SECRET_PROVIDER", name)
+
+		_, err := config.Load()
+
+		assert.Error(t, err, name)
+	}
+}
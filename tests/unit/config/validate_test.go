@@ -0,0 +1,256 @@
+package config_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+)
+
+// validConfig returns a configuration that passes Validate
+func validConfig() *config.Config {
+	return &config.Config{
+		DBSource:                        "postgres://dashtrack@localhost:5432/dashtrack",
+		ServerPort:                      "8080",
+		JWTSecret:                       strings.Repeat("s", config.MinJWTSecretLength),
+		JWTAccessExpireMinutes:          60,
+		JWTRefreshExpireHours:           24,
+		JWTRememberMeRefreshExpireHours: 720,
+		OnlineThresholdMinutes:          5,
+		PasswordResetExpireHours:        1,
+		BcryptCost:                      12,
+		AuthCookieSameSite:              "strict",
+		StorageDriver:                   "local",
+		StorageLocalDir:                 "./uploads",
+		StoragePublicURL:                "/uploads",
+		BatchMaxSize:                    1000,
+		AvatarMaxBytes:                  5 << 20,
+		AvatarMaxDimension:              512,
+	}
+}
+
+func TestValidate_AcceptsValidConfig(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+}
+
+func TestValidate_MissingJWTSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = ""
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET is required")
+}
+
+func TestValidate_ShortJWTSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = "changeme"
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET must be at least")
+}
+
+func TestValidate_JWTKeysReplaceJWTSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = ""
+	cfg.JWTKeys = "2026-10:" + strings.Repeat("n", config.MinJWTSecretLength)
+	cfg.JWTActiveKeyID = "2026-10"
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_InvalidJWTKeys(t *testing.T) {
+	long := strings.Repeat("k", config.MinJWTSecretLength)
+	tests := []struct {
+		name     string
+		keys     string
+		activeID string
+		want     string
+	}{
+		{"malformed entry", "2026-10", config.DefaultJWTKeyID, "JWT_KEYS entries must look like kid:secret"},
+		{"duplicate key ID", "a:" + long + ",a:" + long, config.DefaultJWTKeyID, `defines key "a" twice`},
+		{"short key", "2026-10:short", config.DefaultJWTKeyID, `JWT_KEYS key "2026-10" must be at least`},
+		{"unknown active key", "2026-10:" + long, "2026-11", `JWT_ACTIVE_KEY_ID "2026-11"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.JWTKeys = tt.keys
+			cfg.JWTActiveKeyID = tt.activeID
+
+			err := cfg.Validate()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestJWTSigningKeys_IncludesJWTSecretAsDefault(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTKeys = " 2026-10:new-secret , "
+
+	keys, err := cfg.JWTSigningKeys()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{config.DefaultJWTKeyID: cfg.JWTSecret, "2026-10": "new-secret"}, keys)
+}
+
+func TestValidate_TrustedProxies(t *testing.T) {
+	cfg := validConfig()
+	cfg.TrustedProxies = []string{"10.0.0.0/8", "192.0.2.10", "2001:db8::/32"}
+	cfg.TrustedPlatform = "Cloudflare"
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "CF-Connecting-IP", cfg.TrustedPlatformHeader())
+
+	cfg.TrustedProxies = []string{"10.0.0.0/33", "load-balancer"}
+	cfg.TrustedPlatform = "heroku"
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `TRUSTED_PROXIES entries must be IPs or CIDRs, got "10.0.0.0/33"`)
+	assert.Contains(t, err.Error(), `got "load-balancer"`)
+	assert.Contains(t, err.Error(), `TRUSTED_PLATFORM must be cloudflare, google-app-engine or fly-io, got "heroku"`)
+}
+
+func TestValidate_InvalidPort(t *testing.T) {
+	for _, port := range []string{"", "http", "0", "70000"} {
+		cfg := validConfig()
+		cfg.ServerPort = port
+
+		err := cfg.Validate()
+
+		require.Error(t, err, port)
+		assert.Contains(t, err.Error(), "SERVER_PORT must be a number between 1 and 65535", port)
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = ""
+	cfg.ServerPort = "http"
+	cfg.JWTAccessExpireMinutes = 0
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Len(t, strings.Split(err.Error(), "\n"), 3)
+}
+
+func TestValidate_RefreshMustOutliveAccessToken(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTAccessExpireMinutes = 120
+	cfg.JWTRefreshExpireHours = 1
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_REFRESH_EXPIRE_HOURS")
+}
+
+func TestValidate_ProductionRejectsTestSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerEnv = "production"
+	cfg.JWTSecret = "test-secret-key-min-32-characters-long"
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET is a published default or test value")
+}
+
+func TestValidate_ProductionRejectsLowBcryptCost(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerEnv = "production"
+	cfg.BcryptCost = 4
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BCRYPT_COST 4 is below")
+}
+
+func TestValidate_TestEnvAllowsInsecureDefaults(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerEnv = "test"
+	cfg.JWTSecret = "test-secret-key-min-32-characters-long"
+	cfg.BcryptCost = 4
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_DevelopmentOnlyWarnsAboutInsecureDefaults(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerEnv = "development"
+	cfg.JWTSecret = "test-secret-key-min-32-characters-long"
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	assert.NoError(t, cfg.Validate())
+	assert.Contains(t, logged.String(), "WARNING: JWT_SECRET is a published default or test value")
+}
+
+func TestValidate_MQTTSettingsCheckedOnlyWhenEnabled(t *testing.T) {
+	cfg := validConfig()
+	cfg.MQTTBrokerURL = "localhost"
+	cfg.MQTTQoS = 3
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MQTTEnabled = true
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MQTT_BROKER_URL must be a tcp, mqtt, ssl, mqtts, ws or wss broker URL")
+	assert.Contains(t, err.Error(), "MQTT_CLIENT_ID is required")
+	assert.Contains(t, err.Error(), "MQTT_QOS must be 0, 1 or 2")
+
+	cfg.MQTTBrokerURL = "tcp://broker:1883"
+	cfg.MQTTClientID = "dashtrack-api"
+	cfg.MQTTQoS = 1
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_SMTPSettingsCheckedOnlyWhenHostSet(t *testing.T) {
+	cfg := validConfig()
+	cfg.SMTP.Port = "smtp"
+	cfg.SMTP.TLSMode = "ssl"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.SMTP.Host = "smtp.example.com"
+	cfg.SMTP.Username = "mailer"
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SMTP_PORT must be a number between 1 and 65535")
+	assert.Contains(t, err.Error(), "SMTP_FROM must be an email address")
+	assert.Contains(t, err.Error(), "SMTP_TLS_MODE must be starttls, tls or none")
+	assert.Contains(t, err.Error(), "SMTP_PASSWORD is required when SMTP_USERNAME is set")
+
+	cfg.SMTP.Port = "465"
+	cfg.SMTP.From = "noreply@example.com"
+	cfg.SMTP.TLSMode = config.SMTPTLSModeImplicit
+	cfg.SMTP.Password = "secret"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_ProductionRequiresSMTP(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerEnv = "production"
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SMTP_HOST is required in production")
+}
@@ -0,0 +1,36 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/database"
+)
+
+func TestConfigurePool(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	database.ConfigurePool(db, database.PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 30 * time.Minute,
+	})
+
+	assert.Equal(t, 25, db.Stats().MaxOpenConnections)
+}
+
+func TestConfigurePool_ZeroKeepsDefaults(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	database.ConfigurePool(db, database.PoolConfig{})
+
+	assert.Equal(t, 0, db.Stats().MaxOpenConnections, "0 means unlimited in database/sql")
+}
@@ -0,0 +1,40 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/docs"
+)
+
+func TestOpenAPISpec_IsValidJSON(t *testing.T) {
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(docs.OpenAPISpec, &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+}
+
+func TestOpenAPISpec_CoversCoreEndpoints(t *testing.T) {
+	var spec struct {
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(docs.OpenAPISpec, &spec))
+
+	for _, path := range []string{
+		"/api/v1/auth/login",
+		"/api/v1/users",
+		"/api/v1/company-admin/teams",
+		"/api/v1/company-admin/vehicles",
+	} {
+		assert.Contains(t, spec.Paths, path)
+	}
+
+	for _, schema := range []string{"LoginResponse", "UserResponse", "Team", "Vehicle"} {
+		assert.Contains(t, spec.Components.Schemas, schema)
+	}
+}
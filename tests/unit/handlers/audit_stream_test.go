@@ -0,0 +1,224 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+var streamAuditColumns = []string{
+	"id", "user_id", "user_email", "company_id", "action", "resource", "resource_id",
+	"method", "path", "ip_address", "user_agent", "changes", "metadata",
+	"success", "error_message", "status_code", "duration_ms", "trace_id", "span_id", "created_at",
+}
+
+// streamRecorder is a ResponseRecorder that supports the CloseNotifier gin's c.Stream needs
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r *streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+// streamAuditRows returns n login audit log rows
+func streamAuditRows(n int) *sqlmock.Rows {
+	rows := sqlmock.NewRows(streamAuditColumns)
+	for i := 0; i < n; i++ {
+		rows.AddRow(uuid.New(), nil, nil, nil, "LOGIN", "auth", nil,
+			nil, nil, "127.0.0.1", "test-agent", []byte("null"), []byte("null"),
+			true, nil, nil, nil, nil, nil, time.Now())
+	}
+	return rows
+}
+
+// streamMasterExport runs StreamLogs as master and returns the recorder and its CSV lines
+func streamMasterExport(t *testing.T, handler *handlers.AuditHandler) (*flushCountingRecorder, []string) {
+	w := &flushCountingRecorder{streamRecorder: streamRecorder{httptest.NewRecorder()}}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs/export?format=csv", nil)
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), Role: "master", IsMaster: true})
+
+	handler.StreamLogs(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	return w, strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+}
+
+// flushCountingRecorder counts the chunks flushed to the client
+type flushCountingRecorder struct {
+	streamRecorder
+	flushes int
+}
+
+func (r *flushCountingRecorder) Flush() {
+	r.streamRecorder.Flush()
+	r.flushes++
+}
+
+func TestStreamLogs_TruncatesAtRowCap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM audit_logs")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs") + ".*" + regexp.QuoteMeta("LIMIT $1")).
+		WithArgs(3).
+		WillReturnRows(streamAuditRows(3))
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+	handler.SetExportMaxRows(3)
+
+	w, lines := streamMasterExport(t, handler)
+
+	assert.Len(t, lines, 1+3)
+	assert.Equal(t, "true", w.Header().Get("X-Export-Truncated"))
+	assert.Equal(t, "3", w.Header().Get("X-Export-Row-Limit"))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStreamLogs_NotTruncatedUnderRowCap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM audit_logs")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs") + ".*" + regexp.QuoteMeta("LIMIT $1")).
+		WithArgs(3).
+		WillReturnRows(streamAuditRows(2))
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+	handler.SetExportMaxRows(3)
+
+	w, lines := streamMasterExport(t, handler)
+
+	assert.Len(t, lines, 1+2)
+	assert.Empty(t, w.Header().Get("X-Export-Truncated"))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStreamLogs_PagesThroughChunks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs")).WillReturnRows(streamAuditRows(450))
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+
+	w, lines := streamMasterExport(t, handler)
+
+	// Chunks of 200 rows: 200, 200, then the last 50 and the end of the file
+	assert.Len(t, lines, 1+450)
+	assert.Equal(t, 3, w.flushes)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStreamLogs_ScopesCompanyAdminToOwnCompany(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	companyID := uuid.New()
+	otherCompanyID := uuid.New()
+	rows := sqlmock.NewRows(streamAuditColumns).
+		AddRow(uuid.New(), nil, nil, companyID, "vehicle_import", "vehicle", nil,
+			nil, nil, "127.0.0.1", "test-agent", []byte("null"), []byte(`{"created": 2}`),
+			true, nil, nil, nil, nil, nil, time.Now())
+
+	// company_id from the query string is overridden by the caller's company
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs") + ".*" + regexp.QuoteMeta("AND company_id = $1")).
+		WithArgs(companyID).
+		WillReturnRows(rows)
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+
+	w := &streamRecorder{httptest.NewRecorder()}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs/export?format=csv&company_id="+otherCompanyID.String(), nil)
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), CompanyID: &companyID, Role: "company_admin"})
+
+	handler.StreamLogs(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"{""created"":2}"`)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStreamLogs_RejectsUnknownFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewAuditHandler(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs/export?format=xml", nil)
+
+	handler.StreamLogs(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// cancelOnFlushRecorder cancels the request context the first time a chunk is flushed,
+// as if the client disconnected after receiving it
+type cancelOnFlushRecorder struct {
+	streamRecorder
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnFlushRecorder) Flush() {
+	r.streamRecorder.Flush()
+	r.cancel()
+}
+
+func TestStreamLogs_StopsWhenClientDisconnects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs")).WillReturnRows(streamAuditRows(1000))
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancelOnFlushRecorder{streamRecorder{httptest.NewRecorder()}, cancel}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs/export?format=csv", nil).WithContext(ctx)
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), Role: "master", IsMaster: true})
+
+	handler.StreamLogs(c)
+
+	// Only the chunk written before the disconnect went out: the header plus one chunk of rows
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 1+200)
+}
@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// newAuditLogsRouter mounts GetLogs behind the same role check as the audit routes,
+// authenticated as a user with role in companyID
+func newAuditLogsRouter(handler *handlers.AuditHandler, role string, companyID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/audit/logs",
+		func(c *gin.Context) {
+			c.Set("role_name", role)
+			c.Set("userContext", &models.UserContext{UserID: uuid.New(), CompanyID: &companyID, Role: role, IsMaster: role == "master"})
+		},
+		middleware.NewGinAuthMiddleware(nil).RequireAnyRole("admin", "company_admin"),
+		handler.GetLogs)
+	return router
+}
+
+func TestGetLogs_DriverForbidden(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+	router := newAuditLogsRouter(handler, "driver", uuid.New())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, `/api/v1/audit/logs?metadata={"session_id":"abc"}`, nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestGetLogs_CompanyAdminCannotReadOtherCompany(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	companyID, otherCompanyID := uuid.New(), uuid.New()
+
+	// company_id from the query string is overridden by the caller's company
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs")+".*"+regexp.QuoteMeta("AND company_id = $1")).
+		WithArgs(companyID, 50).
+		WillReturnRows(sqlmock.NewRows(streamAuditColumns))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM audit_logs") + ".*" + regexp.QuoteMeta("AND company_id = $1")).
+		WithArgs(companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	handler := handlers.NewAuditHandler(services.NewAuditService(sqlx.NewDb(mockDB, "sqlmock")))
+	router := newAuditLogsRouter(handler, "company_admin", companyID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs?company_id="+otherCompanyID.String(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,50 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+// Handlers reached without the context the auth middleware sets all answer the same 401
+func TestHandlers_MissingAuthContextIsStandardized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{"vehicles without company", handlers.NewVehicleHandler(nil, nil).GetVehicles},
+		{"teams without company", handlers.NewTeamHandler(nil, nil, nil).GetTeams},
+		{"users without user context", handlers.NewUserHandler(nil).GetUsers},
+		{"profile without user", handlers.NewAuthHandler(new(MockUserRepositoryForTeam), nil, nil, nil, nil, 10).MeGin},
+		{"sessions without user", handlers.NewSessionHandler(nil).GetActiveSessions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			tt.handler(c)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, utils.ErrCodeAuthContextMissing, body.Error.Code)
+		})
+	}
+}
@@ -42,6 +42,14 @@ func (m *MockAuthLogRepository) GetByUserID(userID uuid.UUID, limit int) ([]*mod
 	return args.Get(0).([]*models.AuthLog), args.Error(1)
 }
 
+func (m *MockAuthLogRepository) GetUserLoginCountries(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockAuthLogRepository) CountFailedLogins(ctx context.Context, userID *uuid.UUID, startTime time.Time, endTime time.Time) (int, error) {
 	args := m.Called(ctx, userID, startTime, endTime)
 	return args.Int(0), args.Error(1)
@@ -184,21 +192,39 @@ func (m *MockUserRepositoryForAuth) UpdatePassword(ctx context.Context, id uuid.
 	return args.Error(0)
 }
 
+func (m *MockUserRepositoryForAuth) UpgradePasswordHash(ctx context.Context, id uuid.UUID, currentHash, upgradedHash string) error {
+	args := m.Called(ctx, id, currentHash, upgradedHash)
+	return args.Error(0)
+}
+
 func (m *MockUserRepositoryForAuth) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockUserRepositoryForAuth) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID) ([]*models.User, error) {
-	args := m.Called(ctx, limit, offset, active, roleID)
+func (m *MockUserRepositoryForAuth) RequestErasure(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryForAuth) PurgeUser(ctx context.Context, id uuid.UUID) (*models.UserPurgeResult, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPurgeResult), args.Error(1)
+}
+
+func (m *MockUserRepositoryForAuth) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, limit, offset, active, roleID, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
-func (m *MockUserRepositoryForAuth) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]*models.User, error) {
-	args := m.Called(ctx, companyID, limit, offset)
+func (m *MockUserRepositoryForAuth) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, companyID, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -215,6 +241,30 @@ func (m *MockUserRepositoryForAuth) UpdateLoginAttempts(ctx context.Context, id
 	return args.Error(0)
 }
 
+func (m *MockUserRepositoryForAuth) IncrementLoginAttempts(ctx context.Context, id uuid.UUID, maxAttempts int, blockUntil time.Time) (*models.LoginAttempts, error) {
+	args := m.Called(ctx, id, maxAttempts, blockUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempts), args.Error(1)
+}
+
+func (m *MockUserRepositoryForAuth) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepositoryForAuth) GetLastActivity(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserActivity, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]models.UserActivity), args.Error(1)
+}
+
 func (m *MockUserRepositoryForAuth) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -228,8 +278,8 @@ func (m *MockUserRepositoryForAuth) GetUserContext(ctx context.Context, userID u
 	return args.Get(0).(*models.UserContext), args.Error(1)
 }
 
-func (m *MockUserRepositoryForAuth) Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int) ([]*models.User, error) {
-	args := m.Called(ctx, companyID, searchTerm, limit, offset)
+func (m *MockUserRepositoryForAuth) Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, companyID, searchTerm, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -246,8 +296,26 @@ func (m *MockUserRepositoryForAuth) CountActiveUsers(ctx context.Context, compan
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockUserRepositoryForAuth) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int) ([]*models.User, error) {
-	args := m.Called(ctx, companyID, roles, limit, offset)
+func (m *MockUserRepositoryForAuth) ExistsByCPF(ctx context.Context, companyID *uuid.UUID, cpf string, excludeUserID *uuid.UUID) (bool, error) {
+	args := m.Called(ctx, companyID, cpf, excludeUserID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryForAuth) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryForAuth) UpdatePreferences(ctx context.Context, id uuid.UUID, req models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPreferences), args.Error(1)
+}
+
+func (m *MockUserRepositoryForAuth) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, companyID, roles, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
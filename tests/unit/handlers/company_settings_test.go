@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// fakeCompanySettingsRepo keeps settings in memory
+type fakeCompanySettingsRepo map[uuid.UUID]*models.CompanySettingsRecord
+
+func (f fakeCompanySettingsRepo) Get(ctx context.Context, companyID uuid.UUID) (*models.CompanySettingsRecord, error) {
+	if record, ok := f[companyID]; ok {
+		return record, nil
+	}
+	return &models.CompanySettingsRecord{CompanyID: companyID, Settings: models.DefaultCompanySettings()}, nil
+}
+
+func (f fakeCompanySettingsRepo) Upsert(ctx context.Context, companyID uuid.UUID, settings models.CompanySettings, updatedBy *uuid.UUID) (*models.CompanySettingsRecord, error) {
+	f[companyID] = &models.CompanySettingsRecord{CompanyID: companyID, Settings: settings, UpdatedBy: updatedBy}
+	return f[companyID], nil
+}
+
+func companySettingsRequest(handle gin.HandlerFunc, companyID uuid.UUID, method, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/api/v1/company/settings", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	middleware.SetCompanyID(c, companyID)
+
+	handle(c)
+	return w
+}
+
+func TestGetCompanySettings_ReturnsDefaults(t *testing.T) {
+	handler := handlers.NewCompanySettingsHandler(fakeCompanySettingsRepo{})
+
+	w := companySettingsRequest(handler.GetCompanySettings, uuid.New(), http.MethodGet, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data models.CompanySettingsRecord `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.DefaultCompanySettings(), body.Data.Settings)
+}
+
+func TestUpdateCompanySettings_OmittedSettingsGetDefaults(t *testing.T) {
+	repo := fakeCompanySettingsRepo{}
+	handler := handlers.NewCompanySettingsHandler(repo)
+	companyID := uuid.New()
+
+	w := companySettingsRequest(handler.UpdateCompanySettings, companyID, http.MethodPut, `{"password_max_age_days": 60}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	stored := repo[companyID].Settings
+	require.NotNil(t, stored.PasswordMaxAgeDays)
+	assert.Equal(t, 60, *stored.PasswordMaxAgeDays)
+	assert.Equal(t, models.DefaultMaxSessionsPerUser, stored.MaxSessionsPerUser)
+	assert.True(t, stored.EnforceIPAllowlist)
+}
+
+func TestUpdateCompanySettings_RejectsInvalidValues(t *testing.T) {
+	repo := fakeCompanySettingsRepo{}
+	handler := handlers.NewCompanySettingsHandler(repo)
+	companyID := uuid.New()
+
+	w := companySettingsRequest(handler.UpdateCompanySettings, companyID, http.MethodPut, `{"max_sessions_per_user": 0}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "max_sessions_per_user")
+	assert.NotContains(t, repo, companyID)
+}
@@ -0,0 +1,128 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func newCompanyHandlerWithMockDB(t *testing.T) (*handlers.CompanyHandler, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	handler := handlers.NewCompanyHandler(repository.NewCompanyRepository(db))
+	handler.SetUserRepository(repository.NewUserRepository(db))
+	return handler, mock
+}
+
+func companyRow(id uuid.UUID, status string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "name", "slug", "email", "status"}).
+		AddRow(id, "Fleet Co", "fleet-co", "contact@fleet.co", status)
+}
+
+func dependentsRow(users, vehicles, teams int) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"active_users", "active_vehicles", "active_teams"}).AddRow(users, vehicles, teams)
+}
+
+func expectCompanySoftDelete(mock sqlmock.Sqlmock, companyID uuid.UUID) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM companies WHERE id = $1 FOR UPDATE")).
+		WithArgs(companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(companyID))
+	mock.ExpectQuery(regexp.QuoteMeta("AS active_users")).
+		WithArgs(companyID).
+		WillReturnRows(dependentsRow(0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE companies SET status = 'inactive'")).
+		WithArgs(companyID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func deleteCompanyRequest(companyID uuid.UUID, query string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), Role: "master", IsMaster: true})
+	c.Params = gin.Params{{Key: "id", Value: companyID.String()}}
+	c.Request = httptest.NewRequest("DELETE", "/api/v1/master/companies/"+companyID.String()+query, nil)
+	return c, w
+}
+
+func TestDeleteCompany_ReassignsUsersToTargetCompany(t *testing.T) {
+	handler, mock := newCompanyHandlerWithMockDB(t)
+	companyID := uuid.New()
+	targetID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM companies")).WithArgs(companyID).WillReturnRows(companyRow(companyID, "active"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM companies")).WithArgs(targetID).WillReturnRows(companyRow(targetID, "active"))
+	mock.ExpectQuery(regexp.QuoteMeta("AS active_users")).WithArgs(companyID).WillReturnRows(dependentsRow(3, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SET company_id = $1")).
+		WithArgs(targetID, sqlmock.AnyArg(), companyID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	expectCompanySoftDelete(mock, companyID)
+
+	c, w := deleteCompanyRequest(companyID, "?users=reassign&target_company_id="+targetID.String())
+	handler.DeleteCompany(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCompany_DeactivatesUsers(t *testing.T) {
+	handler, mock := newCompanyHandlerWithMockDB(t)
+	companyID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM companies")).WithArgs(companyID).WillReturnRows(companyRow(companyID, "active"))
+	mock.ExpectQuery(regexp.QuoteMeta("AS active_users")).WithArgs(companyID).WillReturnRows(dependentsRow(2, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SET active = false")).
+		WithArgs(sqlmock.AnyArg(), companyID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectCompanySoftDelete(mock, companyID)
+
+	c, w := deleteCompanyRequest(companyID, "?users=deactivate")
+	handler.DeleteCompany(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCompany_UsersUntouchedWhenVehiclesRemain(t *testing.T) {
+	handler, mock := newCompanyHandlerWithMockDB(t)
+	companyID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM companies")).WithArgs(companyID).WillReturnRows(companyRow(companyID, "active"))
+	mock.ExpectQuery(regexp.QuoteMeta("AS active_users")).WithArgs(companyID).WillReturnRows(dependentsRow(2, 1, 0))
+
+	c, w := deleteCompanyRequest(companyID, "?users=deactivate")
+	handler.DeleteCompany(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCompany_ReassignRequiresTargetCompany(t *testing.T) {
+	handler, mock := newCompanyHandlerWithMockDB(t)
+	companyID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM companies")).WithArgs(companyID).WillReturnRows(companyRow(companyID, "active"))
+
+	c, w := deleteCompanyRequest(companyID, "?users=reassign")
+	handler.DeleteCompany(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,122 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestDatabasePoolStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(25)
+
+	handler := handlers.NewDiagnosticsHandler(db)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/master/diagnostics/database", nil)
+
+	handler.DatabasePoolStats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stats map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, float64(25), stats["max_open_connections"])
+	for _, key := range []string{"open_connections", "in_use", "idle", "wait_count", "wait_duration_ms"} {
+		assert.Contains(t, stats, key)
+	}
+}
+
+// recordingTestEmailSender records test emails instead of sending them
+type recordingTestEmailSender struct {
+	to          []string
+	requestedBy []string
+	err         error
+}
+
+func (s *recordingTestEmailSender) SendTestEmail(email, requestedBy string) error {
+	s.to = append(s.to, email)
+	s.requestedBy = append(s.requestedBy, requestedBy)
+	return s.err
+}
+
+func sendTestEmail(handler *handlers.DiagnosticsHandler, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/master/test-email", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("email", "master@dashtrack.com")
+
+	handler.SendTestEmail(c)
+	return w
+}
+
+func TestSendTestEmail_DispatchesThroughSender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sender := &recordingTestEmailSender{}
+	handler := handlers.NewDiagnosticsHandler(nil)
+	handler.SetTestEmailSender(sender)
+
+	w := sendTestEmail(handler, `{"to": "ops@fleet.com"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"ops@fleet.com"}, sender.to)
+	assert.Equal(t, []string{"master@dashtrack.com"}, sender.requestedBy)
+
+	var result models.TestEmailResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.Sent)
+	assert.Equal(t, "ops@fleet.com", result.To)
+	assert.Empty(t, result.Error)
+}
+
+func TestSendTestEmail_ReportsSendFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sender := &recordingTestEmailSender{err: errors.New("535 authentication failed")}
+	handler := handlers.NewDiagnosticsHandler(nil)
+	handler.SetTestEmailSender(sender)
+
+	w := sendTestEmail(handler, `{"to": "ops@fleet.com"}`)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	var result models.TestEmailResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.False(t, result.Sent)
+	assert.Equal(t, "535 authentication failed", result.Error)
+}
+
+func TestSendTestEmail_RequiresValidAddress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sender := &recordingTestEmailSender{}
+	handler := handlers.NewDiagnosticsHandler(nil)
+	handler.SetTestEmailSender(sender)
+
+	w := sendTestEmail(handler, `{"to": "not-an-email"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, sender.to)
+}
+
+func TestSendTestEmail_DisabledSenderUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewDiagnosticsHandler(nil)
+	handler.SetTestEmailSender(services.NewNoopEmailService())
+
+	w := sendTestEmail(handler, `{"to": "ops@fleet.com"}`)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
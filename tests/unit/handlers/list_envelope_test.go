@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// listEnvelope is the body shape shared by list endpoints
+type listEnvelope struct {
+	Success    bool              `json:"success"`
+	Data       []json.RawMessage `json:"data"`
+	Pagination map[string]any    `json:"pagination"`
+	Meta       map[string]any    `json:"meta"`
+}
+
+func decodeListEnvelope(t *testing.T, w *httptest.ResponseRecorder) listEnvelope {
+	t.Helper()
+	var body listEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+func TestGetTeams_ReturnsListEnvelope(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	handler := handlers.NewTeamHandler(mockTeamRepo, new(MockUserRepositoryForTeam), new(MockVehicleRepository))
+
+	teams := []models.Team{{ID: uuid.New(), Name: "Team 1"}, {ID: uuid.New(), Name: "Team 2"}}
+	mockTeamRepo.On("GetByCompany", mock.Anything, mock.Anything, 2, 0, false).Return(teams, nil)
+	mockTeamRepo.On("CountByCompany", mock.Anything, mock.Anything, false).Return(5, nil)
+
+	c, w := setupTeamTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/teams?limit=2", nil)
+
+	handler.GetTeams(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := decodeListEnvelope(t, w)
+	assert.True(t, body.Success)
+	assert.Len(t, body.Data, 2)
+	assert.Equal(t, map[string]any{"total": float64(5), "limit": float64(2), "offset": float64(0), "has_more": true}, body.Pagination)
+	assert.Nil(t, body.Meta)
+	mockTeamRepo.AssertExpectations(t)
+}
+
+func TestGetVehicles_ReturnsListEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	companyID := uuid.New()
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+		WithArgs(companyID, "active", 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "license_plate"}).AddRow(uuid.New(), "ABC1D23"))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM vehicles")).
+		WithArgs(companyID, "active").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(db), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/company-admin/vehicles?status=active&offset=10", nil)
+	middleware.SetCompanyID(c, companyID)
+
+	handler.GetVehicles(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := decodeListEnvelope(t, w)
+	assert.Len(t, body.Data, 1)
+	assert.Equal(t, float64(11), body.Pagination["total"])
+	assert.Equal(t, float64(10), body.Pagination["limit"])
+	assert.Equal(t, float64(10), body.Pagination["offset"])
+	assert.Equal(t, false, body.Pagination["has_more"])
+	assert.Equal(t, "active", body.Meta["filters"].(map[string]any)["status"])
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestGetUsers_ReturnsListEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userRepo := new(MockUserRepositoryForTeam)
+	users := []*models.User{{ID: uuid.New(), Name: "User 1"}, {ID: uuid.New(), Name: "User 2"}}
+	userRepo.On("List", mock.Anything, 2, 4, (*bool)(nil), (*uuid.UUID)(nil), mock.Anything).Return(users, nil)
+	userRepo.On("CountUsers", mock.Anything, (*uuid.UUID)(nil)).Return(7, nil)
+	handler := handlers.NewUserHandler(services.NewUserService(userRepo, nil, 4))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users?limit=2&offset=4", nil)
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), Role: "master", IsMaster: true})
+
+	handler.GetUsers(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := decodeListEnvelope(t, w)
+	assert.Len(t, body.Data, 2)
+	assert.Equal(t, map[string]any{"total": float64(7), "limit": float64(2), "offset": float64(4), "has_more": true}, body.Pagination)
+	userRepo.AssertExpectations(t)
+}
@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// atomicLoginAttemptsRepo counts failed logins under a lock, like the database does
+type atomicLoginAttemptsRepo struct {
+	*MockUserRepositoryForTeam
+	mu           sync.Mutex
+	attempts     int
+	blockedUntil *time.Time
+}
+
+func (r *atomicLoginAttemptsRepo) IncrementLoginAttempts(ctx context.Context, id uuid.UUID, maxAttempts int, blockUntil time.Time) (*models.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts++
+	result := &models.LoginAttempts{Attempts: r.attempts}
+	if r.attempts >= maxAttempts && (r.blockedUntil == nil || !r.blockedUntil.After(time.Now())) {
+		r.blockedUntil = &blockUntil
+		result.NewlyBlocked = true
+	}
+	result.BlockedUntil = r.blockedUntil
+	return result, nil
+}
+
+// blockedAccountMailer counts blocked account emails
+type blockedAccountMailer struct {
+	*services.NoopEmailService
+	mu   sync.Mutex
+	sent int
+}
+
+func (m *blockedAccountMailer) SendEmail(data services.EmailData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if data.Type == services.EmailTypeBlockedAccount {
+		m.sent++
+	}
+	return nil
+}
+
+func (m *blockedAccountMailer) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sent
+}
+
+func TestLogin_ParallelWrongPasswordsAreAllCounted(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &models.User{ID: uuid.New(), Email: "driver@fleet.com", Password: string(hash), Active: true, Role: &models.Role{Name: "driver"}}
+
+	// Every request reads the user before any failure is recorded
+	userRepo := &atomicLoginAttemptsRepo{MockUserRepositoryForTeam: new(MockUserRepositoryForTeam)}
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	mailer := &blockedAccountMailer{NoopEmailService: services.NewNoopEmailService()}
+	tokenService := services.NewTokenService(sqlx.NewDb(mockDB, "sqlmock"), "test-secret", 15*time.Minute, 24*time.Hour)
+	handler := handlers.NewAuthHandler(userRepo, repository.NewAuthLogRepository(mockDB), nil, tokenService, mailer, bcrypt.MinCost)
+
+	gin.SetMode(gin.TestMode)
+	const requests = 10
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login",
+				strings.NewReader(`{"email": "driver@fleet.com", "password": "wrong-password"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			handler.LoginGin(c)
+			codes <- w.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	statuses := map[int]int{}
+	for code := range codes {
+		statuses[code]++
+	}
+
+	assert.Equal(t, requests, userRepo.attempts)
+	assert.Equal(t, map[int]int{http.StatusUnauthorized: 2, http.StatusForbidden: requests - 2}, statuses)
+	assert.Eventually(t, func() bool { return mailer.count() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool { return mailer.count() > 1 }, 100*time.Millisecond, 10*time.Millisecond)
+}
@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeIPAllowlists serves the allowlist of each company
+type fakeIPAllowlists map[uuid.UUID][]models.IPAllowlistEntry
+
+func (f fakeIPAllowlists) GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.IPAllowlistEntry, error) {
+	return f[companyID], nil
+}
+
+// newAllowlistLoginHandler returns a handler whose company only allows logins from 10.0.0.0/8
+func newAllowlistLoginHandler(t *testing.T, role string) (*handlers.AuthHandler, sqlmock.Sqlmock) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	companyID := uuid.New()
+	user := &models.User{
+		ID:        uuid.New(),
+		Email:     "driver@fleet.com",
+		Password:  string(hash),
+		Active:    true,
+		CompanyID: &companyID,
+		Role:      &models.Role{Name: role},
+	}
+
+	userRepo := new(MockUserRepositoryForTeam)
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("UpdateLastLogin", mock.Anything, user.ID).Return(nil).Maybe()
+
+	tokenService := services.NewTokenService(db, "test-secret", 15*time.Minute, 24*time.Hour)
+	handler := handlers.NewAuthHandler(userRepo, repository.NewAuthLogRepository(mockDB), nil, tokenService, nil, bcrypt.MinCost)
+	handler.SetAuditLogRepository(repository.NewAuditLogRepository(db))
+	handler.SetIPAllowlist(services.NewIPAllowlist(fakeIPAllowlists{
+		companyID: {{ID: uuid.New(), CompanyID: companyID, CIDR: "10.0.0.0/8"}},
+	}))
+	return handler, sqlMock
+}
+
+func loginFrom(handler *handlers.AuthHandler, ip string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login",
+		strings.NewReader(`{"email": "driver@fleet.com", "password": "secret123"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.RemoteAddr = ip + ":40000"
+
+	handler.LoginGin(c)
+	return w
+}
+
+func TestLogin_RejectedOutsideCompanyIPAllowlist(t *testing.T) {
+	handler, sqlMock := newAllowlistLoginHandler(t, "driver")
+	sqlMock.ExpectQuery(regexp.QuoteMeta("INSERT INTO auth_logs")).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_logs")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	w := loginFrom(handler, "203.0.113.9")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, handlers.ErrCodeIPNotAllowed, response["code"])
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestLogin_MasterBypassesCompanyIPAllowlist(t *testing.T) {
+	handler, _ := newAllowlistLoginHandler(t, "master")
+
+	// Token generation has no database here; reaching it means the allowlist let the login through
+	w := loginFrom(handler, "203.0.113.9")
+
+	assert.NotEqual(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), handlers.ErrCodeIPNotAllowed)
+}
@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// newRehashLoginHandler returns a handler configured for bcrypt cost 5 and a user whose
+// "secret123" password is stored at storedCost
+func newRehashLoginHandler(t *testing.T, storedCost int) (*handlers.AuthHandler, *MockUserRepositoryForTeam, *models.User) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), storedCost)
+	require.NoError(t, err)
+	user := &models.User{
+		ID:       uuid.New(),
+		Email:    "driver@fleet.com",
+		Password: string(hash),
+		Active:   true,
+		Role:     &models.Role{Name: "driver"},
+	}
+
+	userRepo := new(MockUserRepositoryForTeam)
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("UpdateLastLogin", mock.Anything, user.ID).Return(nil).Maybe()
+
+	tokenService := services.NewTokenService(db, "test-secret", 15*time.Minute, 24*time.Hour)
+	handler := handlers.NewAuthHandler(userRepo, repository.NewAuthLogRepository(mockDB), nil, tokenService, nil, bcrypt.MinCost+1)
+	return handler, userRepo, user
+}
+
+func TestLogin_UpgradesOutdatedPasswordHash(t *testing.T) {
+	handler, userRepo, user := newRehashLoginHandler(t, bcrypt.MinCost)
+
+	upgraded := make(chan string, 1)
+	userRepo.On("UpgradePasswordHash", mock.Anything, user.ID, user.Password, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { upgraded <- args.String(3) }).
+		Return(nil)
+
+	postJSON(handler.LoginGin, "/api/v1/auth/login", `{"email": "driver@fleet.com", "password": "secret123"}`)
+
+	select {
+	case hash := <-upgraded:
+		cost, err := bcrypt.Cost([]byte(hash))
+		require.NoError(t, err)
+		assert.Equal(t, bcrypt.MinCost+1, cost)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("secret123")))
+	case <-time.After(5 * time.Second):
+		t.Fatal("password hash was not upgraded")
+	}
+}
+
+func TestLogin_KeepsCurrentPasswordHash(t *testing.T) {
+	handler, userRepo, _ := newRehashLoginHandler(t, bcrypt.MinCost+1)
+
+	postJSON(handler.LoginGin, "/api/v1/auth/login", `{"email": "driver@fleet.com", "password": "secret123"}`)
+
+	userRepo.AssertNotCalled(t, "UpgradePasswordHash", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLogin_WrongPasswordDoesNotUpgradeHash(t *testing.T) {
+	handler, userRepo, user := newRehashLoginHandler(t, bcrypt.MinCost)
+	userRepo.On("IncrementLoginAttempts", mock.Anything, user.ID, 3, mock.Anything).Return(&models.LoginAttempts{Attempts: 1}, nil)
+
+	w := postJSON(handler.LoginGin, "/api/v1/auth/login", `{"email": "driver@fleet.com", "password": "wrong-password"}`)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	userRepo.AssertNotCalled(t, "UpgradePasswordHash", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func newLogoutAllHandler(t *testing.T) (*handlers.AuthHandler, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	tokenService := services.NewTokenService(db, "test-secret", 15*time.Minute, 24*time.Hour)
+	handler := handlers.NewAuthHandler(nil, nil, nil, tokenService, nil, 10)
+	handler.SetAuditLogRepository(repository.NewAuditLogRepository(db))
+	return handler, mock
+}
+
+func logoutAllRequest(userID uuid.UUID) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", userID.String())
+	c.Set("email", "driver@fleet.com")
+	c.Request = httptest.NewRequest("POST", "/api/v1/profile/logout-all", nil)
+	return c, w
+}
+
+func TestLogoutAll_RevokesEverySession(t *testing.T) {
+	handler, mock := newLogoutAllHandler(t)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE session_tokens SET revoked = true")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE user_sessions SET active = false WHERE user_id = $1")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_logs")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	c, w := logoutAllRequest(userID)
+	handler.LogoutAllGin(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(3), response["revoked_sessions"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLogoutAll_RollsBackWhenUserSessionsFail(t *testing.T) {
+	handler, mock := newLogoutAllHandler(t)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE session_tokens SET revoked = true")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE user_sessions SET active = false")).
+		WithArgs(userID).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	c, w := logoutAllRequest(userID)
+	handler.LogoutAllGin(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
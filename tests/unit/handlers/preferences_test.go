@@ -0,0 +1,64 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+func preferencesRequest(userID uuid.UUID, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", userID.String())
+	c.Request = httptest.NewRequest("PATCH", "/api/v1/profile/preferences", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestUpdatePreferences_DisablesNewSessionEmails(t *testing.T) {
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewAuthHandler(mockUserRepo, nil, nil, nil, nil, 10)
+	userID := uuid.New()
+
+	disabled := false
+	mockUserRepo.On("UpdatePreferences", mock.Anything, userID, models.UpdatePreferencesRequest{NotifyNewSession: &disabled}).
+		Return(&models.UserPreferences{NotifyNewSession: false}, nil)
+
+	c, w := preferencesRequest(userID, `{"notify_new_session": false}`)
+	handler.UpdatePreferencesGin(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	prefs := response["preferences"].(map[string]interface{})
+	assert.Equal(t, false, prefs["notify_new_session"])
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_EmptyBodyKeepsCurrentValues(t *testing.T) {
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewAuthHandler(mockUserRepo, nil, nil, nil, nil, 10)
+	userID := uuid.New()
+
+	mockUserRepo.On("UpdatePreferences", mock.Anything, userID, models.UpdatePreferencesRequest{}).
+		Return(&models.UserPreferences{NotifyNewSession: true}, nil)
+
+	c, w := preferencesRequest(userID, `{}`)
+	handler.UpdatePreferencesGin(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUserRepo.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func avatarRequest(t *testing.T, userID uuid.UUID, filename string, content []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("avatar", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", userID.String())
+	c.Request = httptest.NewRequest("POST", "/api/v1/profile/avatar", &body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	return c, w
+}
+
+func TestUploadAvatar_StoresImage(t *testing.T) {
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewAuthHandler(mockUserRepo, nil, nil, nil, nil, 10)
+	handler.SetAvatarService(services.NewAvatarService(services.NewLocalFileStorage(t.TempDir(), "/uploads"), mockUserRepo, 1<<20, 128))
+	userID := uuid.New()
+
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 32, 32))))
+
+	mockUserRepo.On("GetByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+	mockUserRepo.On("Update", mock.Anything, userID, mock.AnythingOfType("models.UpdateUserRequest")).
+		Return(&models.User{ID: userID}, nil)
+
+	c, w := avatarRequest(t, userID, "me.png", img.Bytes())
+	handler.UploadAvatarGin(c)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Regexp(t, `^/uploads/avatars/`+userID.String()+`/[0-9a-f]+\.png$`, response["avatar"])
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUploadAvatar_RejectsNonImage(t *testing.T) {
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewAuthHandler(mockUserRepo, nil, nil, nil, nil, 10)
+	handler.SetAvatarService(services.NewAvatarService(services.NewLocalFileStorage(t.TempDir(), "/uploads"), mockUserRepo, 1<<20, 128))
+
+	// The file name and part headers claim an image; the content decides
+	c, w := avatarRequest(t, uuid.New(), "me.png", []byte("%PDF-1.7 not an image"))
+	handler.UploadAvatarGin(c)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadAvatar_RejectsOversizedFile(t *testing.T) {
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewAuthHandler(mockUserRepo, nil, nil, nil, nil, 10)
+	handler.SetAvatarService(services.NewAvatarService(services.NewLocalFileStorage(t.TempDir(), "/uploads"), mockUserRepo, 1024, 128))
+
+	c, w := avatarRequest(t, uuid.New(), "big.png", bytes.Repeat([]byte{0}, 128<<10))
+	handler.UploadAvatarGin(c)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestUploadAvatar_MissingFile(t *testing.T) {
+	handler := handlers.NewAuthHandler(new(MockUserRepositoryForTeam), nil, nil, nil, nil, 10)
+	handler.SetAvatarService(services.NewAvatarService(services.NewLocalFileStorage(t.TempDir(), "/uploads"), nil, 1024, 128))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", uuid.New().String())
+	c.Request = httptest.NewRequest("POST", "/api/v1/profile/avatar", nil)
+	handler.UploadAvatarGin(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,99 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestExportProfile_ContainsAllSections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	userID := uuid.New()
+	now := time.Now()
+
+	userRepo := new(MockUserRepositoryForTeam)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&models.User{
+		ID:        userID,
+		Name:      "Driver",
+		Email:     "driver@fleet.com",
+		Active:    true,
+		Role:      &models.Role{Name: "driver"},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil)
+
+	tokenService := services.NewTokenService(db, "test-secret", 15*time.Minute, 24*time.Hour)
+	handler := handlers.NewAuthHandler(userRepo, nil, nil, tokenService, nil, 10)
+	handler.SetProfileExportRepository(repository.NewProfileExportRepository(db))
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM team_members tm")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "team_name", "role_in_team", "joined_at"}).
+			AddRow(uuid.New(), "North Route", "driver", now))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"vehicle_id", "license_plate", "brand", "model", "year", "assignment"}).
+			AddRow(uuid.New(), "ABC1D23", "Volvo", "FH", 2022, "driver"))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM auth_logs")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "success", "ip_address", "user_agent", "failure_reason", "country_code", "created_at"}).
+			AddRow(uuid.New(), true, "203.0.113.7", "curl", nil, "BR", now).
+			AddRow(uuid.New(), false, "203.0.113.7", "curl", "invalid_password", nil, now))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "action", "resource", "resource_id", "ip_address", "user_agent", "success", "metadata", "created_at"}).
+			AddRow(uuid.New(), "password_change", "user", userID.String(), "203.0.113.7", "curl", true, []byte(`{"change_method":"manual"}`), now))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", userID.String())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/profile/export", nil)
+
+	handler.ExportProfileGin(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+	var bundle map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle), w.Body.String())
+	for _, section := range []string{"exported_at", "profile", "team_memberships", "assigned_vehicles", "auth_history", "audit_entries"} {
+		assert.Contains(t, bundle, section)
+	}
+
+	var profile handlers.UserResponse
+	require.NoError(t, json.Unmarshal(bundle["profile"], &profile))
+	assert.Equal(t, "driver@fleet.com", profile.Email)
+
+	var authHistory []map[string]interface{}
+	require.NoError(t, json.Unmarshal(bundle["auth_history"], &authHistory))
+	assert.Len(t, authHistory, 2)
+
+	var auditEntries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(bundle["audit_entries"], &auditEntries))
+	require.Len(t, auditEntries, 1)
+	assert.Equal(t, map[string]interface{}{"change_method": "manual"}, auditEntries[0]["metadata"])
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
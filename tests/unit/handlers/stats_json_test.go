@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// decodeStatsData decodes the data object keeping numbers as their literal JSON text
+func decodeStatsData(t *testing.T, w *httptest.ResponseRecorder) map[string]json.Number {
+	t.Helper()
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	numbers := make(map[string]json.Number)
+	for key, raw := range body.Data {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var value interface{}
+		require.NoError(t, dec.Decode(&value))
+		if n, ok := value.(json.Number); ok {
+			numbers[key] = n
+		}
+	}
+	return numbers
+}
+
+func TestGetTeamStats_CountsSerializeAsIntegers(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockVehicleRepo := new(MockVehicleRepository)
+	handler := handlers.NewTeamHandler(mockTeamRepo, new(MockUserRepositoryForTeam), mockVehicleRepo)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(&models.Team{ID: teamID, CompanyID: companyID, Name: "Team"}, nil)
+	mockTeamRepo.On("GetMembers", mock.Anything, teamID).Return([]models.TeamMember{{ID: uuid.New()}, {ID: uuid.New()}}, nil)
+	mockVehicleRepo.On("GetByTeam", mock.Anything, teamID, companyID).Return([]models.Vehicle{{ID: uuid.New(), Status: "active"}}, nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/teams/"+teamID.String()+"/stats", nil)
+
+	handler.GetTeamStats(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"member_count":2,`)
+
+	numbers := decodeStatsData(t, w)
+	assert.Equal(t, json.Number("2"), numbers["member_count"])
+	assert.Equal(t, json.Number("1"), numbers["vehicle_count"])
+	assert.Equal(t, json.Number("1"), numbers["active_vehicles"])
+}
+
+func TestGetVehicleStats_CountsSerializeAsIntegers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+			AddRow(uuid.New(), "active").
+			AddRow(uuid.New(), "active").
+			AddRow(uuid.New(), "maintenance"))
+
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(db), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/stats", nil)
+	middleware.SetCompanyID(c, uuid.New())
+
+	handler.GetVehicleStats(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	numbers := decodeStatsData(t, w)
+	assert.Equal(t, map[string]json.Number{
+		"total_vehicles": "3",
+		"active":         "2",
+		"inactive":       "0",
+		"maintenance":    "1",
+	}, numbers)
+}
@@ -4,19 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
 	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
 )
 
 // ============================================================================
@@ -40,14 +48,24 @@ func (m *MockTeamRepository) GetByID(ctx context.Context, id uuid.UUID, companyI
 	return args.Get(0).(*models.Team), args.Error(1)
 }
 
-func (m *MockTeamRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]models.Team, error) {
-	args := m.Called(ctx, companyID, limit, offset)
+func (m *MockTeamRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, includeArchived bool) ([]models.Team, error) {
+	args := m.Called(ctx, companyID, limit, offset, includeArchived)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Team), args.Error(1)
 }
 
+func (m *MockTeamRepository) CountByCompany(ctx context.Context, companyID uuid.UUID, includeArchived bool) (int, error) {
+	args := m.Called(ctx, companyID, includeArchived)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) SetStatus(ctx context.Context, id uuid.UUID, companyID uuid.UUID, status string) error {
+	args := m.Called(ctx, id, companyID, status)
+	return args.Error(0)
+}
+
 func (m *MockTeamRepository) Update(ctx context.Context, team *models.Team) error {
 	args := m.Called(ctx, team)
 	return args.Error(0)
@@ -63,6 +81,14 @@ func (m *MockTeamRepository) AddMember(ctx context.Context, member *models.TeamM
 	return args.Error(0)
 }
 
+func (m *MockTeamRepository) AddMembers(ctx context.Context, companyID uuid.UUID, members []*models.TeamMember) ([]bool, error) {
+	args := m.Called(ctx, companyID, members)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]bool), args.Error(1)
+}
+
 func (m *MockTeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
 	args := m.Called(ctx, teamID, userID)
 	return args.Error(0)
@@ -81,12 +107,12 @@ func (m *MockTeamRepository) UpdateMemberRole(ctx context.Context, teamID, userI
 	return args.Error(0)
 }
 
-func (m *MockTeamRepository) GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]models.Team, error) {
+func (m *MockTeamRepository) GetTeamsByUser(ctx context.Context, userID uuid.UUID) ([]models.UserTeam, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Team), args.Error(1)
+	return args.Get(0).([]models.UserTeam), args.Error(1)
 }
 
 func (m *MockTeamRepository) CheckMemberExists(ctx context.Context, teamID, userID uuid.UUID) (bool, error) {
@@ -131,6 +157,22 @@ func (m *MockTeamRepository) GetUserTeamHistoryWithDetails(ctx context.Context,
 	return args.Get(0).([]models.TeamMemberHistory), args.Error(1)
 }
 
+func (m *MockTeamRepository) GetTeamsManagedBy(ctx context.Context, managerID uuid.UUID) ([]models.Team, error) {
+	args := m.Called(ctx, managerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetMembershipGrowth(ctx context.Context, companyID uuid.UUID, from, to time.Time, bucket string) ([]models.TeamGrowthBucket, error) {
+	args := m.Called(ctx, companyID, from, to, bucket)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TeamGrowthBucket), args.Error(1)
+}
+
 type MockVehicleRepository struct {
 	mock.Mock
 }
@@ -169,8 +211,13 @@ func (m *MockVehicleRepository) GetByLicensePlate(ctx context.Context, licensePl
 	return args.Get(0).(*models.Vehicle), args.Error(1)
 }
 
-func (m *MockVehicleRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]models.Vehicle, error) {
-	args := m.Called(ctx, companyID, limit, offset)
+func (m *MockVehicleRepository) CreateBatch(ctx context.Context, vehicles []*models.Vehicle) error {
+	args := m.Called(ctx, vehicles)
+	return args.Error(0)
+}
+
+func (m *MockVehicleRepository) GetByCompany(ctx context.Context, companyID uuid.UUID, filter models.VehicleFilter, limit, offset int, sort repository.Sort) ([]models.Vehicle, error) {
+	args := m.Called(ctx, companyID, filter, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -185,6 +232,11 @@ func (m *MockVehicleRepository) GetByDriver(ctx context.Context, driverID uuid.U
 	return args.Get(0).([]models.Vehicle), args.Error(1)
 }
 
+func (m *MockVehicleRepository) CountByCompany(ctx context.Context, companyID uuid.UUID, filter models.VehicleFilter) (int, error) {
+	args := m.Called(ctx, companyID, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockVehicleRepository) Update(ctx context.Context, vehicle *models.Vehicle) error {
 	args := m.Called(ctx, vehicle)
 	return args.Error(0)
@@ -211,6 +263,14 @@ func (m *MockVehicleRepository) GetActiveTrip(ctx context.Context, vehicleID uui
 	return args.Get(0).(*models.VehicleTrip), args.Error(1)
 }
 
+func (m *MockVehicleRepository) GetActiveTripsByCompany(ctx context.Context, companyID uuid.UUID) ([]models.ActiveTrip, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ActiveTrip), args.Error(1)
+}
+
 func (m *MockVehicleRepository) Search(ctx context.Context, companyID uuid.UUID, searchTerm string, limit, offset int) ([]models.Vehicle, error) {
 	args := m.Called(ctx, companyID, searchTerm, limit, offset)
 	if args.Get(0) == nil {
@@ -270,8 +330,8 @@ func (m *MockUserRepositoryForTeam) GetByEmail(ctx context.Context, email string
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepositoryForTeam) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int) ([]*models.User, error) {
-	args := m.Called(ctx, companyID, limit, offset)
+func (m *MockUserRepositoryForTeam) GetByCompany(ctx context.Context, companyID uuid.UUID, limit, offset int, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, companyID, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -291,6 +351,11 @@ func (m *MockUserRepositoryForTeam) UpdatePassword(ctx context.Context, id uuid.
 	return args.Error(0)
 }
 
+func (m *MockUserRepositoryForTeam) UpgradePasswordHash(ctx context.Context, id uuid.UUID, currentHash, upgradedHash string) error {
+	args := m.Called(ctx, id, currentHash, upgradedHash)
+	return args.Error(0)
+}
+
 func (m *MockUserRepositoryForTeam) UpdateCompany(ctx context.Context, userID, companyID uuid.UUID) error {
 	args := m.Called(ctx, userID, companyID)
 	return args.Error(0)
@@ -301,16 +366,29 @@ func (m *MockUserRepositoryForTeam) Delete(ctx context.Context, id uuid.UUID) er
 	return args.Error(0)
 }
 
-func (m *MockUserRepositoryForTeam) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID) ([]*models.User, error) {
-	args := m.Called(ctx, limit, offset, active, roleID)
+func (m *MockUserRepositoryForTeam) RequestErasure(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryForTeam) PurgeUser(ctx context.Context, id uuid.UUID) (*models.UserPurgeResult, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPurgeResult), args.Error(1)
+}
+
+func (m *MockUserRepositoryForTeam) List(ctx context.Context, limit, offset int, active *bool, roleID *uuid.UUID, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, limit, offset, active, roleID, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
-func (m *MockUserRepositoryForTeam) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int) ([]*models.User, error) {
-	args := m.Called(ctx, companyID, roles, limit, offset)
+func (m *MockUserRepositoryForTeam) ListByCompanyAndRoles(ctx context.Context, companyID *uuid.UUID, roles []string, limit, offset int, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, companyID, roles, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -335,6 +413,30 @@ func (m *MockUserRepositoryForTeam) UpdateLoginAttempts(ctx context.Context, id
 	return args.Error(0)
 }
 
+func (m *MockUserRepositoryForTeam) IncrementLoginAttempts(ctx context.Context, id uuid.UUID, maxAttempts int, blockUntil time.Time) (*models.LoginAttempts, error) {
+	args := m.Called(ctx, id, maxAttempts, blockUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempts), args.Error(1)
+}
+
+func (m *MockUserRepositoryForTeam) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepositoryForTeam) GetLastActivity(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserActivity, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]models.UserActivity), args.Error(1)
+}
+
 func (m *MockUserRepositoryForTeam) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -348,8 +450,8 @@ func (m *MockUserRepositoryForTeam) GetUserContext(ctx context.Context, userID u
 	return args.Get(0).(*models.UserContext), args.Error(1)
 }
 
-func (m *MockUserRepositoryForTeam) Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int) ([]*models.User, error) {
-	args := m.Called(ctx, companyID, searchTerm, limit, offset)
+func (m *MockUserRepositoryForTeam) Search(ctx context.Context, companyID *uuid.UUID, searchTerm string, limit, offset int, sort repository.Sort) ([]*models.User, error) {
+	args := m.Called(ctx, companyID, searchTerm, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -366,6 +468,24 @@ func (m *MockUserRepositoryForTeam) CountActiveUsers(ctx context.Context, compan
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockUserRepositoryForTeam) ExistsByCPF(ctx context.Context, companyID *uuid.UUID, cpf string, excludeUserID *uuid.UUID) (bool, error) {
+	args := m.Called(ctx, companyID, cpf, excludeUserID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryForTeam) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryForTeam) UpdatePreferences(ctx context.Context, id uuid.UUID, req models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPreferences), args.Error(1)
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
@@ -377,7 +497,7 @@ func setupTeamTestContext() (*gin.Context, *httptest.ResponseRecorder) {
 
 	// Set company context
 	companyID := uuid.New()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 
 	return c, w
 }
@@ -390,7 +510,7 @@ func setupTeamTestContextWithUser() (*gin.Context, *httptest.ResponseRecorder, u
 	// Set company and user context
 	companyID := uuid.New()
 	userID := uuid.New()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Set("userContext", &models.UserContext{
 		UserID:    userID,
 		CompanyID: &companyID,
@@ -437,7 +557,7 @@ func TestGetTeamStats(t *testing.T) {
 	mockVehicleRepo.On("GetByTeam", mock.Anything, teamID, companyID).Return(vehicles, nil)
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
 	c.Request = httptest.NewRequest("GET", "/teams/"+teamID.String()+"/stats", nil)
 
@@ -500,7 +620,7 @@ func TestGetTeamVehicles(t *testing.T) {
 	mockVehicleRepo.On("GetByTeam", mock.Anything, teamID, companyID).Return(vehicles, nil)
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
 	c.Request = httptest.NewRequest("GET", "/teams/"+teamID.String()+"/vehicles", nil)
 
@@ -555,7 +675,7 @@ func TestAssignVehicleToTeam(t *testing.T) {
 	mockVehicleRepo.On("UpdateAssignment", mock.Anything, vehicleID, companyID, vehicle.DriverID, vehicle.HelperID, &teamID).Return(nil)
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{
 		{Key: "id", Value: teamID.String()},
 		{Key: "vehicleId", Value: vehicleID.String()},
@@ -578,6 +698,113 @@ func TestAssignVehicleToTeam(t *testing.T) {
 	mockVehicleRepo.AssertExpectations(t)
 }
 
+func TestAssignVehicleToTeam_StrictRequiresCrewInTeam(t *testing.T) {
+	teamID := uuid.New()
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	driverID := uuid.New()
+	helperID := uuid.New()
+
+	vehicle := &models.Vehicle{
+		ID:           vehicleID,
+		CompanyID:    companyID,
+		LicensePlate: "ABC-1234",
+		DriverID:     &driverID,
+		HelperID:     &helperID,
+		Status:       "active",
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		helperMember  bool
+		expectedCode  int
+		checksMembers bool
+	}{
+		{"strict with outsider helper", "?strict=true", false, http.StatusConflict, true},
+		{"strict with full crew in team", "?strict=true", true, http.StatusOK, true},
+		{"loose by default", "", false, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTeamRepo := new(MockTeamRepository)
+			mockVehicleRepo := new(MockVehicleRepository)
+			handler := handlers.NewTeamHandler(mockTeamRepo, new(MockUserRepositoryForTeam), mockVehicleRepo)
+
+			mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(&models.Team{ID: teamID, CompanyID: companyID}, nil)
+			mockVehicleRepo.On("GetByID", mock.Anything, vehicleID, companyID).Return(vehicle, nil)
+			mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, driverID).Return(true, nil)
+			mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, helperID).Return(tt.helperMember, nil)
+			mockVehicleRepo.On("UpdateAssignment", mock.Anything, vehicleID, companyID, vehicle.DriverID, vehicle.HelperID, &teamID).Return(nil)
+
+			c, w := setupTeamTestContext()
+			middleware.SetCompanyID(c, companyID)
+			c.Params = gin.Params{
+				{Key: "id", Value: teamID.String()},
+				{Key: "vehicleId", Value: vehicleID.String()},
+			}
+			c.Request = httptest.NewRequest("POST", "/teams/"+teamID.String()+"/vehicles/"+vehicleID.String()+tt.query, nil)
+
+			handler.AssignVehicleToTeam(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusConflict {
+				var response struct {
+					Error map[string]interface{} `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "helper", response.Error["assignment"])
+				assert.Equal(t, helperID.String(), response.Error["user_id"])
+				mockVehicleRepo.AssertNotCalled(t, "UpdateAssignment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if !tt.checksMembers {
+				mockTeamRepo.AssertNotCalled(t, "CheckMemberExists", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAssignVehicleToTeam_CrossCompanyDriverRejected(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+
+	teamID := uuid.New()
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	foreignDriverID := uuid.New()
+
+	vehicle := &models.Vehicle{
+		ID:           vehicleID,
+		CompanyID:    companyID,
+		LicensePlate: "ABC-1234",
+		DriverID:     &foreignDriverID,
+		Status:       "active",
+	}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(&models.Team{ID: teamID, CompanyID: companyID}, nil)
+	mockVehicleRepo.On("GetByID", mock.Anything, vehicleID, companyID).Return(vehicle, nil)
+	mockVehicleRepo.On("UpdateAssignment", mock.Anything, vehicleID, companyID, vehicle.DriverID, vehicle.HelperID, &teamID).
+		Return(fmt.Errorf("%w: driver %s", repository.ErrAssignmentOutsideCompany, foreignDriverID))
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{
+		{Key: "id", Value: teamID.String()},
+		{Key: "vehicleId", Value: vehicleID.String()},
+	}
+	c.Request = httptest.NewRequest("POST", "/teams/"+teamID.String()+"/vehicles/"+vehicleID.String(), nil)
+
+	handler.AssignVehicleToTeam(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), foreignDriverID.String())
+	mockVehicleRepo.AssertExpectations(t)
+}
+
 // ============================================================================
 // TEST: Unassign Vehicle from Team
 // ============================================================================
@@ -613,7 +840,7 @@ func TestUnassignVehicleFromTeam(t *testing.T) {
 	mockVehicleRepo.On("UpdateAssignment", mock.Anything, vehicleID, companyID, vehicle.DriverID, vehicle.HelperID, (*uuid.UUID)(nil)).Return(nil)
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{
 		{Key: "id", Value: teamID.String()},
 		{Key: "vehicleId", Value: vehicleID.String()},
@@ -648,9 +875,9 @@ func TestGetMyTeams(t *testing.T) {
 	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
 
 	userID := uuid.New()
-	teams := []models.Team{
-		{ID: uuid.New(), Name: "Team 1", Status: "active"},
-		{ID: uuid.New(), Name: "Team 2", Status: "active"},
+	teams := []models.UserTeam{
+		{Team: models.Team{ID: uuid.New(), Name: "Team 1", Status: "active"}, RoleInTeam: "driver"},
+		{Team: models.Team{ID: uuid.New(), Name: "Team 2", Status: "active"}, RoleInTeam: "manager"},
 	}
 
 	mockTeamRepo.On("GetTeamsByUser", mock.Anything, userID).Return(teams, nil)
@@ -675,8 +902,64 @@ func TestGetMyTeams(t *testing.T) {
 	teamsList := data["teams"].([]interface{})
 	assert.Equal(t, 2, len(teamsList))
 	assert.Equal(t, float64(2), data["count"])
+	for i, entry := range teamsList {
+		team := entry.(map[string]interface{})
+		assert.Equal(t, teams[i].Name, team["name"])
+		assert.Equal(t, teams[i].RoleInTeam, team["role_in_team"])
+	}
+
+	mockTeamRepo.AssertExpectations(t)
+}
+
+func TestGetManagerDashboard_OnlyManagedTeams(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockVehicleRepo := new(MockVehicleRepository)
+	handler := handlers.NewTeamHandler(mockTeamRepo, new(MockUserRepositoryForTeam), mockVehicleRepo)
+
+	managerID := uuid.New()
+	companyID := uuid.New()
+	sharedUserID := uuid.New()
+	teamA := models.Team{ID: uuid.New(), CompanyID: companyID, Name: "North", ManagerID: &managerID, Status: "active"}
+	teamB := models.Team{ID: uuid.New(), CompanyID: companyID, Name: "South", ManagerID: &managerID, Status: "active"}
+
+	mockTeamRepo.On("GetTeamsManagedBy", mock.Anything, managerID).Return([]models.Team{teamA, teamB}, nil)
+	mockTeamRepo.On("GetMembers", mock.Anything, teamA.ID).Return([]models.TeamMember{
+		{TeamID: teamA.ID, UserID: sharedUserID},
+		{TeamID: teamA.ID, UserID: uuid.New()},
+	}, nil)
+	mockTeamRepo.On("GetMembers", mock.Anything, teamB.ID).Return([]models.TeamMember{
+		{TeamID: teamB.ID, UserID: sharedUserID},
+	}, nil)
+	mockVehicleRepo.On("GetByTeam", mock.Anything, teamA.ID, companyID).Return([]models.Vehicle{
+		{ID: uuid.New(), Status: "active"},
+		{ID: uuid.New(), Status: "maintenance"},
+	}, nil)
+	mockVehicleRepo.On("GetByTeam", mock.Anything, teamB.ID, companyID).Return([]models.Vehicle{}, nil)
+
+	c, w, _ := setupTeamTestContextWithUser()
+	c.Set("userContext", &models.UserContext{UserID: managerID, CompanyID: &companyID, Role: "manager"})
+	c.Request = httptest.NewRequest("GET", "/manager/dashboard", nil)
+
+	handler.GetManagerDashboard(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data models.ManagerDashboard `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	dashboard := response.Data
+	assert.Equal(t, 2, dashboard.TeamCount)
+	assert.Equal(t, 2, dashboard.MemberCount, "a user in two managed teams is counted once")
+	assert.Equal(t, 2, dashboard.VehicleCount)
+	assert.Equal(t, 1, dashboard.ActiveVehicles)
+	require.Len(t, dashboard.Teams, 2)
+	assert.Equal(t, "North", dashboard.Teams[0].Team.Name)
+	assert.Len(t, dashboard.Teams[0].Members, 2)
+	assert.Empty(t, dashboard.Teams[1].Vehicles)
 
 	mockTeamRepo.AssertExpectations(t)
+	mockVehicleRepo.AssertExpectations(t)
 }
 
 // ============================================================================
@@ -701,24 +984,13 @@ func TestUpdateMemberRole(t *testing.T) {
 		Status:    "active",
 	}
 
-	user := &models.User{
-		ID:    userID,
-		Name:  "Test User",
-		Email: "test@example.com",
-	}
-
 	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
-	mockUserRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
-	mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, userID).Return(true, nil)
 	mockTeamRepo.On("UpdateMemberRole", mock.Anything, teamID, userID, "manager").Return(nil)
 
-	updateReq := models.UpdateMemberRoleRequest{
-		NewRoleInTeam: "manager",
-	}
-	body, _ := json.Marshal(updateReq)
+	body, _ := json.Marshal(map[string]string{"role_in_team": "manager"})
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{
 		{Key: "id", Value: teamID.String()},
 		{Key: "userId", Value: userID.String()},
@@ -751,7 +1023,7 @@ func TestGetTeamStats_TeamNotFound(t *testing.T) {
 	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return((*models.Team)(nil), nil)
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
 	c.Request = httptest.NewRequest("GET", "/teams/"+teamID.String()+"/stats", nil)
 
@@ -762,6 +1034,113 @@ func TestGetTeamStats_TeamNotFound(t *testing.T) {
 	mockTeamRepo.AssertExpectations(t)
 }
 
+func TestGetTeamStats_DistinguishesDeletedFromArchived(t *testing.T) {
+	deletedAt := time.Now()
+	tests := []struct {
+		name      string
+		status    string
+		deletedAt *time.Time
+		want      string
+	}{
+		{"archived", models.TeamStatusArchived, nil, models.TeamStatusArchived},
+		{"deleted", models.TeamStatusActive, &deletedAt, models.TeamStatusDeleted},
+		{"archived then deleted", models.TeamStatusArchived, &deletedAt, models.TeamStatusDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTeamRepo := new(MockTeamRepository)
+			mockVehicleRepo := new(MockVehicleRepository)
+			handler := handlers.NewTeamHandler(mockTeamRepo, new(MockUserRepositoryForTeam), mockVehicleRepo)
+
+			teamID, companyID := uuid.New(), uuid.New()
+			team := &models.Team{ID: teamID, CompanyID: companyID, Name: "Team", Status: tt.status, DeletedAt: tt.deletedAt}
+			mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+			mockTeamRepo.On("GetMembers", mock.Anything, teamID).Return([]models.TeamMember{}, nil)
+			mockVehicleRepo.On("GetByTeam", mock.Anything, teamID, companyID).Return([]models.Vehicle{}, nil)
+
+			c, w := setupTeamTestContext()
+			middleware.SetCompanyID(c, companyID)
+			c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+			c.Request = httptest.NewRequest("GET", "/teams/"+teamID.String()+"/stats", nil)
+
+			handler.GetTeamStats(c)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var response struct {
+				Data models.TeamStats `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.want, response.Data.Status)
+			assert.Equal(t, tt.deletedAt != nil, response.Data.DeletedAt != nil)
+		})
+	}
+}
+
+// ============================================================================
+// TEST: Archive / Unarchive Team
+// ============================================================================
+
+func archiveTeamRequest(t *testing.T, team *models.Team, archive bool, expectStatus string) *httptest.ResponseRecorder {
+	mockTeamRepo := new(MockTeamRepository)
+	handler := handlers.NewTeamHandler(mockTeamRepo, new(MockUserRepositoryForTeam), new(MockVehicleRepository))
+
+	teamID, companyID := uuid.New(), uuid.New()
+	if team != nil {
+		team.ID, team.CompanyID = teamID, companyID
+	}
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	if expectStatus != "" {
+		mockTeamRepo.On("SetStatus", mock.Anything, teamID, companyID, expectStatus).Return(nil)
+	}
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	if archive {
+		c.Request = httptest.NewRequest("POST", "/api/v1/teams/"+teamID.String()+"/archive", nil)
+		handler.ArchiveTeam(c)
+	} else {
+		c.Request = httptest.NewRequest("POST", "/api/v1/teams/"+teamID.String()+"/unarchive", nil)
+		handler.UnarchiveTeam(c)
+	}
+
+	mockTeamRepo.AssertExpectations(t)
+	return w
+}
+
+func TestArchiveTeam(t *testing.T) {
+	w := archiveTeamRequest(t, &models.Team{Name: "Team", Status: models.TeamStatusActive}, true, models.TeamStatusArchived)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.Team `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.TeamStatusArchived, response.Data.Status)
+}
+
+func TestArchiveTeam_AlreadyArchived(t *testing.T) {
+	w := archiveTeamRequest(t, &models.Team{Name: "Team", Status: models.TeamStatusArchived}, true, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestArchiveTeam_DeletedTeamNotFound(t *testing.T) {
+	deletedAt := time.Now()
+	w := archiveTeamRequest(t, &models.Team{Name: "Team", Status: models.TeamStatusActive, DeletedAt: &deletedAt}, true, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUnarchiveTeam(t *testing.T) {
+	w := archiveTeamRequest(t, &models.Team{Name: "Team", Status: models.TeamStatusArchived}, false, models.TeamStatusActive)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUnarchiveTeam_NotArchived(t *testing.T) {
+	w := archiveTeamRequest(t, &models.Team{Name: "Team", Status: models.TeamStatusActive}, false, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
 func TestAssignVehicle_VehicleNotFound(t *testing.T) {
 	mockTeamRepo := new(MockTeamRepository)
 	mockUserRepo := new(MockUserRepositoryForTeam)
@@ -784,7 +1163,7 @@ func TestAssignVehicle_VehicleNotFound(t *testing.T) {
 	mockVehicleRepo.On("GetByID", mock.Anything, vehicleID, companyID).Return((*models.Vehicle)(nil), nil)
 
 	c, w := setupTeamTestContext()
-	c.Set("company_id", companyID)
+	middleware.SetCompanyID(c, companyID)
 	c.Params = gin.Params{
 		{Key: "id", Value: teamID.String()},
 		{Key: "vehicleId", Value: vehicleID.String()},
@@ -798,3 +1177,585 @@ func TestAssignVehicle_VehicleNotFound(t *testing.T) {
 	mockTeamRepo.AssertExpectations(t)
 	mockVehicleRepo.AssertExpectations(t)
 }
+
+// ============================================================================
+// TEST: Patch Team
+// ============================================================================
+
+func TestPatchTeam_OmittedFieldsArePreserved(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	managerID := uuid.New()
+	description := "Night shift drivers"
+
+	team := &models.Team{
+		ID:          teamID,
+		CompanyID:   companyID,
+		Name:        "Old Name",
+		Description: &description,
+		ManagerID:   &managerID,
+		Status:      "active",
+	}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockTeamRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Team) bool {
+		return updated.Name == "New Name" &&
+			updated.Description != nil && *updated.Description == description &&
+			updated.ManagerID != nil && *updated.ManagerID == managerID
+	})).Return(nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("PATCH", "/teams/"+teamID.String(), bytes.NewBufferString(`{"name":"New Name"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.PatchTeam(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "New Name", data["name"])
+	assert.Equal(t, description, data["description"])
+	assert.Equal(t, managerID.String(), data["manager_id"])
+
+	mockTeamRepo.AssertExpectations(t)
+	mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestPatchTeam_UpdatesManagerOnly(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	newManagerID := uuid.New()
+	description := "Night shift drivers"
+
+	team := &models.Team{
+		ID:          teamID,
+		CompanyID:   companyID,
+		Name:        "Team",
+		Description: &description,
+		Status:      "active",
+	}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockUserRepo.On("GetByID", mock.Anything, newManagerID).Return(&models.User{ID: newManagerID, CompanyID: &companyID}, nil)
+	mockTeamRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Team) bool {
+		return updated.Name == "Team" &&
+			updated.Description != nil && *updated.Description == description &&
+			updated.ManagerID != nil && *updated.ManagerID == newManagerID
+	})).Return(nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	body := `{"manager_id":"` + newManagerID.String() + `"}`
+	c.Request = httptest.NewRequest("PATCH", "/teams/"+teamID.String(), bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.PatchTeam(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockTeamRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestPatchTeam_ManagerFromOtherCompany(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	otherCompanyID := uuid.New()
+	managerID := uuid.New()
+
+	team := &models.Team{ID: teamID, CompanyID: companyID, Name: "Team", Status: "active"}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockUserRepo.On("GetByID", mock.Anything, managerID).Return(&models.User{ID: managerID, CompanyID: &otherCompanyID}, nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	body := `{"manager_id":"` + managerID.String() + `"}`
+	c.Request = httptest.NewRequest("PATCH", "/teams/"+teamID.String(), bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.PatchTeam(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTeam_ReplacesOmittedFields(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	managerID := uuid.New()
+	description := "Night shift drivers"
+
+	team := &models.Team{
+		ID:          teamID,
+		CompanyID:   companyID,
+		Name:        "Old Name",
+		Description: &description,
+		ManagerID:   &managerID,
+		Status:      "active",
+	}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockTeamRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Team) bool {
+		return updated.Name == "New Name" && updated.Description == nil && updated.ManagerID == nil
+	})).Return(nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("PUT", "/teams/"+teamID.String(), bytes.NewBufferString(`{"name":"New Name"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.UpdateTeam(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockTeamRepo.AssertExpectations(t)
+}
+
+// ============================================================================
+// TEST: Change Team Manager
+// ============================================================================
+
+type managerNotification struct {
+	email    string
+	teamName string
+	assigned bool
+}
+
+// fakeManagerNotifier forwards notifications to a channel since they are sent in the background
+type fakeManagerNotifier struct {
+	sent chan managerNotification
+}
+
+func (f *fakeManagerNotifier) SendTeamManagerChanged(email, userName, teamName string, assigned bool) error {
+	f.sent <- managerNotification{email: email, teamName: teamName, assigned: assigned}
+	return nil
+}
+
+func TestChangeTeamManager_UpdatesManagerAndNotifies(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	notifier := &fakeManagerNotifier{sent: make(chan managerNotification, 2)}
+	handler.SetManagerNotifier(notifier)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	oldManagerID := uuid.New()
+	newManagerID := uuid.New()
+
+	team := &models.Team{ID: teamID, CompanyID: companyID, Name: "North Route", ManagerID: &oldManagerID, Status: "active"}
+	oldManager := &models.User{ID: oldManagerID, Email: "old@fleet.com", CompanyID: &companyID, Active: true, Role: &models.Role{Name: "manager"}}
+	newManager := &models.User{ID: newManagerID, Email: "new@fleet.com", CompanyID: &companyID, Active: true, Role: &models.Role{Name: "manager"}}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockUserRepo.On("GetByID", mock.Anything, newManagerID).Return(newManager, nil)
+	mockUserRepo.On("GetByID", mock.Anything, oldManagerID).Return(oldManager, nil)
+	mockTeamRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Team) bool {
+		return updated.ManagerID != nil && *updated.ManagerID == newManagerID
+	})).Return(nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	body := `{"manager_id":"` + newManagerID.String() + `"}`
+	c.Request = httptest.NewRequest("PUT", "/teams/"+teamID.String()+"/manager", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.ChangeTeamManager(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, newManagerID.String(), data["manager_id"])
+
+	received := map[string]managerNotification{}
+	for i := 0; i < 2; i++ {
+		select {
+		case n := <-notifier.sent:
+			received[n.email] = n
+		case <-time.After(time.Second):
+			t.Fatal("manager notification was not sent")
+		}
+	}
+	assert.Equal(t, managerNotification{email: "old@fleet.com", teamName: "North Route", assigned: false}, received["old@fleet.com"])
+	assert.Equal(t, managerNotification{email: "new@fleet.com", teamName: "North Route", assigned: true}, received["new@fleet.com"])
+
+	mockTeamRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestChangeTeamManager_RejectsUserWithoutManagerRole(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+
+	teamID := uuid.New()
+	companyID := uuid.New()
+	driverID := uuid.New()
+
+	team := &models.Team{ID: teamID, CompanyID: companyID, Name: "North Route", Status: "active"}
+	driver := &models.User{ID: driverID, CompanyID: &companyID, Active: true, Role: &models.Role{Name: "driver"}}
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockUserRepo.On("GetByID", mock.Anything, driverID).Return(driver, nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	body := `{"manager_id":"` + driverID.String() + `"}`
+	c.Request = httptest.NewRequest("PUT", "/teams/"+teamID.String()+"/manager", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.ChangeTeamManager(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestGetTeamMemberHistory_NegativeLimitRejected(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	teamID := uuid.New()
+
+	c, w := setupTeamTestContext()
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("GET", "/teams/"+teamID.String()+"/member-history?limit=-1", nil)
+
+	handler.GetTeamMemberHistory(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockTeamRepo.AssertNotCalled(t, "GetMemberHistoryWithDetails", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTeamMemberHistory_LimitClampedToMax(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	teamID := uuid.New()
+	companyID := uuid.New()
+
+	team := &models.Team{ID: teamID, CompanyID: companyID, Name: "North Route", Status: "active"}
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, companyID).Return(team, nil)
+	mockTeamRepo.On("GetMemberHistoryWithDetails", mock.Anything, teamID, companyID, 500).
+		Return([]models.TeamMemberHistory{}, nil)
+
+	c, w := setupTeamTestContext()
+	middleware.SetCompanyID(c, companyID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("GET", "/teams/"+teamID.String()+"/member-history?limit=9999", nil)
+
+	handler.GetTeamMemberHistory(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockTeamRepo.AssertExpectations(t)
+}
+
+// ============================================================================
+// TEST: Location header on creation
+// ============================================================================
+
+func TestCreateTeam_SetsLocationHeader(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	teamID := uuid.New()
+
+	mockTeamRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Team")).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.Team).ID = teamID }).
+		Return(nil)
+
+	c, w := setupTeamTestContext()
+	c.Request = httptest.NewRequest("POST", "/api/v1/company-admin/teams", bytes.NewBufferString(`{"name":"North Route"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.CreateTeam(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/v1/company-admin/teams/"+teamID.String(), w.Header().Get("Location"))
+}
+
+func TestAddMember_SetsLocationHeader(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	teamID := uuid.New()
+	userID := uuid.New()
+
+	c, w := setupTeamTestContext()
+	companyID, _ := middleware.GetCompanyIDFromContext(c)
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, *companyID).
+		Return(&models.Team{ID: teamID, CompanyID: *companyID, Name: "North Route"}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, userID).
+		Return(&models.User{ID: userID, CompanyID: companyID}, nil)
+	mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, userID).Return(false, nil)
+	mockTeamRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*models.TeamMember")).Return(nil)
+
+	body := fmt.Sprintf(`{"user_id":"%s","role_in_team":"driver"}`, userID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("POST", "/api/v1/company-admin/teams/"+teamID.String()+"/members/", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.AddMember(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/v1/company-admin/teams/"+teamID.String()+"/members/"+userID.String(), w.Header().Get("Location"))
+}
+
+func TestAddMembersBulk_PartialSuccessReturnsMultiStatus(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	teamID := uuid.New()
+	newMember := uuid.New()
+	existingMember := uuid.New()
+	outsider := uuid.New()
+	otherCompany := uuid.New()
+
+	c, w := setupTeamTestContext()
+	companyID, _ := middleware.GetCompanyIDFromContext(c)
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, *companyID).
+		Return(&models.Team{ID: teamID, CompanyID: *companyID, Name: "North Route"}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, newMember).Return(&models.User{ID: newMember, CompanyID: companyID}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, existingMember).Return(&models.User{ID: existingMember, CompanyID: companyID}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, outsider).Return(&models.User{ID: outsider, CompanyID: &otherCompany}, nil)
+	mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, newMember).Return(false, nil)
+	mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, existingMember).Return(true, nil)
+	mockTeamRepo.On("AddMembers", mock.Anything, *companyID, mock.MatchedBy(func(members []*models.TeamMember) bool {
+		return len(members) == 1 && members[0].UserID == newMember
+	})).Return([]bool{true}, nil).Once()
+
+	body := fmt.Sprintf(`[
+		{"user_id":"%s","role_in_team":"driver"},
+		{"user_id":"%s","role_in_team":"driver"},
+		{"user_id":"%s","role_in_team":"helper"},
+		{"user_id":"%s","role_in_team":"pilot"}
+	]`, newMember, existingMember, outsider, uuid.New())
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("POST", "/api/v1/company-admin/teams/"+teamID.String()+"/members/bulk", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.AddMembersBulk(c)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+
+	var response struct {
+		Success bool               `json:"success"`
+		Data    models.BatchResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, models.BatchSummary{Total: 4, Succeeded: 1, Failed: 2, Skipped: 1}, response.Data.Summary)
+
+	require.Len(t, response.Data.Results, 4)
+	for i, want := range []struct{ id, status string }{
+		{newMember.String(), models.BatchItemSucceeded},
+		{existingMember.String(), models.BatchItemSkipped},
+		{outsider.String(), models.BatchItemFailed},
+		{"", models.BatchItemFailed},
+	} {
+		result := response.Data.Results[i]
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, want.id, result.ID)
+		assert.Equal(t, want.status, result.Status)
+		if want.status == models.BatchItemSucceeded {
+			assert.Empty(t, result.Error)
+			assert.NotNil(t, result.Data)
+		} else {
+			assert.NotEmpty(t, result.Error)
+		}
+	}
+	mockTeamRepo.AssertExpectations(t)
+}
+
+func TestAddMembersBulk_AllAddedReturnsOK(t *testing.T) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	mockVehicleRepo := new(MockVehicleRepository)
+
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, mockVehicleRepo)
+	teamID := uuid.New()
+	userID := uuid.New()
+
+	c, w := setupTeamTestContext()
+	companyID, _ := middleware.GetCompanyIDFromContext(c)
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, *companyID).
+		Return(&models.Team{ID: teamID, CompanyID: *companyID}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, userID).Return(&models.User{ID: userID, CompanyID: companyID}, nil)
+	mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, userID).Return(false, nil)
+	mockTeamRepo.On("AddMembers", mock.Anything, *companyID, mock.Anything).Return([]bool{true}, nil)
+
+	body := fmt.Sprintf(`[{"user_id":"%s","role_in_team":"driver"}]`, userID)
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("POST", "/api/v1/company-admin/teams/"+teamID.String()+"/members/bulk", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.AddMembersBulk(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// postBulkMembers sends a bulk add of eligible users, with the repository answering
+// AddMembers with added or addErr, and returns the response
+func postBulkMembers(t *testing.T, users []uuid.UUID, added []bool, addErr error) (int, models.BatchResult) {
+	mockTeamRepo := new(MockTeamRepository)
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewTeamHandler(mockTeamRepo, mockUserRepo, new(MockVehicleRepository))
+	teamID := uuid.New()
+
+	c, w := setupTeamTestContext()
+	companyID, _ := middleware.GetCompanyIDFromContext(c)
+
+	mockTeamRepo.On("GetByID", mock.Anything, teamID, *companyID).Return(&models.Team{ID: teamID, CompanyID: *companyID}, nil)
+	entries := make([]string, len(users))
+	for i, id := range users {
+		mockUserRepo.On("GetByID", mock.Anything, id).Return(&models.User{ID: id, CompanyID: companyID}, nil)
+		mockTeamRepo.On("CheckMemberExists", mock.Anything, teamID, id).Return(false, nil)
+		entries[i] = fmt.Sprintf(`{"user_id":"%s","role_in_team":"driver"}`, id)
+	}
+	if addErr != nil {
+		mockTeamRepo.On("AddMembers", mock.Anything, *companyID, mock.Anything).Return(nil, addErr)
+	} else {
+		mockTeamRepo.On("AddMembers", mock.Anything, *companyID, mock.Anything).Return(added, nil)
+	}
+
+	body := "[" + strings.Join(entries, ",") + "]"
+	c.Params = gin.Params{{Key: "id", Value: teamID.String()}}
+	c.Request = httptest.NewRequest("POST", "/api/v1/company-admin/teams/"+teamID.String()+"/members/bulk", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.AddMembersBulk(c)
+
+	var response struct {
+		Data models.BatchResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	mockTeamRepo.AssertExpectations(t)
+	return w.Code, response.Data
+}
+
+func TestAddMembersBulk_TransactionFailureAddsNobody(t *testing.T) {
+	code, result := postBulkMembers(t, []uuid.UUID{uuid.New(), uuid.New()}, nil, errors.New("connection reset"))
+
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.Equal(t, models.BatchSummary{Total: 2, Failed: 2}, result.Summary)
+}
+
+func TestAddMembersBulk_ConcurrentlyAddedMemberSkipped(t *testing.T) {
+	code, result := postBulkMembers(t, []uuid.UUID{uuid.New(), uuid.New()}, []bool{true, false}, nil)
+
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.Equal(t, models.BatchSummary{Total: 2, Succeeded: 1, Skipped: 1}, result.Summary)
+	assert.Equal(t, models.BatchItemSucceeded, result.Results[0].Status)
+	assert.Equal(t, models.BatchItemSkipped, result.Results[1].Status)
+}
+
+func TestCreateTeam_ManagerShowsUpInMyTeams(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	mockUserRepo := new(MockUserRepositoryForTeam)
+	handler := handlers.NewTeamHandler(repository.NewTeamRepository(sqlx.NewDb(mockDB, "sqlmock")), mockUserRepo, new(MockVehicleRepository))
+
+	c, w := setupTeamTestContext()
+	companyID, _ := middleware.GetCompanyIDFromContext(c)
+	managerID := uuid.New()
+	mockUserRepo.On("GetByID", mock.Anything, managerID).Return(&models.User{ID: managerID, CompanyID: companyID}, nil)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO teams")).WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT role_in_team FROM team_members")).
+		WithArgs(sqlmock.AnyArg(), managerID).
+		WillReturnRows(sqlmock.NewRows([]string{"role_in_team"}))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_members")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), managerID, models.TeamRoleManager, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_member_history")).WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	body := fmt.Sprintf(`{"name":"North Route","manager_id":"%s"}`, managerID)
+	c.Request = httptest.NewRequest("POST", "/api/v1/company-admin/teams", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.CreateTeam(c)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+
+	// The membership row makes the team part of the manager's teams
+	sqlMock.ExpectQuery(regexp.QuoteMeta("JOIN team_members tm ON t.id = tm.team_id")).
+		WithArgs(managerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "name", "description", "manager_id", "status",
+			"created_at", "updated_at", "role_in_team", "joined_at"}).
+			AddRow(uuid.New(), *companyID, "North Route", nil, managerID, "active", time.Now(), time.Now(), models.TeamRoleManager, time.Now()))
+
+	c, w = setupTeamTestContext()
+	c.Set("userContext", &models.UserContext{UserID: managerID, CompanyID: companyID, Role: "manager"})
+	c.Request = httptest.NewRequest("GET", "/api/v1/teams/my-teams", nil)
+
+	handler.GetMyTeams(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data struct {
+			Teams []models.UserTeam `json:"teams"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data.Teams, 1)
+	assert.Equal(t, "North Route", response.Data.Teams[0].Name)
+	assert.Equal(t, models.TeamRoleManager, response.Data.Teams[0].RoleInTeam)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,56 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestCheckEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userRepo := new(MockUserRepositoryForTeam)
+	userRepo.On("ExistsByEmail", mock.Anything, "taken@example.com").Return(true, nil)
+	userRepo.On("ExistsByEmail", mock.Anything, "free@example.com").Return(false, nil)
+	handler := handlers.NewUserHandler(services.NewUserService(userRepo, nil, bcrypt.MinCost))
+
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		available bool
+	}{
+		{"taken email", "?email=taken@example.com", http.StatusOK, false},
+		{"free email", "?email=free@example.com", http.StatusOK, true},
+		{"missing email", "", http.StatusBadRequest, false},
+		{"malformed email", "?email=not-an-email", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/check-email"+tt.query, nil)
+
+			handler.CheckEmail(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				var body map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, map[string]interface{}{"available": tt.available}, body)
+			}
+		})
+	}
+	userRepo.AssertNumberOfCalls(t, "ExistsByEmail", 2)
+}
@@ -0,0 +1,121 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// newStreamServer serves the vehicle stream for a company whose vehicle exists
+func newStreamServer(t *testing.T, hub *services.LiveFeedHub, vehicleID uuid.UUID) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	companyID := uuid.New()
+	sqlMock.MatchExpectationsInOrder(false)
+	for i := 0; i < 3; i++ {
+		sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+			WithArgs(vehicleID, companyID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "license_plate", "status"}).
+				AddRow(vehicleID, companyID, "ABC1D23", "active"))
+	}
+
+	handler := handlers.NewVehicleStreamHandler(hub, repository.NewVehicleRepository(sqlx.NewDb(mockDB, "sqlmock")))
+	engine := gin.New()
+	engine.GET("/api/v1/vehicles/:id/stream", func(c *gin.Context) {
+		middleware.SetCompanyID(c, companyID)
+	}, handler.StreamVehicle)
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dialStream(server *httptest.Server, vehicleID uuid.UUID, origin string) (*websocket.Conn, error) {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/vehicles/" + vehicleID.String() + "/stream"
+	return websocket.Dial(url, "", origin)
+}
+
+func TestStreamVehicle_PushesPublishedEvents(t *testing.T) {
+	hub := services.NewLiveFeedHub(services.DefaultLiveFeedBuffer)
+	vehicleID := uuid.New()
+	server := newStreamServer(t, hub, vehicleID)
+
+	ws, err := dialStream(server, vehicleID, server.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	require.Eventually(t, func() bool { return hub.HasSubscribers(vehicleID) }, time.Second, 10*time.Millisecond)
+	hub.Publish(models.LiveEvent{
+		Type:      models.LiveEventAlert,
+		VehicleID: vehicleID,
+		Source:    "dht11",
+		Timestamp: time.Now().UTC(),
+		Data:      map[string]any{"type": "temperature_high"},
+	})
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var event models.LiveEvent
+	require.NoError(t, websocket.JSON.Receive(ws, &event))
+	assert.Equal(t, models.LiveEventAlert, event.Type)
+	assert.Equal(t, vehicleID, event.VehicleID)
+	assert.Equal(t, "dht11", event.Source)
+}
+
+func TestStreamVehicle_UnsubscribesWhenClientDisconnects(t *testing.T) {
+	hub := services.NewLiveFeedHub(services.DefaultLiveFeedBuffer)
+	vehicleID := uuid.New()
+	server := newStreamServer(t, hub, vehicleID)
+
+	ws, err := dialStream(server, vehicleID, server.URL)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return hub.HasSubscribers(vehicleID) }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, ws.Close())
+
+	assert.Eventually(t, func() bool { return !hub.HasSubscribers(vehicleID) }, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamVehicle_RejectsCrossSiteOrigin(t *testing.T) {
+	hub := services.NewLiveFeedHub(services.DefaultLiveFeedBuffer)
+	vehicleID := uuid.New()
+	server := newStreamServer(t, hub, vehicleID)
+
+	_, err := dialStream(server, vehicleID, "https://evil.example.com")
+
+	assert.Error(t, err)
+	assert.False(t, hub.HasSubscribers(vehicleID))
+}
+
+func TestStreamVehicle_RequiresWebSocketUpgrade(t *testing.T) {
+	hub := services.NewLiveFeedHub(services.DefaultLiveFeedBuffer)
+	vehicleID := uuid.New()
+	server := newStreamServer(t, hub, vehicleID)
+
+	resp, err := http.Get(server.URL + "/api/v1/vehicles/" + vehicleID.String() + "/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
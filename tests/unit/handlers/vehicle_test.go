@@ -0,0 +1,394 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/handlers"
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+const vehicleUpdateBody = `{
+	"license_plate": "ABC1234", "brand": "Volvo", "model": "FH", "year": 2020,
+	"vehicle_type": "truck", "fuel_type": "diesel", "status": "%s"
+}`
+
+// updateVehicleStatus sends an update moving a vehicle stored with status from to status to
+func updateVehicleStatus(t *testing.T, from, to string, expectUpdate bool) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	columns := []string{"id", "company_id", "team_id", "license_plate", "brand", "model", "year", "color",
+		"vehicle_type", "fuel_type", "cargo_capacity", "driver_id", "helper_id", "status", "created_at", "updated_at"}
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(vehicleID, companyID, nil, "ABC1234", "Volvo", "FH", 2020, nil,
+			"truck", "diesel", nil, nil, nil, from, time.Now(), time.Now()))
+	if expectUpdate {
+		sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE vehicles SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(db), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := []byte(fmt.Sprintf(vehicleUpdateBody, to))
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/company-admin/vehicles/"+vehicleID.String(), bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: vehicleID.String()}}
+	middleware.SetCompanyID(c, companyID)
+
+	handler.UpdateVehicle(c)
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	return w
+}
+
+func TestUpdateVehicle_AllowsValidStatusTransition(t *testing.T) {
+	w := updateVehicleStatus(t, "active", "maintenance", true)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"maintenance"`)
+}
+
+func TestUpdateVehicle_RejectsIllegalStatusTransition(t *testing.T) {
+	w := updateVehicleStatus(t, "retired", "active", false)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cannot change from retired to active")
+}
+
+func TestUpdateVehicle_RejectsUnknownStatus(t *testing.T) {
+	w := updateVehicleStatus(t, "active", "actve", false)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid status")
+}
+
+func TestGetVehicles_RejectsInvalidFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewVehicleHandler(nil, nil)
+
+	for _, query := range []string{"status=actve", "vehicle_type=plane", "unassigned=maybe"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/company-admin/vehicles?"+query, nil)
+		middleware.SetCompanyID(c, uuid.New())
+
+		handler.GetVehicles(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestImportVehicles_MissingColumnNamedInError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewVehicleHandler(nil, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	csv := "license_plate,brand,model,type,fuel_type,colour\nABC1234,Volvo,FH,truck,diesel,red\n"
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/vehicles/import", strings.NewReader(csv))
+	c.Request.Header.Set("Content-Type", "text/csv")
+	middleware.SetCompanyID(c, uuid.New())
+
+	handler.ImportVehicles(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Error struct {
+			Message        string   `json:"message"`
+			MissingColumns []string `json:"missing_columns"`
+			UnknownColumns []string `json:"unknown_columns"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"year"}, response.Error.MissingColumns)
+	assert.Equal(t, []string{"colour"}, response.Error.UnknownColumns)
+	assert.Contains(t, response.Error.Message, "missing columns: year")
+}
+
+func TestGetImportTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewVehicleHandler(nil, nil)
+
+	tests := []struct {
+		query string
+		lines int
+	}{
+		{"", 1},
+		{"?sample=true", 2},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/import/template"+tt.query, nil)
+
+		handler.GetImportTemplate(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, tt.lines, tt.query)
+		assert.Equal(t, strings.Join(services.VehicleImportColumns, ","), lines[0])
+	}
+}
+
+func TestGetVehicleAssignment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(sqlx.NewDb(mockDB, "sqlmock")), nil)
+
+	vehicleID, companyID, otherCompanyID := uuid.New(), uuid.New(), uuid.New()
+	driverID, teamID := uuid.New(), uuid.New()
+	columns := []string{"vehicle_id", "license_plate", "driver_id", "driver_name", "helper_id", "helper_name", "team_id", "team_name"}
+	query := regexp.QuoteMeta("WHERE v.id = $1 AND v.company_id = $2")
+	sqlMock.ExpectQuery(query).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(vehicleID, "ABC1234", driverID, "Maria Souza", nil, nil, teamID, "North Route"))
+	sqlMock.ExpectQuery(query).
+		WithArgs(vehicleID, otherCompanyID).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	get := func(companyID uuid.UUID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/"+vehicleID.String()+"/assignment", nil)
+		c.Params = gin.Params{{Key: "id", Value: vehicleID.String()}}
+		middleware.SetCompanyID(c, companyID)
+		handler.GetVehicleAssignment(c)
+		return w
+	}
+
+	w := get(companyID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.VehicleAssignment `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Data.Driver)
+	assert.Equal(t, driverID, response.Data.Driver.ID)
+	assert.Equal(t, "Maria Souza", response.Data.Driver.Name)
+	assert.Nil(t, response.Data.Helper)
+	require.NotNil(t, response.Data.Team)
+	assert.Equal(t, "North Route", response.Data.Team.Name)
+
+	// A vehicle of another company is not found
+	assert.Equal(t, http.StatusNotFound, get(otherCompanyID).Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestGetVehicleByPlate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(sqlx.NewDb(mockDB, "sqlmock")), nil)
+
+	vehicleID, companyID, otherCompanyID := uuid.New(), uuid.New(), uuid.New()
+	columns := []string{"id", "company_id", "team_id", "license_plate", "brand", "model", "year", "color",
+		"vehicle_type", "fuel_type", "cargo_capacity", "driver_id", "helper_id", "status", "created_at", "updated_at"}
+	query := regexp.QuoteMeta("AND company_id = $2 AND status != 'deleted'")
+	sqlMock.ExpectQuery(query).
+		WithArgs("ABC1D23", companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(vehicleID, companyID, nil, "ABC-1D23", "Volvo", "FH", 2020, nil,
+			"truck", "diesel", nil, nil, nil, "active", time.Now(), time.Now()))
+	sqlMock.ExpectQuery(query).
+		WithArgs("ABC1D23", otherCompanyID).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	get := func(plate string, companyID uuid.UUID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/vehicles/by-plate/"+plate, nil)
+		c.Params = gin.Params{{Key: "plate", Value: plate}}
+		middleware.SetCompanyID(c, companyID)
+		handler.GetVehicleByPlate(c)
+		return w
+	}
+
+	w := get("abc-1d23", companyID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.Vehicle `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, vehicleID, response.Data.ID)
+
+	// A vehicle of another company is not found
+	assert.Equal(t, http.StatusNotFound, get("ABC1D23", otherCompanyID).Code)
+	// A plate with nothing left after normalization never reaches the database
+	assert.Equal(t, http.StatusBadRequest, get("--", companyID).Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// assignmentOutbox records notices sent by the assignment notifier worker
+type assignmentOutbox struct {
+	mu    sync.Mutex
+	sent  []string
+	users map[uuid.UUID]models.AssignmentRecipient
+}
+
+func (o *assignmentOutbox) GetAssignmentRecipients(ctx context.Context, ids []uuid.UUID) ([]models.AssignmentRecipient, error) {
+	var found []models.AssignmentRecipient
+	for _, id := range ids {
+		if user, ok := o.users[id]; ok {
+			found = append(found, user)
+		}
+	}
+	return found, nil
+}
+
+func (o *assignmentOutbox) SendVehicleAssignmentChanged(email, userName string, notice services.AssignmentNotice) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sent = append(o.sent, email)
+	return nil
+}
+
+func (o *assignmentOutbox) emails() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.sent...)
+}
+
+func TestAssignUsers_QueuesNotificationForNewDriver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	vehicleID, companyID, driverID := uuid.New(), uuid.New(), uuid.New()
+	columns := []string{"id", "company_id", "team_id", "license_plate", "brand", "model", "year", "color",
+		"vehicle_type", "fuel_type", "cargo_capacity", "driver_id", "helper_id", "status", "created_at", "updated_at"}
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(vehicleID, companyID, nil, "ABC1234", "Volvo", "FH", 2020, nil,
+			"truck", "diesel", nil, nil, nil, "active", time.Now(), time.Now()))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM users")).
+		WithArgs(driverID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT id, driver_id, helper_id, team_id FROM vehicles")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "driver_id", "helper_id", "team_id"}).AddRow(vehicleID, nil, nil, nil))
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE vehicles SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicle_assignment_history")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	outbox := &assignmentOutbox{users: map[uuid.UUID]models.AssignmentRecipient{
+		driverID: {ID: driverID, Name: "Maria Souza", Email: "maria@example.com", NotifyAssignmentChanges: true},
+	}}
+	notifier := services.NewAssignmentNotifier(outbox, outbox)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifier.Start(ctx)
+
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(sqlx.NewDb(mockDB, "sqlmock")), nil)
+	handler.SetAssignmentNotifier(notifier)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := fmt.Sprintf(`{"driver_id": %q}`, driverID)
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/company-admin/vehicles/"+vehicleID.String()+"/assign", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: vehicleID.String()}}
+	middleware.SetCompanyID(c, companyID)
+
+	handler.AssignUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Eventually(t, func() bool {
+		return slices.Equal(outbox.emails(), []string{"maria@example.com"})
+	}, time.Second, 10*time.Millisecond)
+}
+
+// assignUsers sends an assignment update for vehicleID with the given body
+func assignUsers(handler *handlers.VehicleHandler, vehicleID, companyID uuid.UUID, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/company-admin/vehicles/"+vehicleID.String()+"/assign", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: vehicleID.String()}}
+	middleware.SetCompanyID(c, companyID)
+	handler.AssignUsers(c)
+	return w
+}
+
+func TestAssignUsers_RecordsReasonInHistory(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	vehicleID, companyID, driverID := uuid.New(), uuid.New(), uuid.New()
+	columns := []string{"id", "company_id", "team_id", "license_plate", "brand", "model", "year", "color",
+		"vehicle_type", "fuel_type", "cargo_capacity", "driver_id", "helper_id", "status", "created_at", "updated_at"}
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM vehicles")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(vehicleID, companyID, nil, "ABC1234", "Volvo", "FH", 2020, nil,
+			"truck", "diesel", nil, nil, nil, "active", time.Now(), time.Now()))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM users")).
+		WithArgs(driverID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT id, driver_id, helper_id, team_id FROM vehicles")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "driver_id", "helper_id", "team_id"}).AddRow(vehicleID, nil, nil, nil))
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE vehicles SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+	anyArg := sqlmock.AnyArg()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicle_assignment_history")).
+		WithArgs(anyArg, vehicleID, companyID, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, "driver", anyArg, "Driver on leave", anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	companySettings := fakeCompanySettingsRepo{}
+	companySettings[companyID] = &models.CompanySettingsRecord{CompanyID: companyID, Settings: models.CompanySettings{RequireAssignmentReason: true}}
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(sqlx.NewDb(mockDB, "sqlmock")), nil)
+	handler.SetCompanySettings(companySettings)
+
+	w := assignUsers(handler, vehicleID, companyID, fmt.Sprintf(`{"driver_id": %q, "reason": "  Driver on leave "}`, driverID))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAssignUsers_RequiresReasonWhenCompanyDemandsIt(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	vehicleID, companyID := uuid.New(), uuid.New()
+	companySettings := fakeCompanySettingsRepo{}
+	companySettings[companyID] = &models.CompanySettingsRecord{CompanyID: companyID, Settings: models.CompanySettings{RequireAssignmentReason: true}}
+	handler := handlers.NewVehicleHandler(repository.NewVehicleRepository(sqlx.NewDb(mockDB, "sqlmock")), nil)
+	handler.SetCompanySettings(companySettings)
+
+	for _, body := range []string{`{"driver_id": null}`, `{"driver_id": null, "reason": "   "}`} {
+		w := assignUsers(handler, vehicleID, companyID, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
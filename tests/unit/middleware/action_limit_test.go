@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+func newExportTestRouter(limiter *middleware.ActionRateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-Test-User"))
+		c.Next()
+	})
+	router.GET("/export", limiter.Middleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router
+}
+
+func doExport(router *gin.Engine, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/export", nil)
+	req.Header.Set("X-Test-User", userID)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestActionRateLimiter_BlocksRepeatedExports(t *testing.T) {
+	router := newExportTestRouter(middleware.NewActionRateLimiter("Export", 2, time.Minute))
+
+	assert.Equal(t, http.StatusOK, doExport(router, "user-a").Code)
+	assert.Equal(t, http.StatusOK, doExport(router, "user-a").Code)
+
+	w := doExport(router, "user-a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Budgets are per user
+	assert.Equal(t, http.StatusOK, doExport(router, "user-b").Code)
+}
+
+func TestActionRateLimiter_ResetsAfterWindow(t *testing.T) {
+	router := newExportTestRouter(middleware.NewActionRateLimiter("Export", 1, 20*time.Millisecond))
+
+	assert.Equal(t, http.StatusOK, doExport(router, "user-a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doExport(router, "user-a").Code)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, doExport(router, "user-a").Code)
+}
+
+func TestActionRateLimiter_DisabledWhenLimitIsZero(t *testing.T) {
+	router := newExportTestRouter(middleware.NewActionRateLimiter("Export", 0, time.Minute))
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, doExport(router, "user-a").Code)
+	}
+}
+
+func TestActionRateLimiter_NamesTheLimitedAction(t *testing.T) {
+	limiter := middleware.NewActionRateLimiter("Test email", 1, time.Minute)
+	router := newExportTestRouter(limiter)
+
+	assert.Equal(t, http.StatusOK, doExport(router, "user-a").Code)
+	w := doExport(router, "user-a")
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "Test email rate limit exceeded")
+}
+
+func TestActionRateLimiter_EvictsExpiredWindows(t *testing.T) {
+	limiter := middleware.NewActionRateLimiter("Export", 1, 20*time.Millisecond)
+	router := newExportTestRouter(limiter)
+
+	doExport(router, "user-a")
+	doExport(router, "user-b")
+	assert.Equal(t, 2, limiter.Size())
+
+	time.Sleep(30 * time.Millisecond)
+	doExport(router, "user-c")
+
+	assert.Equal(t, 1, limiter.Size())
+}
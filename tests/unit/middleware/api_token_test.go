@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// fakeAPITokens resolves a fixed set of tokens
+type fakeAPITokens map[string]*models.User
+
+func (f fakeAPITokens) GetByAPIToken(ctx context.Context, token string) (*models.User, error) {
+	return f[token], nil
+}
+
+func TestRequireAPIToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	companyID := uuid.New()
+	device := &models.User{ID: uuid.New(), CompanyID: &companyID, Role: &models.Role{Name: "driver"}}
+
+	auth := middleware.NewGinAuthMiddleware(nil)
+	auth.SetAPITokenLookup(fakeAPITokens{"device-token": device})
+
+	router := gin.New()
+	router.POST("/points", auth.RequireAPIToken(), middleware.RequireCompanyAccess(), func(c *gin.Context) {
+		userCtx := c.MustGet("userContext").(*models.UserContext)
+		assert.Equal(t, device.ID, userCtx.UserID)
+		c.Status(http.StatusCreated)
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"unknown token", "other-token", http.StatusUnauthorized},
+		{"valid token", "device-token", http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/points", nil)
+			if tt.token != "" {
+				req.Header.Set(middleware.APITokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
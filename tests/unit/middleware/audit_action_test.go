@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// recordingAuditLogRepository keeps the audit logs passed to Create
+type recordingAuditLogRepository struct {
+	repository.AuditLogRepositoryInterface
+	logs []*models.AuditLog
+}
+
+func (r *recordingAuditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func newAuditActionRouter(repo *recordingAuditLogRepository, userID uuid.UUID, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Set("email", "driver@fleet.com")
+		c.Next()
+	})
+	router.POST("/api/v1/auth/logout", middleware.AuditAction(repo, "logout", "session"), handler)
+	return router
+}
+
+func TestAuditAction_RecordsRequestAndHandlerMetadata(t *testing.T) {
+	repo := &recordingAuditLogRepository{}
+	userID := uuid.New()
+	sessionID := uuid.New().String()
+
+	router := newAuditActionRouter(repo, userID, func(c *gin.Context) {
+		middleware.SetAuditResourceID(c, sessionID)
+		middleware.SetAuditMetadata(c, "session_id", sessionID)
+		c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, repo.logs, 1)
+	log := repo.logs[0]
+	assert.Equal(t, "logout", log.Action)
+	assert.Equal(t, "session", log.Resource)
+	require.NotNil(t, log.UserID)
+	assert.Equal(t, userID, *log.UserID)
+	require.NotNil(t, log.UserEmail)
+	assert.Equal(t, "driver@fleet.com", *log.UserEmail)
+	require.NotNil(t, log.Method)
+	assert.Equal(t, http.MethodPost, *log.Method)
+	require.NotNil(t, log.Path)
+	assert.Equal(t, "/api/v1/auth/logout", *log.Path)
+	require.NotNil(t, log.StatusCode)
+	assert.Equal(t, http.StatusOK, *log.StatusCode)
+	assert.NotNil(t, log.DurationMs)
+	assert.True(t, log.Success)
+	require.NotNil(t, log.ResourceID)
+	assert.Equal(t, sessionID, *log.ResourceID)
+	assert.Equal(t, sessionID, log.Metadata["session_id"])
+}
+
+func TestAuditAction_RecordsFailedRequests(t *testing.T) {
+	repo := &recordingAuditLogRepository{}
+
+	router := newAuditActionRouter(repo, uuid.New(), func(c *gin.Context) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil))
+
+	require.Len(t, repo.logs, 1)
+	assert.False(t, repo.logs[0].Success)
+	assert.Equal(t, http.StatusUnauthorized, *repo.logs[0].StatusCode)
+	assert.Nil(t, repo.logs[0].ResourceID)
+	assert.Nil(t, repo.logs[0].Metadata)
+}
@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+func newCompanyTestContext() *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return c
+}
+
+func TestGetCompanyIDFromContext_CanonicalKey(t *testing.T) {
+	c := newCompanyTestContext()
+	companyID := uuid.New()
+	middleware.SetCompanyID(c, companyID)
+
+	got, err := middleware.GetCompanyIDFromContext(c)
+
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, companyID, *got)
+}
+
+func TestGetCompanyIDFromContext_RequestCompanyWinsOverScope(t *testing.T) {
+	c := newCompanyTestContext()
+	requested := uuid.New()
+	own := uuid.New()
+	c.Set(middleware.ScopeCompanyIDKey, own)
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), CompanyID: &own})
+	middleware.SetCompanyID(c, requested)
+
+	got, err := middleware.GetCompanyIDFromContext(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, requested, *got)
+}
+
+func TestGetCompanyIDFromContext_FallsBackToUserContext(t *testing.T) {
+	c := newCompanyTestContext()
+	own := uuid.New()
+	c.Set("userContext", &models.UserContext{UserID: uuid.New(), CompanyID: &own})
+
+	got, err := middleware.GetCompanyIDFromContext(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, own, *got)
+}
+
+func TestGetCompanyIDFromContext_NoCompany(t *testing.T) {
+	got, err := middleware.GetCompanyIDFromContext(newCompanyTestContext())
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestGetCompanyIDFromContext_WrongTypeErrors(t *testing.T) {
+	c := newCompanyTestContext()
+	c.Set(middleware.CompanyIDKey, uuid.New().String())
+
+	got, err := middleware.GetCompanyIDFromContext(c)
+
+	assert.Nil(t, got)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "holds string, want uuid.UUID")
+}
+
+func TestGetCompanyIDFromContext_LegacyKeyErrors(t *testing.T) {
+	c := newCompanyTestContext()
+	c.Set("company_id", uuid.New())
+
+	got, err := middleware.GetCompanyIDFromContext(c)
+
+	assert.Nil(t, got)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SetCompanyID")
+}
@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func newContentTypeTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequireJSONContentType("/upload"))
+	router.POST("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.PUT("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/upload", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRequireJSONContentType(t *testing.T) {
+	router := newContentTypeTestRouter()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		expected    int
+	}{
+		{"text/plain body is rejected", http.MethodPost, "/resource", "text/plain", "name=x", http.StatusUnsupportedMediaType},
+		{"form body is rejected", http.MethodPut, "/resource", "application/x-www-form-urlencoded", "name=x", http.StatusUnsupportedMediaType},
+		{"missing content type is rejected", http.MethodPost, "/resource", "", `{"name":"x"}`, http.StatusUnsupportedMediaType},
+		{"json body passes", http.MethodPost, "/resource", "application/json", `{"name":"x"}`, http.StatusOK},
+		{"json with charset passes", http.MethodPost, "/resource", "application/json; charset=utf-8", `{"name":"x"}`, http.StatusOK},
+		{"empty body passes", http.MethodPost, "/resource", "", "", http.StatusOK},
+		{"GET is not checked", http.MethodGet, "/resource", "text/plain", "", http.StatusOK},
+		{"exempt upload route passes", http.MethodPost, "/upload", "text/csv", "a,b", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusUnsupportedMediaType {
+				assert.Contains(t, w.Body.String(), utils.ErrCodeUnsupportedMediaType)
+			}
+		})
+	}
+}
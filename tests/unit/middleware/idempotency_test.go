@@ -0,0 +1,250 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// memoryIdempotencyStore honours expires_at the way the SQL repository does
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+}
+
+func (s *memoryIdempotencyStore) Get(_ context.Context, scope, key string) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[scope+"|"+key]
+	if !ok || !record.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return record, nil
+}
+
+func (s *memoryIdempotencyStore) Claim(_ context.Context, record *models.IdempotencyRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.records[record.Scope+"|"+record.Key]; ok && held.ExpiresAt.After(time.Now()) {
+		return false, nil
+	}
+	claim := *record
+	s.records[record.Scope+"|"+record.Key] = &claim
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, record *models.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	completed := *record
+	s.records[record.Scope+"|"+record.Key] = &completed
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.records[scope+"|"+key]; ok && held.Pending() {
+		delete(s.records, scope+"|"+key)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for k, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, k)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newIdempotencyTestRouter(store *memoryIdempotencyStore, ttl time.Duration, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Idempotency(store, ttl))
+	router.POST("/orders", func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusCreated, gin.H{"order": *calls})
+	})
+	return router
+}
+
+func postOrder(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token-a")
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysWithinTTL(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(newMemoryIdempotencyStore(), time.Hour, &calls)
+
+	first := postOrder(router, "key-1", `{"item":"a"}`)
+	second := postOrder(router, "key-1", `{"item":"a"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayHeader))
+	assert.Empty(t, first.Header().Get(middleware.IdempotentReplayHeader))
+}
+
+func TestIdempotency_ExpiredKeyIsPurgedAndRunsAgain(t *testing.T) {
+	calls := 0
+	store := newMemoryIdempotencyStore()
+	router := newIdempotencyTestRouter(store, 20*time.Millisecond, &calls)
+
+	postOrder(router, "key-1", `{"item":"a"}`)
+	time.Sleep(30 * time.Millisecond)
+
+	deleted, err := store.DeleteExpired(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	w := postOrder(router, "key-1", `{"item":"a"}`)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, w.Header().Get(middleware.IdempotentReplayHeader))
+}
+
+func TestIdempotency_KeyReusedForDifferentBody(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(newMemoryIdempotencyStore(), time.Hour, &calls)
+
+	postOrder(router, "key-1", `{"item":"a"}`)
+	w := postOrder(router, "key-1", `{"item":"b"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), middleware.ErrCodeIdempotencyKeyReused)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_RequestsWithoutKeyAlwaysRun(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(newMemoryIdempotencyStore(), time.Hour, &calls)
+
+	postOrder(router, "", `{"item":"a"}`)
+	postOrder(router, "", `{"item":"a"}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_AuthResponsesAreNotStored(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := newMemoryIdempotencyStore()
+	router := gin.New()
+	router.Use(middleware.Idempotency(store, time.Hour))
+	router.POST("/api/v1/auth/login", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"access_token": "secret-token"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{}`))
+	req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, store.records)
+}
+
+func TestIdempotency_ResponsesMarkedNoStoreRunAgain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	store := newMemoryIdempotencyStore()
+	router := gin.New()
+	router.Use(middleware.Idempotency(store, time.Hour))
+	router.POST("/orders", func(c *gin.Context) {
+		calls++
+		middleware.NoStoreIdempotentResponse(c)
+		c.JSON(http.StatusCreated, gin.H{"secret": calls})
+	})
+
+	postOrder(router, "key-1", `{"item":"a"}`)
+	w := postOrder(router, "key-1", `{"item":"a"}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, w.Header().Get(middleware.IdempotentReplayHeader))
+	assert.Empty(t, store.records)
+}
+
+func TestIdempotency_ConcurrentRequestWithSameKeyGetsConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	router := gin.New()
+	router.Use(middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour))
+	router.POST("/orders", func(c *gin.Context) {
+		calls.Add(1)
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"order": 1})
+	})
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- postOrder(router, "key-1", `{"item":"a"}`) }()
+	<-started
+
+	second := postOrder(router, "key-1", `{"item":"a"}`)
+	close(release)
+
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.Contains(t, second.Body.String(), middleware.ErrCodeIdempotencyKeyInProgress)
+	assert.Equal(t, http.StatusCreated, (<-first).Code)
+	assert.Equal(t, int32(1), calls.Load())
+
+	replay := postOrder(router, "key-1", `{"item":"a"}`)
+	assert.Equal(t, "true", replay.Header().Get(middleware.IdempotentReplayHeader))
+}
+
+func TestIdempotency_FailedResponseReleasesKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	store := newMemoryIdempotencyStore()
+	router := gin.New()
+	router.Use(middleware.Idempotency(store, time.Hour))
+	router.POST("/orders", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "try again"})
+	})
+
+	postOrder(router, "key-1", `{"item":"a"}`)
+	w := postOrder(router, "key-1", `{"item":"a"}`)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.records)
+}
+
+func TestIdempotency_OversizedBodyRejected(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(newMemoryIdempotencyStore(), time.Hour, &calls)
+
+	w := postOrder(router, "key-1", strings.Repeat("a", 4<<20+1))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 0, calls)
+}
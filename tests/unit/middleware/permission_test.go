@@ -0,0 +1,102 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+// fakePermissions grants the permissions listed per role
+type fakePermissions map[string][]string
+
+func (f fakePermissions) HasPermission(ctx context.Context, role, permission string) bool {
+	for _, granted := range f[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// withUser stands in for RequireAuth
+func withUser(userID uuid.UUID, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Set("role_name", role)
+		c.Next()
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := middleware.NewGinAuthMiddleware(nil)
+	auth.SetPermissionChecker(fakePermissions{"admin": {models.PermissionUserReadHistory}})
+
+	tests := []struct {
+		role     string
+		expected int
+	}{
+		{"admin", http.StatusOK},
+		{"driver", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/audit", withUser(uuid.New(), tt.role), auth.RequirePermission(models.PermissionUserReadHistory), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit", nil))
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestRequireSelfOrPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	self := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name     string
+		role     string
+		target   string
+		checker  middleware.PermissionChecker
+		expected int
+	}{
+		{"own history", "driver", self.String(), fakePermissions{}, http.StatusOK},
+		{"other user without permission", "driver", other.String(), fakePermissions{}, http.StatusForbidden},
+		{"other user with permission", "admin", other.String(), fakePermissions{"admin": {models.PermissionUserReadHistory}}, http.StatusOK},
+		{"no policy configured", "admin", other.String(), nil, http.StatusForbidden},
+		{"invalid id left to the handler", "driver", "not-a-uuid", fakePermissions{}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := middleware.NewGinAuthMiddleware(nil)
+			if tt.checker != nil {
+				auth.SetPermissionChecker(tt.checker)
+			}
+			router := gin.New()
+			router.GET("/users/:id/history", withUser(self, tt.role), auth.RequireSelfOrPermission("id", models.PermissionUserReadHistory), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+tt.target+"/history", nil))
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+)
+
+func newCSRFTestRouter(cookies *middleware.SessionCookies) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(cookies.CSRFProtection())
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func cookieRequest(method string, cookies ...*http.Cookie) *http.Request {
+	req := httptest.NewRequest(method, "/resource", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestCSRFProtection(t *testing.T) {
+	router := newCSRFTestRouter(middleware.NewSessionCookies("", true, "strict"))
+	accessCookie := &http.Cookie{Name: middleware.AccessTokenCookie, Value: "access-token"}
+	csrfCookie := &http.Cookie{Name: middleware.CSRFTokenCookie, Value: "csrf-token"}
+
+	t.Run("cookie-authenticated POST without CSRF header is rejected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, cookieRequest(http.MethodPost, accessCookie, csrfCookie))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "CSRF token missing or invalid")
+	})
+
+	t.Run("mismatched CSRF header is rejected", func(t *testing.T) {
+		req := cookieRequest(http.MethodPost, accessCookie, csrfCookie)
+		req.Header.Set(middleware.CSRFTokenHeader, "other-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("matching CSRF header passes", func(t *testing.T) {
+		req := cookieRequest(http.MethodPost, accessCookie, csrfCookie)
+		req.Header.Set(middleware.CSRFTokenHeader, "csrf-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("safe methods are not checked", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, cookieRequest(http.MethodGet, accessCookie))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("header-based clients are not affected", func(t *testing.T) {
+		req := cookieRequest(http.MethodPost, accessCookie)
+		req.Header.Set("Authorization", "Bearer access-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("requests without session cookies are not affected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, cookieRequest(http.MethodPost))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestSessionCookies_SetTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	cookies := middleware.NewSessionCookies("app.example.com", true, "lax")
+	require.NoError(t, cookies.SetTokens(c, "access-token", "refresh-token", 15*time.Minute, 24*time.Hour))
+
+	set := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		set[cookie.Name] = cookie
+	}
+
+	access := set[middleware.AccessTokenCookie]
+	require.NotNil(t, access)
+	assert.Equal(t, "access-token", access.Value)
+	assert.True(t, access.HttpOnly)
+	assert.True(t, access.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, access.SameSite)
+	assert.Equal(t, 900, access.MaxAge)
+
+	refresh := set[middleware.RefreshTokenCookie]
+	require.NotNil(t, refresh)
+	assert.True(t, refresh.HttpOnly)
+	assert.Equal(t, "/api/v1/auth/refresh", refresh.Path)
+
+	csrf := set[middleware.CSRFTokenCookie]
+	require.NotNil(t, csrf)
+	assert.False(t, csrf.HttpOnly, "the web client must be able to read the CSRF cookie")
+	assert.Len(t, csrf.Value, 64)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.CORSMiddleware([]string{"https://app.example.com"}))
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("preflight from an allowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/resource", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), middleware.CSRFTokenHeader)
+	})
+
+	t.Run("other origins get no CORS headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
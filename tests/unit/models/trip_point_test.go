@@ -0,0 +1,22 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+func TestTripPointsDistanceKm(t *testing.T) {
+	// One degree of longitude on the equator is about 111.19 km
+	points := []models.TripPoint{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 0.5},
+		{Latitude: 0, Longitude: 1},
+	}
+
+	assert.InDelta(t, 111.19, models.TripPointsDistanceKm(points), 0.01)
+	assert.Zero(t, models.TripPointsDistanceKm(points[:1]))
+	assert.Zero(t, models.TripPointsDistanceKm(nil))
+}
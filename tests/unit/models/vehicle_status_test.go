@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+)
+
+func TestVehicle_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{"active", "inactive", true},
+		{"inactive", "active", true},
+		{"active", "maintenance", true},
+		{"maintenance", "active", true},
+		{"inactive", "retired", true},
+		{"active", "active", true},
+		{"retired", "retired", true},
+		{"retired", "active", false},
+		{"retired", "maintenance", false},
+		{"active", "actve", false},
+		{"active", "deleted", false},
+		{"active", "", false},
+		{"actve", "active", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			vehicle := &models.Vehicle{Status: tt.from}
+			assert.Equal(t, tt.allowed, vehicle.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestIsValidVehicleStatus(t *testing.T) {
+	for _, status := range models.VehicleStatuses {
+		assert.True(t, models.IsValidVehicleStatus(status), status)
+	}
+	assert.False(t, models.IsValidVehicleStatus("Active"))
+	assert.False(t, models.IsValidVehicleStatus("deleted"))
+}
@@ -0,0 +1,342 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/middleware"
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// jsonArg matches a JSON column argument by its decoded value
+type jsonArg struct {
+	expected map[string]interface{}
+}
+
+func (a jsonArg) Match(v driver.Value) bool {
+	raw, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(a.expected, got)
+}
+
+// AuditLogRepositoryTestSuite defines the test suite for AuditLogRepository
+type AuditLogRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.AuditLogRepository
+}
+
+func (suite *AuditLogRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewAuditLogRepository(suite.db)
+}
+
+func (suite *AuditLogRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_PersistsMetadataJSON() {
+	ctx := context.Background()
+	userID := uuid.New()
+	method := "POST"
+	path := "/api/v1/auth/logout"
+	statusCode := 200
+
+	log := &models.AuditLog{
+		UserID:     &userID,
+		Action:     "logout",
+		Resource:   "session",
+		Method:     &method,
+		Path:       &path,
+		IPAddress:  "127.0.0.1",
+		UserAgent:  "test-agent",
+		Success:    true,
+		StatusCode: &statusCode,
+		Metadata: map[string]interface{}{
+			"session_id":               "abc",
+			"session_duration_minutes": 12.5,
+		},
+	}
+
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WithArgs(
+			sqlmock.AnyArg(), // id - generated when unset
+			userID,
+			nil, // user_email
+			nil, // company_id
+			"logout",
+			"session",
+			nil, // resource_id
+			method,
+			path,
+			"127.0.0.1",
+			"test-agent",
+			nil, // changes - empty maps are stored as NULL
+			jsonArg{expected: map[string]interface{}{
+				"session_id":               "abc",
+				"session_duration_minutes": 12.5,
+			}},
+			true,
+			nil, // error_message
+			statusCode,
+			nil,              // duration_ms
+			nil,              // trace_id
+			nil,              // span_id
+			sqlmock.AnyArg(), // created_at - defaulted when unset
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.repo.Create(ctx, log)
+
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), uuid.Nil, log.ID)
+	assert.False(suite.T(), log.CreatedAt.IsZero())
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreateTx_UsesTransaction() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	tx, err := suite.db.BeginTxx(ctx, nil)
+	suite.Require().NoError(err)
+
+	err = suite.repo.CreateTx(ctx, tx, &models.AuditLog{
+		UserID:   &userID,
+		Action:   "password_change",
+		Resource: "user",
+		Success:  true,
+		Metadata: map[string]interface{}{"change_method": "manual"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), tx.Commit())
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_DatabaseError() {
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WillReturnError(sql.ErrConnDone)
+
+	err := suite.repo.Create(context.Background(), &models.AuditLog{Action: "logout", Resource: "session"})
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "failed to create audit log")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_RedactsSensitiveMetadata() {
+	userID := uuid.New()
+
+	metadata := map[string]interface{}{
+		"change_method": "manual",
+		"password":      "hunter2",
+		"Access_Token":  "eyJhbGciOi",
+		"request": map[string]interface{}{
+			"new_password": "hunter3",
+			"ip_address":   "127.0.0.1",
+		},
+	}
+
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WithArgs(
+			sqlmock.AnyArg(), userID, nil, nil, "password_change", "user", nil,
+			nil, nil, "", "", nil,
+			jsonArg{expected: map[string]interface{}{
+				"change_method": "manual",
+				"request": map[string]interface{}{
+					"ip_address": "127.0.0.1",
+				},
+			}},
+			true, nil, nil, nil, nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.repo.Create(context.Background(), &models.AuditLog{
+		UserID:   &userID,
+		Action:   "password_change",
+		Resource: "user",
+		Success:  true,
+		Metadata: metadata,
+	})
+
+	assert.NoError(suite.T(), err)
+	// The caller's map is left untouched
+	assert.Equal(suite.T(), "hunter2", metadata["password"])
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_RedactsConfiguredKeys() {
+	suite.repo.SetRedactedKeys([]string{"email"})
+
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WithArgs(
+			sqlmock.AnyArg(), nil, nil, nil, "login", "auth", nil,
+			nil, nil, "", "", nil,
+			jsonArg{expected: map[string]interface{}{"password": "kept"}},
+			true, nil, nil, nil, nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.repo.Create(context.Background(), &models.AuditLog{
+		Action:   "login",
+		Resource: "auth",
+		Success:  true,
+		Metadata: map[string]interface{}{"email": "user@example.com", "password": "kept"},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// createWithinRequest runs repo.Create from a handler that first sets the given response status
+func (suite *AuditLogRepositoryTestSuite) createWithinRequest(method, path string, status int, log *models.AuditLog) error {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestInfoMiddleware())
+
+	var createErr error
+	router.Handle(method, path, func(c *gin.Context) {
+		c.Status(status)
+		createErr = suite.repo.Create(c.Request.Context(), log)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+	return createErr
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_CapturesRequestInfo() {
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WithArgs(
+			sqlmock.AnyArg(), nil, nil, nil, "vehicle_created", "vehicle", nil,
+			"POST", "/api/v1/company-admin/vehicles", "", "", nil, nil,
+			true, nil, http.StatusCreated, nil, nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	log := &models.AuditLog{Action: "vehicle_created", Resource: "vehicle", Success: true}
+	err := suite.createWithinRequest(http.MethodPost, "/api/v1/company-admin/vehicles", http.StatusCreated, log)
+
+	assert.NoError(suite.T(), err)
+	suite.Require().NotNil(log.StatusCode)
+	assert.Equal(suite.T(), http.StatusCreated, *log.StatusCode)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_ErrorStatusMarksFailure() {
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WithArgs(
+			sqlmock.AnyArg(), nil, nil, nil, "password_change", "user", nil,
+			"POST", "/api/v1/profile/change-password", "", "", nil, nil,
+			false, nil, http.StatusUnauthorized, nil, nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	log := &models.AuditLog{Action: "password_change", Resource: "user", Success: true}
+	err := suite.createWithinRequest(http.MethodPost, "/api/v1/profile/change-password", http.StatusUnauthorized, log)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), log.Success)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCreate_KeepsExplicitRequestFields() {
+	method := "DELETE"
+	status := http.StatusNoContent
+
+	suite.mock.ExpectExec(`INSERT INTO audit_logs`).
+		WithArgs(
+			sqlmock.AnyArg(), nil, nil, nil, "logout", "session", nil,
+			"DELETE", "/api/v1/auth/logout", "", "", nil, nil,
+			true, nil, http.StatusNoContent, nil, nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	log := &models.AuditLog{Action: "logout", Resource: "session", Method: &method, StatusCode: &status, Success: true}
+	err := suite.createWithinRequest(http.MethodPost, "/api/v1/auth/logout", http.StatusOK, log)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestList_FiltersByMetadataContainment() {
+	sessionID := uuid.New().String()
+	logID := uuid.New()
+
+	columns := []string{
+		"id", "user_id", "user_email", "company_id", "action", "resource", "resource_id",
+		"method", "path", "ip_address", "user_agent", "changes", "metadata",
+		"success", "error_message", "status_code", "duration_ms", "trace_id", "span_id", "created_at",
+	}
+	rows := sqlmock.NewRows(columns).AddRow(
+		logID, nil, nil, nil, "logout", "session", nil,
+		"POST", "/api/v1/auth/logout", "127.0.0.1", "test-agent", nil, []byte(`{"session_id":"`+sessionID+`","session_duration_minutes":5}`),
+		true, nil, 200, nil, nil, nil, time.Now(),
+	)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("AND action = $1 AND metadata @> $2 ORDER BY created_at DESC LIMIT $3")).
+		WithArgs("logout", jsonArg{expected: map[string]interface{}{"session_id": sessionID}}, 50).
+		WillReturnRows(rows)
+
+	action := "logout"
+	logs, err := suite.repo.List(context.Background(), &models.AuditLogFilter{
+		Action:   &action,
+		Metadata: map[string]interface{}{"session_id": sessionID},
+		Limit:    50,
+	})
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(logs, 1)
+	assert.Equal(suite.T(), logID, logs[0].ID)
+	assert.Equal(suite.T(), sessionID, logs[0].Metadata["session_id"])
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AuditLogRepositoryTestSuite) TestCount_FiltersByMetadataContainment() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM audit_logs WHERE 1=1 AND metadata @> $1")).
+		WithArgs(jsonArg{expected: map[string]interface{}{"session_id": "abc"}}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := suite.repo.Count(context.Background(), &models.AuditLogFilter{
+		Metadata: map[string]interface{}{"session_id": "abc"},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestAuditLogRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditLogRepositoryTestSuite))
+}
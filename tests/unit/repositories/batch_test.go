@@ -0,0 +1,18 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func TestCheckBatchSize(t *testing.T) {
+	assert.NoError(t, repository.CheckBatchSize(0, 10))
+	assert.NoError(t, repository.CheckBatchSize(10, 10))
+
+	err := repository.CheckBatchSize(11, 10)
+	assert.ErrorIs(t, err, repository.ErrBatchTooLarge)
+	assert.Contains(t, err.Error(), "at most 10")
+}
@@ -0,0 +1,93 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// CompanyRepositoryTestSuite defines the test suite for CompanyRepository
+type CompanyRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.CompanyRepository
+}
+
+func (suite *CompanyRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewCompanyRepository(suite.db)
+}
+
+func (suite *CompanyRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *CompanyRepositoryTestSuite) TestDelete_BlockedByActiveDependents() {
+	id := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM companies WHERE id = $1 FOR UPDATE")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("AS active_users")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"active_users", "active_vehicles", "active_teams"}).AddRow(3, 0, 1))
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.Delete(context.Background(), id)
+
+	suite.Error(err)
+	suite.True(errors.Is(err, repository.ErrCompanyHasDependents))
+	suite.Contains(err.Error(), "users: 3, vehicles: 0, teams: 1")
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *CompanyRepositoryTestSuite) TestDelete_SoftDeletesCompanyWithoutDependents() {
+	id := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM companies")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("AS active_users")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"active_users", "active_vehicles", "active_teams"}).AddRow(0, 0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE companies SET status = 'inactive', deleted_at = NOW()")).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Delete(context.Background(), id)
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *CompanyRepositoryTestSuite) TestDelete_NotFound() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM companies")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.Delete(context.Background(), uuid.New())
+
+	suite.EqualError(err, "company not found")
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestCompanyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CompanyRepositoryTestSuite))
+}
@@ -0,0 +1,80 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func TestCompanySettingsRepository_GetWithoutRowReturnsDefaults(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewCompanySettingsRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	companyID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM company_settings WHERE company_id = $1")).
+		WithArgs(companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"settings", "updated_by", "updated_at"}))
+
+	record, err := repo.Get(context.Background(), companyID)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultCompanySettings(), record.Settings)
+	assert.Nil(t, record.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompanySettingsRepository_GetFillsUnsetKeysWithDefaults(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewCompanySettingsRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	companyID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM company_settings WHERE company_id = $1")).
+		WithArgs(companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"settings", "updated_by", "updated_at"}).
+			AddRow([]byte(`{"password_max_age_days": 30}`), nil, time.Now()))
+
+	record, err := repo.Get(context.Background(), companyID)
+
+	require.NoError(t, err)
+	require.NotNil(t, record.Settings.PasswordMaxAgeDays)
+	assert.Equal(t, 30, *record.Settings.PasswordMaxAgeDays)
+	assert.Equal(t, models.DefaultMaxSessionsPerUser, record.Settings.MaxSessionsPerUser)
+	assert.True(t, record.Settings.EnforceIPAllowlist)
+	assert.Nil(t, record.UpdatedBy)
+	assert.NotNil(t, record.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompanySettingsRepository_Upsert(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewCompanySettingsRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	companyID, adminID := uuid.New(), uuid.New()
+	settings := models.CompanySettings{MaxSessionsPerUser: 5, EnforceIPAllowlist: false}
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (company_id) DO UPDATE")).
+		WithArgs(companyID, []byte(`{"max_sessions_per_user":5,"enforce_ip_allowlist":false,"password_max_age_days":null,"require_assignment_reason":false}`), &adminID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	record, err := repo.Upsert(context.Background(), companyID, settings, &adminID)
+
+	require.NoError(t, err)
+	assert.Equal(t, settings, record.Settings)
+	assert.Equal(t, &adminID, record.UpdatedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
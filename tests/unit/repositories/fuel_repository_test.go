@@ -0,0 +1,145 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// FuelRepositoryTestSuite defines the test suite for FuelRepository
+type FuelRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.FuelRepository
+}
+
+func (suite *FuelRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewFuelRepository(suite.db)
+}
+
+func (suite *FuelRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *FuelRepositoryTestSuite) TestCreateOdometerReading_AdvancesVehicleOdometer() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT odometer FROM vehicles")+".*"+regexp.QuoteMeta("FOR UPDATE")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"odometer"}).AddRow(52000))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO odometer_readings")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE vehicles SET odometer = $1")).
+		WithArgs(52500, vehicleID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	reading := &models.OdometerReading{VehicleID: vehicleID, OdometerKm: 52500, RecordedAt: time.Now(), Source: "manual"}
+	err := suite.repo.CreateOdometerReading(context.Background(), reading, companyID)
+
+	suite.NoError(err)
+	suite.NotEqual(uuid.Nil, reading.ID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FuelRepositoryTestSuite) TestCreateOdometerReading_RejectsLowerReading() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT odometer FROM vehicles")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"odometer"}).AddRow(52000))
+	suite.mock.ExpectRollback()
+
+	reading := &models.OdometerReading{VehicleID: vehicleID, OdometerKm: 51000, RecordedAt: time.Now(), Source: "manual"}
+	err := suite.repo.CreateOdometerReading(context.Background(), reading, companyID)
+
+	suite.Error(err)
+	suite.True(errors.Is(err, repository.ErrOdometerDecreased))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FuelRepositoryTestSuite) TestCreateFuelLog_FirstReadingAllowedWithoutOdometer() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT odometer FROM vehicles")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"odometer"}).AddRow(nil))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO odometer_readings")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE vehicles SET odometer = $1")).
+		WithArgs(1200, vehicleID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO fuel_logs")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	log := &models.FuelLog{VehicleID: vehicleID, Liters: 40, Cost: 240, OdometerKm: 1200, FilledAt: time.Now()}
+	err := suite.repo.CreateFuelLog(context.Background(), log, companyID)
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FuelRepositoryTestSuite) TestGetFuelEfficiency_ComputesKmPerLiter() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	rows := sqlmock.NewRows([]string{"fill_count", "distance_km", "liters_consumed", "total_liters", "total_cost"}).
+		AddRow(3, 1000, 80.0, 120.0, 720.0)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SUM(liters) FILTER (WHERE rn > 1)")).
+		WithArgs(vehicleID, companyID, from, to).
+		WillReturnRows(rows)
+
+	efficiency, err := suite.repo.GetFuelEfficiency(context.Background(), vehicleID, companyID, from, to)
+
+	suite.NoError(err)
+	suite.Require().NotNil(efficiency.KmPerLiter)
+	suite.InDelta(12.5, *efficiency.KmPerLiter, 0.0001)
+	suite.Require().NotNil(efficiency.CostPerKm)
+	suite.InDelta(0.72, *efficiency.CostPerKm, 0.0001)
+	suite.Equal(vehicleID, efficiency.VehicleID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FuelRepositoryTestSuite) TestGetFuelEfficiency_SingleFillHasNoEfficiency() {
+	rows := sqlmock.NewRows([]string{"fill_count", "distance_km", "liters_consumed", "total_liters", "total_cost"}).
+		AddRow(1, 0, 0.0, 40.0, 240.0)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM fills")).WillReturnRows(rows)
+
+	efficiency, err := suite.repo.GetFuelEfficiency(context.Background(), uuid.New(), uuid.New(), time.Now().AddDate(0, 0, -30), time.Now())
+
+	suite.NoError(err)
+	suite.Nil(efficiency.KmPerLiter)
+	suite.Nil(efficiency.CostPerKm)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestFuelRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FuelRepositoryTestSuite))
+}
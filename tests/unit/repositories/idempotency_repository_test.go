@@ -0,0 +1,84 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIdempotencyRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE expires_at <= $1")).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_GetIgnoresExpiredKeys(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIdempotencyRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("expires_at > NOW()")).
+		WithArgs("scope", "key").
+		WillReturnRows(sqlmock.NewRows([]string{"scope"}))
+
+	record, err := repo.Get(context.Background(), "scope", "key")
+
+	require.NoError(t, err)
+	assert.Nil(t, record)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_ClaimHeldKey(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIdempotencyRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_keys") + ".*" +
+		regexp.QuoteMeta("WHERE idempotency_keys.expires_at <= NOW()")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	claimed, err := repo.Claim(context.Background(), &models.IdempotencyRecord{
+		Scope: "scope", Key: "key", RequestHash: "hash", ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_ReleaseOnlyDropsPendingClaims(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIdempotencyRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2 AND status_code = 0")).
+		WithArgs("scope", "key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Release(context.Background(), "scope", "key"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
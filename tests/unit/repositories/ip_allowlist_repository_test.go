@@ -0,0 +1,69 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func TestIPAllowlistRepository_CreateDuplicateRange(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIPAllowlistRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (company_id, cidr) DO NOTHING")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Create(context.Background(), &models.IPAllowlistEntry{CompanyID: uuid.New(), CIDR: "10.0.0.0/8"})
+
+	assert.True(t, errors.Is(err, repository.ErrIPAllowlistEntryExists))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIPAllowlistRepository_GetByCompany(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIPAllowlistRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	companyID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM company_ip_allowlist")).
+		WithArgs(companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "cidr", "description", "created_by", "created_at"}).
+			AddRow(uuid.New(), companyID, "2001:db8::/32", "HQ", nil, time.Now()))
+
+	entries, err := repo.GetByCompany(context.Background(), companyID)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "2001:db8::/32", entries[0].CIDR)
+	assert.Nil(t, entries[0].CreatedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIPAllowlistRepository_DeleteNotFound(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewIPAllowlistRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM company_ip_allowlist")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), uuid.New(), uuid.New())
+
+	assert.True(t, errors.Is(err, repository.ErrIPAllowlistEntryNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,62 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestAnonymizeUserLogs_UpdatesRowsInPlace(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	userID := uuid.New()
+	email := "driver@fleet.com"
+	anonymized := utils.AnonymizeEmail(email)
+
+	// Only UPDATEs are expected: sqlmock fails the test on any DELETE, so every log row
+	// (and with it the counts per period) is kept
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_logs SET email_attempt = $2, ip_address = NULL, user_agent = NULL")).
+		WithArgs(userID, anonymized, email).
+		WillReturnResult(sqlmock.NewResult(0, 14))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET user_email = $2, ip_address = '', user_agent = NULL")).
+		WithArgs(userID, anonymized, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET changes = '{}'::jsonb")).
+		WithArgs(userID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	counts, err := repository.AnonymizeUserLogs(context.Background(), db, userID, email)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(14), counts.AuthLogs)
+	assert.Equal(t, int64(6), counts.AuditLogs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnonymizeUserLogs_StopsOnError(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_logs")).
+		WillReturnError(errors.New("connection reset"))
+
+	_, err = repository.AnonymizeUserLogs(context.Background(), db, uuid.New(), "driver@fleet.com")
+
+	assert.ErrorContains(t, err, "failed to anonymize auth logs")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// MaintenanceRepositoryTestSuite defines the test suite for MaintenanceRepository
+type MaintenanceRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.MaintenanceRepository
+}
+
+func (suite *MaintenanceRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewMaintenanceRepository(suite.db)
+}
+
+func (suite *MaintenanceRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+var maintenanceColumns = []string{
+	"id", "vehicle_id", "type", "performed_at", "odometer_km", "cost", "notes",
+	"next_service_date", "next_service_odometer_km", "created_by", "created_at", "updated_at",
+}
+
+func (suite *MaintenanceRepositoryTestSuite) TestListByVehicle_ScopedToCompany() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	now := time.Now()
+
+	rows := sqlmock.NewRows(maintenanceColumns).
+		AddRow(uuid.New(), vehicleID, "oil_change", now, 52000, 350.0, "Synthetic oil", nil, 62000, nil, now, now)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("JOIN vehicles v ON v.id = m.vehicle_id")+".*"+
+		regexp.QuoteMeta("WHERE m.vehicle_id = $1 AND v.company_id = $2 AND v.deleted_at IS NULL")).
+		WithArgs(vehicleID, companyID, 50, 0).
+		WillReturnRows(rows)
+
+	records, err := suite.repo.ListByVehicle(context.Background(), vehicleID, companyID, 50, 0)
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(records, 1)
+	assert.Equal(suite.T(), "oil_change", records[0].Type)
+	suite.Require().NotNil(records[0].NextServiceOdometerKm)
+	assert.Equal(suite.T(), 62000, *records[0].NextServiceOdometerKm)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MaintenanceRepositoryTestSuite) TestCreate_SetsIDAndTimestamps() {
+	odometer := 52000
+	record := &models.VehicleMaintenance{
+		VehicleID:   uuid.New(),
+		Type:        "tire_rotation",
+		PerformedAt: time.Now(),
+		OdometerKm:  &odometer,
+	}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicle_maintenance")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.repo.Create(context.Background(), record)
+
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), uuid.Nil, record.ID)
+	assert.False(suite.T(), record.CreatedAt.IsZero())
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MaintenanceRepositoryTestSuite) TestUpdate_NotFoundInCompany() {
+	record := &models.VehicleMaintenance{ID: uuid.New(), VehicleID: uuid.New(), Type: "inspection", PerformedAt: time.Now()}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE vehicle_maintenance m SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.repo.Update(context.Background(), record, uuid.New())
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "not found or not authorized")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MaintenanceRepositoryTestSuite) TestGetDueSoon_UsesLatestRecordPerType() {
+	companyID := uuid.New()
+	vehicleID := uuid.New()
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"vehicle_id", "license_plate", "type", "last_performed_at", "next_service_date",
+		"next_service_odometer_km", "current_odometer_km", "days_remaining", "km_remaining",
+	}).AddRow(vehicleID, "ABC1D23", "oil_change", now, nil, 62000, 61500, nil, 500)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT ON (m.vehicle_id, m.type)")+".*"+
+		regexp.QuoteMeta("l.next_service_date <= CURRENT_DATE + $2::int")+".*"+
+		regexp.QuoteMeta("l.next_service_odometer_km - v.odometer <= $3")).
+		WithArgs(companyID, 30, 1000).
+		WillReturnRows(rows)
+
+	due, err := suite.repo.GetDueSoon(context.Background(), companyID, 30, 1000)
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(due, 1)
+	assert.Equal(suite.T(), "ABC1D23", due[0].LicensePlate)
+	suite.Require().NotNil(due[0].KmRemaining)
+	assert.Equal(suite.T(), 500, *due[0].KmRemaining)
+	assert.Nil(suite.T(), due[0].DaysRemaining)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MaintenanceRepositoryTestSuite) TestGetDueSoon_DatabaseError() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM vehicle_maintenance m")).
+		WillReturnError(sql.ErrConnDone)
+
+	due, err := suite.repo.GetDueSoon(context.Background(), uuid.New(), 30, 1000)
+
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), due)
+	assert.Contains(suite.T(), err.Error(), "failed to get maintenance due soon")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestMaintenanceRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceRepositoryTestSuite))
+}
@@ -0,0 +1,88 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// PasswordHistoryRepositoryTestSuite defines the test suite for PasswordHistoryRepository
+type PasswordHistoryRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.PasswordHistoryRepository
+}
+
+func (suite *PasswordHistoryRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewPasswordHistoryRepository(suite.db)
+}
+
+func (suite *PasswordHistoryRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *PasswordHistoryRepositoryTestSuite) TestGetRecentHashes_NewestFirst() {
+	userID := uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM password_history")+".*"+regexp.QuoteMeta("ORDER BY created_at DESC")).
+		WithArgs(userID, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow("hash-2").AddRow("hash-1"))
+
+	hashes, err := suite.repo.GetRecentHashes(context.Background(), userID, 5)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"hash-2", "hash-1"}, hashes)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PasswordHistoryRepositoryTestSuite) TestAdd_InsertsAndTrims() {
+	userID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO password_history")).
+		WithArgs(sqlmock.AnyArg(), userID, "old-hash").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM password_history")+".*"+regexp.QuoteMeta("LIMIT $2")).
+		WithArgs(userID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Add(context.Background(), userID, "old-hash", 3)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PasswordHistoryRepositoryTestSuite) TestAdd_RollsBackOnInsertError() {
+	userID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO password_history")).
+		WillReturnError(sql.ErrConnDone)
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.Add(context.Background(), userID, "old-hash", 3)
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "failed to add password history")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestPasswordHistoryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordHistoryRepositoryTestSuite))
+}
@@ -0,0 +1,127 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/paulochiaradia/dashtrack/internal/logger"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/tests/testutils"
+)
+
+func TestQueryTimeout_CancelsSlowQueryAndMarksSpan(t *testing.T) {
+	recorder := testutils.InstallSpanRecorder(t)
+
+	repository.SetQueryTimeout(20 * time.Millisecond)
+	t.Cleanup(func() { repository.SetQueryTimeout(repository.DefaultQueryTimeout) })
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewTeamRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM teams")).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	start := time.Now()
+	_, err = repo.GetByID(context.Background(), uuid.New(), uuid.New())
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	require.Len(t, recorder.Ended(), 1)
+	span := recorder.RequireSpan(t, "TeamRepository.GetByID")
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), attribute.Int64("db.query_timeout_ms", 20))
+	assert.Contains(t, span.Attributes(), attribute.Bool("db.query_timed_out", true))
+}
+
+func TestQueryTimeout_FastQueryUnaffected(t *testing.T) {
+	repository.SetQueryTimeout(time.Second)
+	t.Cleanup(func() { repository.SetQueryTimeout(repository.DefaultQueryTimeout) })
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewUserRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := repo.CountUsers(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.WarnLevel)
+	previous := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = previous })
+	return logs
+}
+
+func TestSlowQuery_LoggedWithNameDurationAndTraceID(t *testing.T) {
+	recorder := testutils.InstallSpanRecorder(t)
+	logs := observeLogs(t)
+
+	repository.SetSlowQueryThreshold(10 * time.Millisecond)
+	t.Cleanup(func() { repository.SetSlowQueryThreshold(0) })
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewUserRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).
+		WillDelayFor(30 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	_, err = repo.CountUsers(context.Background(), nil)
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("Slow query").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "UserRepository.CountUsers", fields["query"])
+	assert.GreaterOrEqual(t, fields["duration"], 30*time.Millisecond)
+
+	span := recorder.RequireSpan(t, "UserRepository.CountUsers")
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields["trace_id"])
+}
+
+func TestSlowQuery_FastQueryNotLogged(t *testing.T) {
+	logs := observeLogs(t)
+
+	repository.SetSlowQueryThreshold(time.Second)
+	t.Cleanup(func() { repository.SetSlowQueryThreshold(0) })
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewUserRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	_, err = repo.CountUsers(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Zero(t, logs.Len())
+}
@@ -0,0 +1,113 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// RoleRepositoryTestSuite defines the test suite for RoleRepository
+type RoleRepositoryTestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	repo *repository.RoleRepository
+}
+
+func (suite *RoleRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.mock = mock
+	suite.repo = repository.NewRoleRepository(db)
+}
+
+func (suite *RoleRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *RoleRepositoryTestSuite) TestList_SearchFiltersByName() {
+	now := time.Now()
+	driverID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+		AddRow(driverID, "driver", "Vehicle driver", now, now)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM roles r WHERE 1=1 AND r.name ILIKE $1 ORDER BY r.name, r.id")).
+		WithArgs("%driv%").
+		WillReturnRows(rows)
+
+	roles, err := suite.repo.List(context.Background(), models.RoleFilter{Search: "driv"})
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(roles, 1)
+	assert.Equal(suite.T(), driverID, roles[0].ID)
+	assert.Equal(suite.T(), "driver", roles[0].Name)
+	assert.Nil(suite.T(), roles[0].PermissionCount)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RoleRepositoryTestSuite) TestList_IncludesPermissionCountsAndPaginates() {
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at", "permission_count"}).
+		AddRow(uuid.New(), "helper", "Driver helper", now, now, 3)
+
+	// Counted from role_permissions, never from users, which would expose other tenants
+	suite.mock.ExpectQuery(regexp.QuoteMeta("(SELECT COUNT(*) FROM role_permissions rp WHERE rp.role_id = r.id) AS permission_count FROM roles r WHERE 1=1 ORDER BY r.name, r.id LIMIT $1 OFFSET $2")).
+		WithArgs(1, 2).
+		WillReturnRows(rows)
+
+	roles, err := suite.repo.List(context.Background(), models.RoleFilter{IncludePermissionCount: true, Limit: 1, Offset: 2})
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(roles, 1)
+	suite.Require().NotNil(roles[0].PermissionCount)
+	assert.Equal(suite.T(), 3, *roles[0].PermissionCount)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RoleRepositoryTestSuite) TestList_DatabaseError() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM roles r")).
+		WillReturnError(sql.ErrConnDone)
+
+	roles, err := suite.repo.List(context.Background(), models.RoleFilter{})
+
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), roles)
+	assert.Contains(suite.T(), err.Error(), "failed to list roles")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RoleRepositoryTestSuite) TestListPermissions() {
+	rows := sqlmock.NewRows([]string{"name", "permission"}).
+		AddRow("admin", models.PermissionUserReadHistory).
+		AddRow("master", models.PermissionUserReadHistory)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM role_permissions rp")).
+		WillReturnRows(rows)
+
+	permissions, err := suite.repo.ListPermissions(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.RolePermission{
+		{RoleName: "admin", Permission: models.PermissionUserReadHistory},
+		{RoleName: "master", Permission: models.PermissionUserReadHistory},
+	}, permissions)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestRoleRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleRepositoryTestSuite))
+}
@@ -0,0 +1,46 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func TestSortFieldsParse_AcceptsAllowedKeys(t *testing.T) {
+	sort, err := repository.UserSortFields.Parse("last_login", "DESC")
+	require.NoError(t, err)
+	assert.NotEqual(t, repository.Sort{}, sort)
+
+	sort, err = repository.VehicleSortFields.Parse("license_plate", "")
+	require.NoError(t, err)
+	assert.NotEqual(t, repository.Sort{}, sort)
+}
+
+func TestSortFieldsParse_EmptyKeyKeepsDefaultOrder(t *testing.T) {
+	sort, err := repository.UserSortFields.Parse("", "")
+	require.NoError(t, err)
+	assert.Equal(t, repository.Sort{}, sort)
+}
+
+func TestSortFieldsParse_RejectsUnknownInput(t *testing.T) {
+	cases := map[string][2]string{
+		"unknown key":       {"password", "asc"},
+		"sql in key":        {"name; DROP TABLE users", ""},
+		"column not key":    {"u.name", ""},
+		"invalid order":     {"name", "sideways"},
+		"order without key": {"", "desc"},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := repository.UserSortFields.Parse(input[0], input[1])
+			assert.ErrorIs(t, err, repository.ErrInvalidSort)
+		})
+	}
+}
+
+func TestSortFieldsKeys_Sorted(t *testing.T) {
+	assert.Equal(t, []string{"created_at", "email", "last_login", "name"}, repository.UserSortFields.Keys())
+}
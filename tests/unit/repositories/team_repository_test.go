@@ -0,0 +1,488 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// TeamRepositoryTestSuite defines the test suite for TeamRepository
+type TeamRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.TeamRepository
+}
+
+func (suite *TeamRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewTeamRepository(suite.db)
+}
+
+func (suite *TeamRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *TeamRepositoryTestSuite) TestAddMembers_OneHistoryRowPerAddition() {
+	ctx := context.Background()
+	companyID, teamID := uuid.New(), uuid.New()
+	members := []*models.TeamMember{
+		{TeamID: teamID, UserID: uuid.New(), RoleInTeam: "driver"},
+		{TeamID: teamID, UserID: uuid.New(), RoleInTeam: "helper"},
+	}
+
+	insert := regexp.QuoteMeta("ON CONFLICT (team_id, user_id) WHERE left_at IS NULL DO NOTHING")
+	history := regexp.QuoteMeta("INSERT INTO team_member_history")
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(history).
+		WithArgs(sqlmock.AnyArg(), teamID, members[0].UserID, companyID, nil, sqlmock.AnyArg(), "added",
+			nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Already an active member: no history row
+	suite.mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+
+	added, err := suite.repo.AddMembers(ctx, companyID, members)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []bool{true, false}, added)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestAddMembers_RollsBackOnFailure() {
+	ctx := context.Background()
+	teamID := uuid.New()
+	members := []*models.TeamMember{
+		{TeamID: teamID, UserID: uuid.New(), RoleInTeam: "driver"},
+		{TeamID: teamID, UserID: uuid.New(), RoleInTeam: "helper"},
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_members")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_member_history")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_members")).WillReturnError(sql.ErrConnDone)
+	suite.mock.ExpectRollback()
+
+	added, err := suite.repo.AddMembers(ctx, uuid.New(), members)
+
+	suite.Require().Error(err)
+	assert.Nil(suite.T(), added)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// Two requests move the same driver to helper at once. The member row is locked for the
+// whole read-update-log sequence, so with a single connection the second transaction only
+// starts after the first commits: it reads "helper" and logs nothing, instead of both
+// logging driver -> helper.
+func (suite *TeamRepositoryTestSuite) TestUpdateMemberRole_ConcurrentUpdatesKeepHistoryConsistent() {
+	ctx := context.Background()
+	companyID, teamID, userID := uuid.New(), uuid.New(), uuid.New()
+	suite.db.SetMaxOpenConns(1)
+
+	lockedRead := regexp.QuoteMeta("FOR UPDATE OF tm")
+	roleRow := func(role string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"role_in_team", "company_id"}).AddRow(role, companyID)
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(lockedRead).WithArgs(teamID, userID).WillReturnRows(roleRow("driver"))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE team_members")).
+		WithArgs("helper", teamID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_member_history")).
+		WithArgs(sqlmock.AnyArg(), teamID, userID, companyID, "driver", "helper", "role_changed",
+			nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(lockedRead).WithArgs(teamID, userID).WillReturnRows(roleRow("helper"))
+	suite.mock.ExpectRollback()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = suite.repo.UpdateMemberRole(ctx, teamID, userID, "helper")
+		}(i)
+	}
+	wg.Wait()
+
+	suite.NoError(errs[0])
+	suite.NoError(errs[1])
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestUpdateMemberRole_NotFound() {
+	ctx := context.Background()
+	teamID, userID := uuid.New(), uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE OF tm")).
+		WithArgs(teamID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"role_in_team", "company_id"}))
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.UpdateMemberRole(ctx, teamID, userID, "helper")
+
+	suite.EqualError(err, "team member not found")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestUpdate_ManagerChangeMovesMembership() {
+	ctx := context.Background()
+	companyID, teamID := uuid.New(), uuid.New()
+	previousManager, newManager := uuid.New(), uuid.New()
+	team := &models.Team{ID: teamID, CompanyID: companyID, Name: "North", ManagerID: &newManager, Status: "active"}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT manager_id FROM teams WHERE id = $1 AND company_id = $2 FOR UPDATE")).
+		WithArgs(teamID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"manager_id"}).AddRow(previousManager))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE teams SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+	// The previous manager leaves the team
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT role_in_team FROM team_members")).
+		WithArgs(teamID, previousManager).
+		WillReturnRows(sqlmock.NewRows([]string{"role_in_team"}).AddRow("manager"))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team_members")).
+		WithArgs(teamID, previousManager).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_member_history")).
+		WithArgs(sqlmock.AnyArg(), teamID, previousManager, companyID, "manager", nil, "removed",
+			nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The new manager, already a driver, is promoted
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT role_in_team FROM team_members")).
+		WithArgs(teamID, newManager).
+		WillReturnRows(sqlmock.NewRows([]string{"role_in_team"}).AddRow("driver"))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE team_members SET role_in_team = $1")).
+		WithArgs("manager", teamID, newManager).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_member_history")).
+		WithArgs(sqlmock.AnyArg(), teamID, newManager, companyID, "driver", "manager", "role_changed",
+			nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Update(ctx, team)
+
+	suite.Require().NoError(err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestUpdate_SameManagerLeavesMembership() {
+	ctx := context.Background()
+	managerID := uuid.New()
+	team := &models.Team{ID: uuid.New(), CompanyID: uuid.New(), Name: "North", ManagerID: &managerID, Status: "active"}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT manager_id FROM teams")).
+		WillReturnRows(sqlmock.NewRows([]string{"manager_id"}).AddRow(managerID))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE teams SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Update(ctx, team)
+
+	suite.Require().NoError(err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetTeamsManagedBy() {
+	ctx := context.Background()
+	managerID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "company_id", "name", "description", "manager_id", "status", "created_at", "updated_at"}).
+		AddRow(uuid.New(), uuid.New(), "North", nil, managerID, "active", time.Now(), time.Now())
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE manager_id = $1 AND deleted_at IS NULL AND status != 'archived'")).
+		WithArgs(managerID).
+		WillReturnRows(rows)
+
+	teams, err := suite.repo.GetTeamsManagedBy(ctx, managerID)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(teams, 1)
+	assert.Equal(suite.T(), "North", teams[0].Name)
+	assert.Equal(suite.T(), managerID, *teams[0].ManagerID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetMembershipGrowth_BucketsAddsAndRemoves() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"bucket", "added", "removed"}).
+		AddRow(jan, 5, 1).
+		AddRow(feb, 2, 3)
+
+	expectedQuery := regexp.QuoteMeta("date_trunc($4, h.changed_at) AS bucket") +
+		".*" + regexp.QuoteMeta("FILTER (WHERE h.change_type IN ('added', 'transferred_in')) AS added") +
+		".*" + regexp.QuoteMeta("FILTER (WHERE h.change_type IN ('removed', 'transferred_out')) AS removed") +
+		".*" + regexp.QuoteMeta("WHERE h.company_id = $1 AND h.changed_at >= $2 AND h.changed_at < $3") +
+		".*GROUP BY 1"
+	suite.mock.ExpectQuery(expectedQuery).
+		WithArgs(companyID, from, to, "month").
+		WillReturnRows(rows)
+
+	growth, err := suite.repo.GetMembershipGrowth(ctx, companyID, from, to, "month")
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(growth, 2)
+	assert.True(suite.T(), jan.Equal(growth[0].Bucket))
+	assert.Equal(suite.T(), 5, growth[0].Added)
+	assert.Equal(suite.T(), 1, growth[0].Removed)
+	assert.True(suite.T(), feb.Equal(growth[1].Bucket))
+	assert.Equal(suite.T(), 2, growth[1].Added)
+	assert.Equal(suite.T(), 3, growth[1].Removed)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetMembershipGrowth_NoHistory() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM team_member_history h")).
+		WithArgs(companyID, from, to, "day").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "added", "removed"}))
+
+	growth, err := suite.repo.GetMembershipGrowth(ctx, companyID, from, to, "day")
+
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), growth)
+	assert.Empty(suite.T(), growth)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetMembershipGrowth_DatabaseError() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	to := time.Now()
+	from := to.AddDate(0, -1, 0)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM team_member_history h")).
+		WithArgs(companyID, from, to, "week").
+		WillReturnError(sql.ErrConnDone)
+
+	growth, err := suite.repo.GetMembershipGrowth(ctx, companyID, from, to, "week")
+
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), growth)
+	assert.Contains(suite.T(), err.Error(), "failed to get membership growth")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetByCompany_PaginatesStablyOnEqualTimestamps() {
+	companyID := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Three teams created in the same instant, in the order the id tiebreaker returns them
+	ids := []uuid.UUID{
+		uuid.MustParse("cccccccc-0000-0000-0000-000000000000"),
+		uuid.MustParse("bbbbbbbb-0000-0000-0000-000000000000"),
+		uuid.MustParse("aaaaaaaa-0000-0000-0000-000000000000"),
+	}
+	columns := []string{"id", "company_id", "name", "description", "manager_id", "status", "created_at", "updated_at"}
+	page := func(ids ...uuid.UUID) *sqlmock.Rows {
+		rows := sqlmock.NewRows(columns)
+		for _, id := range ids {
+			rows.AddRow(id, companyID, "Team "+id.String()[:1], nil, nil, "active", createdAt, createdAt)
+		}
+		return rows
+	}
+
+	orderBy := regexp.QuoteMeta("ORDER BY created_at DESC, id DESC") + `\s+` + regexp.QuoteMeta("LIMIT $2 OFFSET $3")
+	suite.mock.ExpectQuery(orderBy).WithArgs(companyID, 2, 0, false).WillReturnRows(page(ids[0], ids[1]))
+	suite.mock.ExpectQuery(orderBy).WithArgs(companyID, 2, 2, false).WillReturnRows(page(ids[2]))
+
+	first, err := suite.repo.GetByCompany(context.Background(), companyID, 2, 0, false)
+	suite.Require().NoError(err)
+	second, err := suite.repo.GetByCompany(context.Background(), companyID, 2, 2, false)
+	suite.Require().NoError(err)
+
+	seen := []uuid.UUID{}
+	for _, team := range append(first, second...) {
+		seen = append(seen, team.ID)
+	}
+	suite.Equal(ids, seen)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetByCompany_ArchivedOnlyWhenRequested() {
+	companyID := uuid.New()
+	filter := regexp.QuoteMeta("WHERE company_id = $1 AND deleted_at IS NULL AND ($4 OR status != 'archived')")
+
+	suite.mock.ExpectQuery(filter).WithArgs(companyID, 10, 0, false).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(filter).WithArgs(companyID, 10, 0, true).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("deleted_at IS NULL AND ($2 OR status != 'archived')")).
+		WithArgs(companyID, true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	_, err := suite.repo.GetByCompany(context.Background(), companyID, 10, 0, false)
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetByCompany(context.Background(), companyID, 10, 0, true)
+	suite.Require().NoError(err)
+	count, err := suite.repo.CountByCompany(context.Background(), companyID, true)
+	suite.Require().NoError(err)
+
+	suite.Equal(3, count)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestSetStatus_SkipsDeletedTeams() {
+	teamID, companyID := uuid.New(), uuid.New()
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("SET status = $1, updated_at = NOW()")+`\s+`+
+		regexp.QuoteMeta("WHERE id = $2 AND company_id = $3 AND deleted_at IS NULL")).
+		WithArgs(models.TeamStatusArchived, teamID, companyID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.repo.SetStatus(context.Background(), teamID, companyID, models.TeamStatusArchived)
+
+	suite.Error(err)
+	suite.Contains(err.Error(), "team not found")
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetTeamsByUser_IncludesRoleInTeam() {
+	ctx := context.Background()
+	userID := uuid.New()
+	joined := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "company_id", "name", "description", "manager_id", "status", "created_at", "updated_at", "role_in_team", "joined_at"}).
+		AddRow(uuid.New(), uuid.New(), "Alpha", nil, nil, "active", joined, joined, "driver", joined).
+		AddRow(uuid.New(), uuid.New(), "Bravo", nil, nil, "active", joined, joined, "manager", joined)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("tm.role_in_team, tm.joined_at") + ".*" + regexp.QuoteMeta("WHERE tm.user_id = $1")).
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	teams, err := suite.repo.GetTeamsByUser(ctx, userID)
+
+	suite.NoError(err)
+	suite.Require().Len(teams, 2)
+	assert.Equal(suite.T(), "Alpha", teams[0].Name)
+	assert.Equal(suite.T(), "driver", teams[0].RoleInTeam)
+	assert.Equal(suite.T(), "manager", teams[1].RoleInTeam)
+	assert.Equal(suite.T(), joined, teams[1].JoinedAt)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestRemoveMember_SoftDeleteKeepsRowOutOfActiveMembers() {
+	ctx := context.Background()
+	teamID, userID, companyID := uuid.New(), uuid.New(), uuid.New()
+	joined := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	suite.repo.SetSoftDeleteMembers(true)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM team_members tm")+".*"+regexp.QuoteMeta("tm.left_at IS NULL")).
+		WithArgs(teamID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "user_id", "role_in_team", "joined_at"}).
+			AddRow(uuid.New(), teamID, userID, "driver", joined))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT company_id FROM teams WHERE id = $1")).
+		WithArgs(teamID).
+		WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(companyID))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE team_members SET left_at = NOW()")).
+		WithArgs(teamID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_member_history")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	suite.NoError(suite.repo.RemoveMember(ctx, teamID, userID))
+
+	// Only the remaining active member comes back; the removed row is filtered, not gone
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE tm.team_id = $1 AND tm.left_at IS NULL")).
+		WithArgs(teamID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "user_id", "role_in_team", "joined_at", "name", "email", "phone", "active"}).
+			AddRow(uuid.New(), teamID, uuid.New(), "manager", joined, "Ana", "ana@example.com", nil, true))
+
+	members, err := suite.repo.GetMembers(ctx, teamID)
+
+	suite.NoError(err)
+	suite.Require().Len(members, 1)
+	suite.NotEqual(userID, members[0].UserID)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamRepositoryTestSuite) TestRemoveMember_HardDeleteByDefault() {
+	ctx := context.Background()
+	teamID, userID := uuid.New(), uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM team_members tm")).
+		WithArgs(teamID, userID).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team_members WHERE team_id = $1 AND user_id = $2 AND left_at IS NULL")).
+		WithArgs(teamID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	suite.NoError(suite.repo.RemoveMember(ctx, teamID, userID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestTeamRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamRepositoryTestSuite))
+}
+
+func (suite *TeamRepositoryTestSuite) TestGetMemberHistoryWithDetails_LoadsUsersInOneQuery() {
+	ctx := context.Background()
+	teamID, companyID := uuid.New(), uuid.New()
+	anaID, brunoID, adminID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	historyColumns := []string{
+		"id", "team_id", "user_id", "company_id", "previous_role_in_team", "new_role_in_team",
+		"change_type", "previous_team_id", "new_team_id", "changed_by_user_id", "change_reason",
+		"changed_at", "created_at",
+	}
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM team_member_history h")).
+		WithArgs(teamID, companyID, 50).
+		WillReturnRows(sqlmock.NewRows(historyColumns).
+			AddRow(uuid.New(), teamID, anaID, companyID, nil, "driver", "added", nil, nil, adminID, nil, now, now).
+			AddRow(uuid.New(), teamID, brunoID, companyID, nil, "helper", "added", nil, nil, adminID, nil, now, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE u.id = ANY($1) AND u.deleted_at IS NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "email", "phone", "cpf", "avatar", "role_id", "company_id",
+			"active", "last_login", "dashboard_config", "login_attempts",
+			"blocked_until", "password_changed_at", "created_at", "updated_at",
+			"role_id", "role_name", "role_description", "role_created_at", "role_updated_at",
+		}).
+			AddRow(anaID, "Ana", "ana@example.com", nil, nil, nil, uuid.New(), companyID, true, nil, nil, 0, nil, now, now, now, uuid.New(), "driver", "", now, now).
+			AddRow(adminID, "Carla", "carla@example.com", nil, nil, nil, uuid.New(), companyID, true, nil, nil, 0, nil, now, now, now, uuid.New(), "company_admin", "", now, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, company_id FROM teams WHERE id = $1")).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, company_id FROM teams WHERE id = $1")).
+		WillReturnError(sql.ErrNoRows)
+
+	history, err := suite.repo.GetMemberHistoryWithDetails(ctx, teamID, companyID, 0)
+
+	suite.NoError(err)
+	suite.Require().Len(history, 2)
+	suite.Equal("Ana", history[0].User.Name)
+	suite.Equal("Carla", history[0].ChangedByUser.Name)
+	suite.Nil(history[1].User, "deleted users are left unpopulated")
+	suite.Equal("Carla", history[1].ChangedByUser.Name)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
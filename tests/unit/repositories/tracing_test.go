@@ -0,0 +1,46 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/tests/testutils"
+)
+
+func TestTeamRepository_GetByCompanyRecordsSpan(t *testing.T) {
+	// Install before building the repository: it takes its tracer at construction
+	recorder := testutils.InstallSpanRecorder(t)
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewTeamRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	companyID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM teams")).
+		WithArgs(companyID, 25, 50, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+
+	_, err = repo.GetByCompany(context.Background(), companyID, 25, 50, true)
+	require.NoError(t, err)
+
+	span := recorder.RequireSpan(t, "TeamRepository.GetByCompany")
+	attrs := testutils.SpanAttributes(span)
+	assert.Equal(t, attribute.StringValue(companyID.String()), attrs["company.id"])
+	assert.Equal(t, attribute.IntValue(25), attrs["limit"])
+	assert.Equal(t, attribute.IntValue(50), attrs["offset"])
+	assert.Equal(t, attribute.BoolValue(true), attrs["include_archived"])
+	assert.Equal(t, attribute.IntValue(2), attrs["teams.count"])
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
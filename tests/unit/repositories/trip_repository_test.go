@@ -0,0 +1,140 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// TripRepositoryTestSuite defines the test suite for TripRepository
+type TripRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.TripRepository
+}
+
+func (suite *TripRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewTripRepository(suite.db)
+}
+
+func (suite *TripRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *TripRepositoryTestSuite) TestAutoCloseStaleTrips_ClosesActiveTripsStartedBeforeCutoff() {
+	cutoff := time.Now().Add(-24 * time.Hour)
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("SET status = 'auto_closed'")+".*"+
+		regexp.QuoteMeta("WHERE status = 'active' AND start_time < $2")).
+		WithArgs("auto-closed", cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	closed, err := suite.repo.AutoCloseStaleTrips(context.Background(), cutoff, "auto-closed")
+
+	suite.NoError(err)
+	suite.Equal(int64(2), closed)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TripRepositoryTestSuite) TestGetActiveTrips_MostRecentFirst() {
+	vehicleID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "vehicle_id", "start_time", "status"}).
+		AddRow(uuid.New(), vehicleID, time.Now().Add(-time.Hour), "active").
+		AddRow(uuid.New(), vehicleID, time.Now().Add(-5*time.Hour), "active")
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE vehicle_id = $1 AND status = 'active'") + ".*" +
+		regexp.QuoteMeta("ORDER BY start_time DESC")).
+		WithArgs(vehicleID).
+		WillReturnRows(rows)
+
+	trips, err := suite.repo.GetActiveTrips(context.Background(), vehicleID)
+
+	suite.NoError(err)
+	suite.Len(trips, 2)
+	suite.True(trips[0].StartTime.After(trips[1].StartTime))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TripRepositoryTestSuite) TestCloseTrips_NoIDsIsNoop() {
+	closed, err := suite.repo.CloseTrips(context.Background(), nil, "note")
+
+	suite.NoError(err)
+	suite.Zero(closed)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TripRepositoryTestSuite) TestAddPoints_InsertsBatchInOneStatement() {
+	tripID := uuid.New()
+	recordedAt := time.Now()
+	points := []models.TripPoint{
+		{Latitude: -23.55, Longitude: -46.63, RecordedAt: recordedAt},
+		{Latitude: -23.56, Longitude: -46.64, RecordedAt: recordedAt.Add(time.Minute)},
+	}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO trip_points (trip_id, latitude, longitude, speed, recorded_at) VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)")).
+		WithArgs(tripID, -23.55, -46.63, nil, recordedAt, tripID, -23.56, -46.64, nil, recordedAt.Add(time.Minute)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.AddPoints(context.Background(), tripID, points)
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TripRepositoryTestSuite) TestEndTrip_ComputesDistanceFromPointsWhenMissing() {
+	tripID := uuid.New()
+	now := time.Now()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM trip_points")).
+		WithArgs(tripID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "trip_id", "latitude", "longitude", "recorded_at"}).
+			AddRow(1, tripID, 0.0, 0.0, now).
+			AddRow(2, tripID, 0.0, 1.0, now.Add(time.Minute)))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("UPDATE vehicle_trips")).
+		WithArgs(tripID, nil, nil, nil, sqlmock.AnyArg(), nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "distance_km"}).AddRow(tripID, "completed", 111.19))
+	suite.mock.ExpectCommit()
+
+	trip, err := suite.repo.EndTrip(context.Background(), tripID, models.EndTripRequest{})
+
+	suite.NoError(err)
+	suite.Equal("completed", trip.Status)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TripRepositoryTestSuite) TestEndTrip_NotActive() {
+	tripID := uuid.New()
+	distance := 12.5
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("UPDATE vehicle_trips")).
+		WithArgs(tripID, nil, nil, nil, &distance, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.repo.EndTrip(context.Background(), tripID, models.EndTripRequest{DistanceKm: &distance})
+
+	suite.ErrorIs(err, repository.ErrTripNotActive)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestTripRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TripRepositoryTestSuite))
+}
@@ -10,11 +10,13 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/paulochiaradia/dashtrack/internal/models"
 	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
 )
 
 // UserRepositoryTestSuite defines the test suite for UserRepository
@@ -417,7 +419,7 @@ func (suite *UserRepositoryTestSuite) TestList_Success() {
 		WillReturnRows(rows)
 
 	// Test
-	result, err := suite.repo.List(ctx, limit, offset, &active, nil)
+	result, err := suite.repo.List(ctx, limit, offset, &active, nil, repository.Sort{})
 
 	// Assertions
 	assert.NoError(suite.T(), err)
@@ -427,6 +429,378 @@ func (suite *UserRepositoryTestSuite) TestList_Success() {
 	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
 }
 
+func (suite *UserRepositoryTestSuite) TestList_AppliesRequestedSort() {
+	sort, err := repository.UserSortFields.Parse("last_login", "desc")
+	suite.Require().NoError(err)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("ORDER BY u.last_login DESC NULLS LAST, u.id DESC")).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	users, err := suite.repo.List(context.Background(), 10, 0, nil, nil, sort)
+
+	suite.NoError(err)
+	suite.Empty(users)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdatePreferences_OnlyChangesSuppliedFields() {
+	userID := uuid.New()
+	disabled := false
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SET notify_new_session = COALESCE($1, notify_new_session)")).
+		WithArgs(&disabled, nil, sqlmock.AnyArg(), userID).
+		WillReturnRows(sqlmock.NewRows([]string{"notify_new_session", "notify_assignment_changes"}).AddRow(false, false))
+
+	prefs, err := suite.repo.UpdatePreferences(context.Background(), userID, models.UpdatePreferencesRequest{NotifyNewSession: &disabled})
+
+	suite.NoError(err)
+	suite.Require().NotNil(prefs)
+	suite.False(prefs.NotifyNewSession)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdatePreferences_UserNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("RETURNING notify_new_session")).
+		WillReturnRows(sqlmock.NewRows([]string{"notify_new_session"}))
+
+	prefs, err := suite.repo.UpdatePreferences(context.Background(), uuid.New(), models.UpdatePreferencesRequest{})
+
+	suite.NoError(err)
+	suite.Nil(prefs)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestGetAssignmentRecipients_ReturnsPreference() {
+	driverID := uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE id = ANY($1) AND active = true AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "notify_assignment_changes"}).
+			AddRow(driverID, "Maria Souza", "maria@example.com", true))
+
+	recipients, err := suite.repo.GetAssignmentRecipients(context.Background(), []uuid.UUID{driverID})
+
+	suite.NoError(err)
+	suite.Require().Len(recipients, 1)
+	suite.Equal(driverID, recipients[0].ID)
+	suite.True(recipients[0].NotifyAssignmentChanges)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestReassignCompany_MovesAllCompanyUsers() {
+	fromCompanyID := uuid.New()
+	toCompanyID := uuid.New()
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("SET company_id = $1, updated_at = $2")+".*"+
+		regexp.QuoteMeta("WHERE company_id = $3 AND deleted_at IS NULL")).
+		WithArgs(toCompanyID, sqlmock.AnyArg(), fromCompanyID).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	moved, err := suite.repo.ReassignCompany(context.Background(), fromCompanyID, toCompanyID)
+
+	suite.NoError(err)
+	suite.Equal(int64(4), moved)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestUpgradePasswordHash_KeepsPasswordChangedAt() {
+	userID := uuid.New()
+
+	// Only the hash changes, and only while it is still the one that was verified
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND password = $4")).
+		WithArgs("new-hash", sqlmock.AnyArg(), userID, "old-hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.UpgradePasswordHash(context.Background(), userID, "old-hash", "new-hash")
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestIncrementLoginAttempts_CountsInTheDatabase() {
+	userID := uuid.New()
+	blockUntil := time.Now().Add(15 * time.Minute)
+
+	// The count is incremented by the UPDATE itself, never from a value read earlier
+	suite.mock.ExpectQuery(regexp.QuoteMeta("login_attempts = login_attempts + 1")+".*"+
+		regexp.QuoteMeta("RETURNING login_attempts, blocked_until")).
+		WithArgs(userID, 3, blockUntil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"login_attempts", "blocked_until", "newly_blocked"}).AddRow(3, blockUntil, true))
+
+	result, err := suite.repo.IncrementLoginAttempts(context.Background(), userID, 3, blockUntil)
+
+	suite.NoError(err)
+	suite.Equal(3, result.Attempts)
+	suite.Require().NotNil(result.BlockedUntil)
+	suite.True(result.NewlyBlocked)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestGetLastActivity_GroupsSessionsByUser() {
+	activeID, loggedOutID := uuid.New(), uuid.New()
+	usedAt := time.Now().Add(-time.Minute)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM session_tokens") + ".*" + regexp.QuoteMeta("WHERE user_id = ANY($1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "last_activity_at", "active_session_used_at"}).
+			AddRow(activeID, usedAt, usedAt).
+			AddRow(loggedOutID, usedAt, nil))
+
+	activity, err := suite.repo.GetLastActivity(context.Background(), []uuid.UUID{activeID, loggedOutID, uuid.New()})
+
+	suite.NoError(err)
+	suite.Len(activity, 2)
+	suite.NotNil(activity[activeID].ActiveSessionUsedAt)
+	suite.Nil(activity[loggedOutID].ActiveSessionUsedAt)
+	suite.WithinDuration(usedAt, activity[loggedOutID].LastActivityAt, time.Second)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestDeactivateByCompany_DeactivatesActiveUsers() {
+	companyID := uuid.New()
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("SET active = false, updated_at = $1")+".*"+
+		regexp.QuoteMeta("WHERE company_id = $2 AND active = true AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), companyID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deactivated, err := suite.repo.DeactivateByCompany(context.Background(), companyID)
+
+	suite.NoError(err)
+	suite.Equal(int64(2), deactivated)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestDeactivateByCompany_DatabaseError() {
+	suite.mock.ExpectExec(regexp.QuoteMeta("SET active = false")).
+		WillReturnError(sql.ErrConnDone)
+
+	_, err := suite.repo.DeactivateByCompany(context.Background(), uuid.New())
+
+	suite.Error(err)
+	suite.Contains(err.Error(), "failed to deactivate company users")
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestReadReplica_RoutesCountsAndKeepsGuardsOnPrimary() {
+	replicaDB, replicaMock, err := sqlmock.New()
+	suite.Require().NoError(err)
+	defer replicaDB.Close()
+	suite.repo.SetReadReplica(sqlx.NewDb(replicaDB, "sqlmock"))
+
+	companyID := uuid.New()
+	replicaMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).
+		WithArgs(companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	count, err := suite.repo.CountUsers(context.Background(), &companyID)
+	suite.NoError(err)
+	suite.Equal(7, count)
+
+	exists, err := suite.repo.ExistsByCPF(context.Background(), &companyID, "52998224725", nil)
+	suite.NoError(err)
+	suite.False(exists)
+
+	suite.NoError(replicaMock.ExpectationsWereMet())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestExistsByEmail_IncludesDeletedUsers() {
+	// users.email is unique across deleted rows too, so they must count as taken
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM users WHERE email = $1)")).
+		WithArgs("old@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := suite.repo.ExistsByEmail(context.Background(), "old@example.com")
+
+	suite.NoError(err)
+	suite.True(exists)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestReadReplica_NilFallsBackToPrimary() {
+	suite.repo.SetReadReplica(nil)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := suite.repo.CountUsers(context.Background(), nil)
+
+	suite.NoError(err)
+	suite.Equal(3, count)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestPurgeUser_AnonymizesAndDeletesCredentials() {
+	userID := uuid.New()
+	email := "driver@fleet.com"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT email, avatar FROM users WHERE id = $1 FOR UPDATE")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"email", "avatar"}).AddRow(email, "/uploads/avatars/a1b2c3.png"))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_logs")).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET user_email")).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET changes")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_sessions WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM session_tokens WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM password_reset_tokens WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM password_history WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 5))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM two_factor_auth WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta("name = 'Deleted user', email = $2")).
+		WithArgs(userID, utils.AnonymizeEmail(email)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	result, err := suite.repo.PurgeUser(context.Background(), userID)
+
+	suite.Require().NoError(err)
+	suite.Equal(int64(12), result.AuthLogs)
+	suite.Equal(int64(3), result.AuditLogs)
+	suite.Equal(int64(3), result.SessionsDeleted)
+	suite.Require().NotNil(result.Avatar)
+	suite.Equal("/uploads/avatars/a1b2c3.png", *result.Avatar)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestPurgeUser_NotFound() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT email, avatar FROM users")).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectRollback()
+
+	result, err := suite.repo.PurgeUser(context.Background(), uuid.New())
+
+	suite.NoError(err)
+	suite.Nil(result)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+// searchColumns are the columns selected by UserRepository.Search
+var searchColumns = []string{
+	"id", "name", "email", "phone", "cpf", "avatar", "role_id", "company_id",
+	"active", "last_login", "dashboard_config", "login_attempts",
+	"blocked_until", "password_changed_at", "created_at", "updated_at",
+	"role_id", "role_name", "role_description", "role_created_at", "role_updated_at",
+}
+
+func searchRow(phone, cpf string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(searchColumns).AddRow(
+		uuid.New(), "Driver", "driver@fleet.com", phone, cpf, nil, uuid.New(), nil,
+		true, nil, nil, 0,
+		nil, now, now, now,
+		uuid.New(), "driver", "Driver", now, now,
+	)
+}
+
+func (suite *UserRepositoryTestSuite) TestSearch_MatchesPhoneDigits() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`regexp_replace(u.phone, '\D', '', 'g') LIKE $2 OR u.cpf LIKE $2`)).
+		WithArgs("%(11) 98765-43%", "%119876543%", 20, 0).
+		WillReturnRows(searchRow("+5511987654321", "52998224725"))
+
+	users, err := suite.repo.Search(context.Background(), nil, "(11) 98765-43", 20, 0, repository.Sort{})
+
+	suite.NoError(err)
+	suite.Len(users, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestSearch_MatchesCPFDigits() {
+	companyID := uuid.New()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("u.cpf LIKE $2")+".*"+regexp.QuoteMeta("u.company_id = $3")).
+		WithArgs("%529.982.247%", "%529982247%", companyID, 20, 0).
+		WillReturnRows(searchRow("+5511987654321", "52998224725"))
+
+	users, err := suite.repo.Search(context.Background(), &companyID, "529.982.247", 20, 0, repository.Sort{})
+
+	suite.NoError(err)
+	suite.Len(users, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestSearch_TextTermSkipsDigitColumns() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("(LOWER(u.name) LIKE $1 OR LOWER(u.email) LIKE $1) AND u.active = true")).
+		WithArgs("%maria 2%", 20, 0).
+		WillReturnRows(sqlmock.NewRows(searchColumns))
+
+	_, err := suite.repo.Search(context.Background(), nil, "Maria 2", 20, 0, repository.Sort{})
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestListByCompanyAndRoles_PassesRolesAsArray() {
+	companyID := uuid.New()
+	roles := []string{"company_admin", "manager", "driver", "helper"}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("r.name = ANY($1) AND u.company_id = $2")).
+		WithArgs(pq.Array(roles), companyID, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	users, err := suite.repo.ListByCompanyAndRoles(context.Background(), &companyID, roles, 10, 0, repository.Sort{})
+
+	suite.NoError(err)
+	suite.Empty(users)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestCountByCompanyAndRoles_PassesRolesAsArray() {
+	roles := []string{"company_admin"}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE r.name = ANY($1)")).
+		WithArgs(pq.Array(roles)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := suite.repo.CountByCompanyAndRoles(context.Background(), nil, roles)
+
+	suite.NoError(err)
+	suite.Equal(3, count)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
+
+func (suite *UserRepositoryTestSuite) TestGetByIDs_KeysUsersByID() {
+	anaID, brunoID, roleID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	columns := []string{
+		"id", "name", "email", "phone", "cpf", "avatar", "role_id", "company_id",
+		"active", "last_login", "dashboard_config", "login_attempts",
+		"blocked_until", "password_changed_at", "created_at", "updated_at",
+		"role_id", "role_name", "role_description", "role_created_at", "role_updated_at",
+	}
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE u.id = ANY($1) AND u.deleted_at IS NULL")).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(anaID, "Ana", "ana@example.com", nil, nil, nil, roleID, nil, true, nil, nil, 0, nil, now, now, now, roleID, "driver", "Driver role", now, now).
+			AddRow(brunoID, "Bruno", "bruno@example.com", nil, nil, nil, roleID, nil, true, nil, nil, 0, nil, now, now, now, roleID, "driver", "Driver role", now, now))
+
+	users, err := suite.repo.GetByIDs(context.Background(), []uuid.UUID{anaID, brunoID, uuid.New()})
+
+	suite.NoError(err)
+	suite.Len(users, 2)
+	suite.Equal("Ana", users[anaID].Name)
+	suite.Equal("driver", users[brunoID].Role.Name)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserRepositoryTestSuite) TestGetByIDs_NoIDsSkipsQuery() {
+	users, err := suite.repo.GetByIDs(context.Background(), nil)
+
+	suite.NoError(err)
+	suite.Empty(users)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
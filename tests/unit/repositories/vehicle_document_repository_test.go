@@ -0,0 +1,91 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// VehicleDocumentRepositoryTestSuite defines the test suite for VehicleDocumentRepository
+type VehicleDocumentRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.VehicleDocumentRepository
+}
+
+func (suite *VehicleDocumentRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewVehicleDocumentRepository(suite.db)
+}
+
+func (suite *VehicleDocumentRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *VehicleDocumentRepositoryTestSuite) TestGetExpiringDocuments_ScopedToCompanyAndWindow() {
+	companyID := uuid.New()
+	expiresAt := time.Now().AddDate(0, 0, 10)
+
+	rows := sqlmock.NewRows([]string{
+		"document_id", "vehicle_id", "license_plate", "type", "number", "expires_at", "days_remaining",
+	}).
+		AddRow(uuid.New(), uuid.New(), "ABC1D23", "registration", "RNV-001", time.Now().AddDate(0, 0, -2), -2).
+		AddRow(uuid.New(), uuid.New(), "XYZ9K87", "insurance", "POL-123", expiresAt, 10)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE v.company_id = $1 AND v.deleted_at IS NULL")+".*"+
+		regexp.QuoteMeta("AND d.expires_at <= CURRENT_DATE + $2::int")).
+		WithArgs(companyID, 30).
+		WillReturnRows(rows)
+
+	docs, err := suite.repo.GetExpiringDocuments(context.Background(), companyID, 30)
+
+	suite.NoError(err)
+	suite.Len(docs, 2)
+	suite.Equal(-2, docs[0].DaysRemaining)
+	suite.Equal("XYZ9K87", docs[1].LicensePlate)
+	suite.Equal("POL-123", docs[1].Number)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleDocumentRepositoryTestSuite) TestDelete_OtherCompanyNotFound() {
+	id := uuid.New()
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM vehicle_documents d")).
+		WithArgs(id, vehicleID, companyID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.repo.Delete(context.Background(), id, vehicleID, companyID)
+
+	suite.EqualError(err, "vehicle document not found or not authorized")
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleDocumentRepositoryTestSuite) TestGetByID_NotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM vehicle_documents d")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	doc, err := suite.repo.GetByID(context.Background(), uuid.New(), uuid.New(), uuid.New())
+
+	suite.NoError(err)
+	suite.Nil(doc)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestVehicleDocumentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(VehicleDocumentRepositoryTestSuite))
+}
@@ -0,0 +1,201 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+// VehicleRepositoryTestSuite defines the test suite for VehicleRepository
+type VehicleRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	mock sqlmock.Sqlmock
+	repo *repository.VehicleRepository
+}
+
+func (suite *VehicleRepositoryTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.repo = repository.NewVehicleRepository(suite.db)
+}
+
+func (suite *VehicleRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *VehicleRepositoryTestSuite) TestUpdateAssignment_RejectsCrossCompanyDriver() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	driverID := uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND company_id = $2")).
+		WithArgs(driverID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	err := suite.repo.UpdateAssignment(context.Background(), vehicleID, companyID, &driverID, nil, nil)
+
+	suite.Error(err)
+	suite.True(errors.Is(err, repository.ErrAssignmentOutsideCompany))
+	suite.Contains(err.Error(), "driver "+driverID.String())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestUpdateAssignment_RejectsCrossCompanyTeam() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	helperID := uuid.New()
+	teamID := uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = $1")).
+		WithArgs(helperID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM teams WHERE id = $1 AND company_id = $2")).
+		WithArgs(teamID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	err := suite.repo.UpdateAssignment(context.Background(), vehicleID, companyID, nil, &helperID, &teamID)
+
+	suite.True(errors.Is(err, repository.ErrAssignmentOutsideCompany))
+	suite.Contains(err.Error(), "team "+teamID.String())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestUpdateAssignment_SameCompanyUpdates() {
+	vehicleID := uuid.New()
+	companyID := uuid.New()
+	driverID := uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = $1")).
+		WithArgs(driverID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, driver_id, helper_id, team_id FROM vehicles")).
+		WithArgs(vehicleID, companyID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "driver_id", "helper_id", "team_id"}).AddRow(vehicleID, driverID, nil, nil))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE vehicles SET")).
+		WithArgs(&driverID, nil, nil, vehicleID, companyID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.UpdateAssignment(context.Background(), vehicleID, companyID, &driverID, nil, nil)
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestCreateBatch_InsertsAllInOneTransaction() {
+	companyID := uuid.New()
+	vehicles := []*models.Vehicle{
+		{CompanyID: companyID, LicensePlate: "ABC1234", Brand: "Volvo", Model: "FH", Year: 2020, VehicleType: "truck", FuelType: "diesel"},
+		{CompanyID: companyID, LicensePlate: "ABC1D23", Brand: "Fiat", Model: "Ducato", Year: 2022, VehicleType: "van", FuelType: "diesel"},
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicles")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicles")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.CreateBatch(context.Background(), vehicles)
+
+	suite.NoError(err)
+	for _, vehicle := range vehicles {
+		suite.NotEqual(uuid.Nil, vehicle.ID)
+		suite.Equal("active", vehicle.Status)
+	}
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestCreateBatch_RollsBackOnFailure() {
+	companyID := uuid.New()
+	vehicles := []*models.Vehicle{
+		{CompanyID: companyID, LicensePlate: "ABC1234", Brand: "Volvo", Model: "FH", Year: 2020, VehicleType: "truck", FuelType: "diesel"},
+		{CompanyID: companyID, LicensePlate: "ABC1D23", Brand: "Fiat", Model: "Ducato", Year: 2022, VehicleType: "van", FuelType: "diesel"},
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicles")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vehicles")).WillReturnError(errors.New("duplicate key"))
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.CreateBatch(context.Background(), vehicles)
+
+	suite.Error(err)
+	suite.Contains(err.Error(), "vehicle ABC1D23")
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestGetByCompany_AppliesFilters() {
+	companyID := uuid.New()
+	filter := models.VehicleFilter{Status: "maintenance", VehicleType: "truck", Unassigned: true}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(
+		"AND status = $2 AND vehicle_type = $3 AND (driver_id IS NULL OR team_id IS NULL) ORDER BY created_at DESC, id DESC LIMIT $4 OFFSET $5")).
+		WithArgs(companyID, "maintenance", "truck", 20, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	vehicles, err := suite.repo.GetByCompany(context.Background(), companyID, filter, 20, 40, repository.Sort{})
+
+	suite.NoError(err)
+	suite.Empty(vehicles)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestGetByCompany_WithoutFiltersKeepsPagination() {
+	companyID := uuid.New()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("WHERE company_id = $1 AND status != 'deleted' ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3")).
+		WithArgs(companyID, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := suite.repo.GetByCompany(context.Background(), companyID, models.VehicleFilter{}, 10, 0, repository.Sort{})
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *VehicleRepositoryTestSuite) TestGetActiveTripsByCompany_JoinsVehicleAndDriver() {
+	companyID := uuid.New()
+	vehicleID := uuid.New()
+	driverID := uuid.New()
+	startedAt := time.Now().Add(-time.Hour)
+
+	columns := []string{"id", "vehicle_id", "driver_id", "start_time", "status",
+		"license_plate", "brand", "model", "vehicle_type", "team_id", "driver_name", "driver_phone"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(uuid.New(), vehicleID, driverID, startedAt, "active",
+			"ABC1D23", "Volvo", "FH", "truck", nil, "Maria", "11999990000").
+		AddRow(uuid.New(), uuid.New(), nil, startedAt, "active",
+			"XYZ9K87", "Fiat", "Ducato", "van", nil, nil, nil)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("JOIN vehicles v ON v.id = t.vehicle_id")).
+		WithArgs(companyID).
+		WillReturnRows(rows)
+
+	trips, err := suite.repo.GetActiveTripsByCompany(context.Background(), companyID)
+
+	suite.NoError(err)
+	suite.Require().Len(trips, 2)
+	suite.Equal(vehicleID, trips[0].Vehicle.ID)
+	suite.Equal("ABC1D23", trips[0].Vehicle.LicensePlate)
+	suite.Require().NotNil(trips[0].Driver)
+	suite.Equal(driverID, trips[0].Driver.ID)
+	suite.Equal("Maria", trips[0].Driver.Name)
+	suite.Nil(trips[1].Driver)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestVehicleRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(VehicleRepositoryTestSuite))
+}
@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+)
+
+func TestWebhookRepository_RotateSecretKeepsPreviousSecret(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewWebhookRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	id, companyID := uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta("previous_secret = CASE WHEN $4::timestamp IS NULL THEN NULL ELSE secret END")).
+		WithArgs(id, companyID, "new-secret", &expiresAt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "company_id", "url", "secret", "events", "active", "created_at", "updated_at",
+			"previous_secret", "previous_secret_expires_at",
+		}).AddRow(id, companyID, "https://example.com/hook", "new-secret", pq.StringArray{"auth.account_blocked"}, true,
+			time.Now(), time.Now(), "old-secret", expiresAt))
+
+	webhook, err := repo.RotateSecret(context.Background(), id, companyID, "new-secret", &expiresAt)
+
+	require.NoError(t, err)
+	require.NotNil(t, webhook)
+	assert.Equal(t, "new-secret", webhook.Secret)
+	assert.Equal(t, "old-secret", webhook.ActivePreviousSecret(time.Now()))
+	assert.Empty(t, webhook.ActivePreviousSecret(expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_RotateSecretNotFound(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	repo := repository.NewWebhookRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE webhooks SET")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	webhook, err := repo.RotateSecret(context.Background(), uuid.New(), uuid.New(), "new-secret", nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, webhook)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+	"github.com/paulochiaradia/dashtrack/internal/routes"
+)
+
+func newTestEngine(t *testing.T) *gin.Engine {
+	return newTestEngineWithConfig(t, &config.Config{})
+}
+
+// newTestEngineWithConfig builds the router from cfg filled with the settings it needs to start
+func newTestEngineWithConfig(t *testing.T, cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	cfg.JWTSecret, cfg.BcryptCost, cfg.JWTAccessExpireMinutes, cfg.JWTRefreshExpireHours = "test-secret", 4, 15, 24
+	return routes.NewRouter(db, nil, cfg).Engine()
+}
+
+func TestRouter_TrailingSlashRedirects(t *testing.T) {
+	engine := newTestEngine(t)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/", nil))
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/health", w.Header().Get("Location"))
+}
+
+func TestRouter_WrongMethodReturns405(t *testing.T) {
+	engine := newTestEngine(t)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/login", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Allow"))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, false, response["success"])
+	details := response["error"].(map[string]interface{})
+	assert.Equal(t, "METHOD_NOT_ALLOWED", details["code"])
+	assert.Equal(t, []interface{}{"POST"}, details["allowed"])
+}
+
+func TestRouter_UnknownPathReturns404(t *testing.T) {
+	engine := newTestEngine(t)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/does-not-exist", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "NOT_FOUND", response["error"].(map[string]interface{})["code"])
+}
+
+func TestRouter_ClientIPFromTrustedProxiesOnly(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "no trusted proxies ignores forwarded headers",
+			cfg:        &config.Config{},
+			remoteAddr: "10.0.0.5:4000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "trusted proxy forwards the client address",
+			cfg:        &config.Config{TrustedProxies: []string{"10.0.0.0/8"}, ClientIPHeaders: []string{"X-Forwarded-For"}},
+			remoteAddr: "10.0.0.5:4000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.9"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer can't spoof its address",
+			cfg:        &config.Config{TrustedProxies: []string{"10.0.0.0/8"}, ClientIPHeaders: []string{"X-Forwarded-For"}},
+			remoteAddr: "192.0.2.10:4000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:       "192.0.2.10",
+		},
+		{
+			name:       "trusted platform header",
+			cfg:        &config.Config{TrustedPlatform: "cloudflare"},
+			remoteAddr: "172.64.0.1:4000",
+			headers:    map[string]string{"CF-Connecting-IP": "203.0.113.7"},
+			want:       "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngineWithConfig(t, tt.cfg)
+			engine.GET("/client-ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/client-ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
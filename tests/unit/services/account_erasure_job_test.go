@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestAccountErasureJob_AnonymizesLogsAfterGracePeriod(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	job := services.NewAccountErasureJob(sqlx.NewDb(mockDB, "sqlmock"), 30*24*time.Hour)
+	storage := newMemoryStorage()
+	job.SetFileStorage(storage)
+	erasedUser := uuid.New()
+	failingUser := uuid.New()
+	avatarKey := "avatars/" + erasedUser.String() + "/a1b2c3.jpg"
+	avatarURL, err := storage.Put(context.Background(), avatarKey, "image/jpeg", []byte("jpeg"))
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(erasedUser).AddRow(failingUser))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT email, avatar FROM users WHERE id = $1")).
+		WithArgs(erasedUser).
+		WillReturnRows(sqlmock.NewRows([]string{"email", "avatar"}).AddRow("driver@fleet.com", avatarURL))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_logs")).
+		WithArgs(erasedUser, utils.AnonymizeEmail("driver@fleet.com"), "driver@fleet.com").
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET user_email")).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET changes")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET erased_at = NOW(), avatar = NULL")).
+		WithArgs(erasedUser).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT email, avatar FROM users")).
+		WithArgs(failingUser).
+		WillReturnError(errors.New("lock timeout"))
+	mock.ExpectRollback()
+
+	erased, err := job.RunOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, []string{avatarKey}, storage.deleted, "the erased user's picture is no longer served")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/metrics"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func newActivityMetricsCollectorWithMockDB(t *testing.T) (*services.ActivityMetricsCollector, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	return services.NewActivityMetricsCollector(sqlx.NewDb(mockDB, "sqlmock"), time.Minute), mock
+}
+
+func TestActivityMetricsCollector_RunOnceSetsGauges(t *testing.T) {
+	collector, mock := newActivityMetricsCollectorWithMockDB(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM session_tokens")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM vehicle_trips WHERE status = 'active'")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	require.NoError(t, collector.RunOnce(context.Background()))
+
+	assert.Equal(t, float64(7), testutil.ToFloat64(metrics.ActiveSessionsTotal))
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.ActiveTripsTotal))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActivityMetricsCollector_FailedCountKeepsPreviousValue(t *testing.T) {
+	collector, mock := newActivityMetricsCollectorWithMockDB(t)
+	metrics.ActiveSessionsTotal.Set(5)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM session_tokens")).
+		WillReturnError(errors.New("connection reset"))
+
+	err := collector.RunOnce(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to count active sessions")
+	assert.Equal(t, float64(5), testutil.ToFloat64(metrics.ActiveSessionsTotal))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeAssignmentRecipients serves recipients from a fixed set of users
+type fakeAssignmentRecipients map[uuid.UUID]models.AssignmentRecipient
+
+func (f fakeAssignmentRecipients) GetAssignmentRecipients(ctx context.Context, ids []uuid.UUID) ([]models.AssignmentRecipient, error) {
+	var found []models.AssignmentRecipient
+	for _, id := range ids {
+		if recipient, ok := f[id]; ok {
+			found = append(found, recipient)
+		}
+	}
+	return found, nil
+}
+
+type sentAssignmentNotice struct {
+	email  string
+	notice services.AssignmentNotice
+}
+
+// fakeAssignmentMailer records the notices it was asked to send
+type fakeAssignmentMailer struct {
+	sent []sentAssignmentNotice
+}
+
+func (f *fakeAssignmentMailer) SendVehicleAssignmentChanged(email, userName string, notice services.AssignmentNotice) error {
+	f.sent = append(f.sent, sentAssignmentNotice{email: email, notice: notice})
+	return nil
+}
+
+func assignmentRecipient(name string, optedIn bool) models.AssignmentRecipient {
+	return models.AssignmentRecipient{
+		ID:                      uuid.New(),
+		Name:                    name,
+		Email:                   name + "@example.com",
+		NotifyAssignmentChanges: optedIn,
+	}
+}
+
+func TestAssignmentNotifier_NotifiesOptedInCrewAndManager(t *testing.T) {
+	oldDriver := assignmentRecipient("old", true)
+	newDriver := assignmentRecipient("new", true)
+	helper := assignmentRecipient("helper", false)
+	manager := assignmentRecipient("manager", true)
+	users := fakeAssignmentRecipients{oldDriver.ID: oldDriver, newDriver.ID: newDriver, helper.ID: helper, manager.ID: manager}
+
+	mailer := &fakeAssignmentMailer{}
+	notifier := services.NewAssignmentNotifier(users, mailer)
+
+	sent, err := notifier.Process(context.Background(), services.AssignmentChange{
+		LicensePlate:     "ABC1234",
+		PreviousDriverID: &oldDriver.ID,
+		DriverID:         &newDriver.ID,
+		HelperID:         &helper.ID,
+		ManagerID:        &manager.ID,
+	})
+	require.NoError(t, err)
+
+	// Both drivers plus one manager notice per crew change; the helper did not opt in
+	assert.Equal(t, 5, sent)
+	require.Len(t, mailer.sent, 5)
+	assert.Equal(t, "old@example.com", mailer.sent[0].email)
+	assert.False(t, mailer.sent[0].notice.Assigned)
+	assert.Equal(t, "new@example.com", mailer.sent[2].email)
+	assert.True(t, mailer.sent[2].notice.Assigned)
+	assert.Equal(t, services.AssignmentRoleDriver, mailer.sent[2].notice.Role)
+
+	helperNotice := mailer.sent[4]
+	assert.Equal(t, "manager@example.com", helperNotice.email)
+	assert.True(t, helperNotice.notice.ForManager)
+	assert.Equal(t, "helper", helperNotice.notice.CrewName)
+	assert.Equal(t, services.AssignmentRoleHelper, helperNotice.notice.Role)
+}
+
+func TestAssignmentNotifier_UnchangedCrewSendsNothing(t *testing.T) {
+	driver := assignmentRecipient("driver", true)
+	mailer := &fakeAssignmentMailer{}
+	notifier := services.NewAssignmentNotifier(fakeAssignmentRecipients{driver.ID: driver}, mailer)
+
+	sent, err := notifier.Process(context.Background(), services.AssignmentChange{
+		LicensePlate:     "ABC1234",
+		PreviousDriverID: &driver.ID,
+		DriverID:         &driver.ID,
+	})
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, mailer.sent)
+}
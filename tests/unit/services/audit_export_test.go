@@ -0,0 +1,54 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestAuditLogEncoder_CSVSerializesMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	encoder, err := services.NewAuditLogEncoder("csv", &buf)
+	require.NoError(t, err)
+
+	log := &models.AuditLog{
+		ID:       uuid.New(),
+		Action:   "vehicle_import",
+		Resource: "vehicle",
+		Success:  true,
+		Metadata: map[string]interface{}{"rejected": 1, "created": 3},
+	}
+	require.NoError(t, encoder.Encode(log))
+	require.NoError(t, encoder.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Metadata", records[0][len(records[0])-1])
+	assert.Equal(t, `{"created":3,"rejected":1}`, records[1][len(records[1])-1])
+}
+
+func TestAuditLogEncoder_JSONArray(t *testing.T) {
+	for _, count := range []int{0, 1, 3} {
+		var buf bytes.Buffer
+		encoder, err := services.NewAuditLogEncoder("json", &buf)
+		require.NoError(t, err)
+
+		for i := 0; i < count; i++ {
+			require.NoError(t, encoder.Encode(&models.AuditLog{ID: uuid.New(), Action: "LOGIN"}))
+		}
+		require.NoError(t, encoder.Close())
+
+		var logs []models.AuditLog
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logs), buf.String())
+		assert.Len(t, logs, count)
+	}
+}
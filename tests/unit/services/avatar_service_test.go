@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/tests/testutils/mocks"
+)
+
+// memoryStorage is an in-memory FileStorage
+type memoryStorage struct {
+	files   map[string][]byte
+	types   map[string]string
+	deleted []string
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	s.files[key] = data
+	s.types[key] = contentType
+	return "https://cdn.example.com/" + key, nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *memoryStorage) KeyForURL(fileURL string) (string, bool) {
+	key, ok := strings.CutPrefix(fileURL, "https://cdn.example.com/")
+	return key, ok
+}
+
+// testImage returns a width x height PNG or JPEG
+func testImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	} else {
+		require.NoError(t, png.Encode(&buf, img))
+	}
+	return buf.Bytes()
+}
+
+func TestAvatarService_UploadResizesAndStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	storage := newMemoryStorage()
+	service := services.NewAvatarService(storage, &userRepoAdapter{userRepo}, 1<<20, 64)
+
+	userID := uuid.New()
+	oldAvatar := "https://cdn.example.com/avatars/" + userID.String() + "/old.png"
+	storage.files["avatars/"+userID.String()+"/old.png"] = []byte("old")
+
+	userRepo.EXPECT().GetByID(gomock.Any(), userID).Return(&models.User{ID: userID, Avatar: &oldAvatar}, nil)
+	var savedAvatar string
+	userRepo.EXPECT().Update(gomock.Any(), userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, req models.UpdateUserRequest) (*models.User, error) {
+			savedAvatar = req.Avatar
+			return &models.User{ID: userID, Avatar: &req.Avatar}, nil
+		})
+
+	url, err := service.Upload(context.Background(), userID, bytes.NewReader(testImage(t, "jpeg", 200, 100)))
+
+	require.NoError(t, err)
+	assert.Equal(t, url, savedAvatar)
+	assert.True(t, strings.HasPrefix(url, "https://cdn.example.com/avatars/"+userID.String()+"/"))
+	assert.True(t, strings.HasSuffix(url, ".jpg"))
+
+	key, _ := storage.KeyForURL(url)
+	assert.Equal(t, "image/jpeg", storage.types[key])
+	stored, _, err := image.DecodeConfig(bytes.NewReader(storage.files[key]))
+	require.NoError(t, err)
+	assert.Equal(t, 64, stored.Width)
+	assert.Equal(t, 32, stored.Height)
+
+	assert.Equal(t, []string{"avatars/" + userID.String() + "/old.png"}, storage.deleted)
+}
+
+func TestAvatarService_UploadRejectsLargeFile(t *testing.T) {
+	service := services.NewAvatarService(newMemoryStorage(), nil, 100, 64)
+
+	_, err := service.Upload(context.Background(), uuid.New(), bytes.NewReader(testImage(t, "png", 64, 64)))
+
+	assert.ErrorIs(t, err, services.ErrAvatarTooLarge)
+}
+
+func TestAvatarService_UploadRejectsNonImage(t *testing.T) {
+	service := services.NewAvatarService(newMemoryStorage(), nil, 1<<20, 64)
+
+	_, err := service.Upload(context.Background(), uuid.New(), strings.NewReader("<svg xmlns='http://www.w3.org/2000/svg'></svg>"))
+
+	assert.ErrorIs(t, err, services.ErrAvatarUnsupportedType)
+}
+
+func TestAvatarService_UploadRejectsCorruptImage(t *testing.T) {
+	service := services.NewAvatarService(newMemoryStorage(), nil, 1<<20, 64)
+	data := testImage(t, "png", 16, 16)[:40] // valid signature, truncated body
+
+	_, err := service.Upload(context.Background(), uuid.New(), bytes.NewReader(data))
+
+	assert.ErrorIs(t, err, services.ErrAvatarInvalidImage)
+}
+
+func TestFitImage_KeepsSmallImages(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 30))
+
+	assert.Same(t, img, services.FitImage(img, 64))
+}
+
+func TestFitImage_KeepsAspectRatio(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 900))
+
+	fitted := services.FitImage(img, 90)
+
+	assert.Equal(t, 30, fitted.Bounds().Dx())
+	assert.Equal(t, 90, fitted.Bounds().Dy())
+}
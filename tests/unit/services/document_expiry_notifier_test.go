@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/tests/testutils/mocks"
+)
+
+// fakeDocumentRepo returns canned expiring documents per company
+type fakeDocumentRepo struct {
+	repository.VehicleDocumentRepositoryInterface
+	expiring   map[uuid.UUID][]models.ExpiringDocument
+	withinDays int
+}
+
+func (f *fakeDocumentRepo) GetExpiringDocuments(ctx context.Context, companyID uuid.UUID, withinDays int) ([]models.ExpiringDocument, error) {
+	f.withinDays = withinDays
+	return f.expiring[companyID], nil
+}
+
+// fakeCompanyRepo serves a fixed company list
+type fakeCompanyRepo struct {
+	repository.CompanyRepositoryInterface
+	companies []models.Company
+}
+
+func (f *fakeCompanyRepo) List(ctx context.Context, limit, offset int) ([]models.Company, error) {
+	if offset >= len(f.companies) {
+		return []models.Company{}, nil
+	}
+	end := offset + limit
+	if end > len(f.companies) {
+		end = len(f.companies)
+	}
+	return f.companies[offset:end], nil
+}
+
+type sentAlert struct {
+	email string
+	docs  int
+}
+
+// fakeMailer records the alerts it was asked to send
+type fakeMailer struct {
+	sent    []sentAlert
+	failFor string
+}
+
+func (f *fakeMailer) SendDocumentExpiryAlert(email, userName string, docs []models.ExpiringDocument, withinDays int) error {
+	if email == f.failFor {
+		return errors.New("smtp unavailable")
+	}
+	f.sent = append(f.sent, sentAlert{email: email, docs: len(docs)})
+	return nil
+}
+
+func TestDocumentExpiryNotifier_EmailsActiveManagersOfCompaniesWithExpiringDocuments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+
+	withDocs := models.Company{ID: uuid.New(), Status: "active"}
+	withoutDocs := models.Company{ID: uuid.New(), Status: "active"}
+	suspended := models.Company{ID: uuid.New(), Status: "suspended"}
+
+	docs := &fakeDocumentRepo{expiring: map[uuid.UUID][]models.ExpiringDocument{
+		withDocs.ID: {
+			{DocumentID: uuid.New(), LicensePlate: "ABC1D23", Type: "insurance", ExpiresAt: time.Now().AddDate(0, 0, 5), DaysRemaining: 5},
+			{DocumentID: uuid.New(), LicensePlate: "XYZ9K87", Type: "registration", ExpiresAt: time.Now().AddDate(0, 0, -1), DaysRemaining: -1},
+		},
+		suspended.ID: {
+			{DocumentID: uuid.New(), LicensePlate: "OLD0A00", Type: "insurance", ExpiresAt: time.Now(), DaysRemaining: 0},
+		},
+	}}
+	companies := &fakeCompanyRepo{companies: []models.Company{withDocs, withoutDocs, suspended}}
+	mailer := &fakeMailer{}
+
+	userRepo.EXPECT().
+		ListByCompanyAndRoles(gomock.Any(), &withDocs.ID, []string{"company_admin", "manager"}, gomock.Any(), 0, repository.Sort{}).
+		Return([]*models.User{
+			{ID: uuid.New(), Email: "admin@fleet.com", Name: "Admin", Active: true},
+			{ID: uuid.New(), Email: "former@fleet.com", Name: "Former", Active: false},
+			{ID: uuid.New(), Email: "manager@fleet.com", Name: "Manager", Active: true},
+		}, nil)
+
+	notifier := services.NewDocumentExpiryNotifier(docs, companies, &userRepoAdapter{userRepo}, mailer, 15)
+
+	sent, err := notifier.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, 15, docs.withinDays)
+	assert.Equal(t, []sentAlert{
+		{email: "admin@fleet.com", docs: 2},
+		{email: "manager@fleet.com", docs: 2},
+	}, mailer.sent)
+}
+
+func TestDocumentExpiryNotifier_MailFailureDoesNotStopOtherRecipients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+
+	company := models.Company{ID: uuid.New(), Status: "active"}
+	docs := &fakeDocumentRepo{expiring: map[uuid.UUID][]models.ExpiringDocument{
+		company.ID: {{DocumentID: uuid.New(), LicensePlate: "ABC1D23", Type: "insurance", DaysRemaining: 3}},
+	}}
+	mailer := &fakeMailer{failFor: "broken@fleet.com"}
+
+	userRepo.EXPECT().
+		ListByCompanyAndRoles(gomock.Any(), &company.ID, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*models.User{
+			{ID: uuid.New(), Email: "broken@fleet.com", Active: true},
+			{ID: uuid.New(), Email: "ok@fleet.com", Active: true},
+		}, nil)
+
+	notifier := services.NewDocumentExpiryNotifier(docs, &fakeCompanyRepo{companies: []models.Company{company}}, &userRepoAdapter{userRepo}, mailer, 30)
+
+	sent, err := notifier.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []sentAlert{{email: "ok@fleet.com", docs: 1}}, mailer.sent)
+}
@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/paulochiaradia/dashtrack/internal/config"
+	"github.com/paulochiaradia/dashtrack/internal/metrics"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+	"github.com/paulochiaradia/dashtrack/tests/testutils"
+)
+
+// unreachableSMTPConfig points at a local port nothing listens on
+func unreachableSMTPConfig(t *testing.T) *config.Config {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	cfg := &config.Config{}
+	cfg.SMTP.Host = "127.0.0.1"
+	cfg.SMTP.Port = strconv.Itoa(port)
+	cfg.SMTP.From = "noreply@example.com"
+	return cfg
+}
+
+func TestSendEmail_FailureRecordsSpanAndMetrics(t *testing.T) {
+	recorder := testutils.InstallSpanRecorder(t)
+	emailService := services.NewEmailService(unreachableSMTPConfig(t))
+
+	failures := metrics.EmailSentTotal.WithLabelValues(services.EmailTypeBlockedAccount, "failure")
+	before := testutil.ToFloat64(failures)
+
+	err := emailService.SendEmail(services.EmailData{
+		Type:    services.EmailTypeBlockedAccount,
+		To:      "user@example.com",
+		Subject: "Conta bloqueada",
+		Body:    "corpo",
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(failures))
+
+	span := recorder.RequireSpan(t, "EmailService.SendEmail")
+	assert.Equal(t, attribute.StringValue(services.EmailTypeBlockedAccount), testutils.SpanAttributes(span)["email.type"])
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
+
+func TestSendEmail_UntypedEmailCountedAsOther(t *testing.T) {
+	emailService := services.NewEmailService(unreachableSMTPConfig(t))
+
+	failures := metrics.EmailSentTotal.WithLabelValues(services.EmailTypeOther, "failure")
+	before := testutil.ToFloat64(failures)
+
+	// Rejected before reaching SMTP: no recipient
+	err := emailService.SendEmail(services.EmailData{Subject: "Sem destinatário"})
+
+	require.Error(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(failures))
+}
+
+// alternativeParts parses a multipart/alternative message into its decoded parts by content type
+func alternativeParts(t *testing.T, raw string) map[string]string {
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = strings.ReplaceAll(string(content), "\r\n", "\n")
+	}
+	return parts
+}
+
+func smtpServerConfig(server *testutils.SMTPServer) *config.Config {
+	cfg := &config.Config{}
+	cfg.SMTP.Host = server.Host
+	cfg.SMTP.Port = server.Port
+	cfg.SMTP.From = "noreply@example.com"
+	cfg.SMTP.FromName = "DashTrack"
+	return cfg
+}
+
+func TestSendEmail_HTMLSentWithGeneratedTextAlternative(t *testing.T) {
+	server := testutils.StartSMTPServer(t)
+	emailService := services.NewEmailService(smtpServerConfig(server))
+
+	err := emailService.SendEmail(services.EmailData{
+		To:      "user@example.com",
+		Subject: "Aviso",
+		Body: `<html><head><style>p { color: red; }</style></head><body>
+			<h1>Olá Maria</h1><p>Sua conta foi <strong>bloqueada</strong> &amp; protegida.</p>
+			<ul><li>Item um</li><li>Item dois</li></ul>
+			<p><a href="https://app.example.com/reset">Redefinir senha</a></p></body></html>`,
+		IsHTML: true,
+	})
+	require.NoError(t, err)
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	parts := alternativeParts(t, messages[0])
+
+	assert.Contains(t, parts["text/html"], "<strong>bloqueada</strong>")
+	text := parts["text/plain"]
+	assert.Contains(t, text, "Olá Maria")
+	assert.Contains(t, text, "Sua conta foi bloqueada & protegida.")
+	assert.Contains(t, text, "- Item um\n- Item dois\n")
+	assert.Contains(t, text, "Redefinir senha (https://app.example.com/reset)")
+	assert.NotContains(t, text, "<")
+	assert.NotContains(t, text, "color: red")
+}
+
+func TestSendEmail_ExplicitTextBodyUsed(t *testing.T) {
+	server := testutils.StartSMTPServer(t)
+	emailService := services.NewEmailService(smtpServerConfig(server))
+
+	err := emailService.SendEmail(services.EmailData{
+		To:       "user@example.com",
+		Subject:  "Aviso",
+		Body:     "<p>Versão HTML</p>",
+		TextBody: "Versão em texto escrita à mão",
+		IsHTML:   true,
+	})
+	require.NoError(t, err)
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	parts := alternativeParts(t, messages[0])
+	assert.Equal(t, "Versão em texto escrita à mão", parts["text/plain"])
+	assert.Equal(t, "<p>Versão HTML</p>", parts["text/html"])
+}
+
+func TestNewEmailSender_NoopWithoutHost(t *testing.T) {
+	sender, err := services.NewEmailSender(&config.Config{})
+
+	require.NoError(t, err)
+	assert.IsType(t, &services.NoopEmailService{}, sender)
+	assert.NoError(t, sender.SendPasswordResetCode("user@example.com", "123456", "Maria"))
+	assert.ErrorIs(t, sender.SendTestEmail("user@example.com", "master@example.com"), services.ErrEmailDisabled)
+}
+
+func TestNewEmailSender_RejectsInvalidSMTPConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.SMTP.Host = "smtp.example.com"
+	cfg.SMTP.Port = "0"
+	cfg.SMTP.From = "noreply@example.com"
+	cfg.SMTP.TLSMode = "ssl"
+
+	_, err := services.NewEmailSender(cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SMTP_PORT")
+	assert.Contains(t, err.Error(), "SMTP_TLS_MODE")
+}
+
+func TestNewEmailSender_DeliversThroughConfiguredServer(t *testing.T) {
+	server := testutils.StartSMTPServer(t)
+	cfg := smtpServerConfig(server)
+	cfg.SMTP.TLSMode = config.SMTPTLSModeNone
+
+	sender, err := services.NewEmailSender(cfg)
+	require.NoError(t, err)
+	require.NoError(t, sender.SendTestEmail("ops@example.com", "master@example.com"))
+
+	assert.Len(t, server.Messages(), 1)
+}
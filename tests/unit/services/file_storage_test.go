@@ -0,0 +1,101 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestLocalFileStorage_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	storage := services.NewLocalFileStorage(dir, "/uploads/")
+
+	url, err := storage.Put(context.Background(), "avatars/u1/a.png", "image/png", []byte("png"))
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/avatars/u1/a.png", url)
+
+	data, err := os.ReadFile(filepath.Join(dir, "avatars", "u1", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	key, ok := storage.KeyForURL(url)
+	require.True(t, ok)
+	require.NoError(t, storage.Delete(context.Background(), key))
+	assert.NoFileExists(t, filepath.Join(dir, "avatars", "u1", "a.png"))
+
+	// Deleting again is not an error
+	assert.NoError(t, storage.Delete(context.Background(), key))
+
+	_, ok = storage.KeyForURL("https://gravatar.com/avatar/abc")
+	assert.False(t, ok)
+}
+
+func TestLocalFileStorage_RejectsEscapingKeys(t *testing.T) {
+	storage := services.NewLocalFileStorage(t.TempDir(), "/uploads")
+
+	for _, key := range []string{"../etc/passwd", "/etc/passwd", "avatars/../../x", ""} {
+		_, err := storage.Put(context.Background(), key, "image/png", []byte("x"))
+		assert.ErrorIs(t, err, services.ErrInvalidStorageKey, key)
+	}
+}
+
+func TestS3FileStorage_PutSignsRequest(t *testing.T) {
+	var gotMethod, gotPath, gotAuth, gotContentType, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotContentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	storage := services.NewS3FileStorage(services.S3Config{
+		Endpoint:        server.URL,
+		Region:          "eu-west-1",
+		Bucket:          "avatars-bucket",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		PublicURL:       "https://cdn.example.com",
+	})
+
+	url, err := storage.Put(context.Background(), "avatars/u1/a.jpg", "image/jpeg", []byte("jpeg-bytes"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/avatars/u1/a.jpg", url)
+	assert.Equal(t, http.MethodPut, gotMethod)
+	assert.Equal(t, "/avatars-bucket/avatars/u1/a.jpg", gotPath)
+	assert.Equal(t, "image/jpeg", gotContentType)
+	assert.Equal(t, "jpeg-bytes", gotBody)
+	assert.True(t, strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), gotAuth)
+	assert.Contains(t, gotAuth, "/eu-west-1/s3/aws4_request")
+	assert.Contains(t, gotAuth, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date")
+
+	key, ok := storage.KeyForURL(url)
+	assert.True(t, ok)
+	assert.Equal(t, "avatars/u1/a.jpg", key)
+}
+
+func TestS3FileStorage_PutFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	storage := services.NewS3FileStorage(services.S3Config{Endpoint: server.URL, Region: "us-east-1", Bucket: "b"})
+
+	_, err := storage.Put(context.Background(), "avatars/a.png", "image/png", []byte("x"))
+
+	assert.Error(t, err)
+}
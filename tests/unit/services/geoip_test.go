@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+const testGeoIPDatabase = `network,country_iso_code
+177.0.0.0/10,BR
+8.8.8.0/24,us
+2804::/16,BR
+203.0.113.0/24,
+`
+
+func newTestGeoIPProvider(t *testing.T) *services.CIDRCountryProvider {
+	provider, err := services.NewCIDRCountryProvider(strings.NewReader(testGeoIPDatabase))
+	require.NoError(t, err)
+	return provider
+}
+
+func TestCIDRCountryProvider_CountryCode(t *testing.T) {
+	provider := newTestGeoIPProvider(t)
+
+	tests := []struct {
+		ip      string
+		country string
+	}{
+		{"177.10.20.30", "BR"},
+		{"177.63.255.255", "BR"},
+		{"177.64.0.0", ""},
+		{"8.8.8.8", "US"},
+		{"2804:14c::1", "BR"},
+		{"203.0.113.5", ""},
+		{"1.1.1.1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			country, err := provider.CountryCode(net.ParseIP(tt.ip))
+			require.NoError(t, err)
+			assert.Equal(t, tt.country, country)
+		})
+	}
+}
+
+func TestCIDRCountryProvider_RejectsInvalidNetwork(t *testing.T) {
+	_, err := services.NewCIDRCountryProvider(strings.NewReader("network,country\n10.0.0.0/8,BR\nnot-a-network,US\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "record 3")
+}
+
+func newSuspiciousLoginDetector(t *testing.T) (*services.SuspiciousLoginDetector, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	return services.NewSuspiciousLoginDetector(newTestGeoIPProvider(t), repository.NewAuthLogRepository(mockDB)), mock
+}
+
+func expectLoginCountries(mock sqlmock.Sqlmock, userID uuid.UUID, countries ...string) {
+	rows := sqlmock.NewRows([]string{"country_code"})
+	for _, country := range countries {
+		rows.AddRow(country)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT country_code")).
+		WithArgs(userID).
+		WillReturnRows(rows)
+}
+
+func TestSuspiciousLoginDetector_FlagsNewCountry(t *testing.T) {
+	detector, mock := newSuspiciousLoginDetector(t)
+	userID := uuid.New()
+	expectLoginCountries(mock, userID, "BR")
+
+	country, newCountry := detector.Check(context.Background(), userID, "8.8.8.8")
+
+	assert.Equal(t, "US", country)
+	assert.True(t, newCountry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuspiciousLoginDetector_KnownCountryIsNotFlagged(t *testing.T) {
+	detector, mock := newSuspiciousLoginDetector(t)
+	userID := uuid.New()
+	expectLoginCountries(mock, userID, "BR", "US")
+
+	country, newCountry := detector.Check(context.Background(), userID, "177.10.20.30")
+
+	assert.Equal(t, "BR", country)
+	assert.False(t, newCountry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuspiciousLoginDetector_FirstLocatedLoginIsNotFlagged(t *testing.T) {
+	detector, mock := newSuspiciousLoginDetector(t)
+	userID := uuid.New()
+	expectLoginCountries(mock, userID)
+
+	country, newCountry := detector.Check(context.Background(), userID, "8.8.8.8")
+
+	assert.Equal(t, "US", country)
+	assert.False(t, newCountry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuspiciousLoginDetector_UnlocatableIPSkipsLookup(t *testing.T) {
+	detector, mock := newSuspiciousLoginDetector(t)
+
+	for _, ip := range []string{"127.0.0.1", "192.168.0.10", "1.1.1.1", "not-an-ip"} {
+		country, newCountry := detector.Check(context.Background(), uuid.New(), ip)
+
+		assert.Empty(t, country, ip)
+		assert.False(t, newCountry, ip)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
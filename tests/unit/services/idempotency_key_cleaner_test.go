@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeIdempotencyRepo keeps records in memory and purges them by expires_at
+type fakeIdempotencyRepo struct {
+	records []*models.IdempotencyRecord
+}
+
+func (f *fakeIdempotencyRepo) Get(ctx context.Context, scope, key string) (*models.IdempotencyRecord, error) {
+	for _, record := range f.records {
+		if record.Scope == scope && record.Key == key && record.ExpiresAt.After(time.Now()) {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeIdempotencyRepo) Claim(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	f.records = append(f.records, record)
+	return true, nil
+}
+
+func (f *fakeIdempotencyRepo) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	return nil
+}
+
+func (f *fakeIdempotencyRepo) Release(ctx context.Context, scope, key string) error {
+	return nil
+}
+
+func (f *fakeIdempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	kept := f.records[:0]
+	for _, record := range f.records {
+		if record.ExpiresAt.After(now) {
+			kept = append(kept, record)
+		}
+	}
+	deleted := int64(len(f.records) - len(kept))
+	f.records = kept
+	return deleted, nil
+}
+
+func TestIdempotencyKeyCleaner_PurgesOnlyExpiredKeys(t *testing.T) {
+	repo := &fakeIdempotencyRepo{records: []*models.IdempotencyRecord{
+		{Scope: "s", Key: "expired", ExpiresAt: time.Now().Add(-time.Minute)},
+		{Scope: "s", Key: "live", ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	cleaner := services.NewIdempotencyKeyCleaner(repo)
+
+	deleted, err := cleaner.RunOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	require.Len(t, repo.records, 1)
+	assert.Equal(t, "live", repo.records[0].Key)
+}
@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeIPAllowlistSource serves a fixed list of ranges for every company
+type fakeIPAllowlistSource []string
+
+func (f fakeIPAllowlistSource) GetByCompany(ctx context.Context, companyID uuid.UUID) ([]models.IPAllowlistEntry, error) {
+	entries := make([]models.IPAllowlistEntry, 0, len(f))
+	for _, cidr := range f {
+		entries = append(entries, models.IPAllowlistEntry{ID: uuid.New(), CompanyID: companyID, CIDR: cidr})
+	}
+	return entries, nil
+}
+
+func TestIPAllowlist_Allows(t *testing.T) {
+	allowlist := services.NewIPAllowlist(fakeIPAllowlistSource{"192.168.10.0/24", "2001:db8::/32"})
+
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"192.168.10.42", true},
+		{"::ffff:192.168.10.42", true},
+		{"192.168.11.1", false},
+		{"2001:db8:1::5", true},
+		{"2001:db9::1", false},
+		{"not-an-ip", false},
+	}
+
+	for _, tt := range tests {
+		allowed, err := allowlist.Allows(context.Background(), uuid.New(), tt.ip)
+		require.NoError(t, err)
+		assert.Equal(t, tt.allowed, allowed, tt.ip)
+	}
+}
+
+func TestIPAllowlist_EmptyAllowlistHasNoRestriction(t *testing.T) {
+	allowlist := services.NewIPAllowlist(fakeIPAllowlistSource{})
+
+	allowed, err := allowlist.Allows(context.Background(), uuid.New(), "203.0.113.9")
+
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestParseAllowlistCIDR(t *testing.T) {
+	valid := map[string]string{
+		"10.1.2.3/8":      "10.0.0.0/8",
+		" 203.0.113.9 ":   "203.0.113.9/32",
+		"2001:db8::1/32":  "2001:db8::/32",
+		"2001:db8::1":     "2001:db8::1/128",
+		"::ffff:10.0.0.1": "10.0.0.1/32",
+		"192.168.0.0/16":  "192.168.0.0/16",
+	}
+	for input, expected := range valid {
+		cidr, err := services.ParseAllowlistCIDR(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, cidr, input)
+	}
+
+	for _, input := range []string{"", "10.0.0.0/33", "office", "fe80::1%eth0"} {
+		_, err := services.ParseAllowlistCIDR(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestIPAllowlist_CompanyCanTurnEnforcementOff(t *testing.T) {
+	enforcedID, disabledID := uuid.New(), uuid.New()
+	allowlist := services.NewIPAllowlist(fakeIPAllowlistSource{"192.168.10.0/24"})
+	allowlist.SetCompanySettings(fakeCompanySettings{
+		disabledID: {MaxSessionsPerUser: 3, EnforceIPAllowlist: false},
+	})
+
+	allowed, err := allowlist.Allows(context.Background(), enforcedID, "203.0.113.9")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = allowlist.Allows(context.Background(), disabledID, "203.0.113.9")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
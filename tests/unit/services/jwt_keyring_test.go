@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+const (
+	oldSigningSecret = "old-signing-secret-of-32-characters!"
+	newSigningSecret = "new-signing-secret-of-32-characters!"
+)
+
+// tokenServiceWithKeys returns a token service signing with activeKeyID from keys
+func tokenServiceWithKeys(t *testing.T, keys map[string]string, activeKeyID string) *services.TokenService {
+	keyring, err := services.NewJWTKeyring(keys, activeKeyID)
+	require.NoError(t, err)
+	ts := services.NewTokenService(nil, "", 15*time.Minute, 24*time.Hour)
+	ts.SetKeyring(keyring)
+	return ts
+}
+
+func TestJWTKeyring_RotationKeepsOldTokensValid(t *testing.T) {
+	user := &models.User{ID: uuid.New(), PasswordChangedAt: time.Now()}
+
+	before := tokenServiceWithKeys(t, map[string]string{"2026-09": oldSigningSecret}, "2026-09")
+	oldToken, err := before.GeneratePasswordChangeToken(user, false)
+	require.NoError(t, err)
+
+	during := tokenServiceWithKeys(t, map[string]string{"2026-09": oldSigningSecret, "2026-10": newSigningSecret}, "2026-10")
+	newToken, err := during.GeneratePasswordChangeToken(user, false)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", parsed.Header["kid"])
+
+	for _, token := range []string{oldToken, newToken} {
+		claims, err := during.ValidatePasswordChangeToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+	}
+
+	// Once the old key is dropped, the tokens it signed stop working
+	after := tokenServiceWithKeys(t, map[string]string{"2026-10": newSigningSecret}, "2026-10")
+	_, err = after.ValidatePasswordChangeToken(oldToken)
+	assert.Error(t, err)
+	_, err = after.ValidatePasswordChangeToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTKeyring_TokenWithoutKidUsesDefaultKey(t *testing.T) {
+	userID := uuid.New()
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":                 userID.String(),
+		"purpose":             "password_change",
+		"password_changed_at": time.Now().Unix(),
+		"exp":                 time.Now().Add(time.Minute).Unix(),
+	})
+	token, err := legacy.SignedString([]byte(oldSigningSecret))
+	require.NoError(t, err)
+
+	ts := tokenServiceWithKeys(t, map[string]string{services.DefaultJWTKeyID: oldSigningSecret, "2026-10": newSigningSecret}, "2026-10")
+
+	claims, err := ts.ValidatePasswordChangeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+}
+
+func TestNewJWTKeyring_ActiveKeyMustExist(t *testing.T) {
+	_, err := services.NewJWTKeyring(map[string]string{"2026-09": oldSigningSecret}, "2026-10")
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestLiveFeedHub_DeliversOnlyToTheVehicleSubscribers(t *testing.T) {
+	hub := services.NewLiveFeedHub(4)
+	vehicleID := uuid.New()
+
+	sub := hub.Subscribe(vehicleID)
+	other := hub.Subscribe(uuid.New())
+	defer sub.Close()
+	defer other.Close()
+
+	hub.Publish(models.LiveEvent{Type: models.LiveEventReading, VehicleID: vehicleID})
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, models.LiveEventReading, event.Type)
+		assert.Equal(t, vehicleID, event.VehicleID)
+	default:
+		t.Fatal("expected an event for the subscribed vehicle")
+	}
+	assert.Empty(t, other.Events())
+}
+
+func TestLiveFeedHub_DropsSubscriberThatFallsBehind(t *testing.T) {
+	hub := services.NewLiveFeedHub(2)
+	vehicleID := uuid.New()
+	slow := hub.Subscribe(vehicleID)
+
+	for i := 0; i < 3; i++ {
+		hub.Publish(models.LiveEvent{Type: models.LiveEventReading, VehicleID: vehicleID})
+	}
+
+	assert.True(t, slow.Lagged())
+	assert.False(t, hub.HasSubscribers(vehicleID))
+
+	// The buffered events are still drained before the channel reports closed
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, 2, received)
+}
+
+func TestLiveFeedHub_CloseUnsubscribes(t *testing.T) {
+	hub := services.NewLiveFeedHub(1)
+	vehicleID := uuid.New()
+	sub := hub.Subscribe(vehicleID)
+	require.True(t, hub.HasSubscribers(vehicleID))
+
+	sub.Close()
+	sub.Close()
+
+	assert.False(t, hub.HasSubscribers(vehicleID))
+	assert.False(t, sub.Lagged())
+	hub.Publish(models.LiveEvent{VehicleID: vehicleID})
+	_, open := <-sub.Events()
+	assert.False(t, open)
+}
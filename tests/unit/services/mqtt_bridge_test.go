@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func newTestBridge(device *models.ESP32Device, sensor *models.Sensor) (*services.MQTTBridge, *fakeSensorRepo) {
+	repo := newFakeSensorRepo(sensor)
+	ingestion := services.NewSensorIngestionService(repo)
+	bridge := services.NewMQTTBridge(services.MQTTConfig{}, ingestion, fakeDeviceLookup{device.DeviceID: device})
+	return bridge, repo
+}
+
+func TestParseMQTTTopic(t *testing.T) {
+	companyID, vehicleID := uuid.New(), uuid.New()
+
+	gotCompany, gotVehicle, deviceID, err := services.ParseMQTTTopic("dashtrack/" + companyID.String() + "/" + vehicleID.String() + "/esp32-01")
+
+	require.NoError(t, err)
+	assert.Equal(t, companyID, gotCompany)
+	assert.Equal(t, vehicleID, gotVehicle)
+	assert.Equal(t, "esp32-01", deviceID)
+
+	for _, topic := range []string{
+		"dashtrack/" + companyID.String() + "/" + vehicleID.String(),
+		"other/" + companyID.String() + "/" + vehicleID.String() + "/esp32-01",
+		"dashtrack/not-a-uuid/" + vehicleID.String() + "/esp32-01",
+		"dashtrack/" + companyID.String() + "/" + vehicleID.String() + "/",
+	} {
+		_, _, _, err := services.ParseMQTTTopic(topic)
+		assert.ErrorIs(t, err, services.ErrInvalidMQTTTopic, topic)
+	}
+}
+
+func TestMQTTBridge_IngestsReadingOfAuthorizedDevice(t *testing.T) {
+	companyID, vehicleID := uuid.New(), uuid.New()
+	device := &models.ESP32Device{DeviceID: "esp32-01", CompanyID: &companyID, VehicleID: &vehicleID, Status: "online"}
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	bridge, repo := newTestBridge(device, sensor)
+
+	topic := "dashtrack/" + companyID.String() + "/" + vehicleID.String() + "/esp32-01"
+	body := []byte(`{"type":"dht11","timestamp":"2024-06-01T12:00:00Z","data":{"temperature":36.5,"humidity":40}}`)
+
+	err := bridge.HandleMessage(context.Background(), topic, body)
+
+	require.NoError(t, err)
+	require.Len(t, repo.dht11, 1)
+	assert.Equal(t, "esp32-01", repo.dht11[0].DeviceID)
+	assert.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), repo.dht11[0].Timestamp)
+	require.Len(t, repo.alerts, 1, "thresholds are checked like the HTTP path")
+}
+
+func TestMQTTBridge_RejectsDeviceOutsideTopicVehicle(t *testing.T) {
+	companyID, vehicleID := uuid.New(), uuid.New()
+	otherVehicle := uuid.New()
+	device := &models.ESP32Device{DeviceID: "esp32-01", CompanyID: &companyID, VehicleID: &otherVehicle, Status: "online"}
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	bridge, repo := newTestBridge(device, sensor)
+
+	topic := "dashtrack/" + companyID.String() + "/" + vehicleID.String() + "/esp32-01"
+	err := bridge.HandleMessage(context.Background(), topic, []byte(`{"type":"dht11","data":{"temperature":20,"humidity":40}}`))
+
+	assert.ErrorIs(t, err, services.ErrDeviceNotAuthorized)
+	assert.Empty(t, repo.dht11)
+}
+
+func TestMQTTBridge_RejectsInactiveOrUnknownDevice(t *testing.T) {
+	companyID, vehicleID := uuid.New(), uuid.New()
+	device := &models.ESP32Device{DeviceID: "esp32-01", CompanyID: &companyID, VehicleID: &vehicleID, Status: "inactive"}
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	bridge, _ := newTestBridge(device, sensor)
+	body := []byte(`{"type":"dht11","data":{"temperature":20,"humidity":40}}`)
+
+	err := bridge.HandleMessage(context.Background(), "dashtrack/"+companyID.String()+"/"+vehicleID.String()+"/esp32-01", body)
+	assert.ErrorIs(t, err, services.ErrDeviceNotAuthorized)
+
+	err = bridge.HandleMessage(context.Background(), "dashtrack/"+companyID.String()+"/"+vehicleID.String()+"/esp32-99", body)
+	assert.ErrorIs(t, err, services.ErrDeviceNotAuthorized)
+}
+
+func TestMQTTBridge_SharesValidationWithHTTPIngestion(t *testing.T) {
+	companyID, vehicleID := uuid.New(), uuid.New()
+	device := &models.ESP32Device{DeviceID: "esp32-01", CompanyID: &companyID, VehicleID: &vehicleID, Status: "online"}
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	bridge, repo := newTestBridge(device, sensor)
+
+	topic := "dashtrack/" + companyID.String() + "/" + vehicleID.String() + "/esp32-01"
+	err := bridge.HandleMessage(context.Background(), topic, []byte(`{"type":"dht11","data":{"temperature":20}}`))
+
+	var validationErr *services.SensorValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "humidity", validationErr.Field)
+	assert.Empty(t, repo.dht11)
+}
+
+func TestMQTTBridge_StartDoesNotBlockOnUnreachableBroker(t *testing.T) {
+	ingestion := services.NewSensorIngestionService(newFakeSensorRepo())
+	bridge := services.NewMQTTBridge(services.MQTTConfig{BrokerURL: "tcp://127.0.0.1:1", ClientID: "test"}, ingestion, fakeDeviceLookup{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	go func() {
+		bridge.Start(ctx)
+		close(started)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start blocked on an unreachable broker")
+	}
+	assert.False(t, bridge.Connected())
+	cancel()
+}
@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeCompanySettings serves the settings of each company, defaults for unknown ones
+type fakeCompanySettings map[uuid.UUID]models.CompanySettings
+
+func (f fakeCompanySettings) Get(ctx context.Context, companyID uuid.UUID) (*models.CompanySettingsRecord, error) {
+	settings, ok := f[companyID]
+	if !ok {
+		settings = models.DefaultCompanySettings()
+	}
+	return &models.CompanySettingsRecord{CompanyID: companyID, Settings: settings}, nil
+}
+
+func TestPasswordExpiryPolicy_Expired(t *testing.T) {
+	strictID, exemptID, defaultID := uuid.New(), uuid.New(), uuid.New()
+	seven, zero := 7, 0
+	policy := services.NewPasswordExpiryPolicy(90, fakeCompanySettings{
+		strictID:  {MaxSessionsPerUser: 3, PasswordMaxAgeDays: &seven},
+		exemptID:  {MaxSessionsPerUser: 3, PasswordMaxAgeDays: &zero},
+		defaultID: models.DefaultCompanySettings(),
+	})
+
+	daysAgo := func(days int) time.Time { return time.Now().Add(-time.Duration(days) * 24 * time.Hour) }
+
+	tests := []struct {
+		name      string
+		companyID *uuid.UUID
+		changedAt time.Time
+		expired   bool
+	}{
+		{"global policy, recent password", nil, daysAgo(30), false},
+		{"global policy, old password", nil, daysAgo(120), true},
+		{"company without override uses global policy", &defaultID, daysAgo(120), true},
+		{"company override shortens the lifetime", &strictID, daysAgo(10), true},
+		{"company override disables expiry", &exemptID, daysAgo(400), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: uuid.New(), CompanyID: tt.companyID, PasswordChangedAt: tt.changedAt}
+
+			expired, err := policy.Expired(context.Background(), user)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expired, expired)
+		})
+	}
+}
+
+func TestPasswordExpiryPolicy_DisabledByDefault(t *testing.T) {
+	policy := services.NewPasswordExpiryPolicy(0, nil)
+	user := &models.User{ID: uuid.New(), PasswordChangedAt: time.Now().AddDate(-5, 0, 0)}
+
+	expired, err := policy.Expired(context.Background(), user)
+
+	require.NoError(t, err)
+	assert.False(t, expired)
+}
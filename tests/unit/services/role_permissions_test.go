@@ -0,0 +1,60 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeRolePermissionSource serves grants and counts how often they were read
+type fakeRolePermissionSource struct {
+	grants []models.RolePermission
+	err    error
+	loads  int
+}
+
+func (f *fakeRolePermissionSource) ListPermissions(ctx context.Context) ([]models.RolePermission, error) {
+	f.loads++
+	return f.grants, f.err
+}
+
+func TestRolePermissions_ChecksGrantsAndCachesThem(t *testing.T) {
+	source := &fakeRolePermissionSource{grants: []models.RolePermission{
+		{RoleName: "admin", Permission: models.PermissionUserReadHistory},
+	}}
+	permissions := services.NewRolePermissions(source)
+	ctx := context.Background()
+
+	assert.True(t, permissions.HasPermission(ctx, "admin", models.PermissionUserReadHistory))
+	assert.False(t, permissions.HasPermission(ctx, "driver", models.PermissionUserReadHistory))
+	assert.False(t, permissions.HasPermission(ctx, "admin", "user:delete"))
+	assert.Equal(t, 1, source.loads)
+}
+
+func TestRolePermissions_KeepsPreviousGrantsWhenReloadFails(t *testing.T) {
+	source := &fakeRolePermissionSource{grants: []models.RolePermission{
+		{RoleName: "admin", Permission: models.PermissionUserReadHistory},
+	}}
+	permissions := services.NewRolePermissions(source)
+	permissions.SetRefreshInterval(time.Nanosecond)
+	ctx := context.Background()
+
+	assert.True(t, permissions.HasPermission(ctx, "admin", models.PermissionUserReadHistory))
+
+	source.err = errors.New("connection refused")
+	assert.True(t, permissions.HasPermission(ctx, "admin", models.PermissionUserReadHistory))
+	assert.Equal(t, 2, source.loads)
+}
+
+func TestRolePermissions_DeniesUntilLoaded(t *testing.T) {
+	source := &fakeRolePermissionSource{err: errors.New("connection refused")}
+	permissions := services.NewRolePermissions(source)
+
+	assert.False(t, permissions.HasPermission(context.Background(), "master", models.PermissionUserReadHistory))
+}
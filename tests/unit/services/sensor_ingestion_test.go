@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeSensorRepo records readings and alerts; methods the ingestion path doesn't use panic
+type fakeSensorRepo struct {
+	repository.SensorRepositoryInterface
+	sensors map[string]*models.Sensor
+	dht11   []*models.DHT11Reading
+	gps     []*models.GPSReading
+	alerts  []*models.SensorAlert
+}
+
+func newFakeSensorRepo(sensors ...*models.Sensor) *fakeSensorRepo {
+	repo := &fakeSensorRepo{sensors: map[string]*models.Sensor{}}
+	for _, sensor := range sensors {
+		repo.sensors[sensor.DeviceID] = sensor
+	}
+	return repo
+}
+
+func (r *fakeSensorRepo) GetSensorByDeviceID(deviceID string) (*models.Sensor, error) {
+	if sensor, ok := r.sensors[deviceID]; ok {
+		return sensor, nil
+	}
+	return nil, errors.New("sql: no rows in result set")
+}
+
+func (r *fakeSensorRepo) CreateDHT11Reading(reading *models.DHT11Reading) error {
+	r.dht11 = append(r.dht11, reading)
+	return nil
+}
+
+func (r *fakeSensorRepo) CreateGPSReading(reading *models.GPSReading) error {
+	r.gps = append(r.gps, reading)
+	return nil
+}
+
+func (r *fakeSensorRepo) CreateSensorAlert(alert *models.SensorAlert) error {
+	alert.CreatedAt = time.Now()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+// fakeDeviceLookup returns the registered ESP32 devices by device ID
+type fakeDeviceLookup map[string]*models.ESP32Device
+
+func (f fakeDeviceLookup) GetByDeviceID(ctx context.Context, deviceID string) (*models.ESP32Device, error) {
+	return f[deviceID], nil
+}
+
+func dht11Payload(deviceID string, temperature, humidity float64) models.SensorDataPayload {
+	return models.SensorDataPayload{
+		DeviceID:  deviceID,
+		Type:      models.SensorTypeDHT11,
+		Timestamp: time.Now().UTC(),
+		Data:      map[string]interface{}{"temperature": temperature, "humidity": humidity},
+	}
+}
+
+func TestSensorIngestion_StoresReadingAndRaisesThresholdAlert(t *testing.T) {
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	repo := newFakeSensorRepo(sensor)
+	ingestion := services.NewSensorIngestionService(repo)
+
+	err := ingestion.Ingest(context.Background(), sensor, dht11Payload("esp32-01", 38, 50))
+
+	require.NoError(t, err)
+	require.Len(t, repo.dht11, 1)
+	assert.Equal(t, 38.0, repo.dht11[0].Temperature)
+	require.Len(t, repo.alerts, 1)
+	assert.Equal(t, "temperature_high", repo.alerts[0].Type)
+}
+
+func TestSensorIngestion_RejectsOutOfRangeReading(t *testing.T) {
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	repo := newFakeSensorRepo(sensor)
+	ingestion := services.NewSensorIngestionService(repo)
+
+	err := ingestion.Ingest(context.Background(), sensor, dht11Payload("esp32-01", 120, 50))
+
+	var validationErr *services.SensorValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "temperature", validationErr.Field)
+	assert.Empty(t, repo.dht11)
+}
+
+func TestSensorIngestion_ResolveSensorChecksRegistrationAndType(t *testing.T) {
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeGPS}
+	ingestion := services.NewSensorIngestionService(newFakeSensorRepo(sensor))
+
+	_, err := ingestion.ResolveSensor(dht11Payload("unknown", 20, 50))
+	assert.ErrorIs(t, err, services.ErrSensorNotRegistered)
+
+	_, err = ingestion.ResolveSensor(dht11Payload("esp32-01", 20, 50))
+	assert.ErrorIs(t, err, services.ErrSensorTypeMismatch)
+}
+
+func TestSensorIngestion_PublishesToVehicleLiveFeed(t *testing.T) {
+	vehicleID := uuid.New()
+	sensor := &models.Sensor{ID: uuid.New(), DeviceID: "esp32-01", Type: models.SensorTypeDHT11}
+	ingestion := services.NewSensorIngestionService(newFakeSensorRepo(sensor))
+	hub := services.NewLiveFeedHub(4)
+	ingestion.SetLiveFeed(hub, fakeDeviceLookup{"esp32-01": {DeviceID: "esp32-01", VehicleID: &vehicleID}})
+	sub := hub.Subscribe(vehicleID)
+	defer sub.Close()
+
+	require.NoError(t, ingestion.Ingest(context.Background(), sensor, dht11Payload("esp32-01", 38, 50)))
+
+	var types []string
+	for len(sub.Events()) > 0 {
+		event := <-sub.Events()
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{models.LiveEventReading, models.LiveEventAlert}, types)
+}
@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeTripRepo keeps trips in memory and applies the auto-close cutoff to them
+type fakeTripRepo struct {
+	trips []*models.VehicleTrip
+}
+
+func (f *fakeTripRepo) GetActiveTrips(ctx context.Context, vehicleID uuid.UUID) ([]models.VehicleTrip, error) {
+	trips := []models.VehicleTrip{}
+	for _, trip := range f.trips {
+		if trip.VehicleID == vehicleID && trip.Status == "active" {
+			trips = append(trips, *trip)
+		}
+	}
+	return trips, nil
+}
+
+func (f *fakeTripRepo) ListVehiclesWithDuplicateActiveTrips(ctx context.Context) ([]uuid.UUID, error) {
+	counts := map[uuid.UUID]int{}
+	vehicleIDs := []uuid.UUID{}
+	for _, trip := range f.trips {
+		if trip.Status != "active" {
+			continue
+		}
+		counts[trip.VehicleID]++
+		if counts[trip.VehicleID] == 2 {
+			vehicleIDs = append(vehicleIDs, trip.VehicleID)
+		}
+	}
+	return vehicleIDs, nil
+}
+
+func (f *fakeTripRepo) CloseTrips(ctx context.Context, ids []uuid.UUID, note string) (int64, error) {
+	var closed int64
+	for _, trip := range f.trips {
+		for _, id := range ids {
+			if trip.ID == id && trip.Status == "active" {
+				trip.Status = "auto_closed"
+				trip.Notes = &note
+				closed++
+			}
+		}
+	}
+	return closed, nil
+}
+
+func (f *fakeTripRepo) activeTrips(vehicleID uuid.UUID) []*models.VehicleTrip {
+	active := []*models.VehicleTrip{}
+	for _, trip := range f.trips {
+		if trip.VehicleID == vehicleID && trip.Status == "active" {
+			active = append(active, trip)
+		}
+	}
+	return active
+}
+
+func (f *fakeTripRepo) AutoCloseStaleTrips(ctx context.Context, startedBefore time.Time, note string) (int64, error) {
+	var closed int64
+	for _, trip := range f.trips {
+		if trip.Status == "active" && trip.StartTime.Before(startedBefore) {
+			trip.Status = "auto_closed"
+			trip.Notes = &note
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+func TestStaleTripCloser_ClosesOnlyTripsPastMaxDuration(t *testing.T) {
+	stale := &models.VehicleTrip{ID: uuid.New(), Status: "active", StartTime: time.Now().Add(-30 * time.Hour)}
+	recent := &models.VehicleTrip{ID: uuid.New(), Status: "active", StartTime: time.Now().Add(-2 * time.Hour)}
+	finished := &models.VehicleTrip{ID: uuid.New(), Status: "completed", StartTime: time.Now().Add(-72 * time.Hour)}
+	repo := &fakeTripRepo{trips: []*models.VehicleTrip{stale, recent, finished}}
+
+	closer := services.NewStaleTripCloser(repo, 24*time.Hour)
+
+	closed, err := closer.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), closed)
+	assert.Equal(t, "auto_closed", stale.Status)
+	require.NotNil(t, stale.Notes)
+	assert.Contains(t, *stale.Notes, "Auto-closed")
+	assert.Equal(t, "active", recent.Status)
+	assert.Equal(t, "completed", finished.Status)
+}
+
+func TestStaleTripCloser_ReconcileKeepsOnlyLatestActiveTrip(t *testing.T) {
+	vehicleID := uuid.New()
+	otherVehicleID := uuid.New()
+
+	// Listed out of order so the latest isn't simply the first one
+	older := &models.VehicleTrip{ID: uuid.New(), VehicleID: vehicleID, Status: "active", StartTime: time.Now().Add(-5 * time.Hour)}
+	latest := &models.VehicleTrip{ID: uuid.New(), VehicleID: vehicleID, Status: "active", StartTime: time.Now().Add(-1 * time.Hour)}
+	oldest := &models.VehicleTrip{ID: uuid.New(), VehicleID: vehicleID, Status: "active", StartTime: time.Now().Add(-9 * time.Hour)}
+	single := &models.VehicleTrip{ID: uuid.New(), VehicleID: otherVehicleID, Status: "active", StartTime: time.Now().Add(-3 * time.Hour)}
+	repo := &fakeTripRepo{trips: []*models.VehicleTrip{older, latest, oldest, single}}
+
+	closer := services.NewStaleTripCloser(repo, 24*time.Hour)
+
+	closed, err := closer.ReconcileActiveTrips(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), closed)
+	remaining := repo.activeTrips(vehicleID)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, latest.ID, remaining[0].ID)
+	assert.Equal(t, "auto_closed", older.Status)
+	assert.Equal(t, "auto_closed", oldest.Status)
+	assert.Contains(t, *older.Notes, latest.ID.String())
+	assert.Equal(t, "active", single.Status)
+}
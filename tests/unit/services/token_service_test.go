@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+const tokenTestSecret = "token-test-secret"
+
+func newTokenServiceWithMockDB(t *testing.T, idleTimeout time.Duration) (*services.TokenService, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	ts := services.NewTokenService(sqlx.NewDb(mockDB, "sqlmock"), tokenTestSecret, 15*time.Minute, 24*time.Hour)
+	ts.SetSessionIdleTimeout(idleTimeout)
+	return ts, mock
+}
+
+func signedAccessToken(t *testing.T, userID uuid.UUID) (string, string) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID.String(),
+		"exp":     time.Now().Add(15 * time.Minute).Unix(),
+	})
+	signed, err := token.SignedString([]byte(tokenTestSecret))
+	require.NoError(t, err)
+	return signed, fmt.Sprintf("%x", sha256.Sum256([]byte(signed)))
+}
+
+func expectUserLookup(mock sqlmock.Sqlmock, userID uuid.UUID) {
+	roleID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "role_id", "active"}).
+			AddRow(userID, "Driver", "driver@fleet.com", roleID, true))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM roles")).
+		WithArgs(roleID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(roleID, "driver"))
+}
+
+func TestValidateAccessTokenWithSession_RejectsIdleSession(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 30*time.Minute)
+	token, hash := signedAccessToken(t, uuid.New())
+
+	// The idle condition filters the session out
+	mock.ExpectQuery(regexp.QuoteMeta("last_used_at > NOW() - $2::bigint * INTERVAL '1 second'")).
+		WithArgs(hash, int64(1800)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_used_at"}))
+
+	_, _, err := ts.ValidateAccessTokenWithSession(context.Background(), token)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateAccessTokenWithSession_RecordsUseAfterThrottle(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 30*time.Minute)
+	userID := uuid.New()
+	sessionID := uuid.New()
+	token, hash := signedAccessToken(t, userID)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, last_used_at FROM session_tokens")).
+		WithArgs(hash, int64(1800)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_used_at"}).AddRow(sessionID, time.Now().Add(-5*time.Minute)))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE session_tokens SET last_used_at = NOW() WHERE id = $1")).
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectUserLookup(mock, userID)
+
+	user, gotSessionID, err := ts.ValidateAccessTokenWithSession(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+	assert.Equal(t, sessionID, gotSessionID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateAccessTokenWithSession_SkipsUpdateWithinThrottle(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 30*time.Minute)
+	userID := uuid.New()
+	token, hash := signedAccessToken(t, userID)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, last_used_at FROM session_tokens")).
+		WithArgs(hash, int64(1800)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_used_at"}).AddRow(uuid.New(), time.Now().Add(-10*time.Second)))
+	// No UPDATE expected: sqlmock fails on unexpected statements
+	expectUserLookup(mock, userID)
+
+	_, _, err := ts.ValidateAccessTokenWithSession(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateAccessTokenWithSession_IdleTimeoutDisabled(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 0)
+	userID := uuid.New()
+	token, hash := signedAccessToken(t, userID)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, last_used_at FROM session_tokens")).
+		WithArgs(hash, int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_used_at"}).AddRow(uuid.New(), time.Now()))
+	expectUserLookup(mock, userID)
+
+	_, _, err := ts.ValidateAccessTokenWithSession(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectSessionStored expects a new session with the given refresh lifetime, for a user
+// below the session limit
+func expectSessionStored(mock sqlmock.Sqlmock, userID uuid.UUID, refreshTTL time.Duration) {
+	mock.ExpectQuery(regexp.QuoteMeta("FROM session_tokens")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO session_tokens")).
+		WithArgs(sqlmock.AnyArg(), userID, sqlmock.AnyArg(), sqlmock.AnyArg(), "10.0.0.1", "app/1.0",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), int64(refreshTTL.Seconds()),
+			false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_sessions")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+}
+
+func TestGenerateRememberedTokenPair_UsesRememberMeRefreshTTL(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 0)
+	ts.SetRememberMeRefreshTTL(30 * 24 * time.Hour)
+	user := &models.User{ID: uuid.New(), Email: "driver@fleet.com", Role: &models.Role{Name: "driver"}}
+
+	expectSessionStored(mock, user.ID, 30*24*time.Hour)
+
+	pair, err := ts.GenerateRememberedTokenPair(context.Background(), user, "10.0.0.1", "app/1.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, 15*60, pair.ExpiresIn, "the access token lifetime is unchanged")
+	assert.Equal(t, 30*24*60*60, pair.RefreshExpiresIn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenPair_KeepsSessionRefreshTTL(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 0)
+	userID := uuid.New()
+	rememberMeTTL := 30 * 24 * time.Hour
+
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID.String(),
+		"exp": time.Now().Add(rememberMeTTL).Unix(),
+	}).SignedString([]byte(tokenTestSecret))
+	require.NoError(t, err)
+	sessionID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE refresh_token_hash = $1")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "refresh_ttl_seconds"}).
+			AddRow(sessionID, userID, int64(rememberMeTTL.Seconds())))
+	expectUserLookup(mock, userID)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE session_tokens")).
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSessionStored(mock, userID, rememberMeTTL)
+
+	pair, err := ts.RefreshTokenPair(context.Background(), refreshToken, "10.0.0.1", "app/1.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, int(rememberMeTTL.Seconds()), pair.RefreshExpiresIn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenPair_RememberMeSurvivesIdleTimeout(t *testing.T) {
+	ts, mock := newTokenServiceWithMockDB(t, 60*time.Minute)
+	userID := uuid.New()
+	rememberMeTTL := 30 * 24 * time.Hour
+
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID.String(),
+		"exp": time.Now().Add(rememberMeTTL).Unix(),
+	}).SignedString([]byte(tokenTestSecret))
+	require.NoError(t, err)
+	sessionID := uuid.New()
+
+	// Idle for two hours: only sessions with a refresh lifetime above the default 24h skip
+	// the idle condition
+	mock.ExpectQuery(regexp.QuoteMeta("refresh_ttl_seconds > $4 OR last_used_at > NOW() - $3::bigint * INTERVAL '1 second'")).
+		WithArgs(sqlmock.AnyArg(), userID, int64(3600), int64(24*60*60)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "refresh_ttl_seconds", "last_used_at"}).
+			AddRow(sessionID, userID, int64(rememberMeTTL.Seconds()), time.Now().Add(-2*time.Hour)))
+	expectUserLookup(mock, userID)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE session_tokens")).
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSessionStored(mock, userID, rememberMeTTL)
+
+	pair, err := ts.RefreshTokenPair(context.Background(), refreshToken, "10.0.0.1", "app/1.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, int(rememberMeTTL.Seconds()), pair.RefreshExpiresIn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -12,6 +12,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
 	"github.com/paulochiaradia/dashtrack/internal/services"
 	"github.com/paulochiaradia/dashtrack/tests/testutils/mocks"
 )
@@ -30,7 +31,7 @@ type userRepoAdapter struct {
 	*mocks.MockUserRepository
 }
 
-func (u *userRepoAdapter) Search(ctx context.Context, companyID *uuid.UUID, query string, limit, offset int) ([]*models.User, error) {
+func (u *userRepoAdapter) Search(ctx context.Context, companyID *uuid.UUID, query string, limit, offset int, sort repository.Sort) ([]*models.User, error) {
 	return nil, nil
 }
 
@@ -181,7 +182,7 @@ func (suite *UserServiceTestSuite) TestGetUsers_Master_CanSeeAll() {
 
 	// For master users, call List with appropriate parameters
 	suite.mockUserRepo.EXPECT().
-		List(ctx, 10, 0, req.Active, gomock.Any()).
+		List(ctx, 10, 0, req.Active, gomock.Any(), req.Sort).
 		Return(expectedUsers, nil)
 
 	suite.mockUserRepo.EXPECT().
@@ -200,6 +201,43 @@ func (suite *UserServiceTestSuite) TestGetUsers_Master_CanSeeAll() {
 	assert.Equal(suite.T(), 10, result.Limit)
 }
 
+func (suite *UserServiceTestSuite) TestGetUsers_Manager_SeesOnlineDrivers() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	currentUser := &models.UserContext{UserID: uuid.New(), CompanyID: &companyID, Role: "manager"}
+	req := services.UserListRequest{Page: 1, Limit: 10}
+
+	online, idle, loggedOut, neverLoggedIn := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	users := []*models.User{{ID: online}, {ID: idle}, {ID: loggedOut}, {ID: neverLoggedIn}}
+	recently, longAgo := time.Now().Add(-2*time.Minute), time.Now().Add(-2*time.Hour)
+
+	suite.mockUserRepo.EXPECT().
+		ListByCompanyAndRoles(ctx, &companyID, []string{"driver", "helper"}, 10, 0, req.Sort).
+		Return(users, nil)
+	suite.mockUserRepo.EXPECT().
+		CountByCompanyAndRoles(ctx, &companyID, []string{"driver", "helper"}).
+		Return(4, nil)
+	suite.mockUserRepo.EXPECT().
+		GetLastActivity(ctx, []uuid.UUID{online, idle, loggedOut, neverLoggedIn}).
+		Return(map[uuid.UUID]models.UserActivity{
+			online:    {UserID: online, LastActivityAt: recently, ActiveSessionUsedAt: &recently},
+			idle:      {UserID: idle, LastActivityAt: longAgo, ActiveSessionUsedAt: &longAgo},
+			loggedOut: {UserID: loggedOut, LastActivityAt: recently},
+		}, nil)
+
+	result, err := suite.userService.GetUsers(ctx, currentUser, req)
+
+	suite.Require().NoError(err)
+	onlineByID := map[uuid.UUID]bool{}
+	for _, user := range result.Users {
+		suite.Require().NotNil(user.Online)
+		onlineByID[user.ID] = *user.Online
+	}
+	suite.Equal(map[uuid.UUID]bool{online: true, idle: false, loggedOut: false, neverLoggedIn: false}, onlineByID)
+	suite.Equal(recently, *result.Users[2].LastActivityAt)
+	suite.Nil(result.Users[3].LastActivityAt)
+}
+
 func (suite *UserServiceTestSuite) TestUpdateUser_Success() {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -259,6 +297,92 @@ func (suite *UserServiceTestSuite) TestUpdateUser_Success() {
 	assert.Equal(suite.T(), updateReq.Email, result.Email)
 }
 
+func (suite *UserServiceTestSuite) TestUpdateUser_NormalizesCPF() {
+	ctx := context.Background()
+	userID := uuid.New()
+	companyID := uuid.New()
+
+	currentUser := &models.UserContext{
+		UserID:    uuid.New(),
+		CompanyID: &companyID,
+		Role:      "company_admin",
+	}
+
+	existingUser := &models.User{
+		ID:        userID,
+		Email:     "driver@example.com",
+		CompanyID: &companyID,
+		Role:      &models.Role{Name: "driver"},
+	}
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(existingUser, nil)
+
+	// Uniqueness is checked on the digits-only value, ignoring the user being updated
+	suite.mockUserRepo.EXPECT().
+		ExistsByCPF(ctx, &companyID, "52998224725", &userID).
+		Return(false, nil)
+
+	expectedReq := models.UpdateUserRequest{CPF: "52998224725"}
+	suite.mockUserRepo.EXPECT().Update(ctx, userID, expectedReq).Return(existingUser, nil)
+
+	_, err := suite.userService.UpdateUser(ctx, currentUser, userID, models.UpdateUserRequest{CPF: "529.982.247-25"})
+
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *UserServiceTestSuite) TestUpdateUser_InvalidCPF() {
+	ctx := context.Background()
+	userID := uuid.New()
+	companyID := uuid.New()
+
+	currentUser := &models.UserContext{
+		UserID:    uuid.New(),
+		CompanyID: &companyID,
+		Role:      "company_admin",
+	}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(&models.User{
+		ID:        userID,
+		CompanyID: &companyID,
+		Role:      &models.Role{Name: "driver"},
+	}, nil)
+
+	_, err := suite.userService.UpdateUser(ctx, currentUser, userID, models.UpdateUserRequest{CPF: "529.982.247-26"})
+
+	assert.Equal(suite.T(), services.ErrInvalidCPF, err)
+}
+
+func (suite *UserServiceTestSuite) TestCreateUser_DuplicateCPFInCompany() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	roleID := uuid.New()
+
+	currentUser := &models.UserContext{
+		UserID:    uuid.New(),
+		CompanyID: &companyID,
+		Role:      "company_admin",
+	}
+
+	createReq := models.CreateUserRequest{
+		Name:     "Second Driver",
+		Email:    "second@example.com",
+		Password: "password123",
+		Phone:    "11999999999",
+		CPF:      "52998224725",
+		RoleID:   roleID.String(),
+	}
+
+	suite.mockRoleRepo.EXPECT().GetByID(ctx, roleID).Return(&models.Role{ID: roleID, Name: "driver"}, nil)
+	suite.mockUserRepo.EXPECT().GetByEmail(ctx, createReq.Email).Return(nil, nil)
+	suite.mockUserRepo.EXPECT().
+		ExistsByCPF(ctx, &companyID, "52998224725", (*uuid.UUID)(nil)).
+		Return(true, nil)
+
+	user, err := suite.userService.CreateUser(ctx, currentUser, createReq)
+
+	assert.Nil(suite.T(), user)
+	assert.Equal(suite.T(), services.ErrCPFAlreadyExists, err)
+}
+
 func (suite *UserServiceTestSuite) TestDeleteUser_Success() {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -322,7 +446,227 @@ func (suite *UserServiceTestSuite) TestDeleteUser_CannotDeleteSelf() {
 	assert.Contains(suite.T(), err.Error(), "cannot delete your own account")
 }
 
+// fakeSessionRevoker records which users had their sessions revoked
+type fakeSessionRevoker struct {
+	revoked map[uuid.UUID]int64
+}
+
+func (f *fakeSessionRevoker) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if f.revoked == nil {
+		f.revoked = map[uuid.UUID]int64{}
+	}
+	f.revoked[userID] = 2
+	return 2, nil
+}
+
+func (suite *UserServiceTestSuite) TestRevokeUserSessions_DeactivatesAccount() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	userID := uuid.New()
+	revoker := &fakeSessionRevoker{}
+	suite.userService.SetSessionRevoker(revoker)
+
+	admin := &models.UserContext{UserID: uuid.New(), CompanyID: &companyID, Role: "admin"}
+	target := &models.User{ID: userID, Email: "driver@example.com", CompanyID: &companyID, Active: true, Role: &models.Role{Name: "driver"}}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(target, nil)
+	suite.mockUserRepo.EXPECT().
+		Update(ctx, userID, models.UpdateUserRequest{Active: boolPtr(false)}).
+		Return(&models.User{ID: userID, Email: target.Email, CompanyID: &companyID, Active: false}, nil)
+
+	user, revoked, err := suite.userService.RevokeUserSessions(ctx, admin, userID, true)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), revoked)
+	assert.False(suite.T(), user.Active)
+	assert.Contains(suite.T(), revoker.revoked, userID)
+}
+
+func (suite *UserServiceTestSuite) TestRevokeUserSessions_AdminOutsideCompanyForbidden() {
+	ctx := context.Background()
+	adminCompanyID := uuid.New()
+	otherCompanyID := uuid.New()
+	userID := uuid.New()
+	revoker := &fakeSessionRevoker{}
+	suite.userService.SetSessionRevoker(revoker)
+
+	admin := &models.UserContext{UserID: uuid.New(), CompanyID: &adminCompanyID, Role: "admin"}
+	target := &models.User{ID: userID, CompanyID: &otherCompanyID, Active: true, Role: &models.Role{Name: "driver"}}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(target, nil)
+
+	_, _, err := suite.userService.RevokeUserSessions(ctx, admin, userID, false)
+
+	assert.Equal(suite.T(), services.ErrCompanyMismatch, err)
+	assert.Empty(suite.T(), revoker.revoked)
+}
+
+func (suite *UserServiceTestSuite) TestRevokeUserSessions_AdminCannotTargetMaster() {
+	ctx := context.Background()
+	userID := uuid.New()
+	revoker := &fakeSessionRevoker{}
+	suite.userService.SetSessionRevoker(revoker)
+
+	// Neither the admin nor the master belongs to a company
+	admin := &models.UserContext{UserID: uuid.New(), Role: "admin"}
+	target := &models.User{ID: userID, Active: true, Role: &models.Role{Name: "master"}}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(target, nil)
+
+	_, _, err := suite.userService.RevokeUserSessions(ctx, admin, userID, true)
+
+	assert.Equal(suite.T(), services.ErrCompanyMismatch, err)
+	assert.Empty(suite.T(), revoker.revoked)
+}
+
+func (suite *UserServiceTestSuite) TestRevokeUserSessions_AdminCannotTargetCompanyAdmin() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	userID := uuid.New()
+	revoker := &fakeSessionRevoker{}
+	suite.userService.SetSessionRevoker(revoker)
+
+	admin := &models.UserContext{UserID: uuid.New(), CompanyID: &companyID, Role: "admin"}
+	target := &models.User{ID: userID, CompanyID: &companyID, Active: true, Role: &models.Role{Name: "company_admin"}}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(target, nil)
+
+	_, _, err := suite.userService.RevokeUserSessions(ctx, admin, userID, false)
+
+	assert.Equal(suite.T(), services.ErrCompanyMismatch, err)
+	assert.Empty(suite.T(), revoker.revoked)
+}
+
+func (suite *UserServiceTestSuite) TestRevokeUserSessions_MasterAnyCompany() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	userID := uuid.New()
+	suite.userService.SetSessionRevoker(&fakeSessionRevoker{})
+
+	master := &models.UserContext{UserID: uuid.New(), Role: "master", IsMaster: true}
+	target := &models.User{ID: userID, CompanyID: &companyID, Active: true}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(target, nil)
+
+	user, revoked, err := suite.userService.RevokeUserSessions(ctx, master, userID, false)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), revoked)
+	assert.True(suite.T(), user.Active)
+}
+
 // Helper function
+func (suite *UserServiceTestSuite) TestRequestAccountDeletion_RevokesSessions() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	userID := uuid.New()
+	revoker := &fakeSessionRevoker{}
+	suite.userService.SetSessionRevoker(revoker)
+
+	driver := &models.User{ID: userID, CompanyID: &companyID, Role: &models.Role{Name: "driver"}, Active: true}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(driver, nil)
+	suite.mockUserRepo.EXPECT().RequestErasure(ctx, userID).Return(nil)
+
+	result, err := suite.userService.RequestAccountDeletion(ctx, userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), result.RevokedSessions)
+	assert.Equal(suite.T(), int64(2), revoker.revoked[userID])
+	assert.False(suite.T(), result.User.Active)
+	assert.True(suite.T(), result.ErasureScheduledAt.After(time.Now().Add(29*24*time.Hour)))
+}
+
+func (suite *UserServiceTestSuite) TestRequestAccountDeletion_BlocksLastCompanyAdmin() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	userID := uuid.New()
+	revoker := &fakeSessionRevoker{}
+	suite.userService.SetSessionRevoker(revoker)
+
+	admin := &models.User{ID: userID, CompanyID: &companyID, Role: &models.Role{Name: "company_admin"}, Active: true}
+	inactiveAdmin := &models.User{ID: uuid.New(), CompanyID: &companyID, Active: false}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(admin, nil)
+	suite.mockUserRepo.EXPECT().
+		ListByCompanyAndRoles(ctx, &companyID, []string{"company_admin", "admin"}, 100, 0, repository.Sort{}).
+		Return([]*models.User{admin, inactiveAdmin}, nil)
+
+	_, err := suite.userService.RequestAccountDeletion(ctx, userID)
+
+	assert.Equal(suite.T(), services.ErrLastCompanyAdmin, err)
+	assert.Empty(suite.T(), revoker.revoked)
+}
+
+func (suite *UserServiceTestSuite) TestRequestAccountDeletion_AllowsAdminWithAnotherAdmin() {
+	ctx := context.Background()
+	companyID := uuid.New()
+	userID := uuid.New()
+	suite.userService.SetSessionRevoker(&fakeSessionRevoker{})
+
+	admin := &models.User{ID: userID, CompanyID: &companyID, Role: &models.Role{Name: "admin"}, Active: true}
+	otherAdmin := &models.User{ID: uuid.New(), CompanyID: &companyID, Active: true}
+
+	suite.mockUserRepo.EXPECT().GetByID(ctx, userID).Return(admin, nil)
+	suite.mockUserRepo.EXPECT().
+		ListByCompanyAndRoles(ctx, &companyID, []string{"company_admin", "admin"}, 100, 0, repository.Sort{}).
+		Return([]*models.User{admin, otherAdmin}, nil)
+	suite.mockUserRepo.EXPECT().RequestErasure(ctx, userID).Return(nil)
+
+	result, err := suite.userService.RequestAccountDeletion(ctx, userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), result.RevokedSessions)
+}
+
+func (suite *UserServiceTestSuite) TestPurgeUser_MasterOnlyAndNotSelf() {
+	ctx := context.Background()
+	masterID := uuid.New()
+	targetID := uuid.New()
+
+	_, err := suite.userService.PurgeUser(ctx, &models.UserContext{UserID: masterID, Role: "admin"}, targetID)
+	assert.Equal(suite.T(), services.ErrInsufficientPermissions, err)
+
+	_, err = suite.userService.PurgeUser(ctx, &models.UserContext{UserID: masterID, Role: "master"}, masterID)
+	assert.Equal(suite.T(), services.ErrCannotDeleteSelf, err)
+
+	suite.mockUserRepo.EXPECT().PurgeUser(ctx, targetID).Return(nil, nil)
+	_, err = suite.userService.PurgeUser(ctx, &models.UserContext{UserID: masterID, Role: "master"}, targetID)
+	assert.Equal(suite.T(), services.ErrUserNotFound, err)
+}
+
+func (suite *UserServiceTestSuite) TestPurgeUser_ReturnsRepositoryCounts() {
+	ctx := context.Background()
+	targetID := uuid.New()
+	expected := &models.UserPurgeResult{UserID: targetID, AuthLogs: 12, AuditLogs: 3, SessionsDeleted: 2}
+
+	suite.mockUserRepo.EXPECT().PurgeUser(ctx, targetID).Return(expected, nil)
+
+	result, err := suite.userService.PurgeUser(ctx, &models.UserContext{UserID: uuid.New(), Role: "master"}, targetID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *UserServiceTestSuite) TestPurgeUser_DeletesStoredAvatar() {
+	ctx := context.Background()
+	targetID := uuid.New()
+	storage := newMemoryStorage()
+	suite.userService.SetFileStorage(storage)
+
+	key := "avatars/" + targetID.String() + "/a1b2c3.png"
+	avatarURL, _ := storage.Put(ctx, key, "image/png", []byte("png"))
+
+	suite.mockUserRepo.EXPECT().PurgeUser(ctx, targetID).
+		Return(&models.UserPurgeResult{UserID: targetID, Avatar: &avatarURL}, nil)
+
+	_, err := suite.userService.PurgeUser(ctx, &models.UserContext{UserID: uuid.New(), Role: "master"}, targetID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{key}, storage.deleted)
+	assert.NotContains(suite.T(), storage.files, key)
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
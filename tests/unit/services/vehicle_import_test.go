@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/repository"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+// fakeImportVehicleRepo knows a set of registered plates and records the created vehicles
+type fakeImportVehicleRepo struct {
+	repository.VehicleRepositoryInterface
+	registered map[string]bool
+	created    []*models.Vehicle
+	createErr  error
+}
+
+func (f *fakeImportVehicleRepo) CheckLicensePlateExists(ctx context.Context, licensePlate string, companyID uuid.UUID, excludeID *uuid.UUID) (bool, error) {
+	return f.registered[licensePlate], nil
+}
+
+func (f *fakeImportVehicleRepo) CreateBatch(ctx context.Context, vehicles []*models.Vehicle) error {
+	if f.createErr != nil {
+		return f.createErr
+	}
+	for _, vehicle := range vehicles {
+		vehicle.ID = uuid.New()
+	}
+	f.created = append(f.created, vehicles...)
+	return nil
+}
+
+func TestVehicleImporter_CreatesValidRowsAndReportsRejected(t *testing.T) {
+	repo := &fakeImportVehicleRepo{registered: map[string]bool{"XYZ9876": true}}
+	companyID := uuid.New()
+	csv := strings.Join([]string{
+		"License_Plate,brand,model,year,vehicle_type,fuel_type,capacity",
+		"abc-1234,Volvo,FH 540,2021,Truck,diesel,25000",
+		"ABC1D23,Fiat,Ducato,2022,van,diesel,",
+		"xyz-9876,Ford,Cargo,2019,truck,diesel,12000",
+		"ABC1234,Volvo,FH 460,2020,truck,diesel,",
+		"AB12,,Uno,1800,plane,water,-3",
+		",,,,,,",
+	}, "\n")
+
+	result, err := services.NewVehicleImporter(repo).Import(context.Background(), companyID, strings.NewReader(csv))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 3, result.Rejected)
+	require.Len(t, result.Rows, 5)
+
+	first := result.Rows[0]
+	assert.Equal(t, 2, first.Row)
+	assert.Equal(t, "ABC1234", first.LicensePlate)
+	assert.Equal(t, models.VehicleImportCreated, first.Status)
+	require.NotNil(t, first.VehicleID)
+	assert.Equal(t, repo.created[0].ID, *first.VehicleID)
+
+	assert.Equal(t, []string{"license_plate is already registered"}, result.Rows[2].Errors)
+	assert.Equal(t, []string{"license_plate is repeated from row 2"}, result.Rows[3].Errors)
+
+	invalid := result.Rows[4]
+	assert.Equal(t, models.VehicleImportRejected, invalid.Status)
+	assert.Nil(t, invalid.VehicleID)
+	assert.Len(t, invalid.Errors, 6)
+
+	require.Len(t, repo.created, 2)
+	assert.Equal(t, companyID, repo.created[0].CompanyID)
+	assert.Equal(t, "truck", repo.created[0].VehicleType)
+	require.NotNil(t, repo.created[0].CargoCapacity)
+	assert.Equal(t, 25000.0, *repo.created[0].CargoCapacity)
+	assert.Nil(t, repo.created[1].CargoCapacity)
+}
+
+func TestVehicleImporter_RejectsUnusableFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		err  error
+	}{
+		{"empty", "", services.ErrVehicleImportEmpty},
+		{"header only", "license_plate,brand,model,year,type,fuel_type\n", services.ErrVehicleImportEmpty},
+		{"missing column", "license_plate,brand,model,year,type\nABC1234,Volvo,FH,2020,truck\n", services.ErrVehicleImportInvalidHeader},
+		{"unknown column", "license_plate,brand,model,year,type,fuel_type,color\n", services.ErrVehicleImportInvalidHeader},
+		{"unterminated quote", "license_plate,brand,model,year,type,fuel_type\n\"ABC1234,Volvo\n", services.ErrVehicleImportMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeImportVehicleRepo{}
+
+			_, err := services.NewVehicleImporter(repo).Import(context.Background(), uuid.New(), strings.NewReader(tt.csv))
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Empty(t, repo.created)
+		})
+	}
+}
+
+func TestVehicleImporter_HeaderErrorListsEveryColumn(t *testing.T) {
+	repo := &fakeImportVehicleRepo{}
+	csv := "license_plate,brand,model,fuel_type,color,brand\nABC1234,Volvo,FH,diesel,red,Volvo\n"
+
+	_, err := services.NewVehicleImporter(repo).Import(context.Background(), uuid.New(), strings.NewReader(csv))
+
+	var headerErr *services.VehicleImportHeaderError
+	require.ErrorAs(t, err, &headerErr)
+	assert.ErrorIs(t, err, services.ErrVehicleImportInvalidHeader)
+	assert.Equal(t, []string{"year", "type"}, headerErr.Missing)
+	assert.Equal(t, []string{"color"}, headerErr.Unknown)
+	assert.Equal(t, []string{"brand"}, headerErr.Repeated)
+	assert.Contains(t, err.Error(), "missing columns: year, type")
+	assert.Empty(t, repo.created)
+}
+
+func TestVehicleImporter_LimitsRows(t *testing.T) {
+	repo := &fakeImportVehicleRepo{}
+	importer := services.NewVehicleImporter(repo)
+	importer.SetMaxRows(1)
+	csv := "license_plate,brand,model,year,type,fuel_type\nABC1234,Volvo,FH,2020,truck,diesel\nABC1D23,Fiat,Ducato,2022,van,diesel\n"
+
+	_, err := importer.Import(context.Background(), uuid.New(), strings.NewReader(csv))
+
+	assert.ErrorIs(t, err, repository.ErrBatchTooLarge)
+	assert.Empty(t, repo.created)
+}
+
+func TestVehicleImporter_FailsWhenInsertFails(t *testing.T) {
+	repo := &fakeImportVehicleRepo{createErr: errors.New("connection reset")}
+	csv := "license_plate,brand,model,year,type,fuel_type\nABC1234,Volvo,FH,2020,truck,diesel\n"
+
+	result, err := services.NewVehicleImporter(repo).Import(context.Background(), uuid.New(), strings.NewReader(csv))
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
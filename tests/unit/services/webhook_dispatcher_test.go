@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestWebhookDispatcher_DeliverSignsPayload(t *testing.T) {
+	secret := "super-secret-signing-key"
+	body := []byte(`{"event":"auth.account_blocked"}`)
+
+	var gotSignature, gotEvent string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(services.WebhookSignatureHeader)
+		gotEvent = r.Header.Get(services.WebhookEventHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher := services.NewWebhookDispatcher(nil)
+	dispatcher.SetHTTPClient(server.Client())
+	webhook := &models.Webhook{ID: uuid.New(), URL: server.URL, Secret: secret}
+
+	err := dispatcher.Deliver(context.Background(), webhook, uuid.New(), models.WebhookEventAccountBlocked, body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sha256="+services.SignPayload(secret, body), gotSignature)
+	assert.Equal(t, models.WebhookEventAccountBlocked, gotEvent)
+	assert.Equal(t, body, gotBody)
+}
+
+func TestWebhookDispatcher_DeliverRetriesOnFailure(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher := services.NewWebhookDispatcher(nil)
+	dispatcher.SetHTTPClient(server.Client())
+	dispatcher.SetRetryPolicy(3, time.Millisecond)
+	webhook := &models.Webhook{ID: uuid.New(), URL: server.URL, Secret: "secret"}
+
+	err := dispatcher.Deliver(context.Background(), webhook, uuid.New(), models.WebhookEventVehicleAssignmentChanged, []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWebhookDispatcher_DeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dispatcher := services.NewWebhookDispatcher(nil)
+	dispatcher.SetHTTPClient(server.Client())
+	dispatcher.SetRetryPolicy(2, time.Millisecond)
+	webhook := &models.Webhook{ID: uuid.New(), URL: server.URL, Secret: "secret"}
+
+	err := dispatcher.Deliver(context.Background(), webhook, uuid.New(), models.WebhookEventVehicleAssignmentChanged, []byte(`{}`))
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestWebhookDispatcher_DeliverSignsWithRotatedSecret(t *testing.T) {
+	body := []byte(`{"event":"vehicle.assignment_changed"}`)
+
+	var gotSignature, gotPrevious string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(services.WebhookSignatureHeader)
+		gotPrevious = r.Header.Get(services.WebhookPreviousSignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	oldSecret := "old-signing-secret-0123456789"
+	expiresAt := time.Now().Add(time.Hour)
+	webhook := &models.Webhook{
+		ID:                      uuid.New(),
+		URL:                     server.URL,
+		Secret:                  "new-signing-secret-0123456789",
+		PreviousSecret:          &oldSecret,
+		PreviousSecretExpiresAt: &expiresAt,
+	}
+
+	dispatcher := services.NewWebhookDispatcher(nil)
+	dispatcher.SetHTTPClient(server.Client())
+	err := dispatcher.Deliver(context.Background(), webhook, uuid.New(), models.WebhookEventVehicleAssignmentChanged, body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sha256="+services.SignPayload(webhook.Secret, body), gotSignature)
+	assert.Equal(t, "sha256="+services.SignPayload(oldSecret, body), gotPrevious)
+}
+
+func TestWebhookDispatcher_DeliverDropsExpiredPreviousSecret(t *testing.T) {
+	var sawHeader bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, sawHeader = r.Header[services.WebhookPreviousSignatureHeader]
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	oldSecret := "old-signing-secret-0123456789"
+	expiredAt := time.Now().Add(-time.Minute)
+	webhook := &models.Webhook{
+		ID:                      uuid.New(),
+		URL:                     server.URL,
+		Secret:                  "new-signing-secret-0123456789",
+		PreviousSecret:          &oldSecret,
+		PreviousSecretExpiresAt: &expiredAt,
+	}
+
+	dispatcher := services.NewWebhookDispatcher(nil)
+	dispatcher.SetHTTPClient(server.Client())
+	err := dispatcher.Deliver(context.Background(), webhook, uuid.New(), models.WebhookEventVehicleAssignmentChanged, []byte(`{}`))
+	require.NoError(t, err)
+
+	assert.False(t, sawHeader)
+}
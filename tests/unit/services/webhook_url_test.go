@@ -0,0 +1,60 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/models"
+	"github.com/paulochiaradia/dashtrack/internal/services"
+)
+
+func TestValidateWebhookURL_RejectsInternalAndPlainHTTP(t *testing.T) {
+	rejected := []string{
+		"http://93.184.216.34/hook",
+		"https://127.0.0.1/hook",
+		"https://localhost:8443/hook",
+		"https://[::1]/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hook",
+		"https://172.16.3.4/hook",
+		"https://192.168.1.10/hook",
+		"https://[fd00::1]/hook",
+		"https://0.0.0.0/hook",
+		"https://[::ffff:127.0.0.1]/hook",
+	}
+	for _, rawURL := range rejected {
+		t.Run(rawURL, func(t *testing.T) {
+			err := services.ValidateWebhookURL(context.Background(), rawURL)
+			assert.ErrorIs(t, err, services.ErrWebhookURLNotAllowed)
+		})
+	}
+}
+
+func TestValidateWebhookURL_AcceptsPublicHTTPS(t *testing.T) {
+	assert.NoError(t, services.ValidateWebhookURL(context.Background(), "https://93.184.216.34/hook"))
+}
+
+func TestWebhookDispatcher_RefusesToDialInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// A URL saved while its host pointed elsewhere, now resolving to loopback
+	dispatcher := services.NewWebhookDispatcher(nil)
+	dispatcher.SetRetryPolicy(1, 0)
+	webhook := &models.Webhook{ID: uuid.New(), URL: server.URL, Secret: "super-secret-signing-key"}
+
+	err := dispatcher.Deliver(context.Background(), webhook, uuid.New(), models.WebhookEventAccountBlocked, []byte(`{}`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), services.ErrWebhookURLNotAllowed.Error())
+	assert.False(t, called)
+}
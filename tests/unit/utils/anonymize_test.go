@@ -0,0 +1,26 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestAnonymizeEmail(t *testing.T) {
+	anonymized := utils.AnonymizeEmail("Driver@Fleet.com")
+
+	assert.NotContains(t, anonymized, "driver")
+	assert.NotContains(t, anonymized, "fleet")
+	assert.True(t, utils.IsAnonymizedEmail(anonymized))
+	assert.Equal(t, anonymized, utils.AnonymizeEmail(" driver@fleet.com "), "the same email must give the same pseudonym")
+	assert.NotEqual(t, anonymized, utils.AnonymizeEmail("helper@fleet.com"))
+	assert.LessOrEqual(t, len(anonymized), 100, "must fit auth_logs.email_attempt")
+	assert.Empty(t, utils.AnonymizeEmail(""))
+}
+
+func TestIsAnonymizedEmail(t *testing.T) {
+	assert.False(t, utils.IsAnonymizedEmail("driver@fleet.com"))
+	assert.False(t, utils.IsAnonymizedEmail("anon-driver@fleet.com"))
+}
@@ -0,0 +1,92 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+type bindTestRequest struct {
+	Name  string `json:"name" binding:"required,min=2"`
+	Email string `json:"email" binding:"required,email"`
+}
+
+// bindBody posts body to a handler that binds it with utils.BindJSON
+func bindBody(t *testing.T, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/bind", func(c *gin.Context) {
+		var req bindTestRequest
+		if bindErr := utils.BindJSON(c, &req); bindErr != nil {
+			utils.BindErrorResponse(c, bindErr)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/bind", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type bindErrorBody struct {
+	Success bool `json:"success"`
+	Error   struct {
+		Code   string             `json:"code"`
+		Fields []utils.FieldError `json:"fields"`
+	} `json:"error"`
+}
+
+func TestBindJSON_MalformedBody(t *testing.T) {
+	w := bindBody(t, `{"name": "Team A", "email": `)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body bindErrorBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.False(t, body.Success)
+	assert.Equal(t, utils.ErrCodeMalformedJSON, body.Error.Code)
+	assert.Empty(t, body.Error.Fields)
+}
+
+func TestBindJSON_WrongFieldType(t *testing.T) {
+	w := bindBody(t, `{"name": 42, "email": "a@b.com"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body bindErrorBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, utils.ErrCodeMalformedJSON, body.Error.Code)
+}
+
+func TestBindJSON_ValidationErrors(t *testing.T) {
+	w := bindBody(t, `{"name": "A", "email": "not-an-email"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body bindErrorBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, utils.ErrCodeValidationFailed, body.Error.Code)
+	require.Len(t, body.Error.Fields, 2)
+	assert.Equal(t, "name", body.Error.Fields[0].Field)
+	assert.Equal(t, "min", body.Error.Fields[0].Tag)
+	assert.Equal(t, "2", body.Error.Fields[0].Param)
+	assert.Equal(t, "email", body.Error.Fields[1].Field)
+	assert.Equal(t, "email", body.Error.Fields[1].Tag)
+}
+
+func TestBindJSON_ValidBody(t *testing.T) {
+	w := bindBody(t, `{"name": "Team A", "email": "a@b.com"}`)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,39 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestValidateCPF(t *testing.T) {
+	tests := []struct {
+		name  string
+		cpf   string
+		valid bool
+	}{
+		{"formatted", "529.982.247-25", true},
+		{"digits only", "52998224725", true},
+		{"wrong first check digit", "529.982.247-15", false},
+		{"wrong second check digit", "52998224726", false},
+		{"repeated digits", "111.111.111-11", false},
+		{"all zeros", "00000000000", false},
+		{"partial formatting", "529982.247-25", false},
+		{"too short", "5299822472", false},
+		{"letters", "529.982.247-2a", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, utils.ValidateCPF(tt.cpf))
+		})
+	}
+}
+
+func TestNormalizeCPF(t *testing.T) {
+	assert.Equal(t, "52998224725", utils.NormalizeCPF("529.982.247-25"))
+	assert.Equal(t, "52998224725", utils.NormalizeCPF("52998224725"))
+}
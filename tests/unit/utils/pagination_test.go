@@ -0,0 +1,44 @@
+package utils_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		wantErr bool
+	}{
+		{"missing uses default", "", 50, false},
+		{"valid value", "?limit=20", 20, false},
+		{"above max is clamped", "?limit=10000", 500, false},
+		{"negative is rejected", "?limit=-5", 0, true},
+		{"zero is rejected", "?limit=0", 0, true},
+		{"non-numeric is rejected", "?limit=abc", 0, true},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/history"+tt.query, nil)
+
+			limit, err := utils.ParseLimit(c, 50, 500)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestNormalizeLicensePlate(t *testing.T) {
+	assert.Equal(t, "ABC1234", utils.NormalizeLicensePlate("abc-1234"))
+	assert.Equal(t, "ABC1D23", utils.NormalizeLicensePlate(" ABC 1D23 "))
+}
+
+func TestValidateLicensePlate(t *testing.T) {
+	tests := []struct {
+		name  string
+		plate string
+		valid bool
+	}{
+		{"old format", "ABC1234", true},
+		{"mercosul format", "ABC1D23", true},
+		{"lowercase", "abc1234", false},
+		{"with dash", "ABC-1234", false},
+		{"too short", "ABC123", false},
+		{"digit in prefix", "AB11234", false},
+		{"letter in suffix", "ABC12D4", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, utils.ValidateLicensePlate(tt.plate))
+		})
+	}
+}
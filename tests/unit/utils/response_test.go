@@ -0,0 +1,79 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/paulochiaradia/dashtrack/internal/utils"
+)
+
+func TestCreatedResponse_SetsLocationFromCollectionPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, path := range []string{"/api/v1/vehicles", "/api/v1/vehicles/"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, path, nil)
+
+		utils.CreatedResponse(c, "42", "Vehicle created successfully", gin.H{"id": "42"})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/api/v1/vehicles/42", w.Header().Get("Location"), path)
+	}
+}
+
+func TestAuthContextMissingResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+
+	utils.AuthContextMissingResponse(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.True(t, c.IsAborted())
+
+	var body struct {
+		Success bool `json:"success"`
+		Error   struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.False(t, body.Success)
+	assert.Equal(t, utils.ErrCodeAuthContextMissing, body.Error.Code)
+}
+
+func TestNewPagination_HasMore(t *testing.T) {
+	assert.True(t, utils.NewPagination(25, 10, 10).HasMore)
+	assert.False(t, utils.NewPagination(20, 10, 10).HasMore)
+	assert.False(t, utils.NewPagination(0, 10, 0).HasMore)
+}
+
+func TestPaginatedResponse_SendsEmptyListForNilItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	var items []string
+	utils.PaginatedResponse(c, items, 0, 10, 0)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"message":"OK","data":[],"pagination":{"total":0,"limit":10,"offset":0,"has_more":false}}`, w.Body.String())
+}
+
+func TestFilteredPaginatedResponse_EchoesFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	utils.FilteredPaginatedResponse(c, []string{"a", "b"}, 3, 2, 0, gin.H{"status": "active"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"message":"OK","data":["a","b"],"pagination":{"total":3,"limit":2,"offset":0,"has_more":true},"meta":{"filters":{"status":"active"}}}`, w.Body.String())
+}